@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/oklahomer/go-kasumi/worker"
 	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib/debughttp"
 	"github.com/oklahomer/go-sarah/v4/slack"
 	"gopkg.in/yaml.v2"
 	"os"
@@ -21,6 +22,7 @@ func readConfig(path string) (*config, error) {
 		Slack:        slack.NewConfig(),
 		ContextCache: sarah.NewCacheConfig(),
 		Worker:       worker.NewConfig(),
+		Debug:        debughttp.NewConfig(),
 	}
 	err = yaml.Unmarshal(body, c)
 	if err != nil {
@@ -35,4 +37,5 @@ type config struct {
 	Slack        *slack.Config      `json:"slack" yaml:"slack"`
 	ContextCache *sarah.CacheConfig `json:"context_cache" yaml:"context_cache"`
 	Worker       *worker.Config     `json:"worker" yaml:"worker"`
+	Debug        *debughttp.Config  `json:"debug" yaml:"debug"`
 }
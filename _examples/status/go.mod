@@ -1,11 +1,31 @@
 module status
 
-go 1.15
+go 1.21
 
 require (
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/oklahomer/go-kasumi v0.0.0-20220203122045-3db87696aa9c
 	github.com/oklahomer/go-sarah/v4 v4.0.2
-	github.com/tidwall/gjson v1.14.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0
 )
+
+require (
+	github.com/creack/pty v1.1.9 // indirect
+	github.com/fsnotify/fsnotify v1.8.0 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/kr/pretty v0.3.0 // indirect
+	github.com/kr/pty v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/oklahomer/golack/v2 v2.1.0 // indirect
+	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
+	github.com/pkg/diff v0.0.0-20210226163009-20ebb0f2a09e // indirect
+	github.com/robfig/cron/v3 v3.0.1 // indirect
+	github.com/rogpeppe/go-internal v1.8.0 // indirect
+	github.com/tidwall/gjson v1.18.0 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.1 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+	gopkg.in/errgo.v2 v2.1.0 // indirect
+)
+
+replace github.com/oklahomer/go-sarah/v4 => ../..
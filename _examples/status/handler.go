@@ -6,6 +6,7 @@ import (
 	"github.com/oklahomer/go-sarah/v4"
 	"net/http"
 	"runtime"
+	"time"
 )
 
 // setStatusHandler sets an endpoint that returns current status of go-sarah, its belonging sarah.Bot implementations and sarah.Worker.
@@ -37,18 +38,26 @@ import (
 //	  "runtime": {
 //	    "goroutine_count": 115,
 //	    "cpu_count": 4,
-//	    "gc_count": 1
+//	    "gc_count": 1,
+//	    "heap_alloc_bytes": 2621440,
+//	    "gc_pause_total_ms": 0.12,
+//	    "uptime_sec": 45
 //	  },
 //	  "bot_system": {
 //	    "running": true,
 //	    "bots": [
 //	      {
 //	        "type": "nullBot",
-//	        "running": true
+//	        "running": true,
+//	        "restart_count": 0
 //	      },
 //	      {
 //	        "type": "slack",
-//	        "running": true
+//	        "running": true,
+//	        "last_error": "failed to connect: i/o timeout",
+//	        "last_error_at": "2018-06-23T15:20:12.102314568+09:00",
+//	        "restart_count": 1,
+//	        "connected_since": "2018-06-23T15:20:13.274064679+09:00"
 //	      }
 //	    ]
 //	  }
@@ -60,20 +69,41 @@ func setStatusHandler(mux *http.ServeMux, ws *workerStats) {
 		systemStatus.Running = runnerStatus.Running
 		for _, b := range runnerStatus.Bots {
 			bs := &botStatus{
-				BotType: b.Type,
-				Running: b.Running,
+				BotType:      b.Type,
+				Running:      b.Running,
+				LastError:    b.LastError,
+				RestartCount: b.RestartCount,
+			}
+			if !b.LastErrorAt.IsZero() {
+				bs.LastErrorAt = &b.LastErrorAt
+			}
+			if !b.ConnectedSince.IsZero() {
+				bs.ConnectedSince = &b.ConnectedSince
+			}
+			if b.Drain != nil {
+				bs.Drain = &drainStatus{
+					StartedAt: b.Drain.StartedAt,
+					Pending:   b.Drain.Pending,
+					Rejected:  b.Drain.Rejected,
+					TimedOut:  b.Drain.TimedOut,
+				}
+				if !b.Drain.FinishedAt.IsZero() {
+					bs.Drain.FinishedAt = &b.Drain.FinishedAt
+				}
 			}
 			systemStatus.Bots = append(systemStatus.Bots, bs)
 		}
 
-		var memStats runtime.MemStats
-		runtime.ReadMemStats(&memStats)
+		runtimeStats := runnerStatus.Runtime
 		status := &status{
 			Worker: ws.history(),
 			Runtime: &runtimeStatus{
-				NumGoroutine: runtime.NumGoroutine(),
+				NumGoroutine: runtimeStats.Goroutines,
 				NumCPU:       runtime.NumCPU(),
-				NumGC:        memStats.NumGC,
+				NumGC:        runtimeStats.NumGC,
+				HeapAlloc:    runtimeStats.HeapAlloc,
+				PauseTotal:   float64(runtimeStats.PauseTotal) / float64(time.Millisecond),
+				Uptime:       int64(runtimeStats.Uptime / time.Second),
 			},
 			BotRunner: systemStatus,
 		}
@@ -95,14 +125,31 @@ type status struct {
 }
 
 type runtimeStatus struct {
-	NumGoroutine int    `json:"goroutine_count"`
-	NumCPU       int    `json:"cpu_count"`
-	NumGC        uint32 `json:"gc_count"`
+	NumGoroutine int     `json:"goroutine_count"`
+	NumCPU       int     `json:"cpu_count"`
+	NumGC        uint32  `json:"gc_count"`
+	HeapAlloc    uint64  `json:"heap_alloc_bytes"`
+	PauseTotal   float64 `json:"gc_pause_total_ms"`
+	Uptime       int64   `json:"uptime_sec"`
 }
 
 type botStatus struct {
-	BotType sarah.BotType `json:"type"`
-	Running bool          `json:"running"`
+	BotType        sarah.BotType `json:"type"`
+	Running        bool          `json:"running"`
+	LastError      string        `json:"last_error,omitempty"`
+	LastErrorAt    *time.Time    `json:"last_error_at,omitempty"`
+	RestartCount   int           `json:"restart_count"`
+	ConnectedSince *time.Time    `json:"connected_since,omitempty"`
+	Drain          *drainStatus  `json:"drain,omitempty"`
+}
+
+// drainStatus is present while and after the Bot drains its in-flight inputs on shutdown.
+type drainStatus struct {
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Pending    int        `json:"pending"`
+	Rejected   int        `json:"rejected"`
+	TimedOut   bool       `json:"timed_out"`
 }
 
 type botSystemStatus struct {
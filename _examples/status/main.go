@@ -16,7 +16,6 @@ import (
 	"github.com/oklahomer/go-sarah/v4/slack"
 	"os"
 	"os/signal"
-	"time"
 )
 
 func main() {
@@ -46,12 +45,23 @@ func main() {
 	}
 	sarah.RegisterBot(slackBot)
 
-	// Setup worker
+	// Setup worker.
+	// The worker keeps running until the Runner stops so the Bots can drain their in-flight Inputs on shutdown.
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
 	workerReporter := &workerStats{}
 	reporterOpt := worker.WithReporter(workerReporter)
-	wkr := worker.Run(ctx, cfg.Worker, reporterOpt)
+	wkr := worker.Run(workerCtx, cfg.Worker, reporterOpt)
 	sarah.RegisterWorker(wkr)
 
+	// Get notified when all Bots finish draining and stop.
+	stopped := make(chan struct{})
+	sarah.RegisterLifecycleHook(func(event *sarah.LifecycleEvent) {
+		if event.Type == sarah.RunnerStopped {
+			close(stopped)
+		}
+	})
+
 	// Setup a Runner to run and supervise above bots
 	err = sarah.Run(ctx, cfg.Runner)
 	if err != nil {
@@ -59,7 +69,10 @@ func main() {
 	}
 
 	// Run HTTP server that reports current status
-	server := newServer(workerReporter)
+	server, err := newServer(workerReporter, cfg.Debug)
+	if err != nil {
+		panic(err)
+	}
 	go server.Run(ctx)
 
 	// Wait til signal reception
@@ -70,7 +83,7 @@ func main() {
 	// Stop
 	logger.Info("Stopping due to signal reception.")
 	cancel()
-	time.Sleep(1 * time.Second) // Wait a bit til things finish
+	<-stopped
 }
 
 func setupSlackBot(cfg *config) (sarah.Bot, error) {
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4/contrib/debughttp"
 	"net/http"
 	"runtime"
 )
@@ -11,12 +12,19 @@ type server struct {
 	sv *http.Server
 }
 
-func newServer(wsr *workerStats) *server {
+func newServer(wsr *workerStats, debugConfig *debughttp.Config) (*server, error) {
 	mux := http.NewServeMux()
 	setStatusHandler(mux, wsr)
+
+	// Mount pprof and other debug endpoints only when enabled in the configuration file.
+	err := debughttp.Mount(mux, debugConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &server{
 		sv: &http.Server{Addr: ":8080", Handler: mux},
-	}
+	}, nil
 }
 
 func (s *server) Run(ctx context.Context) {
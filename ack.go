@@ -0,0 +1,34 @@
+package sarah
+
+// AckableInput is an optional interface that an Input implementation can satisfy to be acknowledged after its processing.
+// A webhook-based Adapter typically acknowledges a delivery before the Input is processed, so the Input is lost when the process crashes in the middle.
+// Such an Adapter can instead bind an acknowledgement to the Input and hold the response to the chat service until Ack is called;
+// the chat service then redelivers the Input that is not processed.
+// Combined with a deduplication of the redelivered Inputs, the processing becomes at-least-once.
+type AckableInput interface {
+	Input
+
+	// Ack is called once the Input is processed.
+	// The given error is nil when Bot.Respond successfully handles the Input or when the Input is intentionally discarded by an InputFilterConfig.
+	// Otherwise, the error tells why the Input is not processed: the error returned by Bot.Respond or *BlockedInputError when the Input can not be enqueued.
+	Ack(err error)
+}
+
+// ackInput calls AckableInput.Ack when the given Input satisfies AckableInput.
+func ackInput(input Input, err error) {
+	// HelpInput and AbortInput are created by the Adapter from the original Input, which holds the acknowledgement.
+	switch typed := input.(type) {
+	case *HelpInput:
+		input = typed.OriginalInput
+
+	case *AbortInput:
+		input = typed.OriginalInput
+
+	}
+
+	ackable, ok := input.(AckableInput)
+	if !ok {
+		return
+	}
+	ackable.Ack(err)
+}
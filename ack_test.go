@@ -0,0 +1,117 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type DummyAckableInput struct {
+	DummyInput
+	AckFunc func(error)
+}
+
+func (i *DummyAckableInput) Ack(err error) {
+	i.AckFunc(err)
+}
+
+func Test_ackInput(t *testing.T) {
+	var acked []error
+	ackable := &DummyAckableInput{
+		AckFunc: func(err error) {
+			acked = append(acked, err)
+		},
+	}
+	err := errors.New("dummy")
+
+	ackInput(ackable, nil)
+	ackInput(NewHelpInput(ackable), err)
+	ackInput(NewAbortInput(ackable), nil)
+	ackInput(&DummyInput{}, nil) // Does not panic.
+
+	if len(acked) != 3 {
+		t.Fatalf("Unexpected number of acknowledgements: %d.", len(acked))
+	}
+	if acked[0] != nil || acked[1] != err || acked[2] != nil {
+		t.Errorf("Unexpected acknowledgements: %#v.", acked)
+	}
+}
+
+func Test_setupInputReceiver_Ack(t *testing.T) {
+	respondErr := errors.New("dummy")
+	testSets := []struct {
+		enqueueErr error
+		respondErr error
+		blocked    bool
+	}{
+		{},
+		{respondErr: respondErr},
+		{enqueueErr: errors.New("busy"), blocked: true},
+	}
+
+	for i, tt := range testSets {
+		SetupAndRun(func() {
+			worker := &DummyWorker{
+				EnqueueFunc: func(fnc func()) error {
+					if tt.enqueueErr != nil {
+						return tt.enqueueErr
+					}
+					fnc()
+					return nil
+				},
+			}
+			bot := &DummyBot{
+				BotTypeValue: "DUMMY",
+				RespondFunc: func(_ context.Context, _ Input) error {
+					return tt.respondErr
+				},
+			}
+
+			var acked []error
+			input := &DummyAckableInput{
+				AckFunc: func(err error) {
+					acked = append(acked, err)
+				},
+			}
+			_ = setupInputReceiver(context.TODO(), bot, worker, nil)(input)
+
+			if len(acked) != 1 {
+				t.Fatalf("Unexpected number of acknowledgements on test #%d: %d.", i+1, len(acked))
+			}
+			if tt.blocked {
+				if _, ok := acked[0].(*BlockedInputError); !ok {
+					t.Errorf("Expected error is not given on test #%d: %#v.", i+1, acked[0])
+				}
+				return
+			}
+			if acked[0] != tt.respondErr {
+				t.Errorf("Unexpected error is given on test #%d: %#v.", i+1, acked[0])
+			}
+		})
+	}
+}
+
+func Test_filteringInputReceiver_Ack(t *testing.T) {
+	received := false
+	receive := filteringInputReceiver(context.TODO(), func() *InputFilterConfig {
+		return &InputFilterConfig{AllowedChannels: []string{"general"}}
+	}, func(_ Input) error {
+		received = true
+		return nil
+	})
+
+	acked := false
+	_ = receive(&DummyAckableInput{
+		DummyInput: DummyInput{ReplyToValue: "random"},
+		AckFunc: func(err error) {
+			acked = err == nil
+		},
+	})
+
+	if received {
+		t.Error("Discarded input is received.")
+	}
+	if !acked {
+		t.Error("Discarded input is not acknowledged.")
+	}
+}
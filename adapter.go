@@ -1,6 +1,9 @@
 package sarah
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Adapter defines an interface that each bot adapter implementation must satisfy.
 // An instance of its implementation and DefaultBotOption values can be passed to NewBot to set up a bot.
@@ -21,3 +24,24 @@ type Adapter interface {
 	// This must be capable of being called simultaneously by multiple workers.
 	SendMessage(context.Context, Output)
 }
+
+// ConnectionStats represents the statistics of the connection between an Adapter and its chat service.
+type ConnectionStats struct {
+	// Connected indicates if the connection is currently established.
+	Connected bool
+
+	// Reconnects is the number of re-connections made after the initial connection.
+	Reconnects uint64
+
+	// LastConnectedAt is the time when the latest connection was established.
+	// This is zero value when no connection has been established yet.
+	LastConnectedAt time.Time
+}
+
+// ConnectionStatsReporter defines an interface that an Adapter implementation may satisfy to report its connection statistics.
+// When an Adapter passed to NewBot satisfies this, the statistics are included in the Bot's BotStatus.
+type ConnectionStatsReporter interface {
+	// ConnectionStats returns the current statistics.
+	// This may return nil when the Adapter does not maintain a persistent connection in the current setting.
+	ConnectionStats() *ConnectionStats
+}
@@ -19,3 +19,12 @@ func (adapter *DummyAdapter) Run(ctx context.Context, enqueueInput func(Input) e
 func (adapter *DummyAdapter) SendMessage(ctx context.Context, output Output) {
 	adapter.SendMessageFunc(ctx, output)
 }
+
+type DummyConnectionStatsAdapter struct {
+	DummyAdapter
+	ConnectionStatsFunc func() *ConnectionStats
+}
+
+func (adapter *DummyConnectionStatsAdapter) ConnectionStats() *ConnectionStats {
+	return adapter.ConnectionStatsFunc()
+}
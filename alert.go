@@ -40,29 +40,39 @@ func (a *alerters) appendAlerter(alerter Alerter) {
 	*a = append(*a, alerter)
 }
 
+// alertAll sends the given error to all registered Alerters concurrently so a slow Alerter does not delay the others.
+// When the given context is canceled or its deadline is exceeded, this stops waiting for the remaining Alerters and reports them as failures.
+// The returned errors are ordered in the same order as the Alerters' registration.
 func (a *alerters) alertAll(ctx context.Context, botType BotType, err error) error {
+	results := make([]chan error, len(*a))
+	for i, alerter := range *a {
+		result := make(chan error, 1)
+		results[i] = result
+		go func(alerter Alerter) {
+			result <- alert(ctx, alerter, botType, err)
+		}(alerter)
+	}
+
 	errs := &alertErrs{}
-	for _, alerter := range *a {
-		// Considering the irregular state of Bot's lifecycle and importance of alert,
-		// it is safer to be panic-proof.
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					e, ok := r.(error)
-					if ok {
-						errs.appendError(fmt.Errorf("panic on alerting via %T: %w", alerter, e))
-						return
-					}
-
-					errs.appendError(fmt.Errorf("panic on alerting via %T: %+v", alerter, r))
-				}
-			}()
-
-			err := alerter.Alert(ctx, botType, err)
-			if err != nil {
-				errs.appendError(fmt.Errorf("failed to send alert via %T: %w", alerter, err))
+	for i, result := range results {
+		var e error
+		select {
+		case e = <-result:
+			// O.K.
+
+		case <-ctx.Done():
+			// Prefer the result when the Alerter has already finished.
+			select {
+			case e = <-result:
+			default:
+				e = fmt.Errorf("failed to send alert via %T: %w", (*a)[i], ctx.Err())
 			}
-		}()
+
+		}
+
+		if e != nil {
+			errs.appendError(e)
+		}
 	}
 
 	if errs.isEmpty() {
@@ -70,3 +80,25 @@ func (a *alerters) alertAll(ctx context.Context, botType BotType, err error) err
 	}
 	return errs
 }
+
+// alert sends the given error via the given Alerter.
+// Considering the irregular state of Bot's lifecycle and importance of alert, this is panic-proof.
+func alert(ctx context.Context, alerter Alerter, botType BotType, err error) (alertErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(error)
+			if ok {
+				alertErr = fmt.Errorf("panic on alerting via %T: %w", alerter, e)
+				return
+			}
+
+			alertErr = fmt.Errorf("panic on alerting via %T: %+v", alerter, r)
+		}
+	}()
+
+	e := alerter.Alert(ctx, botType, err)
+	if e != nil {
+		return fmt.Errorf("failed to send alert via %T: %w", alerter, e)
+	}
+	return nil
+}
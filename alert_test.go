@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 type DummyAlerter struct {
@@ -130,3 +131,52 @@ func TestAlerters_alertAll(t *testing.T) {
 		t.Errorf("Expected error is not wrapped: %+v", (*typed)[2])
 	}
 }
+
+func TestAlerters_alertAll_Concurrency(t *testing.T) {
+	alerted := make(chan struct{}, 1)
+	blocked := make(chan struct{})
+	defer close(blocked)
+	a := &alerters{
+		&DummyAlerter{
+			AlertFunc: func(_ context.Context, _ BotType, _ error) error {
+				// Do not respond even though the context is canceled.
+				<-blocked
+				return nil
+			},
+		},
+		&DummyAlerter{
+			AlertFunc: func(_ context.Context, _ BotType, _ error) error {
+				alerted <- struct{}{}
+				return nil
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := a.alertAll(ctx, "FOO", errors.New("error"))
+
+	select {
+	case <-alerted:
+		// O.K.
+
+	default:
+		t.Error("The second Alerter should not be blocked by the first one.")
+
+	}
+
+	if err == nil {
+		t.Fatal("Expected error to be returned.")
+	}
+
+	typed, ok := err.(*alertErrs)
+	if !ok {
+		t.Fatalf("Expected error type of *alertErrs, but was %T.", err)
+	}
+	if len(*typed) != 1 {
+		t.Fatalf("Expected 1 error to be stored: %#v.", err)
+	}
+	if !errors.Is((*typed)[0], context.DeadlineExceeded) {
+		t.Errorf("Expected error is not wrapped: %+v", (*typed)[0])
+	}
+}
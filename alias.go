@@ -0,0 +1,110 @@
+package sarah
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// aliasInput is an Input that replaces the alias at the beginning of the message with the Command's current name.
+type aliasInput struct {
+	Input
+	message string
+}
+
+func (i *aliasInput) Message() string {
+	return i.message
+}
+
+// matchedAlias returns the alias that the given Input starts with. This returns an empty string when no alias matches.
+func (command *defaultCommand) matchedAlias(input Input) string {
+	if len(command.aliases) == 0 {
+		return ""
+	}
+
+	message := input.Message()
+	for _, alias := range command.aliases {
+		if !strings.HasPrefix(message, alias) {
+			continue
+		}
+
+		// Make sure the alias is not a part of a longer word. e.g. ".old" must not match ".older".
+		if endsWord(message[len(alias):]) {
+			return alias
+		}
+	}
+	return ""
+}
+
+// executeAlias executes the Command with the given Input that starts with the given alias and appends the deprecation notice to the response.
+func (command *defaultCommand) executeAlias(ctx context.Context, input Input, alias string) (*CommandResponse, error) {
+	name := command.aliasTarget
+	if name != "" {
+		// Let the Command parse the Input as if the current name is given.
+		input = &aliasInput{
+			Input:   input,
+			message: name + strings.TrimPrefix(input.Message(), alias),
+		}
+	}
+
+	res, err := command.execute(ctx, input)
+	if err != nil {
+		return res, err
+	}
+
+	notice := command.deprecation
+	if notice == "" {
+		notice = fmt.Sprintf("%s is deprecated.", alias)
+		if name != "" {
+			notice = fmt.Sprintf("%s is deprecated. Use %s instead.", alias, name)
+		}
+	}
+	return appendDeprecationNotice(res, notice), nil
+}
+
+// appendDeprecationNotice appends the given notice to the response content.
+// The notice is appended when the content is a string or *RichContent; other types of contents are returned as-is
+// since the form of the content depends on the Adapter.
+func appendDeprecationNotice(res *CommandResponse, notice string) *CommandResponse {
+	if res == nil {
+		return &CommandResponse{Content: notice}
+	}
+
+	switch content := res.Content.(type) {
+	case nil:
+		res.Content = notice
+
+	case string:
+		res.Content = content + "\n\n" + notice
+
+	case *RichContent:
+		// Copy the content so a content shared among responses is not modified.
+		copied := *content
+		if len(copied.Blocks) == 0 {
+			// Text is rendered only when there is no block.
+			copied.Text = strings.TrimLeft(copied.Text+"\n\n"+notice, "\n")
+		} else {
+			copied.Blocks = append(slices.Clone(copied.Blocks), &SectionBlock{Text: notice})
+		}
+		res.Content = &copied
+
+	}
+	return res
+}
+
+// aliasedPrefix returns the literal prefix that the Command's pattern and all the aliases share, so the Command is still indexed by PrefixedCommand.
+func aliasedPrefix(prefix string, aliases []string) string {
+	if prefix == "" {
+		return ""
+	}
+
+	for _, alias := range aliases {
+		i := 0
+		for i < len(prefix) && i < len(alias) && prefix[i] == alias[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	return prefix
+}
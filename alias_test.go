@@ -0,0 +1,180 @@
+package sarah
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func buildAliasedCommand(t *testing.T, deprecation string, content func(Input) interface{}) Command {
+	builder := NewCommandPropsBuilder().
+		BotType("dummy").
+		Identifier("weather").
+		Instruction("dummy").
+		MatchPattern(regexp.MustCompile(`^\.weather\b`)).
+		Alias(".forecast").
+		Alias(".wthr").
+		Func(func(_ context.Context, input Input) (*CommandResponse, error) {
+			return &CommandResponse{Content: content(input)}, nil
+		})
+	if deprecation != "" {
+		builder.Deprecated(deprecation)
+	}
+
+	props, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	command, err := buildCommand(context.TODO(), props, &DummyConfigWatcher{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	return command
+}
+
+func TestCommandPropsBuilder_Alias(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+	builder.Alias(".old").Alias(".older")
+
+	if len(builder.props.aliases) != 2 || builder.props.aliases[0] != ".old" || builder.props.aliases[1] != ".older" {
+		t.Errorf("Unexpected aliases are set: %#v.", builder.props.aliases)
+	}
+}
+
+func TestCommandPropsBuilder_Deprecated(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+	builder.Deprecated("Use .new instead.")
+
+	if builder.props.deprecation != "Use .new instead." {
+		t.Errorf("Unexpected notice is set: %s.", builder.props.deprecation)
+	}
+}
+
+func TestDefaultCommand_Alias(t *testing.T) {
+	command := buildAliasedCommand(t, "", func(input Input) interface{} {
+		return input.Message()
+	})
+
+	testSets := []struct {
+		message  string
+		match    bool
+		expected string
+	}{
+		{
+			message:  ".weather tokyo",
+			match:    true,
+			expected: ".weather tokyo",
+		},
+		{
+			message:  ".forecast tokyo",
+			match:    true,
+			expected: ".weather tokyo\n\n.forecast is deprecated. Use .weather instead.",
+		},
+		{
+			message:  ".wthr",
+			match:    true,
+			expected: ".weather\n\n.wthr is deprecated. Use .weather instead.",
+		},
+		{
+			message: ".forecasts",
+			match:   false,
+		},
+		{
+			message: "say .forecast",
+			match:   false,
+		},
+	}
+
+	for i, tt := range testSets {
+		input := &DummyInput{MessageValue: tt.message}
+		if command.Match(input) != tt.match {
+			t.Errorf("Unexpected match result on test #%d.", i+1)
+			continue
+		}
+		if !tt.match {
+			continue
+		}
+
+		res, err := command.Execute(context.TODO(), input)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if res.Content != tt.expected {
+			t.Errorf("Unexpected content is returned on test #%d: %#v.", i+1, res.Content)
+		}
+	}
+
+	if command.(PrefixedCommand).MatchPrefix() != "." {
+		t.Errorf("Unexpected prefix is returned: %s.", command.(PrefixedCommand).MatchPrefix())
+	}
+}
+
+func TestDefaultCommand_Alias_Deprecated(t *testing.T) {
+	command := buildAliasedCommand(t, "Use .weather from now on.", func(_ Input) interface{} {
+		return "sunny"
+	})
+
+	res, err := command.Execute(context.TODO(), &DummyInput{MessageValue: ".forecast"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if res.Content != "sunny\n\nUse .weather from now on." {
+		t.Errorf("Unexpected content is returned: %#v.", res.Content)
+	}
+}
+
+func Test_appendDeprecationNotice(t *testing.T) {
+	res := appendDeprecationNotice(nil, "notice")
+	if res.Content != "notice" {
+		t.Errorf("Unexpected content is returned: %#v.", res.Content)
+	}
+
+	res = appendDeprecationNotice(&CommandResponse{}, "notice")
+	if res.Content != "notice" {
+		t.Errorf("Unexpected content is returned: %#v.", res.Content)
+	}
+
+	shared := &RichContent{Text: "text"}
+	res = appendDeprecationNotice(&CommandResponse{Content: shared}, "notice")
+	if res.Content.(*RichContent).Text != "text\n\nnotice" {
+		t.Errorf("Unexpected text is returned: %s.", res.Content.(*RichContent).Text)
+	}
+	if shared.Text != "text" {
+		t.Error("Given content should not be modified.")
+	}
+
+	blocks := &RichContent{Blocks: []ContentBlock{&HeaderBlock{Text: "header"}}}
+	res = appendDeprecationNotice(&CommandResponse{Content: blocks}, "notice")
+	appended := res.Content.(*RichContent).Blocks
+	if len(appended) != 2 || appended[1].(*SectionBlock).Text != "notice" {
+		t.Errorf("Unexpected blocks are returned: %#v.", appended)
+	}
+
+	other := struct{}{}
+	res = appendDeprecationNotice(&CommandResponse{Content: other}, "notice")
+	if res.Content != other {
+		t.Errorf("Unexpected content is returned: %#v.", res.Content)
+	}
+}
+
+func Test_aliasedPrefix(t *testing.T) {
+	testSets := []struct {
+		prefix   string
+		aliases  []string
+		expected string
+	}{
+		{prefix: ".weather", aliases: nil, expected: ".weather"},
+		{prefix: ".weather", aliases: []string{".forecast"}, expected: "."},
+		{prefix: ".weather", aliases: []string{"weather"}, expected: ""},
+		{prefix: "", aliases: []string{".forecast"}, expected: ""},
+	}
+
+	for i, tt := range testSets {
+		prefix := aliasedPrefix(tt.prefix, tt.aliases)
+		if prefix != tt.expected {
+			t.Errorf("Unexpected prefix is returned on test #%d: %s.", i+1, prefix)
+		}
+	}
+}
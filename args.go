@@ -0,0 +1,285 @@
+package sarah
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArgsSpec declares the arguments that a Command accepts following its name, such as ".build deploy --branch develop".
+// The same spec parses Input.Message with Parse and generates the instruction with Instruction,
+// so the help message always reflects the actual parsing rules.
+//
+//	var spec = &sarah.ArgsSpec{
+//		Command: ".build",
+//		Args: []*sarah.ArgSpec{
+//			{Name: "job", Description: "The job to build."},
+//		},
+//		Flags: []*sarah.FlagSpec{
+//			{Name: "branch", Description: "The branch to build.", Default: "main"},
+//		},
+//		Examples: []string{".build deploy --branch develop"},
+//	}
+//
+//	sarah.NewCommandPropsBuilder().
+//		Match(sarah.MatchPrefix(spec.Command)).
+//		Args(spec).
+//		Func(func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+//			args, err := spec.Parse(input.Message())
+//			if err != nil {
+//				return slack.NewResponse(input, err.Error()+"\n"+spec.Instruction(input))
+//			}
+//			return slack.NewResponse(input, "Building "+args.Value("job")+" on "+args.Flag("branch"))
+//		})
+type ArgsSpec struct {
+	// Command is the name of the Command that precedes the arguments such as ".build".
+	Command string
+
+	// Args declares the positional arguments in order.
+	Args []*ArgSpec
+
+	// Flags declares the flags given in a form of --name value, --name=value, or --name for a boolean flag.
+	Flags []*FlagSpec
+
+	// Examples declares the example messages that are shown in the instruction.
+	Examples []string
+}
+
+// ArgSpec declares a positional argument.
+type ArgSpec struct {
+	// Name is the name of the argument to refer to the parsed value with Args.Value.
+	Name string
+
+	// Description describes the argument in the instruction.
+	Description string
+
+	// Optional declares that the argument may be omitted.
+	// Only the trailing arguments can be optional.
+	Optional bool
+
+	// Variadic declares that the argument receives all the remaining values. Only the last argument can be variadic.
+	Variadic bool
+}
+
+// FlagSpec declares a flag.
+type FlagSpec struct {
+	// Name is the name of the flag without the leading "--".
+	Name string
+
+	// Description describes the flag in the instruction.
+	Description string
+
+	// Bool declares that the flag takes no value. Args.Bool returns true when the flag is given.
+	Bool bool
+
+	// Default is the value that Args.Flag returns when the flag is not given.
+	Default string
+}
+
+// Args holds the arguments parsed by ArgsSpec.Parse.
+type Args struct {
+	values map[string][]string
+	flags  map[string]string
+}
+
+// Value returns the value of the given positional argument. An empty string is returned when the argument is not given.
+// Use Values for a variadic argument.
+func (a *Args) Value(name string) string {
+	values := a.values[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Values returns all the values of the given positional argument.
+func (a *Args) Values(name string) []string {
+	return a.values[name]
+}
+
+// Flag returns the value of the given flag, or its default value when the flag is not given.
+func (a *Args) Flag(name string) string {
+	return a.flags[name]
+}
+
+// Bool returns true when the given boolean flag is given.
+func (a *Args) Bool(name string) bool {
+	return a.flags[name] == "true"
+}
+
+// Parse parses the given message that starts with the Command name.
+// The arguments are separated by whitespaces. An error describing the invalid usage is returned when the message does not satisfy the spec.
+func (spec *ArgsSpec) Parse(message string) (*Args, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(message), spec.Command)
+	if !ok || !endsWord(rest) {
+		return nil, fmt.Errorf("message does not start with %s", spec.Command)
+	}
+
+	args := &Args{
+		values: map[string][]string{},
+		flags:  map[string]string{},
+	}
+	for _, flag := range spec.Flags {
+		if !flag.Bool && flag.Default != "" {
+			args.flags[flag.Name] = flag.Default
+		}
+	}
+
+	var positional []string
+	tokens := strings.Fields(rest)
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "--" {
+			// Everything after "--" is positional.
+			positional = append(positional, tokens[i+1:]...)
+			break
+		}
+
+		name, ok := strings.CutPrefix(token, "--")
+		if !ok {
+			positional = append(positional, token)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(name, "=")
+		flag := spec.flag(name)
+		if flag == nil {
+			return nil, fmt.Errorf("unknown flag: --%s", name)
+		}
+
+		switch {
+		case flag.Bool && hasValue:
+			return nil, fmt.Errorf("flag --%s does not take a value", name)
+
+		case flag.Bool:
+			value = "true"
+
+		case !hasValue:
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("flag --%s requires a value", name)
+			}
+			i++
+			value = tokens[i]
+
+		}
+		args.flags[name] = value
+	}
+
+	for i, arg := range spec.Args {
+		if i >= len(positional) {
+			if !arg.Optional {
+				return nil, fmt.Errorf("missing argument: %s", arg.Name)
+			}
+			continue
+		}
+
+		if arg.Variadic {
+			args.values[arg.Name] = positional[i:]
+			return args, nil
+		}
+		args.values[arg.Name] = []string{positional[i]}
+	}
+	if len(positional) > len(spec.Args) {
+		return nil, fmt.Errorf("too many arguments: %s", strings.Join(positional[len(spec.Args):], " "))
+	}
+
+	return args, nil
+}
+
+func (spec *ArgsSpec) flag(name string) *FlagSpec {
+	for _, flag := range spec.Flags {
+		if flag.Name == name {
+			return flag
+		}
+	}
+	return nil
+}
+
+// UsageStyle defines how the instruction generated from ArgsSpec is decorated with the markup of the chat service.
+type UsageStyle struct {
+	// Code decorates a literal text such as the usage line and the examples.
+	Code func(string) string
+
+	// Heading decorates a heading such as "Examples:".
+	Heading func(string) string
+}
+
+// PlainUsageStyle is the UsageStyle with no decoration. This is used when the Input does not satisfy UsageStyledInput.
+var PlainUsageStyle = &UsageStyle{
+	Code:    func(s string) string { return s },
+	Heading: func(s string) string { return s },
+}
+
+// UsageStyledInput is an optional interface that an Input implementation can satisfy to decorate the instruction generated from ArgsSpec
+// with the markup of the chat service.
+type UsageStyledInput interface {
+	Input
+
+	// UsageStyle returns the style to decorate the instruction.
+	UsageStyle() *UsageStyle
+}
+
+// Instruction generates the instruction that consists of the usage line, the descriptions of the arguments and the flags, and the examples.
+// The text is decorated with the UsageStyle of the given Input when the Input satisfies UsageStyledInput; HelpInput is unwrapped for this.
+func (spec *ArgsSpec) Instruction(input Input) string {
+	if help, ok := input.(*HelpInput); ok {
+		input = help.OriginalInput
+	}
+	style := PlainUsageStyle
+	if styled, ok := input.(UsageStyledInput); ok && styled.UsageStyle() != nil {
+		style = styled.UsageStyle()
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(style.Heading("Usage:"))
+	b.WriteString(" ")
+	b.WriteString(style.Code(spec.usageLine()))
+
+	for _, arg := range spec.Args {
+		if arg.Description != "" {
+			fmt.Fprintf(b, "\n  %s: %s", arg.Name, arg.Description)
+		}
+	}
+	for _, flag := range spec.Flags {
+		description := flag.Description
+		if !flag.Bool && flag.Default != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s (default: %s)", description, flag.Default))
+		}
+		if description != "" {
+			fmt.Fprintf(b, "\n  --%s: %s", flag.Name, description)
+		}
+	}
+
+	if len(spec.Examples) > 0 {
+		b.WriteString("\n")
+		b.WriteString(style.Heading("Examples:"))
+		for _, example := range spec.Examples {
+			b.WriteString("\n  ")
+			b.WriteString(style.Code(example))
+		}
+	}
+
+	return b.String()
+}
+
+// usageLine returns the usage line such as ".build <job> [<options>...] [--branch <branch>] [--dry-run]".
+func (spec *ArgsSpec) usageLine() string {
+	parts := []string{spec.Command}
+	for _, arg := range spec.Args {
+		part := "<" + arg.Name + ">"
+		if arg.Variadic {
+			part += "..."
+		}
+		if arg.Optional {
+			part = "[" + part + "]"
+		}
+		parts = append(parts, part)
+	}
+	for _, flag := range spec.Flags {
+		part := "--" + flag.Name
+		if !flag.Bool {
+			part += " <" + flag.Name + ">"
+		}
+		parts = append(parts, "["+part+"]")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,146 @@
+package sarah
+
+import (
+	"slices"
+	"testing"
+)
+
+type DummyUsageStyledInput struct {
+	DummyInput
+}
+
+func (i *DummyUsageStyledInput) UsageStyle() *UsageStyle {
+	return &UsageStyle{
+		Code: func(s string) string {
+			return "`" + s + "`"
+		},
+		Heading: func(s string) string {
+			return "*" + s + "*"
+		},
+	}
+}
+
+var dummyArgsSpec = &ArgsSpec{
+	Command: ".build",
+	Args: []*ArgSpec{
+		{Name: "job", Description: "The job to build."},
+		{Name: "params", Description: "The build parameters.", Optional: true, Variadic: true},
+	},
+	Flags: []*FlagSpec{
+		{Name: "branch", Description: "The branch to build.", Default: "main"},
+		{Name: "dry-run", Description: "Only validate the parameters.", Bool: true},
+	},
+	Examples: []string{".build deploy --branch develop"},
+}
+
+func TestArgsSpec_Parse(t *testing.T) {
+	args, err := dummyArgsSpec.Parse(".build deploy --branch=develop key=value --dry-run foo=bar")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if args.Value("job") != "deploy" {
+		t.Errorf("Unexpected job is returned: %s.", args.Value("job"))
+	}
+	if !slices.Equal(args.Values("params"), []string{"key=value", "foo=bar"}) {
+		t.Errorf("Unexpected params are returned: %#v.", args.Values("params"))
+	}
+	if args.Flag("branch") != "develop" {
+		t.Errorf("Unexpected branch is returned: %s.", args.Flag("branch"))
+	}
+	if !args.Bool("dry-run") {
+		t.Error("Boolean flag is not set.")
+	}
+}
+
+func TestArgsSpec_Parse_Default(t *testing.T) {
+	args, err := dummyArgsSpec.Parse(".build deploy")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if args.Flag("branch") != "main" {
+		t.Errorf("Default value is not returned: %s.", args.Flag("branch"))
+	}
+	if args.Bool("dry-run") {
+		t.Error("Boolean flag is unexpectedly set.")
+	}
+	if args.Values("params") != nil {
+		t.Errorf("Unexpected params are returned: %#v.", args.Values("params"))
+	}
+}
+
+func TestArgsSpec_Parse_Error(t *testing.T) {
+	spec := &ArgsSpec{
+		Command: ".echo",
+		Args: []*ArgSpec{
+			{Name: "text"},
+		},
+		Flags: []*FlagSpec{
+			{Name: "upper", Bool: true},
+			{Name: "repeat"},
+		},
+	}
+
+	testSets := []struct {
+		message string
+		error   string
+	}{
+		{message: ".say hello", error: "message does not start with .echo"},
+		{message: ".echoes hello", error: "message does not start with .echo"},
+		{message: ".echo", error: "missing argument: text"},
+		{message: ".echo hello world", error: "too many arguments: world"},
+		{message: ".echo hello --unknown", error: "unknown flag: --unknown"},
+		{message: ".echo hello --upper=yes", error: "flag --upper does not take a value"},
+		{message: ".echo hello --repeat", error: "flag --repeat requires a value"},
+	}
+
+	for i, tt := range testSets {
+		_, err := spec.Parse(tt.message)
+		if err == nil {
+			t.Errorf("Expected error is not returned on test #%d.", i+1)
+			continue
+		}
+		if err.Error() != tt.error {
+			t.Errorf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+	}
+
+	// Values after "--" are treated as positional arguments.
+	args, err := spec.Parse(".echo -- --upper")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if args.Value("text") != "--upper" {
+		t.Errorf("Unexpected text is returned: %s.", args.Value("text"))
+	}
+}
+
+func TestArgsSpec_Instruction(t *testing.T) {
+	expected := "Usage: .build <job> [<params>...] [--branch <branch>] [--dry-run]\n" +
+		"  job: The job to build.\n" +
+		"  params: The build parameters.\n" +
+		"  --branch: The branch to build. (default: main)\n" +
+		"  --dry-run: Only validate the parameters.\n" +
+		"Examples:\n" +
+		"  .build deploy --branch develop"
+
+	instruction := dummyArgsSpec.Instruction(&DummyInput{})
+	if instruction != expected {
+		t.Errorf("Unexpected instruction is returned: %s.", instruction)
+	}
+}
+
+func TestArgsSpec_Instruction_Styled(t *testing.T) {
+	spec := &ArgsSpec{
+		Command:  ".ping",
+		Examples: []string{".ping"},
+	}
+	input := &HelpInput{OriginalInput: &DummyUsageStyledInput{}}
+
+	expected := "*Usage:* `.ping`\n*Examples:*\n  `.ping`"
+	instruction := spec.Instruction(input)
+	if instruction != expected {
+		t.Errorf("Unexpected instruction is returned: %s.", instruction)
+	}
+}
@@ -0,0 +1,133 @@
+package sarah
+
+import (
+	"context"
+	"github.com/oklahomer/go-kasumi/retry"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// minBackoffInterval is the interval used when BackoffPolicy.BaseInterval is not a positive value.
+const minBackoffInterval = 100 * time.Millisecond
+
+// BackoffPolicy declares an exponential backoff strategy with jitter.
+// While retry.Policy retries for a fixed number of trials with a constant interval,
+// this grows the interval on every failure so a chat service is not flooded with requests during its outage.
+// Adapter implementations accept this wherever retry.Policy is accepted, and prefer this one when both are set.
+//
+//	backoff:
+//	  base_interval: 500ms
+//	  max_interval: 1m
+//	  multiplier: 2
+//	  max_elapsed_time: 15m
+//	  jitter: 0.5
+type BackoffPolicy struct {
+	// BaseInterval declares the interval before the first retrial.
+	// A value of zero or less is treated as 100 milliseconds so a failing function is not called in a tight loop.
+	BaseInterval time.Duration `json:"base_interval" yaml:"base_interval"`
+
+	// MaxInterval declares the upper limit of the interval between trials.
+	// Zero value means no limit.
+	MaxInterval time.Duration `json:"max_interval" yaml:"max_interval"`
+
+	// Multiplier declares the factor to multiply the interval on every failure.
+	// A value smaller than 1 is treated as 1, which means a constant interval.
+	Multiplier float64 `json:"multiplier" yaml:"multiplier"`
+
+	// MaxElapsedTime declares the time limit to give up retrying.
+	// The retrial stops when the next interval exceeds this limit. Zero value means retrying until the context is canceled.
+	MaxElapsedTime time.Duration `json:"max_elapsed_time" yaml:"max_elapsed_time"`
+
+	// Jitter declares the randomization factor of each interval.
+	// With 0.5, an interval of 1 second becomes a random duration between 0.5 and 1.5 seconds.
+	// The value is rounded to a range of 0 to 1.
+	Jitter float64 `json:"jitter" yaml:"jitter"`
+}
+
+// NewBackoffPolicy creates and returns a new BackoffPolicy instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewBackoffPolicy() *BackoffPolicy {
+	return &BackoffPolicy{
+		BaseInterval:   500 * time.Millisecond,
+		MaxInterval:    1 * time.Minute,
+		Multiplier:     2,
+		MaxElapsedTime: 15 * time.Minute,
+		Jitter:         0.5,
+	}
+}
+
+// Interval returns the interval to wait after the given number of failed attempts.
+// The first failure is represented by 1.
+func (p *BackoffPolicy) Interval(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	if attempt < 1 {
+		attempt = 1
+	}
+	base := p.BaseInterval
+	if base <= 0 {
+		base = minBackoffInterval
+	}
+	interval := float64(base) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && interval > float64(p.MaxInterval) {
+		interval = float64(p.MaxInterval)
+	}
+
+	jitter := math.Max(0, math.Min(1, p.Jitter))
+	if jitter > 0 {
+		delta := jitter * interval
+		interval = interval - delta + rand.Float64()*2*delta
+	}
+
+	if interval > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(interval)
+}
+
+// RetryWithBackoff calls the given function until it succeeds, the given context is canceled, or BackoffPolicy.MaxElapsedTime elapses.
+// When the context derives from the one that Sarah gives to a Bot, the intervals follow the Clock registered via RegisterClock.
+// When the function never succeeds, this returns *retry.Errors that contains all errors just like retry.WithPolicy does,
+// so retry.LastErrorOf can be used to inspect the last error.
+func RetryWithBackoff(ctx context.Context, policy *BackoffPolicy, function func() error) error {
+	errs := &retry.Errors{}
+	clock := clockFromContext(ctx)
+	startedAt := clock.Now()
+	for attempt := 1; ; attempt++ {
+		err := function()
+		if err == nil {
+			return nil
+		}
+		*errs = append(*errs, err)
+
+		interval := policy.Interval(attempt)
+		if policy.MaxElapsedTime > 0 && clock.Now().Sub(startedAt)+interval > policy.MaxElapsedTime {
+			return errs
+		}
+
+		timer := clock.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			*errs = append(*errs, ctx.Err())
+			return errs
+
+		case <-timer.C():
+			// Try again.
+
+		}
+	}
+}
+
+// Retry calls the given function with the given BackoffPolicy if it is not nil, or with the given retry.Policy otherwise.
+// This is a convenient function for Adapter implementations that accept both retry strategies.
+func Retry(ctx context.Context, backoff *BackoffPolicy, policy *retry.Policy, function func() error) error {
+	if backoff != nil {
+		return RetryWithBackoff(ctx, backoff, function)
+	}
+	return retry.WithPolicy(policy, function)
+}
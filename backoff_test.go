@@ -0,0 +1,208 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/retry"
+	"gopkg.in/yaml.v2"
+	"testing"
+	"time"
+)
+
+func TestNewBackoffPolicy(t *testing.T) {
+	policy := NewBackoffPolicy()
+
+	if policy.BaseInterval <= 0 || policy.MaxInterval < policy.BaseInterval {
+		t.Errorf("Unexpected default interval is set: %#v.", policy)
+	}
+
+	if policy.Multiplier <= 1 {
+		t.Errorf("Unexpected default multiplier is set: %f.", policy.Multiplier)
+	}
+}
+
+func TestBackoffPolicy_UnmarshalYAML(t *testing.T) {
+	input := []byte(`
+base_interval: 1s
+max_interval: 30s
+multiplier: 1.5
+max_elapsed_time: 5m
+jitter: 0.2
+`)
+
+	policy := NewBackoffPolicy()
+	err := yaml.Unmarshal(input, policy)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	expected := &BackoffPolicy{
+		BaseInterval:   1 * time.Second,
+		MaxInterval:    30 * time.Second,
+		Multiplier:     1.5,
+		MaxElapsedTime: 5 * time.Minute,
+		Jitter:         0.2,
+	}
+	if *policy != *expected {
+		t.Errorf("Unexpected value is set: %#v.", policy)
+	}
+}
+
+func TestBackoffPolicy_Interval(t *testing.T) {
+	testSets := []struct {
+		policy   *BackoffPolicy
+		attempt  int
+		expected time.Duration
+	}{
+		{
+			policy:   &BackoffPolicy{BaseInterval: time.Second, Multiplier: 2},
+			attempt:  1,
+			expected: time.Second,
+		},
+		{
+			policy:   &BackoffPolicy{BaseInterval: time.Second, Multiplier: 2},
+			attempt:  3,
+			expected: 4 * time.Second,
+		},
+		{
+			policy:   &BackoffPolicy{BaseInterval: time.Second, Multiplier: 2, MaxInterval: 3 * time.Second},
+			attempt:  3,
+			expected: 3 * time.Second,
+		},
+		{
+			policy:   &BackoffPolicy{BaseInterval: time.Second, Multiplier: 0},
+			attempt:  5,
+			expected: time.Second,
+		},
+		{
+			policy:   &BackoffPolicy{BaseInterval: time.Second, Multiplier: 2},
+			attempt:  0,
+			expected: time.Second,
+		},
+		{
+			policy:   &BackoffPolicy{},
+			attempt:  3,
+			expected: minBackoffInterval,
+		},
+		{
+			policy:   &BackoffPolicy{BaseInterval: -1 * time.Second, Multiplier: 2},
+			attempt:  2,
+			expected: 2 * minBackoffInterval,
+		},
+	}
+
+	for i, tt := range testSets {
+		interval := tt.policy.Interval(tt.attempt)
+		if interval != tt.expected {
+			t.Errorf("Unexpected interval is returned on test %d: %s.", i, interval)
+		}
+	}
+
+	t.Run("With jitter", func(t *testing.T) {
+		policy := &BackoffPolicy{BaseInterval: time.Second, Multiplier: 2, Jitter: 0.5}
+		for i := 0; i < 100; i++ {
+			interval := policy.Interval(2)
+			if interval < time.Second || interval > 3*time.Second {
+				t.Fatalf("Interval is out of range: %s.", interval)
+			}
+		}
+	})
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	t.Run("Succeed after failure", func(t *testing.T) {
+		policy := &BackoffPolicy{BaseInterval: time.Millisecond, Multiplier: 2}
+		trial := 0
+		err := RetryWithBackoff(context.TODO(), policy, func() error {
+			trial++
+			if trial < 3 {
+				return errors.New("error")
+			}
+			return nil
+		})
+
+		if err != nil {
+			t.Errorf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if trial != 3 {
+			t.Errorf("Unexpected number of trials: %d.", trial)
+		}
+	})
+
+	t.Run("Max elapsed time", func(t *testing.T) {
+		policy := &BackoffPolicy{BaseInterval: 10 * time.Millisecond, Multiplier: 2, MaxElapsedTime: 50 * time.Millisecond}
+		expected := errors.New("error")
+		trial := 0
+		err := RetryWithBackoff(context.TODO(), policy, func() error {
+			trial++
+			return expected
+		})
+
+		if !errors.Is(retry.LastErrorOf(err), expected) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+
+		// Trials are made at 0ms, 10ms and 30ms at most. The next trial at 70ms exceeds the limit.
+		if trial < 2 || trial > 3 {
+			t.Errorf("Unexpected number of trials: %d.", trial)
+		}
+	})
+
+	t.Run("Zero value policy", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+		trial := 0
+		_ = RetryWithBackoff(ctx, &BackoffPolicy{}, func() error {
+			trial++
+			return errors.New("error")
+		})
+
+		// Trials are made at 0ms, 100ms and 200ms. The function must not be called in a tight loop.
+		if trial > 5 {
+			t.Errorf("Unexpected number of trials: %d.", trial)
+		}
+	})
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		policy := &BackoffPolicy{BaseInterval: time.Hour}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := RetryWithBackoff(ctx, policy, func() error {
+			return errors.New("error")
+		})
+
+		if !errors.Is(retry.LastErrorOf(err), context.Canceled) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("With BackoffPolicy", func(t *testing.T) {
+		trial := 0
+		err := Retry(context.TODO(), &BackoffPolicy{BaseInterval: time.Millisecond}, &retry.Policy{Trial: 1}, func() error {
+			trial++
+			if trial < 2 {
+				return errors.New("error")
+			}
+			return nil
+		})
+
+		if err != nil {
+			t.Errorf("Unexpected error is returned: %s.", err.Error())
+		}
+	})
+
+	t.Run("With retry.Policy", func(t *testing.T) {
+		trial := 0
+		_ = Retry(context.TODO(), nil, &retry.Policy{Trial: 2}, func() error {
+			trial++
+			return errors.New("error")
+		})
+
+		if trial != 2 {
+			t.Errorf("Unexpected number of trials: %d.", trial)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package sarah
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NewBatchInput creates a new instance of an Input implementation -- BatchInput -- with the given Inputs.
+// An Adapter that receives events in batches passes the BatchInput to the function given to Bot.Run instead of passing each Input separately.
+func NewBatchInput(inputs ...Input) *BatchInput {
+	return &BatchInput{
+		Inputs: inputs,
+	}
+}
+
+// BatchInput is a common Input implementation that bundles the Inputs delivered together.
+// Sarah passes a BatchInput to the worker.Worker as a single job and calls Bot.Respond with each Input sequentially in the given order,
+// so the Inputs in a batch never run concurrently nor overtake one another.
+// Each Input is still handled individually: an error or a panic on one Input is logged and does not prevent the following Inputs from being handled.
+//
+// Because BatchInput itself must satisfy Input, the methods return the values of the first Input.
+// Use Inputs to access each bundled Input.
+type BatchInput struct {
+	Inputs []Input
+}
+
+var _ Input = (*BatchInput)(nil)
+
+// SenderKey returns the sender key of the first Input.
+func (bi *BatchInput) SenderKey() string {
+	if len(bi.Inputs) == 0 {
+		return ""
+	}
+	return bi.Inputs[0].SenderKey()
+}
+
+// Message returns the message of the first Input.
+func (bi *BatchInput) Message() string {
+	if len(bi.Inputs) == 0 {
+		return ""
+	}
+	return bi.Inputs[0].Message()
+}
+
+// SentAt returns the timestamp of the first Input.
+func (bi *BatchInput) SentAt() time.Time {
+	if len(bi.Inputs) == 0 {
+		return time.Time{}
+	}
+	return bi.Inputs[0].SentAt()
+}
+
+// ReplyTo returns the destination of the first Input.
+func (bi *BatchInput) ReplyTo() OutputDestination {
+	if len(bi.Inputs) == 0 {
+		return nil
+	}
+	return bi.Inputs[0].ReplyTo()
+}
+
+// batchAware applies the given receiver's process such as recording or filtering to each Input in a BatchInput individually.
+// The Inputs that pass through the process are bundled into a new BatchInput and are then passed to receive.
+// Any other Input is simply passed to the receiver.
+func batchAware(wrap func(receive func(Input) error) func(Input) error, receive func(Input) error) func(Input) error {
+	single := wrap(receive)
+	return func(input Input) error {
+		batch, ok := input.(*BatchInput)
+		if !ok {
+			return single(input)
+		}
+
+		var passed []Input
+		collect := wrap(func(item Input) error {
+			passed = append(passed, item)
+			return nil
+		})
+		for _, item := range batch.Inputs {
+			_ = collect(item)
+		}
+		if len(passed) == 0 {
+			return nil
+		}
+
+		return receive(NewBatchInput(passed...))
+	}
+}
+
+// respondBatch handles the Inputs in the given BatchInput one by one.
+// Each Input is given its own correlation ID so its log lines can be told apart from the other Inputs in the same batch.
+func respondBatch(ctx context.Context, bot Bot, responder CommandErrorResponder, batch *BatchInput) {
+	for _, input := range batch.Inputs {
+		itemCtx := withCorrelationID(ctx, newCorrelationID())
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err := fmt.Errorf("panic on handling a batched input: %+v", r)
+					LoggerFromContext(itemCtx).Errorf("%s", err.Error())
+					ackInput(input, err)
+				}
+			}()
+
+			respondInput(itemCtx, bot, responder, input)
+		}()
+	}
+}
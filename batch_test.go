@@ -0,0 +1,185 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewBatchInput(t *testing.T) {
+	first := &DummyInput{SenderKeyValue: "first", MessageValue: "hello", SentAtValue: time.Now(), ReplyToValue: "channel"}
+	second := &DummyInput{SenderKeyValue: "second"}
+	batch := NewBatchInput(first, second)
+
+	if len(batch.Inputs) != 2 || batch.Inputs[0] != first || batch.Inputs[1] != second {
+		t.Fatalf("Given inputs are not set: %#v.", batch.Inputs)
+	}
+
+	if batch.SenderKey() != first.SenderKey() {
+		t.Errorf("Unexpected sender key is returned: %s.", batch.SenderKey())
+	}
+	if batch.Message() != first.Message() {
+		t.Errorf("Unexpected message is returned: %s.", batch.Message())
+	}
+	if !batch.SentAt().Equal(first.SentAt()) {
+		t.Errorf("Unexpected timestamp is returned: %s.", batch.SentAt())
+	}
+	if batch.ReplyTo() != first.ReplyTo() {
+		t.Errorf("Unexpected destination is returned: %#v.", batch.ReplyTo())
+	}
+}
+
+func TestBatchInput_Empty(t *testing.T) {
+	batch := NewBatchInput()
+
+	if batch.SenderKey() != "" || batch.Message() != "" || !batch.SentAt().IsZero() || batch.ReplyTo() != nil {
+		t.Errorf("Zero values should be returned: %#v.", batch)
+	}
+}
+
+func Test_batchAware(t *testing.T) {
+	var processed []Input
+	wrap := func(receive func(Input) error) func(Input) error {
+		return func(input Input) error {
+			processed = append(processed, input)
+			if input.Message() == "discard" {
+				return nil
+			}
+			return receive(input)
+		}
+	}
+
+	var received []Input
+	receive := batchAware(wrap, func(input Input) error {
+		received = append(received, input)
+		return nil
+	})
+
+	// A non-batch input is simply passed.
+	single := &DummyInput{MessageValue: "single"}
+	_ = receive(single)
+	if len(processed) != 1 || len(received) != 1 || received[0] != single {
+		t.Fatalf("Unexpected inputs are received: %#v.", received)
+	}
+
+	// Each input in a batch is processed, and the passed ones are bundled again.
+	passed := &DummyInput{MessageValue: "pass"}
+	_ = receive(NewBatchInput(&DummyInput{MessageValue: "discard"}, passed))
+	if len(processed) != 3 {
+		t.Errorf("Each input is not processed: %#v.", processed)
+	}
+	batch, ok := received[1].(*BatchInput)
+	if !ok {
+		t.Fatalf("Unexpected input is received: %#v.", received[1])
+	}
+	if len(batch.Inputs) != 1 || batch.Inputs[0] != passed {
+		t.Errorf("Unexpected inputs are bundled: %#v.", batch.Inputs)
+	}
+
+	// Nothing is received when all inputs are discarded.
+	_ = receive(NewBatchInput(&DummyInput{MessageValue: "discard"}))
+	if len(received) != 2 {
+		t.Errorf("Empty batch is received: %#v.", received[len(received)-1])
+	}
+}
+
+func Test_setupInputReceiver_BatchInput(t *testing.T) {
+	SetupAndRun(func() {
+		enqueued := 0
+		worker := &DummyWorker{
+			EnqueueFunc: func(fnc func()) error {
+				enqueued++
+				fnc()
+				return nil
+			},
+		}
+
+		var responded []string
+		bot := &DummyBot{
+			BotTypeValue: "DUMMY",
+			RespondFunc: func(_ context.Context, input Input) error {
+				responded = append(responded, input.Message())
+				switch input.Message() {
+				case "error":
+					return errors.New("dummy")
+
+				case "panic":
+					panic("boom")
+
+				}
+				return nil
+			},
+		}
+
+		var acked []error
+		newInput := func(message string) Input {
+			return &DummyAckableInput{
+				DummyInput: DummyInput{MessageValue: message},
+				AckFunc: func(err error) {
+					acked = append(acked, err)
+				},
+			}
+		}
+
+		receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
+		err := receiveInput(NewBatchInput(newInput("first"), newInput("error"), newInput("panic"), newInput("last")))
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if enqueued != 1 {
+			t.Errorf("Batch should be enqueued as a single job: %d.", enqueued)
+		}
+
+		expected := []string{"first", "error", "panic", "last"}
+		if len(responded) != len(expected) {
+			t.Fatalf("Unexpected inputs are handled: %#v.", responded)
+		}
+		for i, message := range expected {
+			if responded[i] != message {
+				t.Errorf("Unexpected order of inputs: %#v.", responded)
+				break
+			}
+		}
+
+		if len(acked) != 4 {
+			t.Fatalf("Unexpected number of acknowledgements: %d.", len(acked))
+		}
+		if acked[0] != nil || acked[1] == nil || acked[2] == nil || acked[3] != nil {
+			t.Errorf("Unexpected acknowledgements: %#v.", acked)
+		}
+	})
+}
+
+func Test_setupInputReceiver_BatchInput_BlockedInputError(t *testing.T) {
+	SetupAndRun(func() {
+		worker := &DummyWorker{
+			EnqueueFunc: func(_ func()) error {
+				return errors.New("busy")
+			},
+		}
+
+		var acked []error
+		input := &DummyAckableInput{
+			AckFunc: func(err error) {
+				acked = append(acked, err)
+			},
+		}
+
+		receiveInput := setupInputReceiver(context.TODO(), &DummyBot{}, worker, nil)
+		err := receiveInput(NewBatchInput(input, input))
+		if _, ok := err.(*BlockedInputError); !ok {
+			t.Fatalf("Expected error is not returned: %#v.", err)
+		}
+
+		if len(acked) != 2 {
+			t.Fatalf("Each input is not acknowledged: %#v.", acked)
+		}
+		for _, e := range acked {
+			if e != err {
+				t.Errorf("Unexpected error is given: %#v.", e)
+			}
+		}
+	})
+}
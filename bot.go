@@ -2,7 +2,10 @@ package sarah
 
 import (
 	"context"
-	"github.com/oklahomer/go-kasumi/logger"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Bot defines an interface that each interacting bot must satisfy.
@@ -57,10 +60,21 @@ type Bot interface {
 
 type defaultBot struct {
 	botType            BotType
+	adapterType        string
 	runFunc            func(context.Context, func(Input) error, func(error))
 	sendMessageFunc    func(context.Context, Output)
 	commands           *Commands
 	userContextStorage UserContextStorage
+	reminder           *expirationReminder
+	connStatsReporter  ConnectionStatsReporter
+	dmResolver         DirectMessageResolver
+	editor             MessageEditor
+	deleter            MessageDeleter
+	sensitiveSenders   map[string]struct{}
+	sensitiveMutex     sync.Mutex
+	duplicates         *duplicateSuppressor
+	transformers       []OutputTransformer
+	sendLatency        int64
 }
 
 // NewBot creates a new defaultBot instance with the given Adapter implementation.
@@ -88,10 +102,28 @@ type defaultBot struct {
 func NewBot(adapter Adapter, options ...DefaultBotOption) Bot {
 	bot := &defaultBot{
 		botType:            adapter.BotType(),
+		adapterType:        fmt.Sprintf("%T", adapter),
 		runFunc:            adapter.Run,
 		sendMessageFunc:    adapter.SendMessage,
 		commands:           NewCommands(),
 		userContextStorage: nil,
+		reminder:           nil,
+	}
+
+	if reporter, ok := adapter.(ConnectionStatsReporter); ok {
+		bot.connStatsReporter = reporter
+	}
+
+	if resolver, ok := adapter.(DirectMessageResolver); ok {
+		bot.dmResolver = resolver
+	}
+
+	if editor, ok := adapter.(MessageEditor); ok {
+		bot.editor = editor
+	}
+
+	if deleter, ok := adapter.(MessageDeleter); ok {
+		bot.deleter = deleter
 	}
 
 	for _, opt := range options {
@@ -117,12 +149,51 @@ func BotWithStorage(storage UserContextStorage) DefaultBotOption {
 	}
 }
 
+// BotWithExpirationReminder creates and returns a DefaultBotOption to remind a user that the stored UserContext is about to expire.
+// When a UserContext is stored, a reminder is scheduled to be sent threshold before its expiration.
+// The reminder is canceled when the user continues the conversation in time.
+//
+// defaultTTL is used to calculate the expiration of a UserContext without UserContext.TTL,
+// so this must be equal to the UserContextStorage's default expiration such as CacheConfig.ExpiresIn.
+// content is called to build a reminder message for the Input that set the UserContext; the form of the returned value depends on the Adapter.
+//
+//  opt := sarah.BotWithExpirationReminder(30*time.Second, 3*time.Minute, func(_ sarah.Input) interface{} {
+//  	return "Your input is still awaited. Type .abort to quit."
+//  })
+//  bot := sarah.NewBot(myAdapter, sarah.BotWithStorage(storage), opt)
+func BotWithExpirationReminder(threshold time.Duration, defaultTTL time.Duration, content func(Input) interface{}) DefaultBotOption {
+	return func(bot *defaultBot) {
+		bot.reminder = &expirationReminder{
+			threshold:  threshold,
+			defaultTTL: defaultTTL,
+			content:    content,
+			timers:     map[string]Timer{},
+		}
+	}
+}
+
+// BotWithInstanceName creates and returns a DefaultBotOption to name the Bot so the same Adapter implementation can run more than once.
+// The Bot's BotType becomes the Adapter's BotType with the given name such as "slack:support". See BotType.Instance.
+// Because Commands and ScheduledTasks are registered per BotType, register them with the named BotType, AnyBotType, or CommandPropsBuilder.BotTypes.
+//
+//	main := sarah.NewBot(mainAdapter)
+//	support := sarah.NewBot(supportAdapter, sarah.BotWithInstanceName("support"))
+//	sarah.RegisterBot(main)
+//	sarah.RegisterBot(support)
+//	sarah.RegisterCommand(slack.SLACK.Instance("support"), command)
+func BotWithInstanceName(name string) DefaultBotOption {
+	return func(bot *defaultBot) {
+		bot.botType = bot.botType.Instance(name)
+	}
+}
+
 func (bot *defaultBot) BotType() BotType {
 	return bot.botType
 }
 
 func (bot *defaultBot) Respond(ctx context.Context, input Input) error {
 	senderKey := input.SenderKey()
+	sensitive := bot.consumeSensitiveInput(senderKey)
 
 	// See if any conversational context is stored.
 	var nextFunc ContextualFunc
@@ -140,6 +211,10 @@ func (bot *defaultBot) Respond(ctx context.Context, input Input) error {
 		// If no conversational context is stored, simply search for corresponding command.
 		switch in := input.(type) {
 		case *HelpInput:
+			if isBotAuthored(in) {
+				// Do not answer a help request from another bot, which may reply again.
+				return nil
+			}
 			res = &CommandResponse{
 				Content:     bot.commands.Helps(in),
 				UserContext: nil,
@@ -148,9 +223,13 @@ func (bot *defaultBot) Respond(ctx context.Context, input Input) error {
 			res, err = bot.commands.ExecuteFirstMatched(ctx, input)
 		}
 	} else {
+		if bot.reminder != nil {
+			bot.reminder.cancel(senderKey)
+		}
+
 		e := bot.userContextStorage.Delete(senderKey)
 		if e != nil {
-			logger.Warnf("Failed to delete UserContext: BotType: %s. SenderKey: %s. Error: %+v", bot.BotType(), senderKey, e)
+			LoggerFromContext(ctx).Warnf("Failed to delete UserContext: SenderKey: %s. Error: %+v", senderKey, e)
 		}
 
 		switch input.(type) {
@@ -158,6 +237,9 @@ func (bot *defaultBot) Respond(ctx context.Context, input Input) error {
 			return nil
 		default:
 			res, err = nextFunc(ctx, input)
+			if sensitive {
+				bot.deleteSensitiveMessage(ctx, input)
+			}
 		}
 	}
 
@@ -174,7 +256,16 @@ func (bot *defaultBot) Respond(ctx context.Context, input Input) error {
 	// This may damage user experience since user is left in conversational context set by CommandResponse without any sort of notification.
 	if res.UserContext != nil && bot.userContextStorage != nil {
 		if err := bot.userContextStorage.Set(senderKey, res.UserContext); err != nil {
-			logger.Errorf("Failed to store UserContext. BotType: %s. SenderKey: %s. UserContext: %#v. Error: %+v", bot.BotType(), senderKey, res.UserContext, err)
+			LoggerFromContext(ctx).Errorf("Failed to store UserContext. SenderKey: %s. UserContext: %#v. Error: %+v", senderKey, res.UserContext, err)
+		} else {
+			if res.UserContext.Sensitive {
+				bot.markSensitiveInput(senderKey)
+			}
+			if bot.reminder != nil {
+				bot.reminder.schedule(ctx, senderKey, res.UserContext.TTL, func() {
+					bot.SendMessage(ctx, NewOutputMessage(input.ReplyTo(), bot.reminder.content(input)))
+				})
+			}
 		}
 	}
 	if res.Content != nil {
@@ -185,8 +276,116 @@ func (bot *defaultBot) Respond(ctx context.Context, input Input) error {
 	return nil
 }
 
+// userContextStorageStats returns the statistics of the UserContextStorage if available.
+func (bot *defaultBot) userContextStorageStats() *UserContextStorageStats {
+	reporter, ok := bot.userContextStorage.(UserContextStorageStatsReporter)
+	if !ok {
+		return nil
+	}
+	return reporter.Stats()
+}
+
+// probeUserContextStorage reads the UserContextStorage to see if it is reachable.
+// The first returned value is false when the Bot has no UserContextStorage.
+func (bot *defaultBot) probeUserContextStorage() (bool, error) {
+	if bot.userContextStorage == nil {
+		return false, nil
+	}
+	_, err := bot.userContextStorage.Get(selfTestKey)
+	return true, err
+}
+
+// lastSendLatency returns how long the latest Adapter.SendMessage call took.
+func (bot *defaultBot) lastSendLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&bot.sendLatency))
+}
+
+// connectionStats returns the statistics of the Adapter's connection if available.
+func (bot *defaultBot) connectionStats() *ConnectionStats {
+	if bot.connStatsReporter == nil {
+		return nil
+	}
+	return bot.connStatsReporter.ConnectionStats()
+}
+
+// DirectMessageDestination returns the destination to send a direct message to the given user.
+// This returns ErrDirectMessageUnsupported when the Adapter does not satisfy DirectMessageResolver.
+func (bot *defaultBot) DirectMessageDestination(ctx context.Context, senderKey string) (OutputDestination, error) {
+	if bot.dmResolver == nil {
+		return nil, ErrDirectMessageUnsupported
+	}
+	return bot.dmResolver.DirectMessageDestination(ctx, senderKey)
+}
+
+// markSensitiveInput remembers that the next Input from the given user is sensitive.
+func (bot *defaultBot) markSensitiveInput(senderKey string) {
+	bot.sensitiveMutex.Lock()
+	defer bot.sensitiveMutex.Unlock()
+
+	if bot.sensitiveSenders == nil {
+		bot.sensitiveSenders = make(map[string]struct{})
+	}
+	bot.sensitiveSenders[senderKey] = struct{}{}
+}
+
+// consumeSensitiveInput tells if the current Input from the given user is sensitive and forgets it.
+// Since the mark is cleared on the user's next Input, one that outlives the expired UserContext affects only that Input.
+func (bot *defaultBot) consumeSensitiveInput(senderKey string) bool {
+	bot.sensitiveMutex.Lock()
+	defer bot.sensitiveMutex.Unlock()
+
+	_, ok := bot.sensitiveSenders[senderKey]
+	delete(bot.sensitiveSenders, senderKey)
+	return ok
+}
+
+func (bot *defaultBot) expectsSensitiveInput(senderKey string) bool {
+	bot.sensitiveMutex.Lock()
+	defer bot.sensitiveMutex.Unlock()
+
+	_, ok := bot.sensitiveSenders[senderKey]
+	return ok
+}
+
+// deleteSensitiveMessage requests the Adapter to delete the user's sensitive message when the Adapter satisfies MessageDeleter.
+func (bot *defaultBot) deleteSensitiveMessage(ctx context.Context, input Input) {
+	if bot.deleter == nil {
+		return
+	}
+
+	err := bot.deleter.DeleteMessage(ctx, input)
+	if err != nil {
+		LoggerFromContext(ctx).Warnf("Failed to delete a sensitive message. SenderKey: %s. Error: %+v", input.SenderKey(), err)
+	}
+}
+
+// SendEditableMessage sends the given Output and returns the identifier of the sent message.
+// This returns ErrMessageEditUnsupported when the Adapter does not satisfy MessageEditor.
+func (bot *defaultBot) SendEditableMessage(ctx context.Context, output Output) (string, error) {
+	if bot.editor == nil {
+		return "", ErrMessageEditUnsupported
+	}
+	return bot.editor.SendEditableMessage(ctx, transformOutput(output, bot.transformers))
+}
+
+// EditMessage replaces the content of the message that is sent via SendEditableMessage.
+// This returns ErrMessageEditUnsupported when the Adapter does not satisfy MessageEditor.
+func (bot *defaultBot) EditMessage(ctx context.Context, destination OutputDestination, messageID string, content interface{}) error {
+	if bot.editor == nil {
+		return ErrMessageEditUnsupported
+	}
+	content, _ = transformContent(content, bot.transformers)
+	return bot.editor.EditMessage(ctx, destination, messageID, content)
+}
+
 func (bot *defaultBot) SendMessage(ctx context.Context, output Output) {
+	output = transformOutput(output, bot.transformers)
+	if bot.duplicates != nil && bot.duplicates.suppress(output) {
+		return
+	}
+	started := time.Now()
 	bot.sendMessageFunc(ctx, output)
+	atomic.StoreInt64(&bot.sendLatency, int64(time.Since(started)))
 }
 
 func (bot *defaultBot) AppendCommand(command Command) {
@@ -205,3 +404,62 @@ func NewSuppressedResponseWithNext(next ContextualFunc) *CommandResponse {
 		UserContext: NewUserContext(next),
 	}
 }
+
+// expirationReminder schedules reminders that are sent before the users' contexts expire.
+type expirationReminder struct {
+	threshold  time.Duration
+	defaultTTL time.Duration
+	content    func(Input) interface{}
+	timers     map[string]Timer
+	mutex      sync.Mutex
+}
+
+// schedule sets a timer to call the given function threshold before the expiration.
+// A previously scheduled reminder for the same key is canceled.
+func (r *expirationReminder) schedule(ctx context.Context, key string, ttl time.Duration, remind func()) {
+	if ttl <= 0 {
+		ttl = r.defaultTTL
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if timer, ok := r.timers[key]; ok {
+		timer.Stop()
+		delete(r.timers, key)
+	}
+
+	wait := ttl - r.threshold
+	if wait <= 0 {
+		// Too short to remind.
+		return
+	}
+
+	var timer Timer
+	timer = currentClock().AfterFunc(wait, func() {
+		r.mutex.Lock()
+		current := r.timers[key]
+		if current == timer {
+			delete(r.timers, key)
+		}
+		r.mutex.Unlock()
+
+		if current != timer || ctx.Err() != nil {
+			// Already replaced by a newer reminder, or the Bot is no longer running.
+			return
+		}
+		remind()
+	})
+	r.timers[key] = timer
+}
+
+// cancel stops the scheduled reminder for the given key if any.
+func (r *expirationReminder) cancel(key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if timer, ok := r.timers[key]; ok {
+		timer.Stop()
+		delete(r.timers, key)
+	}
+}
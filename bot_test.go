@@ -487,6 +487,164 @@ func TestDefaultBot_Run(t *testing.T) {
 	}
 }
 
+func TestBotWithInstanceName(t *testing.T) {
+	bot := NewBot(&DummyAdapter{BotTypeValue: "slack"}, BotWithInstanceName("support"))
+
+	if bot.BotType() != "slack:support" {
+		t.Errorf("Unexpected BotType is returned: %s.", bot.BotType())
+	}
+}
+
+func TestBotWithExpirationReminder(t *testing.T) {
+	content := func(_ Input) interface{} {
+		return "reminder"
+	}
+	bot := &defaultBot{}
+	BotWithExpirationReminder(10*time.Second, 3*time.Minute, content)(bot)
+
+	if bot.reminder == nil {
+		t.Fatal("Reminder is not set.")
+	}
+
+	if bot.reminder.threshold != 10*time.Second || bot.reminder.defaultTTL != 3*time.Minute {
+		t.Errorf("Unexpected setting is applied: %#v.", bot.reminder)
+	}
+}
+
+func TestDefaultBot_Respond_WithExpirationReminder(t *testing.T) {
+	sent := make(chan Output, 1)
+	myBot := &defaultBot{
+		sendMessageFunc: func(_ context.Context, output Output) {
+			sent <- output
+		},
+		userContextStorage: &DummyUserContextStorage{
+			GetFunc: func(_ string) (ContextualFunc, error) {
+				return nil, nil
+			},
+			SetFunc: func(_ string, _ *UserContext) error {
+				return nil
+			},
+		},
+		commands: &Commands{
+			collection: []Command{
+				&DummyCommand{
+					MatchFunc: func(_ Input) bool {
+						return true
+					},
+					ExecuteFunc: func(_ context.Context, _ Input) (*CommandResponse, error) {
+						userContext := NewUserContext(func(_ context.Context, _ Input) (*CommandResponse, error) {
+							return nil, nil
+						})
+						userContext.TTL = 20 * time.Millisecond
+						return &CommandResponse{UserContext: userContext}, nil
+					},
+				},
+			},
+		},
+	}
+	BotWithExpirationReminder(10*time.Millisecond, 3*time.Minute, func(_ Input) interface{} {
+		return "reminder"
+	})(myBot)
+
+	err := myBot.Respond(context.TODO(), &DummyInput{SenderKeyValue: "senderKey", ReplyToValue: "replyTo"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %#v.", err)
+	}
+
+	select {
+	case output := <-sent:
+		if output.Content() != "reminder" {
+			t.Errorf("Unexpected content is sent: %#v.", output.Content())
+		}
+		if output.Destination() != "replyTo" {
+			t.Errorf("Unexpected destination is set: %#v.", output.Destination())
+		}
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Error("Reminder is not sent.")
+
+	}
+}
+
+func Test_expirationReminder(t *testing.T) {
+	reminder := &expirationReminder{
+		threshold:  10 * time.Millisecond,
+		defaultTTL: 20 * time.Millisecond,
+		timers:     map[string]Timer{},
+	}
+
+	// Canceled reminder is not sent.
+	reminder.schedule(context.TODO(), "canceled", 0, func() {
+		t.Error("Canceled reminder must not be sent.")
+	})
+	reminder.cancel("canceled")
+
+	// Replaced reminder is not sent.
+	reminder.schedule(context.TODO(), "replaced", 0, func() {
+		t.Error("Replaced reminder must not be sent.")
+	})
+	called := make(chan struct{}, 1)
+	reminder.schedule(context.TODO(), "replaced", 0, func() {
+		called <- struct{}{}
+	})
+
+	// TTL shorter than the threshold is not scheduled.
+	reminder.schedule(context.TODO(), "short", 5*time.Millisecond, func() {
+		t.Error("Reminder with too short TTL must not be sent.")
+	})
+
+	select {
+	case <-called:
+		// O.K.
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Error("Reminder is not sent.")
+
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	reminder.mutex.Lock()
+	defer reminder.mutex.Unlock()
+	if len(reminder.timers) != 0 {
+		t.Errorf("Timers must be cleaned up: %#v.", reminder.timers)
+	}
+}
+
+func TestDefaultBot_userContextStorageStats(t *testing.T) {
+	bot := &defaultBot{userContextStorage: nil}
+	if bot.userContextStorageStats() != nil {
+		t.Error("Nil should be returned when storage is not set.")
+	}
+
+	bot = &defaultBot{userContextStorage: &DummyUserContextStorage{}}
+	if bot.userContextStorageStats() != nil {
+		t.Error("Nil should be returned when storage does not report stats.")
+	}
+
+	bot = &defaultBot{userContextStorage: NewUserContextStorage(NewCacheConfig())}
+	if bot.userContextStorageStats() == nil {
+		t.Error("Stats should be returned.")
+	}
+}
+
+func TestDefaultBot_connectionStats(t *testing.T) {
+	bot := NewBot(&DummyAdapter{}).(*defaultBot)
+	if bot.connectionStats() != nil {
+		t.Error("Nil should be returned when adapter does not report stats.")
+	}
+
+	stats := &ConnectionStats{Connected: true, Reconnects: 2}
+	adapter := &DummyConnectionStatsAdapter{
+		ConnectionStatsFunc: func() *ConnectionStats {
+			return stats
+		},
+	}
+	bot = NewBot(adapter).(*defaultBot)
+	if bot.connectionStats() != stats {
+		t.Errorf("Unexpected stats are returned: %#v.", bot.connectionStats())
+	}
+}
+
 func TestDefaultBot_SendMessage(t *testing.T) {
 	adapterProcessed := false
 	bot := &defaultBot{
@@ -521,3 +679,30 @@ func TestNewSuppressedResponseWithNext(t *testing.T) {
 		t.Errorf("Unexpected ContextualFunc is set %T.", res.UserContext.Next)
 	}
 }
+
+func BenchmarkDefaultBot_Respond(b *testing.B) {
+	myBot := &defaultBot{
+		botType: "dummy",
+		userContextStorage: &DummyUserContextStorage{
+			GetFunc: func(_ string) (ContextualFunc, error) {
+				return nil, nil
+			},
+		},
+		commands: benchmarkCommands(b, 50),
+		sendMessageFunc: func(_ context.Context, _ Output) {
+		},
+	}
+	input := &DummyInput{
+		SenderKeyValue: "senderKey",
+		MessageValue:   ".command25 foo",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := myBot.Respond(context.TODO(), input)
+		if err != nil {
+			b.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoAlertRoute is returned by BotAlerter.Alert when none of the AlertRoutes is available to deliver the alert.
+var ErrNoAlertRoute = errors.New("no alert route is available")
+
+// AlertRoute declares a chat channel that BotAlerter posts alerts to via the Bot with BotType.
+// Create one with AlertToDestination or AlertToTaskDestination.
+type AlertRoute struct {
+	botType     BotType
+	destination OutputDestination
+	taskID      string
+}
+
+// AlertToDestination creates and returns an AlertRoute that posts alerts to the given destination via the Bot with the given BotType.
+func AlertToDestination(botType BotType, destination OutputDestination) *AlertRoute {
+	return &AlertRoute{
+		botType:     botType,
+		destination: destination,
+	}
+}
+
+// AlertToTaskDestination creates and returns an AlertRoute that posts alerts to the default destination of the ScheduledTask with the given identifier.
+// This is handy when a ScheduledTask already reports to the administrators' channel, so the channel does not have to be declared twice.
+// The route is unavailable while the ScheduledTask is not registered to the running Bot or has no default destination.
+func AlertToTaskDestination(botType BotType, taskID string) *AlertRoute {
+	return &AlertRoute{
+		botType: botType,
+		taskID:  taskID,
+	}
+}
+
+// resolveDestination returns the destination to post the alert to.
+// The ScheduledTask is looked up from the Runner that the given Bot's context derives from.
+func (route *AlertRoute) resolveDestination(botCtx context.Context) (OutputDestination, error) {
+	if route.taskID == "" {
+		return route.destination, nil
+	}
+
+	task, ok := registriesFromContext(botCtx).taskTriggers.get(route.botType, route.taskID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s:%s", ErrTaskNotFound, route.botType, route.taskID)
+	}
+
+	destination := task.task.DefaultDestination()
+	if destination == nil {
+		return nil, fmt.Errorf("scheduled task %s:%s has no default destination", route.botType, route.taskID)
+	}
+	return destination, nil
+}
+
+// runnerBoundAlerter is satisfied by an Alerter that works with the Bots of the runner it is registered to.
+type runnerBoundAlerter interface {
+	bind(*runner)
+}
+
+// BotAlerterOption defines a function's signature that NewBotAlerter's functional options must satisfy.
+type BotAlerterOption func(*BotAlerter)
+
+// BotAlerterWithFormatter creates and returns a BotAlerterOption that customizes the message to post.
+// The given function receives the BotType of the failing Bot and the error, and returns the content to pass to Bot.SendMessage.
+func BotAlerterWithFormatter(fnc func(BotType, error) interface{}) BotAlerterOption {
+	return func(alerter *BotAlerter) {
+		alerter.format = fnc
+	}
+}
+
+// BotAlerter is an Alerter that posts the alert into a chat channel via another healthy Bot registered to the same Runner.
+// e.g. When the Gitter Bot dies, administrators are notified in a Slack channel via the Slack Bot.
+//
+// The AlertRoutes are tried in the given order, and the alert is posted via the first available one.
+// To avoid a loop, a route is skipped when its Bot is the failing Bot itself, so a failing Bot never alerts through itself.
+// A route is also skipped when its Bot is not running at the moment such as while the replica is on hot standby.
+// Register a BotAlerter to one Runner; The routes are resolved with the Bots of the Runner it is registered to.
+//
+//	alerter := sarah.NewBotAlerter([]*sarah.AlertRoute{
+//		sarah.AlertToDestination(slack.SLACK, event.ChannelID("C12345")),
+//		sarah.AlertToTaskDestination(gitter.GITTER, "daily_report"),
+//	})
+//	sarah.RegisterAlerter(alerter)
+type BotAlerter struct {
+	routes []*AlertRoute
+	format func(BotType, error) interface{}
+	runner *runner
+	mutex  sync.RWMutex
+}
+
+var _ Alerter = (*BotAlerter)(nil)
+var _ runnerBoundAlerter = (*BotAlerter)(nil)
+
+// NewBotAlerter creates and returns a new BotAlerter that posts alerts via the given AlertRoutes.
+func NewBotAlerter(routes []*AlertRoute, options ...BotAlerterOption) *BotAlerter {
+	alerter := &BotAlerter{
+		routes: routes,
+		format: func(botType BotType, err error) interface{} {
+			return fmt.Sprintf("%s is in a critical state: %s", botType, err.Error())
+		},
+	}
+
+	for _, opt := range options {
+		opt(alerter)
+	}
+
+	return alerter
+}
+
+// bind lets the BotAlerter look up the running Bots of the given runner. This is called on RegisterAlerter.
+func (alerter *BotAlerter) bind(r *runner) {
+	alerter.mutex.Lock()
+	defer alerter.mutex.Unlock()
+
+	alerter.runner = r
+}
+
+// Alert posts the given error via the first available AlertRoute.
+// ErrNoAlertRoute is returned when no route is available, so other Alerters should be registered as a fallback.
+func (alerter *BotAlerter) Alert(ctx context.Context, botType BotType, err error) error {
+	alerter.mutex.RLock()
+	r := alerter.runner
+	alerter.mutex.RUnlock()
+	if r == nil {
+		return fmt.Errorf("%w: BotAlerter is not registered via RegisterAlerter", ErrNoAlertRoute)
+	}
+
+	var errs []error
+	for _, route := range alerter.routes {
+		if route.botType == botType {
+			// Never alert through the failing Bot itself.
+			continue
+		}
+
+		running, ok := r.runningBot(route.botType)
+		if !ok || running.ctx.Err() != nil {
+			errs = append(errs, fmt.Errorf("%s is not running", route.botType))
+			continue
+		}
+
+		destination, e := route.resolveDestination(running.ctx)
+		if e != nil {
+			errs = append(errs, e)
+			continue
+		}
+
+		running.bot.SendMessage(ctx, NewOutputMessage(destination, alerter.format(botType, err)))
+		return nil
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("%w for %s", ErrNoAlertRoute, botType)
+	}
+	return fmt.Errorf("%w for %s: %w", ErrNoAlertRoute, botType, errors.Join(errs...))
+}
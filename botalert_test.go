@@ -0,0 +1,191 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertToDestination(t *testing.T) {
+	route := AlertToDestination("slack", "#admin")
+
+	if route.botType != "slack" {
+		t.Errorf("Unexpected BotType is set: %s.", route.botType)
+	}
+
+	destination, err := route.resolveDestination(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if destination != "#admin" {
+		t.Errorf("Unexpected destination is returned: %#v.", destination)
+	}
+}
+
+func TestAlertToTaskDestination(t *testing.T) {
+	var botType BotType = "slack"
+	bot := &DummyBot{BotTypeValue: botType}
+	reg := newRegistries()
+	botCtx := withRegistries(context.Background(), reg)
+	reg.taskTriggers.set(botCtx, bot, &DummyScheduledTask{IdentifierValue: "report", DefaultDestinationValue: "#report"})
+	reg.taskTriggers.set(botCtx, bot, &DummyScheduledTask{IdentifierValue: "nodest"})
+
+	destination, err := AlertToTaskDestination(botType, "report").resolveDestination(botCtx)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if destination != "#report" {
+		t.Errorf("Unexpected destination is returned: %#v.", destination)
+	}
+
+	_, err = AlertToTaskDestination(botType, "nodest").resolveDestination(botCtx)
+	if err == nil {
+		t.Error("Expected error is not returned for a task without default destination.")
+	}
+
+	_, err = AlertToTaskDestination(botType, "unknown").resolveDestination(botCtx)
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	_, err = AlertToTaskDestination(botType, "report").resolveDestination(context.Background())
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Task of another Runner should not be found: %#v.", err)
+	}
+}
+
+func TestNewBotAlerter(t *testing.T) {
+	routes := []*AlertRoute{AlertToDestination("slack", "#admin")}
+	alerter := NewBotAlerter(routes, BotAlerterWithFormatter(func(_ BotType, _ error) interface{} {
+		return "formatted"
+	}))
+
+	if len(alerter.routes) != 1 {
+		t.Errorf("Unexpected routes are set: %#v.", alerter.routes)
+	}
+
+	if alerter.format("gitter", errors.New("error")) != "formatted" {
+		t.Error("Given formatter is not set.")
+	}
+
+	content := NewBotAlerter(routes).format("gitter", errors.New("connection lost"))
+	if content != "gitter is in a critical state: connection lost" {
+		t.Errorf("Unexpected default content is returned: %#v.", content)
+	}
+}
+
+func TestRunner_RegisterAlerter_BotAlerter(t *testing.T) {
+	rn := New()
+	alerter := NewBotAlerter(nil)
+	rn.RegisterAlerter(alerter)
+
+	config := NewConfig()
+	config.TimeZone = time.UTC.String()
+	_, errs := prepareRunner(config, rn.options)
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors are returned: %#v.", errs)
+	}
+	if alerter.runner != nil {
+		t.Error("BotAlerter should not be bound on validation.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := newRunner(ctx, config, rn.options)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if alerter.runner != r {
+		t.Error("BotAlerter is not bound to the runner.")
+	}
+}
+
+func TestBotAlerter_Alert(t *testing.T) {
+	var sent []Output
+	newBot := func(botType BotType) Bot {
+		return &DummyBot{
+			BotTypeValue: botType,
+			SendMessageFunc: func(_ context.Context, output Output) {
+				sent = append(sent, output)
+			},
+		}
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &runner{}
+	r.setRunningBot(context.Background(), newBot("gitter"))
+	r.setRunningBot(canceled, newBot("stopped"))
+	r.setRunningBot(context.Background(), newBot("slack"))
+
+	alerter := NewBotAlerter([]*AlertRoute{
+		AlertToDestination("gitter", "gitter-room"),
+		AlertToDestination("stopped", "stopped-room"),
+		AlertToDestination("standby", "standby-room"),
+		AlertToDestination("slack", "#admin"),
+	})
+	alerter.bind(r)
+
+	// The failing Bot itself, the Bot with the canceled context, and the Bot that is not running are skipped.
+	err := alerter.Alert(context.Background(), "gitter", errors.New("connection lost"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Unexpected number of messages are sent: %d.", len(sent))
+	}
+	if sent[0].Destination() != "#admin" {
+		t.Errorf("Unexpected destination is set: %#v.", sent[0].Destination())
+	}
+	if sent[0].Content() != "gitter is in a critical state: connection lost" {
+		t.Errorf("Unexpected content is set: %#v.", sent[0].Content())
+	}
+
+	// The first available route wins.
+	sent = nil
+	err = alerter.Alert(context.Background(), "slack", errors.New("connection lost"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(sent) != 1 || sent[0].Destination() != "gitter-room" {
+		t.Errorf("Unexpected messages are sent: %#v.", sent)
+	}
+}
+
+func TestBotAlerter_Alert_NoRoute(t *testing.T) {
+	t.Run("Not registered", func(t *testing.T) {
+		alerter := NewBotAlerter([]*AlertRoute{AlertToDestination("slack", "#admin")})
+
+		err := alerter.Alert(context.Background(), "gitter", errors.New("connection lost"))
+		if !errors.Is(err, ErrNoAlertRoute) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Only itself", func(t *testing.T) {
+		r := &runner{}
+		r.setRunningBot(context.Background(), &DummyBot{BotTypeValue: "slack"})
+		alerter := NewBotAlerter([]*AlertRoute{AlertToDestination("slack", "#admin")})
+		alerter.bind(r)
+
+		err := alerter.Alert(context.Background(), "slack", errors.New("connection lost"))
+		if !errors.Is(err, ErrNoAlertRoute) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Not running", func(t *testing.T) {
+		alerter := NewBotAlerter([]*AlertRoute{AlertToDestination("slack", "#admin")})
+		alerter.bind(&runner{})
+
+		err := alerter.Alert(context.Background(), "gitter", errors.New("connection lost"))
+		if !errors.Is(err, ErrNoAlertRoute) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+		if !strings.Contains(err.Error(), "slack is not running") {
+			t.Errorf("Unexpected error message is returned: %s.", err.Error())
+		}
+	})
+}
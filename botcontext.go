@@ -0,0 +1,100 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// maxBotContexts is the number of the latest BotContextStatus values that each BotStatus retains.
+// The older ones are discarded so a Bot that keeps restarting does not grow the history infinitely.
+const maxBotContexts = 100
+
+// CancellationReason tells why the context.Context of a Bot is canceled.
+type CancellationReason string
+
+const (
+	// CancellationRunnerStop indicates that the context given to Run is canceled.
+	CancellationRunnerStop CancellationReason = "runner stop"
+
+	// CancellationSupervisorStop indicates that the Bot is stopped by a SupervisionDirective with StopBot.
+	CancellationSupervisorStop CancellationReason = "supervisor stop"
+
+	// CancellationSupervisorRestart indicates that the Bot is restarted by a SupervisionDirective with RestartBot.
+	CancellationSupervisorRestart CancellationReason = "supervisor restart"
+
+	// CancellationNonContinuableError indicates that the Bot escalated a BotNonContinuableError.
+	CancellationNonContinuableError CancellationReason = "non-continuable error"
+
+	// CancellationPanic indicates that Bot.Run panicked.
+	CancellationPanic CancellationReason = "panic"
+
+	// CancellationBotReturned indicates that Bot.Run returned while its context was still active.
+	CancellationBotReturned CancellationReason = "bot returned"
+
+	// CancellationLeadershipLost indicates that the replica lost the leadership of the Bot. See RegisterLeaderElector.
+	CancellationLeadershipLost CancellationReason = "leadership lost"
+)
+
+// BotContextStatus represents a context.Context that Sarah derived to run a Bot.
+// A new context is derived every time the Bot starts, including a restart by a SupervisionDirective and a takeover of the leadership.
+type BotContextStatus struct {
+	// StartedAt is the time when the context was derived and the Bot started with it.
+	StartedAt time.Time
+
+	// Canceled indicates if the context is already canceled.
+	Canceled bool
+
+	// CanceledAt is the time when the Bot stopped with the canceled context. This is zero when Canceled is false.
+	CanceledAt time.Time
+
+	// Reason tells why the context is canceled. This is empty when Canceled is false.
+	Reason CancellationReason
+
+	// Cause is the description of the error that canceled the context such as the error that the Bot escalated.
+	Cause string
+}
+
+// startBotContext records the context that is derived to run the Bot with the given BotType.
+func (r *runner) startBotContext(botType BotType) *BotContextStatus {
+	if r.status == nil {
+		return nil
+	}
+	return r.status.startBotContext(botType)
+}
+
+// cancelBotContext records the cancellation of the given context.
+func (r *runner) cancelBotContext(record *BotContextStatus, reason CancellationReason, cause error) {
+	if r.status == nil || record == nil {
+		return
+	}
+	r.status.cancelBotContext(record, reason, cause)
+}
+
+// cancellationReason judges why the Bot's context is canceled with the cause of the cancellation.
+// The given runnerCtx is the parent of the Bot's context, and the reason is already judged when Bot.Run panics or returns before the cancellation.
+func cancellationReason(runnerCtx context.Context, cause error, judged CancellationReason) CancellationReason {
+	if judged != "" {
+		return judged
+	}
+
+	var restart *botRestart
+	var nonContinuable *BotNonContinuableError
+	switch {
+	case errors.Is(cause, ErrLeadershipLost):
+		return CancellationLeadershipLost
+
+	case errors.As(cause, &restart):
+		return CancellationSupervisorRestart
+
+	case errors.As(cause, &nonContinuable):
+		return CancellationNonContinuableError
+
+	case runnerCtx.Err() != nil && errors.Is(cause, context.Cause(runnerCtx)):
+		return CancellationRunnerStop
+
+	default:
+		return CancellationSupervisorStop
+
+	}
+}
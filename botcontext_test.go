@@ -0,0 +1,125 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_cancellationReason(t *testing.T) {
+	activeCtx := context.Background()
+	stoppedCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	lostCtx, cancelLeadership := context.WithCancelCause(context.Background())
+	cancelLeadership(ErrLeadershipLost)
+
+	testSets := []struct {
+		runnerCtx context.Context
+		cause     error
+		judged    CancellationReason
+		expected  CancellationReason
+	}{
+		{runnerCtx: activeCtx, cause: NewBotNonContinuableError("shutdown"), judged: CancellationPanic, expected: CancellationPanic},
+		{runnerCtx: stoppedCtx, cause: context.Canceled, expected: CancellationRunnerStop},
+		{runnerCtx: lostCtx, cause: ErrLeadershipLost, expected: CancellationLeadershipLost},
+		{runnerCtx: activeCtx, cause: &botRestart{cooldown: time.Second}, expected: CancellationSupervisorRestart},
+		{runnerCtx: activeCtx, cause: NewBotNonContinuableError("unrecoverable"), expected: CancellationNonContinuableError},
+		{runnerCtx: activeCtx, cause: errors.New("supervised"), expected: CancellationSupervisorStop},
+		{runnerCtx: stoppedCtx, cause: errors.New("supervised"), expected: CancellationSupervisorStop},
+	}
+
+	for i, tt := range testSets {
+		reason := cancellationReason(tt.runnerCtx, tt.cause, tt.judged)
+		if reason != tt.expected {
+			t.Errorf("Unexpected reason is returned on test #%d: %s.", i+1, reason)
+		}
+	}
+}
+
+func Test_status_botContext(t *testing.T) {
+	now := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		var botType BotType = "dummy"
+		s := &status{}
+		s.addBot(&DummyBot{BotTypeValue: botType})
+
+		first := s.startBotContext(botType)
+		now = now.Add(12 * time.Minute)
+		s.cancelBotContext(first, CancellationSupervisorRestart, errors.New("connection lost"))
+
+		// The first cancellation wins.
+		s.cancelBotContext(first, CancellationNonContinuableError, errors.New("shutdown"))
+
+		s.startBotContext(botType)
+
+		contexts := s.snapshot().Bots[0].Contexts
+		if len(contexts) != 2 {
+			t.Fatalf("Unexpected number of contexts are returned: %d.", len(contexts))
+		}
+		expected := BotContextStatus{
+			StartedAt:  time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC),
+			Canceled:   true,
+			CanceledAt: time.Date(2026, 1, 1, 3, 12, 0, 0, time.UTC),
+			Reason:     CancellationSupervisorRestart,
+			Cause:      "connection lost",
+		}
+		if contexts[0] != expected {
+			t.Errorf("Unexpected context is returned: %#v.", contexts[0])
+		}
+		if contexts[1].Canceled || !contexts[1].StartedAt.Equal(now) {
+			t.Errorf("Unexpected context is returned: %#v.", contexts[1])
+		}
+	})
+}
+
+func Test_botStatus_addContext(t *testing.T) {
+	bs := &botStatus{}
+	for i := 0; i < maxBotContexts+10; i++ {
+		bs.addContext(&BotContextStatus{Cause: fmt.Sprint(i)})
+	}
+
+	contexts := bs.contextStates()
+	if len(contexts) != maxBotContexts {
+		t.Fatalf("Unexpected number of contexts are retained: %d.", len(contexts))
+	}
+	if contexts[0].Cause != "10" {
+		t.Errorf("Older contexts are not discarded: %#v.", contexts[0])
+	}
+}
+
+func Test_runner_runBot_BotContext(t *testing.T) {
+	var botType BotType = "dummy"
+	bot := &DummyBot{
+		BotTypeValue:      botType,
+		AppendCommandFunc: func(_ Command) {},
+		RunFunc: func(_ context.Context, _ func(Input) error, _ func(error)) {
+			panic("unexpected")
+		},
+	}
+	s := &status{}
+	s.addBot(bot)
+	r := &runner{
+		configWatcher: &nullConfigWatcher{},
+		alerters:      &alerters{},
+		worker:        &DummyWorker{},
+		status:        s,
+	}
+
+	r.runBot(context.Background(), bot)
+
+	contexts := s.snapshot().Bots[0].Contexts
+	if len(contexts) != 1 {
+		t.Fatalf("Unexpected number of contexts are returned: %d.", len(contexts))
+	}
+	if !contexts[0].Canceled || contexts[0].Reason != CancellationPanic {
+		t.Errorf("Unexpected context is returned: %#v.", contexts[0])
+	}
+}
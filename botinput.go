@@ -0,0 +1,44 @@
+package sarah
+
+// BotAuthoredInput is an optional interface that an Input implementation can satisfy to tell if the Input is sent by another bot.
+// Bots replying to bots can end up in an infinite loop, so Sarah ignores bot-authored Inputs by default:
+// no Command is executed unless the Command explicitly accepts them via CommandPropsBuilder.AcceptBotInput or BotInputAcceptor,
+// and a help request from a bot is not answered.
+type BotAuthoredInput interface {
+	Input
+
+	// IsBot returns true when the Input is sent by a bot.
+	IsBot() bool
+}
+
+// BotInputAcceptor is an optional interface that a Command can implement to handle the Inputs sent by other bots.
+// This is meant for intentional bot-to-bot workflows such as a Command that receives a notification from a CI bot.
+// Be careful not to reply to the sending bot in a way that triggers the sending bot again.
+type BotInputAcceptor interface {
+	Command
+
+	// AcceptsBotInput returns true when the Command handles the Inputs that satisfy BotAuthoredInput and report IsBot as true.
+	AcceptsBotInput() bool
+}
+
+// isBotAuthored tells if the given Input is sent by a bot.
+func isBotAuthored(input Input) bool {
+	// HelpInput and AbortInput are created by the Adapter from the original Input, which tells the sender.
+	switch typed := input.(type) {
+	case *HelpInput:
+		input = typed.OriginalInput
+
+	case *AbortInput:
+		input = typed.OriginalInput
+
+	}
+
+	authored, ok := input.(BotAuthoredInput)
+	return ok && authored.IsBot()
+}
+
+// acceptsBotInput tells if the given Command, or the Command wrapped by it, handles the Inputs sent by bots.
+func acceptsBotInput(command Command) bool {
+	acceptor, ok := resolveCommand[BotInputAcceptor](command)
+	return ok && acceptor.AcceptsBotInput()
+}
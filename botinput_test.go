@@ -0,0 +1,104 @@
+package sarah
+
+import (
+	"context"
+	"testing"
+)
+
+type DummyBotAuthoredInput struct {
+	DummyInput
+	IsBotValue bool
+}
+
+func (i *DummyBotAuthoredInput) IsBot() bool {
+	return i.IsBotValue
+}
+
+func Test_isBotAuthored(t *testing.T) {
+	testSets := []struct {
+		input    Input
+		expected bool
+	}{
+		{input: &DummyInput{}, expected: false},
+		{input: &DummyBotAuthoredInput{IsBotValue: false}, expected: false},
+		{input: &DummyBotAuthoredInput{IsBotValue: true}, expected: true},
+		{input: NewHelpInput(&DummyBotAuthoredInput{IsBotValue: true}), expected: true},
+		{input: NewAbortInput(&DummyBotAuthoredInput{IsBotValue: true}), expected: true},
+	}
+
+	for i, tt := range testSets {
+		if isBotAuthored(tt.input) != tt.expected {
+			t.Errorf("Unexpected result on test #%d.", i+1)
+		}
+	}
+}
+
+func TestCommandPropsBuilder_AcceptBotInput(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+	builder.AcceptBotInput()
+
+	if !builder.props.acceptBotInput {
+		t.Error("Flag is not set.")
+	}
+}
+
+func TestCommands_FindFirstMatched_BotInput(t *testing.T) {
+	matchAll := func(_ Input) bool {
+		return true
+	}
+	props, err := NewCommandPropsBuilder().
+		BotType("dummy").
+		Identifier("ci").
+		Instruction("dummy").
+		MatchFunc(matchAll).
+		AcceptBotInput().
+		Func(func(_ context.Context, _ Input) (*CommandResponse, error) {
+			return nil, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	accepting, err := buildCommand(context.TODO(), props, &DummyConfigWatcher{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	regular := &DummyCommand{
+		IdentifierValue: "regular",
+		MatchFunc:       matchAll,
+	}
+
+	commands := NewCommands()
+	commands.Append(regular)
+	commands.Append(&kvCommand{Command: accepting})
+
+	if found := commands.FindFirstMatched(&DummyBotAuthoredInput{IsBotValue: false}); found != regular {
+		t.Errorf("Unexpected command is returned: %#v.", found)
+	}
+
+	found := commands.FindFirstMatched(&DummyBotAuthoredInput{IsBotValue: true})
+	if found == nil || found.Identifier() != "ci" {
+		t.Errorf("Unexpected command is returned: %#v.", found)
+	}
+
+	commands = NewCommands()
+	commands.Append(regular)
+	if found := commands.FindFirstMatched(&DummyBotAuthoredInput{IsBotValue: true}); found != nil {
+		t.Errorf("Input from a bot should be ignored: %#v.", found)
+	}
+}
+
+func TestDefaultBot_Respond_BotHelpInput(t *testing.T) {
+	bot := &defaultBot{
+		commands: NewCommands(),
+		sendMessageFunc: func(_ context.Context, _ Output) {
+			t.Error("Help must not be sent to a bot.")
+		},
+	}
+
+	err := bot.Respond(context.TODO(), NewHelpInput(&DummyBotAuthoredInput{IsBotValue: true}))
+	if err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+}
@@ -1,5 +1,10 @@
 package sarah
 
+import (
+	"fmt"
+	"strings"
+)
+
 // BotType tells what type of chat service a Bot or a plugin integrates with. e.g. slack, gitter, cli, etc...
 // This can be used as a unique ID to distinguish one Bot implementation from another.
 type BotType string
@@ -8,3 +13,53 @@ type BotType string
 func (botType BotType) String() string {
 	return string(botType)
 }
+
+// Instance returns a BotType that distinguishes a named instance of the Bot with this BotType.
+// e.g. slack.SLACK.Instance("support") returns "slack:support".
+// Use this to refer to a Bot that is created with BotWithInstanceName when registering Commands and ScheduledTasks.
+func (botType BotType) Instance(name string) BotType {
+	return BotType(string(botType) + ":" + name)
+}
+
+// AnyBotType is a wildcard BotType that shares a CommandProps with all registered Bots.
+// Pass this to CommandPropsBuilder.BotType or CommandPropsBuilder.BotTypes instead of listing every BotType.
+const AnyBotType BotType = "*"
+
+// validateBots checks that every registered Bot has a non-empty and unique BotType.
+// Each error lists all the conflicting registrations so the developer can tell which Bots to name.
+func validateBots(bots []Bot) []error {
+	var errs []error
+	registrations := map[BotType][]string{}
+	var botTypes []BotType
+	for i, bot := range bots {
+		botType := bot.BotType()
+		if botType == "" {
+			errs = append(errs, fmt.Errorf("bot #%d (%s) has an empty BotType", i+1, describeBot(bot)))
+			continue
+		}
+
+		if _, ok := registrations[botType]; !ok {
+			botTypes = append(botTypes, botType)
+		}
+		registrations[botType] = append(registrations[botType], fmt.Sprintf("bot #%d (%s)", i+1, describeBot(bot)))
+	}
+
+	for _, botType := range botTypes {
+		registered := registrations[botType]
+		if len(registered) == 1 {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("BotType %s is registered by %d bots: %s. Give each bot a distinct name with BotWithInstanceName to run them together",
+			botType, len(registered), strings.Join(registered, ", ")))
+	}
+
+	return errs
+}
+
+// describeBot returns the type of the given Bot, or the type of its Adapter when the Bot is created by NewBot.
+func describeBot(bot Bot) string {
+	if d, ok := bot.(*defaultBot); ok && d.adapterType != "" {
+		return d.adapterType
+	}
+	return fmt.Sprintf("%T", bot)
+}
@@ -8,3 +8,35 @@ func TestBotType_String(t *testing.T) {
 		t.Errorf("Expected BotType was 'myNewBotType,' but was %s", BAR.String())
 	}
 }
+
+func TestBotType_Instance(t *testing.T) {
+	var botType BotType = "slack"
+	if botType.Instance("support") != "slack:support" {
+		t.Errorf("Unexpected BotType is returned: %s.", botType.Instance("support"))
+	}
+}
+
+func Test_validateBots(t *testing.T) {
+	bots := []Bot{
+		NewBot(&DummyAdapter{BotTypeValue: "slack"}),
+		&DummyBot{BotTypeValue: "gitter"},
+		NewBot(&DummyAdapter{BotTypeValue: "slack"}),
+		NewBot(&DummyAdapter{BotTypeValue: "slack"}, BotWithInstanceName("support")),
+		&DummyBot{},
+	}
+
+	errs := validateBots(bots)
+	if len(errs) != 2 {
+		t.Fatalf("Unexpected number of errors are returned: %#v.", errs)
+	}
+
+	if errs[0].Error() != "bot #5 (*sarah.DummyBot) has an empty BotType" {
+		t.Errorf("Unexpected error is returned: %s.", errs[0].Error())
+	}
+
+	expected := "BotType slack is registered by 2 bots: bot #1 (*sarah.DummyAdapter), bot #3 (*sarah.DummyAdapter). " +
+		"Give each bot a distinct name with BotWithInstanceName to run them together"
+	if errs[1].Error() != expected {
+		t.Errorf("Unexpected error is returned: %s.", errs[1].Error())
+	}
+}
@@ -0,0 +1,389 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker.Allow and CircuitBreaker.Execute while the circuit is open.
+var ErrCircuitOpen = errors.New("circuit is open")
+
+// errExecutionPanicked is recorded as the result of an execution that panicked.
+var errExecutionPanicked = errors.New("execution panicked")
+
+// CircuitState represents the state of a CircuitBreaker.
+type CircuitState string
+
+const (
+	// CircuitClosed indicates that the executions are allowed and their results are monitored.
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen indicates that the executions are rejected until the cooldown passes.
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen indicates that a limited number of probe executions are allowed to see if the upstream is recovered.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerConfig contains some configuration variables for CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureRate declares the ratio of failed executions in Window that opens the circuit. e.g. 0.5 for 50%.
+	FailureRate float64 `json:"failure_rate" yaml:"failure_rate"`
+
+	// MinRequests declares the minimum number of executions in Window before FailureRate is evaluated,
+	// so a few failures right after a quiet period do not open the circuit.
+	MinRequests uint `json:"min_requests" yaml:"min_requests"`
+
+	// Window declares the length of the period that the executions are counted in. The counts are reset when the period passes.
+	Window time.Duration `json:"window" yaml:"window"`
+
+	// Cooldown declares how long the circuit stays open before the probe executions are allowed.
+	Cooldown time.Duration `json:"cooldown" yaml:"cooldown"`
+
+	// HalfOpenProbes declares the number of the probe executions that must succeed to close the circuit.
+	// Up to this number of executions are allowed concurrently while the circuit is half-open, and a single failure opens the circuit again.
+	HalfOpenProbes uint `json:"half_open_probes" yaml:"half_open_probes"`
+
+	// UnavailableMessage declares the message that a Command with CommandPropsBuilder.CircuitBreaker responds while the circuit is open.
+	UnavailableMessage string `json:"unavailable_message" yaml:"unavailable_message"`
+}
+
+// NewCircuitBreakerConfig creates and returns a new CircuitBreakerConfig instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{
+		FailureRate:        0.5,
+		MinRequests:        10,
+		Window:             1 * time.Minute,
+		Cooldown:           30 * time.Second,
+		HalfOpenProbes:     1,
+		UnavailableMessage: "This command is temporarily unavailable. Please try again later.",
+	}
+}
+
+// CircuitStats represents the statistics of a CircuitBreaker.
+type CircuitStats struct {
+	// State is the current state of the circuit.
+	State CircuitState
+
+	// Requests is the number of the finished executions in the current window.
+	Requests uint64
+
+	// Failures is the number of the failed executions in the current window.
+	Failures uint64
+
+	// Rejected is the number of the executions that are rejected while the circuit is open or half-open.
+	Rejected uint64
+
+	// Trips is the number of times the circuit opened.
+	Trips uint64
+
+	// OpenedAt is the time when the circuit opened last time. This is zero when the circuit never opened.
+	OpenedAt time.Time
+}
+
+// CircuitStatus represents the CircuitBreaker of a Command that is built with CommandPropsBuilder.CircuitBreaker.
+type CircuitStatus struct {
+	// Identifier is the identifier of the Command.
+	Identifier string
+
+	// Stats is the statistics of the Command's CircuitBreaker.
+	Stats *CircuitStats
+}
+
+// CircuitBreaker protects the chat responsiveness from a flaky upstream such as an external API.
+// When the failure rate exceeds the threshold, the circuit opens and the executions are rejected with ErrCircuitOpen for a cooldown
+// instead of waiting for the upstream to time out. After the cooldown, the probe executions are allowed to see if the upstream is recovered.
+//
+// Use CommandPropsBuilder.CircuitBreaker to protect a Command. A CircuitBreaker can also be used by itself in a Command or a ScheduledTask:
+//
+//	breaker := sarah.NewCircuitBreaker(sarah.NewCircuitBreakerConfig())
+//	err := breaker.Execute(func() error {
+//		return callWeatherAPI(ctx)
+//	})
+type CircuitBreaker struct {
+	config   *CircuitBreakerConfig
+	state    CircuitState
+	window   time.Time
+	requests uint64
+	failures uint64
+	rejected uint64
+	trips    uint64
+	openedAt time.Time
+	probes   uint
+	passed   uint
+	mutex    sync.Mutex
+}
+
+// NewCircuitBreaker creates and returns a new CircuitBreaker with the given configuration.
+func NewCircuitBreaker(config *CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		config: config,
+		state:  CircuitClosed,
+	}
+}
+
+// Allow tells if an execution is allowed at the moment.
+// When allowed, the returned function must be called with the result of the execution so the CircuitBreaker can judge the state.
+// Otherwise, ErrCircuitOpen is returned.
+func (cb *CircuitBreaker) Allow() (func(error), error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := currentClock().Now()
+	if cb.state == CircuitOpen && now.Sub(cb.openedAt) >= cb.config.Cooldown {
+		cb.state = CircuitHalfOpen
+		cb.probes = 0
+		cb.passed = 0
+	}
+
+	switch cb.state {
+	case CircuitOpen:
+		cb.rejected++
+		return nil, ErrCircuitOpen
+
+	case CircuitHalfOpen:
+		if cb.probes >= cb.halfOpenProbes() {
+			cb.rejected++
+			return nil, ErrCircuitOpen
+		}
+		cb.probes++
+
+	}
+
+	state := cb.state
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			cb.done(state, err)
+		})
+	}, nil
+}
+
+// Execute calls the given function when the circuit allows, and returns its result.
+// ErrCircuitOpen is returned without calling the function while the circuit is open.
+// A panic in the function is recorded as a failure and is then propagated to the caller.
+func (cb *CircuitBreaker) Execute(fnc func() error) (err error) {
+	done, err := cb.Allow()
+	if err != nil {
+		return err
+	}
+	defer finishExecution(done, &err)
+
+	return fnc()
+}
+
+// finishExecution passes the result of an execution to the function that CircuitBreaker.Allow returned.
+// This must be deferred so a panicking execution is still recorded as a failure;
+// otherwise a half-open probe is never released and the circuit keeps rejecting every execution.
+// The recovered panic is re-thrown so the caller can handle it as usual.
+func finishExecution(done func(error), err *error) {
+	if r := recover(); r != nil {
+		done(fmt.Errorf("%w: %v", errExecutionPanicked, r))
+		panic(r)
+	}
+	done(*err)
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	return cb.Stats().State
+}
+
+// Stats returns the current statistics of the CircuitBreaker.
+func (cb *CircuitBreaker) Stats() *CircuitStats {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	state := cb.state
+	if state == CircuitOpen && currentClock().Now().Sub(cb.openedAt) >= cb.config.Cooldown {
+		// The next execution is allowed as a probe.
+		state = CircuitHalfOpen
+	}
+
+	return &CircuitStats{
+		State:    state,
+		Requests: cb.requests,
+		Failures: cb.failures,
+		Rejected: cb.rejected,
+		Trips:    cb.trips,
+		OpenedAt: cb.openedAt,
+	}
+}
+
+// setConfig replaces the configuration while keeping the current state.
+func (cb *CircuitBreaker) setConfig(config *CircuitBreakerConfig) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.config = config
+}
+
+func (cb *CircuitBreaker) unavailableMessage() string {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	return cb.config.UnavailableMessage
+}
+
+func (cb *CircuitBreaker) halfOpenProbes() uint {
+	if cb.config.HalfOpenProbes == 0 {
+		return 1
+	}
+	return cb.config.HalfOpenProbes
+}
+
+// done records the result of an execution that is allowed in the given state.
+func (cb *CircuitBreaker) done(allowedIn CircuitState, err error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := currentClock().Now()
+
+	if allowedIn == CircuitHalfOpen {
+		if cb.state != CircuitHalfOpen {
+			// Another probe already judged the state.
+			return
+		}
+
+		if err != nil {
+			cb.open(now)
+			return
+		}
+
+		cb.passed++
+		if cb.passed >= cb.halfOpenProbes() {
+			cb.state = CircuitClosed
+			cb.resetWindow(now)
+		}
+		return
+	}
+
+	if cb.state != CircuitClosed {
+		// The execution started before the circuit opened.
+		return
+	}
+
+	if cb.window.IsZero() || now.Sub(cb.window) >= cb.config.Window {
+		cb.resetWindow(now)
+	}
+	cb.requests++
+	if err != nil {
+		cb.failures++
+	}
+
+	if cb.requests >= uint64(cb.config.MinRequests) && float64(cb.failures)/float64(cb.requests) >= cb.config.FailureRate {
+		cb.open(now)
+	}
+}
+
+func (cb *CircuitBreaker) open(now time.Time) {
+	cb.state = CircuitOpen
+	cb.openedAt = now
+	cb.trips++
+	cb.resetWindow(now)
+}
+
+func (cb *CircuitBreaker) resetWindow(now time.Time) {
+	cb.window = now
+	cb.requests = 0
+	cb.failures = 0
+}
+
+// CircuitBreaker is a setter to protect the Command with a CircuitBreaker.
+// While the circuit is open, the Command is not executed and CircuitBreakerConfig.UnavailableMessage is returned right away
+// so the users are not kept waiting for a flaky upstream API. An error returned by the Command counts as a failure.
+// A rejected execution is not notified to CommandExecutionObserver since the Command is not executed.
+// The state of the circuit is reported via BotStatus.Circuits and is kept when the Command is rebuilt with an updated configuration.
+//
+//	props := sarah.NewCommandPropsBuilder().
+//		BotType(slack.SLACK).
+//		Identifier("weather").
+//		CircuitBreaker(sarah.NewCircuitBreakerConfig()).
+//		...
+//		MustBuild()
+func (builder *CommandPropsBuilder) CircuitBreaker(config *CircuitBreakerConfig) *CommandPropsBuilder {
+	builder.props.circuitBreaker = config
+	return builder
+}
+
+// circuitBreakingCommand is satisfied by a Command that is built with CommandPropsBuilder.CircuitBreaker.
+type circuitBreakingCommand interface {
+	circuitBreakerConfig() *CircuitBreakerConfig
+}
+
+// circuitBreakers holds the CircuitBreakers of the Commands so their states survive the rebuilds of the Commands.
+// Calls to its methods are thread-safe.
+type circuitBreakers struct {
+	breakers map[BotType]map[string]*CircuitBreaker
+	ids      map[BotType][]string
+	mutex    sync.RWMutex
+}
+
+func (c *circuitBreakers) get(botType BotType, id string, config *CircuitBreakerConfig) *CircuitBreaker {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.breakers == nil {
+		c.breakers = map[BotType]map[string]*CircuitBreaker{}
+		c.ids = map[BotType][]string{}
+	}
+	if _, ok := c.breakers[botType]; !ok {
+		c.breakers[botType] = map[string]*CircuitBreaker{}
+	}
+
+	breaker, ok := c.breakers[botType][id]
+	if ok {
+		breaker.setConfig(config)
+		return breaker
+	}
+
+	breaker = NewCircuitBreaker(config)
+	c.breakers[botType][id] = breaker
+	c.ids[botType] = append(c.ids[botType], id)
+	return breaker
+}
+
+// stats returns the states of the CircuitBreakers of the Bot in the order of their creation.
+func (c *circuitBreakers) stats(botType BotType) []CircuitStatus {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var circuits []CircuitStatus
+	for _, id := range c.ids[botType] {
+		circuits = append(circuits, CircuitStatus{
+			Identifier: id,
+			Stats:      c.breakers[botType][id].Stats(),
+		})
+	}
+	return circuits
+}
+
+// circuitBreakerCommand wraps a Command and rejects its execution while the circuit is open.
+type circuitBreakerCommand struct {
+	Command
+	breaker *CircuitBreaker
+}
+
+func (command *circuitBreakerCommand) Execute(ctx context.Context, input Input) (res *CommandResponse, err error) {
+	done, err := command.breaker.Allow()
+	if err != nil {
+		LoggerFromContext(ctx).Warnf("Reject the execution of %s: %s", command.Identifier(), err.Error())
+		return &CommandResponse{
+			Content: command.breaker.unavailableMessage(),
+		}, nil
+	}
+
+	defer finishExecution(done, &err)
+
+	return command.Command.Execute(ctx, input)
+}
+
+// Unwrap returns the wrapped Command.
+// The preview in the dry-run mode is resolved through this and does not call the upstream, so the circuit is not involved.
+func (command *circuitBreakerCommand) Unwrap() Command {
+	return command.Command
+}
@@ -0,0 +1,398 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewCircuitBreakerConfig(t *testing.T) {
+	config := NewCircuitBreakerConfig()
+
+	if config.FailureRate != 0.5 {
+		t.Errorf("Unexpected failure rate is set: %f.", config.FailureRate)
+	}
+
+	if config.MinRequests != 10 {
+		t.Errorf("Unexpected minimum requests is set: %d.", config.MinRequests)
+	}
+
+	if config.Window != time.Minute {
+		t.Errorf("Unexpected window is set: %s.", config.Window)
+	}
+
+	if config.Cooldown != 30*time.Second {
+		t.Errorf("Unexpected cooldown is set: %s.", config.Cooldown)
+	}
+
+	if config.HalfOpenProbes != 1 {
+		t.Errorf("Unexpected number of probes is set: %d.", config.HalfOpenProbes)
+	}
+
+	if config.UnavailableMessage == "" {
+		t.Error("Default message is not set.")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		breaker := NewCircuitBreaker(&CircuitBreakerConfig{
+			FailureRate:    0.5,
+			MinRequests:    4,
+			Window:         time.Minute,
+			Cooldown:       30 * time.Second,
+			HalfOpenProbes: 2,
+		})
+		failure := errors.New("upstream error")
+
+		// One failure and two successes do not satisfy MinRequests.
+		for _, err := range []error{failure, nil, nil} {
+			_ = breaker.Execute(func() error {
+				return err
+			})
+		}
+		if breaker.State() != CircuitClosed {
+			t.Fatalf("Unexpected state: %s.", breaker.State())
+		}
+
+		// The fourth execution makes the failure rate 50%.
+		_ = breaker.Execute(func() error {
+			return failure
+		})
+		stats := breaker.Stats()
+		if stats.State != CircuitOpen || stats.Trips != 1 || !stats.OpenedAt.Equal(now) {
+			t.Fatalf("Unexpected stats: %#v.", stats)
+		}
+
+		// Rejected while open
+		called := false
+		err := breaker.Execute(func() error {
+			called = true
+			return nil
+		})
+		if !errors.Is(err, ErrCircuitOpen) || called {
+			t.Errorf("Execution should be rejected: %#v.", err)
+		}
+		if breaker.Stats().Rejected != 1 {
+			t.Errorf("Rejection is not counted: %d.", breaker.Stats().Rejected)
+		}
+
+		// Half-open after the cooldown
+		now = now.Add(30 * time.Second)
+		if breaker.State() != CircuitHalfOpen {
+			t.Fatalf("Unexpected state: %s.", breaker.State())
+		}
+
+		// Up to HalfOpenProbes executions are allowed concurrently.
+		done1, err := breaker.Allow()
+		if err != nil {
+			t.Fatalf("Probe should be allowed: %s.", err.Error())
+		}
+		done2, err := breaker.Allow()
+		if err != nil {
+			t.Fatalf("Probe should be allowed: %s.", err.Error())
+		}
+		_, err = breaker.Allow()
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Extra probe should be rejected: %#v.", err)
+		}
+
+		// A failed probe opens the circuit again.
+		done1(failure)
+		done2(nil)
+		stats = breaker.Stats()
+		if stats.State != CircuitOpen || stats.Trips != 2 || !stats.OpenedAt.Equal(now) {
+			t.Fatalf("Unexpected stats: %#v.", stats)
+		}
+
+		// Successful probes close the circuit.
+		now = now.Add(30 * time.Second)
+		for i := 0; i < 2; i++ {
+			err = breaker.Execute(func() error {
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Probe should be allowed: %s.", err.Error())
+			}
+		}
+		stats = breaker.Stats()
+		if stats.State != CircuitClosed || stats.Requests != 0 || stats.Failures != 0 {
+			t.Errorf("Unexpected stats: %#v.", stats)
+		}
+	})
+}
+
+func TestCircuitBreaker_Window(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		breaker := NewCircuitBreaker(&CircuitBreakerConfig{
+			FailureRate: 1,
+			MinRequests: 2,
+			Window:      time.Minute,
+			Cooldown:    time.Minute,
+		})
+		failure := errors.New("upstream error")
+
+		_ = breaker.Execute(func() error {
+			return failure
+		})
+
+		// The previous failure is out of the window.
+		now = now.Add(time.Minute)
+		_ = breaker.Execute(func() error {
+			return failure
+		})
+
+		stats := breaker.Stats()
+		if stats.State != CircuitClosed || stats.Requests != 1 || stats.Failures != 1 {
+			t.Errorf("Unexpected stats: %#v.", stats)
+		}
+	})
+}
+
+func TestCircuitBreaker_LateResult(t *testing.T) {
+	breaker := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureRate: 1,
+		MinRequests: 1,
+		Window:      time.Minute,
+		Cooldown:    time.Minute,
+	})
+
+	done, err := breaker.Allow()
+	if err != nil {
+		t.Fatalf("Execution should be allowed: %s.", err.Error())
+	}
+
+	_ = breaker.Execute(func() error {
+		return errors.New("upstream error")
+	})
+
+	// The execution started before the circuit opened does not affect the state.
+	done(nil)
+	done(nil)
+	stats := breaker.Stats()
+	if stats.State != CircuitOpen || stats.Requests != 0 {
+		t.Errorf("Unexpected stats: %#v.", stats)
+	}
+}
+
+func Test_circuitBreakerCommand_Execute_PanicInHalfOpen(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		breaker := NewCircuitBreaker(&CircuitBreakerConfig{
+			FailureRate:    1,
+			MinRequests:    1,
+			Window:         time.Minute,
+			Cooldown:       time.Minute,
+			HalfOpenProbes: 1,
+		})
+		panicking := true
+		command := &circuitBreakerCommand{
+			Command: &DummyCommand{
+				IdentifierValue: "weather",
+				ExecuteFunc: func(_ context.Context, _ Input) (*CommandResponse, error) {
+					if panicking {
+						panic("upstream client panics")
+					}
+					return nil, nil
+				},
+			},
+			breaker: breaker,
+		}
+		execute := func() (recovered interface{}) {
+			defer func() {
+				recovered = recover()
+			}()
+			_, _ = command.Execute(context.TODO(), &DummyInput{})
+			return nil
+		}
+
+		// A panic is counted as a failure and opens the circuit.
+		if execute() == nil {
+			t.Fatal("Panic should be propagated.")
+		}
+		if breaker.State() != CircuitOpen {
+			t.Fatalf("Unexpected state: %s.", breaker.State())
+		}
+
+		// The panicking probe releases its slot and opens the circuit again.
+		now = now.Add(time.Minute)
+		if execute() == nil {
+			t.Fatal("Panic should be propagated.")
+		}
+		stats := breaker.Stats()
+		if stats.State != CircuitOpen || stats.Trips != 2 {
+			t.Fatalf("Unexpected stats: %#v.", stats)
+		}
+
+		// The next probe is allowed after the cooldown and closes the circuit.
+		now = now.Add(time.Minute)
+		panicking = false
+		if recovered := execute(); recovered != nil {
+			t.Fatalf("Unexpected panic: %#v.", recovered)
+		}
+		if breaker.State() != CircuitClosed {
+			t.Errorf("Unexpected state: %s.", breaker.State())
+		}
+	})
+}
+
+func TestCircuitBreaker_Execute_Panic(t *testing.T) {
+	breaker := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureRate: 1,
+		MinRequests: 1,
+		Window:      time.Minute,
+		Cooldown:    time.Minute,
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Panic should be propagated.")
+			}
+		}()
+		_ = breaker.Execute(func() error {
+			panic("panic")
+		})
+	}()
+
+	stats := breaker.Stats()
+	if stats.State != CircuitOpen || stats.Trips != 1 {
+		t.Errorf("Panic should be recorded as a failure: %#v.", stats)
+	}
+}
+
+func TestCommandPropsBuilder_CircuitBreaker(t *testing.T) {
+	config := NewCircuitBreakerConfig()
+	props := NewCommandPropsBuilder().
+		BotType("dummy").
+		Identifier("weather").
+		MatchFunc(func(_ Input) bool {
+			return true
+		}).
+		Func(func(_ context.Context, _ Input) (*CommandResponse, error) {
+			return nil, nil
+		}).
+		Instruction("weather").
+		CircuitBreaker(config).
+		MustBuild()
+
+	if props.circuitBreaker != config {
+		t.Fatal("Given config is not set.")
+	}
+
+	command, err := buildCommand(context.TODO(), props, &DummyConfigWatcher{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	breaking, ok := command.(circuitBreakingCommand)
+	if !ok || breaking.circuitBreakerConfig() != config {
+		t.Error("Built Command does not have the config.")
+	}
+}
+
+func Test_runner_wrapCommand_WithCircuitBreaker(t *testing.T) {
+	config := &CircuitBreakerConfig{
+		FailureRate:        1,
+		MinRequests:        1,
+		Window:             time.Minute,
+		Cooldown:           time.Minute,
+		UnavailableMessage: "unavailable",
+	}
+	command := &defaultCommand{
+		identifier: "weather",
+		commandFunc: func(_ context.Context, _ Input, _ ...CommandConfig) (*CommandResponse, error) {
+			return nil, errors.New("upstream error")
+		},
+		mutating:       true,
+		circuitBreaker: config,
+	}
+
+	r := &runner{
+		executionObservers: []CommandExecutionObserver{
+			func(_ context.Context, _ *CommandExecution) {},
+		},
+	}
+	wrapped, ok := r.wrapCommand("dummy", command).(*circuitBreakerCommand)
+	if !ok {
+		t.Fatal("Command is not wrapped.")
+	}
+
+	if _, ok := wrapped.Command.(*observedCommand); !ok {
+		t.Errorf("CircuitBreaker should wrap the observed Command: %T.", wrapped.Command)
+	}
+
+	if mutating, ok := resolveCommand[MutatingCommand](wrapped); !ok || !mutating.Mutating() {
+		t.Error("Mutating flag should be kept.")
+	}
+
+	_, err := wrapped.Execute(context.TODO(), &DummyInput{})
+	if err == nil {
+		t.Fatal("Error should be returned from the Command.")
+	}
+
+	res, err := wrapped.Execute(context.TODO(), &DummyInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if res == nil || res.Content != "unavailable" {
+		t.Errorf("Unexpected response is returned: %#v.", res)
+	}
+
+	// The state is kept when the Command is rebuilt.
+	rebuilt := r.wrapCommand("dummy", command).(*circuitBreakerCommand)
+	if rebuilt.breaker != wrapped.breaker {
+		t.Error("CircuitBreaker should be reused.")
+	}
+
+	circuits := r.circuits.stats("dummy")
+	if len(circuits) != 1 {
+		t.Fatalf("Unexpected number of circuits are returned: %d.", len(circuits))
+	}
+	if circuits[0].Identifier != "weather" || circuits[0].Stats.State != CircuitOpen || circuits[0].Stats.Rejected != 1 {
+		t.Errorf("Unexpected circuit is returned: %#v.", circuits[0])
+	}
+
+	if r.circuits.stats("other") != nil {
+		t.Error("Circuits of another Bot should not be returned.")
+	}
+}
+
+func Test_status_snapshot_Circuits(t *testing.T) {
+	s := &status{}
+	s.addBot(&DummyBot{BotTypeValue: "dummy"})
+
+	if s.snapshot().Bots[0].Circuits != nil {
+		t.Error("No circuit should be returned.")
+	}
+
+	s.setCircuitStats(func(botType BotType) []CircuitStatus {
+		return []CircuitStatus{{Identifier: string(botType), Stats: &CircuitStats{State: CircuitClosed}}}
+	})
+
+	circuits := s.snapshot().Bots[0].Circuits
+	if len(circuits) != 1 || circuits[0].Identifier != "dummy" {
+		t.Errorf("Unexpected circuits are returned: %#v.", circuits)
+	}
+}
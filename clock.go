@@ -0,0 +1,136 @@
+package sarah
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to Sarah's time-based behaviors
+// such as supervision windows, rate limiters, retry intervals, reminders of expiring user contexts, and intervals between batched task results.
+// The execution timing of ScheduledTasks and the expiration of the UserContext values in the default UserContextStorage also follow this.
+// Register a Clock via RegisterClock to control time in tests; see sarahtest.FakeClock for a ready-to-use implementation.
+//
+// Latency measurements such as those in WorkerStats and SelfTest always use the system clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a new Timer that sends the current time on its channel after at least the given duration.
+	NewTimer(d time.Duration) Timer
+
+	// AfterFunc waits for the given duration to elapse and then calls the given function in its own goroutine.
+	// The returned Timer can be used to cancel the call. Its channel is nil.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer represents a single event that a Clock delivers.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing.
+	// This returns true if the call stops the timer, false if the timer has already expired or been stopped.
+	Stop() bool
+}
+
+// RegisterClock registers the given Clock to be used by Sarah's time-based behaviors instead of the system clock.
+// The Clock is shared in the process while Sarah runs, so this also affects the functions called outside of Run such as EscalateAfter and ReplyOnBlockedInput,
+// and the Runners created by New. Hence there is no Runner counterpart of this function.
+// The previous Clock is restored when Sarah stops.
+func RegisterClock(clock Clock) {
+	options.register(func(r *runner) {
+		r.clock = clock
+	})
+}
+
+var clock = struct {
+	current Clock
+
+	// generation is incremented on every replacement so a stale restoration does not override a newer Clock.
+	generation uint64
+	mutex      sync.RWMutex
+}{
+	current: &systemClock{},
+}
+
+// CurrentClock returns the Clock registered via RegisterClock while Sarah runs, or the system clock otherwise.
+// Storage and Adapter implementations in other packages use this so their time-based behaviors follow the registered Clock.
+func CurrentClock() Clock {
+	return currentClock()
+}
+
+// currentClock returns the Clock registered via RegisterClock, or the system clock by default.
+func currentClock() Clock {
+	clock.mutex.RLock()
+	defer clock.mutex.RUnlock()
+	return clock.current
+}
+
+// setClock replaces the process-wide Clock with the given one, and returns a function that restores the previous one.
+// The restoration does nothing when the Clock is replaced again in the meantime.
+func setClock(c Clock) func() {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	previous := clock.current
+	clock.current = c
+	clock.generation++
+	generation := clock.generation
+
+	return func() {
+		clock.mutex.Lock()
+		defer clock.mutex.Unlock()
+
+		if clock.generation != generation {
+			return
+		}
+		clock.current = previous
+		clock.generation++
+	}
+}
+
+type clockKey struct{}
+
+func withRunnerClock(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// clockFromContext returns the Clock of the runner that the given context derives from.
+// The process-wide Clock is returned when the context does not carry one.
+func clockFromContext(ctx context.Context) Clock {
+	c, ok := ctx.Value(clockKey{}).(Clock)
+	if !ok {
+		return currentClock()
+	}
+	return c
+}
+
+// systemClock is a Clock implementation that relies on the time package.
+type systemClock struct{}
+
+var _ Clock = (*systemClock)(nil)
+
+func (*systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (*systemClock) NewTimer(d time.Duration) Timer {
+	return &systemTimer{timer: time.NewTimer(d)}
+}
+
+func (*systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return &systemTimer{timer: time.AfterFunc(d, f)}
+}
+
+type systemTimer struct {
+	timer *time.Timer
+}
+
+func (t *systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t *systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
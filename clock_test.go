@@ -0,0 +1,242 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type DummyClock struct {
+	NowFunc       func() time.Time
+	NewTimerFunc  func(time.Duration) Timer
+	AfterFuncFunc func(time.Duration, func()) Timer
+}
+
+func (c *DummyClock) Now() time.Time {
+	return c.NowFunc()
+}
+
+func (c *DummyClock) NewTimer(d time.Duration) Timer {
+	return c.NewTimerFunc(d)
+}
+
+func (c *DummyClock) AfterFunc(d time.Duration, f func()) Timer {
+	return c.AfterFuncFunc(d, f)
+}
+
+type DummyTimer struct {
+	CValue chan time.Time
+}
+
+func (t *DummyTimer) C() <-chan time.Time {
+	return t.CValue
+}
+
+func (t *DummyTimer) Stop() bool {
+	return true
+}
+
+// withClock replaces the process-wide Clock during the given function call.
+func withClock(c Clock, fnc func()) {
+	restore := setClock(c)
+	defer restore()
+	fnc()
+}
+
+func TestRegisterClock(t *testing.T) {
+	SetupAndRun(func() {
+		c := &DummyClock{}
+		RegisterClock(c)
+
+		r := &runner{}
+		options.apply(r)
+		if r.clock != c {
+			t.Errorf("Given Clock is not set: %#v.", r.clock)
+		}
+	})
+}
+
+func TestRegisterClock_RestoredOnStop(t *testing.T) {
+	SetupAndRun(func() {
+		original := currentClock()
+		c := &DummyClock{NowFunc: time.Now}
+		RegisterClock(c)
+
+		var given Clock
+		RegisterBot(&DummyBot{
+			BotTypeValue: "dummy",
+			RunFunc: func(ctx context.Context, _ func(Input) error, _ func(error)) {
+				given = clockFromContext(ctx)
+				<-ctx.Done()
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		config := NewConfig()
+		config.TimeZone = time.UTC.String()
+		err := Run(ctx, config)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		if currentClock() != c {
+			t.Error("Registered Clock is not effective while running.")
+		}
+
+		stopped := running.get().stopped
+		cancel()
+		select {
+		case <-stopped:
+			// O.K.
+		case <-time.NewTimer(10 * time.Second).C:
+			t.Fatal("Runner is not stopped.")
+		}
+
+		if given != c {
+			t.Errorf("Registered Clock is not given to the Bot: %#v.", given)
+		}
+		if currentClock() != original {
+			t.Errorf("Previous Clock is not restored: %#v.", currentClock())
+		}
+	})
+}
+
+func TestCurrentClock(t *testing.T) {
+	c := &DummyClock{}
+	withClock(c, func() {
+		if CurrentClock() != c {
+			t.Errorf("Registered Clock is not returned: %#v.", CurrentClock())
+		}
+	})
+}
+
+func Test_setClock(t *testing.T) {
+	original := currentClock()
+	first := &DummyClock{}
+	second := &DummyClock{}
+
+	restoreFirst := setClock(first)
+	restoreSecond := setClock(second)
+
+	// A stale restoration does not override the newer Clock.
+	restoreFirst()
+	if currentClock() != second {
+		t.Errorf("Newer Clock is overridden: %#v.", currentClock())
+	}
+
+	restoreSecond()
+	if currentClock() != first {
+		t.Errorf("Previous Clock is not restored: %#v.", currentClock())
+	}
+
+	// Put back the Clock for the other tests.
+	setClock(original)
+}
+
+func Test_clockFromContext(t *testing.T) {
+	if clockFromContext(context.TODO()) != currentClock() {
+		t.Error("Process-wide Clock should be returned by default.")
+	}
+
+	c := &DummyClock{}
+	if clockFromContext(withRunnerClock(context.TODO(), c)) != c {
+		t.Error("Clock of the runner is not returned.")
+	}
+}
+
+func Test_systemClock(t *testing.T) {
+	c := &systemClock{}
+	if time.Since(c.Now()) > time.Minute {
+		t.Errorf("Unexpected time is returned: %s.", c.Now())
+	}
+
+	timer := c.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+		// O.K.
+	case <-time.NewTimer(10 * time.Second).C:
+		t.Error("Timer did not fire.")
+	}
+
+	called := make(chan struct{})
+	c.AfterFunc(time.Millisecond, func() {
+		close(called)
+	})
+	select {
+	case <-called:
+		// O.K.
+	case <-time.NewTimer(10 * time.Second).C:
+		t.Error("Function is not called.")
+	}
+
+	if c.AfterFunc(time.Hour, func() {}).Stop() != true {
+		t.Error("Pending timer should be stopped.")
+	}
+}
+
+func TestEscalateAfter_WithClock(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		directive := &SupervisionDirective{}
+		escalate := EscalateAfter(2, time.Minute, directive)
+
+		if escalate() != nil {
+			t.Fatal("Directive should not be returned on the first occurrence.")
+		}
+
+		// The first occurrence falls out of the window.
+		now = now.Add(time.Minute)
+		if escalate() != nil {
+			t.Fatal("Directive should not be returned when the previous occurrence is out of the window.")
+		}
+
+		now = now.Add(59 * time.Second)
+		if escalate() != directive {
+			t.Error("Directive should be returned on the second occurrence within the window.")
+		}
+	})
+}
+
+func TestRetryWithBackoff_WithClock(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	var waited []time.Duration
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+		NewTimerFunc: func(d time.Duration) Timer {
+			// Fire immediately while advancing the fake time.
+			waited = append(waited, d)
+			now = now.Add(d)
+			ch := make(chan time.Time, 1)
+			ch <- now
+			return &DummyTimer{CValue: ch}
+		},
+	}
+
+	withClock(c, func() {
+		policy := &BackoffPolicy{
+			BaseInterval:   time.Second,
+			MaxInterval:    time.Hour,
+			Multiplier:     2,
+			MaxElapsedTime: 10 * time.Second,
+		}
+		err := RetryWithBackoff(context.TODO(), policy, func() error {
+			return errors.New("dummy")
+		})
+		if err == nil {
+			t.Fatal("Expected error is not returned.")
+		}
+
+		// 1s + 2s + 4s elapse, and the next 8s interval exceeds the max elapsed time.
+		if len(waited) != 3 {
+			t.Errorf("Unexpected waits: %#v.", waited)
+		}
+	})
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// file is a generated file.
+type file struct {
+	name string
+	body []byte
+}
+
+type adapterView struct {
+	Name       string
+	Field      string
+	Package    string
+	BotType    string
+	NewAdapter string
+}
+
+type pluginView struct {
+	Package string
+	Admin   bool
+}
+
+type view struct {
+	Module       string
+	SarahVersion string
+	GoVersion    string
+	Imports      []string
+	Adapters     []*adapterView
+	Plugins      []*pluginView
+	Storage      bool
+	Daemon       bool
+	Admin        bool
+	Config       string
+	Envs         []string
+	Ports        []int
+}
+
+var mainTemplate = template.Must(template.New("main.go").Parse(`// Package main runs a bot built with go-sarah.
+// This file is generated by sarah-gen as a starting point, so edit as needed.
+package main
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+type config struct {
+	Runner *sarah.Config ` + "`yaml:\"runner\"`" + `
+{{- if .Storage}}
+	Storage *sarah.CacheConfig ` + "`yaml:\"storage\"`" + `
+{{- end}}
+{{- if .Daemon}}
+	Daemon *daemon.Config ` + "`yaml:\"daemon\"`" + `
+{{- end}}
+{{- if .Admin}}
+	Admins []string ` + "`yaml:\"admins\"`" + `
+{{- end}}
+{{- range .Adapters}}
+	{{.Field}} *{{.Package}}.Config ` + "`yaml:\"{{.Name}}\"`" + `
+{{- end}}
+}
+
+func newConfig() *config {
+	// Use a constructor function for each config struct, so default values are pre-set.
+	return &config{
+		Runner: sarah.NewConfig(),
+{{- if .Storage}}
+		Storage: sarah.NewCacheConfig(),
+{{- end}}
+{{- if .Daemon}}
+		Daemon: daemon.NewConfig(),
+{{- end}}
+{{- range .Adapters}}
+		{{.Field}}: {{.Package}}.NewConfig(),
+{{- end}}
+	}
+}
+
+func main() {
+	path := flag.String("config", "config.yml", "path to the configuration file.")
+	flag.Parse()
+
+	config, err := readConfig(*path)
+	if err != nil {
+		logger.Errorf("Failed to read configuration: %+v", err)
+		os.Exit(1)
+	}
+{{- if .Storage}}
+
+	// Set up a storage that is shared among the Bots.
+	storage := sarah.NewUserContextStorage(config.Storage)
+{{- end}}
+{{range .Adapters}}
+	err = setup{{.Field}}(config{{if $.Storage}}, storage{{end}})
+	if err != nil {
+		logger.Errorf("Failed to set up {{.Name}}: %+v", err)
+		os.Exit(1)
+	}
+{{end}}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+{{if .Daemon}}
+	// Run until a signal is received or the service manager requests to stop.
+	err = daemon.Run(ctx, daemon.DefaultRunner(), config.Runner, config.Daemon)
+	if err != nil {
+		logger.Errorf("Failed to run: %+v", err)
+		os.Exit(1)
+	}
+{{- else}}
+	err = sarah.Run(ctx, config.Runner)
+	if err != nil {
+		logger.Errorf("Failed to run: %+v", err)
+		os.Exit(1)
+	}
+
+	<-ctx.Done()
+	logger.Info("Stopping due to signal reception.")
+{{- end}}
+}
+
+// readConfig reads the configuration file.
+// Environment variables such as ${SLACK_TOKEN} are expanded, so credentials can be given to the container at runtime.
+func readConfig(path string) (*config, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := newConfig()
+	err = yaml.Unmarshal([]byte(os.ExpandEnv(string(body))), config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+{{range .Adapters}}
+func setup{{.Field}}(config *config{{if $.Storage}}, storage sarah.UserContextStorage{{end}}) error {
+	adapter, err := {{.NewAdapter}}
+	if err != nil {
+		return err
+	}
+
+	bot := sarah.NewBot(adapter{{if $.Storage}}, sarah.BotWithStorage(storage){{end}})
+	sarah.RegisterBot(bot)
+	registerPlugins({{.BotType}}, config)
+
+	return nil
+}
+{{end}}
+// registerPlugins registers the contrib plugins to the Bot with the given BotType.
+func registerPlugins(botType sarah.BotType, {{if .Admin}}config{{else}}_{{end}} *config) {
+{{- if .Admin}}
+	authorize := adminAuthorizer(config.Admins)
+{{- end}}
+{{- range .Plugins}}
+	sarah.RegisterCommandProps({{.Package}}.New(botType{{if .Admin}}, {{.Package}}.WithAuthorizer(authorize){{end}}).CommandProps())
+{{- end}}
+}
+{{- if .Admin}}
+
+// adminAuthorizer returns a function that allows only the users listed in the configuration to run the administrative commands.
+// The user ID is the last segment of sarah.Input.SenderKey, which the bundled adapters format as "channel|user."
+func adminAuthorizer(admins []string) func(sarah.Input) bool {
+	return func(input sarah.Input) bool {
+		key := input.SenderKey()
+		return slices.Contains(admins, key[strings.LastIndex(key, "|")+1:])
+	}
+}
+{{- end}}
+`))
+
+var goModTemplate = template.Must(template.New("go.mod").Parse(`module {{.Module}}
+
+go {{.GoVersion}}
+{{- if .SarahVersion}}
+
+require github.com/oklahomer/go-sarah/v4 {{.SarahVersion}}
+{{- end}}
+`))
+
+var dockerfileTemplate = template.Must(template.New("Dockerfile").Parse(`FROM golang:{{.GoVersion}} AS build
+WORKDIR /src
+COPY . .
+RUN go mod tidy && CGO_ENABLED=0 go build -trimpath -ldflags="-s -w" -o /bot .
+
+FROM gcr.io/distroless/static-debian12
+COPY --from=build /bot /bot
+COPY config.yml /etc/bot/config.yml
+{{- range .Ports}}
+EXPOSE {{.}}
+{{- end}}
+{{- if .Envs}}
+# Give the credentials at runtime such as "docker run{{range .Envs}} -e {{.}}{{end}} ..."
+{{- end}}
+ENTRYPOINT ["/bot", "-config", "/etc/bot/config.yml"]
+`))
+
+var configTemplate = template.Must(template.New("config.yml").Parse(`runner:
+  timezone: UTC
+{{- if .Storage}}
+storage:
+  expires_in: 3m
+{{- end}}
+{{- if .Daemon}}
+daemon:
+  service_name: sarah
+{{- end}}
+{{- if .Admin}}
+# The user IDs that are allowed to run the administrative commands.
+admins: []
+{{- end}}
+{{.Config}}`))
+
+// generate renders the files of the bot that the given Manifest declares.
+func generate(manifest *Manifest) ([]*file, error) {
+	v := newView(manifest)
+
+	templates := []*template.Template{mainTemplate, goModTemplate, configTemplate, dockerfileTemplate}
+	var files []*file
+	for _, tmpl := range templates {
+		buf := &bytes.Buffer{}
+		err := tmpl.Execute(buf, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+		}
+
+		body := buf.Bytes()
+		if strings.HasSuffix(tmpl.Name(), ".go") {
+			body, err = format.Source(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to format %s: %w", tmpl.Name(), err)
+			}
+		}
+
+		files = append(files, &file{
+			name: tmpl.Name(),
+			body: body,
+		})
+	}
+
+	return files, nil
+}
+
+func newView(manifest *Manifest) *view {
+	v := &view{
+		Module:       manifest.Module,
+		SarahVersion: manifest.SarahVersion,
+		GoVersion:    manifest.GoVersion,
+		Storage:      manifest.Storage != "",
+		Daemon:       manifest.Daemon,
+	}
+
+	imports := map[string]struct{}{
+		"context":                               {},
+		"flag":                                  {},
+		"github.com/oklahomer/go-kasumi/logger": {},
+		"github.com/oklahomer/go-sarah/v4":      {},
+		"gopkg.in/yaml.v2":                      {},
+		"os":                                    {},
+		"os/signal":                             {},
+		"syscall":                               {},
+	}
+	if manifest.Daemon {
+		imports["github.com/oklahomer/go-sarah/v4/daemon"] = struct{}{}
+	}
+
+	var configs []string
+	for _, adapter := range manifest.Adapters {
+		spec := adapters[adapter.Name]
+		imports[spec.importPath] = struct{}{}
+
+		newAdapter := spec.newAdapter
+		if len(spec.apis) > 0 {
+			api := adapter.API
+			if api == "" {
+				api = spec.defaultAPI
+			}
+			newAdapter = fmt.Sprintf(newAdapter, spec.apis[api])
+
+			// Slack's RTM API connects to Slack over WebSocket, so only Events API listens to the port.
+			if api == "events" && spec.port > 0 {
+				v.Ports = append(v.Ports, spec.port)
+			}
+		}
+
+		v.Adapters = append(v.Adapters, &adapterView{
+			Name:       adapter.Name,
+			Field:      spec.field,
+			Package:    path.Base(spec.importPath),
+			BotType:    spec.botType,
+			NewAdapter: newAdapter,
+		})
+		configs = append(configs, spec.config)
+		v.Envs = append(v.Envs, spec.envs...)
+	}
+	v.Config = strings.Join(configs, "")
+
+	for _, name := range manifest.Plugins {
+		spec := plugins[name]
+		imports[spec.importPath] = struct{}{}
+		v.Plugins = append(v.Plugins, &pluginView{
+			Package: path.Base(spec.importPath),
+			Admin:   spec.admin,
+		})
+		if spec.admin {
+			v.Admin = true
+		}
+	}
+	if v.Admin {
+		imports["slices"] = struct{}{}
+		imports["strings"] = struct{}{}
+	}
+
+	for imp := range imports {
+		v.Imports = append(v.Imports, imp)
+	}
+	sort.Strings(v.Imports)
+
+	return v
+}
+
+// write writes the given files to the directory.
+// An existing file is not overwritten unless force is true.
+func write(dir string, files []*file, force bool) error {
+	if !force {
+		for _, f := range files {
+			name := filepath.Join(dir, f.name)
+			_, err := os.Stat(name)
+			if err == nil {
+				return fmt.Errorf("%s already exists: use -force to overwrite", name)
+			}
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("failed to check %s: %w", name, err)
+			}
+		}
+	}
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	for _, f := range files {
+		name := filepath.Join(dir, f.name)
+		err := os.WriteFile(name, f.body, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func findFile(files []*file, name string) *file {
+	for _, f := range files {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func TestGenerate(t *testing.T) {
+	testSets := []struct {
+		manifest    *Manifest
+		imports     []string
+		contains    map[string][]string
+		notContains map[string][]string
+	}{
+		{
+			manifest: &Manifest{
+				Module:       "example.com/mybot",
+				SarahVersion: "v4.0.0",
+				GoVersion:    "1.21",
+				Adapters:     []*AdapterManifest{{Name: "slack"}, {Name: "gitter"}},
+				Storage:      "memory",
+				Plugins:      []string{"selftest", "commands"},
+				Daemon:       true,
+			},
+			imports: []string{
+				"github.com/oklahomer/go-sarah/v4/slack",
+				"github.com/oklahomer/go-sarah/v4/gitter",
+				"github.com/oklahomer/go-sarah/v4/contrib/selftest",
+				"github.com/oklahomer/go-sarah/v4/contrib/commands",
+				"github.com/oklahomer/go-sarah/v4/daemon",
+				"slices",
+			},
+			contains: map[string][]string{
+				"main.go": {
+					"slack.WithEventsPayloadHandler(slack.DefaultEventsPayloadHandler)",
+					"gitter.NewAdapter(config.Gitter)",
+					"sarah.BotWithStorage(storage)",
+					"registerPlugins(slack.SLACK, config)",
+					"registerPlugins(gitter.GITTER, config)",
+					"selftest.New(botType).CommandProps()",
+					"commands.New(botType, commands.WithAuthorizer(authorize)).CommandProps()",
+					"daemon.Run(ctx, daemon.DefaultRunner(), config.Runner, config.Daemon)",
+				},
+				"go.mod": {
+					"module example.com/mybot",
+					"go 1.21",
+					"require github.com/oklahomer/go-sarah/v4 v4.0.0",
+				},
+				"config.yml": {
+					"storage:",
+					"daemon:",
+					"admins: []",
+					"token: ${SLACK_TOKEN}",
+					"token: ${GITTER_TOKEN}",
+				},
+				"Dockerfile": {
+					"FROM golang:1.21 AS build",
+					"EXPOSE 8080",
+					"-e SLACK_TOKEN -e SLACK_APP_SECRET -e GITTER_TOKEN",
+				},
+			},
+		},
+		{
+			manifest: &Manifest{
+				Module:    "example.com/mybot",
+				GoVersion: "1.22",
+				Adapters:  []*AdapterManifest{{Name: "slack", API: "rtm"}},
+				Plugins:   []string{"selftest"},
+			},
+			imports: []string{
+				"github.com/oklahomer/go-sarah/v4/slack",
+				"github.com/oklahomer/go-sarah/v4/contrib/selftest",
+			},
+			contains: map[string][]string{
+				"main.go": {
+					"slack.WithRTMPayloadHandler(slack.DefaultRTMPayloadHandler)",
+					"sarah.NewBot(adapter)",
+					"sarah.Run(ctx, config.Runner)",
+					"func registerPlugins(botType sarah.BotType, _ *config)",
+				},
+				"go.mod": {
+					"go 1.22",
+				},
+				"Dockerfile": {
+					"FROM golang:1.22 AS build",
+				},
+			},
+			notContains: map[string][]string{
+				"main.go": {
+					"storage",
+					"daemon",
+					"adminAuthorizer",
+				},
+				"go.mod": {
+					"require",
+				},
+				"config.yml": {
+					"storage:",
+					"daemon:",
+					"admins:",
+				},
+				"Dockerfile": {
+					"EXPOSE",
+				},
+			},
+		},
+	}
+
+	for i, testSet := range testSets {
+		files, err := generate(testSet.manifest)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i, err)
+		}
+
+		for _, name := range []string{"main.go", "go.mod", "config.yml", "Dockerfile"} {
+			if findFile(files, name) == nil {
+				t.Errorf("%s is not generated on test #%d.", name, i)
+			}
+		}
+
+		main := findFile(files, "main.go")
+		parsed, err := parser.ParseFile(token.NewFileSet(), main.name, main.body, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("Generated main.go can not be parsed on test #%d: %s.", i, err)
+		}
+		imported := map[string]struct{}{}
+		for _, spec := range parsed.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			imported[path] = struct{}{}
+		}
+		for _, path := range testSet.imports {
+			if _, ok := imported[path]; !ok {
+				t.Errorf("%s is not imported on test #%d.", path, i)
+			}
+		}
+
+		for name, strs := range testSet.contains {
+			body := string(findFile(files, name).body)
+			for _, str := range strs {
+				if !strings.Contains(body, str) {
+					t.Errorf("%q is not included in %s on test #%d:\n%s", str, name, i, body)
+				}
+			}
+		}
+
+		for name, strs := range testSet.notContains {
+			body := string(findFile(files, name).body)
+			for _, str := range strs {
+				if strings.Contains(body, str) {
+					t.Errorf("%q is unexpectedly included in %s on test #%d:\n%s", str, name, i, body)
+				}
+			}
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mybot")
+	files := []*file{
+		{
+			name: "main.go",
+			body: []byte("package main\n"),
+		},
+	}
+
+	err := write(dir, files, false)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatalf("Generated file can not be read: %s.", err)
+	}
+	if string(body) != "package main\n" {
+		t.Errorf("Unexpected content is written: %s.", string(body))
+	}
+
+	err = write(dir, files, false)
+	if err == nil {
+		t.Error("Expected error is not returned when the file already exists.")
+	}
+
+	err = write(dir, files, true)
+	if err != nil {
+		t.Errorf("Unexpected error is returned with force: %s.", err)
+	}
+}
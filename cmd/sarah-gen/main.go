@@ -0,0 +1,47 @@
+// Command sarah-gen generates a ready-to-build main package of a bot from a small YAML manifest.
+// The manifest declares the adapters, the storage, and the contrib plugins to wire.
+// See Manifest for the available fields.
+//
+//	go run github.com/oklahomer/go-sarah/v4/cmd/sarah-gen -manifest sarah.yml -out ./mybot
+//
+// The output directory contains main.go, go.mod, config.yml, and Dockerfile,
+// so the bot can be built into a single static binary with "docker build ./mybot."
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	manifestPath := flag.String("manifest", "sarah.yml", "path to the manifest file.")
+	out := flag.String("out", ".", "directory to write the generated files to.")
+	force := flag.Bool("force", false, "overwrite the existing files.")
+	flag.Parse()
+
+	err := run(*manifestPath, *out, *force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sarah-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(manifestPath string, out string, force bool) error {
+	body, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	manifest, err := parseManifest(body)
+	if err != nil {
+		return err
+	}
+
+	files, err := generate(manifest)
+	if err != nil {
+		return err
+	}
+
+	return write(out, files, force)
+}
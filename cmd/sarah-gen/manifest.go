@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"sort"
+	"strings"
+)
+
+// Manifest declares the components that the generated bot consists of.
+//
+//	module: example.com/mybot
+//	adapters:
+//	  - name: slack
+//	    api: events
+//	  - name: gitter
+//	storage: memory
+//	plugins:
+//	  - selftest
+//	  - commands
+//	daemon: true
+type Manifest struct {
+	// Module is the module path of the generated main package.
+	Module string `yaml:"module"`
+
+	// SarahVersion is the version of go-sarah to require in go.mod such as v4.0.0.
+	// When this is empty, the latest version is resolved by "go mod tidy."
+	SarahVersion string `yaml:"sarah_version"`
+
+	// GoVersion is the Go version to declare in go.mod and to build the binary with in Dockerfile.
+	GoVersion string `yaml:"go_version"`
+
+	// Adapters declares the chat services to connect to. A Bot is set up for each of them.
+	Adapters []*AdapterManifest `yaml:"adapters"`
+
+	// Storage declares the sarah.UserContextStorage that the Bots share. Leave this empty to use no storage.
+	Storage string `yaml:"storage"`
+
+	// Plugins declares the names of the contrib plugins to register to every Bot.
+	Plugins []string `yaml:"plugins"`
+
+	// Daemon declares whether to run Sarah via the daemon package to integrate with systemd and Windows service.
+	Daemon bool `yaml:"daemon"`
+}
+
+// AdapterManifest declares a chat service to connect to.
+type AdapterManifest struct {
+	// Name is the name of the adapter such as slack.
+	Name string `yaml:"name"`
+
+	// API declares the way to receive messages when the adapter supports more than one.
+	// Slack supports "events" for Events API and "rtm" for RTM API. Events API is used by default.
+	API string `yaml:"api"`
+}
+
+// adapterSpec describes how to set up a Bot with the adapter.
+type adapterSpec struct {
+	importPath string
+	field      string
+	botType    string
+	apis       map[string]string
+	defaultAPI string
+	newAdapter string
+	config     string
+	envs       []string
+	port       int
+}
+
+// pluginSpec describes how to build a Command of the contrib plugin.
+type pluginSpec struct {
+	importPath string
+	admin      bool
+}
+
+var adapters = map[string]*adapterSpec{
+	"slack": {
+		importPath: "github.com/oklahomer/go-sarah/v4/slack",
+		field:      "Slack",
+		botType:    "slack.SLACK",
+		apis: map[string]string{
+			"events": "slack.WithEventsPayloadHandler(slack.DefaultEventsPayloadHandler)",
+			"rtm":    "slack.WithRTMPayloadHandler(slack.DefaultRTMPayloadHandler)",
+		},
+		defaultAPI: "events",
+		newAdapter: "slack.NewAdapter(config.Slack, %s)",
+		config: `slack:
+  token: ${SLACK_TOKEN}
+  app_secret: ${SLACK_APP_SECRET}
+  listen_port: 8080
+`,
+		envs: []string{"SLACK_TOKEN", "SLACK_APP_SECRET"},
+		port: 8080,
+	},
+	"gitter": {
+		importPath: "github.com/oklahomer/go-sarah/v4/gitter",
+		field:      "Gitter",
+		botType:    "gitter.GITTER",
+		newAdapter: "gitter.NewAdapter(config.Gitter)",
+		config: `gitter:
+  token: ${GITTER_TOKEN}
+`,
+		envs: []string{"GITTER_TOKEN"},
+	},
+}
+
+var storages = map[string]struct{}{
+	"memory": {},
+}
+
+var plugins = map[string]*pluginSpec{
+	"commands": {
+		importPath: "github.com/oklahomer/go-sarah/v4/contrib/commands",
+		admin:      true,
+	},
+	"dryrun": {
+		importPath: "github.com/oklahomer/go-sarah/v4/contrib/dryrun",
+		admin:      true,
+	},
+	"job": {
+		importPath: "github.com/oklahomer/go-sarah/v4/contrib/job",
+		admin:      true,
+	},
+	"loglevel": {
+		importPath: "github.com/oklahomer/go-sarah/v4/contrib/loglevel",
+		admin:      true,
+	},
+	"rollback": {
+		importPath: "github.com/oklahomer/go-sarah/v4/contrib/rollback",
+		admin:      true,
+	},
+	"selftest": {
+		importPath: "github.com/oklahomer/go-sarah/v4/contrib/selftest",
+		admin:      false,
+	},
+}
+
+// parseManifest parses the given YAML document and validates its content.
+func parseManifest(body []byte) (*Manifest, error) {
+	manifest := &Manifest{
+		GoVersion: "1.21",
+	}
+	err := yaml.UnmarshalStrict(body, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	err = manifest.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func (m *Manifest) validate() error {
+	var errs []error
+
+	if m.Module == "" {
+		errs = append(errs, errors.New("module is not set"))
+	}
+
+	if m.SarahVersion != "" && !strings.HasPrefix(m.SarahVersion, "v4.") {
+		errs = append(errs, fmt.Errorf("sarah_version must be a v4 version: %s", m.SarahVersion))
+	}
+
+	if len(m.Adapters) == 0 {
+		errs = append(errs, errors.New("no adapter is declared"))
+	}
+	seenAdapters := map[string]struct{}{}
+	for i, adapter := range m.Adapters {
+		spec, ok := adapters[adapter.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("adapter #%d: unsupported adapter %q: choose from %s", i, adapter.Name, names(adapters)))
+			continue
+		}
+
+		if _, ok := seenAdapters[adapter.Name]; ok {
+			errs = append(errs, fmt.Errorf("adapter #%d: %s is declared more than once", i, adapter.Name))
+		}
+		seenAdapters[adapter.Name] = struct{}{}
+
+		if adapter.API != "" {
+			if _, ok := spec.apis[adapter.API]; !ok {
+				errs = append(errs, fmt.Errorf("adapter #%d: unsupported api %q for %s", i, adapter.API, adapter.Name))
+			}
+		}
+	}
+
+	if m.Storage != "" {
+		if _, ok := storages[m.Storage]; !ok {
+			errs = append(errs, fmt.Errorf("unsupported storage %q: choose from %s", m.Storage, names(storages)))
+		}
+	}
+
+	seenPlugins := map[string]struct{}{}
+	for _, plugin := range m.Plugins {
+		if _, ok := plugins[plugin]; !ok {
+			errs = append(errs, fmt.Errorf("unsupported plugin %q: choose from %s", plugin, names(plugins)))
+			continue
+		}
+
+		if _, ok := seenPlugins[plugin]; ok {
+			errs = append(errs, fmt.Errorf("plugin %s is declared more than once", plugin))
+		}
+		seenPlugins[plugin] = struct{}{}
+	}
+
+	return errors.Join(errs...)
+}
+
+func names[T any](m map[string]T) string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
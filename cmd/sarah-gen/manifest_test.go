@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	body := `
+module: example.com/mybot
+sarah_version: v4.0.0
+adapters:
+  - name: slack
+    api: rtm
+  - name: gitter
+storage: memory
+plugins:
+  - selftest
+  - commands
+daemon: true
+`
+	manifest, err := parseManifest([]byte(body))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err)
+	}
+
+	if manifest.Module != "example.com/mybot" {
+		t.Errorf("Unexpected module is set: %s.", manifest.Module)
+	}
+
+	if manifest.SarahVersion != "v4.0.0" {
+		t.Errorf("Unexpected version is set: %s.", manifest.SarahVersion)
+	}
+
+	if manifest.GoVersion != "1.21" {
+		t.Errorf("Default Go version is not set: %s.", manifest.GoVersion)
+	}
+
+	if len(manifest.Adapters) != 2 {
+		t.Fatalf("Unexpected number of adapters: %d.", len(manifest.Adapters))
+	}
+
+	if manifest.Adapters[0].Name != "slack" || manifest.Adapters[0].API != "rtm" {
+		t.Errorf("Unexpected adapter is set: %+v.", manifest.Adapters[0])
+	}
+
+	if manifest.Storage != "memory" {
+		t.Errorf("Unexpected storage is set: %s.", manifest.Storage)
+	}
+
+	if len(manifest.Plugins) != 2 {
+		t.Errorf("Unexpected number of plugins: %d.", len(manifest.Plugins))
+	}
+
+	if !manifest.Daemon {
+		t.Error("Daemon is not enabled.")
+	}
+}
+
+func TestParseManifest_Error(t *testing.T) {
+	testSets := []struct {
+		body     string
+		contains []string
+	}{
+		{
+			body:     "adapters: [",
+			contains: []string{"failed to parse manifest"},
+		},
+		{
+			body:     "module: example.com/mybot\nunknown: true\nadapters:\n  - name: slack\n",
+			contains: []string{"failed to parse manifest"},
+		},
+		{
+			body:     "adapters:\n  - name: slack\n",
+			contains: []string{"module is not set"},
+		},
+		{
+			body:     "module: example.com/mybot\n",
+			contains: []string{"no adapter is declared"},
+		},
+		{
+			body:     "module: example.com/mybot\nsarah_version: v3.0.0\nadapters:\n  - name: slack\n",
+			contains: []string{"sarah_version must be a v4 version: v3.0.0"},
+		},
+		{
+			body:     "module: example.com/mybot\nadapters:\n  - name: mqtt\n",
+			contains: []string{`adapter #0: unsupported adapter "mqtt": choose from gitter, slack`},
+		},
+		{
+			body:     "module: example.com/mybot\nadapters:\n  - name: slack\n  - name: slack\n",
+			contains: []string{"adapter #1: slack is declared more than once"},
+		},
+		{
+			body:     "module: example.com/mybot\nadapters:\n  - name: gitter\n    api: rtm\n",
+			contains: []string{`adapter #0: unsupported api "rtm" for gitter`},
+		},
+		{
+			body:     "module: example.com/mybot\nadapters:\n  - name: slack\nstorage: redis\n",
+			contains: []string{`unsupported storage "redis": choose from memory`},
+		},
+		{
+			body: "module: example.com/mybot\nadapters:\n  - name: slack\nplugins: [unknown, selftest, selftest]\n",
+			contains: []string{
+				`unsupported plugin "unknown"`,
+				"plugin selftest is declared more than once",
+			},
+		},
+	}
+
+	for i, testSet := range testSets {
+		_, err := parseManifest([]byte(testSet.body))
+		if err == nil {
+			t.Errorf("Expected error is not returned on test #%d.", i)
+			continue
+		}
+
+		for _, str := range testSet.contains {
+			if !strings.Contains(err.Error(), str) {
+				t.Errorf("Expected error message is not included on test #%d: %s.", i, err.Error())
+			}
+		}
+	}
+}
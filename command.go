@@ -7,6 +7,7 @@ import (
 	"github.com/oklahomer/go-kasumi/logger"
 	"reflect"
 	"regexp"
+	"regexp/syntax"
 	"slices"
 	"strings"
 	"sync"
@@ -49,6 +50,55 @@ type Command interface {
 	Match(Input) bool
 }
 
+// wrappedCommand is implemented by the Commands that wrap another Command to provide additional features around its execution.
+// The optional interfaces such as PrefixedCommand and MutatingCommand are resolved through Unwrap,
+// so the wrapping does not hide the wrapped Command's capabilities.
+type wrappedCommand interface {
+	Command
+
+	// Unwrap returns the wrapped Command.
+	Unwrap() Command
+}
+
+// contextPreparer is implemented by the wrappedCommand that provides values to the wrapped Command via context.
+type contextPreparer interface {
+	prepareContext(context.Context) context.Context
+}
+
+// resolveCommand walks through the chain of wrapped Commands from the given one and returns the first Command that satisfies T.
+func resolveCommand[T Command](command Command) (T, bool) {
+	for command != nil {
+		if found, ok := command.(T); ok {
+			return found, true
+		}
+
+		wrapped, ok := command.(wrappedCommand)
+		if !ok {
+			break
+		}
+		command = wrapped.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
+// prepareCommandContext returns a context that is prepared by all wrappedCommands in the chain from the given Command.
+func prepareCommandContext(ctx context.Context, command Command) context.Context {
+	for command != nil {
+		if preparer, ok := command.(contextPreparer); ok {
+			ctx = preparer.prepareContext(ctx)
+		}
+
+		wrapped, ok := command.(wrappedCommand)
+		if !ok {
+			break
+		}
+		command = wrapped.Unwrap()
+	}
+	return ctx
+}
+
 type commandConfigWrapper struct {
 	value CommandConfig
 	mutex *sync.RWMutex
@@ -57,11 +107,24 @@ type commandConfigWrapper struct {
 type defaultCommand struct {
 	identifier      string
 	matchFunc       func(Input) bool
+	matchPrefix     string
 	instructionFunc func(*HelpInput) string
 	commandFunc     commandFunc
 	configWrapper   *commandConfigWrapper
+	aliases         []string
+	aliasTarget     string
+	deprecation     string
+	mutating        bool
+	previewFunc     func(context.Context, Input) (*CommandResponse, error)
+	acceptBotInput  bool
+	circuitBreaker  *CircuitBreakerConfig
 }
 
+var _ PrefixedCommand = (*defaultCommand)(nil)
+var _ MutatingCommand = (*defaultCommand)(nil)
+var _ BotInputAcceptor = (*defaultCommand)(nil)
+var _ circuitBreakingCommand = (*defaultCommand)(nil)
+
 func (command *defaultCommand) Identifier() string {
 	return command.identifier
 }
@@ -71,10 +134,43 @@ func (command *defaultCommand) Instruction(input *HelpInput) string {
 }
 
 func (command *defaultCommand) Match(input Input) bool {
-	return command.matchFunc(input)
+	return command.matchFunc(input) || command.matchedAlias(input) != ""
+}
+
+func (command *defaultCommand) MatchPrefix() string {
+	return command.matchPrefix
+}
+
+func (command *defaultCommand) AcceptsBotInput() bool {
+	return command.acceptBotInput
+}
+
+func (command *defaultCommand) Mutating() bool {
+	return command.mutating
+}
+
+func (command *defaultCommand) circuitBreakerConfig() *CircuitBreakerConfig {
+	return command.circuitBreaker
+}
+
+func (command *defaultCommand) Preview(ctx context.Context, input Input) (*CommandResponse, error) {
+	if command.previewFunc == nil {
+		return defaultPreview(command.identifier, input), nil
+	}
+	return command.previewFunc(ctx, input)
 }
 
 func (command *defaultCommand) Execute(ctx context.Context, input Input) (*CommandResponse, error) {
+	if len(command.aliases) > 0 && !command.matchFunc(input) {
+		if alias := command.matchedAlias(input); alias != "" {
+			return command.executeAlias(ctx, input, alias)
+		}
+	}
+
+	return command.execute(ctx, input)
+}
+
+func (command *defaultCommand) execute(ctx context.Context, input Input) (*CommandResponse, error) {
 	wrapper := command.configWrapper
 	if wrapper == nil {
 		return command.commandFunc(ctx, input)
@@ -92,9 +188,17 @@ func buildCommand(ctx context.Context, props *CommandProps, watcher ConfigWatche
 		return &defaultCommand{
 			identifier:      props.identifier,
 			matchFunc:       props.matchFunc,
+			matchPrefix:     aliasedPrefix(props.matchPrefix, props.aliases),
 			instructionFunc: props.instructionFunc,
 			commandFunc:     props.commandFunc,
 			configWrapper:   nil,
+			aliases:         props.aliases,
+			aliasTarget:     props.aliasTarget,
+			deprecation:     props.deprecation,
+			mutating:        props.mutating,
+			previewFunc:     props.previewFunc,
+			acceptBotInput:  props.acceptBotInput,
+			circuitBreaker:  props.circuitBreaker,
 		}, nil
 	}
 
@@ -137,12 +241,20 @@ func buildCommand(ctx context.Context, props *CommandProps, watcher ConfigWatche
 	return &defaultCommand{
 		identifier:      props.identifier,
 		matchFunc:       props.matchFunc,
+		matchPrefix:     aliasedPrefix(props.matchPrefix, props.aliases),
 		instructionFunc: props.instructionFunc,
 		commandFunc:     props.commandFunc,
 		configWrapper: &commandConfigWrapper{
 			value: cfg,
 			mutex: locker,
 		},
+		aliases:        props.aliases,
+		aliasTarget:    props.aliasTarget,
+		deprecation:    props.deprecation,
+		mutating:       props.mutating,
+		previewFunc:    props.previewFunc,
+		acceptBotInput: props.acceptBotInput,
+		circuitBreaker: props.circuitBreaker,
 	}, nil
 }
 
@@ -157,6 +269,7 @@ func StripMessage(pattern *regexp.Regexp, input string) string {
 // A Bot implementation can refer to this to register a given command on Bot.AppendCommand call, and to find a matching Command on Bot.Respond call.
 type Commands struct {
 	collection []Command
+	index      *commandIndex
 	mutex      sync.RWMutex
 }
 
@@ -183,12 +296,14 @@ func (commands *Commands) Append(command Command) {
 		// Does NOT exist, then append to the last.
 		logger.Infof("Append new command: %s.", command.Identifier())
 		commands.collection = append(commands.collection, command)
+		commands.index = newCommandIndex(commands.collection)
 		return
 	}
 
 	// Replace the existing same command with the new one
 	logger.Infof("Replace old command in favor of newly appending one: %s.", command.Identifier())
 	commands.collection[i] = command
+	commands.index = newCommandIndex(commands.collection)
 }
 
 // FindFirstMatched looks for the first matching command by calling each Command's Command.Match method:
@@ -196,12 +311,37 @@ func (commands *Commands) Append(command Command) {
 //
 // The check for each Command is run in the order of registration; The earlier the Commands.Append is called, the earlier the check.
 // Be sure to register an important Command first.
+//
+// Commands that satisfy PrefixedCommand are indexed by their prefixes, so those with non-matching prefixes are skipped without calling Command.Match.
+// This does not change the above order.
+//
+// When the Input is sent by another bot as BotAuthoredInput tells, only the Commands that accept bot Inputs via BotInputAcceptor are checked.
 func (commands *Commands) FindFirstMatched(input Input) Command {
 	commands.mutex.RLock()
 	defer commands.mutex.RUnlock()
 
+	// Inputs from other bots are only passed to the Commands that explicitly accept them to avoid an infinite loop.
+	fromBot := isBotAuthored(input)
+
+	if commands.index != nil {
+		var buf [32]int
+		for _, i := range commands.index.candidates(input.Message(), buf[:0]) {
+			command := commands.collection[i]
+			if fromBot && !acceptsBotInput(command) {
+				continue
+			}
+			if command.Match(input) {
+				return command
+			}
+		}
+		return nil
+	}
+
 	// See if a matching command exists
 	i := slices.IndexFunc(commands.collection, func(command Command) bool {
+		if fromBot && !acceptsBotInput(command) {
+			return false
+		}
 		return command.Match(input)
 	})
 
@@ -221,6 +361,12 @@ func (commands *Commands) ExecuteFirstMatched(ctx context.Context, input Input)
 		return nil, nil
 	}
 
+	ctx = withLogField(ctx, "Command", command.Identifier())
+	res, previewed, err := previewIfDryRun(ctx, command, input)
+	if previewed {
+		return res, err
+	}
+
 	return command.Execute(ctx, input)
 }
 
@@ -229,20 +375,22 @@ func (commands *Commands) Helps(input *HelpInput) *CommandHelps {
 	commands.mutex.RLock()
 	defer commands.mutex.RUnlock()
 
-	helps := &CommandHelps{}
+	// Allocate the CommandHelp values at once instead of one by one.
+	values := make([]CommandHelp, 0, len(commands.collection))
+	helps := make(CommandHelps, 0, len(commands.collection))
 	for _, command := range commands.collection {
 		instruction := command.Instruction(input)
 		if instruction == "" {
 			continue
 		}
 
-		h := &CommandHelp{
+		values = append(values, CommandHelp{
 			Identifier:  command.Identifier(),
 			Instruction: instruction,
-		}
-		*helps = append(*helps, h)
+		})
+		helps = append(helps, &values[len(values)-1])
 	}
-	return helps
+	return &helps
 }
 
 // CommandHelps is an alias to a slice of CommandHelp pointers.
@@ -275,11 +423,20 @@ func NewCommandPropsBuilder() *CommandPropsBuilder {
 // This holds a relatively complex set of Command construction arguments and properties.
 type CommandProps struct {
 	botType         BotType
+	botTypes        []BotType
 	identifier      string
 	config          CommandConfig
 	commandFunc     commandFunc
 	matchFunc       func(Input) bool
+	matchPrefix     string
+	aliasTarget     string
 	instructionFunc func(*HelpInput) string
+	aliases         []string
+	deprecation     string
+	mutating        bool
+	previewFunc     func(context.Context, Input) (*CommandResponse, error)
+	acceptBotInput  bool
+	circuitBreaker  *CircuitBreakerConfig
 }
 
 // CommandPropsBuilder helps to construct a CommandProps.
@@ -290,8 +447,29 @@ type CommandPropsBuilder struct {
 }
 
 // BotType is a setter to provide the belonging BotType.
+// Pass AnyBotType to share the Command with all registered Bots.
 func (builder *CommandPropsBuilder) BotType(botType BotType) *CommandPropsBuilder {
 	builder.props.botType = botType
+	builder.props.botTypes = nil
+	return builder
+}
+
+// BotTypes is a setter to provide multiple belonging BotTypes, so one registration serves a multi-platform bot.
+// A Command is built for each BotType and reads its configuration for each BotType.
+// Let the Command return an adapter-neutral content such as string or *RichContent so each Adapter can render it in its own format.
+//
+//	props := sarah.NewCommandPropsBuilder().
+//		BotTypes(slack.SLACK, gitter.GITTER).
+//		Identifier("echo").
+//		...
+//		MustBuild()
+func (builder *CommandPropsBuilder) BotTypes(botTypes ...BotType) *CommandPropsBuilder {
+	builder.props.botType = ""
+	builder.props.botTypes = nil
+	if len(botTypes) > 0 {
+		builder.props.botType = botTypes[0]
+		builder.props.botTypes = botTypes
+	}
 	return builder
 }
 
@@ -304,14 +482,46 @@ func (builder *CommandPropsBuilder) Identifier(id string) *CommandPropsBuilder {
 // MatchPattern is a setter to provide a command match pattern.
 // This regular expression is used against the given Input to see if the Command matches the Input.
 //
-// Use MatchFunc to set a more customizable matcher logic.
+// Use Match for a simple prefix or keyword matching, and MatchFunc to set a more customizable matcher logic.
 func (builder *CommandPropsBuilder) MatchPattern(pattern *regexp.Regexp) *CommandPropsBuilder {
+	// Every Input is checked against all Commands' patterns, so skip the regular expression evaluation
+	// with a simple prefix comparison when the pattern requires the message to start with a literal string such as ".echo".
+	prefix := requiredPrefix(pattern)
+	builder.props.matchPrefix = prefix
+	builder.props.aliasTarget = prefix
 	builder.props.matchFunc = func(input Input) bool {
-		return pattern.MatchString(input.Message())
+		message := input.Message()
+		if !strings.HasPrefix(message, prefix) {
+			return false
+		}
+		return pattern.MatchString(message)
 	}
 	return builder
 }
 
+// requiredPrefix returns the literal string that the given pattern requires the message to start with.
+// e.g. `^\.echo\b` returns ".echo". This returns an empty string when no such string is required.
+func requiredPrefix(pattern *regexp.Regexp) string {
+	re, err := syntax.Parse(pattern.String(), syntax.Perl)
+	if err != nil {
+		return ""
+	}
+
+	re = re.Simplify()
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 || re.Sub[0].Op != syntax.OpBeginText {
+		return ""
+	}
+
+	b := strings.Builder{}
+	for _, sub := range re.Sub[1:] {
+		if sub.Op != syntax.OpLiteral || sub.Flags&syntax.FoldCase != 0 {
+			break
+		}
+		b.WriteString(string(sub.Rune))
+	}
+	return b.String()
+}
+
 // MatchFunc is a setter for a function to judge if an incoming Input "matches" the Command.
 // When this returns true, this command is considered to "match the user input" and becomes a Command execution candidate.
 //
@@ -320,6 +530,66 @@ func (builder *CommandPropsBuilder) MatchPattern(pattern *regexp.Regexp) *Comman
 // e.g. only return true on a specific sender's specific message on a specific time range.
 func (builder *CommandPropsBuilder) MatchFunc(matchFunc func(Input) bool) *CommandPropsBuilder {
 	builder.props.matchFunc = matchFunc
+	builder.props.matchPrefix = ""
+	builder.props.aliasTarget = ""
+	return builder
+}
+
+// Match is a setter to provide a Matcher built by MatchPrefix, MatchKeywords, or their case-insensitive variants.
+// Prefer this to a regular expression or a MatchFunc for a simple command such as ".echo" so every Command judges the prefix in the same manner.
+//
+//	sarah.NewCommandPropsBuilder().
+//		Match(sarah.MatchPrefixFold(".echo"))
+func (builder *CommandPropsBuilder) Match(matcher *Matcher) *CommandPropsBuilder {
+	builder.props.matchFunc = matcher.Match
+	builder.props.matchPrefix = matcher.prefix
+	builder.props.aliasTarget = matcher.name
+	return builder
+}
+
+// Alias is a setter to provide an old name of a renamed Command such as ".old," so the users can keep using the old name.
+// An Input that starts with the alias followed by a space or the end of the message also matches the Command.
+// On execution, the alias in Input.Message is replaced with the literal prefix of MatchPattern such as ".new" if available,
+// and a deprecation notice is appended to the response. Use Deprecated to customize the notice.
+// Note that the Input is wrapped to replace the message in this case, so a type assertion to the Adapter's Input type fails.
+// This can be called multiple times to register multiple aliases.
+//
+//	sarah.NewCommandPropsBuilder().
+//		MatchPattern(regexp.MustCompile(`^\.weather\b`)).
+//		Alias(".forecast").
+//		Deprecated(".forecast is renamed. Use .weather instead.")
+func (builder *CommandPropsBuilder) Alias(alias string) *CommandPropsBuilder {
+	builder.props.aliases = append(builder.props.aliases, alias)
+	return builder
+}
+
+// Deprecated is a setter to provide a notice that is appended to the response when the Command is executed via an alias given to Alias.
+// When this is not set, a default notice that tells the new name is used.
+func (builder *CommandPropsBuilder) Deprecated(notice string) *CommandPropsBuilder {
+	builder.props.deprecation = notice
+	return builder
+}
+
+// Mutating marks the Command as one that modifies external state, e.g. deploys an application or restarts a server.
+// While the dry-run mode is enabled via SetDryRun or SetCommandDryRun, a mutating Command is not executed but returns a preview response.
+// Use Preview to customize the preview response.
+func (builder *CommandPropsBuilder) Mutating() *CommandPropsBuilder {
+	builder.props.mutating = true
+	return builder
+}
+
+// Preview is a setter to provide a function that returns a preview response of a mutating Command in the dry-run mode.
+// The function should describe what the Command would do with the given Input without actually modifying anything.
+// When this is not set, a default response that tells the Command is not executed is returned.
+func (builder *CommandPropsBuilder) Preview(fn func(context.Context, Input) (*CommandResponse, error)) *CommandPropsBuilder {
+	builder.props.previewFunc = fn
+	return builder
+}
+
+// AcceptBotInput lets the Command handle the Inputs sent by other bots, which are ignored by default to avoid an infinite loop between bots.
+// Use this for an intentional bot-to-bot workflow; see BotAuthoredInput for details.
+func (builder *CommandPropsBuilder) AcceptBotInput() *CommandPropsBuilder {
+	builder.props.acceptBotInput = true
 	return builder
 }
 
@@ -354,6 +624,15 @@ func (builder *CommandPropsBuilder) Instruction(instruction string) *CommandProp
 	return builder
 }
 
+// Args is a setter to provide an instruction generated from the given ArgsSpec.
+// The instruction is decorated for each chat service as described in ArgsSpec.Instruction.
+func (builder *CommandPropsBuilder) Args(spec *ArgsSpec) *CommandPropsBuilder {
+	builder.props.instructionFunc = func(input *HelpInput) string {
+		return spec.Instruction(input)
+	}
+	return builder
+}
+
 // InstructionFunc is a setter to provide a function that receives a user input and returns an instruction.
 // Use Instruction() when a simple text instruction can always be returned.
 // If the instruction has to be customized per user or the instruction has to be hidden in a certain group or from a certain user,
@@ -391,3 +670,72 @@ func (builder *CommandPropsBuilder) MustBuild() *CommandProps {
 
 	return props
 }
+
+// targetBotTypes returns the BotTypes that the CommandProps belongs to.
+// This returns only AnyBotType when the CommandProps is shared with all Bots.
+func (props *CommandProps) targetBotTypes() []BotType {
+	if len(props.botTypes) == 0 {
+		return []BotType{props.botType}
+	}
+
+	var botTypes []BotType
+	seen := map[BotType]struct{}{}
+	for _, botType := range props.botTypes {
+		if botType == AnyBotType {
+			return []BotType{AnyBotType}
+		}
+		if _, ok := seen[botType]; ok {
+			continue
+		}
+		seen[botType] = struct{}{}
+		botTypes = append(botTypes, botType)
+	}
+	return botTypes
+}
+
+// forBotType returns the CommandProps that belongs only to the given BotType.
+// When the CommandProps belongs to multiple BotTypes, a copy with its own configuration instance is returned
+// so the Bots do not overwrite each other's configuration.
+func (props *CommandProps) forBotType(botType BotType) *CommandProps {
+	if len(props.botTypes) == 0 && props.botType == botType {
+		return props
+	}
+
+	copied := *props
+	copied.botType = botType
+	copied.botTypes = nil
+	if props.config != nil {
+		copied.config = copyConfig(props.config)
+	}
+	return &copied
+}
+
+// copyConfig returns a shallow copy of the given configuration that is a pointer or a map.
+// Other values are returned as-is because buildCommand copies them on read.
+func copyConfig(cfg CommandConfig) CommandConfig {
+	rv := reflect.ValueOf(cfg)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return cfg
+		}
+		n := reflect.New(rv.Elem().Type())
+		n.Elem().Set(rv.Elem())
+		return n.Interface()
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return cfg
+		}
+		n := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			n.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return n.Interface()
+
+	default:
+		return cfg
+
+	}
+}
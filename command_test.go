@@ -37,6 +37,44 @@ func (command *DummyCommand) Match(input Input) bool {
 	return command.MatchFunc(input)
 }
 
+func Test_resolveCommand(t *testing.T) {
+	prefixed := newDummyPrefixedCommand("echo", ".echo")
+	wrapped := &observedCommand{Command: &kvCommand{Command: prefixed}}
+
+	found, ok := resolveCommand[PrefixedCommand](wrapped)
+	if !ok {
+		t.Fatal("Wrapped PrefixedCommand is not resolved.")
+	}
+	if found != prefixed {
+		t.Errorf("Unexpected command is returned: %#v.", found)
+	}
+
+	_, ok = resolveCommand[MutatingCommand](wrapped)
+	if ok {
+		t.Error("Unimplemented interface should not be resolved.")
+	}
+
+	_, ok = resolveCommand[PrefixedCommand](&DummyCommand{})
+	if ok {
+		t.Error("Unimplemented interface should not be resolved.")
+	}
+}
+
+func Test_prepareCommandContext(t *testing.T) {
+	bucket := NewKVBucket(NewInMemoryKVStore(), "dummy", "command")
+	wrapped := &circuitBreakerCommand{Command: &kvCommand{Command: &DummyCommand{}, bucket: bucket}}
+
+	ctx := prepareCommandContext(context.TODO(), wrapped)
+	if KVBucketFromContext(ctx) != bucket {
+		t.Error("KVBucket is not provided by the wrapped Command.")
+	}
+
+	ctx = prepareCommandContext(context.TODO(), &DummyCommand{})
+	if KVBucketFromContext(ctx) != nil {
+		t.Error("Unexpected KVBucket is provided.")
+	}
+}
+
 func TestNewCommandPropsBuilder(t *testing.T) {
 	builder := NewCommandPropsBuilder()
 	if builder == nil {
@@ -77,6 +115,96 @@ func TestCommandPropsBuilder_BotType(t *testing.T) {
 	}
 }
 
+func TestCommandPropsBuilder_BotTypes(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+
+	builder.BotTypes("slack", "gitter")
+	if builder.props.botType != "slack" || len(builder.props.botTypes) != 2 {
+		t.Errorf("Provided BotTypes were not set: %#v.", builder.props)
+	}
+
+	builder.BotType("dummy")
+	if builder.props.botType != "dummy" || builder.props.botTypes != nil {
+		t.Errorf("BotTypes should be overridden by BotType: %#v.", builder.props)
+	}
+
+	builder.BotTypes()
+	if builder.props.botType != "" {
+		t.Errorf("Empty BotTypes should fail the build: %#v.", builder.props)
+	}
+}
+
+func TestCommandProps_targetBotTypes(t *testing.T) {
+	testSets := []struct {
+		props    *CommandProps
+		expected []BotType
+	}{
+		{
+			props:    &CommandProps{botType: "dummy"},
+			expected: []BotType{"dummy"},
+		},
+		{
+			props:    &CommandProps{botType: "slack", botTypes: []BotType{"slack", "gitter", "slack"}},
+			expected: []BotType{"slack", "gitter"},
+		},
+		{
+			props:    &CommandProps{botType: "slack", botTypes: []BotType{"slack", AnyBotType}},
+			expected: []BotType{AnyBotType},
+		},
+	}
+
+	for i, tt := range testSets {
+		botTypes := tt.props.targetBotTypes()
+		if !reflect.DeepEqual(botTypes, tt.expected) {
+			t.Errorf("Unexpected BotTypes are returned on test #%d: %#v.", i+1, botTypes)
+		}
+	}
+}
+
+func TestCommandProps_forBotType(t *testing.T) {
+	props := &CommandProps{botType: "dummy"}
+	if props.forBotType("dummy") != props {
+		t.Error("CommandProps with a single BotType should be returned as-is.")
+	}
+
+	type config struct {
+		Token string
+	}
+	original := &config{Token: "foo"}
+	props = &CommandProps{
+		botType:  "slack",
+		botTypes: []BotType{"slack", "gitter"},
+		config:   original,
+	}
+
+	copied := props.forBotType("gitter")
+	if copied == props || copied.botType != "gitter" || copied.botTypes != nil {
+		t.Fatalf("Unexpected CommandProps is returned: %#v.", copied)
+	}
+	cfg, ok := copied.config.(*config)
+	if !ok || cfg == original || cfg.Token != "foo" {
+		t.Errorf("Configuration is not copied: %#v.", copied.config)
+	}
+}
+
+func Test_copyConfig(t *testing.T) {
+	m := map[string]string{"foo": "bar"}
+	copied := copyConfig(m).(map[string]string)
+	copied["foo"] = "baz"
+	if m["foo"] != "bar" {
+		t.Error("Map is not copied.")
+	}
+
+	var nilConfig *struct{}
+	if copyConfig(nilConfig) != CommandConfig(nilConfig) {
+		t.Error("Nil pointer should be returned as-is.")
+	}
+
+	if copyConfig(1) != 1 {
+		t.Error("Value should be returned as-is.")
+	}
+}
+
 func TestCommandPropsBuilder_Func(t *testing.T) {
 	wrappedFncCalled := false
 	builder := &CommandPropsBuilder{props: &CommandProps{}}
@@ -134,6 +262,55 @@ func TestCommandPropsBuilder_MatchPattern(t *testing.T) {
 	}
 }
 
+func TestCommandPropsBuilder_MatchPattern_Prefix(t *testing.T) {
+	testSets := []struct {
+		pattern  string
+		message  string
+		expected bool
+	}{
+		{pattern: `^\.echo\b`, message: ".echo foo", expected: true},
+		{pattern: `^\.echo\b`, message: ".echoes", expected: false},
+		{pattern: `^\.echo\b`, message: "say .echo", expected: false},
+		{pattern: `(?i)^\.ECHO`, message: ".echo", expected: true},
+		{pattern: `^\.(echo|say)`, message: ".say", expected: true},
+		{pattern: `^\.a|b`, message: "b", expected: true},
+		{pattern: `echo`, message: "say echo", expected: true},
+		{pattern: `(?m)^echo`, message: "say\necho", expected: true},
+	}
+
+	for i, tt := range testSets {
+		builder := &CommandPropsBuilder{props: &CommandProps{}}
+		builder.MatchPattern(regexp.MustCompile(tt.pattern))
+
+		if builder.props.matchFunc(&DummyInput{MessageValue: tt.message}) != tt.expected {
+			t.Errorf("Unexpected result on test #%d: %s against %s.", i+1, tt.pattern, tt.message)
+		}
+	}
+}
+
+func Test_requiredPrefix(t *testing.T) {
+	testSets := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: `^\.echo\b`, expected: ".echo"},
+		{pattern: `^\.echo\s+(.+)`, expected: ".echo"},
+		{pattern: `^\.ab*`, expected: ".a"},
+		{pattern: `(?i)^\.echo`, expected: ""},
+		{pattern: `^\.(echo|say)`, expected: "."},
+		{pattern: `^\.a|b`, expected: ""},
+		{pattern: `\.echo`, expected: ""},
+		{pattern: `(?m)^\.echo`, expected: ""},
+	}
+
+	for i, tt := range testSets {
+		prefix := requiredPrefix(regexp.MustCompile(tt.pattern))
+		if prefix != tt.expected {
+			t.Errorf("Unexpected prefix is returned on test #%d: %s.", i+1, prefix)
+		}
+	}
+}
+
 func TestCommandPropsBuilder_MatchFunc(t *testing.T) {
 	builder := &CommandPropsBuilder{props: &CommandProps{}}
 	builder.MatchFunc(func(input Input) bool {
@@ -145,6 +322,39 @@ func TestCommandPropsBuilder_MatchFunc(t *testing.T) {
 	}
 }
 
+func TestCommandPropsBuilder_Match(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+	builder.Match(MatchPrefixFold(".echo"))
+
+	if !builder.props.matchFunc(&DummyInput{MessageValue: ".ECHO"}) {
+		t.Error("Expected true to return, but did not.")
+	}
+	if builder.props.matchPrefix != "." {
+		t.Errorf("Unexpected prefix is set: %s.", builder.props.matchPrefix)
+	}
+	if builder.props.aliasTarget != ".echo" {
+		t.Errorf("Unexpected alias target is set: %s.", builder.props.aliasTarget)
+	}
+
+	// MatchFunc resets the prefix.
+	builder.MatchFunc(func(_ Input) bool {
+		return true
+	})
+	if builder.props.matchPrefix != "" || builder.props.aliasTarget != "" {
+		t.Errorf("Prefix is not reset: %#v.", builder.props)
+	}
+}
+
+func TestCommandPropsBuilder_Args(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+	builder.Args(&ArgsSpec{Command: ".ping"})
+
+	instruction := builder.props.instructionFunc(&HelpInput{OriginalInput: &DummyInput{}})
+	if instruction != "Usage: .ping" {
+		t.Errorf("Unexpected instruction is returned: %s.", instruction)
+	}
+}
+
 func TestCommandPropsBuilder_Build(t *testing.T) {
 	builder := &CommandPropsBuilder{props: &CommandProps{}}
 	if _, err := builder.Build(); err == nil {
@@ -646,3 +856,66 @@ func Test_buildCommand(t *testing.T) {
 		})
 	}
 }
+
+// benchmarkCommands builds Commands with the given number of pattern-based Commands whose names are .command0, .command1, and so on.
+func benchmarkCommands(b *testing.B, n int) *Commands {
+	commands := NewCommands()
+	for i := 0; i < n; i++ {
+		props, err := NewCommandPropsBuilder().
+			BotType("dummy").
+			Identifier(fmt.Sprintf("command%d", i)).
+			Instruction("dummy").
+			MatchPattern(regexp.MustCompile(fmt.Sprintf(`^\.command%d\b`, i))).
+			Func(func(_ context.Context, _ Input) (*CommandResponse, error) {
+				return nil, nil
+			}).
+			Build()
+		if err != nil {
+			b.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		command, err := buildCommand(context.TODO(), props, &DummyConfigWatcher{})
+		if err != nil {
+			b.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		commands.Append(command)
+	}
+	return commands
+}
+
+func BenchmarkCommands_FindFirstMatched(b *testing.B) {
+	commands := benchmarkCommands(b, 50)
+	input := &DummyInput{MessageValue: ".command49 foo"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if commands.FindFirstMatched(input) == nil {
+			b.Fatal("Command is not found.")
+		}
+	}
+}
+
+func BenchmarkCommands_FindFirstMatched_NotFound(b *testing.B) {
+	commands := benchmarkCommands(b, 50)
+	input := &DummyInput{MessageValue: "Hello, world."}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if commands.FindFirstMatched(input) != nil {
+			b.Fatal("Unexpected command is found.")
+		}
+	}
+}
+
+func BenchmarkCommands_Helps(b *testing.B) {
+	commands := benchmarkCommands(b, 50)
+	input := &HelpInput{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		commands.Helps(input)
+	}
+}
@@ -0,0 +1,168 @@
+package sarah
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"sync"
+)
+
+// ErrConfigSnapshotNotFound is returned by RollbackConfig when no prior configuration snapshot is kept for the given plugin.
+var ErrConfigSnapshotNotFound = errors.New("no prior configuration snapshot is found")
+
+// ErrConfigHistoryDisabled is returned by RollbackConfig when Config.ConfigHistory is zero.
+var ErrConfigHistoryDisabled = errors.New("configuration history is disabled")
+
+// RollbackConfig re-applies the previous configuration snapshot of the Command or ScheduledTask with the given BotType and identifier.
+// Use this when a bad configuration is pushed; the corresponding Command or ScheduledTask is rebuilt with the prior configuration right away.
+// Calling this repeatedly goes back further in the history that Config.ConfigHistory limits.
+//
+// The snapshots are kept in memory regardless of the registered ConfigWatcher, so this works with any configuration source.
+// The rolled-back configuration stays in effect until the ConfigWatcher detects the next change.
+func RollbackConfig(botType BotType, id string) error {
+	return defaultRunner().RollbackConfig(botType, id)
+}
+
+// RollbackConfig is the Runner counterpart of the package-level RollbackConfig.
+func (rn *Runner) RollbackConfig(botType BotType, id string) error {
+	r := rn.running.get()
+	if r == nil {
+		return ErrRunnerNotRunning
+	}
+	if r.configHistory == nil {
+		return ErrConfigHistoryDisabled
+	}
+
+	return r.configHistory.rollback(botType, id)
+}
+
+type configKey struct {
+	botType BotType
+	id      string
+}
+
+// configHistory is a ConfigWatcher that wraps the registered one to keep the snapshots of the configurations it reads.
+// A snapshot is serialized with encoding/json, so only the exported fields of a configuration struct are kept.
+type configHistory struct {
+	watcher   ConfigWatcher
+	size      int
+	snapshots map[configKey][][]byte
+	pinned    map[configKey][]byte
+	callbacks map[configKey]func()
+	mutex     sync.Mutex
+}
+
+var _ ConfigWatcher = (*configHistory)(nil)
+
+func newConfigHistory(watcher ConfigWatcher, size int) *configHistory {
+	return &configHistory{
+		watcher:   watcher,
+		size:      size,
+		snapshots: make(map[configKey][][]byte),
+		pinned:    make(map[configKey][]byte),
+		callbacks: make(map[configKey]func()),
+	}
+}
+
+func (h *configHistory) Read(botCtx context.Context, botType BotType, id string, configPtr interface{}) error {
+	key := configKey{botType: botType, id: id}
+
+	h.mutex.Lock()
+	pinned, ok := h.pinned[key]
+	h.mutex.Unlock()
+	if ok {
+		err := json.Unmarshal(pinned, configPtr)
+		if err != nil {
+			return fmt.Errorf("failed to apply configuration snapshot for %s:%s: %w", botType, id, err)
+		}
+		return nil
+	}
+
+	err := h.watcher.Read(botCtx, botType, id, configPtr)
+	if err != nil {
+		return err
+	}
+	h.record(key, configPtr)
+	return nil
+}
+
+func (h *configHistory) record(key configKey, configPtr interface{}) {
+	snapshot, err := json.Marshal(configPtr)
+	if err != nil {
+		logger.Debugf("Failed to take a snapshot of the configuration for %s:%s: %+v", key.botType, key.id, err)
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	snapshots := h.snapshots[key]
+	if len(snapshots) > 0 && bytes.Equal(snapshots[len(snapshots)-1], snapshot) {
+		// Not changed.
+		return
+	}
+
+	snapshots = append(snapshots, snapshot)
+	if len(snapshots) > h.size {
+		snapshots = snapshots[len(snapshots)-h.size:]
+	}
+	h.snapshots[key] = snapshots
+}
+
+func (h *configHistory) Watch(botCtx context.Context, botType BotType, id string, callback func()) error {
+	key := configKey{botType: botType, id: id}
+
+	h.mutex.Lock()
+	h.callbacks[key] = callback
+	h.mutex.Unlock()
+
+	return h.watcher.Watch(botCtx, botType, id, func() {
+		// A new configuration is pushed, so stop applying the rolled-back one.
+		h.mutex.Lock()
+		delete(h.pinned, key)
+		h.mutex.Unlock()
+
+		callback()
+	})
+}
+
+func (h *configHistory) Unwatch(botType BotType) error {
+	h.mutex.Lock()
+	for key := range h.callbacks {
+		if key.botType == botType {
+			delete(h.callbacks, key)
+		}
+	}
+	h.mutex.Unlock()
+
+	return h.watcher.Unwatch(botType)
+}
+
+func (h *configHistory) rollback(botType BotType, id string) error {
+	key := configKey{botType: botType, id: id}
+
+	h.mutex.Lock()
+	snapshots := h.snapshots[key]
+	if len(snapshots) < 2 {
+		h.mutex.Unlock()
+		return ErrConfigSnapshotNotFound
+	}
+
+	// Discard the current snapshot and pin the previous one so the next Read applies it.
+	snapshots = snapshots[:len(snapshots)-1]
+	h.snapshots[key] = snapshots
+	h.pinned[key] = snapshots[len(snapshots)-1]
+	callback, ok := h.callbacks[key]
+	h.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("configuration for %s:%s is not subscribed, so the snapshot is applied on the next build", botType, id)
+	}
+
+	logger.Infof("Rolling back the configuration for %s:%s", botType, id)
+	callback()
+	return nil
+}
@@ -0,0 +1,130 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type historyConfig struct {
+	Token string
+}
+
+func TestRollbackConfig(t *testing.T) {
+	SetupAndRun(func() {
+		err := RollbackConfig("dummy", "id")
+		if !errors.Is(err, ErrRunnerNotRunning) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+
+		running.set(&runner{})
+		err = RollbackConfig("dummy", "id")
+		if !errors.Is(err, ErrConfigHistoryDisabled) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func Test_configHistory(t *testing.T) {
+	var botType BotType = "dummy"
+	id := "id"
+	token := "first"
+	var notify func()
+	watcher := &DummyConfigWatcher{
+		ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+			configPtr.(*historyConfig).Token = token
+			return nil
+		},
+		WatchFunc: func(_ context.Context, _ BotType, _ string, callback func()) error {
+			notify = callback
+			return nil
+		},
+	}
+	h := newConfigHistory(watcher, 2)
+
+	config := &historyConfig{}
+	rebuild := func() {
+		err := h.Read(context.TODO(), botType, id, config)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+	}
+	err := h.Watch(context.TODO(), botType, id, rebuild)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	rebuild()
+	err = h.rollback(botType, id)
+	if !errors.Is(err, ErrConfigSnapshotNotFound) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	// Bad configurations are pushed.
+	for _, pushed := range []string{"second", "bad"} {
+		token = pushed
+		notify()
+	}
+	if len(h.snapshots[configKey{botType: botType, id: id}]) != 2 {
+		t.Errorf("Snapshots should be limited to the given size: %d.", len(h.snapshots[configKey{botType: botType, id: id}]))
+	}
+
+	err = h.rollback(botType, id)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if config.Token != "second" {
+		t.Errorf("Previous configuration is not applied: %s.", config.Token)
+	}
+
+	// The rolled-back configuration is applied on the rebuild without the change.
+	rebuild()
+	if config.Token != "second" {
+		t.Errorf("Rolled-back configuration should stay: %s.", config.Token)
+	}
+
+	// The oldest snapshot is already discarded.
+	err = h.rollback(botType, id)
+	if !errors.Is(err, ErrConfigSnapshotNotFound) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	// A new configuration is pushed.
+	token = "fixed"
+	notify()
+	if config.Token != "fixed" {
+		t.Errorf("New configuration is not applied: %s.", config.Token)
+	}
+}
+
+func Test_configHistory_rollback_WithoutSubscription(t *testing.T) {
+	var botType BotType = "dummy"
+	id := "id"
+	h := newConfigHistory(&DummyConfigWatcher{
+		UnwatchFunc: func(_ BotType) error {
+			return nil
+		},
+	}, 5)
+	h.callbacks[configKey{botType: botType, id: id}] = func() {}
+	h.snapshots[configKey{botType: botType, id: id}] = [][]byte{[]byte(`{"Token":"first"}`), []byte(`{"Token":"second"}`)}
+
+	err := h.Unwatch(botType)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	err = h.rollback(botType, id)
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+
+	// Applied on the next build.
+	config := &historyConfig{}
+	err = h.Read(context.TODO(), botType, id, config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if config.Token != "first" {
+		t.Errorf("Snapshot is not applied: %s.", config.Token)
+	}
+}
@@ -0,0 +1,74 @@
+package sarah
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// ConfirmYes is the reply that accepts the confirmation of Confirm.
+	ConfirmYes = "yes"
+
+	// ConfirmNo is the reply that declines the confirmation of Confirm.
+	ConfirmNo = "no"
+)
+
+// Confirm asks the user a yes/no question and returns a CommandResponse that waits for the answer.
+// Return this from a Command, and onYes or onNo is called with the user's reply just like UserContext.Next.
+// The prompt is sent as RichContent with Yes and No buttons, so an Adapter that supports buttons lets the user answer with a click
+// while other Adapters show the prompt as a plain text and let the user type "yes" or "no."
+// "y" and "n" are also accepted, and any other reply asks the question again.
+// When onNo is nil, the user is simply told that the operation is canceled.
+//
+//	Func(func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+//		return sarah.Confirm(ctx, input, "Drop the staging database?", func(ctx context.Context, _ sarah.Input) (*sarah.CommandResponse, error) {
+//			if err := dropDatabase(ctx, "staging"); err != nil {
+//				return nil, err
+//			}
+//			return &sarah.CommandResponse{Content: "Dropped."}, nil
+//		}, nil)
+//	})
+func Confirm(ctx context.Context, input Input, prompt string, onYes ContextualFunc, onNo ContextualFunc) (*CommandResponse, error) {
+	LoggerFromContext(ctx).Debugf("Asking %s for confirmation: %s", input.SenderKey(), prompt)
+
+	if onNo == nil {
+		onNo = func(_ context.Context, _ Input) (*CommandResponse, error) {
+			return &CommandResponse{Content: "Canceled."}, nil
+		}
+	}
+
+	var next ContextualFunc
+	next = func(ctx context.Context, input Input) (*CommandResponse, error) {
+		switch strings.ToLower(strings.TrimSpace(input.Message())) {
+		case ConfirmYes, "y":
+			return onYes(ctx, input)
+
+		case ConfirmNo, "n":
+			return onNo(ctx, input)
+
+		default:
+			return &CommandResponse{
+				Content:     confirmationContent("Please answer yes or no. " + prompt),
+				UserContext: NewUserContext(next),
+			}, nil
+
+		}
+	}
+
+	return &CommandResponse{
+		Content:     confirmationContent(prompt),
+		UserContext: NewUserContext(next),
+	}, nil
+}
+
+func confirmationContent(prompt string) *RichContent {
+	return &RichContent{
+		Blocks: []ContentBlock{
+			&SectionBlock{Text: prompt},
+			&ButtonsBlock{Buttons: []*ContentButton{
+				{Text: "Yes", Value: ConfirmYes, Style: ButtonDanger},
+				{Text: "No", Value: ConfirmNo},
+			}},
+		},
+	}
+}
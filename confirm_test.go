@@ -0,0 +1,95 @@
+package sarah
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	onYes := func(_ context.Context, _ Input) (*CommandResponse, error) {
+		return &CommandResponse{Content: "accepted"}, nil
+	}
+	onNo := func(_ context.Context, _ Input) (*CommandResponse, error) {
+		return &CommandResponse{Content: "declined"}, nil
+	}
+
+	testSets := []struct {
+		onNo     ContextualFunc
+		reply    string
+		expected interface{}
+	}{
+		{
+			onNo:     onNo,
+			reply:    "yes",
+			expected: "accepted",
+		},
+		{
+			onNo:     onNo,
+			reply:    " Y ",
+			expected: "accepted",
+		},
+		{
+			onNo:     onNo,
+			reply:    "no",
+			expected: "declined",
+		},
+		{
+			onNo:     nil,
+			reply:    "n",
+			expected: "Canceled.",
+		},
+	}
+
+	for i, tt := range testSets {
+		response, err := Confirm(context.TODO(), &DummyInput{SenderKeyValue: "user"}, "Are you sure?", onYes, tt.onNo)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+
+		content, ok := response.Content.(*RichContent)
+		if !ok {
+			t.Fatalf("Unexpected content is returned on test #%d: %#v.", i+1, response.Content)
+		}
+		if content.PlainText() != "Are you sure?\nReply with: yes / no" {
+			t.Errorf("Unexpected prompt is returned on test #%d: %q.", i+1, content.PlainText())
+		}
+		if response.UserContext == nil || response.UserContext.Next == nil {
+			t.Fatalf("UserContext is not returned on test #%d.", i+1)
+		}
+
+		answered, err := response.UserContext.Next(context.TODO(), &DummyInput{MessageValue: tt.reply})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if answered.Content != tt.expected {
+			t.Errorf("Unexpected response is returned on test #%d: %#v.", i+1, answered.Content)
+		}
+	}
+}
+
+func TestConfirm_InvalidReply(t *testing.T) {
+	accepted := false
+	response, _ := Confirm(context.TODO(), &DummyInput{}, "Are you sure?", func(_ context.Context, _ Input) (*CommandResponse, error) {
+		accepted = true
+		return &CommandResponse{Content: "accepted"}, nil
+	}, nil)
+
+	retried, err := response.UserContext.Next(context.TODO(), &DummyInput{MessageValue: "maybe"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if retried.UserContext == nil {
+		t.Fatal("The question should be asked again.")
+	}
+	if content, ok := retried.Content.(*RichContent); !ok || content.PlainText() != "Please answer yes or no. Are you sure?\nReply with: yes / no" {
+		t.Errorf("Unexpected content is returned: %#v.", retried.Content)
+	}
+
+	_, err = retried.UserContext.Next(context.TODO(), &DummyInput{MessageValue: "yes"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !accepted {
+		t.Error("onYes is not called on the retried answer.")
+	}
+}
@@ -0,0 +1,135 @@
+package sarah
+
+import (
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+)
+
+// ErrCommandConflict is returned when a Command is registered with an identifier that is already registered for the same Bot
+// and the Bot's CommandConflictPolicy does not allow the replacement.
+var ErrCommandConflict = errors.New("command identifier is already registered")
+
+// CommandConflictPolicy declares how a Bot treats the Commands that share the same identifier.
+// Register one for each Bot via RegisterCommandConflictPolicy.
+type CommandConflictPolicy int
+
+const (
+	// CommandConflictReplace lets the later registration replace the earlier one. This is the default policy.
+	CommandConflictReplace CommandConflictPolicy = iota
+
+	// CommandConflictKeepFirst keeps the earlier registration and ignores the later one.
+	CommandConflictKeepFirst
+
+	// CommandConflictError fails Run with ErrCommandConflict, so the conflict is found on startup.
+	CommandConflictError
+)
+
+// String returns the stringified representation of the policy.
+func (p CommandConflictPolicy) String() string {
+	switch p {
+	case CommandConflictReplace:
+		return "replace"
+
+	case CommandConflictKeepFirst:
+		return "keep-first"
+
+	case CommandConflictError:
+		return "error"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", int(p))
+
+	}
+}
+
+// RegisterCommandConflictPolicy registers a given CommandConflictPolicy for the Bot with the given BotType.
+// The policy applies to the Commands registered via RegisterCommand and RegisterCommandProps on Run,
+// where the ones built from CommandProps come earlier than the ones registered via RegisterCommand just like the order that Bots check them.
+// The policy also applies to AddCommand and AddCommandProps; those return ErrCommandConflict unless the policy is CommandConflictReplace.
+// A rebuild of a Command on its configuration update is not a conflict and always replaces the current one.
+//
+//	sarah.RegisterCommandConflictPolicy(slack.SLACK, sarah.CommandConflictError)
+func RegisterCommandConflictPolicy(botType BotType, policy CommandConflictPolicy) {
+	defaultRunner().RegisterCommandConflictPolicy(botType, policy)
+}
+
+// RegisterCommandConflictPolicy is the Runner counterpart of the package-level RegisterCommandConflictPolicy.
+func (rn *Runner) RegisterCommandConflictPolicy(botType BotType, policy CommandConflictPolicy) {
+	rn.options.register(func(r *runner) {
+		if r.commandConflictPolicies == nil {
+			r.commandConflictPolicies = make(map[BotType]CommandConflictPolicy)
+		}
+		r.commandConflictPolicies[botType] = policy
+	})
+}
+
+func (r *runner) commandConflictPolicy(botType BotType) CommandConflictPolicy {
+	return r.commandConflictPolicies[botType]
+}
+
+// resolveCommandConflicts applies each Bot's CommandConflictPolicy to the registered Commands.
+// This returns the conflicts of the Bots with CommandConflictError, and discards the later registrations of the Bots with CommandConflictKeepFirst.
+func (r *runner) resolveCommandConflicts() []error {
+	var errs []error
+	for botType, policy := range r.commandConflictPolicies {
+		if policy == CommandConflictReplace {
+			continue
+		}
+
+		registered := map[string]struct{}{}
+		conflicts := func(id string) bool {
+			if _, ok := registered[id]; !ok {
+				registered[id] = struct{}{}
+				return false
+			}
+
+			if policy == CommandConflictError {
+				errs = append(errs, fmt.Errorf("%w: %s:%s", ErrCommandConflict, botType, id))
+			} else {
+				logger.Warnf("Command conflict: %s:%s is already registered, so the later registration is ignored.", botType, id)
+			}
+			return true
+		}
+
+		var props []*CommandProps
+		for _, p := range r.commandProps[botType] {
+			if !conflicts(p.identifier) {
+				props = append(props, p)
+			}
+		}
+		var commands []Command
+		for _, command := range r.commands[botType] {
+			if !conflicts(command.Identifier()) {
+				commands = append(commands, command)
+			}
+		}
+
+		if policy == CommandConflictKeepFirst {
+			r.commandProps[botType] = props
+			r.commands[botType] = commands
+		}
+	}
+
+	return errs
+}
+
+// checkCommandConflict tells if a Command with the given identifier can be added to the Bot with the given BotType at runtime.
+// This must be called while r.mutex is locked.
+func (r *runner) checkCommandConflict(botType BotType, id string) error {
+	if r.commandConflictPolicy(botType) == CommandConflictReplace {
+		return nil
+	}
+
+	for _, p := range r.commandProps[botType] {
+		if p.identifier == id {
+			return fmt.Errorf("%w: %s:%s", ErrCommandConflict, botType, id)
+		}
+	}
+	for _, command := range r.commands[botType] {
+		if command.Identifier() == id {
+			return fmt.Errorf("%w: %s:%s", ErrCommandConflict, botType, id)
+		}
+	}
+	return nil
+}
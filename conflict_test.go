@@ -0,0 +1,165 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCommandConflictPolicy_String(t *testing.T) {
+	testSets := []struct {
+		policy   CommandConflictPolicy
+		expected string
+	}{
+		{policy: CommandConflictReplace, expected: "replace"},
+		{policy: CommandConflictKeepFirst, expected: "keep-first"},
+		{policy: CommandConflictError, expected: "error"},
+		{policy: CommandConflictPolicy(100), expected: "unknown(100)"},
+	}
+
+	for i, tt := range testSets {
+		if tt.policy.String() != tt.expected {
+			t.Errorf("Unexpected string is returned on test #%d: %s.", i+1, tt.policy.String())
+		}
+	}
+}
+
+func TestRegisterCommandConflictPolicy(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterCommandConflictPolicy("dummy", CommandConflictError)
+
+		r := &runner{}
+		options.apply(r)
+		if r.commandConflictPolicy("dummy") != CommandConflictError {
+			t.Errorf("Given policy is not set: %s.", r.commandConflictPolicy("dummy"))
+		}
+		if r.commandConflictPolicy("other") != CommandConflictReplace {
+			t.Errorf("Replace should be the default policy: %s.", r.commandConflictPolicy("other"))
+		}
+	})
+}
+
+func Test_runner_resolveCommandConflicts(t *testing.T) {
+	newRunner := func(policy CommandConflictPolicy) *runner {
+		return &runner{
+			commandProps: map[BotType][]*CommandProps{
+				"dummy": {
+					{botType: "dummy", identifier: "echo"},
+					{botType: "dummy", identifier: "echo"},
+					{botType: "dummy", identifier: "hello"},
+				},
+			},
+			commands: map[BotType][]Command{
+				"dummy": {
+					&DummyCommand{IdentifierValue: "hello"},
+					&DummyCommand{IdentifierValue: "weather"},
+				},
+			},
+			commandConflictPolicies: map[BotType]CommandConflictPolicy{
+				"dummy": policy,
+			},
+		}
+	}
+
+	t.Run("replace", func(t *testing.T) {
+		r := newRunner(CommandConflictReplace)
+		errs := r.resolveCommandConflicts()
+		if len(errs) != 0 {
+			t.Errorf("Unexpected errors are returned: %#v.", errs)
+		}
+		if len(r.commandProps["dummy"]) != 3 || len(r.commands["dummy"]) != 2 {
+			t.Error("Registrations should be kept as-is.")
+		}
+	})
+
+	t.Run("keep-first", func(t *testing.T) {
+		r := newRunner(CommandConflictKeepFirst)
+		errs := r.resolveCommandConflicts()
+		if len(errs) != 0 {
+			t.Errorf("Unexpected errors are returned: %#v.", errs)
+		}
+		props := r.commandProps["dummy"]
+		if len(props) != 2 || props[0].identifier != "echo" || props[1].identifier != "hello" {
+			t.Errorf("Unexpected CommandProps are kept: %#v.", props)
+		}
+		commands := r.commands["dummy"]
+		if len(commands) != 1 || commands[0].Identifier() != "weather" {
+			t.Errorf("Unexpected Commands are kept: %#v.", commands)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		r := newRunner(CommandConflictError)
+		errs := r.resolveCommandConflicts()
+		if len(errs) != 2 {
+			t.Fatalf("Unexpected errors are returned: %#v.", errs)
+		}
+		for _, err := range errs {
+			if !errors.Is(err, ErrCommandConflict) {
+				t.Errorf("Unexpected error is returned: %#v.", err)
+			}
+		}
+	})
+}
+
+func TestRun_CommandConflict(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterCommandConflictPolicy("dummy", CommandConflictError)
+		RegisterCommand("dummy", &DummyCommand{IdentifierValue: "echo"})
+		RegisterCommand("dummy", &DummyCommand{IdentifierValue: "echo"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := Run(ctx, NewConfig())
+		if !errors.Is(err, ErrCommandConflict) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func TestAddCommand_Conflict(t *testing.T) {
+	SetupAndRun(func() {
+		bot := &DummyBot{
+			BotTypeValue: "dummy",
+			AppendCommandFunc: func(_ Command) {
+				t.Error("Conflicting Command should not be appended.")
+			},
+		}
+		r := &runner{
+			bots:                    []Bot{bot},
+			commands:                map[BotType][]Command{"dummy": {&DummyCommand{IdentifierValue: "echo"}}},
+			commandProps:            map[BotType][]*CommandProps{},
+			commandConflictPolicies: map[BotType]CommandConflictPolicy{"dummy": CommandConflictKeepFirst},
+		}
+		running.set(r)
+		r.setRunningBot(context.TODO(), bot)
+
+		err := AddCommand("dummy", &DummyCommand{IdentifierValue: "echo"})
+		if !errors.Is(err, ErrCommandConflict) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+
+		err = AddCommandProps(&CommandProps{botType: "dummy", identifier: "echo"})
+		if !errors.Is(err, ErrCommandConflict) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+
+		if len(r.botCommands("dummy")) != 1 || len(r.botCommandProps("dummy")) != 0 {
+			t.Error("Conflicting registrations should not be stashed.")
+		}
+	})
+}
+
+func TestValidate_ConflictPolicy(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterCommandConflictPolicy("slack", CommandConflictKeepFirst)
+		RegisterCommand("slack", &DummyCommand{IdentifierValue: "echo"})
+		RegisterCommand("slack", &DummyCommand{IdentifierValue: "echo"})
+
+		warnings := Validate()
+		if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "ignored") {
+			t.Errorf("Unexpected warnings are returned: %#v.", warnings)
+		}
+	})
+}
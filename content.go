@@ -0,0 +1,220 @@
+package sarah
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RichContent is an adapter-neutral representation of a rich message.
+// A Command can return this as CommandResponse.Content instead of an Adapter-specific type such as Slack's webapi.PostMessage,
+// so a single Command implementation produces rich output on a chat service that supports such a layout
+// and a sensible fallback on others.
+// Each Adapter renders this into its own format; one without any rich layout support may use PlainText.
+type RichContent struct {
+	// Text is the main text of the message.
+	// This is also used as a notification text or a fallback where blocks and attachments can not be displayed.
+	Text string
+
+	// Blocks declares the layout of the message body.
+	Blocks []ContentBlock
+
+	// Attachments declares the secondary contents attached to the message.
+	Attachments []*ContentAttachment
+
+	// Files declares the files to be shared with the message.
+	Files []*ContentFile
+}
+
+// ContentBlock represents a building block of RichContent's layout.
+// The implementations are HeaderBlock, SectionBlock, DividerBlock, ImageBlock, and ButtonsBlock.
+type ContentBlock interface {
+	contentBlock()
+}
+
+// HeaderBlock represents a heading text.
+type HeaderBlock struct {
+	Text string
+}
+
+var _ ContentBlock = (*HeaderBlock)(nil)
+
+func (*HeaderBlock) contentBlock() {}
+
+// SectionBlock represents a paragraph with optional key-value style fields.
+type SectionBlock struct {
+	Text   string
+	Fields []string
+}
+
+var _ ContentBlock = (*SectionBlock)(nil)
+
+func (*SectionBlock) contentBlock() {}
+
+// DividerBlock represents a horizontal rule between blocks.
+type DividerBlock struct{}
+
+var _ ContentBlock = (*DividerBlock)(nil)
+
+func (*DividerBlock) contentBlock() {}
+
+// ImageBlock represents an image with alternative text.
+type ImageBlock struct {
+	URL     string
+	AltText string
+}
+
+var _ ContentBlock = (*ImageBlock)(nil)
+
+func (*ImageBlock) contentBlock() {}
+
+// ButtonsBlock represents a row of buttons that a user can click to reply.
+// An Adapter that supports buttons renders this so a click is delivered as an Input with the clicked ContentButton.Value as its message.
+// Elsewhere, the buttons are rendered as a list of the values that the user can type to reply.
+type ButtonsBlock struct {
+	Buttons []*ContentButton
+}
+
+var _ ContentBlock = (*ButtonsBlock)(nil)
+
+func (*ButtonsBlock) contentBlock() {}
+
+// ButtonStyle represents how a ContentButton is emphasized.
+type ButtonStyle string
+
+const (
+	// ButtonDefault is the default appearance of a button.
+	ButtonDefault ButtonStyle = ""
+
+	// ButtonPrimary emphasizes the button as the recommended action.
+	ButtonPrimary ButtonStyle = "primary"
+
+	// ButtonDanger emphasizes the button as a destructive action.
+	ButtonDanger ButtonStyle = "danger"
+)
+
+// ContentButton represents a button in ButtonsBlock.
+type ContentButton struct {
+	// Text is the label of the button.
+	Text string
+
+	// Value is the reply that a click on the button sends. This is also what the user types where buttons are not available.
+	Value string
+
+	// Style is the emphasis of the button. Adapters without styling support ignore this.
+	Style ButtonStyle
+}
+
+// ContentAttachment represents a secondary content attached to RichContent.
+type ContentAttachment struct {
+	Title     string
+	TitleLink string
+	Text      string
+
+	// Color is a hex color code such as #36a64f. Adapters without coloring support ignore this.
+	Color string
+
+	Fields []*ContentField
+}
+
+// ContentField represents a key-value pair in ContentAttachment.
+type ContentField struct {
+	Title string
+	Value string
+
+	// Short tells if the field is short enough to be displayed side by side with other fields.
+	Short bool
+}
+
+// ContentFile represents a file shared with RichContent.
+type ContentFile struct {
+	Name  string
+	Title string
+	URL   string
+}
+
+// PlainText renders the RichContent into a plain text.
+// Adapters that do not support any rich layout can use this as a fallback rendering.
+func (c *RichContent) PlainText() string {
+	var lines []string
+	if c.Text != "" {
+		lines = append(lines, c.Text)
+	}
+
+	for _, block := range c.Blocks {
+		switch b := block.(type) {
+		case *HeaderBlock:
+			lines = append(lines, b.Text)
+
+		case *SectionBlock:
+			if b.Text != "" {
+				lines = append(lines, b.Text)
+			}
+			for _, field := range b.Fields {
+				lines = append(lines, "- "+field)
+			}
+
+		case *DividerBlock:
+			lines = append(lines, "----")
+
+		case *ImageBlock:
+			lines = append(lines, labeledURL(b.AltText, b.URL))
+
+		case *ButtonsBlock:
+			var options []string
+			for _, button := range b.Buttons {
+				options = append(options, button.option())
+			}
+			if len(options) > 0 {
+				lines = append(lines, "Reply with: "+strings.Join(options, " / "))
+			}
+
+		}
+	}
+
+	for _, attachment := range c.Attachments {
+		if attachment.Title != "" {
+			lines = append(lines, labeledURL(attachment.Title, attachment.TitleLink))
+		}
+		if attachment.Text != "" {
+			lines = append(lines, attachment.Text)
+		}
+		for _, field := range attachment.Fields {
+			lines = append(lines, fmt.Sprintf("%s: %s", field.Title, field.Value))
+		}
+	}
+
+	for _, file := range c.Files {
+		lines = append(lines, labeledURL(file.label(), file.URL))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (f *ContentFile) label() string {
+	if f.Title != "" {
+		return f.Title
+	}
+	return f.Name
+}
+
+// option describes what the user types to reply. e.g. "Delete (yes)" or "yes" when the label and the value are the same.
+func (b *ContentButton) option() string {
+	if b.Text == "" || strings.EqualFold(b.Text, b.Value) {
+		return b.Value
+	}
+	return fmt.Sprintf("%s (%s)", b.Text, b.Value)
+}
+
+func labeledURL(label string, url string) string {
+	switch {
+	case url == "":
+		return label
+
+	case label == "":
+		return url
+
+	default:
+		return fmt.Sprintf("%s: %s", label, url)
+
+	}
+}
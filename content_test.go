@@ -0,0 +1,76 @@
+package sarah
+
+import (
+	"testing"
+)
+
+func TestRichContent_PlainText(t *testing.T) {
+	testSets := []struct {
+		content  *RichContent
+		expected string
+	}{
+		{
+			content:  &RichContent{Text: "Hello"},
+			expected: "Hello",
+		},
+		{
+			content: &RichContent{
+				Text: "Deployment finished",
+				Blocks: []ContentBlock{
+					&HeaderBlock{Text: "Summary"},
+					&SectionBlock{Text: "All services are up.", Fields: []string{"api: v1.2.0", "web: v3.4.1"}},
+					&DividerBlock{},
+					&ImageBlock{URL: "https://example.com/graph.png", AltText: "Latency"},
+				},
+				Attachments: []*ContentAttachment{
+					{
+						Title:     "Release note",
+						TitleLink: "https://example.com/release",
+						Text:      "Bug fixes",
+						Fields:    []*ContentField{{Title: "Author", Value: "sarah"}},
+					},
+				},
+				Files: []*ContentFile{
+					{Name: "report.csv", URL: "https://example.com/report.csv"},
+				},
+			},
+			expected: "Deployment finished\n" +
+				"Summary\n" +
+				"All services are up.\n" +
+				"- api: v1.2.0\n" +
+				"- web: v3.4.1\n" +
+				"----\n" +
+				"Latency: https://example.com/graph.png\n" +
+				"Release note: https://example.com/release\n" +
+				"Bug fixes\n" +
+				"Author: sarah\n" +
+				"report.csv: https://example.com/report.csv",
+		},
+		{
+			content: &RichContent{
+				Blocks: []ContentBlock{&ImageBlock{URL: "https://example.com/image.png"}},
+				Files:  []*ContentFile{{Title: "Title", Name: "name.txt"}},
+			},
+			expected: "https://example.com/image.png\nTitle",
+		},
+		{
+			content: &RichContent{
+				Text: "Are you sure?",
+				Blocks: []ContentBlock{
+					&ButtonsBlock{Buttons: []*ContentButton{
+						{Text: "Yes", Value: "yes", Style: ButtonPrimary},
+						{Text: "Cancel", Value: "no"},
+					}},
+				},
+			},
+			expected: "Are you sure?\nReply with: yes / Cancel (no)",
+		},
+	}
+
+	for i, testSet := range testSets {
+		text := testSet.content.PlainText()
+		if text != testSet.expected {
+			t.Errorf("Unexpected text is returned on test #%d: %q.", i+1, text)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Package analytics provides a plugin that aggregates the usage of the Commands and reports it on a daily basis.
+//
+// Analytics counts each Command's executions and unique users per day by observing the executions with sarah.RegisterCommandExecutionObserver.
+// The report of the previous day is posted to a chat destination and is handed to the given Exporter values such as CSVExporter and HTTPExporter.
+//
+//	a := analytics.New(analytics.NewConfig(), analytics.WithExporter(analytics.NewCSVExporter("/var/log/sarah")))
+//	sarah.RegisterCommandExecutionObserver(a.Observe)
+//	sarah.RegisterScheduledTaskProps(a.ReportTaskProps(slack.SLACK, event.ChannelID("C8901")))
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Config contains some configuration variables for the analytics plugin.
+type Config struct {
+	// ReportSchedule declares when to report the usage of the previous day.
+	ReportSchedule string `json:"report_schedule" yaml:"report_schedule"`
+
+	// RetentionDays declares how many days of the usage are kept in memory.
+	RetentionDays int `json:"retention_days" yaml:"retention_days"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		ReportSchedule: "0 0 9 * * *",
+		RetentionDays:  7,
+	}
+}
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*Analytics)
+
+// WithLocation creates an Option that sets the time zone to determine the boundary of a day.
+// By default, time.Local is used.
+func WithLocation(location *time.Location) Option {
+	return func(a *Analytics) {
+		a.location = location
+	}
+}
+
+// WithExporter creates an Option that adds an Exporter to export the daily report to.
+func WithExporter(exporter Exporter) Option {
+	return func(a *Analytics) {
+		a.exporters = append(a.exporters, exporter)
+	}
+}
+
+// Analytics aggregates the Commands' usage per day.
+// Register Observe via sarah.RegisterCommandExecutionObserver to start the aggregation.
+type Analytics struct {
+	config    *Config
+	location  *time.Location
+	exporters []Exporter
+	now       func() time.Time
+	days      map[string]*dailyUsage
+	mutex     sync.Mutex
+}
+
+// New creates and returns a new Analytics instance.
+func New(config *Config, options ...Option) *Analytics {
+	a := &Analytics{
+		config:   config,
+		location: time.Local,
+		now:      time.Now,
+		days:     map[string]*dailyUsage{},
+	}
+
+	for _, opt := range options {
+		opt(a)
+	}
+
+	return a
+}
+
+// Observe counts the given execution.
+// This satisfies sarah.CommandExecutionObserver.
+func (a *Analytics) Observe(_ context.Context, execution *sarah.CommandExecution) {
+	date := execution.StartedAt.In(a.location).Format(dateLayout)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	usage, ok := a.days[date]
+	if !ok {
+		usage = newDailyUsage()
+		a.days[date] = usage
+		a.expire(execution.StartedAt)
+	}
+	usage.add(execution)
+}
+
+// Report builds and returns the usage report of the day that the given time belongs to.
+func (a *Analytics) Report(day time.Time) *Report {
+	date := day.In(a.location).Format(dateLayout)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	usage, ok := a.days[date]
+	if !ok {
+		return &Report{
+			Date:     date,
+			Commands: []*CommandUsage{},
+		}
+	}
+	return usage.report(date)
+}
+
+// ReportTaskProps builds and returns a sarah.ScheduledTaskProps that reports the usage of the previous day.
+// The report is handed to the Exporter values given via WithExporter and is then posted to the given destination.
+// When destination is nil, the report is only exported.
+func (a *Analytics) ReportTaskProps(botType sarah.BotType, destination sarah.OutputDestination) *sarah.ScheduledTaskProps {
+	builder := sarah.NewScheduledTaskPropsBuilder().
+		BotType(botType).
+		Identifier("analytics_report").
+		Schedule(a.config.ReportSchedule)
+	if destination != nil {
+		builder = builder.DefaultDestination(destination)
+	}
+
+	return builder.
+		Func(func(ctx context.Context) ([]*sarah.ScheduledTaskResult, error) {
+			return a.reportPreviousDay(ctx, destination != nil), nil
+		}).
+		MustBuild()
+}
+
+// reportPreviousDay exports the report of the previous day, and returns it as a sarah.ScheduledTaskResult when post is true.
+func (a *Analytics) reportPreviousDay(ctx context.Context, post bool) []*sarah.ScheduledTaskResult {
+	report := a.Report(a.now().In(a.location).AddDate(0, 0, -1))
+	for _, exporter := range a.exporters {
+		if err := exporter.Export(ctx, report); err != nil {
+			logger.Errorf("Failed to export the usage report of %s: %+v", report.Date, err)
+		}
+	}
+
+	if !post {
+		return nil
+	}
+	return []*sarah.ScheduledTaskResult{
+		{
+			Content: report.String(),
+		},
+	}
+}
+
+// expire removes the usage older than Config.RetentionDays.
+func (a *Analytics) expire(now time.Time) {
+	if a.config.RetentionDays <= 0 {
+		return
+	}
+
+	oldest := now.In(a.location).AddDate(0, 0, -a.config.RetentionDays).Format(dateLayout)
+	for date := range a.days {
+		// The dates in the form of YYYY-MM-DD can be compared as strings.
+		if date <= oldest {
+			delete(a.days, date)
+		}
+	}
+}
+
+// Report represents the usage of the Commands in a day.
+type Report struct {
+	// Date is the reported day in the form of YYYY-MM-DD.
+	Date string `json:"date"`
+
+	// UniqueUsers is the number of the users who executed any Command on the day.
+	UniqueUsers int `json:"unique_users"`
+
+	// Commands holds each Command's usage sorted by the number of executions in descending order.
+	Commands []*CommandUsage `json:"commands"`
+}
+
+// String returns the stringified representation of the report that is posted to the chat.
+func (r *Report) String() string {
+	if len(r.Commands) == 0 {
+		return fmt.Sprintf("No command was executed on %s.", r.Date)
+	}
+
+	lines := []string{fmt.Sprintf("Command usage on %s. Unique users: %d.", r.Date, r.UniqueUsers)}
+	for _, command := range r.Commands {
+		lines = append(lines, fmt.Sprintf("%s (%s): %d executions, %d failures, %d users",
+			command.Identifier, command.BotType, command.Executions, command.Failures, command.UniqueUsers))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CommandUsage represents a Command's usage in a day.
+type CommandUsage struct {
+	// BotType represents the Bot that executed the Command.
+	BotType sarah.BotType `json:"bot_type"`
+
+	// Identifier is the identifier of the Command.
+	Identifier string `json:"identifier"`
+
+	// Executions is the number of the executions.
+	Executions int `json:"executions"`
+
+	// Failures is the number of the executions that returned an error.
+	Failures int `json:"failures"`
+
+	// UniqueUsers is the number of the users who executed the Command.
+	UniqueUsers int `json:"unique_users"`
+}
+
+type commandKey struct {
+	botType    sarah.BotType
+	identifier string
+}
+
+type commandCounter struct {
+	executions int
+	failures   int
+	users      map[string]struct{}
+}
+
+// dailyUsage holds the counters of a day.
+type dailyUsage struct {
+	commands map[commandKey]*commandCounter
+	users    map[string]struct{}
+}
+
+func newDailyUsage() *dailyUsage {
+	return &dailyUsage{
+		commands: map[commandKey]*commandCounter{},
+		users:    map[string]struct{}{},
+	}
+}
+
+func (d *dailyUsage) add(execution *sarah.CommandExecution) {
+	key := commandKey{
+		botType:    execution.BotType,
+		identifier: execution.Identifier,
+	}
+	counter, ok := d.commands[key]
+	if !ok {
+		counter = &commandCounter{
+			users: map[string]struct{}{},
+		}
+		d.commands[key] = counter
+	}
+
+	counter.executions++
+	if execution.Err != nil {
+		counter.failures++
+	}
+	counter.users[execution.SenderKey] = struct{}{}
+	d.users[execution.SenderKey] = struct{}{}
+}
+
+func (d *dailyUsage) report(date string) *Report {
+	commands := make([]*CommandUsage, 0, len(d.commands))
+	for key, counter := range d.commands {
+		commands = append(commands, &CommandUsage{
+			BotType:     key.botType,
+			Identifier:  key.identifier,
+			Executions:  counter.executions,
+			Failures:    counter.failures,
+			UniqueUsers: len(counter.users),
+		})
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		if commands[i].Executions != commands[j].Executions {
+			return commands[i].Executions > commands[j].Executions
+		}
+		if commands[i].BotType != commands[j].BotType {
+			return commands[i].BotType < commands[j].BotType
+		}
+		return commands[i].Identifier < commands[j].Identifier
+	})
+
+	return &Report{
+		Date:        date,
+		UniqueUsers: len(d.users),
+		Commands:    commands,
+	}
+}
@@ -0,0 +1,233 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyExporter struct {
+	ExportFunc func(context.Context, *Report) error
+}
+
+func (e *DummyExporter) Export(ctx context.Context, report *Report) error {
+	return e.ExportFunc(ctx, report)
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config == nil {
+		t.Fatal("Expected *Config is not returned.")
+	}
+
+	if config.ReportSchedule == "" {
+		t.Error("Default schedule is not set.")
+	}
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	a := New(NewConfig(), func(_ *Analytics) {
+		optCalled = true
+	})
+
+	if a == nil {
+		t.Fatal("Analytics is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Option is not applied.")
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+	location := time.FixedZone("JST", 9*60*60)
+	a := &Analytics{}
+	WithLocation(location)(a)
+
+	if a.location != location {
+		t.Errorf("Given location is not set: %s.", a.location)
+	}
+}
+
+func TestWithExporter(t *testing.T) {
+	exporter := &DummyExporter{}
+	a := &Analytics{}
+	WithExporter(exporter)(a)
+
+	if len(a.exporters) != 1 || a.exporters[0] != exporter {
+		t.Errorf("Given Exporter is not set: %#v.", a.exporters)
+	}
+}
+
+func TestAnalytics_Observe(t *testing.T) {
+	a := New(NewConfig(), WithLocation(time.UTC))
+	day := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	executions := []*sarah.CommandExecution{
+		{BotType: "slack", Identifier: "echo", SenderKey: "alice", StartedAt: day},
+		{BotType: "slack", Identifier: "echo", SenderKey: "alice", StartedAt: day, Err: errors.New("dummy")},
+		{BotType: "slack", Identifier: "echo", SenderKey: "bob", StartedAt: day},
+		{BotType: "slack", Identifier: "weather", SenderKey: "carol", StartedAt: day},
+		{BotType: "slack", Identifier: "weather", SenderKey: "carol", StartedAt: day.AddDate(0, 0, 1)},
+	}
+	for _, execution := range executions {
+		a.Observe(context.TODO(), execution)
+	}
+
+	report := a.Report(day)
+	if report.Date != "2026-01-01" {
+		t.Errorf("Unexpected date is set: %s.", report.Date)
+	}
+	if report.UniqueUsers != 3 {
+		t.Errorf("Unexpected number of unique users: %d.", report.UniqueUsers)
+	}
+
+	expected := []CommandUsage{
+		{BotType: "slack", Identifier: "echo", Executions: 3, Failures: 1, UniqueUsers: 2},
+		{BotType: "slack", Identifier: "weather", Executions: 1, Failures: 0, UniqueUsers: 1},
+	}
+	if len(report.Commands) != len(expected) {
+		t.Fatalf("Unexpected usage is returned: %#v.", report.Commands)
+	}
+	for i, usage := range report.Commands {
+		if *usage != expected[i] {
+			t.Errorf("Unexpected usage is returned at %d: %#v.", i, usage)
+		}
+	}
+
+	next := a.Report(day.AddDate(0, 0, 1))
+	if next.UniqueUsers != 1 || len(next.Commands) != 1 {
+		t.Errorf("Unexpected report is returned for the next day: %#v.", next)
+	}
+}
+
+func TestAnalytics_Observe_Location(t *testing.T) {
+	location := time.FixedZone("JST", 9*60*60)
+	a := New(NewConfig(), WithLocation(location))
+
+	// 2026-01-01 20:00 in UTC is 2026-01-02 05:00 in JST.
+	started := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	a.Observe(context.TODO(), &sarah.CommandExecution{BotType: "slack", Identifier: "echo", SenderKey: "alice", StartedAt: started})
+
+	report := a.Report(started)
+	if report.Date != "2026-01-02" || len(report.Commands) != 1 {
+		t.Errorf("Execution is not counted in the given location: %#v.", report)
+	}
+}
+
+func TestAnalytics_Observe_Retention(t *testing.T) {
+	config := NewConfig()
+	config.RetentionDays = 2
+	a := New(config, WithLocation(time.UTC))
+
+	day := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		a.Observe(context.TODO(), &sarah.CommandExecution{BotType: "slack", Identifier: "echo", SenderKey: "alice", StartedAt: day.AddDate(0, 0, i)})
+	}
+
+	if len(a.days) != 2 {
+		t.Errorf("Expired usage is kept: %#v.", a.days)
+	}
+	if len(a.Report(day).Commands) != 0 {
+		t.Error("The oldest usage should be expired.")
+	}
+}
+
+func TestAnalytics_ReportTaskProps(t *testing.T) {
+	a := New(NewConfig())
+
+	props := a.ReportTaskProps("slack", "channel")
+	if props == nil {
+		t.Fatal("ScheduledTaskProps is not returned.")
+	}
+
+	props = a.ReportTaskProps("slack", nil)
+	if props == nil {
+		t.Fatal("ScheduledTaskProps is not returned without destination.")
+	}
+}
+
+func TestAnalytics_reportPreviousDay(t *testing.T) {
+	now := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	var exported *Report
+	exporters := []Exporter{
+		&DummyExporter{
+			ExportFunc: func(_ context.Context, _ *Report) error {
+				return errors.New("dummy")
+			},
+		},
+		&DummyExporter{
+			ExportFunc: func(_ context.Context, report *Report) error {
+				exported = report
+				return nil
+			},
+		},
+	}
+	a := New(NewConfig(), WithLocation(time.UTC), WithExporter(exporters[0]), WithExporter(exporters[1]))
+	a.now = func() time.Time {
+		return now
+	}
+	a.Observe(context.TODO(), &sarah.CommandExecution{BotType: "slack", Identifier: "echo", SenderKey: "alice", StartedAt: now.AddDate(0, 0, -1)})
+
+	results := a.reportPreviousDay(context.TODO(), true)
+
+	if exported == nil || exported.Date != "2026-01-01" {
+		t.Errorf("The previous day's report is not exported: %#v.", exported)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Content.(string), "echo (slack): 1 executions") {
+		t.Errorf("Unexpected results are returned: %#v.", results)
+	}
+}
+
+func TestAnalytics_reportPreviousDay_WithoutPost(t *testing.T) {
+	exported := false
+	a := New(NewConfig(), WithExporter(&DummyExporter{
+		ExportFunc: func(_ context.Context, _ *Report) error {
+			exported = true
+			return nil
+		},
+	}))
+
+	results := a.reportPreviousDay(context.TODO(), false)
+
+	if !exported {
+		t.Error("Report is not exported.")
+	}
+	if len(results) != 0 {
+		t.Errorf("Nothing should be posted: %#v.", results)
+	}
+}
+
+func TestReport_String(t *testing.T) {
+	testSets := []struct {
+		report   *Report
+		expected string
+	}{
+		{
+			report: &Report{
+				Date:     "2026-01-01",
+				Commands: []*CommandUsage{},
+			},
+			expected: "No command was executed on 2026-01-01.",
+		},
+		{
+			report: &Report{
+				Date:        "2026-01-01",
+				UniqueUsers: 2,
+				Commands: []*CommandUsage{
+					{BotType: "slack", Identifier: "echo", Executions: 3, Failures: 1, UniqueUsers: 2},
+				},
+			},
+			expected: "Command usage on 2026-01-01. Unique users: 2.\necho (slack): 3 executions, 1 failures, 2 users",
+		},
+	}
+
+	for i, tt := range testSets {
+		if tt.report.String() != tt.expected {
+			t.Errorf("Unexpected string is returned on test #%d: %s.", i+1, tt.report.String())
+		}
+	}
+}
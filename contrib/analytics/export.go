@@ -0,0 +1,117 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Exporter defines an interface that each destination of the daily report must satisfy.
+type Exporter interface {
+	// Export exports the given report.
+	Export(ctx context.Context, report *Report) error
+}
+
+// CSVExporter writes the daily report to a CSV file under the given directory.
+// A file is created for each day with the name of usage-YYYY-MM-DD.csv.
+type CSVExporter struct {
+	dir string
+}
+
+var _ Exporter = (*CSVExporter)(nil)
+
+// NewCSVExporter creates and returns a new CSVExporter instance that writes the files under the given directory.
+func NewCSVExporter(dir string) *CSVExporter {
+	return &CSVExporter{
+		dir: dir,
+	}
+}
+
+// Export writes the given report to a CSV file.
+// The first row is the header, and each of the following rows represents a Command's usage.
+// The existing file for the same day is overwritten.
+func (e *CSVExporter) Export(_ context.Context, report *Report) error {
+	file, err := os.Create(filepath.Join(e.dir, fmt.Sprintf("usage-%s.csv", report.Date)))
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	return writeCSV(file, report)
+}
+
+func writeCSV(writer io.Writer, report *Report) error {
+	w := csv.NewWriter(writer)
+	_ = w.Write([]string{"date", "bot_type", "identifier", "executions", "failures", "unique_users"})
+	for _, command := range report.Commands {
+		_ = w.Write([]string{
+			report.Date,
+			command.BotType.String(),
+			command.Identifier,
+			strconv.Itoa(command.Executions),
+			strconv.Itoa(command.Failures),
+			strconv.Itoa(command.UniqueUsers),
+		})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// HTTPExporter posts the daily report to an HTTP endpoint in the form of JSON.
+type HTTPExporter struct {
+	client *http.Client
+	url    string
+}
+
+var _ Exporter = (*HTTPExporter)(nil)
+
+// NewHTTPExporter creates and returns a new HTTPExporter instance that posts to the given URL.
+// When client is nil, http.DefaultClient is used. Use sarah.NewHTTPClient to communicate via a proxy server.
+func NewHTTPExporter(client *http.Client, url string) *HTTPExporter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPExporter{
+		client: client,
+		url:    url,
+	}
+}
+
+// Export posts the given report to the endpoint.
+func (e *HTTPExporter) Export(ctx context.Context, report *Report) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to construct HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed executing HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code is returned: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCSVExporter_Export(t *testing.T) {
+	dir := t.TempDir()
+	report := &Report{
+		Date:        "2026-01-01",
+		UniqueUsers: 2,
+		Commands: []*CommandUsage{
+			{BotType: "slack", Identifier: "echo", Executions: 3, Failures: 1, UniqueUsers: 2},
+		},
+	}
+
+	err := NewCSVExporter(dir).Export(context.TODO(), report)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	written, err := os.ReadFile(filepath.Join(dir, "usage-2026-01-01.csv"))
+	if err != nil {
+		t.Fatalf("CSV file is not written: %s.", err.Error())
+	}
+
+	expected := "date,bot_type,identifier,executions,failures,unique_users\n2026-01-01,slack,echo,3,1,2\n"
+	if string(written) != expected {
+		t.Errorf("Unexpected CSV is written: %s.", written)
+	}
+}
+
+func TestCSVExporter_Export_Error(t *testing.T) {
+	err := NewCSVExporter(filepath.Join(t.TempDir(), "missing")).Export(context.TODO(), &Report{Date: "2026-01-01"})
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestHTTPExporter_Export(t *testing.T) {
+	var received *Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request is received: %s %s.", r.Method, r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	report := &Report{
+		Date:        "2026-01-01",
+		UniqueUsers: 1,
+		Commands: []*CommandUsage{
+			{BotType: "slack", Identifier: "echo", Executions: 1, UniqueUsers: 1},
+		},
+	}
+	err := NewHTTPExporter(nil, server.URL).Export(context.TODO(), report)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if received == nil || received.Date != report.Date || len(received.Commands) != 1 || *received.Commands[0] != *report.Commands[0] {
+		t.Errorf("Unexpected report is received: %#v.", received)
+	}
+}
+
+func TestHTTPExporter_Export_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewHTTPExporter(server.Client(), server.URL).Export(context.TODO(), &Report{Date: "2026-01-01"})
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
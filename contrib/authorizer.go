@@ -0,0 +1,35 @@
+package contrib
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// Authorizer tells if the user who sent the given Input is allowed to use a restricted feature of a plugin.
+// A plugin with such a feature, e.g. triggering a deployment or changing the process-wide state, accepts an Authorizer via its WithAuthorizer option.
+//
+//	authorizer := func(input sarah.Input) bool {
+//		return input.SenderKey() == "admin"
+//	}
+//	r := rollback.New(slack.SLACK, rollback.WithAuthorizer(authorizer))
+type Authorizer func(sarah.Input) bool
+
+// DenyAll is an Authorizer that denies every Input.
+// The plugins use this by default so a restricted feature is not available to anyone until an Authorizer is explicitly given.
+func DenyAll(_ sarah.Input) bool {
+	return false
+}
+
+// AllowAll is an Authorizer that allows every Input.
+// Give this explicitly to opt out of the access control, e.g. in a private workspace where every member is trusted.
+func AllowAll(_ sarah.Input) bool {
+	return true
+}
+
+// Authorize tells if the given Authorizer allows the given Input.
+// A nil Authorizer denies every Input just like DenyAll.
+func Authorize(authorizer Authorizer, input sarah.Input) bool {
+	if authorizer == nil {
+		return false
+	}
+	return authorizer(input)
+}
@@ -0,0 +1,58 @@
+package contrib
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+type DummyInput struct{}
+
+func (i *DummyInput) SenderKey() string {
+	return "dummy"
+}
+
+func (i *DummyInput) Message() string {
+	return ""
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return "dummy"
+}
+
+func TestAuthorize(t *testing.T) {
+	testSets := []struct {
+		authorizer Authorizer
+		expected   bool
+	}{
+		{
+			authorizer: nil,
+			expected:   false,
+		},
+		{
+			authorizer: DenyAll,
+			expected:   false,
+		},
+		{
+			authorizer: AllowAll,
+			expected:   true,
+		},
+		{
+			authorizer: func(input sarah.Input) bool {
+				return input.SenderKey() == "dummy"
+			},
+			expected: true,
+		},
+	}
+
+	for i, tt := range testSets {
+		authorized := Authorize(tt.authorizer, &DummyInput{})
+		if authorized != tt.expected {
+			t.Errorf("Unexpected result is returned on test #%d: %t.", i+1, authorized)
+		}
+	}
+}
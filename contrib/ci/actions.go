@@ -0,0 +1,103 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// GitHubActionsConfig contains some configuration variables for GitHub Actions.
+type GitHubActionsConfig struct {
+	// APIEndpoint declares the base URL of GitHub REST API.
+	// Change this to use GitHub Enterprise Server.
+	APIEndpoint string `json:"api_endpoint" yaml:"api_endpoint"`
+
+	// Token declares the access token with the permission to dispatch workflows.
+	Token string `json:"token" yaml:"token"`
+
+	// Owner declares the owner of the repository.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Repository declares the name of the repository.
+	Repository string `json:"repository" yaml:"repository"`
+
+	// Ref declares the git reference to run the workflow on.
+	Ref string `json:"ref" yaml:"ref"`
+}
+
+// NewGitHubActionsConfig creates and returns a new GitHubActionsConfig instance with default settings.
+// Token, Owner, and Repository are empty at this point as there can not be default values.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank value or override those default values.
+func NewGitHubActionsConfig() *GitHubActionsConfig {
+	return &GitHubActionsConfig{
+		APIEndpoint: "https://api.github.com/",
+		Token:       "",
+		Owner:       "",
+		Repository:  "",
+		Ref:         "main",
+	}
+}
+
+// GitHubActions triggers GitHub Actions workflows via the workflow_dispatch event.
+// The target workflow must declare the workflow_dispatch trigger and its inputs.
+type GitHubActions struct {
+	config *GitHubActionsConfig
+}
+
+var _ Trigger = (*GitHubActions)(nil)
+
+// NewGitHubActions creates and returns a new GitHubActions instance.
+func NewGitHubActions(config *GitHubActionsConfig) *GitHubActions {
+	return &GitHubActions{
+		config: config,
+	}
+}
+
+type workflowDispatch struct {
+	Ref    string            `json:"ref"`
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// Trigger dispatches the given workflow with the given inputs.
+// job is the workflow's file name such as "deploy.yml" or its ID.
+func (g *GitHubActions) Trigger(ctx context.Context, job string, params map[string]string) error {
+	endpoint, err := url.Parse(g.config.APIEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to parse API endpoint: %w", err)
+	}
+	endpoint.Path = path.Join(endpoint.Path, "repos", g.config.Owner, g.config.Repository, "actions", "workflows", job, "dispatches")
+
+	reqBody, err := json.Marshal(&workflowDispatch{
+		Ref:    g.config.Ref,
+		Inputs: params,
+	})
+	if err != nil {
+		return fmt.Errorf("can not marshal given payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint.String(), strings.NewReader(string(reqBody)))
+	if err != nil {
+		return fmt.Errorf("failed to construct HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+g.config.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed executing HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// GitHub responds with 204 No Content on success.
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code is returned: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewGitHubActions(t *testing.T) {
+	config := NewGitHubActionsConfig()
+	actions := NewGitHubActions(config)
+
+	if actions.config != config {
+		t.Error("Given config is not set.")
+	}
+}
+
+func TestGitHubActions_Trigger(t *testing.T) {
+	testSets := []struct {
+		status int
+		hasErr bool
+	}{
+		{
+			status: http.StatusNoContent,
+			hasErr: false,
+		},
+		{
+			status: http.StatusUnprocessableEntity,
+			hasErr: true,
+		},
+	}
+
+	for i, testSet := range testSets {
+		status := testSet.status
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/repos/oklahomer/go-sarah/actions/workflows/deploy.yml/dispatches" {
+				t.Errorf("Unexpected path is requested on test %d: %s.", i, r.URL.Path)
+			}
+
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("Expected token is not given on test %d.", i)
+			}
+
+			payload := &workflowDispatch{}
+			err := json.NewDecoder(r.Body).Decode(payload)
+			if err != nil {
+				t.Errorf("Unexpected payload is given on test %d: %s.", i, err.Error())
+			}
+			if payload.Ref != "main" || payload.Inputs["env"] != "production" {
+				t.Errorf("Unexpected payload is given on test %d: %#v.", i, payload)
+			}
+
+			w.WriteHeader(status)
+		}))
+
+		config := NewGitHubActionsConfig()
+		config.APIEndpoint = server.URL
+		config.Token = "token"
+		config.Owner = "oklahomer"
+		config.Repository = "go-sarah"
+		err := NewGitHubActions(config).Trigger(context.TODO(), "deploy.yml", map[string]string{"env": "production"})
+		server.Close()
+
+		if testSet.hasErr && err == nil {
+			t.Errorf("Expected error is not returned on test %d.", i)
+		} else if !testSet.hasErr && err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+		}
+	}
+}
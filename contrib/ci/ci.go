@@ -0,0 +1,151 @@
+// Package ci provides a plugin to trigger CI builds from chat and to relay build results to chat destinations.
+//
+// CI serves the ".build" command that triggers a parameterized build with the given Trigger implementation.
+// Jenkins and GitHubActions are bundled; implement Trigger to support other CI services.
+// Because triggering a build may result in a deployment, no user can trigger builds until an Authorizer is given with WithAuthorizer.
+//
+//	trigger := ci.NewJenkins(ci.NewJenkinsConfig())
+//	c := ci.New(slack.SLACK, trigger, ci.WithAuthorizer(func(input sarah.Input) bool {
+//		return input.SenderKey() == "admin"
+//	}))
+//	sarah.RegisterCommandProps(c.CommandProps())
+//
+// Receiver receives build notifications and sends formatted results to the configured destinations.
+// Each notification is verified with ReceiverConfig.Token or ReceiverConfig.Secret, so at least one of them must be given.
+package ci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib"
+	"regexp"
+	"strings"
+)
+
+var matchPattern = regexp.MustCompile(`^\.build\b`)
+
+// ErrInvalidParameter is returned when a build parameter is not given in the form of key=value.
+var ErrInvalidParameter = errors.New("parameter must be given in the form of key=value")
+
+// Trigger defines an interface that each CI service must satisfy to start a build.
+type Trigger interface {
+	// Trigger starts a build of the given job with the given parameters.
+	// The form of job depends on the CI service: a job name for Jenkins and a workflow file name for GitHub Actions.
+	Trigger(ctx context.Context, job string, params map[string]string) error
+}
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*CI)
+
+// WithAuthorizer creates and returns an Option that restricts who can trigger builds.
+// When the given function returns false, the build is not triggered and the user is notified.
+// By default, no user can trigger builds.
+func WithAuthorizer(authorizer contrib.Authorizer) Option {
+	return func(c *CI) {
+		c.authorize = authorizer
+	}
+}
+
+// WithJobs creates and returns an Option that restricts which jobs can be triggered.
+// By default, any job can be triggered.
+func WithJobs(jobs ...string) Option {
+	return func(c *CI) {
+		c.jobs = append(c.jobs, jobs...)
+	}
+}
+
+// CI triggers CI builds via the ".build" command.
+type CI struct {
+	botType   sarah.BotType
+	trigger   Trigger
+	authorize contrib.Authorizer
+	jobs      []string
+}
+
+// New creates and returns a new CI instance with the given Trigger.
+func New(botType sarah.BotType, trigger Trigger, options ...Option) *CI {
+	c := &CI{
+		botType:   botType,
+		trigger:   trigger,
+		authorize: contrib.DenyAll,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".build" command.
+// A job name follows the command, and build parameters can be given in the form of key=value as below:
+//
+//	.build deploy env=production branch=main
+func (c *CI) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(c.botType).
+		Identifier("build").
+		Instruction("Input .build job [key=value ...] to trigger a CI build.").
+		MatchPattern(matchPattern).
+		Func(func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: c.handle(ctx, input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (c *CI) handle(ctx context.Context, input sarah.Input) string {
+	if !contrib.Authorize(c.authorize, input) {
+		return "You are not allowed to trigger builds."
+	}
+
+	fields := strings.Fields(sarah.StripMessage(matchPattern, input.Message()))
+	if len(fields) == 0 {
+		return "Usage: .build job [key=value ...]"
+	}
+
+	job := fields[0]
+	if !c.allowed(job) {
+		return fmt.Sprintf("Unknown job: %s.", job)
+	}
+
+	params, err := parseParams(fields[1:])
+	if err != nil {
+		return fmt.Sprintf("Failed to parse parameters: %s.", err.Error())
+	}
+
+	err = c.trigger.Trigger(ctx, job, params)
+	if err != nil {
+		return fmt.Sprintf("Failed to trigger %s: %s.", job, err.Error())
+	}
+
+	return fmt.Sprintf("Triggered %s.", job)
+}
+
+func (c *CI) allowed(job string) bool {
+	if len(c.jobs) == 0 {
+		return true
+	}
+
+	for _, j := range c.jobs {
+		if j == job {
+			return true
+		}
+	}
+	return false
+}
+
+func parseParams(fields []string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, ErrInvalidParameter
+		}
+		params[key] = value
+	}
+	return params, nil
+}
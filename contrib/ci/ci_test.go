@@ -0,0 +1,162 @@
+package ci
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+type DummyTrigger struct {
+	TriggerFunc func(context.Context, string, map[string]string) error
+}
+
+func (t *DummyTrigger) Trigger(ctx context.Context, job string, params map[string]string) error {
+	return t.TriggerFunc(ctx, job, params)
+}
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.SenderKeyValue
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	c := New("dummy", &DummyTrigger{}, func(_ *CI) {
+		optCalled = true
+	})
+
+	if c == nil {
+		t.Fatal("CI is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Given Option is not applied.")
+	}
+
+	if c.authorize(&DummyInput{}) {
+		t.Error("No user should be authorized by default.")
+	}
+}
+
+func TestWithAuthorizer(t *testing.T) {
+	c := &CI{}
+	WithAuthorizer(func(_ sarah.Input) bool {
+		return false
+	})(c)
+
+	if c.authorize == nil {
+		t.Fatal("Given function is not set.")
+	}
+
+	if c.authorize(&DummyInput{}) {
+		t.Error("Unexpected function is set.")
+	}
+}
+
+func TestWithJobs(t *testing.T) {
+	c := &CI{}
+	WithJobs("deploy", "test")(c)
+
+	if len(c.jobs) != 2 {
+		t.Errorf("Unexpected jobs are set: %#v.", c.jobs)
+	}
+}
+
+func TestCI_CommandProps(t *testing.T) {
+	c := New("dummy", &DummyTrigger{})
+	props := c.CommandProps()
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestCI_handle(t *testing.T) {
+	testSets := []struct {
+		message    string
+		authorized bool
+		err        error
+		expected   string
+		triggered  bool
+	}{
+		{
+			message:    ".build deploy env=production",
+			authorized: false,
+			expected:   "You are not allowed to trigger builds.",
+		},
+		{
+			message:    ".build",
+			authorized: true,
+			expected:   "Usage: .build job [key=value ...]",
+		},
+		{
+			message:    ".build unknown",
+			authorized: true,
+			expected:   "Unknown job: unknown.",
+		},
+		{
+			message:    ".build deploy production",
+			authorized: true,
+			expected:   "Failed to parse parameters: " + ErrInvalidParameter.Error() + ".",
+		},
+		{
+			message:    ".build deploy env=production",
+			authorized: true,
+			err:        errors.New("connection refused"),
+			expected:   "Failed to trigger deploy: connection refused.",
+			triggered:  true,
+		},
+		{
+			message:    ".build deploy env=production",
+			authorized: true,
+			expected:   "Triggered deploy.",
+			triggered:  true,
+		},
+	}
+
+	for i, testSet := range testSets {
+		triggered := false
+		trigger := &DummyTrigger{
+			TriggerFunc: func(_ context.Context, job string, params map[string]string) error {
+				triggered = true
+				if job != "deploy" {
+					t.Errorf("Unexpected job is given on test %d: %s.", i, job)
+				}
+				if params["env"] != "production" {
+					t.Errorf("Unexpected parameters are given on test %d: %#v.", i, params)
+				}
+				return testSet.err
+			},
+		}
+		authorized := testSet.authorized
+		c := New("dummy", trigger, WithJobs("deploy"), WithAuthorizer(func(_ sarah.Input) bool {
+			return authorized
+		}))
+
+		response := c.handle(context.TODO(), &DummyInput{SenderKeyValue: "user", MessageValue: testSet.message})
+		if response != testSet.expected {
+			t.Errorf("Unexpected response is returned on test %d: %s.", i, response)
+		}
+
+		if triggered != testSet.triggered {
+			t.Errorf("Unexpected trigger state on test %d: %t.", i, triggered)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// JenkinsConfig contains some configuration variables for Jenkins.
+type JenkinsConfig struct {
+	// URL declares the base URL of the Jenkins server such as "https://jenkins.example.com/".
+	URL string `json:"url" yaml:"url"`
+
+	// User declares the user name to authenticate with.
+	User string `json:"user" yaml:"user"`
+
+	// APIToken declares the API token of the User.
+	APIToken string `json:"api_token" yaml:"api_token"`
+}
+
+// NewJenkinsConfig creates and returns a new JenkinsConfig instance with default settings.
+// Those values are empty at this point as there can not be default values.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank values.
+func NewJenkinsConfig() *JenkinsConfig {
+	return &JenkinsConfig{
+		URL:      "",
+		User:     "",
+		APIToken: "",
+	}
+}
+
+// Jenkins triggers parameterized Jenkins jobs via the remote access API.
+type Jenkins struct {
+	config *JenkinsConfig
+}
+
+var _ Trigger = (*Jenkins)(nil)
+
+// NewJenkins creates and returns a new Jenkins instance.
+func NewJenkins(config *JenkinsConfig) *Jenkins {
+	return &Jenkins{
+		config: config,
+	}
+}
+
+// Trigger starts a build of the given Jenkins job with the given parameters.
+func (j *Jenkins) Trigger(ctx context.Context, job string, params map[string]string) error {
+	endpoint, err := url.Parse(j.config.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse Jenkins URL: %w", err)
+	}
+	endpoint.Path = path.Join(endpoint.Path, "job", job, "buildWithParameters")
+
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, value)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint.String(), strings.NewReader(values.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to construct HTTP request: %w", err)
+	}
+	req.SetBasicAuth(j.config.User, j.config.APIToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(ctx)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed executing HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Jenkins responds with 201 Created and the queue item location on success.
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code is returned: %d", resp.StatusCode)
+	}
+
+	return nil
+}
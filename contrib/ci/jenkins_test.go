@@ -0,0 +1,67 @@
+package ci
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewJenkins(t *testing.T) {
+	config := NewJenkinsConfig()
+	jenkins := NewJenkins(config)
+
+	if jenkins.config != config {
+		t.Error("Given config is not set.")
+	}
+}
+
+func TestJenkins_Trigger(t *testing.T) {
+	testSets := []struct {
+		status int
+		hasErr bool
+	}{
+		{
+			status: http.StatusCreated,
+			hasErr: false,
+		},
+		{
+			status: http.StatusForbidden,
+			hasErr: true,
+		},
+	}
+
+	for i, testSet := range testSets {
+		status := testSet.status
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/job/deploy/buildWithParameters" {
+				t.Errorf("Unexpected path is requested on test %d: %s.", i, r.URL.Path)
+			}
+
+			user, token, ok := r.BasicAuth()
+			if !ok || user != "user" || token != "token" {
+				t.Errorf("Expected credential is not given on test %d.", i)
+			}
+
+			if r.FormValue("env") != "production" {
+				t.Errorf("Expected parameter is not given on test %d.", i)
+			}
+
+			w.WriteHeader(status)
+		}))
+
+		config := &JenkinsConfig{
+			URL:      server.URL,
+			User:     "user",
+			APIToken: "token",
+		}
+		err := NewJenkins(config).Trigger(context.TODO(), "deploy", map[string]string{"env": "production"})
+		server.Close()
+
+		if testSet.hasErr && err == nil {
+			t.Errorf("Expected error is not returned on test %d.", i)
+		} else if !testSet.hasErr && err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+		}
+	}
+}
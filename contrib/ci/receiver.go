@@ -0,0 +1,230 @@
+package ci
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib/github"
+	"github.com/oklahomer/go-sarah/v4/contrib/internal/webhook"
+	"net/http"
+	"time"
+)
+
+// errIgnored is returned when the given notification does not have to be relayed. e.g. a build is started but not completed.
+var errIgnored = errors.New("notification is ignored")
+
+// ErrCredentialNotSet is returned by NewReceiver when neither ReceiverConfig.Token nor ReceiverConfig.Secret is given.
+var ErrCredentialNotSet = errors.New("either token or secret must be set")
+
+// ReceiverConfig contains some configuration variables for Receiver.
+type ReceiverConfig struct {
+	// ListenPort declares the port number that receives build notifications.
+	ListenPort int `json:"listen_port" yaml:"listen_port"`
+
+	// Path declares the URL path that receives build notifications.
+	Path string `json:"path" yaml:"path"`
+
+	// Token declares the token that Jenkins must give as a "token" query parameter.
+	// When this is empty, notifications from Jenkins are rejected.
+	Token string `json:"token" yaml:"token"`
+
+	// Secret declares the GitHub webhook secret to verify workflow_run events.
+	// When this is empty, notifications from GitHub are rejected.
+	Secret string `json:"secret" yaml:"secret"`
+
+	// Destinations declares a list of destination IDs to send build results to.
+	Destinations []string `json:"destinations" yaml:"destinations"`
+
+	// Timeout declares the timeout to send notifications on each request. Zero value means no timeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// MaxBodySize declares the maximum size of a request body in bytes.
+	// A larger request is rejected with 413 Request Entity Too Large. Zero value means no limit.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size"`
+
+	// MaxConcurrentNotifications declares the maximum number of requests whose notifications are sent concurrently.
+	// A request that exceeds the limit is rejected with 503 Service Unavailable. Zero value means no limit.
+	MaxConcurrentNotifications int `json:"max_concurrent_notifications" yaml:"max_concurrent_notifications"`
+}
+
+// NewReceiverConfig creates and returns a new ReceiverConfig instance with default settings.
+// Token, Secret, and Destinations are empty at this point as there can not be default values; NewReceiver fails until Token or Secret is populated.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank value or override those default values.
+func NewReceiverConfig() *ReceiverConfig {
+	return &ReceiverConfig{
+		ListenPort:                 8082,
+		Path:                       "/ci",
+		Token:                      "",
+		Secret:                     "",
+		Destinations:               []string{},
+		Timeout:                    10 * time.Second,
+		MaxBodySize:                1 << 20,
+		MaxConcurrentNotifications: 10,
+	}
+}
+
+// Receiver receives build notifications and sends the results to the configured destinations.
+// Two forms of notifications are supported:
+//   - A JSON payload sent by Jenkins Notification Plugin.
+//   - A workflow_run event sent by GitHub webhook, which is identified by the X-GitHub-Event header.
+type Receiver struct {
+	config       *ReceiverConfig
+	bot          sarah.Bot
+	destinations []sarah.OutputDestination
+	webhook      *webhook.Receiver
+}
+
+var _ http.Handler = (*Receiver)(nil)
+
+// NewReceiver creates and returns a new Receiver instance.
+// bot is used to send notifications, and destination is called to convert the IDs given by ReceiverConfig.Destinations into sarah.OutputDestination.
+// ErrCredentialNotSet is returned when neither ReceiverConfig.Token nor ReceiverConfig.Secret is given.
+func NewReceiver(config *ReceiverConfig, bot sarah.Bot, destination func(string) sarah.OutputDestination) (*Receiver, error) {
+	if config.Token == "" && config.Secret == "" {
+		return nil, ErrCredentialNotSet
+	}
+
+	var dests []sarah.OutputDestination
+	for _, id := range config.Destinations {
+		dests = append(dests, destination(id))
+	}
+
+	r := &Receiver{
+		config:       config,
+		bot:          bot,
+		destinations: dests,
+	}
+	r.webhook = webhook.NewReceiver(&webhook.Config{
+		Name:                       "build notification",
+		ListenPort:                 config.ListenPort,
+		Path:                       config.Path,
+		Timeout:                    config.Timeout,
+		MaxBodySize:                config.MaxBodySize,
+		MaxConcurrentNotifications: config.MaxConcurrentNotifications,
+	}, bot, r.handle)
+	return r, nil
+}
+
+// Run starts an HTTP server to receive build notifications and blocks until the given context is canceled.
+// Use Receiver as an http.Handler to mount this on an existing HTTP server instead.
+func (r *Receiver) Run(ctx context.Context) error {
+	return r.webhook.Run(ctx)
+}
+
+// ServeHTTP receives a build notification, verifies it, and sends the formatted result to the configured destinations.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.webhook.ServeHTTP(w, req)
+}
+
+func (r *Receiver) handle(req *http.Request, body []byte) (*webhook.Notification, error) {
+	var message string
+	var err error
+	if eventType := req.Header.Get("X-GitHub-Event"); eventType != "" {
+		if r.config.Secret == "" {
+			return nil, fmt.Errorf("%w: secret is not set", webhook.ErrUnauthorized)
+		}
+		err = github.VerifySignature(r.config.Secret, req.Header.Get("X-Hub-Signature-256"), body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", webhook.ErrUnauthorized, err)
+		}
+		message, err = formatWorkflowRun(eventType, body)
+	} else {
+		if !r.validToken(req.URL.Query().Get("token")) {
+			return nil, fmt.Errorf("%w: invalid token", webhook.ErrUnauthorized)
+		}
+		message, err = formatJenkins(body)
+	}
+
+	if errors.Is(err, errIgnored) {
+		return nil, fmt.Errorf("%w: %w", webhook.ErrIgnored, err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &webhook.Notification{
+		Destinations: r.destinations,
+		Message:      message,
+	}, nil
+}
+
+func (r *Receiver) validToken(token string) bool {
+	if r.config.Token == "" {
+		// Reject every request rather than letting anyone post a notification.
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.config.Token), []byte(token)) == 1
+}
+
+// JenkinsNotification represents a payload sent by Jenkins Notification Plugin.
+type JenkinsNotification struct {
+	Name  string        `json:"name"`
+	Build *JenkinsBuild `json:"build"`
+}
+
+// JenkinsBuild represents a build given as part of JenkinsNotification.
+type JenkinsBuild struct {
+	FullURL string `json:"full_url"`
+	Number  int    `json:"number"`
+	Phase   string `json:"phase"`
+	Status  string `json:"status"`
+}
+
+// WorkflowRunEvent represents a payload of GitHub's workflow_run event.
+type WorkflowRunEvent struct {
+	Action      string       `json:"action"`
+	WorkflowRun *WorkflowRun `json:"workflow_run"`
+}
+
+// WorkflowRun represents a workflow run given as part of WorkflowRunEvent.
+type WorkflowRun struct {
+	Name       string `json:"name"`
+	HeadBranch string `json:"head_branch"`
+	RunNumber  int    `json:"run_number"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+}
+
+func formatJenkins(body []byte) (string, error) {
+	n := &JenkinsNotification{}
+	err := json.Unmarshal(body, n)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	if n.Build == nil {
+		return "", errors.New("build is not given")
+	}
+
+	// Relay the result only once the build is completed.
+	if n.Build.Phase != "COMPLETED" {
+		return "", errIgnored
+	}
+
+	return fmt.Sprintf("[%s] Build #%d finished with %s: %s", n.Name, n.Build.Number, n.Build.Status, n.Build.FullURL), nil
+}
+
+func formatWorkflowRun(eventType string, body []byte) (string, error) {
+	if eventType != "workflow_run" {
+		return "", errIgnored
+	}
+
+	e := &WorkflowRunEvent{}
+	err := json.Unmarshal(body, e)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	if e.WorkflowRun == nil {
+		return "", errors.New("workflow_run is not given")
+	}
+
+	if e.Action != "completed" {
+		return "", errIgnored
+	}
+
+	run := e.WorkflowRun
+	return fmt.Sprintf("[%s] Run #%d on %s finished with %s: %s", run.Name, run.RunNumber, run.HeadBranch, run.Conclusion, run.HTMLURL), nil
+}
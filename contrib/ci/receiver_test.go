@@ -0,0 +1,342 @@
+package ci
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyBot struct {
+	SendMessageFunc func(context.Context, sarah.Output)
+}
+
+func (bot *DummyBot) BotType() sarah.BotType {
+	return "dummy"
+}
+
+func (bot *DummyBot) Respond(_ context.Context, _ sarah.Input) error {
+	return nil
+}
+
+func (bot *DummyBot) SendMessage(ctx context.Context, output sarah.Output) {
+	bot.SendMessageFunc(ctx, output)
+}
+
+func (bot *DummyBot) AppendCommand(_ sarah.Command) {
+}
+
+func (bot *DummyBot) Run(_ context.Context, _ func(sarah.Input) error, _ func(error)) {
+}
+
+func sign(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func destination(id string) sarah.OutputDestination {
+	return id
+}
+
+func TestNewReceiver(t *testing.T) {
+	config := NewReceiverConfig()
+	_, err := NewReceiver(config, &DummyBot{}, destination)
+	if !errors.Is(err, ErrCredentialNotSet) {
+		t.Errorf("Expected error is not returned without credentials: %#v.", err)
+	}
+
+	config.Token = "token"
+	config.Destinations = []string{"C1"}
+	receiver, err := NewReceiver(config, &DummyBot{}, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(receiver.destinations) != 1 {
+		t.Errorf("Unexpected destinations are set: %#v.", receiver.destinations)
+	}
+}
+
+func TestNewReceiverConfig(t *testing.T) {
+	config := NewReceiverConfig()
+	if config == nil {
+		t.Fatal("Expected *ReceiverConfig is not returned.")
+	}
+
+	if config.Path == "" {
+		t.Error("Default path is not set.")
+	}
+}
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	jenkinsCompleted := `{"name":"deploy","build":{"full_url":"https://jenkins.example.com/job/deploy/1/","number":1,"phase":"COMPLETED","status":"SUCCESS"}}`
+	jenkinsStarted := `{"name":"deploy","build":{"number":1,"phase":"STARTED"}}`
+	workflowCompleted := `{"action":"completed","workflow_run":{"name":"CI","head_branch":"main","run_number":2,"conclusion":"failure"}}`
+
+	testSets := []struct {
+		method   string
+		target   string
+		event    string
+		secret   string
+		body     string
+		status   int
+		contains string
+	}{
+		{
+			method: http.MethodGet,
+			target: "/ci?token=token",
+			body:   jenkinsCompleted,
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			method: http.MethodPost,
+			target: "/ci?token=invalid",
+			body:   jenkinsCompleted,
+			status: http.StatusUnauthorized,
+		},
+		{
+			method: http.MethodPost,
+			target: "/ci?token=token",
+			body:   "invalid",
+			status: http.StatusBadRequest,
+		},
+		{
+			method: http.MethodPost,
+			target: "/ci?token=token",
+			body:   jenkinsStarted,
+			status: http.StatusOK,
+		},
+		{
+			method:   http.MethodPost,
+			target:   "/ci?token=token",
+			body:     jenkinsCompleted,
+			status:   http.StatusOK,
+			contains: "Build #1 finished with SUCCESS",
+		},
+		{
+			method: http.MethodPost,
+			target: "/ci",
+			event:  "ping",
+			secret: "secret",
+			body:   "{}",
+			status: http.StatusOK,
+		},
+		{
+			method: http.MethodPost,
+			target: "/ci",
+			event:  "workflow_run",
+			secret: "invalid",
+			body:   workflowCompleted,
+			status: http.StatusUnauthorized,
+		},
+		{
+			method:   http.MethodPost,
+			target:   "/ci",
+			event:    "workflow_run",
+			secret:   "secret",
+			body:     workflowCompleted,
+			status:   http.StatusOK,
+			contains: "Run #2 on main finished with failure",
+		},
+	}
+
+	for i, testSet := range testSets {
+		sent := make(chan sarah.Output, 1)
+		config := NewReceiverConfig()
+		config.Token = "token"
+		config.Secret = "secret"
+		config.Destinations = []string{"C1"}
+		bot := &DummyBot{
+			SendMessageFunc: func(_ context.Context, output sarah.Output) {
+				sent <- output
+			},
+		}
+		receiver, err := NewReceiver(config, bot, destination)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		req := httptest.NewRequest(testSet.method, testSet.target, strings.NewReader(testSet.body))
+		if testSet.event != "" {
+			req.Header.Set("X-GitHub-Event", testSet.event)
+			req.Header.Set("X-Hub-Signature-256", sign(testSet.secret, testSet.body))
+		}
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, req)
+
+		if recorder.Code != testSet.status {
+			t.Errorf("Unexpected status code is returned on test %d: %d.", i, recorder.Code)
+		}
+
+		if testSet.contains == "" {
+			continue
+		}
+
+		select {
+		case output := <-sent:
+			if output.Destination() != "C1" {
+				t.Errorf("Unexpected destination is given on test %d: %#v.", i, output.Destination())
+			}
+
+			message, _ := output.Content().(string)
+			if !strings.Contains(message, testSet.contains) {
+				t.Errorf("Unexpected message is sent on test %d: %s.", i, message)
+			}
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Errorf("Notification is not sent on test %d.", i)
+
+		}
+	}
+}
+
+func TestReceiver_ServeHTTP_WithoutCredential(t *testing.T) {
+	body := `{"action":"completed","workflow_run":{"name":"CI","head_branch":"main","run_number":2,"conclusion":"failure"}}`
+
+	testSets := []struct {
+		token  string
+		secret string
+		target string
+		event  string
+	}{
+		{
+			// Jenkins notification is rejected without token.
+			secret: "secret",
+			target: "/ci?token=",
+		},
+		{
+			// GitHub notification is rejected without secret.
+			token:  "token",
+			target: "/ci",
+			event:  "workflow_run",
+		},
+	}
+
+	for i, testSet := range testSets {
+		config := NewReceiverConfig()
+		config.Token = testSet.token
+		config.Secret = testSet.secret
+		bot := &DummyBot{
+			SendMessageFunc: func(_ context.Context, _ sarah.Output) {
+				t.Errorf("Notification should not be sent on test %d.", i)
+			},
+		}
+		receiver, err := NewReceiver(config, bot, destination)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		req := httptest.NewRequest(http.MethodPost, testSet.target, strings.NewReader(body))
+		if testSet.event != "" {
+			req.Header.Set("X-GitHub-Event", testSet.event)
+			req.Header.Set("X-Hub-Signature-256", sign("", body))
+		}
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Unexpected status code is returned on test %d: %d.", i, recorder.Code)
+		}
+	}
+}
+
+func TestReceiver_ServeHTTP_TooLarge(t *testing.T) {
+	config := NewReceiverConfig()
+	config.Token = "token"
+	config.MaxBodySize = 10
+	receiver, err := NewReceiver(config, &DummyBot{}, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/ci?token=token", strings.NewReader(`{"name":"deploy","build":{"phase":"COMPLETED"}}`))
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Unexpected status code is returned: %d.", recorder.Code)
+	}
+}
+
+func TestReceiver_ServeHTTP_TooManyNotifications(t *testing.T) {
+	body := `{"name":"deploy","build":{"full_url":"https://jenkins.example.com/job/deploy/1/","number":1,"phase":"COMPLETED","status":"SUCCESS"}}`
+
+	config := NewReceiverConfig()
+	config.Token = "token"
+	config.Destinations = []string{"C1"}
+	config.MaxConcurrentNotifications = 1
+	sending := make(chan struct{})
+	done := make(chan struct{})
+	bot := &DummyBot{
+		SendMessageFunc: func(_ context.Context, _ sarah.Output) {
+			sending <- struct{}{}
+			<-done
+		},
+	}
+	receiver, err := NewReceiver(config, bot, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/ci?token=token", strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if status := serve(); status != http.StatusOK {
+		t.Fatalf("Unexpected status code is returned: %d.", status)
+	}
+	<-sending
+
+	if status := serve(); status != http.StatusServiceUnavailable {
+		t.Errorf("Request exceeding the limit should be rejected: %d.", status)
+	}
+	close(done)
+}
+
+func TestReceiver_ServeHTTP_ZeroTimeout(t *testing.T) {
+	body := `{"name":"deploy","build":{"full_url":"https://jenkins.example.com/job/deploy/1/","number":1,"phase":"COMPLETED","status":"SUCCESS"}}`
+
+	config := NewReceiverConfig()
+	config.Token = "token"
+	config.Destinations = []string{"C1"}
+	config.Timeout = 0
+	ctxErr := make(chan error, 1)
+	bot := &DummyBot{
+		SendMessageFunc: func(ctx context.Context, _ sarah.Output) {
+			ctxErr <- ctx.Err()
+		},
+	}
+	receiver, err := NewReceiver(config, bot, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/ci?token=token", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected status code is returned: %d.", recorder.Code)
+	}
+
+	select {
+	case err := <-ctxErr:
+		if err != nil {
+			t.Errorf("Notification is sent with a context that is already done: %s.", err.Error())
+		}
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Error("Notification is not sent.")
+
+	}
+}
@@ -0,0 +1,119 @@
+// Package commands provides an administrative command to list the configuration states of the Commands and ScheduledTasks.
+//
+// The ".commands" command replies each Command and ScheduledTask that is built from CommandProps or ScheduledTaskProps
+// along with its configuration state reported via sarah.CurrentStatus, so operators can notice a plugin that is missing due to an unreadable configuration.
+//
+//	c := commands.New(slack.SLACK, commands.WithAuthorizer(func(input sarah.Input) bool {
+//		return input.SenderKey() == "admin"
+//	}))
+//	sarah.RegisterCommandProps(c.CommandProps())
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var matchPattern = regexp.MustCompile(`^\.commands\b`)
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*Commands)
+
+// WithAuthorizer creates and returns an Option that restricts who can view the states.
+// When the given function returns false, the states are not shown and the user is notified.
+// By default, no user can view the states.
+func WithAuthorizer(authorizer contrib.Authorizer) Option {
+	return func(c *Commands) {
+		c.authorize = authorizer
+	}
+}
+
+// Commands serves the ".commands" command.
+type Commands struct {
+	botType   sarah.BotType
+	authorize contrib.Authorizer
+	status    func() sarah.Status
+}
+
+// New creates and returns a new Commands instance.
+func New(botType sarah.BotType, options ...Option) *Commands {
+	c := &Commands{
+		botType:   botType,
+		authorize: contrib.DenyAll,
+		status:    sarah.CurrentStatus,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".commands" command.
+//
+//	.commands  replies the configuration states of the Commands and ScheduledTasks of the Bot.
+func (c *Commands) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(c.botType).
+		Identifier("commands").
+		Instruction("Input .commands to view the configuration states of the commands and scheduled tasks.").
+		MatchPattern(matchPattern).
+		Func(func(_ context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: c.handle(input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (c *Commands) handle(input sarah.Input) string {
+	if !contrib.Authorize(c.authorize, input) {
+		return "You are not allowed to view the commands."
+	}
+
+	var plugins []sarah.PluginStatus
+	for _, bot := range c.status().Bots {
+		if bot.Type == c.botType {
+			plugins = bot.Plugins
+			break
+		}
+	}
+	if len(plugins) == 0 {
+		return "No configurable command or scheduled task is registered."
+	}
+
+	lines := make([]string, 0, len(plugins))
+	failed := 0
+	for _, plugin := range plugins {
+		if !plugin.Loaded {
+			failed++
+		}
+		lines = append(lines, format(plugin))
+	}
+
+	header := fmt.Sprintf("%d plugins are registered.", len(plugins))
+	if failed > 0 {
+		header = fmt.Sprintf("%d plugins are registered and %d of them failed to load.", len(plugins), failed)
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+func format(plugin sarah.PluginStatus) string {
+	line := fmt.Sprintf("%s (%s): ", plugin.Identifier, plugin.Kind)
+	if plugin.Loaded {
+		line += fmt.Sprintf("loaded at %s", plugin.LoadedAt.Format(time.RFC3339))
+	} else {
+		line += fmt.Sprintf("FAILED: %s", plugin.Error)
+	}
+
+	if !plugin.ReloadedAt.IsZero() {
+		line += fmt.Sprintf(". last reloaded at %s", plugin.ReloadedAt.Format(time.RFC3339))
+	}
+	return line
+}
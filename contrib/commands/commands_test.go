@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.SenderKeyValue
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	c := New("dummy", func(_ *Commands) {
+		optCalled = true
+	})
+
+	if c == nil {
+		t.Fatal("Commands is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Given Option is not applied.")
+	}
+
+	if c.authorize(&DummyInput{}) {
+		t.Error("No user should be authorized by default.")
+	}
+}
+
+func TestWithAuthorizer(t *testing.T) {
+	c := &Commands{}
+	WithAuthorizer(func(_ sarah.Input) bool {
+		return false
+	})(c)
+
+	if c.authorize == nil {
+		t.Fatal("Given function is not set.")
+	}
+
+	if c.authorize(&DummyInput{}) {
+		t.Error("Unexpected function is set.")
+	}
+}
+
+func TestCommands_CommandProps(t *testing.T) {
+	props := New("dummy").CommandProps()
+
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestCommands_handle(t *testing.T) {
+	loadedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	testSets := []struct {
+		authorize bool
+		plugins   []sarah.PluginStatus
+		expected  []string
+	}{
+		{
+			authorize: false,
+			expected:  []string{"You are not allowed to view the commands."},
+		},
+		{
+			authorize: true,
+			expected:  []string{"No configurable command or scheduled task is registered."},
+		},
+		{
+			authorize: true,
+			plugins: []sarah.PluginStatus{
+				{
+					Identifier: "hello",
+					Kind:       sarah.PluginCommand,
+					Loaded:     true,
+					LoadedAt:   loadedAt,
+				},
+				{
+					Identifier: "weather",
+					Kind:       sarah.PluginScheduledTask,
+					Error:      "no configuration found",
+					ReloadedAt: loadedAt.Add(time.Hour),
+				},
+			},
+			expected: []string{
+				"2 plugins are registered and 1 of them failed to load.",
+				"hello (command): loaded at 2026-01-01T09:00:00Z",
+				"weather (scheduled task): FAILED: no configuration found. last reloaded at 2026-01-01T10:00:00Z",
+			},
+		},
+	}
+
+	for i, tt := range testSets {
+		c := &Commands{
+			botType: "dummy",
+			authorize: func(_ sarah.Input) bool {
+				return tt.authorize
+			},
+			status: func() sarah.Status {
+				return sarah.Status{
+					Bots: []sarah.BotStatus{
+						{Type: "other"},
+						{Type: "dummy", Plugins: tt.plugins},
+					},
+				}
+			},
+		}
+
+		response := c.handle(&DummyInput{MessageValue: ".commands"})
+		if response != strings.Join(tt.expected, "\n") {
+			t.Errorf("Unexpected response is returned on test #%d: %s.", i+1, response)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package debughttp provides HTTP endpoints to debug a running process such as a stuck worker in production.
+//
+// Mount registers the below endpoints to the given http.ServeMux, typically the one that serves the status endpoint.
+//
+//	/debug/pprof/      the profiles of net/http/pprof.
+//	/debug/goroutines  the stack traces of all current goroutines.
+//	/debug/inputs      the Inputs waiting in the worker's queue or being handled, and the worker statistics in JSON format.
+//
+// The endpoints expose the process internals and the users' messages, so they are mounted only when Config.Enabled is true
+// and every request must carry Config.Token as a bearer token.
+//
+//	curl -s -H "Authorization: Bearer $TOKEN" "http://localhost:8080/debug/inputs" | jq .
+package debughttp
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+	"strings"
+	"time"
+)
+
+// ErrTokenNotGiven is returned by Mount when the endpoints are enabled without a token.
+var ErrTokenNotGiven = errors.New("token is required to enable the debug endpoints")
+
+// Config contains some configuration variables for the debug endpoints.
+type Config struct {
+	// Enabled tells if the debug endpoints are mounted. The default value is false.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Token is the bearer token that every request to the debug endpoints must carry.
+	Token string `json:"token" yaml:"token"`
+}
+
+// NewConfig returns a new Config instance with the default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override default values.
+func NewConfig() *Config {
+	return &Config{
+		Enabled: false,
+		Token:   "",
+	}
+}
+
+// Mount registers the debug endpoints to the given http.ServeMux when Config.Enabled is true.
+// This returns ErrTokenNotGiven when the endpoints are enabled without a token.
+func Mount(mux *http.ServeMux, config *Config) error {
+	if !config.Enabled {
+		return nil
+	}
+	if config.Token == "" {
+		return ErrTokenNotGiven
+	}
+
+	auth := func(handler http.HandlerFunc) http.Handler {
+		return &authorizer{token: config.Token, next: handler}
+	}
+	mux.Handle("/debug/pprof/", auth(pprof.Index))
+	mux.Handle("/debug/pprof/cmdline", auth(pprof.Cmdline))
+	mux.Handle("/debug/pprof/profile", auth(pprof.Profile))
+	mux.Handle("/debug/pprof/symbol", auth(pprof.Symbol))
+	mux.Handle("/debug/pprof/trace", auth(pprof.Trace))
+	mux.Handle("/debug/goroutines", auth(serveGoroutines))
+	mux.Handle("/debug/inputs", auth(serveInputs))
+	return nil
+}
+
+// authorizer passes a request to the next handler only when the request carries the token.
+type authorizer struct {
+	token string
+	next  http.Handler
+}
+
+func (a *authorizer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	given := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(given), []byte(a.token)) != 1 {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	a.next.ServeHTTP(w, req)
+}
+
+func serveGoroutines(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	err := runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+	if err != nil {
+		logger.Errorf("Failed to dump goroutines: %+v", err)
+	}
+}
+
+type inputsJSON struct {
+	Worker *workerJSON  `json:"worker"`
+	Inputs []*inputJSON `json:"inputs"`
+}
+
+type workerJSON struct {
+	QueueDepth      int    `json:"queue_depth"`
+	RecoveredPanics uint64 `json:"recovered_panics"`
+}
+
+type inputJSON struct {
+	CorrelationID string        `json:"correlation_id"`
+	BotType       sarah.BotType `json:"bot_type"`
+	SenderKey     string        `json:"sender_key"`
+	Message       string        `json:"message"`
+	ReceivedAt    time.Time     `json:"received_at"`
+	StartedAt     *time.Time    `json:"started_at,omitempty"`
+}
+
+func serveInputs(w http.ResponseWriter, _ *http.Request) {
+	body := &inputsJSON{
+		Inputs: []*inputJSON{},
+	}
+	if stats := sarah.CurrentStatus().Worker; stats != nil {
+		body.Worker = &workerJSON{
+			QueueDepth:      stats.QueueDepth,
+			RecoveredPanics: stats.RecoveredPanics,
+		}
+	}
+	for _, queued := range sarah.InputQueueSnapshot() {
+		input := &inputJSON{
+			CorrelationID: queued.CorrelationID,
+			BotType:       queued.BotType,
+			SenderKey:     queued.SenderKey,
+			Message:       queued.Message,
+			ReceivedAt:    queued.ReceivedAt,
+		}
+		if !queued.StartedAt.IsZero() {
+			input.StartedAt = &queued.StartedAt
+		}
+		body.Inputs = append(body.Inputs, input)
+	}
+
+	bytes, err := json.Marshal(body)
+	if err != nil {
+		logger.Errorf("Failed to marshal input queue: %+v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bytes)
+}
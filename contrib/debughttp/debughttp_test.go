@@ -0,0 +1,91 @@
+package debughttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config.Enabled {
+		t.Error("Debug endpoints should be disabled by default.")
+	}
+}
+
+func TestMount(t *testing.T) {
+	mux := http.NewServeMux()
+	err := Mount(mux, NewConfig())
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/goroutines", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Disabled endpoint is mounted: %d.", recorder.Code)
+	}
+
+	err = Mount(http.NewServeMux(), &Config{Enabled: true})
+	if !errors.Is(err, ErrTokenNotGiven) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestMount_Enabled(t *testing.T) {
+	mux := http.NewServeMux()
+	err := Mount(mux, &Config{Enabled: true, Token: "secret"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	testSets := []struct {
+		path   string
+		token  string
+		status int
+	}{
+		{path: "/debug/goroutines", token: "", status: http.StatusUnauthorized},
+		{path: "/debug/goroutines", token: "wrong", status: http.StatusUnauthorized},
+		{path: "/debug/goroutines", token: "secret", status: http.StatusOK},
+		{path: "/debug/inputs", token: "secret", status: http.StatusOK},
+		{path: "/debug/pprof/", token: "secret", status: http.StatusOK},
+		{path: "/debug/pprof/", token: "", status: http.StatusUnauthorized},
+	}
+
+	for i, tt := range testSets {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		if recorder.Code != tt.status {
+			t.Errorf("Unexpected status is returned on test #%d: %d.", i+1, recorder.Code)
+		}
+	}
+}
+
+func Test_serveGoroutines(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	serveGoroutines(recorder, httptest.NewRequest(http.MethodGet, "/debug/goroutines", nil))
+
+	if !strings.Contains(recorder.Body.String(), "Test_serveGoroutines") {
+		t.Errorf("Current goroutine is not dumped: %s.", recorder.Body.String())
+	}
+}
+
+func Test_serveInputs(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	serveInputs(recorder, httptest.NewRequest(http.MethodGet, "/debug/inputs", nil))
+
+	body := &inputsJSON{}
+	err := json.Unmarshal(recorder.Body.Bytes(), body)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if body.Inputs == nil {
+		t.Errorf("Unexpected body is returned: %s.", recorder.Body.String())
+	}
+}
@@ -0,0 +1,3 @@
+// Package contrib and its sub packages provide ready-to-use plugins that are built on top of sarah's public interfaces.
+// Each plugin also serves as a reference to see how Command, ScheduledTask, and UserContext work together.
+package contrib
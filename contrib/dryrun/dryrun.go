@@ -0,0 +1,139 @@
+// Package dryrun provides an administrative command to toggle the dry-run mode at runtime.
+//
+// While the dry-run mode is enabled, Commands marked with sarah.CommandPropsBuilder.Mutating are not executed but return preview responses.
+// This is handy during an incident response or on testing configuration reloads.
+//
+//	.dryrun                replies the current dry-run status.
+//	.dryrun on             enables the dry-run mode for all Commands.
+//	.dryrun off            disables the dry-run mode for all Commands.
+//	.dryrun on deploy      enables the dry-run mode for the Command with the identifier of "deploy."
+//	.dryrun off deploy     disables the dry-run mode for the Command with the identifier of "deploy."
+//
+// Because the dry-run mode affects the whole process, the command is denied to everyone until an Authorizer that allows administrators is given with WithAuthorizer.
+//
+//	d := dryrun.New(slack.SLACK, dryrun.WithAuthorizer(func(input sarah.Input) bool {
+//		return input.SenderKey() == "admin"
+//	}))
+//	sarah.RegisterCommandProps(d.CommandProps())
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib"
+	"regexp"
+	"strings"
+)
+
+var matchPattern = regexp.MustCompile(`^\.dryrun\b`)
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*DryRun)
+
+// WithAuthorizer creates and returns an Option that restricts who can toggle the dry-run mode.
+// When the given function returns false, the dry-run mode is not changed and the user is notified.
+// By default, no user can toggle the dry-run mode.
+func WithAuthorizer(authorizer contrib.Authorizer) Option {
+	return func(d *DryRun) {
+		d.authorize = authorizer
+	}
+}
+
+// DryRun serves the ".dryrun" command.
+type DryRun struct {
+	botType     sarah.BotType
+	authorize   contrib.Authorizer
+	setGlobal   func(bool)
+	getGlobal   func() bool
+	setCommand  func(string, bool)
+	getCommands func() []string
+}
+
+// New creates and returns a new DryRun instance.
+func New(botType sarah.BotType, options ...Option) *DryRun {
+	d := &DryRun{
+		botType:     botType,
+		authorize:   contrib.DenyAll,
+		setGlobal:   sarah.SetDryRun,
+		getGlobal:   sarah.DryRun,
+		setCommand:  sarah.SetCommandDryRun,
+		getCommands: sarah.DryRunCommands,
+	}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	return d
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".dryrun" command.
+func (d *DryRun) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(d.botType).
+		Identifier("dryrun").
+		Instruction("Input .dryrun [on|off] [command] to view or toggle the dry-run mode.").
+		MatchPattern(matchPattern).
+		Func(func(_ context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: d.handle(input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (d *DryRun) handle(input sarah.Input) string {
+	if !contrib.Authorize(d.authorize, input) {
+		return "You are not allowed to change the dry-run mode."
+	}
+
+	args := strings.Fields(sarah.StripMessage(matchPattern, input.Message()))
+	if len(args) == 0 {
+		return d.status()
+	}
+
+	var enabled bool
+	switch strings.ToLower(args[0]) {
+	case "on":
+		enabled = true
+
+	case "off":
+		enabled = false
+
+	default:
+		return fmt.Sprintf("Unknown argument is given: %s. Input .dryrun [on|off] [command].", args[0])
+
+	}
+
+	switch len(args) {
+	case 1:
+		d.setGlobal(enabled)
+		return fmt.Sprintf("Dry-run mode is %s for all commands.", onOff(enabled))
+
+	case 2:
+		d.setCommand(args[1], enabled)
+		return fmt.Sprintf("Dry-run mode is %s for %s.", onOff(enabled), args[1])
+
+	default:
+		return "Too many arguments are given. Input .dryrun [on|off] [command]."
+
+	}
+}
+
+func (d *DryRun) status() string {
+	status := fmt.Sprintf("Dry-run mode is %s for all commands.", onOff(d.getGlobal()))
+
+	commands := d.getCommands()
+	if len(commands) == 0 {
+		return status
+	}
+	return fmt.Sprintf("%s\nDry-run mode is on for: %s.", status, strings.Join(commands, ", "))
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
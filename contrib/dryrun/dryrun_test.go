@@ -0,0 +1,177 @@
+package dryrun
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.SenderKeyValue
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	d := New("dummy", func(_ *DryRun) {
+		optCalled = true
+	})
+
+	if d == nil {
+		t.Fatal("DryRun is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Given Option is not applied.")
+	}
+
+	if d.authorize(&DummyInput{}) {
+		t.Error("No user should be authorized by default.")
+	}
+}
+
+func TestWithAuthorizer(t *testing.T) {
+	d := &DryRun{}
+	WithAuthorizer(func(_ sarah.Input) bool {
+		return false
+	})(d)
+
+	if d.authorize == nil {
+		t.Fatal("Given function is not set.")
+	}
+
+	if d.authorize(&DummyInput{}) {
+		t.Error("Unexpected function is set.")
+	}
+}
+
+func TestDryRun_CommandProps(t *testing.T) {
+	props := New("dummy").CommandProps()
+
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestDryRun_handle(t *testing.T) {
+	testSets := []struct {
+		message   string
+		authorize bool
+		global    bool
+		commands  []string
+		expected  string
+	}{
+		{
+			message:   ".dryrun",
+			authorize: true,
+			expected:  "Dry-run mode is off for all commands.",
+		},
+		{
+			message:   ".dryrun ON",
+			authorize: true,
+			global:    true,
+			expected:  "Dry-run mode is on for all commands.",
+		},
+		{
+			message:   ".dryrun on deploy",
+			authorize: true,
+			commands:  []string{"deploy"},
+			expected:  "Dry-run mode is on for deploy.",
+		},
+		{
+			message:   ".dryrun off deploy",
+			authorize: true,
+			expected:  "Dry-run mode is off for deploy.",
+		},
+		{
+			message:   ".dryrun maybe",
+			authorize: true,
+			expected:  "Unknown argument is given: maybe. Input .dryrun [on|off] [command].",
+		},
+		{
+			message:   ".dryrun on deploy restart",
+			authorize: true,
+			expected:  "Too many arguments are given. Input .dryrun [on|off] [command].",
+		},
+		{
+			message:   ".dryrun on",
+			authorize: false,
+			expected:  "You are not allowed to change the dry-run mode.",
+		},
+	}
+
+	for i, tt := range testSets {
+		global := false
+		commands := map[string]bool{}
+		d := &DryRun{
+			authorize: func(_ sarah.Input) bool {
+				return tt.authorize
+			},
+			setGlobal: func(enabled bool) {
+				global = enabled
+			},
+			getGlobal: func() bool {
+				return global
+			},
+			setCommand: func(identifier string, enabled bool) {
+				if enabled {
+					commands[identifier] = true
+					return
+				}
+				delete(commands, identifier)
+			},
+			getCommands: func() []string {
+				return nil
+			},
+		}
+
+		content := d.handle(&DummyInput{MessageValue: tt.message})
+		if content != tt.expected {
+			t.Errorf("Unexpected content is returned on test #%d: %s.", i+1, content)
+		}
+		if global != tt.global {
+			t.Errorf("Unexpected global flag is set on test #%d: %t.", i+1, global)
+		}
+		if len(commands) != len(tt.commands) {
+			t.Errorf("Unexpected commands are set on test #%d: %#v.", i+1, commands)
+		}
+		for _, identifier := range tt.commands {
+			if !commands[identifier] {
+				t.Errorf("Expected command is not set on test #%d: %s.", i+1, identifier)
+			}
+		}
+	}
+}
+
+func TestDryRun_status(t *testing.T) {
+	d := &DryRun{
+		getGlobal: func() bool {
+			return true
+		},
+		getCommands: func() []string {
+			return []string{"deploy", "restart"}
+		},
+	}
+
+	status := d.status()
+	expected := "Dry-run mode is on for all commands.\nDry-run mode is on for: deploy, restart."
+	if status != expected {
+		t.Errorf("Unexpected status is returned: %s.", status)
+	}
+}
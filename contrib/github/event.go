@@ -0,0 +1,145 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedEvent is returned when the given event type is not supported by this package.
+var ErrUnsupportedEvent = errors.New("unsupported event")
+
+// Repository represents a repository given as part of a webhook payload.
+type Repository struct {
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// User represents a user given as part of a webhook payload.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Commit represents a commit given as part of a push event payload.
+type Commit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
+
+// PushEvent represents a payload of the push event.
+type PushEvent struct {
+	Ref        string      `json:"ref"`
+	Compare    string      `json:"compare"`
+	Commits    []*Commit   `json:"commits"`
+	Repository *Repository `json:"repository"`
+	Sender     *User       `json:"sender"`
+}
+
+// PullRequest represents a pull request given as part of a pull_request event payload.
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	Merged  bool   `json:"merged"`
+}
+
+// PullRequestEvent represents a payload of the pull_request event.
+type PullRequestEvent struct {
+	Action      string       `json:"action"`
+	PullRequest *PullRequest `json:"pull_request"`
+	Repository  *Repository  `json:"repository"`
+	Sender      *User        `json:"sender"`
+}
+
+// Issue represents an issue given as part of an issues event payload.
+type Issue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+}
+
+// IssuesEvent represents a payload of the issues event.
+type IssuesEvent struct {
+	Action     string      `json:"action"`
+	Issue      *Issue      `json:"issue"`
+	Repository *Repository `json:"repository"`
+	Sender     *User       `json:"sender"`
+}
+
+// FormatEvent decodes the given payload based on the event type and returns the repository's full name and a notification message.
+// Supported event types are push, pull_request, and issues; ErrUnsupportedEvent is returned for other types.
+func FormatEvent(eventType string, body []byte) (string, string, error) {
+	switch eventType {
+	case "push":
+		e := &PushEvent{}
+		if err := decode(body, e); err != nil {
+			return "", "", err
+		}
+		if e.Repository == nil || e.Sender == nil {
+			return "", "", errors.New("repository or sender is not given")
+		}
+		return e.Repository.FullName, formatPush(e), nil
+
+	case "pull_request":
+		e := &PullRequestEvent{}
+		if err := decode(body, e); err != nil {
+			return "", "", err
+		}
+		if e.Repository == nil || e.Sender == nil || e.PullRequest == nil {
+			return "", "", errors.New("repository, sender, or pull_request is not given")
+		}
+		return e.Repository.FullName, formatPullRequest(e), nil
+
+	case "issues":
+		e := &IssuesEvent{}
+		if err := decode(body, e); err != nil {
+			return "", "", err
+		}
+		if e.Repository == nil || e.Sender == nil || e.Issue == nil {
+			return "", "", errors.New("repository, sender, or issue is not given")
+		}
+		return e.Repository.FullName, formatIssue(e), nil
+
+	default:
+		return "", "", ErrUnsupportedEvent
+
+	}
+}
+
+func formatPush(e *PushEvent) string {
+	branch := strings.TrimPrefix(e.Ref, "refs/heads/")
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[%s] %s pushed %d commit(s) to %s: %s", e.Repository.FullName, e.Sender.Login, len(e.Commits), branch, e.Compare))
+	for _, c := range e.Commits {
+		// Only the first line of the commit message is relevant.
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		id := c.ID
+		if len(id) > 7 {
+			id = id[:7]
+		}
+		sb.WriteString(fmt.Sprintf("\n- %s %s", id, subject))
+	}
+	return sb.String()
+}
+
+func formatPullRequest(e *PullRequestEvent) string {
+	action := e.Action
+	if action == "closed" && e.PullRequest.Merged {
+		action = "merged"
+	}
+	return fmt.Sprintf("[%s] %s %s pull request #%d: %s %s", e.Repository.FullName, e.Sender.Login, action, e.PullRequest.Number, e.PullRequest.Title, e.PullRequest.HTMLURL)
+}
+
+func formatIssue(e *IssuesEvent) string {
+	return fmt.Sprintf("[%s] %s %s issue #%d: %s %s", e.Repository.FullName, e.Sender.Login, e.Action, e.Issue.Number, e.Issue.Title, e.Issue.HTMLURL)
+}
+
+func decode(body []byte, v interface{}) error {
+	err := json.Unmarshal(body, v)
+	if err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatEvent(t *testing.T) {
+	testSets := []struct {
+		eventType string
+		body      string
+		repo      string
+		contains  string
+		err       bool
+	}{
+		{
+			eventType: "push",
+			body:      `{"ref":"refs/heads/main","compare":"https://example.com/compare","commits":[{"id":"0123456789","message":"Fix bug\n\nDetails"}],"repository":{"full_name":"oklahomer/go-sarah"},"sender":{"login":"oklahomer"}}`,
+			repo:      "oklahomer/go-sarah",
+			contains:  "- 0123456 Fix bug",
+		},
+		{
+			eventType: "pull_request",
+			body:      `{"action":"closed","pull_request":{"number":2,"title":"Feature","merged":true},"repository":{"full_name":"oklahomer/go-sarah"},"sender":{"login":"oklahomer"}}`,
+			repo:      "oklahomer/go-sarah",
+			contains:  "merged pull request #2",
+		},
+		{
+			eventType: "issues",
+			body:      `{"action":"opened","issue":{"number":3,"title":"Bug"},"repository":{"full_name":"oklahomer/go-sarah"},"sender":{"login":"oklahomer"}}`,
+			repo:      "oklahomer/go-sarah",
+			contains:  "opened issue #3",
+		},
+		{
+			eventType: "issues",
+			body:      `{"action":"opened"}`,
+			err:       true,
+		},
+		{
+			eventType: "push",
+			body:      `invalid`,
+			err:       true,
+		},
+	}
+
+	for i, testSet := range testSets {
+		repo, message, err := FormatEvent(testSet.eventType, []byte(testSet.body))
+		if testSet.err {
+			if err == nil {
+				t.Errorf("Expected error is not returned on test %d.", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+			continue
+		}
+
+		if repo != testSet.repo {
+			t.Errorf("Unexpected repository is returned on test %d: %s.", i, repo)
+		}
+
+		if !strings.Contains(message, testSet.contains) {
+			t.Errorf("Unexpected message is returned on test %d: %s.", i, message)
+		}
+	}
+}
+
+func TestFormatEvent_Unsupported(t *testing.T) {
+	_, _, err := FormatEvent("ping", []byte("{}"))
+	if !errors.Is(err, ErrUnsupportedEvent) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
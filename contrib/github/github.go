@@ -0,0 +1,304 @@
+// Package github provides a GitHub webhook integration that relays repository events to chat destinations.
+//
+// Receiver works as an http.Handler that receives GitHub webhook requests, verifies their signatures,
+// and sends formatted notifications to the subscribing destinations through the given sarah.Bot.
+// Destinations subscribe to a repository with the ".github subscribe owner/repo" command or with Config.Subscriptions.
+//
+//	config := github.NewConfig()
+//	config.Secret = "my webhook secret"
+//	receiver, err := github.NewReceiver(config, slackBot, func(id string) sarah.OutputDestination {
+//		return event.ChannelID(id)
+//	})
+//	if err != nil {
+//		panic(err)
+//	}
+//	sarah.RegisterCommandProps(receiver.CommandProps())
+//	go receiver.Run(ctx)
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib/internal/webhook"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	matchPattern = regexp.MustCompile(`^\.github\b`)
+
+	// ErrInvalidSignature is returned when the request signature does not match with the computed one.
+	ErrInvalidSignature = errors.New("signature does not match")
+
+	// ErrSecretNotSet is returned when the webhook secret to verify the request signature is not given.
+	ErrSecretNotSet = errors.New("webhook secret is not set")
+)
+
+// Config contains some configuration variables for the GitHub webhook integration.
+type Config struct {
+	// ListenPort declares the port number that receives webhook requests from GitHub.
+	ListenPort int `json:"listen_port" yaml:"listen_port"`
+
+	// Path declares the URL path that receives webhook requests.
+	Path string `json:"path" yaml:"path"`
+
+	// Secret declares the webhook secret to verify X-Hub-Signature-256 header.
+	// This is required since anyone who can reach the endpoint could otherwise post forged events.
+	Secret string `json:"secret" yaml:"secret"`
+
+	// Subscriptions declares pre-defined subscriptions.
+	// Each key represents a repository's full name such as "oklahomer/go-sarah" and its value represents a list of destination IDs.
+	Subscriptions map[string][]string `json:"subscriptions" yaml:"subscriptions"`
+
+	// Timeout declares the timeout to send notifications on each webhook request. Zero value means no timeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// MaxBodySize declares the maximum size of a webhook request body in bytes.
+	// A larger request is rejected with 413 Request Entity Too Large. Zero value means no limit.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size"`
+
+	// MaxConcurrentNotifications declares the maximum number of webhook requests whose notifications are sent concurrently.
+	// A request that exceeds the limit is rejected with 503 Service Unavailable so it can be redelivered from GitHub.
+	// Zero value means no limit.
+	MaxConcurrentNotifications int `json:"max_concurrent_notifications" yaml:"max_concurrent_notifications"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Secret is empty at this point as there can not be a default value; NewReceiver fails until it is populated.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank value or override those default values.
+func NewConfig() *Config {
+	return &Config{
+		ListenPort:                 8081,
+		Path:                       "/github",
+		Secret:                     "",
+		Subscriptions:              map[string][]string{},
+		Timeout:                    10 * time.Second,
+		MaxBodySize:                1 << 20,
+		MaxConcurrentNotifications: 10,
+	}
+}
+
+// Receiver receives GitHub webhook requests and sends notifications to the subscribing destinations.
+type Receiver struct {
+	config        *Config
+	bot           sarah.Bot
+	subscriptions *subscriptions
+	webhook       *webhook.Receiver
+}
+
+var _ http.Handler = (*Receiver)(nil)
+
+// NewReceiver creates and returns a new Receiver instance.
+// bot is used to send notifications, and destination is called to convert the destination IDs given by Config.Subscriptions into sarah.OutputDestination.
+// ErrSecretNotSet is returned when Config.Secret is empty.
+func NewReceiver(config *Config, bot sarah.Bot, destination func(string) sarah.OutputDestination) (*Receiver, error) {
+	if config.Secret == "" {
+		return nil, ErrSecretNotSet
+	}
+
+	s := &subscriptions{
+		repositories: map[string][]sarah.OutputDestination{},
+	}
+	for repo, ids := range config.Subscriptions {
+		for _, id := range ids {
+			s.subscribe(repo, destination(id))
+		}
+	}
+
+	r := &Receiver{
+		config:        config,
+		bot:           bot,
+		subscriptions: s,
+	}
+	r.webhook = webhook.NewReceiver(&webhook.Config{
+		Name:                       "GitHub",
+		ListenPort:                 config.ListenPort,
+		Path:                       config.Path,
+		Timeout:                    config.Timeout,
+		MaxBodySize:                config.MaxBodySize,
+		MaxConcurrentNotifications: config.MaxConcurrentNotifications,
+	}, bot, r.handle)
+	return r, nil
+}
+
+// Run starts an HTTP server to receive webhook requests and blocks until the given context is canceled.
+// Use Receiver as an http.Handler to mount this on an existing HTTP server instead.
+func (r *Receiver) Run(ctx context.Context) error {
+	return r.webhook.Run(ctx)
+}
+
+// ServeHTTP receives a webhook request, verifies it, and sends the formatted notification to the subscribing destinations.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.webhook.ServeHTTP(w, req)
+}
+
+func (r *Receiver) handle(req *http.Request, body []byte) (*webhook.Notification, error) {
+	err := VerifySignature(r.config.Secret, req.Header.Get("X-Hub-Signature-256"), body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", webhook.ErrUnauthorized, err)
+	}
+
+	repo, message, err := FormatEvent(req.Header.Get("X-GitHub-Event"), body)
+	if errors.Is(err, ErrUnsupportedEvent) {
+		// Respond with 200 so GitHub does not consider this a delivery failure.
+		return nil, fmt.Errorf("%w: %w", webhook.ErrIgnored, err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &webhook.Notification{
+		Destinations: r.subscriptions.destinations(repo),
+		Message:      message,
+	}, nil
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".github" command.
+// The command lets users subscribe or unsubscribe the current destination to a repository as below:
+//
+//	.github subscribe oklahomer/go-sarah
+//	.github unsubscribe oklahomer/go-sarah
+//	.github list
+func (r *Receiver) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(r.bot.BotType()).
+		Identifier("github").
+		Instruction("Input .github subscribe owner/repo or .github unsubscribe owner/repo to manage GitHub notifications.").
+		MatchPattern(matchPattern).
+		Func(func(_ context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: r.handleCommand(input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (r *Receiver) handleCommand(input sarah.Input) string {
+	fields := strings.Fields(sarah.StripMessage(matchPattern, input.Message()))
+	if len(fields) == 1 && fields[0] == "list" {
+		repos := r.subscriptions.repositoriesOf(input.ReplyTo())
+		if len(repos) == 0 {
+			return "No repository is subscribed."
+		}
+		return fmt.Sprintf("Subscribing repositories: %s", strings.Join(repos, ", "))
+	}
+
+	if len(fields) != 2 {
+		return "Usage: .github subscribe owner/repo, .github unsubscribe owner/repo, or .github list"
+	}
+
+	repo := fields[1]
+	switch fields[0] {
+	case "subscribe":
+		r.subscriptions.subscribe(repo, input.ReplyTo())
+		return fmt.Sprintf("Subscribed to %s.", repo)
+
+	case "unsubscribe":
+		r.subscriptions.unsubscribe(repo, input.ReplyTo())
+		return fmt.Sprintf("Unsubscribed from %s.", repo)
+
+	default:
+		return fmt.Sprintf("Unknown sub command: %s.", fields[0])
+
+	}
+}
+
+// VerifySignature checks if the given signature matches with the HMAC-SHA256 hex digest of the payload.
+// The signature is expected to be given in the form of X-Hub-Signature-256 header value: "sha256=<hex digest>".
+// ErrSecretNotSet is returned when secret is empty so a misconfiguration does not let forged requests pass.
+func VerifySignature(secret string, signature string, payload []byte) error {
+	if secret == "" {
+		return ErrSecretNotSet
+	}
+
+	given, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	decoded, err := hex.DecodeString(given)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	if !hmac.Equal(decoded, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// subscriptions stashes the destinations that subscribe to each repository.
+// Calls to its methods are thread-safe.
+type subscriptions struct {
+	repositories map[string][]sarah.OutputDestination
+	mutex        sync.RWMutex
+}
+
+func (s *subscriptions) subscribe(repo string, dest sarah.OutputDestination) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, d := range s.repositories[repo] {
+		if reflect.DeepEqual(d, dest) {
+			// Already subscribing.
+			return
+		}
+	}
+	s.repositories[repo] = append(s.repositories[repo], dest)
+}
+
+func (s *subscriptions) unsubscribe(repo string, dest sarah.OutputDestination) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var remains []sarah.OutputDestination
+	for _, d := range s.repositories[repo] {
+		if !reflect.DeepEqual(d, dest) {
+			remains = append(remains, d)
+		}
+	}
+
+	if len(remains) == 0 {
+		delete(s.repositories, repo)
+		return
+	}
+	s.repositories[repo] = remains
+}
+
+func (s *subscriptions) destinations(repo string) []sarah.OutputDestination {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	dests := make([]sarah.OutputDestination, len(s.repositories[repo]))
+	copy(dests, s.repositories[repo])
+	return dests
+}
+
+func (s *subscriptions) repositoriesOf(dest sarah.OutputDestination) []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var repos []string
+	for repo, dests := range s.repositories {
+		for _, d := range dests {
+			if reflect.DeepEqual(d, dest) {
+				repos = append(repos, repo)
+				break
+			}
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
@@ -0,0 +1,382 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyBot struct {
+	SendMessageFunc func(context.Context, sarah.Output)
+}
+
+func (bot *DummyBot) BotType() sarah.BotType {
+	return "dummy"
+}
+
+func (bot *DummyBot) Respond(_ context.Context, _ sarah.Input) error {
+	return nil
+}
+
+func (bot *DummyBot) SendMessage(ctx context.Context, output sarah.Output) {
+	bot.SendMessageFunc(ctx, output)
+}
+
+func (bot *DummyBot) AppendCommand(_ sarah.Command) {
+}
+
+func (bot *DummyBot) Run(_ context.Context, _ func(sarah.Input) error, _ func(error)) {
+}
+
+type DummyInput struct {
+	MessageValue string
+	ReplyToValue sarah.OutputDestination
+}
+
+func (i *DummyInput) SenderKey() string {
+	return "sender"
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.ReplyToValue
+}
+
+func destination(id string) sarah.OutputDestination {
+	return id
+}
+
+func newTestReceiver(t *testing.T) *Receiver {
+	config := NewConfig()
+	config.Secret = "secret"
+	receiver, err := NewReceiver(config, &DummyBot{}, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	return receiver
+}
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config == nil {
+		t.Fatal("Expected *Config is not returned.")
+	}
+
+	if config.Path == "" {
+		t.Error("Default path is not set.")
+	}
+}
+
+func TestNewReceiver(t *testing.T) {
+	config := NewConfig()
+	config.Subscriptions = map[string][]string{
+		"oklahomer/go-sarah": {"C1", "C2"},
+	}
+
+	_, err := NewReceiver(config, &DummyBot{}, destination)
+	if !errors.Is(err, ErrSecretNotSet) {
+		t.Errorf("Expected error is not returned without secret: %#v.", err)
+	}
+
+	config.Secret = "secret"
+	receiver, err := NewReceiver(config, &DummyBot{}, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	dests := receiver.subscriptions.destinations("oklahomer/go-sarah")
+	if len(dests) != 2 {
+		t.Errorf("Expected number of destinations are not set: %d.", len(dests))
+	}
+}
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	secret := "secret"
+	payload := []byte(`{"action":"opened","issue":{"number":1,"title":"Bug","html_url":"https://example.com/1"},"repository":{"full_name":"oklahomer/go-sarah"},"sender":{"login":"oklahomer"}}`)
+
+	testSets := []struct {
+		method    string
+		event     string
+		signature string
+		status    int
+		sent      bool
+	}{
+		{
+			method:    http.MethodGet,
+			event:     "issues",
+			signature: sign(secret, payload),
+			status:    http.StatusMethodNotAllowed,
+		},
+		{
+			method:    http.MethodPost,
+			event:     "issues",
+			signature: "sha256=invalid",
+			status:    http.StatusUnauthorized,
+		},
+		{
+			method:    http.MethodPost,
+			event:     "ping",
+			signature: sign(secret, payload),
+			status:    http.StatusOK,
+		},
+		{
+			method:    http.MethodPost,
+			event:     "issues",
+			signature: sign(secret, payload),
+			status:    http.StatusOK,
+			sent:      true,
+		},
+	}
+
+	for i, testSet := range testSets {
+		sent := make(chan sarah.Output, 1)
+		config := NewConfig()
+		config.Secret = secret
+		config.Subscriptions = map[string][]string{
+			"oklahomer/go-sarah": {"C1"},
+		}
+		bot := &DummyBot{
+			SendMessageFunc: func(_ context.Context, output sarah.Output) {
+				sent <- output
+			},
+		}
+		receiver, err := NewReceiver(config, bot, destination)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		req := httptest.NewRequest(testSet.method, "/github", strings.NewReader(string(payload)))
+		req.Header.Set("X-GitHub-Event", testSet.event)
+		req.Header.Set("X-Hub-Signature-256", testSet.signature)
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, req)
+
+		if recorder.Code != testSet.status {
+			t.Errorf("Unexpected status code is returned on test %d: %d.", i, recorder.Code)
+		}
+
+		if !testSet.sent {
+			continue
+		}
+
+		select {
+		case output := <-sent:
+			if output.Destination() != "C1" {
+				t.Errorf("Unexpected destination is given on test %d: %#v.", i, output.Destination())
+			}
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Errorf("Notification is not sent on test %d.", i)
+
+		}
+	}
+}
+
+func TestReceiver_ServeHTTP_TooLarge(t *testing.T) {
+	config := NewConfig()
+	config.Secret = "secret"
+	config.MaxBodySize = 10
+	receiver, err := NewReceiver(config, &DummyBot{}, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	payload := []byte(`{"repository":{"full_name":"oklahomer/go-sarah"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/github", strings.NewReader(string(payload)))
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-Hub-Signature-256", sign("secret", payload))
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Unexpected status code is returned: %d.", recorder.Code)
+	}
+}
+
+func TestReceiver_ServeHTTP_TooManyNotifications(t *testing.T) {
+	secret := "secret"
+	payload := []byte(`{"action":"opened","issue":{"number":1,"title":"Bug","html_url":"https://example.com/1"},"repository":{"full_name":"oklahomer/go-sarah"},"sender":{"login":"oklahomer"}}`)
+
+	config := NewConfig()
+	config.Secret = secret
+	config.MaxConcurrentNotifications = 1
+	config.Subscriptions = map[string][]string{
+		"oklahomer/go-sarah": {"C1"},
+	}
+	sending := make(chan struct{}, 1)
+	done := make(chan struct{})
+	bot := &DummyBot{
+		SendMessageFunc: func(_ context.Context, _ sarah.Output) {
+			sending <- struct{}{}
+			<-done
+		},
+	}
+	receiver, err := NewReceiver(config, bot, destination)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/github", strings.NewReader(string(payload)))
+		req.Header.Set("X-GitHub-Event", "issues")
+		req.Header.Set("X-Hub-Signature-256", sign(secret, payload))
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	if status := serve(); status != http.StatusOK {
+		t.Fatalf("Unexpected status code is returned: %d.", status)
+	}
+	<-sending
+
+	if status := serve(); status != http.StatusServiceUnavailable {
+		t.Errorf("Request exceeding the limit should be rejected: %d.", status)
+	}
+
+	close(done)
+	deadline := time.Now().Add(1 * time.Second)
+	for serve() != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("Slot is not released.")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReceiver_CommandProps(t *testing.T) {
+	receiver := newTestReceiver(t)
+
+	props := receiver.CommandProps()
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestReceiver_handleCommand(t *testing.T) {
+	receiver := newTestReceiver(t)
+
+	testSets := []struct {
+		message  string
+		expected string
+	}{
+		{
+			message:  ".github list",
+			expected: "No repository is subscribed.",
+		},
+		{
+			message:  ".github subscribe oklahomer/go-sarah",
+			expected: "Subscribed to oklahomer/go-sarah.",
+		},
+		{
+			message:  ".github list",
+			expected: "Subscribing repositories: oklahomer/go-sarah",
+		},
+		{
+			message:  ".github unsubscribe oklahomer/go-sarah",
+			expected: "Unsubscribed from oklahomer/go-sarah.",
+		},
+		{
+			message:  ".github foo oklahomer/go-sarah",
+			expected: "Unknown sub command: foo.",
+		},
+		{
+			message:  ".github",
+			expected: "Usage: .github subscribe owner/repo, .github unsubscribe owner/repo, or .github list",
+		},
+	}
+
+	for i, testSet := range testSets {
+		input := &DummyInput{MessageValue: testSet.message, ReplyToValue: "C1"}
+		response := receiver.handleCommand(input)
+		if response != testSet.expected {
+			t.Errorf("Unexpected response is returned on test %d: %s.", i, response)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte("payload")
+	testSets := []struct {
+		secret    string
+		signature string
+		valid     bool
+	}{
+		{
+			secret:    "secret",
+			signature: sign("secret", payload),
+			valid:     true,
+		},
+		{
+			secret:    "secret",
+			signature: sign("other", payload),
+			valid:     false,
+		},
+		{
+			secret:    "secret",
+			signature: "sha1=foo",
+			valid:     false,
+		},
+		{
+			secret:    "secret",
+			signature: "sha256=not hex",
+			valid:     false,
+		},
+	}
+
+	for i, testSet := range testSets {
+		err := VerifySignature(testSet.secret, testSet.signature, payload)
+		if testSet.valid && err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+		} else if !testSet.valid && err != ErrInvalidSignature {
+			t.Errorf("Expected error is not returned on test %d: %#v.", i, err)
+		}
+	}
+
+	err := VerifySignature("", "", payload)
+	if !errors.Is(err, ErrSecretNotSet) {
+		t.Errorf("Verification should fail without secret: %#v.", err)
+	}
+}
+
+func Test_subscriptions(t *testing.T) {
+	s := &subscriptions{repositories: map[string][]sarah.OutputDestination{}}
+	s.subscribe("repo", "C1")
+	s.subscribe("repo", "C1")
+	s.subscribe("repo", "C2")
+
+	if len(s.destinations("repo")) != 2 {
+		t.Fatalf("Duplicated subscription is stored: %#v.", s.repositories)
+	}
+
+	s.unsubscribe("repo", "C1")
+	if len(s.destinations("repo")) != 1 {
+		t.Fatalf("Subscription is not removed: %#v.", s.repositories)
+	}
+
+	s.unsubscribe("repo", "C2")
+	if _, ok := s.repositories["repo"]; ok {
+		t.Error("Repository without subscriber should be removed.")
+	}
+}
@@ -0,0 +1,104 @@
+// Package history provides a command, ".history [n]", that returns the last n messages of the channel.
+//
+// The messages are read from sarah.TranscriptStore, so the same store must be registered via sarah.RegisterTranscriptStore
+// to populate the transcript.
+//
+//	store := sarah.NewInMemoryTranscriptStore(100)
+//	sarah.RegisterTranscriptStore(store)
+//	sarah.RegisterCommandProps(history.NewCommandProps(slack.SLACK, store, history.NewConfig()))
+package history
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var matchPattern = regexp.MustCompile(`^\.history(\s+\d+)?\s*$`)
+
+// Config contains some configuration variables for the history command.
+type Config struct {
+	// DefaultCount declares the number of messages to return when the count is not given.
+	DefaultCount int `json:"default_count" yaml:"default_count"`
+
+	// MaxCount declares the maximum number of messages to return.
+	MaxCount int `json:"max_count" yaml:"max_count"`
+
+	// TimeFormat declares the layout to format each message's timestamp. See time.Layout for details.
+	TimeFormat string `json:"time_format" yaml:"time_format"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		DefaultCount: 10,
+		MaxCount:     50,
+		TimeFormat:   "15:04",
+	}
+}
+
+// NewCommandProps builds and returns a sarah.CommandProps that returns the recent messages of the channel stored in the given sarah.TranscriptStore.
+func NewCommandProps(botType sarah.BotType, store sarah.TranscriptStore, config *Config) *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(botType).
+		Identifier("history").
+		Instruction("Input .history [n] to see the last n messages in this channel.").
+		MatchPattern(matchPattern).
+		Func(func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return execute(ctx, botType, store, config, input)
+		}).
+		MustBuild()
+}
+
+func execute(ctx context.Context, botType sarah.BotType, store sarah.TranscriptStore, config *Config, input sarah.Input) (*sarah.CommandResponse, error) {
+	n := count(input.Message(), config)
+
+	// Fetch one more entry since the transcript may already contain this .history input.
+	entries, err := store.Recent(ctx, botType, sarah.TranscriptChannel(input), n+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		if last.Sender == input.SenderKey() && last.Message == input.Message() {
+			entries = entries[:len(entries)-1]
+		}
+	}
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+
+	if len(entries) == 0 {
+		return &sarah.CommandResponse{Content: "No message is found."}, nil
+	}
+
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = fmt.Sprintf("[%s] %s: %s", entry.SentAt.Format(config.TimeFormat), entry.Sender, entry.Message)
+	}
+	return &sarah.CommandResponse{Content: strings.Join(lines, "\n")}, nil
+}
+
+// count extracts the requested number of messages from the given message and caps it with Config.MaxCount.
+func count(message string, config *Config) int {
+	n := config.DefaultCount
+	match := matchPattern.FindStringSubmatch(strings.TrimSpace(message))
+	if len(match) > 1 && match[1] != "" {
+		if i, err := strconv.Atoi(strings.TrimSpace(match[1])); err == nil {
+			n = i
+		}
+	}
+
+	if config.MaxCount > 0 && n > config.MaxCount {
+		n = config.MaxCount
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
@@ -0,0 +1,137 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+	ReplyToValue   string
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.ReplyToValue
+}
+
+type DummyTranscriptStore struct {
+	AppendFunc func(context.Context, sarah.BotType, string, *sarah.TranscriptEntry) error
+	RecentFunc func(context.Context, sarah.BotType, string, int) ([]*sarah.TranscriptEntry, error)
+}
+
+func (s *DummyTranscriptStore) Append(ctx context.Context, botType sarah.BotType, channel string, entry *sarah.TranscriptEntry) error {
+	return s.AppendFunc(ctx, botType, channel, entry)
+}
+
+func (s *DummyTranscriptStore) Recent(ctx context.Context, botType sarah.BotType, channel string, n int) ([]*sarah.TranscriptEntry, error) {
+	return s.RecentFunc(ctx, botType, channel, n)
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+
+	if config.DefaultCount <= 0 || config.MaxCount < config.DefaultCount {
+		t.Errorf("Unexpected counts are set: %#v.", config)
+	}
+}
+
+func TestNewCommandProps(t *testing.T) {
+	props := NewCommandProps("dummy", sarah.NewInMemoryTranscriptStore(10), NewConfig())
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func Test_matchPattern(t *testing.T) {
+	testSets := []struct {
+		message string
+		matched bool
+	}{
+		{message: ".history", matched: true},
+		{message: ".history 5", matched: true},
+		{message: ".history foo", matched: false},
+		{message: ".historyfoo", matched: false},
+	}
+
+	for _, testSet := range testSets {
+		if matchPattern.MatchString(testSet.message) != testSet.matched {
+			t.Errorf("Unexpected match result for %s.", testSet.message)
+		}
+	}
+}
+
+func Test_count(t *testing.T) {
+	config := &Config{DefaultCount: 10, MaxCount: 20}
+	testSets := []struct {
+		message  string
+		expected int
+	}{
+		{message: ".history", expected: 10},
+		{message: ".history 3", expected: 3},
+		{message: ".history 100", expected: 20},
+		{message: ".history 0", expected: 1},
+	}
+
+	for _, testSet := range testSets {
+		n := count(testSet.message, config)
+		if n != testSet.expected {
+			t.Errorf("Unexpected count is returned for %s: %d.", testSet.message, n)
+		}
+	}
+}
+
+func Test_execute(t *testing.T) {
+	store := sarah.NewInMemoryTranscriptStore(10)
+	sentAt := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	for _, message := range []string{"hello", "how are you?", "fine"} {
+		_ = store.Append(context.TODO(), "dummy", "#general", &sarah.TranscriptEntry{Sender: "alice", Message: message, SentAt: sentAt})
+	}
+	_ = store.Append(context.TODO(), "dummy", "#general", &sarah.TranscriptEntry{Sender: "bob", Message: ".history 2", SentAt: sentAt})
+
+	input := &DummyInput{SenderKeyValue: "bob", MessageValue: ".history 2", ReplyToValue: "#general"}
+	res, err := execute(context.TODO(), "dummy", store, NewConfig(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	expected := "[09:30] alice: how are you?\n[09:30] alice: fine"
+	if res.Content != expected {
+		t.Errorf("Unexpected content is returned: %q.", res.Content)
+	}
+
+	input = &DummyInput{SenderKeyValue: "bob", MessageValue: ".history", ReplyToValue: "#random"}
+	res, _ = execute(context.TODO(), "dummy", store, NewConfig(), input)
+	if !strings.HasPrefix(res.Content.(string), "No message") {
+		t.Errorf("Unexpected content is returned for an empty channel: %q.", res.Content)
+	}
+}
+
+func Test_execute_StoreError(t *testing.T) {
+	store := &DummyTranscriptStore{
+		RecentFunc: func(_ context.Context, _ sarah.BotType, _ string, _ int) ([]*sarah.TranscriptEntry, error) {
+			return nil, errors.New("connection error")
+		},
+	}
+
+	_, err := execute(context.TODO(), "dummy", store, NewConfig(), &DummyInput{MessageValue: ".history"})
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
@@ -0,0 +1,193 @@
+// Package webhook provides the plumbing that the webhook receivers under contrib share.
+// Receiver reads a bounded request body, limits the number of concurrent notifications, and sends them through sarah.Bot,
+// while each plugin only verifies the request and maps the payload to a Notification.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrIgnored is returned by HandleFunc when the request does not have to be notified.
+	// The request is responded with 200 so the sender does not consider this a delivery failure.
+	ErrIgnored = errors.New("request is ignored")
+
+	// ErrUnauthorized is returned by HandleFunc when the request can not be verified.
+	// The request is responded with 401.
+	ErrUnauthorized = errors.New("request is not authorized")
+)
+
+// Config contains some configuration variables for Receiver.
+type Config struct {
+	// Name is the name of the sender that appears in the log messages.
+	Name string
+
+	// ListenPort declares the port number that Run listens to.
+	ListenPort int
+
+	// Path declares the URL path that Run serves.
+	Path string
+
+	// Timeout declares the timeout to send notifications on each request. Zero value means no timeout.
+	Timeout time.Duration
+
+	// MaxBodySize declares the maximum size of a request body in bytes.
+	// A larger request is rejected with 413 Request Entity Too Large. Zero value means no limit.
+	MaxBodySize int64
+
+	// MaxConcurrentNotifications declares the maximum number of requests whose notifications are sent concurrently.
+	// A request that exceeds the limit is rejected with 503 Service Unavailable. Zero value means no limit.
+	MaxConcurrentNotifications int
+}
+
+// Notification represents a message to send to the destinations.
+type Notification struct {
+	Destinations []sarah.OutputDestination
+	Message      string
+}
+
+// HandleFunc verifies the given request and its body, and then returns the Notification to send.
+// Return an error that wraps ErrIgnored or ErrUnauthorized to control the response status; any other error results in 400.
+type HandleFunc func(req *http.Request, body []byte) (*Notification, error)
+
+// Receiver is an http.Handler that receives webhook requests and sends the Notifications built by HandleFunc.
+type Receiver struct {
+	config    *Config
+	bot       sarah.Bot
+	handle    HandleFunc
+	notifying chan struct{}
+}
+
+var _ http.Handler = (*Receiver)(nil)
+
+// NewReceiver creates and returns a new Receiver instance.
+func NewReceiver(config *Config, bot sarah.Bot, handle HandleFunc) *Receiver {
+	var notifying chan struct{}
+	if config.MaxConcurrentNotifications > 0 {
+		notifying = make(chan struct{}, config.MaxConcurrentNotifications)
+	}
+
+	return &Receiver{
+		config:    config,
+		bot:       bot,
+		handle:    handle,
+		notifying: notifying,
+	}
+}
+
+// Run starts an HTTP server to receive webhook requests and blocks until the given context is canceled.
+func (r *Receiver) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(r.config.Path, r)
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", r.config.ListenPort),
+		Handler: mux,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = server.Shutdown(context.Background())
+		return nil
+
+	case err := <-errCh:
+		return fmt.Errorf("failed to run %s webhook server: %w", r.config.Name, err)
+
+	}
+}
+
+// ServeHTTP reads the request body, passes it to HandleFunc, and then sends the returned Notification asynchronously.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.config.MaxBodySize > 0 {
+		req.Body = http.MaxBytesReader(w, req.Body, r.config.MaxBodySize)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	notification, err := r.handle(req, body)
+	if errors.Is(err, ErrIgnored) {
+		w.WriteHeader(http.StatusOK)
+		return
+	} else if errors.Is(err, ErrUnauthorized) {
+		logger.Warnf("Rejecting %s webhook request: %+v", r.config.Name, err)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		logger.Warnf("Failed to handle %s webhook request: %+v", r.config.Name, err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if !r.acquire() {
+		logger.Warnf("Too many %s notifications are being sent. Rejecting a request.", r.config.Name)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	// Respond quickly and send notifications asynchronously.
+	w.WriteHeader(http.StatusOK)
+	go func() {
+		defer r.release()
+		r.notify(notification)
+	}()
+}
+
+// acquire reserves a slot to send notifications. false is returned when Config.MaxConcurrentNotifications is reached.
+func (r *Receiver) acquire() bool {
+	if r.notifying == nil {
+		return true
+	}
+
+	select {
+	case r.notifying <- struct{}{}:
+		return true
+
+	default:
+		return false
+
+	}
+}
+
+func (r *Receiver) release() {
+	if r.notifying == nil {
+		return
+	}
+	<-r.notifying
+}
+
+func (r *Receiver) notify(notification *Notification) {
+	ctx := context.Background()
+	if r.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+		defer cancel()
+	}
+
+	for _, dest := range notification.Destinations {
+		r.bot.SendMessage(ctx, sarah.NewOutputMessage(dest, notification.Message))
+	}
+}
@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyBot struct {
+	SendMessageFunc func(context.Context, sarah.Output)
+}
+
+func (bot *DummyBot) BotType() sarah.BotType {
+	return "dummy"
+}
+
+func (bot *DummyBot) Respond(_ context.Context, _ sarah.Input) error {
+	return nil
+}
+
+func (bot *DummyBot) SendMessage(ctx context.Context, output sarah.Output) {
+	bot.SendMessageFunc(ctx, output)
+}
+
+func (bot *DummyBot) AppendCommand(_ sarah.Command) {
+}
+
+func (bot *DummyBot) Run(_ context.Context, _ func(sarah.Input) error, _ func(error)) {
+}
+
+func serve(receiver *Receiver, method string, body string) int {
+	req := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestNewReceiver(t *testing.T) {
+	receiver := NewReceiver(&Config{}, &DummyBot{}, nil)
+	if receiver.notifying != nil {
+		t.Error("Semaphore should not be set without MaxConcurrentNotifications.")
+	}
+
+	receiver = NewReceiver(&Config{MaxConcurrentNotifications: 3}, &DummyBot{}, nil)
+	if cap(receiver.notifying) != 3 {
+		t.Errorf("Unexpected semaphore size is set: %d.", cap(receiver.notifying))
+	}
+}
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	testSets := []struct {
+		method string
+		err    error
+		status int
+		sent   bool
+	}{
+		{
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			method: http.MethodPost,
+			err:    ErrUnauthorized,
+			status: http.StatusUnauthorized,
+		},
+		{
+			method: http.MethodPost,
+			err:    ErrIgnored,
+			status: http.StatusOK,
+		},
+		{
+			method: http.MethodPost,
+			err:    errors.New("malformed"),
+			status: http.StatusBadRequest,
+		},
+		{
+			method: http.MethodPost,
+			status: http.StatusOK,
+			sent:   true,
+		},
+	}
+
+	for i, testSet := range testSets {
+		sent := make(chan sarah.Output, 1)
+		bot := &DummyBot{
+			SendMessageFunc: func(_ context.Context, output sarah.Output) {
+				sent <- output
+			},
+		}
+		handle := func(_ *http.Request, body []byte) (*Notification, error) {
+			if testSet.err != nil {
+				return nil, testSet.err
+			}
+			return &Notification{
+				Destinations: []sarah.OutputDestination{"C1"},
+				Message:      string(body),
+			}, nil
+		}
+		receiver := NewReceiver(&Config{Timeout: time.Second}, bot, handle)
+
+		status := serve(receiver, testSet.method, "payload")
+		if status != testSet.status {
+			t.Errorf("Unexpected status code is returned on test %d: %d.", i, status)
+		}
+
+		if !testSet.sent {
+			continue
+		}
+
+		select {
+		case output := <-sent:
+			if output.Destination() != "C1" {
+				t.Errorf("Unexpected destination is given on test %d: %#v.", i, output.Destination())
+			}
+			if output.Content() != "payload" {
+				t.Errorf("Unexpected content is given on test %d: %#v.", i, output.Content())
+			}
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Errorf("Notification is not sent on test %d.", i)
+
+		}
+	}
+}
+
+func TestReceiver_ServeHTTP_TooLarge(t *testing.T) {
+	handle := func(_ *http.Request, _ []byte) (*Notification, error) {
+		t.Error("HandleFunc should not be called.")
+		return nil, nil
+	}
+	receiver := NewReceiver(&Config{MaxBodySize: 3}, &DummyBot{}, handle)
+
+	status := serve(receiver, http.MethodPost, "payload")
+	if status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Unexpected status code is returned: %d.", status)
+	}
+}
+
+func TestReceiver_ServeHTTP_TooManyNotifications(t *testing.T) {
+	sending := make(chan struct{}, 1)
+	done := make(chan struct{})
+	bot := &DummyBot{
+		SendMessageFunc: func(_ context.Context, _ sarah.Output) {
+			sending <- struct{}{}
+			<-done
+		},
+	}
+	handle := func(_ *http.Request, _ []byte) (*Notification, error) {
+		return &Notification{
+			Destinations: []sarah.OutputDestination{"C1"},
+			Message:      "message",
+		}, nil
+	}
+	receiver := NewReceiver(&Config{MaxConcurrentNotifications: 1}, bot, handle)
+
+	if status := serve(receiver, http.MethodPost, "payload"); status != http.StatusOK {
+		t.Fatalf("Unexpected status code is returned: %d.", status)
+	}
+	<-sending
+
+	if status := serve(receiver, http.MethodPost, "payload"); status != http.StatusServiceUnavailable {
+		t.Errorf("Request exceeding the limit should be rejected: %d.", status)
+	}
+
+	close(done)
+	select {
+	case receiver.notifying <- struct{}{}:
+		// The slot is released.
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Error("Slot is not released.")
+
+	}
+}
+
+func TestReceiver_notify(t *testing.T) {
+	testSets := []struct {
+		timeout     time.Duration
+		hasDeadline bool
+	}{
+		{
+			timeout:     0,
+			hasDeadline: false,
+		},
+		{
+			timeout:     time.Minute,
+			hasDeadline: true,
+		},
+	}
+
+	for i, testSet := range testSets {
+		var sent int
+		bot := &DummyBot{
+			SendMessageFunc: func(ctx context.Context, _ sarah.Output) {
+				sent++
+				if ctx.Err() != nil {
+					t.Errorf("Context is already done on test %d: %s.", i, ctx.Err())
+				}
+				if _, ok := ctx.Deadline(); ok != testSet.hasDeadline {
+					t.Errorf("Unexpected deadline setting on test %d: %t.", i, ok)
+				}
+			},
+		}
+		receiver := NewReceiver(&Config{Timeout: testSet.timeout}, bot, nil)
+
+		receiver.notify(&Notification{
+			Destinations: []sarah.OutputDestination{"C1", "C2"},
+			Message:      "message",
+		})
+
+		if sent != 2 {
+			t.Errorf("Unexpected number of messages are sent on test %d: %d.", i, sent)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package job provides a command to look up and cancel the long-running jobs that Commands submit via sarah.SubmitJob.
+//
+// The ".job status 42" command shows the state and the latest progress of the job with the identifier "42,"
+// and the ".job cancel 42" command cancels the job.
+// A job can be canceled by the user who submitted it or by the users that WithAuthorizer allows.
+//
+//	j := job.New(slack.SLACK, job.WithAuthorizer(func(input sarah.Input) bool {
+//		return input.SenderKey() == "admin"
+//	}))
+//	sarah.RegisterCommandProps(j.CommandProps())
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var matchPattern = regexp.MustCompile(`^\.job\b`)
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*Job)
+
+// WithAuthorizer creates and returns an Option that restricts who can cancel other users' jobs.
+// The user who submitted a job can always cancel it.
+// By default, no user can cancel other users' jobs.
+func WithAuthorizer(authorizer contrib.Authorizer) Option {
+	return func(j *Job) {
+		j.authorize = authorizer
+	}
+}
+
+// Job serves the ".job" command.
+type Job struct {
+	botType   sarah.BotType
+	authorize contrib.Authorizer
+	find      func(context.Context, string) (*sarah.Job, error)
+	cancel    func(context.Context, string) error
+}
+
+// New creates and returns a new Job instance.
+// The command handles the jobs that are submitted on the Bot with the given BotType.
+func New(botType sarah.BotType, options ...Option) *Job {
+	j := &Job{
+		botType:   botType,
+		authorize: contrib.DenyAll,
+		find:      sarah.FindJob,
+		cancel:    sarah.CancelJob,
+	}
+
+	for _, opt := range options {
+		opt(j)
+	}
+
+	return j
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".job" command.
+//
+//	.job status 42  shows the state and the latest progress of the job with the identifier "42."
+//	.job cancel 42  cancels the job with the identifier "42."
+func (j *Job) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(j.botType).
+		Identifier("job").
+		Instruction("Input .job status {id} to see the job's progress, or .job cancel {id} to cancel the job.").
+		MatchPattern(matchPattern).
+		Func(func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: j.handle(ctx, input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (j *Job) handle(ctx context.Context, input sarah.Input) string {
+	args := strings.Fields(sarah.StripMessage(matchPattern, input.Message()))
+	if len(args) != 2 || (args[0] != "status" && args[0] != "cancel") {
+		return "Input .job status {id} or .job cancel {id}."
+	}
+
+	id := args[1]
+	job, err := j.find(ctx, id)
+	if errors.Is(err, sarah.ErrJobNotFound) {
+		return fmt.Sprintf("Job %s is not found.", id)
+	} else if err != nil {
+		return fmt.Sprintf("Failed to find job %s: %s.", id, err.Error())
+	}
+
+	if args[0] == "status" {
+		return format(job)
+	}
+
+	if job.SenderKey != input.SenderKey() && !contrib.Authorize(j.authorize, input) {
+		return fmt.Sprintf("You are not allowed to cancel job %s.", id)
+	}
+	if job.Finished() {
+		return fmt.Sprintf("Job %s is already %s.", id, job.State)
+	}
+
+	err = j.cancel(ctx, id)
+	if err != nil {
+		return fmt.Sprintf("Failed to cancel job %s: %s.", id, err.Error())
+	}
+
+	return fmt.Sprintf("Job %s is canceled.", id)
+}
+
+func format(job *sarah.Job) string {
+	lines := []string{
+		fmt.Sprintf("Job %s (%s) is %s.", job.ID, job.Name, job.State),
+		fmt.Sprintf("Started at: %s", job.StartedAt.Format(time.RFC3339)),
+	}
+	if job.Finished() {
+		lines = append(lines, fmt.Sprintf("Finished at: %s", job.FinishedAt.Format(time.RFC3339)))
+	}
+	if job.Progress != "" {
+		lines = append(lines, fmt.Sprintf("Progress: %s", job.Progress))
+	}
+	if job.Error != "" {
+		lines = append(lines, fmt.Sprintf("Error: %s", job.Error))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,206 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.SenderKeyValue
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	j := New("dummy", func(_ *Job) {
+		optCalled = true
+	})
+
+	if j == nil {
+		t.Fatal("Job is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Given Option is not applied.")
+	}
+
+	if j.authorize(&DummyInput{}) {
+		t.Error("No user should be authorized by default.")
+	}
+}
+
+func TestWithAuthorizer(t *testing.T) {
+	j := &Job{}
+	WithAuthorizer(func(_ sarah.Input) bool {
+		return true
+	})(j)
+
+	if j.authorize == nil {
+		t.Fatal("Given function is not set.")
+	}
+
+	if !j.authorize(&DummyInput{}) {
+		t.Error("Unexpected function is set.")
+	}
+}
+
+func TestJob_CommandProps(t *testing.T) {
+	props := New("dummy").CommandProps()
+
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestJob_handle(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	running := &sarah.Job{
+		ID:        "42",
+		Name:      "deploy",
+		SenderKey: "owner",
+		State:     sarah.JobRunning,
+		Progress:  "1/3 done",
+		StartedAt: startedAt,
+	}
+	failed := &sarah.Job{
+		ID:         "42",
+		Name:       "deploy",
+		SenderKey:  "owner",
+		State:      sarah.JobFailed,
+		Error:      "boom",
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(time.Minute),
+	}
+
+	testSets := []struct {
+		message   string
+		sender    string
+		authorize bool
+		job       *sarah.Job
+		findErr   error
+		cancelErr error
+		expected  string
+		canceled  bool
+	}{
+		{
+			message:  ".job",
+			expected: "Input .job status {id} or .job cancel {id}.",
+		},
+		{
+			message:  ".job restart 42",
+			expected: "Input .job status {id} or .job cancel {id}.",
+		},
+		{
+			message:  ".job status 42",
+			findErr:  sarah.ErrJobNotFound,
+			expected: "Job 42 is not found.",
+		},
+		{
+			message:  ".job status 42",
+			findErr:  errors.New("dummy"),
+			expected: "Failed to find job 42: dummy.",
+		},
+		{
+			message: ".job status 42",
+			job:     running,
+			expected: strings.Join([]string{
+				"Job 42 (deploy) is running.",
+				"Started at: 2026-01-01T09:00:00Z",
+				"Progress: 1/3 done",
+			}, "\n"),
+		},
+		{
+			message: ".job status 42",
+			job:     failed,
+			expected: strings.Join([]string{
+				"Job 42 (deploy) is failed.",
+				"Started at: 2026-01-01T09:00:00Z",
+				"Finished at: 2026-01-01T09:01:00Z",
+				"Error: boom",
+			}, "\n"),
+		},
+		{
+			message:  ".job cancel 42",
+			sender:   "other",
+			job:      running,
+			expected: "You are not allowed to cancel job 42.",
+		},
+		{
+			message:  ".job cancel 42",
+			sender:   "owner",
+			job:      running,
+			expected: "Job 42 is canceled.",
+			canceled: true,
+		},
+		{
+			message:   ".job cancel 42",
+			sender:    "admin",
+			authorize: true,
+			job:       running,
+			expected:  "Job 42 is canceled.",
+			canceled:  true,
+		},
+		{
+			message:   ".job cancel 42",
+			sender:    "owner",
+			job:       running,
+			cancelErr: errors.New("dummy"),
+			expected:  "Failed to cancel job 42: dummy.",
+			canceled:  true,
+		},
+		{
+			message:  ".job cancel 42",
+			sender:   "owner",
+			job:      failed,
+			expected: "Job 42 is already failed.",
+		},
+	}
+
+	for i, tt := range testSets {
+		canceled := false
+		j := &Job{
+			botType: "dummy",
+			authorize: func(_ sarah.Input) bool {
+				return tt.authorize
+			},
+			find: func(_ context.Context, id string) (*sarah.Job, error) {
+				if id != "42" {
+					t.Errorf("Unexpected identifier is given on test #%d: %s.", i+1, id)
+				}
+				return tt.job, tt.findErr
+			},
+			cancel: func(_ context.Context, id string) error {
+				canceled = true
+				return tt.cancelErr
+			},
+		}
+
+		response := j.handle(context.TODO(), &DummyInput{SenderKeyValue: tt.sender, MessageValue: tt.message})
+		if response != tt.expected {
+			t.Errorf("Unexpected response is returned on test #%d: %s.", i+1, response)
+		}
+		if canceled != tt.canceled {
+			t.Errorf("Unexpected cancellation on test #%d: %t.", i+1, canceled)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package loglevel provides an administrative command to view and change the log level at runtime.
+//
+// The ".loglevel" command replies the current log level, and ".loglevel info" changes the log level via sarah.SetLogLevel.
+// Because the log verbosity affects the whole process, the command is denied to everyone until an Authorizer that allows administrators is given with WithAuthorizer.
+//
+//	l := loglevel.New(slack.SLACK, loglevel.WithAuthorizer(func(input sarah.Input) bool {
+//		return input.SenderKey() == "admin"
+//	}))
+//	sarah.RegisterCommandProps(l.CommandProps())
+package loglevel
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib"
+	"regexp"
+	"strings"
+)
+
+var matchPattern = regexp.MustCompile(`^\.loglevel\b`)
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*LogLevel)
+
+// WithAuthorizer creates and returns an Option that restricts who can change the log level.
+// When the given function returns false, the log level is not changed and the user is notified.
+// By default, no user can change the log level.
+func WithAuthorizer(authorizer contrib.Authorizer) Option {
+	return func(l *LogLevel) {
+		l.authorize = authorizer
+	}
+}
+
+// LogLevel serves the ".loglevel" command.
+type LogLevel struct {
+	botType   sarah.BotType
+	authorize contrib.Authorizer
+	set       func(string) error
+	get       func() string
+}
+
+// New creates and returns a new LogLevel instance.
+func New(botType sarah.BotType, options ...Option) *LogLevel {
+	l := &LogLevel{
+		botType:   botType,
+		authorize: contrib.DenyAll,
+		set:       sarah.SetLogLevel,
+		get:       sarah.LogLevel,
+	}
+
+	for _, opt := range options {
+		opt(l)
+	}
+
+	return l
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".loglevel" command.
+//
+//	.loglevel        replies the current log level.
+//	.loglevel debug  changes the log level to debug.
+func (l *LogLevel) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(l.botType).
+		Identifier("loglevel").
+		Instruction("Input .loglevel [debug|info|warn|error] to view or change the log level.").
+		MatchPattern(matchPattern).
+		Func(func(_ context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: l.handle(input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (l *LogLevel) handle(input sarah.Input) string {
+	if !contrib.Authorize(l.authorize, input) {
+		return "You are not allowed to change the log level."
+	}
+
+	level := strings.TrimSpace(sarah.StripMessage(matchPattern, input.Message()))
+	if level == "" {
+		return fmt.Sprintf("Current log level is %s.", l.get())
+	}
+
+	err := l.set(level)
+	if err != nil {
+		return fmt.Sprintf("Failed to change the log level: %s.", err.Error())
+	}
+
+	return fmt.Sprintf("Log level is changed to %s.", l.get())
+}
@@ -0,0 +1,134 @@
+package loglevel
+
+import (
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.SenderKeyValue
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	l := New("dummy", func(_ *LogLevel) {
+		optCalled = true
+	})
+
+	if l == nil {
+		t.Fatal("LogLevel is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Given Option is not applied.")
+	}
+
+	if l.authorize(&DummyInput{}) {
+		t.Error("No user should be authorized by default.")
+	}
+}
+
+func TestWithAuthorizer(t *testing.T) {
+	l := &LogLevel{}
+	WithAuthorizer(func(_ sarah.Input) bool {
+		return false
+	})(l)
+
+	if l.authorize == nil {
+		t.Fatal("Given function is not set.")
+	}
+
+	if l.authorize(&DummyInput{}) {
+		t.Error("Unexpected function is set.")
+	}
+}
+
+func TestLogLevel_CommandProps(t *testing.T) {
+	props := New("dummy").CommandProps()
+
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestLogLevel_handle(t *testing.T) {
+	testSets := []struct {
+		message   string
+		authorize bool
+		setErr    error
+		expected  string
+		set       string
+	}{
+		{
+			message:   ".loglevel",
+			authorize: true,
+			expected:  "Current log level is info.",
+		},
+		{
+			message:   ".loglevel  warn ",
+			authorize: true,
+			expected:  "Log level is changed to warn.",
+			set:       "warn",
+		},
+		{
+			message:   ".loglevel verbose",
+			authorize: true,
+			setErr:    errors.New("unknown log level: verbose"),
+			expected:  "Failed to change the log level: unknown log level: verbose.",
+			set:       "verbose",
+		},
+		{
+			message:   ".loglevel debug",
+			authorize: false,
+			expected:  "You are not allowed to change the log level.",
+		},
+	}
+
+	for i, tt := range testSets {
+		current := "info"
+		var set string
+		l := &LogLevel{
+			authorize: func(_ sarah.Input) bool {
+				return tt.authorize
+			},
+			set: func(level string) error {
+				set = level
+				if tt.setErr != nil {
+					return tt.setErr
+				}
+				current = level
+				return nil
+			},
+			get: func() string {
+				return current
+			},
+		}
+
+		content := l.handle(&DummyInput{MessageValue: tt.message})
+		if content != tt.expected {
+			t.Errorf("Unexpected content is returned on test #%d: %s.", i+1, content)
+		}
+		if set != tt.set {
+			t.Errorf("Unexpected level is set on test #%d: %s.", i+1, set)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package rollback provides an administrative command to roll back the configuration of a Command or ScheduledTask at runtime.
+//
+// The ".rollback hello" command re-applies the previous configuration snapshot of the plugin with the identifier "hello" via sarah.RollbackConfig.
+// Because a rollback affects every user of the plugin, the command is denied to everyone until an Authorizer that allows administrators is given with WithAuthorizer.
+//
+//	r := rollback.New(slack.SLACK, rollback.WithAuthorizer(func(input sarah.Input) bool {
+//		return input.SenderKey() == "admin"
+//	}))
+//	sarah.RegisterCommandProps(r.CommandProps())
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/contrib"
+	"regexp"
+	"strings"
+)
+
+var matchPattern = regexp.MustCompile(`^\.rollback\b`)
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*Rollback)
+
+// WithAuthorizer creates and returns an Option that restricts who can roll back the configurations.
+// When the given function returns false, the configuration is not rolled back and the user is notified.
+// By default, no user can roll back the configurations.
+func WithAuthorizer(authorizer contrib.Authorizer) Option {
+	return func(r *Rollback) {
+		r.authorize = authorizer
+	}
+}
+
+// Rollback serves the ".rollback" command.
+type Rollback struct {
+	botType   sarah.BotType
+	authorize contrib.Authorizer
+	rollback  func(sarah.BotType, string) error
+}
+
+// New creates and returns a new Rollback instance.
+// The command rolls back the configurations of the plugins that belong to the given BotType.
+func New(botType sarah.BotType, options ...Option) *Rollback {
+	r := &Rollback{
+		botType:   botType,
+		authorize: contrib.DenyAll,
+		rollback:  sarah.RollbackConfig,
+	}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".rollback" command.
+//
+//	.rollback hello  re-applies the previous configuration of the plugin with the identifier "hello."
+func (r *Rollback) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(r.botType).
+		Identifier("rollback").
+		Instruction("Input .rollback {identifier} to re-apply the previous configuration of the command or scheduled task.").
+		MatchPattern(matchPattern).
+		Func(func(_ context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: r.handle(input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (r *Rollback) handle(input sarah.Input) string {
+	if !contrib.Authorize(r.authorize, input) {
+		return "You are not allowed to roll back the configuration."
+	}
+
+	id := strings.TrimSpace(sarah.StripMessage(matchPattern, input.Message()))
+	if id == "" {
+		return "Input .rollback {identifier} to roll back the configuration."
+	}
+
+	err := r.rollback(r.botType, id)
+	if errors.Is(err, sarah.ErrConfigSnapshotNotFound) {
+		return fmt.Sprintf("No previous configuration of %s is kept.", id)
+	} else if err != nil {
+		return fmt.Sprintf("Failed to roll back the configuration of %s: %s.", id, err.Error())
+	}
+
+	return fmt.Sprintf("The configuration of %s is rolled back.", id)
+}
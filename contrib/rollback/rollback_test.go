@@ -0,0 +1,137 @@
+package rollback
+
+import (
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return i.SenderKeyValue
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	r := New("dummy", func(_ *Rollback) {
+		optCalled = true
+	})
+
+	if r == nil {
+		t.Fatal("Rollback is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Given Option is not applied.")
+	}
+
+	if r.authorize(&DummyInput{}) {
+		t.Error("No user should be authorized by default.")
+	}
+}
+
+func TestWithAuthorizer(t *testing.T) {
+	r := &Rollback{}
+	WithAuthorizer(func(_ sarah.Input) bool {
+		return false
+	})(r)
+
+	if r.authorize == nil {
+		t.Fatal("Given function is not set.")
+	}
+
+	if r.authorize(&DummyInput{}) {
+		t.Error("Unexpected function is set.")
+	}
+}
+
+func TestRollback_CommandProps(t *testing.T) {
+	props := New("dummy").CommandProps()
+
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestRollback_handle(t *testing.T) {
+	testSets := []struct {
+		message     string
+		authorize   bool
+		rollbackErr error
+		expected    string
+		rolledBack  string
+	}{
+		{
+			message:   ".rollback hello",
+			authorize: false,
+			expected:  "You are not allowed to roll back the configuration.",
+		},
+		{
+			message:   ".rollback",
+			authorize: true,
+			expected:  "Input .rollback {identifier} to roll back the configuration.",
+		},
+		{
+			message:    ".rollback hello",
+			authorize:  true,
+			expected:   "The configuration of hello is rolled back.",
+			rolledBack: "hello",
+		},
+		{
+			message:     ".rollback hello",
+			authorize:   true,
+			rollbackErr: sarah.ErrConfigSnapshotNotFound,
+			expected:    "No previous configuration of hello is kept.",
+			rolledBack:  "hello",
+		},
+		{
+			message:     ".rollback hello",
+			authorize:   true,
+			rollbackErr: errors.New("dummy"),
+			expected:    "Failed to roll back the configuration of hello: dummy.",
+			rolledBack:  "hello",
+		},
+	}
+
+	for i, tt := range testSets {
+		rolledBack := ""
+		r := &Rollback{
+			botType: "dummy",
+			authorize: func(_ sarah.Input) bool {
+				return tt.authorize
+			},
+			rollback: func(botType sarah.BotType, id string) error {
+				if botType != "dummy" {
+					t.Errorf("Unexpected BotType is given on test #%d: %s.", i+1, botType)
+				}
+				rolledBack = id
+				return tt.rollbackErr
+			},
+		}
+
+		response := r.handle(&DummyInput{MessageValue: tt.message})
+		if response != tt.expected {
+			t.Errorf("Unexpected response is returned on test #%d: %s.", i+1, response)
+		}
+		if rolledBack != tt.rolledBack {
+			t.Errorf("Unexpected identifier is rolled back on test #%d: %s.", i+1, rolledBack)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package selftest provides the ".ping" command and an HTTP endpoint that report the health of the running process with sarah.SelfTest.
+//
+// The ".ping" command replies how long it took from the input's arrival to the command execution,
+// in addition to the checks of the worker, the UserContextStorage, and the ConfigWatcher.
+// The reply itself is sent via Adapter.SendMessage, whose latency shows up in the next report.
+//
+//	s := selftest.New(slack.SLACK)
+//	sarah.RegisterCommandProps(s.CommandProps())
+//
+// SelfTest also works as an http.Handler so external monitoring can poll the same report next to the status endpoint.
+// The handler responds 200 with a JSON body when every check succeeds; otherwise, it responds 503.
+//
+//	mux.Handle("/status/selftest", s)
+package selftest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var matchPattern = regexp.MustCompile(`^\.ping\b`)
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*SelfTest)
+
+// WithTimeout creates and returns an Option that limits how long the checks can take.
+// The default value is five seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *SelfTest) {
+		s.timeout = timeout
+	}
+}
+
+// SelfTest serves the ".ping" command and the HTTP endpoint.
+type SelfTest struct {
+	botType sarah.BotType
+	timeout time.Duration
+	test    func(context.Context) (*sarah.SelfTestReport, error)
+	now     func() time.Time
+}
+
+var _ http.Handler = (*SelfTest)(nil)
+
+// New creates and returns a new SelfTest instance.
+func New(botType sarah.BotType, options ...Option) *SelfTest {
+	s := &SelfTest{
+		botType: botType,
+		timeout: 5 * time.Second,
+		test:    sarah.SelfTest,
+		now:     time.Now,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".ping" command.
+func (s *SelfTest) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(s.botType).
+		Identifier("ping").
+		Instruction("Input .ping to check the bot's health.").
+		MatchPattern(matchPattern).
+		Func(func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return &sarah.CommandResponse{
+				Content: s.handle(ctx, input),
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (s *SelfTest) handle(ctx context.Context, input sarah.Input) string {
+	// Measure before the checks so the checks' own latency is not included.
+	var elapsed time.Duration
+	if sentAt := input.SentAt(); !sentAt.IsZero() {
+		elapsed = s.now().Sub(sentAt)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	report, err := s.test(ctx)
+	if err != nil {
+		return fmt.Sprintf("Failed to run the self-test: %s.", err.Error())
+	}
+
+	lines := []string{"pong"}
+	if elapsed > 0 {
+		lines = append(lines, fmt.Sprintf("input to command: %s", formatLatency(elapsed)))
+	}
+	lines = append(lines, fmt.Sprintf("worker: %s", formatResult(report.Worker)))
+	for _, bot := range report.Bots {
+		if bot.UserContextStorage != nil {
+			lines = append(lines, fmt.Sprintf("%s user context storage: %s", bot.Type, formatResult(bot.UserContextStorage)))
+		}
+		if bot.ConfigWatcher != nil {
+			lines = append(lines, fmt.Sprintf("%s config watcher: %s", bot.Type, formatResult(bot.ConfigWatcher)))
+		}
+		if bot.LastSendMessage > 0 {
+			lines = append(lines, fmt.Sprintf("%s last message sending: %s", bot.Type, formatLatency(bot.LastSendMessage)))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ServeHTTP runs sarah.SelfTest and responds the report in JSON format.
+//
+//	curl -s -XGET "http://localhost:8080/status/selftest" | jq .
+//	{
+//	  "healthy": true,
+//	  "tested_at": "2026-10-16T15:22:37.274064679+09:00",
+//	  "worker": {
+//	    "latency_ms": 0.05
+//	  },
+//	  "bots": [
+//	    {
+//	      "type": "slack",
+//	      "user_context_storage": {
+//	        "latency_ms": 1.2
+//	      },
+//	      "last_send_message_ms": 120.5
+//	    }
+//	  ]
+//	}
+func (s *SelfTest) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), s.timeout)
+	defer cancel()
+	report, err := s.test(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := json.Marshal(newReportJSON(report))
+	if err != nil {
+		logger.Errorf("Failed to marshal self-test report: %+v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Healthy() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write(body)
+}
+
+type reportJSON struct {
+	Healthy  bool        `json:"healthy"`
+	TestedAt time.Time   `json:"tested_at"`
+	Worker   *resultJSON `json:"worker"`
+	Bots     []*botJSON  `json:"bots"`
+}
+
+type botJSON struct {
+	Type               sarah.BotType `json:"type"`
+	UserContextStorage *resultJSON   `json:"user_context_storage,omitempty"`
+	ConfigWatcher      *resultJSON   `json:"config_watcher,omitempty"`
+	LastSendMessage    float64       `json:"last_send_message_ms,omitempty"`
+}
+
+type resultJSON struct {
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+func newReportJSON(report *sarah.SelfTestReport) *reportJSON {
+	r := &reportJSON{
+		Healthy:  report.Healthy(),
+		TestedAt: report.TestedAt,
+		Worker:   newResultJSON(report.Worker),
+		Bots:     []*botJSON{},
+	}
+	for _, bot := range report.Bots {
+		r.Bots = append(r.Bots, &botJSON{
+			Type:               bot.Type,
+			UserContextStorage: newResultJSON(bot.UserContextStorage),
+			ConfigWatcher:      newResultJSON(bot.ConfigWatcher),
+			LastSendMessage:    milliseconds(bot.LastSendMessage),
+		})
+	}
+	return r
+}
+
+func newResultJSON(result *sarah.SelfTestResult) *resultJSON {
+	if result == nil {
+		return nil
+	}
+	return &resultJSON{
+		Latency: milliseconds(result.Latency),
+		Error:   result.Error,
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func formatResult(result *sarah.SelfTestResult) string {
+	if !result.OK() {
+		return fmt.Sprintf("NG (%s)", result.Error)
+	}
+	return fmt.Sprintf("OK (%s)", formatLatency(result.Latency))
+}
+
+func formatLatency(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
@@ -0,0 +1,147 @@
+package selftest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	MessageValue string
+	SentAtValue  time.Time
+}
+
+func (i *DummyInput) SenderKey() string {
+	return "dummy"
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return i.SentAtValue
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	return "dummy"
+}
+
+func healthyReport() *sarah.SelfTestReport {
+	return &sarah.SelfTestReport{
+		Worker: &sarah.SelfTestResult{Latency: time.Millisecond},
+		Bots: []*sarah.BotSelfTestReport{
+			{
+				Type:               "dummy",
+				UserContextStorage: &sarah.SelfTestResult{Latency: 2 * time.Millisecond},
+				LastSendMessage:    100 * time.Millisecond,
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	s := New("dummy", WithTimeout(time.Second))
+
+	if s == nil {
+		t.Fatal("SelfTest is not returned.")
+	}
+
+	if s.timeout != time.Second {
+		t.Errorf("Given Option is not applied: %s.", s.timeout)
+	}
+}
+
+func TestSelfTest_CommandProps(t *testing.T) {
+	s := New("dummy")
+	props := s.CommandProps()
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+}
+
+func TestSelfTest_handle(t *testing.T) {
+	now := time.Now()
+	s := &SelfTest{
+		timeout: time.Second,
+		test: func(ctx context.Context) (*sarah.SelfTestReport, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("Timeout is not set.")
+			}
+			return healthyReport(), nil
+		},
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	reply := s.handle(context.TODO(), &DummyInput{MessageValue: ".ping", SentAtValue: now.Add(-50 * time.Millisecond)})
+	expected := []string{
+		"pong",
+		"input to command: 50ms",
+		"worker: OK (1ms)",
+		"dummy user context storage: OK (2ms)",
+		"dummy last message sending: 100ms",
+	}
+	if reply != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected reply is returned: %s.", reply)
+	}
+
+	s.test = func(_ context.Context) (*sarah.SelfTestReport, error) {
+		return nil, sarah.ErrRunnerNotRunning
+	}
+	reply = s.handle(context.TODO(), &DummyInput{MessageValue: ".ping"})
+	if !strings.HasPrefix(reply, "Failed to run the self-test") {
+		t.Errorf("Unexpected reply is returned: %s.", reply)
+	}
+}
+
+func TestSelfTest_ServeHTTP(t *testing.T) {
+	unhealthy := healthyReport()
+	unhealthy.Bots[0].ConfigWatcher = &sarah.SelfTestResult{Error: "permission denied"}
+
+	testSets := []struct {
+		method string
+		report *sarah.SelfTestReport
+		err    error
+		status int
+	}{
+		{method: http.MethodGet, report: healthyReport(), status: http.StatusOK},
+		{method: http.MethodGet, report: unhealthy, status: http.StatusServiceUnavailable},
+		{method: http.MethodGet, err: errors.New("dummy"), status: http.StatusServiceUnavailable},
+		{method: http.MethodPost, status: http.StatusMethodNotAllowed},
+	}
+
+	for i, tt := range testSets {
+		s := &SelfTest{
+			timeout: time.Second,
+			test: func(_ context.Context) (*sarah.SelfTestReport, error) {
+				return tt.report, tt.err
+			},
+		}
+
+		recorder := httptest.NewRecorder()
+		s.ServeHTTP(recorder, httptest.NewRequest(tt.method, "/status/selftest", nil))
+		if recorder.Code != tt.status {
+			t.Errorf("Unexpected status is returned on test #%d: %d.", i+1, recorder.Code)
+		}
+
+		if tt.report == nil {
+			continue
+		}
+		body := &reportJSON{}
+		err := json.Unmarshal(recorder.Body.Bytes(), body)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if body.Healthy != tt.report.Healthy() || len(body.Bots) != 1 || body.Bots[0].LastSendMessage != 100 {
+			t.Errorf("Unexpected body is returned on test #%d: %s.", i+1, recorder.Body.String())
+		}
+	}
+}
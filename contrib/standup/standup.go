@@ -0,0 +1,271 @@
+// Package standup provides a stand-up meeting plugin that collects each team member's report in a conversational manner.
+//
+// The plugin consists of three components:
+//   - A scheduled task that sends each member a direct message to start the stand-up.
+//   - A command, ".standup", that asks the configured questions one by one with sarah.UserContext.
+//   - A scheduled task that posts a summary of the collected answers to a channel.
+//
+// Because the form of sarah.OutputDestination varies depending on the chat service,
+// a function to convert a member ID or a channel ID into sarah.OutputDestination must be given to New.
+//
+//	config := standup.NewConfig()
+//	config.Members = []string{"D0123", "D4567"}
+//	config.SummaryChannel = "C8901"
+//	s, _ := standup.New(slack.SLACK, config, func(id string) sarah.OutputDestination {
+//		return event.ChannelID(id)
+//	})
+//	sarah.RegisterCommandProps(s.CommandProps())
+//	sarah.RegisterScheduledTaskProps(s.PromptTaskProps())
+//	sarah.RegisterScheduledTaskProps(s.SummaryTaskProps())
+package standup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var matchPattern = regexp.MustCompile(`^\.standup\b`)
+
+// ErrNoQuestion is returned when no question is configured.
+var ErrNoQuestion = errors.New("at least one question must be configured")
+
+// Config contains some configuration variables for the stand-up plugin.
+type Config struct {
+	// PromptSchedule declares when to send a stand-up invitation to members.
+	PromptSchedule string `json:"prompt_schedule" yaml:"prompt_schedule"`
+
+	// SummarySchedule declares when to post the summary of the collected answers.
+	SummarySchedule string `json:"summary_schedule" yaml:"summary_schedule"`
+
+	// Questions declares a list of questions to ask each member in order.
+	Questions []string `json:"questions" yaml:"questions"`
+
+	// Members declares a list of member IDs to send the invitation to.
+	// Each value is converted to sarah.OutputDestination with the function given to New.
+	// The reports in the summary are keyed by these IDs; see WithMemberIdentifier for how a reporting user is mapped to one of them.
+	Members []string `json:"members" yaml:"members"`
+
+	// SummaryChannel declares a channel ID to post the summary to.
+	// The value is converted to sarah.OutputDestination with the function given to New.
+	SummaryChannel string `json:"summary_channel" yaml:"summary_channel"`
+
+	// Invitation declares a message sent to each member on PromptSchedule.
+	Invitation string `json:"invitation" yaml:"invitation"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Members and SummaryChannel are empty at this point as there can not be default values.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank value or override those default values.
+func NewConfig() *Config {
+	return &Config{
+		PromptSchedule:  "0 0 10 * * 1-5",
+		SummarySchedule: "0 30 10 * * 1-5",
+		Questions: []string{
+			"What did you do yesterday?",
+			"What will you do today?",
+			"Is there anything blocking your progress?",
+		},
+		Members:        []string{},
+		SummaryChannel: "",
+		Invitation:     "It is time for stand-up. Reply with .standup to start.",
+	}
+}
+
+// Option defines a function's signature that New's functional options must satisfy.
+type Option func(*Standup)
+
+// WithMemberIdentifier creates an Option that replaces the default member identification logic.
+// The given function must return the member's ID declared in Config.Members so the summary can tell who has not reported yet.
+//
+// By default, a member is identified by comparing sarah.Input.ReplyTo with the destinations of Config.Members,
+// i.e., a report given in the direct message channel that the invitation was sent to belongs to that member.
+// sarah.Input.SenderKey is used when no member matches, e.g. when a report is given in a shared channel.
+func WithMemberIdentifier(fnc func(sarah.Input) string) Option {
+	return func(s *Standup) {
+		s.identify = fnc
+	}
+}
+
+// Standup holds the stand-up configuration and the answers collected so far.
+// Use CommandProps, PromptTaskProps, and SummaryTaskProps to build the components to register.
+type Standup struct {
+	botType     sarah.BotType
+	config      *Config
+	destination func(string) sarah.OutputDestination
+	identify    func(sarah.Input) string
+	reports     *reports
+}
+
+// New creates and returns a new Standup instance.
+// destination is called to convert Config.Members and Config.SummaryChannel into sarah.OutputDestination.
+func New(botType sarah.BotType, config *Config, destination func(string) sarah.OutputDestination, options ...Option) (*Standup, error) {
+	if len(config.Questions) == 0 {
+		return nil, ErrNoQuestion
+	}
+
+	s := &Standup{
+		botType:     botType,
+		config:      config,
+		destination: destination,
+		reports: &reports{
+			answers: map[string][]string{},
+		},
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// CommandProps builds and returns a sarah.CommandProps for the ".standup" command.
+// The command asks the configured questions one by one and stores the answers until the summary is posted.
+func (s *Standup) CommandProps() *sarah.CommandProps {
+	return sarah.NewCommandPropsBuilder().
+		BotType(s.botType).
+		Identifier("standup").
+		Instruction("Input .standup to report your stand-up.").
+		MatchPattern(matchPattern).
+		Func(func(_ context.Context, _ sarah.Input) (*sarah.CommandResponse, error) {
+			return s.ask(0, []string{}), nil
+		}).
+		MustBuild()
+}
+
+// PromptTaskProps builds and returns a sarah.ScheduledTaskProps that invites each member to the stand-up.
+func (s *Standup) PromptTaskProps() *sarah.ScheduledTaskProps {
+	return sarah.NewScheduledTaskPropsBuilder().
+		BotType(s.botType).
+		Identifier("standup_prompt").
+		Schedule(s.config.PromptSchedule).
+		Func(func(_ context.Context) ([]*sarah.ScheduledTaskResult, error) {
+			var results []*sarah.ScheduledTaskResult
+			for _, member := range s.config.Members {
+				results = append(results, &sarah.ScheduledTaskResult{
+					Content:     s.config.Invitation,
+					Destination: s.destination(member),
+				})
+			}
+			return results, nil
+		}).
+		MustBuild()
+}
+
+// SummaryTaskProps builds and returns a sarah.ScheduledTaskProps that posts the collected answers to Config.SummaryChannel.
+// The collected answers are cleared once the summary is built.
+func (s *Standup) SummaryTaskProps() *sarah.ScheduledTaskProps {
+	return sarah.NewScheduledTaskPropsBuilder().
+		BotType(s.botType).
+		Identifier("standup_summary").
+		Schedule(s.config.SummarySchedule).
+		DefaultDestination(s.destination(s.config.SummaryChannel)).
+		Func(func(_ context.Context) ([]*sarah.ScheduledTaskResult, error) {
+			return []*sarah.ScheduledTaskResult{
+				{
+					Content: s.summary(s.reports.flush()),
+				},
+			}, nil
+		}).
+		MustBuild()
+}
+
+func (s *Standup) ask(i int, answers []string) *sarah.CommandResponse {
+	next := func(_ context.Context, given sarah.Input) (*sarah.CommandResponse, error) {
+		answer := strings.TrimSpace(given.Message())
+		if answer == "" {
+			// Ask the same question again.
+			return s.ask(i, answers), nil
+		}
+
+		answers = append(answers, answer)
+		if len(answers) < len(s.config.Questions) {
+			return s.ask(i+1, answers), nil
+		}
+
+		s.reports.set(s.member(given), answers)
+		return &sarah.CommandResponse{
+			Content: "Thanks! Your report is stored.",
+		}, nil
+	}
+
+	return &sarah.CommandResponse{
+		Content:     s.config.Questions[i],
+		UserContext: sarah.NewUserContext(next),
+	}
+}
+
+// member returns the ID of the member who sent the given Input.
+func (s *Standup) member(input sarah.Input) string {
+	if s.identify != nil {
+		return s.identify(input)
+	}
+
+	replyTo := input.ReplyTo()
+	for _, member := range s.config.Members {
+		if reflect.DeepEqual(s.destination(member), replyTo) {
+			return member
+		}
+	}
+	return input.SenderKey()
+}
+
+func (s *Standup) summary(answers map[string][]string) string {
+	if len(answers) == 0 {
+		return "No stand-up report was submitted."
+	}
+
+	// Sort the members so the summary is always in the same order.
+	var members []string
+	for member := range answers {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Stand-up summary: %d report(s)", len(members)))
+	for _, member := range members {
+		sb.WriteString(fmt.Sprintf("\n\n*%s*", member))
+		for i, answer := range answers[member] {
+			sb.WriteString(fmt.Sprintf("\n%s\n> %s", s.config.Questions[i], answer))
+		}
+	}
+
+	for _, member := range s.config.Members {
+		if _, ok := answers[member]; !ok {
+			sb.WriteString(fmt.Sprintf("\n\nNo report from %s.", member))
+		}
+	}
+
+	return sb.String()
+}
+
+// reports stashes the answers collected since the last summary.
+// Calls to its methods are thread-safe.
+type reports struct {
+	answers map[string][]string
+	mutex   sync.Mutex
+}
+
+func (r *reports) set(member string, answers []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.answers[member] = answers
+}
+
+func (r *reports) flush() map[string][]string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	answers := r.answers
+	r.answers = map[string][]string{}
+	return answers
+}
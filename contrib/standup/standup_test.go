@@ -0,0 +1,229 @@
+package standup
+
+import (
+	"context"
+	"github.com/oklahomer/go-sarah/v4"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyInput struct {
+	SenderKeyValue string
+	MessageValue   string
+	ReplyToValue   sarah.OutputDestination
+}
+
+func (i *DummyInput) SenderKey() string {
+	return i.SenderKeyValue
+}
+
+func (i *DummyInput) Message() string {
+	return i.MessageValue
+}
+
+func (i *DummyInput) SentAt() time.Time {
+	return time.Now()
+}
+
+func (i *DummyInput) ReplyTo() sarah.OutputDestination {
+	if i.ReplyToValue != nil {
+		return i.ReplyToValue
+	}
+	return i.SenderKeyValue
+}
+
+func destination(id string) sarah.OutputDestination {
+	return id
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config == nil {
+		t.Fatal("Expected *Config is not returned.")
+	}
+
+	if len(config.Questions) == 0 {
+		t.Error("Default questions are not set.")
+	}
+}
+
+func TestNew(t *testing.T) {
+	optCalled := false
+	s, err := New("dummy", NewConfig(), destination, func(_ *Standup) {
+		optCalled = true
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if s == nil {
+		t.Fatal("Standup is not returned.")
+	}
+
+	if !optCalled {
+		t.Error("Given Option is not applied.")
+	}
+}
+
+func TestNew_WithoutQuestion(t *testing.T) {
+	config := NewConfig()
+	config.Questions = []string{}
+	_, err := New("dummy", config, destination)
+	if err != ErrNoQuestion {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestWithMemberIdentifier(t *testing.T) {
+	fnc := func(_ sarah.Input) string {
+		return "member"
+	}
+	s := &Standup{}
+	WithMemberIdentifier(fnc)(s)
+
+	if s.identify == nil {
+		t.Fatal("Given function is not set.")
+	}
+
+	if s.identify(&DummyInput{}) != "member" {
+		t.Error("Unexpected function is set.")
+	}
+}
+
+func TestStandup_CommandProps(t *testing.T) {
+	config := NewConfig()
+	config.Questions = []string{"Q1", "Q2"}
+	s, _ := New("dummy", config, destination)
+	props := s.CommandProps()
+	if props == nil {
+		t.Fatal("CommandProps is not returned.")
+	}
+
+	// Proceed with the conversation.
+	ctx := context.TODO()
+	res := s.ask(0, []string{})
+	if res.Content != "Q1" {
+		t.Errorf("Unexpected question is returned: %s.", res.Content)
+	}
+
+	// Empty input results in asking the same question.
+	res, _ = res.UserContext.Next(ctx, &DummyInput{SenderKeyValue: "user", MessageValue: " "})
+	if res.Content != "Q1" {
+		t.Errorf("Unexpected question is returned: %s.", res.Content)
+	}
+
+	res, _ = res.UserContext.Next(ctx, &DummyInput{SenderKeyValue: "user", MessageValue: "A1"})
+	if res.Content != "Q2" {
+		t.Errorf("Unexpected question is returned: %s.", res.Content)
+	}
+
+	res, _ = res.UserContext.Next(ctx, &DummyInput{SenderKeyValue: "user", MessageValue: "A2"})
+	if res.UserContext != nil {
+		t.Error("UserContext should not be returned when all questions are answered.")
+	}
+
+	answers := s.reports.flush()
+	if len(answers["user"]) != 2 || answers["user"][0] != "A1" || answers["user"][1] != "A2" {
+		t.Errorf("Unexpected answers are stored: %#v.", answers)
+	}
+}
+
+func TestStandup_PromptTaskProps(t *testing.T) {
+	config := NewConfig()
+	config.Members = []string{"member1", "member2"}
+	s, _ := New("dummy", config, destination)
+
+	props := s.PromptTaskProps()
+	if props == nil {
+		t.Fatal("ScheduledTaskProps is not returned.")
+	}
+}
+
+func TestStandup_SummaryTaskProps(t *testing.T) {
+	config := NewConfig()
+	config.SummaryChannel = "channel"
+	s, _ := New("dummy", config, destination)
+
+	props := s.SummaryTaskProps()
+	if props == nil {
+		t.Fatal("ScheduledTaskProps is not returned.")
+	}
+}
+
+func TestStandup_member(t *testing.T) {
+	config := NewConfig()
+	config.Members = []string{"D1", "D2"}
+	s, _ := New("dummy", config, destination)
+
+	testSets := []struct {
+		input    *DummyInput
+		expected string
+	}{
+		{
+			// Reported in the direct message channel the invitation was sent to.
+			input:    &DummyInput{SenderKeyValue: "D2|U2", ReplyToValue: "D2"},
+			expected: "D2",
+		},
+		{
+			// Reported in a shared channel.
+			input:    &DummyInput{SenderKeyValue: "C1|U3", ReplyToValue: "C1"},
+			expected: "C1|U3",
+		},
+	}
+
+	for i, tt := range testSets {
+		member := s.member(tt.input)
+		if member != tt.expected {
+			t.Errorf("Unexpected member is returned on test #%d: %s.", i+1, member)
+		}
+	}
+
+	WithMemberIdentifier(func(_ sarah.Input) string {
+		return "D1"
+	})(s)
+	if member := s.member(testSets[1].input); member != "D1" {
+		t.Errorf("Given identifier is not used: %s.", member)
+	}
+}
+
+func TestStandup_summary(t *testing.T) {
+	config := NewConfig()
+	config.Questions = []string{"Q1"}
+	config.Members = []string{"D1", "D2"}
+	s, _ := New("dummy", config, destination)
+
+	summary := s.summary(map[string][]string{})
+	if summary != "No stand-up report was submitted." {
+		t.Errorf("Unexpected summary is returned: %s.", summary)
+	}
+
+	// The member reports in the direct message channel with a SenderKey that differs from the member ID.
+	res := s.ask(0, []string{})
+	_, _ = res.UserContext.Next(context.TODO(), &DummyInput{SenderKeyValue: "D1|U1", MessageValue: "A1", ReplyToValue: "D1"})
+
+	summary = s.summary(s.reports.flush())
+	if !strings.Contains(summary, "*D1*") || !strings.Contains(summary, "A1") {
+		t.Errorf("Answer is not included: %s.", summary)
+	}
+	if strings.Contains(summary, "No report from D1.") {
+		t.Errorf("Reported member is considered missing: %s.", summary)
+	}
+	if !strings.Contains(summary, "No report from D2.") {
+		t.Errorf("Missing member is not included: %s.", summary)
+	}
+}
+
+func Test_reports(t *testing.T) {
+	r := &reports{answers: map[string][]string{}}
+	r.set("member", []string{"answer"})
+
+	answers := r.flush()
+	if len(answers) != 1 {
+		t.Fatalf("Unexpected number of answers are returned: %d.", len(answers))
+	}
+
+	if len(r.answers) != 0 {
+		t.Error("Answers are not flushed.")
+	}
+}
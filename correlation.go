@@ -0,0 +1,50 @@
+package sarah
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// correlationSequence is used to generate a unique correlation ID when random bytes are not available.
+var correlationSequence uint64
+
+type correlationIDKey struct{}
+
+// CorrelationIDFromContext returns the correlation ID of the Input that is currently being handled.
+// Sarah generates a unique correlation ID for every received Input and logs it along with the Input's handling,
+// so a Command can include this in its own log lines or pass this to other components to trace a single user request.
+// The given context must be the one passed to Command's execution.
+// This returns an empty string when no correlation ID is set.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+func withCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// correlationBuffers pools the buffers to read random bytes since a correlation ID is generated on every Input reception.
+var correlationBuffers = sync.Pool{
+	New: func() interface{} {
+		return new([8]byte)
+	},
+}
+
+// newCorrelationID generates a new random correlation ID.
+func newCorrelationID() string {
+	buf := correlationBuffers.Get().(*[8]byte)
+	defer correlationBuffers.Put(buf)
+
+	b := buf[:]
+	_, err := rand.Read(b)
+	if err != nil {
+		return fmt.Sprintf("%x-%d", time.Now().UnixNano(), atomic.AddUint64(&correlationSequence, 1))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,60 @@
+package sarah
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCorrelationIDFromContext(t *testing.T) {
+	if id := CorrelationIDFromContext(context.TODO()); id != "" {
+		t.Errorf("Empty string is expected: %s.", id)
+	}
+
+	ctx := withCorrelationID(context.TODO(), "abc")
+	if id := CorrelationIDFromContext(ctx); id != "abc" {
+		t.Errorf("Unexpected ID is returned: %s.", id)
+	}
+}
+
+func Test_newCorrelationID(t *testing.T) {
+	id := newCorrelationID()
+	if len(id) != 16 {
+		t.Errorf("Unexpected ID is generated: %s.", id)
+	}
+
+	if newCorrelationID() == id {
+		t.Error("Same ID is generated.")
+	}
+}
+
+func Test_setupInputReceiver_CorrelationID(t *testing.T) {
+	worker := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			fnc()
+			return nil
+		},
+	}
+
+	var ids []string
+	bot := &DummyBot{
+		BotTypeValue: "DUMMY",
+		RespondFunc: func(ctx context.Context, _ Input) error {
+			ids = append(ids, CorrelationIDFromContext(ctx))
+			return nil
+		},
+	}
+
+	receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
+	_ = receiveInput(&DummyInput{})
+	_ = receiveInput(&DummyInput{})
+
+	if len(ids) != 2 {
+		t.Fatalf("Unexpected number of inputs are handled: %d.", len(ids))
+	}
+	if ids[0] == "" || ids[1] == "" {
+		t.Errorf("Correlation ID is not set: %#v.", ids)
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("Same correlation ID is given to different inputs: %s.", ids[0])
+	}
+}
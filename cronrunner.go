@@ -0,0 +1,189 @@
+package sarah
+
+import (
+	"github.com/robfig/cron/v3"
+	"sort"
+	"sync"
+	"time"
+)
+
+// clockCron runs the cron jobs at the activation times based on the Clock registered via RegisterClock.
+// This replaces the run loop of cron.Cron, which always relies on the system clock, so the execution timing of ScheduledTasks can be controlled in tests.
+// The schedules are still parsed and the jobs are still wrapped by the cron package.
+type clockCron struct {
+	location *time.Location
+	chain    cron.Chain
+	logger   cron.Logger
+	entries  map[cron.EntryID]*cron.Entry
+	nextID   cron.EntryID
+	changed  chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	mutex    sync.Mutex
+}
+
+func newClockCron(location *time.Location, chain cron.Chain, logger cron.Logger) *clockCron {
+	return &clockCron{
+		location: location,
+		chain:    chain,
+		logger:   logger,
+		entries:  map[cron.EntryID]*cron.Entry{},
+		changed:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Location returns the time zone that the schedules are evaluated in.
+func (c *clockCron) Location() *time.Location {
+	return c.location
+}
+
+// AddFunc parses the given spec in the standard cron format and schedules the given function.
+func (c *clockCron) AddFunc(spec string, fnc func()) (cron.EntryID, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return 0, err
+	}
+	return c.Schedule(schedule, cron.FuncJob(fnc)), nil
+}
+
+// Schedule schedules the given job with the given schedule.
+func (c *clockCron) Schedule(schedule cron.Schedule, job cron.Job) cron.EntryID {
+	now := c.now()
+	c.mutex.Lock()
+	c.nextID++
+	entry := &cron.Entry{
+		ID:         c.nextID,
+		Schedule:   schedule,
+		Next:       schedule.Next(now),
+		Job:        job,
+		WrappedJob: c.chain.Then(job),
+	}
+	c.entries[entry.ID] = entry
+	c.mutex.Unlock()
+
+	c.logger.Info("added", "now", now, "entry", entry.ID, "next", entry.Next)
+
+	c.notifyChange()
+	return entry.ID
+}
+
+// Remove stops the future executions of the job with the given ID.
+func (c *clockCron) Remove(id cron.EntryID) {
+	c.mutex.Lock()
+	delete(c.entries, id)
+	c.mutex.Unlock()
+
+	c.logger.Info("removed", "entry", id)
+
+	c.notifyChange()
+}
+
+// Entries returns the copies of the scheduled entries in order of their next activation times.
+func (c *clockCron) Entries() []cron.Entry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries := make([]cron.Entry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Next.Before(entries[j].Next)
+	})
+	return entries
+}
+
+// Start runs the scheduling loop in a new goroutine.
+func (c *clockCron) Start() {
+	go c.run()
+}
+
+// Stop stops the scheduling loop. The running jobs are not interrupted.
+func (c *clockCron) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+func (c *clockCron) now() time.Time {
+	return currentClock().Now().In(c.location)
+}
+
+func (c *clockCron) notifyChange() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+		// The loop is already notified.
+	}
+}
+
+// next returns the earliest activation time among the entries. The second returned value is false when no entry is scheduled.
+func (c *clockCron) next() (time.Time, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var next time.Time
+	for _, entry := range c.entries {
+		if entry.Next.IsZero() {
+			// Never runs again.
+			continue
+		}
+		if next.IsZero() || entry.Next.Before(next) {
+			next = entry.Next
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// runDue runs the jobs whose activation times are reached, and then calculates their next activation times.
+func (c *clockCron) runDue(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, entry := range c.entries {
+		if entry.Next.IsZero() || entry.Next.After(now) {
+			continue
+		}
+		go entry.WrappedJob.Run()
+		entry.Prev = entry.Next
+		entry.Next = entry.Schedule.Next(now)
+		c.logger.Info("run", "now", now, "entry", entry.ID, "next", entry.Next)
+	}
+}
+
+func (c *clockCron) run() {
+	// The entries added before Start are taken into account at the first iteration.
+	select {
+	case <-c.changed:
+	default:
+	}
+
+	for {
+		var timer Timer
+		var fired <-chan time.Time
+		if next, ok := c.next(); ok {
+			timer = currentClock().NewTimer(next.Sub(c.now()))
+			fired = timer.C()
+		}
+
+		select {
+		case <-fired:
+			c.runDue(c.now())
+
+		case <-c.changed:
+			// Recalculate the earliest activation time.
+
+		case <-c.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package sarah
+
+import (
+	"github.com/robfig/cron/v3"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_clockCron(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+	var mutex sync.Mutex
+	timers := make(chan chan time.Time, 10)
+	durations := make(chan time.Duration, 10)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return now
+		},
+		NewTimerFunc: func(d time.Duration) Timer {
+			ch := make(chan time.Time, 1)
+			durations <- d
+			timers <- ch
+			return &DummyTimer{CValue: ch}
+		},
+	}
+	advance := func(d time.Duration) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		now = now.Add(d)
+	}
+
+	withClock(c, func() {
+		cr := newClockCron(time.UTC, cron.NewChain(), cron.DiscardLogger)
+		executed := make(chan time.Time, 10)
+		_, err := cr.AddFunc("@hourly", func() {
+			executed <- c.Now()
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		cr.Start()
+		defer cr.Stop()
+
+		var timer chan time.Time
+		select {
+		case d := <-durations:
+			if d != time.Hour {
+				t.Errorf("Unexpected duration is given to the timer: %s.", d)
+			}
+			timer = <-timers
+
+		case <-time.NewTimer(time.Second).C:
+			t.Fatal("Timer is not set.")
+
+		}
+
+		select {
+		case <-executed:
+			t.Fatal("Job must not be executed before the clock reaches the activation time.")
+		default:
+			// O.K.
+		}
+
+		advance(time.Hour)
+		timer <- c.Now()
+
+		select {
+		case at := <-executed:
+			if !at.Equal(time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)) {
+				t.Errorf("Job is executed at unexpected time: %s.", at)
+			}
+
+		case <-time.NewTimer(time.Second).C:
+			t.Fatal("Job is not executed.")
+
+		}
+
+		select {
+		case d := <-durations:
+			if d != time.Hour {
+				t.Errorf("Unexpected duration is given to the next timer: %s.", d)
+			}
+			<-timers
+
+		case <-time.NewTimer(time.Second).C:
+			t.Fatal("Timer for the next activation is not set.")
+
+		}
+
+		entries := cr.Entries()
+		if len(entries) != 1 {
+			t.Fatalf("Unexpected number of entries are returned: %d.", len(entries))
+		}
+		if !entries[0].Next.Equal(time.Date(2026, time.January, 1, 11, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected next activation time: %s.", entries[0].Next)
+		}
+
+		cr.Remove(entries[0].ID)
+		if len(cr.Entries()) != 0 {
+			t.Error("Entry is not removed.")
+		}
+	})
+}
+
+func Test_clockCron_AddFunc_InvalidSpec(t *testing.T) {
+	cr := newClockCron(time.UTC, cron.NewChain(), cron.DiscardLogger)
+
+	_, err := cr.AddFunc("invalid", func() {})
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func Test_clockCron_oneShot(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		cr := newClockCron(time.UTC, cron.NewChain(), cron.DiscardLogger)
+		executed := false
+		cr.Schedule(&oneShotSchedule{at: now.Add(time.Minute)}, cron.FuncJob(func() {
+			executed = true
+		}))
+
+		next, ok := cr.next()
+		if !ok || !next.Equal(now.Add(time.Minute)) {
+			t.Fatalf("Unexpected next activation time: %s.", next)
+		}
+
+		cr.runDue(now)
+		if executed {
+			t.Error("Job must not be executed before the activation time.")
+		}
+
+		cr.runDue(now.Add(time.Minute))
+		if _, ok := cr.next(); ok {
+			t.Error("One-shot job must not be scheduled again.")
+		}
+	})
+}
@@ -0,0 +1,292 @@
+// Package daemon integrates Sarah's lifecycle with the service managers of the operating systems.
+//
+// On Linux, Run tells systemd that the service is ready once all Bots start, and pings the watchdog while SelfTest reports healthy.
+// Use this with a unit file such as below:
+//
+//	[Service]
+//	Type=notify
+//	WatchdogSec=30s
+//	ExecStart=/usr/local/bin/mybot
+//
+// On Windows, Run reports the service status to the service control manager and stops Sarah on a stop or shutdown request
+// when the process is started as a Windows service.
+//
+// In other environments, Run simply runs Sarah until the given context is canceled.
+//
+//	func main() {
+//		sarah.RegisterBot(bot)
+//		err := daemon.Run(context.Background(), daemon.DefaultRunner(), sarah.NewConfig(), daemon.NewConfig())
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"sync"
+	"time"
+)
+
+// Config contains some configuration variables for Run.
+type Config struct {
+	// ServiceName is the name of the Windows service. This is ignored on other operating systems.
+	ServiceName string `json:"service_name" yaml:"service_name"`
+
+	// ReadyCheckInterval declares the interval to check if all Bots are running before the service is reported as ready.
+	ReadyCheckInterval time.Duration `json:"ready_check_interval" yaml:"ready_check_interval"`
+
+	// ShutdownTimeout declares how long to wait for all Bots to stop after the service is requested to stop.
+	// Set this longer than sarah.DrainConfig.Timeout so the Bots can drain their in-flight Inputs.
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+}
+
+// NewConfig returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override the default values.
+func NewConfig() *Config {
+	return &Config{
+		ServiceName:        "sarah",
+		ReadyCheckInterval: 1 * time.Second,
+		ShutdownTimeout:    30 * time.Second,
+	}
+}
+
+// Runner defines the operations of Sarah that Run depends on. *sarah.Runner satisfies this.
+// Use DefaultRunner to run Sarah with the package-level functions such as sarah.RegisterBot.
+type Runner interface {
+	RegisterLifecycleHook(hook sarah.LifecycleHook)
+	Run(ctx context.Context, config *sarah.Config) error
+	Status() sarah.Status
+	SelfTest(ctx context.Context) (*sarah.SelfTestReport, error)
+}
+
+var _ Runner = (*sarah.Runner)(nil)
+
+type defaultRunner struct{}
+
+var _ Runner = (*defaultRunner)(nil)
+
+// DefaultRunner returns a Runner that operates on the default Sarah instance that the package-level functions such as sarah.Run operate on.
+func DefaultRunner() Runner {
+	return &defaultRunner{}
+}
+
+func (*defaultRunner) RegisterLifecycleHook(hook sarah.LifecycleHook) {
+	sarah.RegisterLifecycleHook(hook)
+}
+
+func (*defaultRunner) Run(ctx context.Context, config *sarah.Config) error {
+	return sarah.Run(ctx, config)
+}
+
+func (*defaultRunner) Status() sarah.Status {
+	return sarah.CurrentStatus()
+}
+
+func (*defaultRunner) SelfTest(ctx context.Context) (*sarah.SelfTestReport, error) {
+	return sarah.SelfTest(ctx)
+}
+
+// Run runs Sarah with the given Runner and blocks until Sarah stops.
+// The service manager is notified that the service is ready when all Bots start, and that the service is stopping when ctx is canceled.
+// Any registration to the Runner must be done before this call.
+//
+// When the process is started as a Windows service, a stop or shutdown request from the service control manager also stops Sarah.
+func Run(ctx context.Context, runner Runner, config *sarah.Config, daemonConfig *Config) error {
+	d := newDaemon(runner, daemonConfig, NewNotifier())
+
+	served, err := runService(ctx, d, config)
+	if served {
+		return err
+	}
+	return d.run(ctx, config, func() {})
+}
+
+type daemon struct {
+	runner   Runner
+	config   *Config
+	notifier *Notifier
+	started  map[sarah.BotType]struct{}
+	stopped  chan struct{}
+	mutex    sync.Mutex
+}
+
+func newDaemon(runner Runner, config *Config, notifier *Notifier) *daemon {
+	return &daemon{
+		runner:   runner,
+		config:   config,
+		notifier: notifier,
+		started:  map[sarah.BotType]struct{}{},
+		stopped:  make(chan struct{}),
+	}
+}
+
+// run runs Sarah and blocks until Sarah stops. The given onReady is called when all Bots start.
+func (d *daemon) run(ctx context.Context, config *sarah.Config, onReady func()) error {
+	d.runner.RegisterLifecycleHook(d.observe)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err := d.runner.Run(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if !d.waitReady(ctx) {
+			return
+		}
+
+		logger.Infof("All bots are running")
+		d.notify(StateReady)
+		onReady()
+	}()
+	go d.watchdog(ctx)
+
+	select {
+	case <-ctx.Done():
+		// Requested to stop.
+
+	case <-d.stopped:
+		// All Bots stopped by themselves.
+		return nil
+
+	}
+
+	d.notify(StateStopping)
+	timer := time.NewTimer(d.config.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-d.stopped:
+		return nil
+
+	case <-timer.C:
+		return fmt.Errorf("bots did not stop in %s", d.config.ShutdownTimeout)
+
+	}
+}
+
+// observe is a sarah.LifecycleHook that tracks the Bots' lifecycle.
+func (d *daemon) observe(event *sarah.LifecycleEvent) {
+	switch event.Type {
+	case sarah.BotStarted:
+		d.mutex.Lock()
+		d.started[event.BotType] = struct{}{}
+		d.mutex.Unlock()
+
+	case sarah.BotDraining:
+		d.notify(fmt.Sprintf("STATUS=%s draining", event.BotType))
+
+	case sarah.BotStopped:
+		d.mutex.Lock()
+		delete(d.started, event.BotType)
+		d.mutex.Unlock()
+		d.notify(fmt.Sprintf("STATUS=%s stopped: %v", event.BotType, event.Reason))
+
+	case sarah.RunnerStopped:
+		if errors.Is(event.Reason, sarah.ErrRunnerRestarting) {
+			// The Bots start again with a new runner.
+			d.notify("STATUS=restarting")
+			return
+		}
+		close(d.stopped)
+
+	}
+}
+
+// waitReady blocks until all Bots are running, and then returns true. False is returned when ctx is canceled.
+func (d *daemon) waitReady(ctx context.Context) bool {
+	ticker := time.NewTicker(d.config.ReadyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if d.ready() {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+
+		case <-ticker.C:
+			// Check again.
+
+		}
+	}
+}
+
+// ready tells if every Bot has started. A Bot on hot standby for its leadership is considered ready.
+func (d *daemon) ready() bool {
+	status := d.runner.Status()
+	if !status.Running || len(status.Bots) == 0 {
+		return false
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, bot := range status.Bots {
+		if !bot.Running {
+			return false
+		}
+		if bot.Leadership == sarah.LeadershipFollower {
+			continue
+		}
+		if _, ok := d.started[bot.Type]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// watchdog pings the systemd watchdog at the half of its timeout while SelfTest reports healthy,
+// so systemd restarts the process when Sarah is stuck.
+func (d *daemon) watchdog(ctx context.Context) {
+	timeout := d.notifier.WatchdogInterval()
+	if timeout <= 0 {
+		return
+	}
+
+	interval := timeout / 2
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if d.healthy(ctx, interval) {
+				d.notify(StateWatchdog)
+			}
+
+		}
+	}
+}
+
+func (d *daemon) healthy(ctx context.Context, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	report, err := d.runner.SelfTest(ctx)
+	if err != nil {
+		logger.Warnf("Skip watchdog ping due to self-test failure: %+v", err)
+		return false
+	}
+	if !report.Healthy() {
+		logger.Warnf("Skip watchdog ping due to unhealthy self-test result: %+v", report)
+		return false
+	}
+	return true
+}
+
+func (d *daemon) notify(state string) {
+	err := d.notifier.Notify(state)
+	if err != nil {
+		logger.Errorf("Failed to notify service manager: %+v", err)
+	}
+}
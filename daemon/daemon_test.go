@@ -0,0 +1,303 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	oldLogger := logger.GetLogger()
+	defer logger.SetLogger(oldLogger)
+
+	l := log.New(io.Discard, "dummyLog", 0)
+	logger.SetLogger(logger.NewWithStandardLogger(l))
+
+	code := m.Run()
+
+	os.Exit(code)
+}
+
+type DummyRunner struct {
+	RegisterLifecycleHookFunc func(sarah.LifecycleHook)
+	RunFunc                   func(context.Context, *sarah.Config) error
+	StatusFunc                func() sarah.Status
+	SelfTestFunc              func(context.Context) (*sarah.SelfTestReport, error)
+}
+
+func (r *DummyRunner) RegisterLifecycleHook(hook sarah.LifecycleHook) {
+	r.RegisterLifecycleHookFunc(hook)
+}
+
+func (r *DummyRunner) Run(ctx context.Context, config *sarah.Config) error {
+	return r.RunFunc(ctx, config)
+}
+
+func (r *DummyRunner) Status() sarah.Status {
+	return r.StatusFunc()
+}
+
+func (r *DummyRunner) SelfTest(ctx context.Context) (*sarah.SelfTestReport, error) {
+	return r.SelfTestFunc(ctx)
+}
+
+// listen starts receiving the states sent to a notification socket.
+func listen(t *testing.T) (*Notifier, <-chan string) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("Unix datagram socket is not available: %s.", err.Error())
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	states := make(chan string, 10)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			states <- string(buf[:n])
+		}
+	}()
+
+	return &Notifier{socket: socket, watchdogInterval: 20 * time.Millisecond}, states
+}
+
+func receiveState(t *testing.T, states <-chan string) string {
+	select {
+	case state := <-states:
+		return state
+
+	case <-time.After(time.Second):
+		t.Fatal("State is not sent.")
+		return ""
+
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+
+	if config.ServiceName == "" || config.ReadyCheckInterval <= 0 || config.ShutdownTimeout <= 0 {
+		t.Errorf("Unexpected default setting: %#v.", config)
+	}
+}
+
+func Test_daemon_run(t *testing.T) {
+	notifier, states := listen(t)
+
+	var hook sarah.LifecycleHook
+	status := sarah.Status{}
+	runner := &DummyRunner{
+		RegisterLifecycleHookFunc: func(h sarah.LifecycleHook) {
+			hook = h
+		},
+		RunFunc: func(ctx context.Context, _ *sarah.Config) error {
+			status = sarah.Status{
+				Running: true,
+				Bots: []sarah.BotStatus{
+					{Type: "slack", Running: true},
+					{Type: "gitter", Running: true, Leadership: sarah.LeadershipFollower},
+				},
+			}
+			go func() {
+				hook(&sarah.LifecycleEvent{Type: sarah.BotStarted, BotType: "slack"})
+				<-ctx.Done()
+				hook(&sarah.LifecycleEvent{Type: sarah.BotStopped, BotType: "slack", Reason: ctx.Err()})
+				hook(&sarah.LifecycleEvent{Type: sarah.RunnerStopped})
+			}()
+			return nil
+		},
+		StatusFunc: func() sarah.Status {
+			return status
+		},
+		SelfTestFunc: func(_ context.Context) (*sarah.SelfTestReport, error) {
+			return &sarah.SelfTestReport{}, nil
+		},
+	}
+	config := &Config{ReadyCheckInterval: 10 * time.Millisecond, ShutdownTimeout: time.Second}
+	d := newDaemon(runner, config, notifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	finished := make(chan error, 1)
+	go func() {
+		finished <- d.run(ctx, sarah.NewConfig(), func() {
+			close(ready)
+		})
+	}()
+
+	// The watchdog may be pinged before the readiness.
+	state := receiveState(t, states)
+	for state == StateWatchdog {
+		state = receiveState(t, states)
+	}
+	if state != StateReady {
+		t.Fatalf("Unexpected state is sent: %s.", state)
+	}
+	<-ready
+
+	if state := receiveState(t, states); state != StateWatchdog {
+		t.Errorf("Unexpected state is sent: %s.", state)
+	}
+
+	cancel()
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Errorf("Unexpected error is returned: %s.", err.Error())
+		}
+
+	case <-time.After(time.Second):
+		t.Fatal("Daemon did not stop.")
+
+	}
+}
+
+func Test_daemon_run_Error(t *testing.T) {
+	expected := errors.New("invalid config")
+	runner := &DummyRunner{
+		RegisterLifecycleHookFunc: func(_ sarah.LifecycleHook) {},
+		RunFunc: func(_ context.Context, _ *sarah.Config) error {
+			return expected
+		},
+	}
+	d := newDaemon(runner, NewConfig(), &Notifier{})
+
+	err := d.run(context.Background(), sarah.NewConfig(), func() {})
+	if err != expected {
+		t.Errorf("Unexpected error is returned: %#v.", err)
+	}
+}
+
+func Test_daemon_run_ShutdownTimeout(t *testing.T) {
+	runner := &DummyRunner{
+		RegisterLifecycleHookFunc: func(_ sarah.LifecycleHook) {},
+		RunFunc: func(_ context.Context, _ *sarah.Config) error {
+			return nil
+		},
+		StatusFunc: func() sarah.Status {
+			return sarah.Status{}
+		},
+	}
+	d := newDaemon(runner, &Config{ReadyCheckInterval: 10 * time.Millisecond, ShutdownTimeout: 10 * time.Millisecond}, &Notifier{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := d.run(ctx, sarah.NewConfig(), func() {})
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func Test_daemon_ready(t *testing.T) {
+	testSets := []struct {
+		status   sarah.Status
+		started  []sarah.BotType
+		expected bool
+	}{
+		{
+			status:   sarah.Status{},
+			expected: false,
+		},
+		{
+			status:   sarah.Status{Running: true},
+			expected: false,
+		},
+		{
+			status:   sarah.Status{Running: true, Bots: []sarah.BotStatus{{Type: "slack", Running: true}}},
+			expected: false,
+		},
+		{
+			status:   sarah.Status{Running: true, Bots: []sarah.BotStatus{{Type: "slack", Running: true}}},
+			started:  []sarah.BotType{"slack"},
+			expected: true,
+		},
+		{
+			status:   sarah.Status{Running: true, Bots: []sarah.BotStatus{{Type: "slack", Running: false}}},
+			started:  []sarah.BotType{"slack"},
+			expected: false,
+		},
+		{
+			status:   sarah.Status{Running: true, Bots: []sarah.BotStatus{{Type: "slack", Running: true, Leadership: sarah.LeadershipFollower}}},
+			expected: true,
+		},
+	}
+
+	for i, tt := range testSets {
+		runner := &DummyRunner{
+			StatusFunc: func() sarah.Status {
+				return tt.status
+			},
+		}
+		d := newDaemon(runner, NewConfig(), &Notifier{})
+		for _, botType := range tt.started {
+			d.observe(&sarah.LifecycleEvent{Type: sarah.BotStarted, BotType: botType})
+		}
+
+		if d.ready() != tt.expected {
+			t.Errorf("Unexpected readiness on test #%d.", i+1)
+		}
+	}
+}
+
+func Test_daemon_observe_Restart(t *testing.T) {
+	d := newDaemon(&DummyRunner{}, NewConfig(), &Notifier{})
+
+	d.observe(&sarah.LifecycleEvent{Type: sarah.RunnerStopped, Reason: sarah.ErrRunnerRestarting})
+	select {
+	case <-d.stopped:
+		t.Fatal("Restart should not be considered as a stop.")
+
+	default:
+		// O.K.
+
+	}
+
+	d.observe(&sarah.LifecycleEvent{Type: sarah.RunnerStopped})
+	select {
+	case <-d.stopped:
+		// O.K.
+
+	default:
+		t.Fatal("Stop is not observed.")
+
+	}
+}
+
+func Test_daemon_healthy(t *testing.T) {
+	testSets := []struct {
+		report   *sarah.SelfTestReport
+		err      error
+		expected bool
+	}{
+		{report: &sarah.SelfTestReport{}, expected: true},
+		{report: &sarah.SelfTestReport{Worker: &sarah.SelfTestResult{Error: "timeout"}}, expected: false},
+		{err: sarah.ErrRunnerNotRunning, expected: false},
+	}
+
+	for i, tt := range testSets {
+		runner := &DummyRunner{
+			SelfTestFunc: func(_ context.Context) (*sarah.SelfTestReport, error) {
+				return tt.report, tt.err
+			},
+		}
+		d := newDaemon(runner, NewConfig(), &Notifier{})
+
+		if d.healthy(context.Background(), time.Second) != tt.expected {
+			t.Errorf("Unexpected health on test #%d.", i+1)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// StateReady tells systemd that the service startup is finished.
+	StateReady = "READY=1"
+
+	// StateStopping tells systemd that the service is beginning its shutdown.
+	StateStopping = "STOPPING=1"
+
+	// StateWatchdog tells systemd to update the watchdog timestamp.
+	StateWatchdog = "WATCHDOG=1"
+)
+
+// Notifier sends the service state to systemd via the socket given as $NOTIFY_SOCKET, which is the protocol that sd_notify(3) implements.
+// All methods are no-op when the process is not started by systemd with Type=notify, so the same binary can run anywhere.
+type Notifier struct {
+	socket           string
+	watchdogInterval time.Duration
+}
+
+// NewNotifier creates a new Notifier with the environment variables that systemd sets.
+func NewNotifier() *Notifier {
+	return newNotifier(os.Getenv, os.Getpid())
+}
+
+func newNotifier(getenv func(string) string, pid int) *Notifier {
+	notifier := &Notifier{
+		socket: getenv("NOTIFY_SOCKET"),
+	}
+
+	// The watchdog is for this process only when WATCHDOG_PID is not set or matches.
+	if watchdogPID := getenv("WATCHDOG_PID"); watchdogPID == "" || watchdogPID == strconv.Itoa(pid) {
+		usec, err := strconv.ParseInt(getenv("WATCHDOG_USEC"), 10, 64)
+		if err == nil && usec > 0 {
+			notifier.watchdogInterval = time.Duration(usec) * time.Microsecond
+		}
+	}
+
+	return notifier
+}
+
+// Enabled tells if the process is started by systemd with Type=notify.
+func (n *Notifier) Enabled() bool {
+	return n != nil && n.socket != ""
+}
+
+// WatchdogInterval returns the timeout of the watchdog that systemd configures with WatchdogSec.
+// Zero is returned when the watchdog is disabled.
+func (n *Notifier) WatchdogInterval() time.Duration {
+	if !n.Enabled() {
+		return 0
+	}
+	return n.watchdogInterval
+}
+
+// Notify sends the given state such as StateReady and "STATUS=..." to systemd.
+func (n *Notifier) Notify(state string) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	addr := &net.UnixAddr{
+		Name: n.socket,
+		Net:  "unixgram",
+	}
+	if strings.HasPrefix(addr.Name, "@") {
+		// Abstract socket
+		addr.Name = "\x00" + addr.Name[1:]
+	}
+
+	conn, err := net.DialUnix(addr.Net, nil, addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to notification socket: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	if err != nil {
+		return fmt.Errorf("failed to send %q: %w", state, err)
+	}
+	return nil
+}
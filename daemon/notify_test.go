@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewNotifier(t *testing.T) {
+	testSets := []struct {
+		env      map[string]string
+		enabled  bool
+		interval time.Duration
+	}{
+		{
+			env:      map[string]string{},
+			enabled:  false,
+			interval: 0,
+		},
+		{
+			env:      map[string]string{"NOTIFY_SOCKET": "/run/systemd/notify"},
+			enabled:  true,
+			interval: 0,
+		},
+		{
+			env:      map[string]string{"NOTIFY_SOCKET": "/run/systemd/notify", "WATCHDOG_USEC": "30000000"},
+			enabled:  true,
+			interval: 30 * time.Second,
+		},
+		{
+			env:      map[string]string{"NOTIFY_SOCKET": "/run/systemd/notify", "WATCHDOG_USEC": "30000000", "WATCHDOG_PID": "123"},
+			enabled:  true,
+			interval: 30 * time.Second,
+		},
+		{
+			env:      map[string]string{"NOTIFY_SOCKET": "/run/systemd/notify", "WATCHDOG_USEC": "30000000", "WATCHDOG_PID": "456"},
+			enabled:  true,
+			interval: 0,
+		},
+		{
+			env:      map[string]string{"WATCHDOG_USEC": "30000000"},
+			enabled:  false,
+			interval: 0,
+		},
+	}
+
+	for i, tt := range testSets {
+		notifier := newNotifier(func(key string) string {
+			return tt.env[key]
+		}, 123)
+
+		if notifier.Enabled() != tt.enabled {
+			t.Errorf("Unexpected enabled state on test #%d: %t.", i+1, notifier.Enabled())
+		}
+		if notifier.WatchdogInterval() != tt.interval {
+			t.Errorf("Unexpected interval on test #%d: %s.", i+1, notifier.WatchdogInterval())
+		}
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("Unix datagram socket is not available: %s.", err.Error())
+	}
+	defer conn.Close()
+
+	notifier := &Notifier{socket: socket}
+	err = notifier.Notify(StateReady)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	buf := make([]byte, 64)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error on read: %s.", err.Error())
+	}
+	if string(buf[:n]) != StateReady {
+		t.Errorf("Unexpected state is sent: %s.", string(buf[:n]))
+	}
+}
+
+func TestNotifier_Notify_Disabled(t *testing.T) {
+	var notifier *Notifier
+	if notifier.Notify(StateReady) != nil {
+		t.Error("Disabled notifier must not return error.")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"context"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// runService returns false since there is no service control manager to interact with.
+// systemd is notified via Notifier instead.
+func runService(_ context.Context, _ *daemon, _ *sarah.Config) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,87 @@
+//go:build windows
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"golang.org/x/sys/windows/svc"
+)
+
+// runService runs Sarah as a Windows service when the process is started by the service control manager.
+// This returns false when the process is started in other ways such as from a console.
+func runService(ctx context.Context, d *daemon, config *sarah.Config) (bool, error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return true, fmt.Errorf("failed to detect Windows service: %w", err)
+	}
+	if !isService {
+		return false, nil
+	}
+
+	handler := &serviceHandler{
+		ctx:    ctx,
+		daemon: d,
+		config: config,
+	}
+	err = svc.Run(d.config.ServiceName, handler)
+	if err != nil {
+		return true, fmt.Errorf("failed to run Windows service %s: %w", d.config.ServiceName, err)
+	}
+	return true, handler.err
+}
+
+// serviceHandler is a svc.Handler that runs Sarah and reports the service status.
+type serviceHandler struct {
+	ctx    context.Context
+	daemon *daemon
+	config *sarah.Config
+	err    error
+}
+
+var _ svc.Handler = (*serviceHandler)(nil)
+
+// Execute runs Sarah until the service control manager requests to stop, or until Sarah stops by itself.
+func (h *serviceHandler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+
+	finished := make(chan error, 1)
+	go func() {
+		finished <- h.daemon.run(ctx, h.config, func() {
+			changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+		})
+	}()
+
+	for {
+		select {
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				changes <- request.CurrentStatus
+
+			case svc.Stop, svc.Shutdown:
+				logger.Infof("Stopping Windows service %s", h.daemon.config.ServiceName)
+				changes <- svc.Status{State: svc.StopPending}
+				cancel()
+
+			default:
+				logger.Warnf("Unexpected service control request: %d", request.Cmd)
+
+			}
+
+		case err := <-finished:
+			changes <- svc.Status{State: svc.StopPending}
+			h.err = err
+			if err != nil {
+				return false, 1
+			}
+			return false, 0
+
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/logger"
+	"sync"
+	"time"
+)
+
+// ErrBotDraining indicates that the Bot is draining its in-flight Inputs on shutdown and hence does not accept a new Input.
+// An AckableInput is acknowledged with this error so the chat service can redeliver it to another replica.
+var ErrBotDraining = errors.New("bot is draining")
+
+// DrainConfig declares how a Bot drains its in-flight Inputs when Sarah stops the Bot on shutdown.
+//
+// When the context given to Run is canceled, the Bot stops accepting new Inputs,
+// waits for the Inputs that are already enqueued to the worker to be handled within Timeout,
+// and then its context is canceled so the Adapter can close the connection.
+// During the drain, the Bot's context stays active so the Commands can complete and send their responses.
+// The same applies when the replica loses the leadership of the Bot. See RegisterLeaderElector.
+//
+// The worker that Sarah creates by default keeps running until every Bot stops.
+// When a worker.Worker is registered via RegisterWorker, run it with a context that outlives the drain; otherwise the queued Inputs are lost.
+type DrainConfig struct {
+	// Timeout declares how long to wait for the in-flight Inputs. Zero value disables the drain so the Bot's context is canceled immediately.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// DrainStats represents the state of the Bot's drain on shutdown.
+type DrainStats struct {
+	// StartedAt is the time when the Bot stopped accepting new Inputs.
+	StartedAt time.Time
+
+	// FinishedAt is the time when the Bot's context was canceled after the drain. This is zero while the drain is in progress.
+	FinishedAt time.Time
+
+	// Pending is the number of the Inputs that are waiting in the worker's queue or being handled.
+	// After the drain, this is the number of the Inputs that were abandoned at the deadline.
+	Pending int
+
+	// Rejected is the number of the Inputs that were rejected with ErrBotDraining during the drain.
+	Rejected int
+
+	// TimedOut indicates if the drain reached DrainConfig.Timeout before all in-flight Inputs were handled.
+	TimedOut bool
+}
+
+// drain returns the DrainConfig of the given BotType from the current Config.
+func (r *runner) drain(botType BotType) *DrainConfig {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.config == nil {
+		return nil
+	}
+	return r.config.Drain[botType]
+}
+
+// setBotDrainer lets the given inputDrainer report the drain of the Bot's current context via CurrentStatus.
+func (r *runner) setBotDrainer(drainer *inputDrainer) {
+	if r.status == nil {
+		return
+	}
+	r.status.setBotDrainer(drainer)
+}
+
+// inputDrainer tracks the drain of a Bot's context.
+type inputDrainer struct {
+	botType  BotType
+	queue    *inputQueueTracker
+	draining bool
+	stats    DrainStats
+	mutex    sync.Mutex
+}
+
+// start makes the Bot reject new Inputs.
+func (d *inputDrainer) start() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.draining = true
+	d.stats.StartedAt = currentClock().Now()
+}
+
+// reject tells if the given Input must be rejected, and counts the rejection.
+func (d *inputDrainer) reject() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.draining {
+		return false
+	}
+	d.stats.Rejected++
+	return true
+}
+
+// finish records the result of the drain.
+func (d *inputDrainer) finish(pending int, timedOut bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.stats.FinishedAt = currentClock().Now()
+	d.stats.Pending = pending
+	d.stats.TimedOut = timedOut
+}
+
+// snapshot returns a copy of the current state. Nil is returned when the drain has not started.
+func (d *inputDrainer) snapshot() *DrainStats {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.draining {
+		return nil
+	}
+	stats := d.stats
+	if stats.FinishedAt.IsZero() {
+		stats.Pending = d.queue.count(d.botType)
+	}
+	return &stats
+}
+
+// drainingInputReceiver returns a function that rejects the given Input with ErrBotDraining while the Bot is draining.
+func drainingInputReceiver(drainer *inputDrainer, receive func(Input) error) func(Input) error {
+	return func(input Input) error {
+		if !drainer.reject() {
+			return receive(input)
+		}
+
+		if batch, ok := input.(*BatchInput); ok {
+			for _, item := range batch.Inputs {
+				ackInput(item, ErrBotDraining)
+			}
+		} else {
+			ackInput(input, ErrBotDraining)
+		}
+		return ErrBotDraining
+	}
+}
+
+// drainContext derives a context from the given runnerCtx for the Bot with the given DrainConfig.
+// The derived context outlives runnerCtx until the Bot's in-flight Inputs are handled or the timeout is reached,
+// and is then canceled with the cause of runnerCtx's cancellation.
+// The returned function must be called when the Bot stops so the drain does not start after that.
+func (r *runner) drainContext(runnerCtx context.Context, drainer *inputDrainer, config *DrainConfig) (context.Context, func()) {
+	if config == nil || config.Timeout <= 0 {
+		return runnerCtx, func() {}
+	}
+
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(runnerCtx))
+	go func() {
+		select {
+		case <-ctx.Done():
+			// The Bot stopped by itself.
+			return
+
+		case <-runnerCtx.Done():
+			r.drainInputs(drainer, config.Timeout)
+			cancel(context.Cause(runnerCtx))
+
+		}
+	}()
+
+	return ctx, func() {
+		cancel(context.Canceled)
+	}
+}
+
+// drainInputs stops accepting new Inputs, and blocks until the in-flight Inputs are handled or the timeout is reached.
+func (r *runner) drainInputs(drainer *inputDrainer, timeout time.Duration) {
+	botType := drainer.botType
+	logger.Infof("Draining %s in %s. In-flight inputs: %d", botType, timeout, drainer.queue.count(botType))
+	drainer.start()
+	r.notifyLifecycleEvent(BotDraining, botType, nil)
+
+	timer := currentClock().NewTimer(timeout)
+	defer timer.Stop()
+
+	timedOut := false
+	select {
+	case <-drainer.queue.idle(botType):
+		// All in-flight Inputs are handled.
+
+	case <-timer.C():
+		timedOut = true
+
+	}
+
+	pending := drainer.queue.count(botType)
+	drainer.finish(pending, timedOut)
+	if timedOut {
+		logger.Warnf("Drain of %s timed out. Abandoned inputs: %d", botType, pending)
+	} else {
+		logger.Infof("Drained %s", botType)
+	}
+	r.notifyLifecycleEvent(BotDrained, botType, nil)
+}
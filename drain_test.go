@@ -0,0 +1,253 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_inputQueueTracker_idle(t *testing.T) {
+	tracker := &inputQueueTracker{entries: map[string]*QueuedInput{}}
+
+	select {
+	case <-tracker.idle("dummy"):
+		// O.K.
+
+	default:
+		t.Fatal("Channel should be closed when no input is tracked.")
+
+	}
+
+	tracker.add("first", "dummy", &DummyInput{})
+	tracker.add("second", "dummy", &DummyInput{})
+	tracker.add("other", "other", &DummyInput{})
+	idle := tracker.idle("dummy")
+
+	tracker.remove("first")
+	tracker.remove("other")
+	select {
+	case <-idle:
+		t.Fatal("Channel should not be closed while an input is tracked.")
+
+	default:
+		// O.K.
+
+	}
+	if tracker.count("dummy") != 1 {
+		t.Errorf("Unexpected count is returned: %d.", tracker.count("dummy"))
+	}
+
+	tracker.remove("second")
+	select {
+	case <-idle:
+		// O.K.
+
+	default:
+		t.Fatal("Channel should be closed when all inputs are removed.")
+
+	}
+}
+
+func Test_drainingInputReceiver(t *testing.T) {
+	drainer := &inputDrainer{botType: "dummy", queue: newInputQueueTracker()}
+	received := 0
+	receive := drainingInputReceiver(drainer, func(_ Input) error {
+		received++
+		return nil
+	})
+
+	err := receive(&DummyInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	drainer.start()
+	var acked error
+	input := &DummyAckableInput{
+		AckFunc: func(err error) {
+			acked = err
+		},
+	}
+	err = receive(input)
+	if !errors.Is(err, ErrBotDraining) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+	if !errors.Is(acked, ErrBotDraining) {
+		t.Errorf("Input is not acknowledged with the expected error: %#v.", acked)
+	}
+	if received != 1 {
+		t.Errorf("Input should not be passed while draining: %d.", received)
+	}
+
+	stats := drainer.snapshot()
+	if stats == nil || stats.Rejected != 1 || stats.StartedAt.IsZero() || !stats.FinishedAt.IsZero() {
+		t.Errorf("Unexpected stats are returned: %#v.", stats)
+	}
+}
+
+func Test_runner_drainContext(t *testing.T) {
+	t.Run("Without config", func(t *testing.T) {
+		r := &runner{}
+		runnerCtx := context.Background()
+
+		ctx, stop := r.drainContext(runnerCtx, &inputDrainer{botType: "dummy", queue: newInputQueueTracker()}, nil)
+		defer stop()
+
+		if ctx != runnerCtx {
+			t.Error("Given context should be returned.")
+		}
+	})
+
+	t.Run("Stopped by itself", func(t *testing.T) {
+		r := &runner{}
+		drainer := &inputDrainer{botType: "dummy", queue: newInputQueueTracker()}
+		runnerCtx, cancelRunner := context.WithCancel(context.Background())
+		defer cancelRunner()
+
+		ctx, stop := r.drainContext(runnerCtx, drainer, &DrainConfig{Timeout: time.Minute})
+		stop()
+
+		if ctx.Err() == nil {
+			t.Error("Context should be canceled.")
+		}
+		if drainer.snapshot() != nil {
+			t.Error("Drain should not start.")
+		}
+	})
+}
+
+func Test_runner_runBot_WithDrain(t *testing.T) {
+	var botType BotType = "draining"
+	done := make(chan struct{})
+	received := make(chan func(Input) error, 1)
+	bot := &DummyBot{
+		BotTypeValue:      botType,
+		AppendCommandFunc: func(_ Command) {},
+		RespondFunc: func(_ context.Context, _ Input) error {
+			<-done
+			return nil
+		},
+		RunFunc: func(ctx context.Context, receive func(Input) error, _ func(error)) {
+			received <- receive
+			<-ctx.Done()
+		},
+	}
+	var events []LifecycleEventType
+	s := &status{}
+	s.addBot(bot)
+	r := &runner{
+		config: &Config{
+			Drain: map[BotType]*DrainConfig{botType: {Timeout: time.Minute}},
+		},
+		configWatcher: &nullConfigWatcher{},
+		alerters:      &alerters{},
+		worker: &DummyWorker{
+			EnqueueFunc: func(fnc func()) error {
+				go fnc()
+				return nil
+			},
+		},
+		lifecycleHooks: []LifecycleHook{
+			func(event *LifecycleEvent) {
+				events = append(events, event.Type)
+			},
+		},
+		status: s,
+	}
+
+	runnerCtx, cancelRunner := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- r.runBot(runnerCtx, bot)
+	}()
+
+	receive := <-received
+	err := receive(&DummyInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	cancelRunner()
+	time.Sleep(100 * time.Millisecond)
+
+	select {
+	case <-stopped:
+		t.Fatal("Bot should not stop while an input is in flight.")
+
+	default:
+		// O.K.
+
+	}
+
+	err = receive(&DummyInput{})
+	if !errors.Is(err, ErrBotDraining) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	stats := s.snapshot().Bots[0].Drain
+	if stats == nil || stats.Pending != 1 || stats.Rejected != 1 {
+		t.Errorf("Unexpected stats are returned: %#v.", stats)
+	}
+
+	close(done)
+	select {
+	case cause := <-stopped:
+		if !errors.Is(cause, context.Canceled) {
+			t.Errorf("Unexpected cause is returned: %#v.", cause)
+		}
+
+	case <-time.NewTimer(10 * time.Second).C:
+		t.Fatal("Bot did not stop.")
+
+	}
+
+	status := s.snapshot().Bots[0]
+	if status.Drain == nil || status.Drain.FinishedAt.IsZero() || status.Drain.Pending != 0 || status.Drain.TimedOut {
+		t.Errorf("Unexpected stats are returned: %#v.", status.Drain)
+	}
+	if status.Contexts[0].Reason != CancellationRunnerStop {
+		t.Errorf("Unexpected reason is recorded: %s.", status.Contexts[0].Reason)
+	}
+
+	expected := []LifecycleEventType{BotStarting, BotStarted, BotDraining, BotDrained}
+	if len(events) != len(expected) {
+		t.Fatalf("Unexpected events are notified: %#v.", events)
+	}
+	for i, eventType := range expected {
+		if events[i] != eventType {
+			t.Errorf("Unexpected event is notified at %d: %s.", i, events[i])
+		}
+	}
+}
+
+func Test_runner_drainInputs_Timeout(t *testing.T) {
+	var botType BotType = "stuck"
+	r := &runner{}
+	drainer := &inputDrainer{botType: botType, queue: newInputQueueTracker()}
+	drainer.queue.add("stuck", botType, &DummyInput{})
+	defer drainer.queue.remove("stuck")
+
+	r.drainInputs(drainer, 10*time.Millisecond)
+
+	stats := drainer.snapshot()
+	if stats == nil || !stats.TimedOut || stats.Pending != 1 || stats.FinishedAt.IsZero() {
+		t.Errorf("Unexpected stats are returned: %#v.", stats)
+	}
+}
+
+func Test_status_snapshot_Drain(t *testing.T) {
+	s := &status{}
+	s.addBot(&DummyBot{BotTypeValue: "dummy"})
+
+	drainer := &inputDrainer{botType: "dummy", queue: newInputQueueTracker()}
+	s.setBotDrainer(drainer)
+	if s.snapshot().Bots[0].Drain != nil {
+		t.Error("Drain should not be returned before it starts.")
+	}
+
+	drainer.start()
+	if s.snapshot().Bots[0].Drain == nil {
+		t.Error("Drain should be returned.")
+	}
+}
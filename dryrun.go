@@ -0,0 +1,104 @@
+package sarah
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MutatingCommand is an optional interface that a Command can implement to tell that the Command modifies external state,
+// e.g. deploys an application, restarts a server, or updates a database.
+// While the dry-run mode is enabled via SetDryRun or SetCommandDryRun, such a Command is not executed and Preview is called instead.
+//
+// A Command built from CommandProps implements this interface, and CommandPropsBuilder.Mutating marks the Command as mutating.
+type MutatingCommand interface {
+	Command
+
+	// Mutating returns true when the Command modifies external state and hence must not be executed in the dry-run mode.
+	Mutating() bool
+
+	// Preview returns a response that describes what the Command would do with the given Input without actually executing it.
+	Preview(context.Context, Input) (*CommandResponse, error)
+}
+
+var dryRun = struct {
+	enabled  bool
+	commands map[string]struct{}
+	mutex    sync.RWMutex
+}{
+	commands: map[string]struct{}{},
+}
+
+// SetDryRun enables or disables the dry-run mode for all Commands.
+// While this is enabled, MutatingCommand implementations that report Mutating() as true are not executed but return their preview responses.
+// This can be called at runtime, e.g., from an administrative Command during an incident response or on testing configuration reloads.
+func SetDryRun(enabled bool) {
+	dryRun.mutex.Lock()
+	defer dryRun.mutex.Unlock()
+	dryRun.enabled = enabled
+}
+
+// DryRun tells if the dry-run mode is enabled for all Commands via SetDryRun.
+func DryRun() bool {
+	dryRun.mutex.RLock()
+	defer dryRun.mutex.RUnlock()
+	return dryRun.enabled
+}
+
+// SetCommandDryRun enables or disables the dry-run mode for the Commands with the given identifier.
+// This is effective regardless of SetDryRun, so a suspicious Command can be stopped while other Commands keep working.
+func SetCommandDryRun(identifier string, enabled bool) {
+	dryRun.mutex.Lock()
+	defer dryRun.mutex.Unlock()
+	if enabled {
+		dryRun.commands[identifier] = struct{}{}
+		return
+	}
+	delete(dryRun.commands, identifier)
+}
+
+// DryRunCommands returns the sorted identifiers of the Commands whose dry-run mode is enabled via SetCommandDryRun.
+func DryRunCommands() []string {
+	dryRun.mutex.RLock()
+	defer dryRun.mutex.RUnlock()
+
+	identifiers := make([]string, 0, len(dryRun.commands))
+	for identifier := range dryRun.commands {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+	return identifiers
+}
+
+// CommandDryRun tells if the dry-run mode is effective for the Command with the given identifier
+// either via SetDryRun or SetCommandDryRun.
+func CommandDryRun(identifier string) bool {
+	dryRun.mutex.RLock()
+	defer dryRun.mutex.RUnlock()
+	if dryRun.enabled {
+		return true
+	}
+	_, ok := dryRun.commands[identifier]
+	return ok
+}
+
+// previewIfDryRun calls MutatingCommand.Preview instead of Command.Execute when the dry-run mode is effective for the given Command.
+// The second returned value tells if the preview is returned.
+func previewIfDryRun(ctx context.Context, command Command, input Input) (*CommandResponse, bool, error) {
+	mutating, ok := resolveCommand[MutatingCommand](command)
+	if !ok || !mutating.Mutating() || !CommandDryRun(command.Identifier()) {
+		return nil, false, nil
+	}
+
+	LoggerFromContext(ctx).Infof("Skip executing the mutating command in the dry-run mode: %s.", command.Identifier())
+	res, err := mutating.Preview(prepareCommandContext(ctx, command), input)
+	return res, true, err
+}
+
+// defaultPreview returns a response that tells the Command is not executed because of the dry-run mode.
+func defaultPreview(identifier string, input Input) *CommandResponse {
+	return &CommandResponse{
+		Content: fmt.Sprintf("[dry-run] %s is not executed with the input: %s", identifier, input.Message()),
+	}
+}
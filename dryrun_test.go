@@ -0,0 +1,149 @@
+package sarah
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSetDryRun(t *testing.T) {
+	defer SetDryRun(false)
+
+	SetDryRun(true)
+	if !DryRun() {
+		t.Error("Dry-run mode is not enabled.")
+	}
+	if !CommandDryRun("any") {
+		t.Error("Dry-run mode should be effective for any command.")
+	}
+
+	SetDryRun(false)
+	if DryRun() {
+		t.Error("Dry-run mode is not disabled.")
+	}
+}
+
+func TestSetCommandDryRun(t *testing.T) {
+	defer func() {
+		SetCommandDryRun("deploy", false)
+		SetCommandDryRun("restart", false)
+	}()
+
+	SetCommandDryRun("restart", true)
+	SetCommandDryRun("deploy", true)
+
+	if !reflect.DeepEqual(DryRunCommands(), []string{"deploy", "restart"}) {
+		t.Errorf("Unexpected identifiers are returned: %#v.", DryRunCommands())
+	}
+	if !CommandDryRun("deploy") {
+		t.Error("Dry-run mode should be effective for the command.")
+	}
+	if CommandDryRun("echo") {
+		t.Error("Dry-run mode should not be effective for other commands.")
+	}
+
+	SetCommandDryRun("deploy", false)
+	if CommandDryRun("deploy") {
+		t.Error("Dry-run mode is not disabled.")
+	}
+}
+
+func TestCommandPropsBuilder_Mutating(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+	builder.Mutating()
+
+	if !builder.props.mutating {
+		t.Error("Command is not marked as mutating.")
+	}
+}
+
+func TestCommandPropsBuilder_Preview(t *testing.T) {
+	builder := &CommandPropsBuilder{props: &CommandProps{}}
+	builder.Preview(func(_ context.Context, _ Input) (*CommandResponse, error) {
+		return nil, nil
+	})
+
+	if builder.props.previewFunc == nil {
+		t.Error("Preview function is not set.")
+	}
+}
+
+func TestCommands_ExecuteFirstMatched_DryRun(t *testing.T) {
+	defer SetDryRun(false)
+
+	executed := false
+	build := func(builder *CommandPropsBuilder) Command {
+		props, err := builder.
+			BotType("dummy").
+			Identifier("deploy").
+			Instruction("dummy").
+			MatchPattern(regexp.MustCompile(`^\.deploy\b`)).
+			Func(func(_ context.Context, _ Input) (*CommandResponse, error) {
+				executed = true
+				return &CommandResponse{Content: "deployed"}, nil
+			}).
+			Build()
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		command, err := buildCommand(context.TODO(), props, &DummyConfigWatcher{})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		return command
+	}
+
+	testSets := []struct {
+		builder  *CommandPropsBuilder
+		dryRun   bool
+		executed bool
+		expected string
+	}{
+		{
+			builder:  NewCommandPropsBuilder().Mutating(),
+			dryRun:   false,
+			executed: true,
+			expected: "deployed",
+		},
+		{
+			builder:  NewCommandPropsBuilder().Mutating(),
+			dryRun:   true,
+			executed: false,
+			expected: "[dry-run] deploy is not executed with the input: .deploy app",
+		},
+		{
+			builder: NewCommandPropsBuilder().Mutating().Preview(func(_ context.Context, input Input) (*CommandResponse, error) {
+				return &CommandResponse{Content: "would deploy app"}, nil
+			}),
+			dryRun:   true,
+			executed: false,
+			expected: "would deploy app",
+		},
+		{
+			builder:  NewCommandPropsBuilder(),
+			dryRun:   true,
+			executed: true,
+			expected: "deployed",
+		},
+	}
+
+	for i, tt := range testSets {
+		executed = false
+		SetDryRun(tt.dryRun)
+
+		commands := NewCommands()
+		commands.Append(&kvCommand{Command: build(tt.builder)})
+
+		res, err := commands.ExecuteFirstMatched(context.TODO(), &DummyInput{MessageValue: ".deploy app"})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if executed != tt.executed {
+			t.Errorf("Unexpected execution on test #%d: %t.", i+1, executed)
+		}
+		if res.Content != tt.expected {
+			t.Errorf("Unexpected content is returned on test #%d: %#v.", i+1, res.Content)
+		}
+	}
+}
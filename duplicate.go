@@ -0,0 +1,118 @@
+package sarah
+
+import (
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// BotWithDuplicateSuppression creates and returns a DefaultBotOption to suppress the exact same content being sent to the same destination repeatedly.
+// When a misconfigured ScheduledTask or a retry storm tries to send the same content to the same destination within the given window,
+// the second and later Outputs are not passed to Adapter.SendMessage and the number of suppressed Outputs is logged.
+//
+// The contents are compared with reflect.DeepEqual, so a content with a function value or a timestamp is rarely considered a duplicate.
+// The window starts when the content is first sent; the same content can be sent again after the window passes.
+//
+//	bot := sarah.NewBot(myAdapter, sarah.BotWithDuplicateSuppression(time.Minute))
+func BotWithDuplicateSuppression(window time.Duration) DefaultBotOption {
+	return func(bot *defaultBot) {
+		bot.duplicates = newDuplicateSuppressor(window)
+	}
+}
+
+type duplicateSuppressor struct {
+	window  time.Duration
+	entries map[string][]*sentOutput
+	now     func() time.Time
+	mutex   sync.Mutex
+}
+
+// sentOutput is an Output that was recently sent.
+type sentOutput struct {
+	destination string
+	content     interface{}
+	sentAt      time.Time
+	suppressed  int
+}
+
+func newDuplicateSuppressor(window time.Duration) *duplicateSuppressor {
+	return &duplicateSuppressor{
+		window:  window,
+		entries: map[string][]*sentOutput{},
+		now: func() time.Time {
+			// Resolve on every call since a Clock may be registered after the Bot is built.
+			return currentClock().Now()
+		},
+	}
+}
+
+// suppress tells if the given Output is a duplicate of a recently sent one and hence must not be sent.
+// When this returns false, the Output is remembered as a sent one.
+func (s *duplicateSuppressor) suppress(output Output) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	destination := destinationKey(output.Destination())
+	content := output.Content()
+	for _, sent := range s.entries[destination] {
+		if !reflect.DeepEqual(sent.content, content) {
+			continue
+		}
+
+		sent.suppressed++
+		if sent.suppressed == 1 {
+			logger.Warnf("Suppress duplicate message to %s. The same content was sent at %s.", destination, sent.sentAt.Format(time.RFC3339))
+		}
+		return true
+	}
+
+	s.entries[destination] = append(s.entries[destination], &sentOutput{
+		destination: destination,
+		content:     content,
+		sentAt:      now,
+	})
+	return false
+}
+
+// prune removes the entries whose windows are already passed and logs the number of suppressed duplicates.
+func (s *duplicateSuppressor) prune(now time.Time) {
+	for destination, entries := range s.entries {
+		alive := entries[:0]
+		for _, sent := range entries {
+			if now.Sub(sent.sentAt) < s.window {
+				alive = append(alive, sent)
+				continue
+			}
+
+			if sent.suppressed > 0 {
+				logger.Warnf("Suppressed %d duplicate message(s) to %s within %s.", sent.suppressed, sent.destination, s.window)
+			}
+		}
+
+		if len(alive) == 0 {
+			delete(s.entries, destination)
+			continue
+		}
+		s.entries[destination] = alive
+	}
+}
+
+// destinationKey returns the stringified representation of the given OutputDestination.
+func destinationKey(destination OutputDestination) string {
+	switch dest := destination.(type) {
+	case string:
+		return dest
+
+	case fmt.Stringer:
+		return dest.String()
+
+	default:
+		return fmt.Sprintf("%v", dest)
+
+	}
+}
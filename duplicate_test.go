@@ -0,0 +1,88 @@
+package sarah
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBotWithDuplicateSuppression(t *testing.T) {
+	bot := &defaultBot{}
+	BotWithDuplicateSuppression(time.Minute)(bot)
+
+	if bot.duplicates == nil {
+		t.Fatal("Suppressor is not set.")
+	}
+	if bot.duplicates.window != time.Minute {
+		t.Errorf("Unexpected window is set: %s.", bot.duplicates.window)
+	}
+}
+
+func Test_duplicateSuppressor_suppress(t *testing.T) {
+	now := time.Now()
+	s := newDuplicateSuppressor(time.Minute)
+	s.now = func() time.Time {
+		return now
+	}
+
+	if s.suppress(NewOutputMessage("general", "Hello")) {
+		t.Error("The first output should not be suppressed.")
+	}
+	if !s.suppress(NewOutputMessage("general", "Hello")) {
+		t.Error("The duplicate output should be suppressed.")
+	}
+	if s.suppress(NewOutputMessage("general", &RichContent{Text: "Hello"})) {
+		t.Error("The first rich content should not be suppressed.")
+	}
+	if !s.suppress(NewOutputMessage("general", &RichContent{Text: "Hello"})) {
+		t.Error("The duplicate rich content should be suppressed.")
+	}
+	if s.suppress(NewOutputMessage("random", "Hello")) {
+		t.Error("The output to another destination should not be suppressed.")
+	}
+	if s.suppress(NewOutputMessage("general", "Bye")) {
+		t.Error("The output with another content should not be suppressed.")
+	}
+
+	now = now.Add(time.Minute)
+	if s.suppress(NewOutputMessage("general", "Hello")) {
+		t.Error("The output should not be suppressed after the window.")
+	}
+	if len(s.entries["general"]) != 1 || s.entries["random"] != nil {
+		t.Errorf("Expired entries are not pruned: %#v.", s.entries["general"])
+	}
+}
+
+func TestDefaultBot_SendMessage_DuplicateSuppression(t *testing.T) {
+	sent := 0
+	bot := &defaultBot{
+		sendMessageFunc: func(_ context.Context, _ Output) {
+			sent++
+		},
+	}
+	BotWithDuplicateSuppression(time.Minute)(bot)
+
+	bot.SendMessage(context.TODO(), NewOutputMessage("general", "Hello"))
+	bot.SendMessage(context.TODO(), NewOutputMessage("general", "Hello"))
+
+	if sent != 1 {
+		t.Errorf("Unexpected number of outputs are sent: %d.", sent)
+	}
+}
+
+func Test_destinationKey(t *testing.T) {
+	testSets := []struct {
+		destination OutputDestination
+		expected    string
+	}{
+		{destination: "general", expected: "general"},
+		{destination: BotType("slack"), expected: "slack"},
+		{destination: 123, expected: "123"},
+	}
+
+	for i, tt := range testSets {
+		if key := destinationKey(tt.destination); key != tt.expected {
+			t.Errorf("Unexpected key is returned on test #%d: %s.", i+1, key)
+		}
+	}
+}
@@ -1,7 +1,13 @@
 package sarah
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // BotNonContinuableError represents a critical error that Bot can't continue its operation.
@@ -28,18 +34,184 @@ func NewBotNonContinuableError(errorContent string) error {
 // but developers MUST be aware that this modification may cause more concurrent Command.Execute and Bot.SendMessage operation.
 // With that said, developers can set a bigger number to worker.Config.WorkerNum to increase the number of workers and allow more concurrent executions with less delay;
 // set a bigger number to worker.Config.QueueSize to allow more delay with the same maximum number of concurrent executions.
+//
+// QueueCapacity, QueueDepth, and EnqueueLatency tell the state of the worker queue when the input is blocked.
+// An Adapter may refer to these values to tell the user that the bot is overloaded. See ReplyOnBlockedInput.
 type BlockedInputError struct {
 	ContinuationCount int
+
+	// QueueCapacity is the size of the worker queue. This is zero when the capacity is unknown such as when a worker.Worker is registered via RegisterWorker.
+	QueueCapacity int
+
+	// QueueDepth is the number of enqueued jobs that are not started yet.
+	QueueDepth int
+
+	// EnqueueLatency is how long the latest started job waited in the queue.
+	EnqueueLatency time.Duration
 }
 
 // Error returns the detailed message about this blocking situation including the number of continuous occurrences.
 // To get the number of continuous occurrences, call err.(*BlockedInputError).ContinuationCount.
 // e.g. log if the remainder of this number divided by N is 0 to avoid excessive logging.
 func (e BlockedInputError) Error() string {
-	return fmt.Sprintf("continuously failed to enqueue input (%d continuation)", e.ContinuationCount)
+	return fmt.Sprintf("continuously failed to enqueue input (%d continuation, queue depth %d/%d, latency %s)",
+		e.ContinuationCount, e.QueueDepth, e.QueueCapacity, e.EnqueueLatency)
 }
 
 // NewBlockedInputError creates and returns a new BlockedInputError instance.
 func NewBlockedInputError(i int) error {
 	return &BlockedInputError{ContinuationCount: i}
 }
+
+// ReplyOnBlockedInput wraps the given function that enqueues an Input, and calls reply when the Input is blocked due to a lack of worker resources.
+// Adapter implementations can use this to tell the user that the bot is overloaded instead of silently dropping the message.
+// To avoid making the overloaded situation worse, reply is called at most once in the given interval.
+// The error returned by enqueueInput is returned as-is.
+func ReplyOnBlockedInput(enqueueInput func(Input) error, interval time.Duration, reply func(Input, *BlockedInputError)) func(Input) error {
+	var mutex sync.Mutex
+	var lastReplied time.Time
+	return func(input Input) error {
+		err := enqueueInput(input)
+
+		var blockedErr *BlockedInputError
+		if !errors.As(err, &blockedErr) {
+			return err
+		}
+
+		mutex.Lock()
+		now := currentClock().Now()
+		shouldReply := lastReplied.IsZero() || now.Sub(lastReplied) >= interval
+		if shouldReply {
+			lastReplied = now
+		}
+		mutex.Unlock()
+
+		if shouldReply {
+			reply(input, blockedErr)
+		}
+		return err
+	}
+}
+
+// TransientError wraps an error that is expected to be resolved by itself such as a temporary network failure or a server-side error of a chat service.
+// A supervising function registered via RegisterBotErrorSupervisor may ignore this kind of error unless it occurs repeatedly.
+type TransientError struct {
+	Err error
+}
+
+// Error returns the detailed message of the wrapped error.
+func (e *TransientError) Error() string {
+	return fmt.Sprintf("transient error: %s", e.Err.Error())
+}
+
+// Unwrap returns the wrapped error.
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// NewTransientError creates and returns a new TransientError instance that wraps the given error.
+func NewTransientError(err error) error {
+	return &TransientError{Err: err}
+}
+
+// AuthError wraps an error that is caused by invalid or revoked credentials.
+// Such an error is not resolved without administrators' action, so a supervising function may want to alert and stop the failing Bot.
+type AuthError struct {
+	Err error
+}
+
+// Error returns the detailed message of the wrapped error.
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication error: %s", e.Err.Error())
+}
+
+// Unwrap returns the wrapped error.
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// NewAuthError creates and returns a new AuthError instance that wraps the given error.
+func NewAuthError(err error) error {
+	return &AuthError{Err: err}
+}
+
+// RateLimitError wraps an error that is caused by the chat service's rate limiting.
+// RetryAfter tells how long to wait before the next request. This is zero when the chat service does not tell.
+type RateLimitError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+// Error returns the detailed message of the wrapped error including the duration to wait.
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited (retry after %s): %s", e.RetryAfter, e.Err.Error())
+	}
+	return fmt.Sprintf("rate limited: %s", e.Err.Error())
+}
+
+// Unwrap returns the wrapped error.
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// NewRateLimitError creates and returns a new RateLimitError instance that wraps the given error.
+func NewRateLimitError(err error, retryAfter time.Duration) error {
+	return &RateLimitError{Err: err, RetryAfter: retryAfter}
+}
+
+// ParseRetryAfter parses the value of the Retry-After HTTP header, which is given either in seconds or in HTTP-date format.
+// This returns zero when the value is empty, malformed, or represents a past time.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// IsTransientError tells if the given error or any error in its chain is TransientError.
+// RateLimitError is also considered transient since the error is resolved after a while.
+func IsTransientError(err error) bool {
+	var transientErr *TransientError
+	if errors.As(err, &transientErr) {
+		return true
+	}
+	return IsRateLimitError(err)
+}
+
+// IsAuthError tells if the given error or any error in its chain is AuthError.
+func IsAuthError(err error) bool {
+	var authErr *AuthError
+	return errors.As(err, &authErr)
+}
+
+// IsRateLimitError tells if the given error or any error in its chain is RateLimitError.
+func IsRateLimitError(err error) bool {
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
+
+// RetryAfter returns the duration to wait when the given error or any error in its chain is RateLimitError.
+// The second returned value is false when no RateLimitError is found.
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter, true
+	}
+	return 0, false
+}
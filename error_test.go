@@ -1,9 +1,12 @@
 package sarah
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewBlockedInputError(t *testing.T) {
@@ -35,4 +38,137 @@ func TestBlockedInputError_Error(t *testing.T) {
 	if !strings.Contains(err.Error(), strconv.Itoa(i)) {
 		t.Errorf("Returned string does not contain the count of error occurrence: %s.", err.Error())
 	}
+
+	err = &BlockedInputError{ContinuationCount: 1, QueueCapacity: 10, QueueDepth: 8, EnqueueLatency: 3 * time.Second}
+	if !strings.Contains(err.Error(), "8/10") || !strings.Contains(err.Error(), "3s") {
+		t.Errorf("Returned string does not contain the queue diagnostics: %s.", err.Error())
+	}
+}
+
+func TestTransientError(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := fmt.Errorf("failed to connect: %w", NewTransientError(cause))
+
+	if !IsTransientError(err) {
+		t.Error("Wrapped TransientError is not detected.")
+	}
+
+	if IsAuthError(err) || IsRateLimitError(err) {
+		t.Errorf("Error is wrongly classified: %#v.", err)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("Original error is not unwrapped.")
+	}
+
+	if !strings.Contains(err.Error(), cause.Error()) {
+		t.Errorf("Returned string does not contain the original error: %s.", err.Error())
+	}
+}
+
+func TestAuthError(t *testing.T) {
+	cause := errors.New("invalid_auth")
+	err := fmt.Errorf("failed to connect: %w", NewAuthError(cause))
+
+	if !IsAuthError(err) {
+		t.Error("Wrapped AuthError is not detected.")
+	}
+
+	if IsTransientError(err) || IsRateLimitError(err) {
+		t.Errorf("Error is wrongly classified: %#v.", err)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("Original error is not unwrapped.")
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	cause := errors.New("too many requests")
+	err := fmt.Errorf("failed to send message: %w", NewRateLimitError(cause, 30*time.Second))
+
+	if !IsRateLimitError(err) {
+		t.Error("Wrapped RateLimitError is not detected.")
+	}
+
+	if !IsTransientError(err) {
+		t.Error("RateLimitError should be considered transient.")
+	}
+
+	if IsAuthError(err) {
+		t.Errorf("Error is wrongly classified: %#v.", err)
+	}
+
+	retryAfter, ok := RetryAfter(err)
+	if !ok {
+		t.Fatal("RateLimitError is not found.")
+	}
+	if retryAfter != 30*time.Second {
+		t.Errorf("Unexpected duration is returned: %s.", retryAfter)
+	}
+
+	if !strings.Contains(err.Error(), "30s") {
+		t.Errorf("Returned string does not contain the duration to wait: %s.", err.Error())
+	}
+
+	if _, ok := RetryAfter(cause); ok {
+		t.Error("RateLimitError is wrongly detected.")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	testSets := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "", expected: 0},
+		{value: "120", expected: 2 * time.Minute},
+		{value: "-1", expected: 0},
+		{value: "Tue, 01 Jun 2021 00:01:00 GMT", expected: time.Minute},
+		{value: "Mon, 31 May 2021 23:59:00 GMT", expected: 0},
+		{value: "soon", expected: 0},
+	}
+
+	for i, tt := range testSets {
+		d := ParseRetryAfter(tt.value, now)
+		if d != tt.expected {
+			t.Errorf("Unexpected duration is returned on test %d: %s.", i, d)
+		}
+	}
+}
+
+func TestReplyOnBlockedInput(t *testing.T) {
+	blocked := &BlockedInputError{ContinuationCount: 1, QueueCapacity: 10, QueueDepth: 10}
+	var replied []*BlockedInputError
+	enqueueInput := ReplyOnBlockedInput(func(input Input) error {
+		if input.Message() == "blocked" {
+			return blocked
+		}
+		return nil
+	}, time.Hour, func(_ Input, err *BlockedInputError) {
+		replied = append(replied, err)
+	})
+
+	err := enqueueInput(&DummyInput{MessageValue: "ok"})
+	if err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(replied) != 0 {
+		t.Error("Reply should not be made when the input is enqueued.")
+	}
+
+	err = enqueueInput(&DummyInput{MessageValue: "blocked"})
+	if err != blocked {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+	if len(replied) != 1 || replied[0] != blocked {
+		t.Fatalf("Reply is not made with the blocking information: %#v.", replied)
+	}
+
+	// The next reply is suppressed within the interval.
+	_ = enqueueInput(&DummyInput{MessageValue: "blocked"})
+	if len(replied) != 1 {
+		t.Errorf("Reply should be made at most once in the interval: %d.", len(replied))
+	}
 }
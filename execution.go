@@ -0,0 +1,76 @@
+package sarah
+
+import (
+	"context"
+	"time"
+)
+
+// CommandExecution represents a Command's execution that a CommandExecutionObserver receives.
+type CommandExecution struct {
+	// BotType represents the Bot that executed the Command.
+	BotType BotType
+
+	// Identifier is the identifier of the executed Command.
+	Identifier string
+
+	// SenderKey is the user who triggered the execution.
+	SenderKey string
+
+	// StartedAt is the time when the execution started.
+	StartedAt time.Time
+
+	// Elapsed is how long the execution took.
+	Elapsed time.Duration
+
+	// Err is the error that the Command returned.
+	Err error
+}
+
+// CommandExecutionObserver defines a function's signature that receives each Command's execution.
+// This is called synchronously after every execution, so the implementation should return as soon as possible.
+type CommandExecutionObserver func(context.Context, *CommandExecution)
+
+// RegisterCommandExecutionObserver registers a given CommandExecutionObserver to observe the Commands' executions,
+// which is handy to collect the usage statistics of the Commands.
+// Multiple observers can be registered. A preview in the dry-run mode is not an execution, so it is not observed.
+func RegisterCommandExecutionObserver(observer CommandExecutionObserver) {
+	defaultRunner().RegisterCommandExecutionObserver(observer)
+}
+
+// RegisterCommandExecutionObserver is the Runner counterpart of the package-level RegisterCommandExecutionObserver.
+func (rn *Runner) RegisterCommandExecutionObserver(observer CommandExecutionObserver) {
+	rn.options.register(func(r *runner) {
+		r.executionObservers = append(r.executionObservers, observer)
+	})
+}
+
+// observedCommand wraps a Command and notifies its executions to the registered CommandExecutionObserver values.
+type observedCommand struct {
+	Command
+	botType   BotType
+	observers []CommandExecutionObserver
+}
+
+func (command *observedCommand) Execute(ctx context.Context, input Input) (*CommandResponse, error) {
+	started := currentClock().Now()
+	res, err := command.Command.Execute(ctx, input)
+
+	execution := &CommandExecution{
+		BotType:    command.botType,
+		Identifier: command.Identifier(),
+		SenderKey:  input.SenderKey(),
+		StartedAt:  started,
+		Elapsed:    currentClock().Now().Sub(started),
+		Err:        err,
+	}
+	for _, observer := range command.observers {
+		observer(ctx, execution)
+	}
+
+	return res, err
+}
+
+// Unwrap returns the wrapped Command.
+func (command *observedCommand) Unwrap() Command {
+	return command.Command
+}
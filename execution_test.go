@@ -0,0 +1,76 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegisterCommandExecutionObserver(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterCommandExecutionObserver(func(_ context.Context, _ *CommandExecution) {})
+
+		r := &runner{}
+		options.apply(r)
+		if len(r.executionObservers) != 1 {
+			t.Errorf("Given observer is not registered: %d.", len(r.executionObservers))
+		}
+	})
+}
+
+func Test_observedCommand_Execute(t *testing.T) {
+	commandErr := errors.New("dummy")
+	var observed []*CommandExecution
+	command := &observedCommand{
+		Command: &DummyCommand{
+			IdentifierValue: "echo",
+			ExecuteFunc: func(_ context.Context, _ Input) (*CommandResponse, error) {
+				return nil, commandErr
+			},
+		},
+		botType: "dummy",
+		observers: []CommandExecutionObserver{
+			func(_ context.Context, execution *CommandExecution) {
+				observed = append(observed, execution)
+			},
+			func(_ context.Context, execution *CommandExecution) {
+				observed = append(observed, execution)
+			},
+		},
+	}
+
+	_, err := command.Execute(context.TODO(), &DummyInput{SenderKeyValue: "user"})
+	if err != commandErr {
+		t.Errorf("The Command's error is not returned: %#v.", err)
+	}
+
+	if len(observed) != 2 {
+		t.Fatalf("All observers should be notified: %d.", len(observed))
+	}
+	execution := observed[0]
+	if execution.BotType != "dummy" || execution.Identifier != "echo" || execution.SenderKey != "user" || execution.Err != commandErr {
+		t.Errorf("Unexpected execution is observed: %#v.", execution)
+	}
+	if execution.StartedAt.IsZero() {
+		t.Error("Start time is not set.")
+	}
+}
+
+func Test_runner_wrapCommand_WithObserver(t *testing.T) {
+	command := newDummyPrefixedCommand("echo", ".echo")
+	r := &runner{
+		kvStore:            NewInMemoryKVStore(),
+		executionObservers: []CommandExecutionObserver{func(_ context.Context, _ *CommandExecution) {}},
+	}
+
+	wrapped, ok := r.wrapCommand("dummy", command).(*observedCommand)
+	if !ok {
+		t.Fatal("Command is not wrapped.")
+	}
+	if _, ok := wrapped.Command.(*kvCommand); !ok {
+		t.Errorf("KVBucket should still be provided: %#v.", wrapped.Command)
+	}
+	if prefixed, ok := resolveCommand[PrefixedCommand](wrapped); !ok || prefixed.MatchPrefix() != ".echo" {
+		t.Errorf("Prefix is hidden by the wrapping: %#v.", prefixed)
+	}
+}
@@ -0,0 +1,186 @@
+package sarah
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// FormBack is the reply that goes back to the previous field of a Form.
+	FormBack = "back"
+
+	// FormCancel is the reply that quits a Form.
+	FormCancel = "cancel"
+
+	// FormSkip is the reply that leaves an optional field empty.
+	FormSkip = "skip"
+)
+
+// FormField declares a value that a Form collects from the user.
+type FormField struct {
+	// Name is the key of the collected value in FormValues.
+	Name string
+
+	// Prompt is the message that asks the user to input the value.
+	Prompt string
+
+	// Validate converts the user's reply into the value to be stored in FormValues.
+	// When this returns an error, the error message is sent to the user and the same field is asked again.
+	// When this is nil, the trimmed reply is stored as a string.
+	Validate func(string) (interface{}, error)
+
+	// Optional tells if the user can skip the field by replying FormSkip.
+	// A skipped field has nil value in FormValues.
+	Optional bool
+
+	// Sensitive tells if the value is sensitive such as a password. See UserContext.Sensitive.
+	Sensitive bool
+}
+
+// FormValues holds the values that a Form collected, keyed by FormField.Name.
+type FormValues map[string]interface{}
+
+// String returns the value of the given field as a string.
+// This returns an empty string when the field is skipped or the value is not a string.
+func (v FormValues) String(name string) string {
+	str, _ := v[name].(string)
+	return str
+}
+
+// copy returns a copy of the values so each step of the conversation keeps its own state and FormBack can restore it.
+func (v FormValues) copy() FormValues {
+	copied := FormValues{}
+	for name, value := range v {
+		copied[name] = value
+	}
+	return copied
+}
+
+// FormHandler defines a function's signature that receives the values when the user fills in all fields of a Form.
+type FormHandler func(context.Context, Input, FormValues) (*CommandResponse, error)
+
+// Form builds a multi-step conversation that collects the declared fields one by one on top of UserContext.
+// On each step, the user may reply FormBack to re-enter the previous field or FormCancel to quit.
+// When all fields are filled in, the collected values are passed to the FormHandler.
+//
+//	form := sarah.NewForm(func(ctx context.Context, input sarah.Input, values sarah.FormValues) (*sarah.CommandResponse, error) {
+//		storage.Save(input.SenderKey(), values.String("description"), values["due"].(time.Time))
+//		return &sarah.CommandResponse{Content: "Saved."}, nil
+//	}).
+//		Field(&sarah.FormField{Name: "description", Prompt: "Please input a thing to do."}).
+//		Field(&sarah.FormField{Name: "due", Prompt: "Input the due date in YYYY-MM-DD format.", Validate: parseDate})
+//
+//	// In a Command's function
+//	return form.Start(ctx, input)
+//
+// A Form is stateless, so one instance can serve multiple users at once.
+// Since the conversation is stored as UserContext.Next, a Bot must be given a UserContextStorage that stores functions in memory such as the one NewUserContextStorage returns.
+type Form struct {
+	fields  []*FormField
+	handler FormHandler
+}
+
+// NewForm creates and returns a new Form that passes the collected values to the given FormHandler.
+// Use Form.Field to declare the fields to collect.
+func NewForm(handler FormHandler) *Form {
+	return &Form{
+		handler: handler,
+	}
+}
+
+// Field appends the given field to the Form. The fields are asked in the order of this call.
+func (f *Form) Field(field *FormField) *Form {
+	f.fields = append(f.fields, field)
+	return f
+}
+
+// Start starts the conversation and returns a CommandResponse that asks the first field.
+// Return this from a Command.
+func (f *Form) Start(ctx context.Context, input Input) (*CommandResponse, error) {
+	return f.StartWith(ctx, input, nil)
+}
+
+// StartWith is a variant of Start that starts the conversation with the given values.
+// The fields that already have values are not asked, which is handy when a Command receives some values as its arguments.
+// e.g. ".todo buy milk" already tells the description of a TODO.
+func (f *Form) StartWith(ctx context.Context, input Input, values FormValues) (*CommandResponse, error) {
+	return f.ask(ctx, input, values.copy(), fmt.Sprintf("Reply %q to go back to the previous question or %q to quit.", FormBack, FormCancel))
+}
+
+// ask proceeds to the first field that does not have a value.
+// The given notice is sent along with the prompt.
+func (f *Form) ask(ctx context.Context, input Input, values FormValues, notice string) (*CommandResponse, error) {
+	for i, field := range f.fields {
+		if _, ok := values[field.Name]; ok {
+			continue
+		}
+
+		lines := []string{}
+		if notice != "" {
+			lines = append(lines, notice)
+		}
+		lines = append(lines, field.Prompt)
+		if field.Optional {
+			lines = append(lines, fmt.Sprintf("Reply %q to leave this empty.", FormSkip))
+		}
+
+		return &CommandResponse{
+			Content: strings.Join(lines, "\n"),
+			UserContext: &UserContext{
+				Next: func(ctx context.Context, input Input) (*CommandResponse, error) {
+					return f.receive(ctx, input, values, i)
+				},
+				Sensitive: field.Sensitive,
+			},
+		}, nil
+	}
+
+	return f.handler(ctx, input, values)
+}
+
+// receive handles the user's reply to the field at the given index.
+func (f *Form) receive(ctx context.Context, input Input, values FormValues, index int) (*CommandResponse, error) {
+	field := f.fields[index]
+	reply := strings.TrimSpace(input.Message())
+
+	switch strings.ToLower(reply) {
+	case FormCancel:
+		return &CommandResponse{Content: "Canceled."}, nil
+
+	case FormBack:
+		next := values.copy()
+		for i := index - 1; i >= 0; i-- {
+			if _, ok := next[f.fields[i].Name]; ok {
+				delete(next, f.fields[i].Name)
+				break
+			}
+		}
+		return f.ask(ctx, input, next, "")
+
+	case FormSkip:
+		if field.Optional {
+			next := values.copy()
+			next[field.Name] = nil
+			return f.ask(ctx, input, next, "")
+		}
+
+	}
+
+	if reply == "" {
+		return f.ask(ctx, input, values, "This is required.")
+	}
+
+	var value interface{} = reply
+	if field.Validate != nil {
+		var err error
+		value, err = field.Validate(reply)
+		if err != nil {
+			return f.ask(ctx, input, values, err.Error())
+		}
+	}
+
+	next := values.copy()
+	next[field.Name] = value
+	return f.ask(ctx, input, next, "")
+}
@@ -0,0 +1,131 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFormValues_String(t *testing.T) {
+	values := FormValues{"name": "sarah", "age": 1, "skipped": nil}
+
+	if values.String("name") != "sarah" {
+		t.Errorf("Unexpected value is returned: %s.", values.String("name"))
+	}
+	if values.String("age") != "" || values.String("skipped") != "" || values.String("unknown") != "" {
+		t.Error("Empty string should be returned for a non-string value.")
+	}
+}
+
+func TestForm(t *testing.T) {
+	var collected FormValues
+	form := NewForm(func(_ context.Context, _ Input, values FormValues) (*CommandResponse, error) {
+		collected = values
+		return &CommandResponse{Content: "done"}, nil
+	}).
+		Field(&FormField{Name: "name", Prompt: "Name?"}).
+		Field(&FormField{
+			Name:   "age",
+			Prompt: "Age?",
+			Validate: func(reply string) (interface{}, error) {
+				age, err := strconv.Atoi(reply)
+				if err != nil {
+					return nil, errors.New("Please input a number.")
+				}
+				return age, nil
+			},
+		}).
+		Field(&FormField{Name: "note", Prompt: "Note?", Optional: true})
+
+	testSets := []struct {
+		reply    string
+		expected string
+	}{
+		{
+			reply:    "",
+			expected: "This is required.\nName?",
+		},
+		{
+			reply:    "sarah",
+			expected: "Age?",
+		},
+		{
+			reply:    "back",
+			expected: "Name?",
+		},
+		{
+			reply:    "oklahomer",
+			expected: "Age?",
+		},
+		{
+			reply:    "young",
+			expected: "Please input a number.\nAge?",
+		},
+		{
+			reply:    "20",
+			expected: "Note?\nReply \"skip\" to leave this empty.",
+		},
+		{
+			reply:    "skip",
+			expected: "done",
+		},
+	}
+
+	response, err := form.Start(context.TODO(), &DummyInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !strings.HasSuffix(response.Content.(string), "\nName?") {
+		t.Errorf("Unexpected prompt is returned: %s.", response.Content)
+	}
+
+	for i, tt := range testSets {
+		if response.UserContext == nil {
+			t.Fatalf("UserContext is not returned before test #%d.", i+1)
+		}
+
+		response, err = response.UserContext.Next(context.TODO(), &DummyInput{MessageValue: tt.reply})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if response.Content != tt.expected {
+			t.Errorf("Unexpected response is returned on test #%d: %q.", i+1, response.Content)
+		}
+	}
+
+	if response.UserContext != nil {
+		t.Error("UserContext should not be returned after the completion.")
+	}
+	if collected.String("name") != "oklahomer" || collected["age"] != 20 || collected["note"] != nil {
+		t.Errorf("Unexpected values are collected: %#v.", collected)
+	}
+}
+
+func TestForm_StartWith(t *testing.T) {
+	form := NewForm(func(_ context.Context, _ Input, _ FormValues) (*CommandResponse, error) {
+		return &CommandResponse{Content: "done"}, nil
+	}).
+		Field(&FormField{Name: "name", Prompt: "Name?"}).
+		Field(&FormField{Name: "age", Prompt: "Age?", Sensitive: true})
+
+	response, err := form.StartWith(context.TODO(), &DummyInput{}, FormValues{"name": "sarah"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !strings.HasSuffix(response.Content.(string), "\nAge?") {
+		t.Errorf("Filled field should not be asked: %s.", response.Content)
+	}
+	if !response.UserContext.Sensitive {
+		t.Error("Sensitive field should be asked with a sensitive UserContext.")
+	}
+
+	response, err = response.UserContext.Next(context.TODO(), &DummyInput{MessageValue: "cancel"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if response.Content != "Canceled." || response.UserContext != nil {
+		t.Errorf("Form is not canceled: %#v.", response)
+	}
+}
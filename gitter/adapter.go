@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/oklahomer/go-kasumi/logger"
-	"github.com/oklahomer/go-kasumi/retry"
 	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/heartbeat"
+	"net/http"
+	"time"
 )
 
 const (
@@ -17,12 +19,23 @@ const (
 // AdapterOption defines a function's signature that Adapter's functional options must satisfy.
 type AdapterOption func(adapter *Adapter)
 
+// WithHTTPClient creates an AdapterOption with the given http.Client to communicate with Gitter.
+// This takes precedence over Config.HTTPClient.
+// The given client's Timeout is not applied to the Streaming API connection since the connection lasts long.
+func WithHTTPClient(httpClient *http.Client) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.httpClient = httpClient
+	}
+}
+
 // Adapter is a sarah.Adapter implementation for Gitter.
 // This holds REST/Streaming API clients' instances.
 type Adapter struct {
 	config          *Config
 	apiClient       APIClient
 	streamingClient StreamingClient
+	httpClient      *http.Client
+	stats           *roomStats
 }
 
 var _ sarah.Adapter = (*Adapter)(nil)
@@ -30,15 +43,34 @@ var _ sarah.Adapter = (*Adapter)(nil)
 // NewAdapter creates and returns a new Adapter instance.
 func NewAdapter(config *Config, options ...AdapterOption) (*Adapter, error) {
 	adapter := &Adapter{
-		config:          config,
-		apiClient:       NewRestAPIClient(config.Token),
-		streamingClient: NewStreamingAPIClient(config.Token),
+		config: config,
+		stats:  newRoomStats(),
 	}
 
 	for _, opt := range options {
 		opt(adapter)
 	}
 
+	if adapter.httpClient == nil && config.HTTPClient != nil {
+		httpClient, err := sarah.NewHTTPClient(config.HTTPClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build HTTP client: %w", err)
+		}
+		adapter.httpClient = httpClient
+	}
+
+	if adapter.httpClient == nil {
+		adapter.apiClient = NewRestAPIClient(config.Token)
+		adapter.streamingClient = NewStreamingAPIClient(config.Token)
+		return adapter, nil
+	}
+
+	// A streaming connection lasts long, so the request timeout must not be applied.
+	streamingHTTPClient := *adapter.httpClient
+	streamingHTTPClient.Timeout = 0
+	adapter.apiClient = NewRestAPIClient(config.Token, ClientWithHTTPClient(adapter.httpClient))
+	adapter.streamingClient = NewStreamingAPIClient(config.Token, ClientWithHTTPClient(&streamingHTTPClient))
+
 	return adapter, nil
 }
 
@@ -49,10 +81,17 @@ func (adapter *Adapter) BotType() sarah.BotType {
 
 // Run fetches all belonging Room information and connects to them.
 // New goroutines are activated for each Room to connect, and the interactions run in a concurrent manner.
+// When Config.MaxStreamingConnections is set, the Rooms beyond the limit are polled via REST API instead.
 func (adapter *Adapter) Run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
+	if adapter.config.OverloadMessage != "" {
+		enqueueInput = sarah.ReplyOnBlockedInput(enqueueInput, adapter.config.OverloadReplyInterval, func(input sarah.Input, _ *sarah.BlockedInputError) {
+			go adapter.SendMessage(ctx, sarah.NewOutputMessage(input.ReplyTo(), adapter.config.OverloadMessage))
+		})
+	}
+
 	// Get belonging rooms.
 	var rooms *Rooms
-	err := retry.WithPolicy(adapter.config.RetryPolicy, func() (e error) {
+	err := sarah.Retry(ctx, adapter.config.Backoff, adapter.config.RetryPolicy, func() (e error) {
 		rooms, e = adapter.apiClient.Rooms(ctx)
 		return e
 	})
@@ -61,31 +100,88 @@ func (adapter *Adapter) Run(ctx context.Context, enqueueInput func(sarah.Input)
 		return
 	}
 
-	// Connect to each room.
-	for _, room := range *rooms {
+	// Connect to the prioritized rooms up to the limit, and poll the rest.
+	enqueueInput = adapter.stats.observing(enqueueInput)
+	streamed, polled := splitRooms(prioritizeRooms(*rooms, adapter.config.PriorityRooms), adapter.config.MaxStreamingConnections)
+	for _, room := range streamed {
+		adapter.stats.register(room.ID, true)
 		go adapter.runEachRoom(ctx, room, enqueueInput)
 	}
+
+	if len(polled) == 0 {
+		return
+	}
+	fetcher, ok := adapter.apiClient.(MessagesFetcher)
+	if !ok {
+		logger.Warnf("%d rooms exceed the streaming connection limit, but %T can not poll messages", len(polled), adapter.apiClient)
+		return
+	}
+	for _, room := range polled {
+		adapter.stats.register(room.ID, false)
+	}
+	go adapter.pollRooms(ctx, polled, fetcher, enqueueInput)
+}
+
+// RateLimit returns the latest quota of the REST API calls.
+// This returns nil when the APIClient does not satisfy RateLimitReporter or no quota is reported yet.
+func (adapter *Adapter) RateLimit() *RateLimit {
+	reporter, ok := adapter.apiClient.(RateLimitReporter)
+	if !ok {
+		return nil
+	}
+	return reporter.RateLimit()
+}
+
+// RoomStats returns the reception statistics of the rooms the Adapter receives messages from.
+// Compare RoomStats.Lag of the polled rooms with the streamed ones to tune Config.MaxStreamingConnections and Config.PollingInterval.
+func (adapter *Adapter) RoomStats() []*RoomStats {
+	return adapter.stats.snapshot()
 }
 
 // SendMessage lets sarah.Bot send a message to Gitter.
 func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
-	switch content := output.Content().(type) {
+	content, splittable := sarah.UnwrapUnsplitContent(output.Content())
+
+	var text string
+	switch content := content.(type) {
 	case string:
-		room, ok := output.Destination().(*Room)
-		if !ok {
-			logger.Errorf("Destination is not instance of Room. %#v.", output.Destination())
-			return
-		}
-		_, err := adapter.apiClient.PostMessage(ctx, room, content)
-		logger.Errorf("Failed posting message to %s: %+v", room.ID, err)
+		text = content
+
+	case *sarah.RichContent:
+		text = content.PlainText()
 
 	default:
 		logger.Warnf("Unexpected output %#v", output)
+		return
+
+	}
+
+	room, ok := output.Destination().(*Room)
+	if !ok {
+		logger.Errorf("Destination is not instance of Room. %#v.", output.Destination())
+		return
+	}
 
+	texts := []string{text}
+	if splittable {
+		texts = sarah.SplitMessage(text, adapter.config.MaxMessageLength)
+	}
+
+	for _, text := range texts {
+		_, err := adapter.apiClient.PostMessage(ctx, room, text)
+		if err != nil {
+			sarah.LoggerFromContext(ctx).Errorf("Failed posting message to %s: %+v", room.ID, err)
+			return
+		}
 	}
 }
 
 func (adapter *Adapter) runEachRoom(ctx context.Context, room *Room, enqueueInput func(sarah.Input) error) {
+	var backoff *heartbeat.Backoff
+	if adapter.config.Heartbeat != nil {
+		backoff = heartbeat.NewBackoff(adapter.config.Heartbeat)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -95,7 +191,7 @@ func (adapter *Adapter) runEachRoom(ctx context.Context, room *Room, enqueueInpu
 			logger.Infof("Connecting to room: %s", room.ID)
 
 			var conn Connection
-			err := retry.WithPolicy(adapter.config.RetryPolicy, func() (e error) {
+			err := sarah.Retry(ctx, adapter.config.Backoff, adapter.config.RetryPolicy, func() (e error) {
 				conn, e = adapter.streamingClient.Connect(ctx, room)
 				return e
 			})
@@ -104,7 +200,8 @@ func (adapter *Adapter) runEachRoom(ctx context.Context, room *Room, enqueueInpu
 				return
 			}
 
-			connErr := receiveMessageRecursive(conn, enqueueInput)
+			connectedAt := time.Now()
+			connErr := adapter.receive(ctx, conn, enqueueInput)
 			_ = conn.Close()
 
 			// TODO: Intentional connection close such as context.cancel also comes here.
@@ -114,8 +211,56 @@ func (adapter *Adapter) runEachRoom(ctx context.Context, room *Room, enqueueInpu
 			// For now, let an error log appear and proceed to the next loop, select case with ctx.Done() will eventually return.
 			logger.Errorf("Disconnected from room %s: %+v", room.ID, connErr)
 
+			if backoff == nil {
+				continue
+			}
+			if time.Since(connectedAt) > adapter.config.Heartbeat.MaxReconnectInterval {
+				// The connection was stable for a while, so this is not a consecutive failure.
+				backoff.Reset()
+			}
+			if backoff.Wait(ctx) != nil {
+				return
+			}
+
+		}
+	}
+}
+
+// receive receives messages from the given connection until the connection is closed.
+// When Config.Heartbeat is set, the connection is closed if no keep-alive message is given for a while.
+func (adapter *Adapter) receive(ctx context.Context, conn Connection, enqueueInput func(sarah.Input) error) error {
+	if adapter.config.Heartbeat == nil {
+		return receiveMessageRecursive(conn, enqueueInput)
+	}
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Gitter periodically sends keep-alive messages, so there is nothing to send as a ping.
+	watchdog := heartbeat.NewWatchdog(adapter.config.Heartbeat, nil)
+	go func() {
+		err := watchdog.Run(connCtx)
+		if err != nil {
+			logger.Warnf("Closing the connection due to the lack of keep-alive message: %+v", err)
+			_ = conn.Close()
 		}
+	}()
+
+	return receiveMessageRecursive(&watchedReceiver{receiver: conn, alive: watchdog.Alive}, enqueueInput)
+}
+
+// watchedReceiver is a MessageReceiver that tells the connection is alive on every reception including keep-alive messages.
+type watchedReceiver struct {
+	receiver MessageReceiver
+	alive    func()
+}
+
+func (r *watchedReceiver) Receive() (*RoomMessage, error) {
+	message, err := r.receiver.Receive()
+	if err == nil || errors.Is(err, ErrEmptyPayload) {
+		r.alive()
 	}
+	return message, err
 }
 
 func receiveMessageRecursive(messageReceiver MessageReceiver, enqueueInput func(sarah.Input) error) error {
@@ -6,8 +6,10 @@ import (
 	"github.com/oklahomer/go-kasumi/logger"
 	"github.com/oklahomer/go-kasumi/retry"
 	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/heartbeat"
 	"io"
 	"log"
+	"net/http"
 	"os"
 	"reflect"
 	"strconv"
@@ -73,6 +75,37 @@ func TestNewAdapter(t *testing.T) {
 	}
 }
 
+func TestNewAdapter_WithHTTPClient(t *testing.T) {
+	httpClient := &http.Client{
+		Timeout: 3 * time.Second,
+	}
+	adapter, err := NewAdapter(NewConfig(), WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("Unexpected error returned: %s.", err.Error())
+	}
+
+	if adapter.apiClient.(*RestAPIClient).httpClient != httpClient {
+		t.Error("Given http.Client is not passed to RestAPIClient.")
+	}
+
+	streamingHTTPClient := adapter.streamingClient.(*StreamingAPIClient).httpClient
+	if streamingHTTPClient == nil || streamingHTTPClient.Timeout != 0 {
+		t.Errorf("Unexpected http.Client is passed to StreamingAPIClient: %#v.", streamingHTTPClient)
+	}
+}
+
+func TestNewAdapter_WithHTTPClientConfig(t *testing.T) {
+	config := NewConfig()
+	config.HTTPClient = &sarah.HTTPClientConfig{
+		ProxyURL: "://invalid",
+	}
+
+	_, err := NewAdapter(config)
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
 func TestAdapter_BotType(t *testing.T) {
 	adapter := &Adapter{}
 
@@ -278,6 +311,79 @@ func TestAdapter_runEachRoom_ConnectionError(t *testing.T) {
 	}
 }
 
+func TestAdapter_receive_HeartbeatTimeout(t *testing.T) {
+	closed := make(chan struct{})
+	conn := &DummyConnection{
+		ReceiveFunc: func() (*RoomMessage, error) {
+			// Block till the connection is closed.
+			<-closed
+			return nil, errors.New("connection is closed")
+		},
+		CloseFunc: func() error {
+			close(closed)
+			return nil
+		},
+	}
+	adapter := &Adapter{
+		config: &Config{
+			Heartbeat: &heartbeat.Config{
+				PingInterval:   10 * time.Millisecond,
+				MaxMissedPings: 1,
+			},
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- adapter.receive(context.TODO(), conn, func(_ sarah.Input) error { return nil })
+	}()
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Fatal("Connection is not closed even though no keep-alive message is given.")
+
+	}
+}
+
+func Test_watchedReceiver_Receive(t *testing.T) {
+	testSets := []struct {
+		err   error
+		alive bool
+	}{
+		{err: nil, alive: true},
+		{err: ErrEmptyPayload, alive: true},
+		{err: errors.New("connection error"), alive: false},
+	}
+
+	for i, tt := range testSets {
+		called := false
+		receiver := &watchedReceiver{
+			receiver: &DummyConnection{
+				ReceiveFunc: func() (*RoomMessage, error) {
+					return nil, tt.err
+				},
+			},
+			alive: func() {
+				called = true
+			},
+		}
+
+		_, err := receiver.Receive()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Unexpected error is returned on test %d: %#v.", i, err)
+		}
+
+		if called != tt.alive {
+			t.Errorf("Unexpected liveness report on test %d: %t.", i, called)
+		}
+	}
+}
+
 func TestAdapter_Run(t *testing.T) {
 	givenRoom := make(chan string)
 	roomID := "dummy"
@@ -342,9 +448,26 @@ func TestAdapter_Run_RestAPIClientRoomsError(t *testing.T) {
 	}
 }
 
+func TestAdapter_RateLimit(t *testing.T) {
+	adapter := &Adapter{
+		apiClient: &DummyAPIClient{},
+	}
+	if adapter.RateLimit() != nil {
+		t.Error("Nil should be returned when APIClient does not report the quota.")
+	}
+
+	client := &RestAPIClient{}
+	client.limiter.latest = &RateLimit{Limit: 100, Remaining: 10}
+	adapter.apiClient = client
+	if limit := adapter.RateLimit(); limit == nil || limit.Remaining != 10 {
+		t.Errorf("Unexpected RateLimit is returned: %#v.", limit)
+	}
+}
+
 func TestAdapter_SendMessage(t *testing.T) {
 	called := false
 	adapter := &Adapter{
+		config: NewConfig(),
 		apiClient: &DummyAPIClient{
 			PostMessageFunc: func(_ context.Context, _ *Room, _ string) (*Message, error) {
 				called = true
@@ -361,9 +484,62 @@ func TestAdapter_SendMessage(t *testing.T) {
 	}
 }
 
+func TestAdapter_SendMessage_RichContent(t *testing.T) {
+	var posted string
+	adapter := &Adapter{
+		config: NewConfig(),
+		apiClient: &DummyAPIClient{
+			PostMessageFunc: func(_ context.Context, _ *Room, text string) (*Message, error) {
+				posted = text
+				return nil, nil
+			},
+		},
+	}
+	content := &sarah.RichContent{
+		Text:   "Summary",
+		Blocks: []sarah.ContentBlock{&sarah.SectionBlock{Text: "details"}},
+	}
+	output := sarah.NewOutputMessage(&Room{}, content)
+
+	adapter.SendMessage(context.TODO(), output)
+
+	if posted != content.PlainText() {
+		t.Errorf("Unexpected text is posted: %s.", posted)
+	}
+}
+
+func TestAdapter_SendMessage_LongMessage(t *testing.T) {
+	var posted []string
+	config := NewConfig()
+	config.MaxMessageLength = 5
+	adapter := &Adapter{
+		config: config,
+		apiClient: &DummyAPIClient{
+			PostMessageFunc: func(_ context.Context, _ *Room, text string) (*Message, error) {
+				posted = append(posted, text)
+				return nil, nil
+			},
+		},
+	}
+
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(&Room{}, "foo\nbar\nbaz"))
+
+	if len(posted) != 3 {
+		t.Fatalf("Unexpected number of messages are posted: %#v.", posted)
+	}
+
+	posted = nil
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(&Room{}, &sarah.UnsplitContent{Content: "foo\nbar\nbaz"}))
+
+	if len(posted) != 1 {
+		t.Errorf("Unsplit content should be sent as-is: %#v.", posted)
+	}
+}
+
 func TestAdapter_SendMessage_InvalidDestinationError(t *testing.T) {
 	called := false
 	adapter := &Adapter{
+		config: NewConfig(),
 		apiClient: &DummyAPIClient{
 			PostMessageFunc: func(_ context.Context, _ *Room, _ string) (*Message, error) {
 				called = true
@@ -383,6 +559,7 @@ func TestAdapter_SendMessage_InvalidDestinationError(t *testing.T) {
 func TestAdapter_SendMessage_InvalidContentTypeError(t *testing.T) {
 	called := false
 	adapter := &Adapter{
+		config: NewConfig(),
 		apiClient: &DummyAPIClient{
 			PostMessageFunc: func(_ context.Context, _ *Room, _ string) (*Message, error) {
 				called = true
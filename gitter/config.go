@@ -2,6 +2,8 @@ package gitter
 
 import (
 	"github.com/oklahomer/go-kasumi/retry"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/heartbeat"
 	"time"
 )
 
@@ -12,6 +14,45 @@ type Config struct {
 
 	// RetryPolicy declares how a retrial for an API call should behave.
 	RetryPolicy *retry.Policy `json:"retry_policy" yaml:"retry_policy"`
+
+	// Backoff declares an exponential backoff strategy for a retrial of an API call.
+	// When this is set, this is preferred over RetryPolicy.
+	Backoff *sarah.BackoffPolicy `json:"backoff" yaml:"backoff"`
+
+	// HTTPClient declares the settings of the HTTP client to communicate with Gitter such as a proxy server and a CA bundle.
+	// When this is nil, http.DefaultClient is used.
+	HTTPClient *sarah.HTTPClientConfig `json:"http_client" yaml:"http_client"`
+
+	// Heartbeat declares how to detect a dead streaming connection and how to reconnect.
+	// Since Gitter sends keep-alive messages by itself, a missed ping is counted on every PingInterval without any keep-alive message.
+	// When this is nil, the connection is trusted until the reception fails and the reconnection is made immediately.
+	Heartbeat *heartbeat.Config `json:"heartbeat" yaml:"heartbeat"`
+
+	// MaxStreamingConnections declares the maximum number of rooms to connect via Streaming API at once.
+	// The rooms are sorted by their priorities, and the rooms beyond this cap are polled one by one via REST API every PollingInterval.
+	// Zero value connects to all rooms via Streaming API.
+	MaxStreamingConnections int `json:"max_streaming_connections" yaml:"max_streaming_connections"`
+
+	// PriorityRooms declares the IDs or the URIs of the rooms to connect via Streaming API in preference to others.
+	// The rooms not listed here are prioritized in the order of favourites, one-to-one rooms, and the recently accessed ones.
+	PriorityRooms []string `json:"priority_rooms" yaml:"priority_rooms"`
+
+	// PollingInterval declares the interval to poll the next room that exceeds MaxStreamingConnections.
+	// Because the rooms are polled in a round-robin manner, each room is polled every PollingInterval multiplied by the number of such rooms.
+	PollingInterval time.Duration `json:"polling_interval" yaml:"polling_interval"`
+
+	// OverloadMessage declares the message to reply when the user's input is dropped because the bot is too busy.
+	// When this is empty, the overflowing input is silently dropped.
+	OverloadMessage string `json:"overload_message" yaml:"overload_message"`
+
+	// OverloadReplyInterval declares the minimum interval between the replies of OverloadMessage
+	// so the overloaded bot does not make the situation worse by replying to every dropped input.
+	OverloadReplyInterval time.Duration `json:"overload_reply_interval" yaml:"overload_reply_interval"`
+
+	// MaxMessageLength declares the maximum number of characters in a message.
+	// A longer message is split into multiple messages; see sarah.SplitMessage for details.
+	// Zero value disables the splitting.
+	MaxMessageLength int `json:"max_message_length" yaml:"max_message_length"`
 }
 
 // NewConfig creates and returns a new Config instance with default settings.
@@ -24,5 +65,17 @@ func NewConfig() *Config {
 			Trial:    10,
 			Interval: 500 * time.Millisecond,
 		},
+		MaxStreamingConnections: 0,
+		PriorityRooms:           []string{},
+		PollingInterval:         5 * time.Second,
+		OverloadMessage:         "",
+		OverloadReplyInterval:   30 * time.Second,
+		MaxMessageLength:        4000,
+		Heartbeat: &heartbeat.Config{
+			PingInterval:         1 * time.Minute,
+			MaxMissedPings:       3,
+			ReconnectInterval:    1 * time.Second,
+			MaxReconnectInterval: 1 * time.Minute,
+		},
 	}
 }
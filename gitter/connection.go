@@ -95,7 +95,18 @@ type RoomMessage struct {
 	ReceivedMessage *Message
 }
 
+var usageStyle = &sarah.UsageStyle{
+	Code: func(s string) string {
+		return "`" + s + "`"
+	},
+	Heading: func(s string) string {
+		return "**" + s + "**"
+	},
+}
+
 var _ sarah.Input = (*RoomMessage)(nil)
+var _ sarah.NormalizableInput = (*RoomMessage)(nil)
+var _ sarah.UsageStyledInput = (*RoomMessage)(nil)
 
 // NewRoomMessage creates and returns a new RoomMessage instance.
 func NewRoomMessage(room *Room, message *Message) *RoomMessage {
@@ -115,6 +126,16 @@ func (message *RoomMessage) Message() string {
 	return message.ReceivedMessage.Text
 }
 
+// SetMessage replaces the received text with the normalized one.
+func (message *RoomMessage) SetMessage(text string) {
+	message.ReceivedMessage.Text = text
+}
+
+// UsageStyle returns the style to decorate the instruction generated from sarah.ArgsSpec with Markdown.
+func (message *RoomMessage) UsageStyle() *sarah.UsageStyle {
+	return usageStyle
+}
+
 // SentAt returns when the message is sent.
 func (message *RoomMessage) SentAt() time.Time {
 	return message.ReceivedMessage.SendTimeStamp.Time
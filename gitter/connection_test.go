@@ -97,6 +97,30 @@ func TestRoomMessage_Message(t *testing.T) {
 	}
 }
 
+func TestRoomMessage_SetMessage(t *testing.T) {
+	message := &RoomMessage{
+		ReceivedMessage: &Message{
+			Text: "ｔｅｘｔ",
+		},
+	}
+	message.SetMessage("text")
+
+	if message.Message() != "text" {
+		t.Errorf("Message is not replaced: %s.", message.Message())
+	}
+}
+
+func TestRoomMessage_UsageStyle(t *testing.T) {
+	style := (&RoomMessage{}).UsageStyle()
+
+	if style.Code(".echo") != "`.echo`" {
+		t.Errorf("Unexpected code is returned: %s.", style.Code(".echo"))
+	}
+	if style.Heading("Usage:") != "**Usage:**" {
+		t.Errorf("Unexpected heading is returned: %s.", style.Heading("Usage:"))
+	}
+}
+
 func TestRoomMessage_ReplyTo(t *testing.T) {
 	room := &Room{}
 	message := &RoomMessage{
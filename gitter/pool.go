@@ -0,0 +1,206 @@
+package gitter
+
+import (
+	"context"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RoomStats represents the reception statistics of a room.
+type RoomStats struct {
+	// RoomID is the ID of the room.
+	RoomID string
+
+	// Streaming tells if the room is connected via Streaming API. This is false when the room is polled via REST API.
+	Streaming bool
+
+	// Received is the number of the received messages.
+	Received uint64
+
+	// LastReceivedAt is the time when the latest message was received.
+	// This is zero value when no message has been received yet.
+	LastReceivedAt time.Time
+
+	// Lag is the gap between when the latest message was sent and when the message was received.
+	// A polled room typically has a larger lag than a streamed one.
+	Lag time.Duration
+}
+
+// roomStats holds RoomStats per room.
+type roomStats struct {
+	rooms map[string]*RoomStats
+	now   func() time.Time
+	mutex sync.Mutex
+}
+
+func newRoomStats() *roomStats {
+	return &roomStats{
+		rooms: map[string]*RoomStats{},
+		now:   time.Now,
+	}
+}
+
+// register starts tracking the given room.
+func (s *roomStats) register(roomID string, streaming bool) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rooms[roomID] = &RoomStats{
+		RoomID:    roomID,
+		Streaming: streaming,
+	}
+}
+
+// observe updates the statistics of the room where the given message is sent.
+func (s *roomStats) observe(message *RoomMessage) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats, ok := s.rooms[message.Room.ID]
+	if !ok {
+		return
+	}
+
+	now := s.now()
+	stats.Received++
+	stats.LastReceivedAt = now
+	if sentAt := message.SentAt(); !sentAt.IsZero() {
+		stats.Lag = now.Sub(sentAt)
+	}
+}
+
+// snapshot returns the copies of the current statistics sorted by the room IDs.
+func (s *roomStats) snapshot() []*RoomStats {
+	if s == nil {
+		return []*RoomStats{}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	snapshot := make([]*RoomStats, 0, len(s.rooms))
+	for _, stats := range s.rooms {
+		copied := *stats
+		snapshot = append(snapshot, &copied)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].RoomID < snapshot[j].RoomID
+	})
+	return snapshot
+}
+
+// observing returns a function that updates the statistics with each received message and then passes the message to enqueueInput.
+func (s *roomStats) observing(enqueueInput func(sarah.Input) error) func(sarah.Input) error {
+	return func(input sarah.Input) error {
+		inputs := []sarah.Input{input}
+		if batch, ok := input.(*sarah.BatchInput); ok {
+			inputs = batch.Inputs
+		}
+		for _, i := range inputs {
+			if message, ok := i.(*RoomMessage); ok {
+				s.observe(message)
+			}
+		}
+
+		return enqueueInput(input)
+	}
+}
+
+// prioritizeRooms returns a copy of the given rooms sorted by their priorities.
+// The rooms listed in priorities come first in the listed order, followed by favourites, one-to-one rooms, and the recently accessed ones.
+func prioritizeRooms(rooms Rooms, priorities []string) Rooms {
+	rank := func(room *Room) int {
+		for i, priority := range priorities {
+			if room.ID == priority || room.URI == priority {
+				return i
+			}
+		}
+		return len(priorities)
+	}
+
+	sorted := slices.Clone(rooms)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if rankA, rankB := rank(a), rank(b); rankA != rankB {
+			return rankA < rankB
+		}
+		if (a.Favourite > 0) != (b.Favourite > 0) {
+			return a.Favourite > 0
+		}
+		if a.OneToOne != b.OneToOne {
+			return a.OneToOne
+		}
+		return a.LastAccessTime.Time.After(b.LastAccessTime.Time)
+	})
+	return sorted
+}
+
+// splitRooms splits the given rooms into the ones to connect via Streaming API and the ones to poll.
+// When limit is zero or less, all rooms are streamed.
+func splitRooms(rooms Rooms, limit int) (Rooms, Rooms) {
+	if limit <= 0 || len(rooms) <= limit {
+		return rooms, nil
+	}
+	return rooms[:limit], rooms[limit:]
+}
+
+// pollRooms polls the given rooms one by one in a round-robin manner until the context is canceled.
+func (adapter *Adapter) pollRooms(ctx context.Context, rooms Rooms, fetcher MessagesFetcher, enqueueInput func(sarah.Input) error) {
+	logger.Infof("Start polling %d rooms that exceed the streaming connection limit", len(rooms))
+
+	ticker := time.NewTicker(adapter.config.PollingInterval)
+	defer ticker.Stop()
+
+	lastIDs := map[string]string{}
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			adapter.pollRoom(ctx, rooms[i%len(rooms)], fetcher, lastIDs, enqueueInput)
+
+		}
+	}
+}
+
+// pollRoom fetches the messages of the given room posted after the previous poll, and then passes them to enqueueInput in the posted order.
+// The first poll of each room only remembers the latest message so the messages posted before the Adapter starts are not handled.
+func (adapter *Adapter) pollRoom(ctx context.Context, room *Room, fetcher MessagesFetcher, lastIDs map[string]string, enqueueInput func(sarah.Input) error) {
+	lastID, polled := lastIDs[room.ID]
+	messages, err := fetcher.Messages(ctx, room, lastID)
+	if err != nil {
+		logger.Warnf("Failed to poll room: %s. Error: %+v", room.ID, err)
+		return
+	}
+	if len(messages) > 0 {
+		lastIDs[room.ID] = messages[len(messages)-1].ID
+	} else {
+		lastIDs[room.ID] = lastID
+	}
+	if !polled || len(messages) == 0 {
+		return
+	}
+
+	inputs := make([]sarah.Input, len(messages))
+	for i, message := range messages {
+		inputs[i] = NewRoomMessage(room, message)
+	}
+	if len(inputs) == 1 {
+		_ = enqueueInput(inputs[0])
+		return
+	}
+	_ = enqueueInput(sarah.NewBatchInput(inputs...))
+}
@@ -0,0 +1,253 @@
+package gitter
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/retry"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+type DummyPollingAPIClient struct {
+	DummyAPIClient
+	MessagesFunc func(context.Context, *Room, string) ([]*Message, error)
+}
+
+func (c *DummyPollingAPIClient) Messages(ctx context.Context, room *Room, afterID string) ([]*Message, error) {
+	return c.MessagesFunc(ctx, room, afterID)
+}
+
+func Test_roomStats(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	stats := newRoomStats()
+	stats.now = func() time.Time {
+		return now
+	}
+	stats.register("streamed", true)
+	stats.register("polled", false)
+
+	var enqueued []sarah.Input
+	enqueueInput := stats.observing(func(input sarah.Input) error {
+		enqueued = append(enqueued, input)
+		return nil
+	})
+
+	newMessage := func(roomID string, sentAt time.Time) *RoomMessage {
+		return NewRoomMessage(&Room{ID: roomID}, &Message{SendTimeStamp: TimeStamp{Time: sentAt}})
+	}
+	_ = enqueueInput(newMessage("streamed", now.Add(-time.Second)))
+	_ = enqueueInput(sarah.NewBatchInput(newMessage("polled", now.Add(-time.Minute)), newMessage("polled", now.Add(-10*time.Second))))
+	_ = enqueueInput(newMessage("unknown", now))
+
+	if len(enqueued) != 3 {
+		t.Errorf("Inputs are not passed: %d.", len(enqueued))
+	}
+
+	snapshot := stats.snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("Unexpected statistics are returned: %#v.", snapshot)
+	}
+
+	polled := snapshot[0]
+	if polled.RoomID != "polled" || polled.Streaming || polled.Received != 2 || polled.Lag != 10*time.Second || !polled.LastReceivedAt.Equal(now) {
+		t.Errorf("Unexpected statistics of polled room: %#v.", polled)
+	}
+
+	streamed := snapshot[1]
+	if streamed.RoomID != "streamed" || !streamed.Streaming || streamed.Received != 1 || streamed.Lag != time.Second {
+		t.Errorf("Unexpected statistics of streamed room: %#v.", streamed)
+	}
+
+	// The snapshot is a copy.
+	streamed.Received = 100
+	if stats.snapshot()[1].Received != 1 {
+		t.Error("Snapshot should not share the state.")
+	}
+}
+
+func Test_roomStats_Nil(t *testing.T) {
+	var stats *roomStats
+
+	// Does not panic.
+	stats.register("room", true)
+	_ = stats.observing(func(_ sarah.Input) error { return nil })(NewRoomMessage(&Room{ID: "room"}, &Message{}))
+
+	if len(stats.snapshot()) != 0 {
+		t.Error("Empty statistics should be returned.")
+	}
+}
+
+func Test_prioritizeRooms(t *testing.T) {
+	now := time.Now()
+	rooms := Rooms{
+		{ID: "old", LastAccessTime: TimeStamp{Time: now.Add(-time.Hour)}},
+		{ID: "recent", LastAccessTime: TimeStamp{Time: now}},
+		{ID: "one-to-one", OneToOne: true},
+		{ID: "favourite", Favourite: 1},
+		{ID: "second", URI: "org/second"},
+		{ID: "first"},
+	}
+
+	sorted := prioritizeRooms(rooms, []string{"first", "org/second"})
+
+	expected := []string{"first", "second", "favourite", "one-to-one", "recent", "old"}
+	for i, id := range expected {
+		if sorted[i].ID != id {
+			t.Errorf("Unexpected room at %d: %s.", i, sorted[i].ID)
+		}
+	}
+	if rooms[0].ID != "old" {
+		t.Error("Given rooms should not be modified.")
+	}
+}
+
+func Test_splitRooms(t *testing.T) {
+	rooms := Rooms{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+	testSets := []struct {
+		limit    int
+		streamed int
+		polled   int
+	}{
+		{limit: 0, streamed: 3, polled: 0},
+		{limit: 3, streamed: 3, polled: 0},
+		{limit: 2, streamed: 2, polled: 1},
+	}
+
+	for i, tt := range testSets {
+		streamed, polled := splitRooms(rooms, tt.limit)
+		if len(streamed) != tt.streamed || len(polled) != tt.polled {
+			t.Errorf("Unexpected split on test #%d: %d and %d.", i+1, len(streamed), len(polled))
+		}
+	}
+}
+
+func TestAdapter_pollRoom(t *testing.T) {
+	room := &Room{ID: "room"}
+	var givenIDs []string
+	responses := [][]*Message{
+		{{ID: "old1"}, {ID: "old2"}},
+		{},
+		{{ID: "new1"}, {ID: "new2"}},
+		{{ID: "new3"}},
+	}
+	fetcher := &DummyPollingAPIClient{
+		MessagesFunc: func(_ context.Context, _ *Room, afterID string) ([]*Message, error) {
+			givenIDs = append(givenIDs, afterID)
+			messages := responses[0]
+			responses = responses[1:]
+			return messages, nil
+		},
+	}
+
+	var enqueued []sarah.Input
+	enqueueInput := func(input sarah.Input) error {
+		enqueued = append(enqueued, input)
+		return nil
+	}
+
+	adapter := &Adapter{config: NewConfig()}
+	lastIDs := map[string]string{}
+	for i := 0; i < 4; i++ {
+		adapter.pollRoom(context.TODO(), room, fetcher, lastIDs, enqueueInput)
+	}
+
+	expectedIDs := []string{"", "old2", "old2", "new2"}
+	for i, id := range expectedIDs {
+		if givenIDs[i] != id {
+			t.Errorf("Unexpected afterID is given at %d: %s.", i, givenIDs[i])
+		}
+	}
+
+	// The messages of the first poll are not handled.
+	if len(enqueued) != 2 {
+		t.Fatalf("Unexpected number of inputs are enqueued: %d.", len(enqueued))
+	}
+	batch, ok := enqueued[0].(*sarah.BatchInput)
+	if !ok || len(batch.Inputs) != 2 || batch.Inputs[0].(*RoomMessage).ReceivedMessage.ID != "new1" {
+		t.Errorf("Messages are not enqueued in order: %#v.", enqueued[0])
+	}
+	if message, ok := enqueued[1].(*RoomMessage); !ok || message.ReceivedMessage.ID != "new3" {
+		t.Errorf("Unexpected input is enqueued: %#v.", enqueued[1])
+	}
+}
+
+func TestAdapter_pollRoom_Error(t *testing.T) {
+	fetcher := &DummyPollingAPIClient{
+		MessagesFunc: func(_ context.Context, _ *Room, _ string) ([]*Message, error) {
+			return nil, errors.New("dummy")
+		},
+	}
+	adapter := &Adapter{config: NewConfig()}
+	lastIDs := map[string]string{}
+	adapter.pollRoom(context.TODO(), &Room{ID: "room"}, fetcher, lastIDs, func(_ sarah.Input) error {
+		t.Error("Nothing should be enqueued.")
+		return nil
+	})
+
+	if _, ok := lastIDs["room"]; ok {
+		t.Error("Failed poll should not be counted as the first poll.")
+	}
+}
+
+func TestAdapter_Run_Overflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	connected := make(chan string, 2)
+	polled := make(chan string, 10)
+	adapter := &Adapter{
+		config: &Config{
+			RetryPolicy: &retry.Policy{
+				Trial: 1,
+			},
+			MaxStreamingConnections: 1,
+			PriorityRooms:           []string{"priority"},
+			PollingInterval:         10 * time.Millisecond,
+		},
+		apiClient: &DummyPollingAPIClient{
+			DummyAPIClient: DummyAPIClient{
+				RoomsFunc: func(_ context.Context) (*Rooms, error) {
+					return &Rooms{{ID: "other"}, {ID: "priority"}}, nil
+				},
+			},
+			MessagesFunc: func(_ context.Context, room *Room, _ string) ([]*Message, error) {
+				polled <- room.ID
+				return nil, nil
+			},
+		},
+		streamingClient: &DummyStreamingClient{
+			ConnectFunc: func(_ context.Context, room *Room) (Connection, error) {
+				connected <- room.ID
+				return nil, errors.New("to be ignored")
+			},
+		},
+		stats: newRoomStats(),
+	}
+
+	adapter.Run(ctx, func(sarah.Input) error { return nil }, func(error) {})
+
+	select {
+	case id := <-connected:
+		if id != "priority" {
+			t.Errorf("Unexpected room is streamed: %s.", id)
+		}
+	case <-time.NewTimer(10 * time.Second).C:
+		t.Fatal("StreamingClient.Connect is not called.")
+	}
+
+	select {
+	case id := <-polled:
+		if id != "other" {
+			t.Errorf("Unexpected room is polled: %s.", id)
+		}
+	case <-time.NewTimer(10 * time.Second).C:
+		t.Fatal("Overflow room is not polled.")
+	}
+
+	stats := adapter.RoomStats()
+	if len(stats) != 2 || stats[0].RoomID != "other" || stats[0].Streaming || !stats[1].Streaming {
+		t.Errorf("Unexpected statistics are returned: %#v.", stats)
+	}
+}
@@ -0,0 +1,123 @@
+package gitter
+
+import (
+	"context"
+	"github.com/oklahomer/go-kasumi/logger"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit represents the quota of the REST API calls reported by Gitter via the X-RateLimit-* headers.
+type RateLimit struct {
+	// Limit is the maximum number of the API calls in the current window.
+	Limit int
+
+	// Remaining is the number of the API calls left in the current window.
+	Remaining int
+
+	// ResetAt is the time when the current window ends and the quota is reset.
+	ResetAt time.Time
+
+	// UpdatedAt is the time when the values are reported.
+	UpdatedAt time.Time
+}
+
+// RateLimitReporter defines an interface that reports the latest quota of the API calls.
+// When APIClient satisfies this, Adapter.RateLimit reports the quota.
+type RateLimitReporter interface {
+	// RateLimit returns the latest quota. This may return nil when no quota is reported yet.
+	RateLimit() *RateLimit
+}
+
+// rateLimiter holds the latest RateLimit and blocks the API calls while the quota is exhausted.
+// The zero value is ready to use.
+type rateLimiter struct {
+	latest *RateLimit
+	now    func() time.Time
+	mutex  sync.Mutex
+}
+
+func (l *rateLimiter) currentTime() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+	return l.now()
+}
+
+// update stores the quota reported by the given response header.
+func (l *rateLimiter) update(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.currentTime()
+	l.latest = &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		ResetAt:   parseRateLimitReset(header.Get("X-RateLimit-Reset"), now),
+		UpdatedAt: now,
+	}
+}
+
+// wait blocks until the quota is reset when the latest quota is exhausted.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mutex.Lock()
+	var d time.Duration
+	if l.latest != nil && l.latest.Remaining <= 0 {
+		d = l.latest.ResetAt.Sub(l.currentTime())
+	}
+	l.mutex.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+
+	logger.Warnf("Gitter API quota is exhausted. Waiting %s for the reset.", d)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-timer.C:
+		return nil
+
+	}
+}
+
+// snapshot returns a copy of the latest quota.
+func (l *rateLimiter) snapshot() *RateLimit {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.latest == nil {
+		return nil
+	}
+	copied := *l.latest
+	return &copied
+}
+
+// parseRateLimitReset parses the value of the X-RateLimit-Reset header, which is given as a Unix time in milliseconds or in seconds.
+// When the value can not be parsed, the given current time is returned so no wait occurs.
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset <= 0 {
+		return now
+	}
+
+	// A Unix time in seconds does not reach this value until the year 33658.
+	if reset >= 1e12 {
+		return time.UnixMilli(reset)
+	}
+	return time.Unix(reset, 0)
+}
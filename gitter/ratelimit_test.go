@@ -0,0 +1,95 @@
+package gitter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_rateLimiter_update(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	limiter := &rateLimiter{
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	// Headers are not given.
+	limiter.update(http.Header{})
+	if limiter.snapshot() != nil {
+		t.Fatal("RateLimit should not be set without headers.")
+	}
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "100")
+	header.Set("X-RateLimit-Remaining", "42")
+	header.Set("X-RateLimit-Reset", "1767258060000")
+	limiter.update(header)
+
+	limit := limiter.snapshot()
+	if limit == nil {
+		t.Fatal("RateLimit is not set.")
+	}
+	if limit.Limit != 100 || limit.Remaining != 42 || !limit.UpdatedAt.Equal(now) {
+		t.Errorf("Unexpected values are set: %#v.", limit)
+	}
+	if !limit.ResetAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Unexpected reset time is set: %s.", limit.ResetAt)
+	}
+
+	// The snapshot is a copy.
+	limit.Remaining = 0
+	if limiter.snapshot().Remaining != 42 {
+		t.Error("Snapshot should not share the state.")
+	}
+}
+
+func Test_rateLimiter_wait(t *testing.T) {
+	limiter := &rateLimiter{}
+	if err := limiter.wait(context.TODO()); err != nil {
+		t.Fatalf("Unexpected error is returned without quota: %s.", err.Error())
+	}
+
+	limiter.latest = &RateLimit{Remaining: 0, ResetAt: time.Now().Add(50 * time.Millisecond)}
+	started := time.Now()
+	if err := limiter.wait(context.TODO()); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if time.Since(started) < 40*time.Millisecond {
+		t.Error("Wait should block until the reset.")
+	}
+
+	limiter.latest = &RateLimit{Remaining: 0, ResetAt: time.Now().Add(time.Hour)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.wait(ctx); err == nil {
+		t.Error("Expected error is not returned on context cancellation.")
+	}
+
+	limiter.latest = &RateLimit{Remaining: 1, ResetAt: time.Now().Add(time.Hour)}
+	if err := limiter.wait(ctx); err != nil {
+		t.Errorf("Wait should not block with the remaining quota: %s.", err.Error())
+	}
+}
+
+func Test_parseRateLimitReset(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	testSets := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "1767258000000", expected: now},
+		{value: "1767258000", expected: now},
+		{value: "", expected: now.Add(-time.Hour)},
+		{value: "invalid", expected: now.Add(-time.Hour)},
+	}
+
+	for i, tt := range testSets {
+		reset := parseRateLimitReset(tt.value, now.Add(-time.Hour))
+		if !reset.Equal(tt.expected) {
+			t.Errorf("Unexpected time is returned on test #%d: %s.", i+1, reset)
+		}
+	}
+}
@@ -4,40 +4,116 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	// RestAPIEndpoint defines base url of Gitter REST API.
 	RestAPIEndpoint = "https://api.gitter.im/"
+
+	// roomsPageSize is the number of rooms to fetch per request.
+	roomsPageSize = 100
 )
 
+// MessagesFetcher defines an interface that fetches the messages of a Gitter room.
+// When APIClient satisfies this, Adapter polls the rooms that exceed Config.MaxStreamingConnections.
+type MessagesFetcher interface {
+	// Messages fetches the messages posted to the given room after the message with the given ID in chronological order.
+	// When afterID is empty, the latest messages are returned.
+	Messages(ctx context.Context, room *Room, afterID string) ([]*Message, error)
+}
+
 // RoomsFetcher defines an interface that fetches Gitter rooms.
 type RoomsFetcher interface {
 	// Rooms fetch the list of rooms the token's owner belongs.
 	Rooms(context.Context) (*Rooms, error)
 }
 
+// ClientOption defines a function's signature that RestAPIClient's and StreamingAPIClient's functional options must satisfy.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	httpClient *http.Client
+}
+
+// ClientWithHTTPClient creates a ClientOption that replaces the default http.Client with the given one.
+// Use this to communicate via a proxy server or to trust a private CA. See sarah.NewHTTPClient.
+func ClientWithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(options *clientOptions) {
+		options.httpClient = httpClient
+	}
+}
+
+func applyClientOptions(options []ClientOption) *clientOptions {
+	applied := &clientOptions{}
+	for _, opt := range options {
+		opt(applied)
+	}
+	return applied
+}
+
+// doHTTPRequest sends the given request with the given http.Client, or with http.DefaultClient when nil is given.
+func doHTTPRequest(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	if httpClient == nil {
+		return http.DefaultClient.Do(req)
+	}
+	return httpClient.Do(req)
+}
+
+// checkResponseStatus returns an error when the given response has a non-2xx status code.
+// The error is classified with sarah's error types so a supervising function can judge how to react.
+func checkResponseStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err := fmt.Errorf("unexpected status code is returned: %d", resp.StatusCode)
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return sarah.NewAuthError(err)
+
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return sarah.NewRateLimitError(err, sarah.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+
+	case resp.StatusCode >= 500:
+		return sarah.NewTransientError(err)
+
+	default:
+		return err
+
+	}
+}
+
 // RestAPIClient utilizes Gitter REST API.
 type RestAPIClient struct {
 	token      string
 	apiVersion string
+	httpClient *http.Client
+	limiter    rateLimiter
 }
 
+var _ APIClient = (*RestAPIClient)(nil)
+var _ MessagesFetcher = (*RestAPIClient)(nil)
+var _ RateLimitReporter = (*RestAPIClient)(nil)
+
 // NewVersionSpecificRestAPIClient creates a new API client instance with the given API version.
-func NewVersionSpecificRestAPIClient(token string, apiVersion string) *RestAPIClient {
+func NewVersionSpecificRestAPIClient(token string, apiVersion string, options ...ClientOption) *RestAPIClient {
 	return &RestAPIClient{
 		token:      token,
 		apiVersion: apiVersion,
+		httpClient: applyClientOptions(options).httpClient,
 	}
 }
 
 // NewRestAPIClient creates and returns a new API client instance. The version is fixed to v1.
-func NewRestAPIClient(token string) *RestAPIClient {
-	return NewVersionSpecificRestAPIClient(token, "v1")
+func NewRestAPIClient(token string, options ...ClientOption) *RestAPIClient {
+	return NewVersionSpecificRestAPIClient(token, "v1", options...)
 }
 
 func (client *RestAPIClient) buildEndpoint(resourceFragments []string) *url.URL {
@@ -49,8 +125,11 @@ func (client *RestAPIClient) buildEndpoint(resourceFragments []string) *url.URL
 
 // Get sends an HTTP GET request with the given path and parameters.
 func (client *RestAPIClient) Get(ctx context.Context, resourceFragments []string, intf interface{}) error {
+	return client.get(ctx, client.buildEndpoint(resourceFragments), intf)
+}
+
+func (client *RestAPIClient) get(ctx context.Context, endpoint *url.URL, intf interface{}) error {
 	// Set up sending request
-	endpoint := client.buildEndpoint(resourceFragments)
 	req, err := http.NewRequest("GET", endpoint.String(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to construct HTTP request: %w", err)
@@ -58,24 +137,7 @@ func (client *RestAPIClient) Get(ctx context.Context, resourceFragments []string
 	req.Header.Set("Authorization", "Bearer "+client.token)
 	req.Header.Set("Accept", "application/json")
 
-	req = req.WithContext(ctx)
-
-	// Do request
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed executing HTTP request: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	// Handle response
-	err = json.NewDecoder(resp.Body).Decode(&intf)
-	if err != nil {
-		return fmt.Errorf("can not unmarshal given JSON structure: %w", err)
-	}
-
-	// Done
-	return nil
+	return client.do(ctx, req, intf)
 }
 
 // Post sends an HTTP POST request to Gitter with the given parameters.
@@ -94,16 +156,33 @@ func (client *RestAPIClient) Post(ctx context.Context, resourceFragments []strin
 	req.Header.Set("Authorization", "Bearer "+client.token)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
-	req = req.WithContext(ctx)
 
-	resp, err := http.DefaultClient.Do(req)
+	return client.do(ctx, req, responsePayload)
+}
+
+// do sends the given request and decodes the response to responsePayload.
+// When the quota of the API calls is exhausted, this waits for the quota to be reset before sending the request
+// so the excessive requests do not get the token banned.
+func (client *RestAPIClient) do(ctx context.Context, req *http.Request, responsePayload interface{}) error {
+	err := client.limiter.wait(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the rate limit reset: %w", err)
+	}
+
+	// Do request
+	resp, err := doHTTPRequest(client.httpClient, req.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed executing HTTP request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
-	// TODO check status code
+	client.limiter.update(resp.Header)
+
+	err = checkResponseStatus(resp)
+	if err != nil {
+		return err
+	}
 
 	// Handle response
 	err = json.NewDecoder(resp.Body).Decode(&responsePayload)
@@ -115,13 +194,58 @@ func (client *RestAPIClient) Post(ctx context.Context, resourceFragments []strin
 	return nil
 }
 
+// RateLimit returns the latest quota of the API calls reported by Gitter.
+// This returns nil until a response with the X-RateLimit headers is received.
+func (client *RestAPIClient) RateLimit() *RateLimit {
+	return client.limiter.snapshot()
+}
+
 // Rooms fetches belonging rooms' information.
+// The rooms are fetched page by page so a user belonging to a large number of rooms can fetch all of them.
 func (client *RestAPIClient) Rooms(ctx context.Context) (*Rooms, error) {
-	rooms := &Rooms{}
-	if err := client.Get(ctx, []string{"rooms"}, &rooms); err != nil {
-		return nil, err
+	rooms := Rooms{}
+	fetched := map[string]struct{}{}
+	for skip := 0; ; skip += roomsPageSize {
+		endpoint := client.buildEndpoint([]string{"rooms"})
+		endpoint.RawQuery = url.Values{
+			"limit": []string{strconv.Itoa(roomsPageSize)},
+			"skip":  []string{strconv.Itoa(skip)},
+		}.Encode()
+
+		page := Rooms{}
+		if err := client.get(ctx, endpoint, &page); err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, room := range page {
+			if _, ok := fetched[room.ID]; ok {
+				continue
+			}
+			fetched[room.ID] = struct{}{}
+			rooms = append(rooms, room)
+			added++
+		}
+
+		// Stop on the last page, or when the pagination is ignored and the same rooms are returned again.
+		if len(page) < roomsPageSize || added == 0 {
+			return &rooms, nil
+		}
+	}
+}
+
+// Messages fetches the messages posted to the given room after the message with the given ID.
+func (client *RestAPIClient) Messages(ctx context.Context, room *Room, afterID string) ([]*Message, error) {
+	endpoint := client.buildEndpoint([]string{"rooms", room.ID, "chatMessages"})
+	if afterID != "" {
+		endpoint.RawQuery = url.Values{"afterId": []string{afterID}}.Encode()
+	}
+
+	var messages []*Message
+	if err := client.get(ctx, endpoint, &messages); err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
 	}
-	return rooms, nil
+	return messages, nil
 }
 
 // PostMessage sends a message to Gitter.
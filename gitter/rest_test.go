@@ -3,11 +3,15 @@ package gitter
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewRestAPIClient(t *testing.T) {
@@ -21,6 +25,12 @@ func TestNewRestAPIClient(t *testing.T) {
 	if client.token != token {
 		t.Errorf("Supplied token is not set: %s.", client.token)
 	}
+
+	httpClient := &http.Client{}
+	client = NewRestAPIClient(token, ClientWithHTTPClient(httpClient))
+	if client.httpClient != httpClient {
+		t.Error("Supplied http.Client is not set.")
+	}
 }
 
 func TestNewVersionSpecificRestAPIClient(t *testing.T) {
@@ -135,7 +145,7 @@ func TestRestAPIClient_Post(t *testing.T) {
 		}
 
 		return &http.Response{
-			StatusCode: http.StatusNotFound,
+			StatusCode: http.StatusOK,
 			Body:       io.NopCloser(strings.NewReader(string(bytes))),
 		}, nil
 	})
@@ -177,7 +187,7 @@ func TestRestAPIClient_Rooms(t *testing.T) {
 		}
 
 		return &http.Response{
-			StatusCode: http.StatusNotFound,
+			StatusCode: http.StatusOK,
 			Body:       io.NopCloser(strings.NewReader(string(bytes))),
 		}, nil
 	})
@@ -198,6 +208,150 @@ func TestRestAPIClient_Rooms(t *testing.T) {
 	}
 }
 
+func TestRestAPIClient_Rooms_Pagination(t *testing.T) {
+	var skips []string
+	resetClient := switchHTTPClient(func(req *http.Request) (*http.Response, error) {
+		skip := req.URL.Query().Get("skip")
+		skips = append(skips, skip)
+
+		// Return a full page first, and then the last page.
+		size := roomsPageSize
+		if skip != "0" {
+			size = 1
+		}
+		rooms := make([]string, size)
+		for i := range rooms {
+			rooms[i] = fmt.Sprintf(`{"id": "%s_%d"}`, skip, i)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("[" + strings.Join(rooms, ",") + "]")),
+		}, nil
+	})
+	defer resetClient()
+
+	client := &RestAPIClient{
+		token:      "buzz",
+		apiVersion: "v1",
+	}
+	rooms, err := client.Rooms(context.TODO())
+
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(*rooms) != roomsPageSize+1 {
+		t.Errorf("Unexpected number of rooms are returned: %d.", len(*rooms))
+	}
+
+	if len(skips) != 2 || skips[1] != strconv.Itoa(roomsPageSize) {
+		t.Errorf("Unexpected pages are requested: %#v.", skips)
+	}
+}
+
+func TestRestAPIClient_Rooms_PaginationIgnored(t *testing.T) {
+	requested := 0
+	resetClient := switchHTTPClient(func(_ *http.Request) (*http.Response, error) {
+		requested++
+
+		// The same rooms are returned regardless of the pagination parameters.
+		rooms := make([]string, roomsPageSize)
+		for i := range rooms {
+			rooms[i] = fmt.Sprintf(`{"id": "%d"}`, i)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("[" + strings.Join(rooms, ",") + "]")),
+		}, nil
+	})
+	defer resetClient()
+
+	client := &RestAPIClient{
+		token:      "buzz",
+		apiVersion: "v1",
+	}
+	rooms, err := client.Rooms(context.TODO())
+
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(*rooms) != roomsPageSize || requested != 2 {
+		t.Errorf("Unexpected result: %d rooms with %d requests.", len(*rooms), requested)
+	}
+}
+
+func TestRestAPIClient_RateLimit(t *testing.T) {
+	resetClient := switchHTTPClient(func(_ *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("X-RateLimit-Limit", "100")
+		header.Set("X-RateLimit-Remaining", "99")
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).UnixMilli(), 10))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+		}, nil
+	})
+	defer resetClient()
+
+	client := &RestAPIClient{
+		token:      "buzz",
+		apiVersion: "v1",
+	}
+	if client.RateLimit() != nil {
+		t.Fatal("RateLimit should not be returned before any request.")
+	}
+
+	_, err := client.PostMessage(context.TODO(), &Room{ID: "room"}, "Hello")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	limit := client.RateLimit()
+	if limit == nil || limit.Limit != 100 || limit.Remaining != 99 {
+		t.Errorf("Unexpected RateLimit is returned: %#v.", limit)
+	}
+}
+
+func TestRestAPIClient_Messages(t *testing.T) {
+	resetClient := switchHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			t.Fatalf("Unexpected request method: %s.", req.Method)
+		}
+
+		if req.URL.Path != "/v1/rooms/room/chatMessages" {
+			t.Errorf("Unexpected path is requested: %s.", req.URL.Path)
+		}
+
+		if req.URL.Query().Get("afterId") != "123" {
+			t.Errorf("Unexpected query is given: %s.", req.URL.RawQuery)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`[{"id":"124","text":"Hello"}]`)),
+		}, nil
+	})
+	defer resetClient()
+
+	client := &RestAPIClient{
+		token:      "buzz",
+		apiVersion: "v1",
+	}
+	messages, err := client.Messages(context.TODO(), &Room{ID: "room"}, "123")
+
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(messages) != 1 || messages[0].ID != "124" {
+		t.Errorf("Unexpected messages are returned: %#v.", messages)
+	}
+}
+
 func TestRestAPIClient_PostMessage(t *testing.T) {
 	resetClient := switchHTTPClient(func(req *http.Request) (*http.Response, error) {
 		if req.Method != http.MethodPost {
@@ -219,7 +373,7 @@ func TestRestAPIClient_PostMessage(t *testing.T) {
 		}
 
 		return &http.Response{
-			StatusCode: http.StatusNotFound,
+			StatusCode: http.StatusOK,
 			Body:       io.NopCloser(strings.NewReader(string(bytes))),
 		}, nil
 	})
@@ -264,3 +418,52 @@ func switchHTTPClient(fnc roundTripFnc) func() {
 		http.DefaultClient = oldClient
 	}
 }
+
+func Test_checkResponseStatus(t *testing.T) {
+	testSets := []struct {
+		status    int
+		header    http.Header
+		transient bool
+		auth      bool
+		rateLimit bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusUnauthorized, auth: true},
+		{status: http.StatusForbidden, auth: true},
+		{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": []string{"30"}}, transient: true, rateLimit: true},
+		{status: http.StatusServiceUnavailable, transient: true},
+		{status: http.StatusNotFound},
+	}
+
+	for i, tt := range testSets {
+		err := checkResponseStatus(&http.Response{StatusCode: tt.status, Header: tt.header})
+
+		if tt.status == http.StatusOK {
+			if err != nil {
+				t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("Expected error is not returned on test %d.", i)
+			continue
+		}
+
+		if sarah.IsTransientError(err) != tt.transient {
+			t.Errorf("Unexpected transient classification on test %d: %#v.", i, err)
+		}
+
+		if sarah.IsAuthError(err) != tt.auth {
+			t.Errorf("Unexpected auth classification on test %d: %#v.", i, err)
+		}
+
+		retryAfter, ok := sarah.RetryAfter(err)
+		if ok != tt.rateLimit {
+			t.Errorf("Unexpected rate limit classification on test %d: %#v.", i, err)
+		}
+		if ok && retryAfter != 30*time.Second {
+			t.Errorf("Unexpected Retry-After value on test %d: %s.", i, retryAfter)
+		}
+	}
+}
@@ -22,20 +22,24 @@ type StreamConnector interface {
 type StreamingAPIClient struct {
 	token      string
 	apiVersion string
+	httpClient *http.Client
 }
 
 // NewVersionSpecificStreamingAPIClient creates and returns a new Streaming API client instance.
-func NewVersionSpecificStreamingAPIClient(apiVersion string, token string) *StreamingAPIClient {
+//
+// Since a streaming connection lasts long, the given http.Client's Timeout must be zero when one is given with ClientWithHTTPClient.
+func NewVersionSpecificStreamingAPIClient(apiVersion string, token string, options ...ClientOption) *StreamingAPIClient {
 	return &StreamingAPIClient{
 		token:      token,
 		apiVersion: apiVersion,
+		httpClient: applyClientOptions(options).httpClient,
 	}
 }
 
 // NewStreamingAPIClient creates and returns a new Streaming API client instance.
 // The API version is fixed to v1.
-func NewStreamingAPIClient(token string) *StreamingAPIClient {
-	return NewVersionSpecificStreamingAPIClient("v1", token)
+func NewStreamingAPIClient(token string, options ...ClientOption) *StreamingAPIClient {
+	return NewVersionSpecificStreamingAPIClient("v1", token, options...)
 }
 
 func (client *StreamingAPIClient) buildEndpoint(room *Room) *url.URL {
@@ -57,11 +61,17 @@ func (client *StreamingAPIClient) Connect(ctx context.Context, room *Room) (Conn
 	req = req.WithContext(ctx)
 
 	// Do request
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doHTTPRequest(client.httpClient, req)
 
 	if err != nil {
 		return nil, err
 	}
 
+	err = checkResponseStatus(resp)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
 	return newConnWrapper(room, resp.Body), nil
 }
@@ -0,0 +1,265 @@
+// Package heartbeat provides utilities to supervise a long-lived connection between an adapter and its chat service.
+//
+// Watchdog periodically sends a ping and expects any sign of liveness within a deadline.
+// When too many pings are missed in a row, Watchdog.Run returns ErrHeartbeatTimeout so the adapter can close the connection and reconnect.
+// Backoff calculates the interval between reconnection trials so a flapping connection does not flood the chat service.
+//
+//	config := heartbeat.NewConfig()
+//	backoff := heartbeat.NewBackoff(config)
+//	for {
+//		conn, err := connect(ctx)
+//		if err != nil {
+//			if backoff.Wait(ctx) != nil {
+//				return
+//			}
+//			continue
+//		}
+//		backoff.Reset()
+//
+//		watchdog := heartbeat.NewWatchdog(config, conn)
+//		go receive(conn, watchdog.Alive) // Call watchdog.Alive on every incoming payload
+//		err = watchdog.Run(ctx)
+//		conn.Close()
+//		if err == nil {
+//			return // Context is canceled.
+//		}
+//	}
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"math"
+	"time"
+)
+
+// ErrHeartbeatTimeout is returned by Watchdog.Run when no sign of liveness is given for Config.MaxMissedPings consecutive pings.
+var ErrHeartbeatTimeout = errors.New("no heartbeat response is given")
+
+// Config contains some configuration variables for Watchdog and Backoff.
+type Config struct {
+	// PingInterval declares the interval to send a ping.
+	PingInterval time.Duration `json:"ping_interval" yaml:"ping_interval"`
+
+	// PongDeadline declares how long to wait for a sign of liveness after a ping is sent.
+	// When this is zero, pings are sent but the response is not checked.
+	PongDeadline time.Duration `json:"pong_deadline" yaml:"pong_deadline"`
+
+	// MaxMissedPings declares the number of consecutive missed pings to consider the connection dead.
+	// When this is zero, the connection is never considered dead due to the lack of response.
+	MaxMissedPings int `json:"max_missed_pings" yaml:"max_missed_pings"`
+
+	// ReconnectInterval declares the initial interval between reconnection trials.
+	ReconnectInterval time.Duration `json:"reconnect_interval" yaml:"reconnect_interval"`
+
+	// MaxReconnectInterval declares the upper limit of the interval between reconnection trials.
+	MaxReconnectInterval time.Duration `json:"max_reconnect_interval" yaml:"max_reconnect_interval"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		PingInterval:         30 * time.Second,
+		PongDeadline:         10 * time.Second,
+		MaxMissedPings:       3,
+		ReconnectInterval:    1 * time.Second,
+		MaxReconnectInterval: 1 * time.Minute,
+	}
+}
+
+// Pinger defines an interface that sends a ping over the supervised connection.
+type Pinger interface {
+	Ping() error
+}
+
+// Watchdog supervises a connection by sending pings and checking the sign of liveness.
+// Calls to Alive and TryPing are non-blocking and can be made from any goroutine.
+type Watchdog struct {
+	config  *Config
+	pinger  Pinger
+	alive   chan struct{}
+	tryPing chan struct{}
+}
+
+// NewWatchdog creates and returns a new Watchdog instance.
+// Pinger can be nil when the chat service sends keep-alive messages by itself.
+// In that case, Watchdog sends nothing and counts a missed ping on every Config.PingInterval when Alive is not called during the interval.
+func NewWatchdog(config *Config, pinger Pinger) *Watchdog {
+	return &Watchdog{
+		config:  config,
+		pinger:  pinger,
+		alive:   make(chan struct{}, 1),
+		tryPing: make(chan struct{}, 1),
+	}
+}
+
+// Alive tells Watchdog that the connection is alive.
+// Call this when a pong or any other payload is received over the connection.
+func (w *Watchdog) Alive() {
+	signal(w.alive)
+}
+
+// TryPing lets Watchdog send a ping immediately.
+// Call this when the connection seems unstable and an early detection of the dead connection is preferred.
+func (w *Watchdog) TryPing() {
+	signal(w.tryPing)
+}
+
+// Run supervises the connection until the given context is canceled or the connection is considered dead.
+// This returns nil on context cancellation, an error returned by Pinger on ping failure, or ErrHeartbeatTimeout.
+func (w *Watchdog) Run(ctx context.Context) error {
+	// Zero interval disables periodic pings. Pings are sent only on TryPing calls.
+	var tick <-chan time.Time
+	if w.config.PingInterval > 0 {
+		ticker := time.NewTicker(w.config.PingInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var deadline <-chan time.Time
+	var timer *time.Timer
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = nil
+		deadline = nil
+	}
+	defer stopTimer()
+
+	missed := 0
+	aliveSinceTick := false
+	miss := func() error {
+		missed++
+		logger.Debugf("Missed heartbeat response: %d", missed)
+		if w.config.MaxMissedPings > 0 && missed >= w.config.MaxMissedPings {
+			return ErrHeartbeatTimeout
+		}
+		return nil
+	}
+	ping := func() error {
+		if w.pinger == nil {
+			// Nothing to send. Only see if the chat service sent something during the last interval.
+			if !aliveSinceTick {
+				return miss()
+			}
+			aliveSinceTick = false
+			return nil
+		}
+
+		logger.Debug("Send ping")
+		err := w.pinger.Ping()
+		if err != nil {
+			return fmt.Errorf("error on ping: %w", err)
+		}
+
+		if deadline == nil && w.config.PongDeadline > 0 {
+			timer = time.NewTimer(w.config.PongDeadline)
+			deadline = timer.C
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-w.alive:
+			missed = 0
+			aliveSinceTick = true
+			stopTimer()
+
+		case <-tick:
+			err := ping()
+			if err != nil {
+				return err
+			}
+
+		case <-w.tryPing:
+			if w.pinger == nil {
+				// Nothing can be sent, so wait for the next tick.
+				continue
+			}
+			err := ping()
+			if err != nil {
+				return err
+			}
+
+		case <-deadline:
+			timer = nil
+			deadline = nil
+			err := miss()
+			if err != nil {
+				return err
+			}
+
+		}
+	}
+}
+
+// Backoff calculates the exponentially growing interval between reconnection trials.
+// A Backoff instance is not safe for concurrent use; create one for each connection.
+type Backoff struct {
+	config  *Config
+	attempt int
+}
+
+// NewBackoff creates and returns a new Backoff instance.
+func NewBackoff(config *Config) *Backoff {
+	return &Backoff{
+		config: config,
+	}
+}
+
+// Next returns the interval to wait before the next reconnection trial and increments the number of attempts.
+// The interval doubles on every call, starting from Config.ReconnectInterval up to Config.MaxReconnectInterval.
+func (b *Backoff) Next() time.Duration {
+	interval := float64(b.config.ReconnectInterval) * math.Pow(2, float64(b.attempt))
+	b.attempt++
+
+	if b.config.MaxReconnectInterval > 0 && interval > float64(b.config.MaxReconnectInterval) {
+		return b.config.MaxReconnectInterval
+	}
+	if interval > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(interval)
+}
+
+// Reset resets the number of attempts. Call this when a connection is successfully established.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Wait blocks for the interval returned by Next.
+// This returns the context's error when the given context is canceled before the interval elapses.
+func (b *Backoff) Wait(ctx context.Context) error {
+	timer := time.NewTimer(b.Next())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-timer.C:
+		return nil
+
+	}
+}
+
+// signal tries to send a signal to the given channel in a non-blocking manner.
+// If one is working on a task triggered by the previous signal, this skips signaling rather than blocking till one is ready to read the channel.
+func signal(target chan<- struct{}) {
+	select {
+	case target <- struct{}{}:
+		// O.K
+
+	default:
+		// A signal is already pending.
+
+	}
+}
@@ -0,0 +1,234 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/logger"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	oldLogger := logger.GetLogger()
+	defer logger.SetLogger(oldLogger)
+
+	l := log.New(io.Discard, "dummyLog", 0)
+	logger.SetLogger(logger.NewWithStandardLogger(l))
+
+	code := m.Run()
+
+	os.Exit(code)
+}
+
+type DummyPinger struct {
+	PingFunc func() error
+}
+
+func (p *DummyPinger) Ping() error {
+	return p.PingFunc()
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+
+	if config.PingInterval <= 0 || config.PongDeadline <= 0 || config.MaxMissedPings <= 0 {
+		t.Errorf("Unexpected default heartbeat setting: %#v.", config)
+	}
+
+	if config.ReconnectInterval <= 0 || config.MaxReconnectInterval < config.ReconnectInterval {
+		t.Errorf("Unexpected default reconnect setting: %#v.", config)
+	}
+}
+
+func TestWatchdog_Run(t *testing.T) {
+	t.Run("Context cancellation", func(t *testing.T) {
+		pinged := make(chan struct{}, 1)
+		pinger := &DummyPinger{
+			PingFunc: func() error {
+				signal(pinged)
+				return nil
+			},
+		}
+		watchdog := NewWatchdog(&Config{PingInterval: 10 * time.Millisecond}, pinger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- watchdog.Run(ctx)
+		}()
+
+		select {
+		case <-pinged:
+			// O.K.
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Fatal("Ping is not sent.")
+
+		}
+
+		cancel()
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Errorf("Unexpected error is returned: %s.", err.Error())
+			}
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Fatal("Run does not return on context cancellation.")
+
+		}
+	})
+
+	t.Run("Ping error", func(t *testing.T) {
+		expected := errors.New("ping error")
+		pinger := &DummyPinger{
+			PingFunc: func() error {
+				return expected
+			},
+		}
+		watchdog := NewWatchdog(&Config{PingInterval: 10 * time.Millisecond}, pinger)
+
+		err := watchdog.Run(context.TODO())
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Missed pongs", func(t *testing.T) {
+		pinger := &DummyPinger{
+			PingFunc: func() error {
+				return nil
+			},
+		}
+		config := &Config{
+			PingInterval:   10 * time.Millisecond,
+			PongDeadline:   5 * time.Millisecond,
+			MaxMissedPings: 2,
+		}
+		watchdog := NewWatchdog(config, pinger)
+
+		err := watchdog.Run(context.TODO())
+		if !errors.Is(err, ErrHeartbeatTimeout) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Pong is given", func(t *testing.T) {
+		config := &Config{
+			PingInterval:   10 * time.Millisecond,
+			PongDeadline:   5 * time.Millisecond,
+			MaxMissedPings: 1,
+		}
+		var watchdog *Watchdog
+		pinger := &DummyPinger{
+			PingFunc: func() error {
+				watchdog.Alive()
+				return nil
+			},
+		}
+		watchdog = NewWatchdog(config, pinger)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err := watchdog.Run(ctx)
+		if err != nil {
+			t.Errorf("Unexpected error is returned: %s.", err.Error())
+		}
+	})
+
+	t.Run("No pinger", func(t *testing.T) {
+		config := &Config{
+			PingInterval:   10 * time.Millisecond,
+			MaxMissedPings: 2,
+		}
+		watchdog := NewWatchdog(config, nil)
+
+		err := watchdog.Run(context.TODO())
+		if !errors.Is(err, ErrHeartbeatTimeout) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("TryPing", func(t *testing.T) {
+		pinged := make(chan struct{}, 1)
+		pinger := &DummyPinger{
+			PingFunc: func() error {
+				signal(pinged)
+				return nil
+			},
+		}
+		// Periodic ping is disabled.
+		watchdog := NewWatchdog(&Config{}, pinger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_ = watchdog.Run(ctx)
+		}()
+
+		watchdog.TryPing()
+		select {
+		case <-pinged:
+			// O.K.
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Fatal("Ping is not sent.")
+
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	config := &Config{
+		ReconnectInterval:    1 * time.Second,
+		MaxReconnectInterval: 5 * time.Second,
+	}
+	backoff := NewBackoff(config)
+
+	expected := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, e := range expected {
+		next := backoff.Next()
+		if next != e {
+			t.Errorf("Unexpected interval is returned on attempt %d: %s.", i, next)
+		}
+	}
+
+	backoff.Reset()
+	if next := backoff.Next(); next != config.ReconnectInterval {
+		t.Errorf("Interval is not reset: %s.", next)
+	}
+}
+
+func TestBackoff_Wait(t *testing.T) {
+	backoff := NewBackoff(&Config{ReconnectInterval: 1 * time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := backoff.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	backoff = NewBackoff(&Config{ReconnectInterval: 1 * time.Millisecond})
+	err = backoff.Wait(context.TODO())
+	if err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+}
+
+func Test_signal(t *testing.T) {
+	// Prepare a channel with a buffer of 1.
+	target := make(chan struct{}, 1)
+	defer close(target)
+
+	// Send twice. This exceed the target channel's cap, but the second call should not block.
+	signal(target)
+	signal(target)
+
+	if len(target) != 1 {
+		t.Errorf("The target channel should have exactly one signal: %d", len(target))
+	}
+}
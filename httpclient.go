@@ -0,0 +1,94 @@
+package sarah
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ErrInvalidCABundle is returned when the given CA bundle file does not contain any valid PEM encoded certificate.
+var ErrInvalidCABundle = errors.New("no valid certificate is found in the CA bundle")
+
+// HTTPClientConfig contains some configuration variables to build an *http.Client that Adapter implementations use to communicate with their chat services.
+// This is typically used to send requests via an HTTP(S) proxy or to trust a private CA in corporate environments.
+//
+//	slackConfig := slack.NewConfig()
+//	slackConfig.HTTPClient = &sarah.HTTPClientConfig{
+//		ProxyURL:     "http://proxy.example.com:8080",
+//		CABundleFile: "/etc/ssl/certs/corporate-ca.pem",
+//	}
+type HTTPClientConfig struct {
+	// ProxyURL declares the URL of the proxy server.
+	// When this is empty, the proxy is determined by HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string `json:"proxy_url" yaml:"proxy_url"`
+
+	// CABundleFile declares the path to a PEM encoded certificate file.
+	// The certificates are trusted in addition to the system's root CAs.
+	CABundleFile string `json:"ca_bundle_file" yaml:"ca_bundle_file"`
+
+	// Timeout declares the time limit for each request including connection and reading the response body.
+	// Zero value means no timeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// TLSHandshakeTimeout declares the time limit to wait for a TLS handshake.
+	TLSHandshakeTimeout time.Duration `json:"tls_handshake_timeout" yaml:"tls_handshake_timeout"`
+}
+
+// NewHTTPClientConfig creates and returns a new HTTPClientConfig instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewHTTPClientConfig() *HTTPClientConfig {
+	return &HTTPClientConfig{
+		ProxyURL:            "",
+		CABundleFile:        "",
+		Timeout:             0,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// NewHTTPClient creates and returns a new *http.Client with the given HTTPClientConfig.
+// The returned client's transport is based on http.DefaultTransport so other settings such as connection pooling stay the same.
+func NewHTTPClient(config *HTTPClientConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if config.CABundleFile != "" {
+		pem, err := os.ReadFile(config.CABundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, ErrInvalidCABundle
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	if config.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = config.TLSHandshakeTimeout
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   config.Timeout,
+	}, nil
+}
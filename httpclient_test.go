@@ -0,0 +1,75 @@
+package sarah
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClientConfig(t *testing.T) {
+	config := NewHTTPClientConfig()
+
+	if config.ProxyURL != "" || config.CABundleFile != "" {
+		t.Errorf("Unexpected default value is set: %#v.", config)
+	}
+
+	if config.TLSHandshakeTimeout <= 0 {
+		t.Errorf("Default TLS handshake timeout is not set: %s.", config.TLSHandshakeTimeout)
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("With proxy", func(t *testing.T) {
+		config := NewHTTPClientConfig()
+		config.ProxyURL = "http://proxy.example.com:8080"
+		config.Timeout = 3 * time.Second
+
+		client, err := NewHTTPClient(config)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if client.Timeout != config.Timeout {
+			t.Errorf("Given timeout is not set: %s.", client.Timeout)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+		proxyURL, err := client.Transport.(*http.Transport).Proxy(req)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if proxyURL == nil || proxyURL.String() != config.ProxyURL {
+			t.Errorf("Given proxy is not set: %s.", proxyURL)
+		}
+	})
+
+	t.Run("Missing CA bundle", func(t *testing.T) {
+		config := NewHTTPClientConfig()
+		config.CABundleFile = filepath.Join(t.TempDir(), "missing.pem")
+
+		_, err := NewHTTPClient(config)
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+	})
+
+	t.Run("Invalid CA bundle", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "invalid.pem")
+		err := os.WriteFile(file, []byte("invalid"), 0600)
+		if err != nil {
+			t.Fatalf("Failed to write file: %s.", err.Error())
+		}
+
+		config := NewHTTPClientConfig()
+		config.CABundleFile = file
+
+		_, err = NewHTTPClient(config)
+		if !errors.Is(err, ErrInvalidCABundle) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
@@ -9,7 +9,9 @@ import "time"
 // When the Input represents a request for help, then pass the Input to NewHelpInput to wrap it with a HelpInput;
 // when the Input represents a request for a context cancellation, pass the Input to AbortInput to wrap it with AbortInput.
 //
-// HelpInput and AbortInput can be passed to Sarah through the function given to Bot.Run -- func(Input) error -- just like any other Input.
+// When the chat service delivers multiple events at once, bundle the Inputs with NewBatchInput to have them handled in the delivered order.
+//
+// HelpInput, AbortInput, and BatchInput can be passed to Sarah through the function given to Bot.Run -- func(Input) error -- just like any other Input.
 // Sarah then passes a job to the worker.Worker implementation to execute Bot.Respond, in a panic-proof concurrent manner, with the given Input.
 type Input interface {
 	// SenderKey returns the stringified representation of the sender identifier.
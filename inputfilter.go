@@ -0,0 +1,101 @@
+package sarah
+
+import (
+	"context"
+	"slices"
+)
+
+// SourcedInput is an optional interface that an Input implementation can satisfy to tell who sent the Input and how.
+// InputFilterConfig refers to this to apply InputFilterConfig.DeniedUsers, InputFilterConfig.DirectMessageOnly, and InputFilterConfig.DenyDirectMessage.
+type SourcedInput interface {
+	Input
+
+	// UserID returns the identifier of the user who sent the Input.
+	// Unlike Input.SenderKey, this must not contain the identifier of the chat room.
+	UserID() string
+
+	// DirectMessage tells if the Input is sent via a direct message to the Bot.
+	DirectMessage() bool
+}
+
+// InputFilterConfig declares the restriction on the Inputs that a Bot handles.
+// The restriction is applied to every Input before it is passed to Bot.Respond, so Commands do not have to duplicate the same checks.
+// An Input that does not meet the restriction is silently discarded.
+//
+// The channel of an Input is identified by TranscriptChannel, which derives the key from Input.ReplyTo.
+// The other settings require the Input to satisfy SourcedInput.
+// When any of them is set, an Input that does not satisfy SourcedInput is discarded since its source can not be verified.
+type InputFilterConfig struct {
+	// AllowedChannels declares the channels where the Bot handles Inputs. When this is empty, Inputs from any channel are handled.
+	AllowedChannels []string `json:"allowed_channels" yaml:"allowed_channels"`
+
+	// DeniedUsers declares the users whose Inputs are discarded.
+	DeniedUsers []string `json:"denied_users" yaml:"denied_users"`
+
+	// DirectMessageOnly declares whether to handle only the Inputs that are sent via direct messages.
+	DirectMessageOnly bool `json:"direct_message_only" yaml:"direct_message_only"`
+
+	// DenyDirectMessage declares whether to discard the Inputs that are sent via direct messages.
+	DenyDirectMessage bool `json:"deny_direct_message" yaml:"deny_direct_message"`
+}
+
+// allows tells if the given Input meets the restriction.
+// The second returned value describes the reason when the Input is discarded.
+func (c *InputFilterConfig) allows(input Input) (bool, string) {
+	// HelpInput and AbortInput are created by the Adapter from the original Input, which is the one to be inspected.
+	switch typed := input.(type) {
+	case *HelpInput:
+		input = typed.OriginalInput
+
+	case *AbortInput:
+		input = typed.OriginalInput
+
+	}
+
+	if len(c.AllowedChannels) > 0 && !slices.Contains(c.AllowedChannels, TranscriptChannel(input)) {
+		return false, "the channel is not allowed"
+	}
+
+	if len(c.DeniedUsers) == 0 && !c.DirectMessageOnly && !c.DenyDirectMessage {
+		return true, ""
+	}
+
+	sourced, ok := input.(SourcedInput)
+	if !ok {
+		return false, "the source of the input can not be verified"
+	}
+
+	if slices.Contains(c.DeniedUsers, sourced.UserID()) {
+		return false, "the user is denied"
+	}
+
+	if c.DirectMessageOnly && !sourced.DirectMessage() {
+		return false, "only direct messages are allowed"
+	}
+
+	if c.DenyDirectMessage && sourced.DirectMessage() {
+		return false, "direct messages are denied"
+	}
+
+	return true, ""
+}
+
+// filteringInputReceiver wraps the given function to discard the Inputs that do not meet the restriction returned by the given function.
+// The restriction is obtained on every Input so a change via configuration reload is applied on the fly.
+func filteringInputReceiver(ctx context.Context, filter func() *InputFilterConfig, receive func(Input) error) func(Input) error {
+	return func(input Input) error {
+		config := filter()
+		if config == nil {
+			return receive(input)
+		}
+
+		if ok, reason := config.allows(input); !ok {
+			LoggerFromContext(ctx).Debugf("Discard an input because %s. SenderKey: %s", reason, input.SenderKey())
+			// The discarded Input is handled as intended, so the Adapter should not wait for its redelivery.
+			ackInput(input, nil)
+			return nil
+		}
+
+		return receive(input)
+	}
+}
@@ -0,0 +1,141 @@
+package sarah
+
+import (
+	"context"
+	"testing"
+)
+
+type DummySourcedInput struct {
+	DummyInput
+	UserIDValue        string
+	DirectMessageValue bool
+}
+
+func (i *DummySourcedInput) UserID() string {
+	return i.UserIDValue
+}
+
+func (i *DummySourcedInput) DirectMessage() bool {
+	return i.DirectMessageValue
+}
+
+func TestInputFilterConfig_allows(t *testing.T) {
+	testSets := []struct {
+		config  *InputFilterConfig
+		input   Input
+		allowed bool
+	}{
+		{
+			config:  &InputFilterConfig{},
+			input:   &DummyInput{ReplyToValue: "general"},
+			allowed: true,
+		},
+		{
+			config:  &InputFilterConfig{AllowedChannels: []string{"general"}},
+			input:   &DummyInput{ReplyToValue: "general"},
+			allowed: true,
+		},
+		{
+			config:  &InputFilterConfig{AllowedChannels: []string{"general"}},
+			input:   &DummyInput{ReplyToValue: "random"},
+			allowed: false,
+		},
+		{
+			config:  &InputFilterConfig{AllowedChannels: []string{"general"}},
+			input:   NewHelpInput(&DummyInput{ReplyToValue: "random"}),
+			allowed: false,
+		},
+		{
+			config:  &InputFilterConfig{DeniedUsers: []string{"spammer"}},
+			input:   &DummySourcedInput{UserIDValue: "spammer"},
+			allowed: false,
+		},
+		{
+			config:  &InputFilterConfig{DeniedUsers: []string{"spammer"}},
+			input:   &DummySourcedInput{UserIDValue: "oklahomer"},
+			allowed: true,
+		},
+		{
+			config:  &InputFilterConfig{DeniedUsers: []string{"spammer"}},
+			input:   &DummyInput{},
+			allowed: false,
+		},
+		{
+			config:  &InputFilterConfig{DeniedUsers: []string{"spammer"}},
+			input:   NewAbortInput(&DummySourcedInput{UserIDValue: "spammer"}),
+			allowed: false,
+		},
+		{
+			config:  &InputFilterConfig{DirectMessageOnly: true},
+			input:   &DummySourcedInput{DirectMessageValue: false},
+			allowed: false,
+		},
+		{
+			config:  &InputFilterConfig{DirectMessageOnly: true},
+			input:   &DummySourcedInput{DirectMessageValue: true},
+			allowed: true,
+		},
+		{
+			config:  &InputFilterConfig{DenyDirectMessage: true},
+			input:   &DummySourcedInput{DirectMessageValue: true},
+			allowed: false,
+		},
+		{
+			config:  &InputFilterConfig{DenyDirectMessage: true},
+			input:   &DummySourcedInput{DirectMessageValue: false},
+			allowed: true,
+		},
+	}
+
+	for i, tt := range testSets {
+		allowed, reason := tt.config.allows(tt.input)
+		if allowed != tt.allowed {
+			t.Errorf("Unexpected result on test #%d: %s.", i+1, reason)
+		}
+		if !allowed && reason == "" {
+			t.Errorf("Reason is not returned on test #%d.", i+1)
+		}
+	}
+}
+
+func Test_filteringInputReceiver(t *testing.T) {
+	var config *InputFilterConfig
+	var received []Input
+	receive := filteringInputReceiver(context.TODO(), func() *InputFilterConfig {
+		return config
+	}, func(input Input) error {
+		received = append(received, input)
+		return nil
+	})
+
+	// No restriction.
+	_ = receive(&DummyInput{ReplyToValue: "random"})
+
+	// The restriction is obtained on every Input.
+	config = &InputFilterConfig{AllowedChannels: []string{"general"}}
+	_ = receive(&DummyInput{ReplyToValue: "random"})
+	_ = receive(&DummyInput{ReplyToValue: "general"})
+
+	if len(received) != 2 {
+		t.Fatalf("Unexpected number of inputs are received: %d.", len(received))
+	}
+	if received[1].ReplyTo() != "general" {
+		t.Errorf("Unexpected input is received: %#v.", received[1])
+	}
+}
+
+func Test_runner_inputFilter(t *testing.T) {
+	r := &runner{}
+	if r.inputFilter("slack") != nil {
+		t.Error("Nil should be returned without Config.")
+	}
+
+	filter := &InputFilterConfig{DirectMessageOnly: true}
+	r.config = &Config{InputFilter: map[BotType]*InputFilterConfig{"slack": filter}}
+	if r.inputFilter("slack") != filter {
+		t.Errorf("Unexpected filter is returned: %#v.", r.inputFilter("slack"))
+	}
+	if r.inputFilter("gitter") != nil {
+		t.Errorf("Unexpected filter is returned: %#v.", r.inputFilter("gitter"))
+	}
+}
@@ -0,0 +1,144 @@
+package sarah
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var inputQueue = newInputQueueTracker()
+
+// QueuedInput represents an Input that is enqueued to the worker and is not handled completely, yet.
+type QueuedInput struct {
+	// CorrelationID is the unique ID of the Input that CorrelationIDFromContext returns and the log lines include.
+	CorrelationID string
+
+	// BotType represents the Bot that received the Input.
+	BotType BotType
+
+	// SenderKey is the value Input.SenderKey returns.
+	SenderKey string
+
+	// Message is the value Input.Message returns.
+	Message string
+
+	// ReceivedAt is the time when the Input was enqueued.
+	ReceivedAt time.Time
+
+	// StartedAt is the time when a worker started handling the Input. This is zero while the Input is waiting in the queue.
+	StartedAt time.Time
+}
+
+// InputQueueSnapshot returns the Inputs that are waiting in the worker's queue or being handled, in order of reception.
+// An Input that stays in this list for long indicates a stuck Command or a stuck worker.
+// The returned values are copies, so modifying them does not affect the tracking.
+func InputQueueSnapshot() []*QueuedInput {
+	return defaultRunner().InputQueueSnapshot()
+}
+
+// InputQueueSnapshot is the Runner counterpart of the package-level InputQueueSnapshot.
+func (rn *Runner) InputQueueSnapshot() []*QueuedInput {
+	return rn.registries.inputQueue.snapshot()
+}
+
+type inputQueueTracker struct {
+	entries map[string]*QueuedInput
+	waiters map[BotType][]chan struct{}
+	mutex   sync.Mutex
+}
+
+func newInputQueueTracker() *inputQueueTracker {
+	return &inputQueueTracker{
+		entries: map[string]*QueuedInput{},
+	}
+}
+
+func (t *inputQueueTracker) add(id string, botType BotType, input Input) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.entries[id] = &QueuedInput{
+		CorrelationID: id,
+		BotType:       botType,
+		SenderKey:     input.SenderKey(),
+		Message:       input.Message(),
+		ReceivedAt:    currentClock().Now(),
+	}
+}
+
+func (t *inputQueueTracker) start(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if entry, ok := t.entries[id]; ok {
+		entry.StartedAt = currentClock().Now()
+	}
+}
+
+func (t *inputQueueTracker) remove(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entry, ok := t.entries[id]
+	if !ok {
+		return
+	}
+	delete(t.entries, id)
+
+	if t.countLocked(entry.BotType) > 0 {
+		return
+	}
+	for _, waiter := range t.waiters[entry.BotType] {
+		close(waiter)
+	}
+	delete(t.waiters, entry.BotType)
+}
+
+// count returns the number of the tracked Inputs that the Bot with the given BotType received.
+func (t *inputQueueTracker) count(botType BotType) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.countLocked(botType)
+}
+
+func (t *inputQueueTracker) countLocked(botType BotType) int {
+	cnt := 0
+	for _, entry := range t.entries {
+		if entry.BotType == botType {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// idle returns a channel that is closed when no Input that the Bot with the given BotType received is tracked.
+func (t *inputQueueTracker) idle(botType BotType) <-chan struct{} {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	waiter := make(chan struct{})
+	if t.countLocked(botType) == 0 {
+		close(waiter)
+		return waiter
+	}
+
+	if t.waiters == nil {
+		t.waiters = map[BotType][]chan struct{}{}
+	}
+	t.waiters[botType] = append(t.waiters[botType], waiter)
+	return waiter
+}
+
+func (t *inputQueueTracker) snapshot() []*QueuedInput {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	inputs := make([]*QueuedInput, 0, len(t.entries))
+	for _, entry := range t.entries {
+		copied := *entry
+		inputs = append(inputs, &copied)
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].ReceivedAt.Before(inputs[j].ReceivedAt)
+	})
+	return inputs
+}
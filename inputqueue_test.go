@@ -0,0 +1,92 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_inputQueueTracker(t *testing.T) {
+	tracker := &inputQueueTracker{entries: map[string]*QueuedInput{}}
+	tracker.add("first", "dummy", &DummyInput{SenderKeyValue: "alice", MessageValue: ".deploy"})
+	tracker.add("second", "dummy", &DummyInput{SenderKeyValue: "bob", MessageValue: ".echo"})
+	tracker.start("first")
+
+	snapshot := tracker.snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("Unexpected number of inputs are returned: %d.", len(snapshot))
+	}
+	if snapshot[0].CorrelationID != "first" || snapshot[0].SenderKey != "alice" || snapshot[0].Message != ".deploy" {
+		t.Errorf("Unexpected input is returned: %#v.", snapshot[0])
+	}
+	if snapshot[0].StartedAt.IsZero() {
+		t.Error("Start time is not set.")
+	}
+	if !snapshot[1].StartedAt.IsZero() {
+		t.Error("Start time should not be set while waiting.")
+	}
+
+	// Modifying the copy must not affect the tracking.
+	snapshot[1].Message = "modified"
+	if tracker.snapshot()[1].Message != ".echo" {
+		t.Error("Tracked input is modified.")
+	}
+
+	tracker.remove("first")
+	tracker.start("unknown")
+	if len(tracker.snapshot()) != 1 {
+		t.Errorf("Removed input is still returned: %#v.", tracker.snapshot())
+	}
+}
+
+func Test_setupInputReceiver_InputQueue(t *testing.T) {
+	SetupAndRun(func() {
+		var job func()
+		worker := &DummyWorker{
+			EnqueueFunc: func(fnc func()) error {
+				job = fnc
+				return nil
+			},
+		}
+		bot := &DummyBot{
+			BotTypeValue: "DUMMY",
+			RespondFunc: func(ctx context.Context, _ Input) error {
+				for _, queued := range InputQueueSnapshot() {
+					if queued.CorrelationID == CorrelationIDFromContext(ctx) && !queued.StartedAt.IsZero() {
+						return nil
+					}
+				}
+				t.Error("Input being handled is not tracked.")
+				return nil
+			},
+		}
+
+		receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
+		_ = receiveInput(&DummyInput{SenderKeyValue: "queued"})
+		if !tracked("queued") {
+			t.Fatal("Enqueued input is not tracked.")
+		}
+
+		job()
+		if tracked("queued") {
+			t.Error("Handled input is still tracked.")
+		}
+
+		worker.EnqueueFunc = func(_ func()) error {
+			return errors.New("queue is full")
+		}
+		_ = receiveInput(&DummyInput{SenderKeyValue: "blocked"})
+		if tracked("blocked") {
+			t.Error("Blocked input should not be tracked.")
+		}
+	})
+}
+
+func tracked(senderKey string) bool {
+	for _, queued := range InputQueueSnapshot() {
+		if queued.SenderKey == senderKey {
+			return true
+		}
+	}
+	return false
+}
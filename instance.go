@@ -0,0 +1,88 @@
+package sarah
+
+import (
+	"context"
+)
+
+// Runner is an instance of Sarah that holds its own registrations and running status.
+// The package-level functions such as RegisterBot, Run, and CurrentStatus operate on the default Runner,
+// so use New only when two or more isolated Sarah instances must run in one process, e.g., in tests or when Sarah is embedded in another application.
+//
+//	runner := sarah.New()
+//	runner.RegisterBot(bot)
+//	runner.RegisterCommand(bot.BotType(), command)
+//	err := runner.Run(ctx, sarah.NewConfig())
+//
+// Each Runner tracks its own maintenance mode, input queue, and ScheduledTasks to trigger.
+// The Clock registered via the package-level RegisterClock and the logger are still shared across the process.
+type Runner struct {
+	options    *optionHolder
+	status     *status
+	running    *runningRunner
+	selfTester *selfTest
+	registries *registries
+}
+
+// New creates and returns a new Runner instance that is isolated from the default one.
+func New() *Runner {
+	reg := newRegistries()
+	return &Runner{
+		options:    &optionHolder{},
+		status:     &status{maintenance: reg.maintenance},
+		running:    &runningRunner{},
+		selfTester: &selfTest{},
+		registries: reg,
+	}
+}
+
+// defaultRunner returns the Runner that the package-level functions operate on.
+// This is built from the package-level variables on each call, so a test can reset the default Runner by replacing them.
+func defaultRunner() *Runner {
+	return &Runner{
+		options:    options,
+		status:     runnerStatus,
+		running:    running,
+		selfTester: selfTester,
+		registries: defaultRegistries(),
+	}
+}
+
+// registries holds the states that a Runner tracks while its Bots run.
+type registries struct {
+	maintenance  *maintenanceModes
+	inputQueue   *inputQueueTracker
+	taskTriggers *triggers
+}
+
+func newRegistries() *registries {
+	return &registries{
+		maintenance:  newMaintenanceModes(),
+		inputQueue:   newInputQueueTracker(),
+		taskTriggers: newTriggers(),
+	}
+}
+
+// defaultRegistries returns the registries of the default Runner.
+func defaultRegistries() *registries {
+	return &registries{
+		maintenance:  maintenance,
+		inputQueue:   inputQueue,
+		taskTriggers: taskTriggers,
+	}
+}
+
+type registriesKey struct{}
+
+func withRegistries(ctx context.Context, reg *registries) context.Context {
+	return context.WithValue(ctx, registriesKey{}, reg)
+}
+
+// registriesFromContext returns the registries of the Runner that the given context derives from.
+// The default Runner's registries are returned when the context does not derive from any Runner.
+func registriesFromContext(ctx context.Context) *registries {
+	reg, ok := ctx.Value(registriesKey{}).(*registries)
+	if !ok {
+		return defaultRegistries()
+	}
+	return reg
+}
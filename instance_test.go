@@ -0,0 +1,188 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	runner := New()
+
+	if runner.options == nil || runner.status == nil || runner.running == nil || runner.selfTester == nil || runner.registries == nil {
+		t.Errorf("Required fields are not set: %#v.", runner)
+	}
+
+	if runner.options == options || runner.status == runnerStatus {
+		t.Error("New Runner should not share the default Runner's state.")
+	}
+
+	reg := runner.registries
+	if reg.maintenance == maintenance || reg.inputQueue == inputQueue || reg.taskTriggers == taskTriggers {
+		t.Error("New Runner should not share the default Runner's registries.")
+	}
+	if runner.status.maintenance != reg.maintenance {
+		t.Error("Status should refer to the Runner's maintenance modes.")
+	}
+}
+
+func Test_registriesFromContext(t *testing.T) {
+	reg := registriesFromContext(context.Background())
+	if reg.maintenance != maintenance || reg.inputQueue != inputQueue || reg.taskTriggers != taskTriggers {
+		t.Errorf("Default registries should be returned: %#v.", reg)
+	}
+
+	given := newRegistries()
+	reg = registriesFromContext(withRegistries(context.Background(), given))
+	if reg != given {
+		t.Errorf("Given registries are not returned: %#v.", reg)
+	}
+}
+
+func TestRunner_EnterMaintenance(t *testing.T) {
+	var botType BotType = "isolated"
+	runner := New()
+	runner.EnterMaintenance(botType, "Upgrading.")
+	defer runner.ExitMaintenance(botType)
+
+	if _, ok := maintenance.message(botType); ok {
+		t.Error("Default Runner should not be in maintenance.")
+	}
+
+	message, ok := runner.registries.maintenance.message(botType)
+	if !ok || message != "Upgrading." {
+		t.Errorf("Unexpected maintenance message is set: %s.", message)
+	}
+
+	runner.ExitMaintenance(botType)
+	if _, ok := runner.registries.maintenance.message(botType); ok {
+		t.Error("Runner is still in maintenance.")
+	}
+}
+
+func TestRunner_TriggerTask(t *testing.T) {
+	var botType BotType = "isolated"
+	executed := false
+	task := &DummyScheduledTask{
+		IdentifierValue: "report",
+		ExecuteFunc: func(_ context.Context) ([]*ScheduledTaskResult, error) {
+			executed = true
+			return nil, nil
+		},
+	}
+
+	runner := New()
+	runner.registries.taskTriggers.set(context.Background(), &DummyBot{BotTypeValue: botType}, task)
+
+	err := TriggerTask(context.TODO(), botType, "report", nil)
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Task of another Runner should not be found: %#v.", err)
+	}
+
+	err = runner.TriggerTask(context.TODO(), botType, "report", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !executed {
+		t.Error("Task is not executed.")
+	}
+}
+
+func TestRunner_InputQueueSnapshot(t *testing.T) {
+	runner := New()
+	runner.registries.inputQueue.add("isolated", "isolated", &DummyInput{})
+
+	snapshot := runner.InputQueueSnapshot()
+	if len(snapshot) != 1 || snapshot[0].CorrelationID != "isolated" {
+		t.Errorf("Unexpected snapshot is returned: %#v.", snapshot)
+	}
+
+	for _, queued := range InputQueueSnapshot() {
+		if queued.CorrelationID == "isolated" {
+			t.Error("Input of another Runner should not be returned.")
+		}
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	SetupAndRun(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		started := make(chan BotType, 2)
+		newBot := func(botType BotType) Bot {
+			return &DummyBot{
+				BotTypeValue:      botType,
+				AppendCommandFunc: func(_ Command) {},
+				RunFunc: func(ctx context.Context, _ func(Input) error, _ func(error)) {
+					started <- botType
+					<-ctx.Done()
+				},
+			}
+		}
+
+		first := New()
+		first.RegisterBot(newBot("first"))
+		second := New()
+		second.RegisterBot(newBot("second"))
+
+		config := NewConfig()
+		config.TimeZone = time.UTC.String()
+		c := &DummyClock{NowFunc: time.Now}
+		withClock(c, func() {
+			for _, runner := range []*Runner{first, second} {
+				err := runner.Run(ctx, config)
+				if err != nil {
+					t.Fatalf("Unexpected error is returned: %s.", err.Error())
+				}
+			}
+
+			if currentClock() != c {
+				t.Error("Runner should not replace the process-wide Clock.")
+			}
+		})
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-started:
+				// O.K.
+			case <-time.NewTimer(10 * time.Second).C:
+				t.Fatal("Bot is not started.")
+			}
+		}
+
+		for _, runner := range []*Runner{first, second} {
+			status := runner.Status()
+			if len(status.Bots) != 1 {
+				t.Errorf("Bots of another Runner should not be included: %#v.", status.Bots)
+			}
+		}
+		if len(CurrentStatus().Bots) != 0 {
+			t.Error("Default Runner should not be affected.")
+		}
+
+		err := first.Run(ctx, config)
+		if !errors.Is(err, ErrRunnerAlreadyRunning) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func TestRunner_RegisterBot_AfterRun(t *testing.T) {
+	SetupAndRun(func() {
+		runner := New()
+		runner.options.seal()
+
+		// The default Runner is not sealed.
+		RegisterBot(&DummyBot{BotTypeValue: "dummy"})
+
+		defer func() {
+			recovered := recover()
+			if recovered != ErrRegistrationAfterRun {
+				t.Errorf("Expected panic is not given: %#v.", recovered)
+			}
+		}()
+		runner.RegisterBot(&DummyBot{BotTypeValue: "dummy"})
+	})
+}
@@ -0,0 +1,355 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrJobsUnavailable is returned by SubmitJob, FindJob, and CancelJob when the given context is not the one passed to Command's or ScheduledTask's execution.
+	ErrJobsUnavailable = errors.New("jobs are not available in the given context")
+
+	// ErrJobNotFound is returned by FindJob and CancelJob when no job with the given identifier is found.
+	ErrJobNotFound = errors.New("job is not found")
+
+	// ErrMessageEditUnsupported is returned when the Bot can not edit a message that is already sent.
+	ErrMessageEditUnsupported = errors.New("message edit is not supported")
+)
+
+// maxFinishedJobs is the number of finished jobs to keep for each Bot so their results can still be looked up via FindJob.
+const maxFinishedJobs = 100
+
+// jobSequence is used to give each job a unique identifier.
+var jobSequence uint64
+
+// MessageEditor defines an interface that an Adapter implementation may satisfy to update a message that is already sent.
+// When an Adapter passed to NewBot satisfies this, the progress of a job submitted via SubmitJob is pushed by editing one message.
+type MessageEditor interface {
+	// SendEditableMessage sends the given Output and returns the identifier of the sent message so the message can be edited later.
+	SendEditableMessage(ctx context.Context, output Output) (string, error)
+
+	// EditMessage replaces the content of the message with the given identifier at the given destination.
+	EditMessage(ctx context.Context, destination OutputDestination, messageID string, content interface{}) error
+}
+
+// JobState represents the state of a job.
+type JobState string
+
+const (
+	// JobRunning indicates that the job is still running.
+	JobRunning JobState = "running"
+
+	// JobSucceeded indicates that the job finished without an error.
+	JobSucceeded JobState = "succeeded"
+
+	// JobFailed indicates that the job returned an error.
+	JobFailed JobState = "failed"
+
+	// JobCanceled indicates that the job is canceled via CancelJob or by the Bot's stop.
+	JobCanceled JobState = "canceled"
+)
+
+// JobFunc defines a function's signature that a long-running job must satisfy.
+// The function should return when the given context is canceled, and may call progress to report how far the job proceeds.
+type JobFunc func(ctx context.Context, progress func(string)) error
+
+// Job represents a snapshot of a long-running job submitted via SubmitJob.
+type Job struct {
+	// ID is the unique identifier of the job. Pass this to FindJob and CancelJob.
+	ID string
+
+	// Name describes the job. e.g. "deploy api to production"
+	Name string
+
+	// SenderKey is the user who submitted the job.
+	SenderKey string
+
+	// State is the current state of the job.
+	State JobState
+
+	// Progress is the latest progress that the job reported.
+	Progress string
+
+	// Error is the reason of the failure. This is empty unless State is JobFailed.
+	Error string
+
+	// StartedAt is the time when the job started.
+	StartedAt time.Time
+
+	// FinishedAt is the time when the job finished. This is zero while the job is running.
+	FinishedAt time.Time
+}
+
+// Finished tells if the job is no longer running.
+func (j *Job) Finished() bool {
+	return j.State != JobRunning
+}
+
+// SubmitJob starts the given function as a long-running job in a new goroutine and returns immediately.
+// The given context must be the one passed to Command's execution, and the given Input is the one that triggered the job.
+// Return the identifier of the returned Job to the user so the user can look up or cancel the job later via FindJob and CancelJob.
+//
+// The job runs with the Bot's context instead of the Command's one, so the job keeps running after the Command returns and is canceled when the Bot stops.
+// When the Bot's Adapter satisfies MessageEditor, a message is sent to Input.ReplyTo and is edited to push each progress and the result.
+// Otherwise, only the result is sent when the job finishes.
+//
+//	job, err := sarah.SubmitJob(ctx, input, "export users", func(ctx context.Context, progress func(string)) error {
+//		for i, chunk := range chunks {
+//			progress(fmt.Sprintf("%d/%d exported", i+1, len(chunks)))
+//			if err := export(ctx, chunk); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	})
+func SubmitJob(ctx context.Context, input Input, name string, fnc JobFunc) (*Job, error) {
+	j := jobsFromContext(ctx)
+	if j == nil || j.registry == nil {
+		return nil, ErrJobsUnavailable
+	}
+
+	return j.submit(input, name, fnc), nil
+}
+
+// FindJob returns the current snapshot of the job with the given identifier.
+// The given context must be the one passed to Command's or ScheduledTask's execution.
+func FindJob(ctx context.Context, id string) (*Job, error) {
+	j := jobsFromContext(ctx)
+	if j == nil || j.registry == nil {
+		return nil, ErrJobsUnavailable
+	}
+
+	job, ok := j.registry.find(j.bot.BotType(), id)
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job.snapshot(), nil
+}
+
+// CancelJob cancels the running job with the given identifier.
+// The given context must be the one passed to Command's or ScheduledTask's execution.
+// This does nothing when the job is already finished.
+func CancelJob(ctx context.Context, id string) error {
+	j := jobsFromContext(ctx)
+	if j == nil || j.registry == nil {
+		return ErrJobsUnavailable
+	}
+
+	job, ok := j.registry.find(j.bot.BotType(), id)
+	if !ok {
+		return ErrJobNotFound
+	}
+	job.cancel()
+	return nil
+}
+
+// jobs holds what SubmitJob requires to run a job for a Bot.
+type jobs struct {
+	ctx      context.Context
+	bot      Bot
+	registry *jobRegistry
+}
+
+type jobsKey struct{}
+
+func withJobs(ctx context.Context, j *jobs) context.Context {
+	return context.WithValue(ctx, jobsKey{}, j)
+}
+
+func jobsFromContext(ctx context.Context) *jobs {
+	j, _ := ctx.Value(jobsKey{}).(*jobs)
+	return j
+}
+
+func (j *jobs) submit(input Input, name string, fnc JobFunc) *Job {
+	ctx, cancel := context.WithCancel(j.ctx)
+	job := &runningJob{
+		job: &Job{
+			ID:        strconv.FormatUint(atomic.AddUint64(&jobSequence, 1), 10),
+			Name:      name,
+			SenderKey: input.SenderKey(),
+			State:     JobRunning,
+			StartedAt: currentClock().Now(),
+		},
+		cancel: cancel,
+	}
+	j.registry.add(j.bot.BotType(), job)
+
+	reporter := j.newReporter(input.ReplyTo(), job)
+
+	go func() {
+		defer cancel()
+
+		err := func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("job panicked: %+v", r)
+				}
+			}()
+			return fnc(ctx, func(progress string) {
+				job.setProgress(progress)
+				reporter(fmt.Sprintf("Job %s (%s): %s", job.job.ID, name, progress))
+			})
+		}()
+
+		snapshot := job.finish(ctx, err)
+		j.registry.trim(j.bot.BotType())
+		logger.Infof("Job %s (%s) finished: %s", snapshot.ID, name, snapshot.State)
+
+		result := fmt.Sprintf("Job %s (%s) %s.", snapshot.ID, name, snapshot.State)
+		if snapshot.Error != "" {
+			result = fmt.Sprintf("Job %s (%s) %s: %s", snapshot.ID, name, snapshot.State, snapshot.Error)
+		}
+		reporter(result)
+	}()
+
+	return job.snapshot()
+}
+
+// newReporter returns a function that pushes the given message to the user.
+// When the Bot can edit a message, one message is sent and edited afterwards; Otherwise, only the final result is sent.
+func (j *jobs) newReporter(destination OutputDestination, job *runningJob) func(string) {
+	editor, ok := j.bot.(MessageEditor)
+	var messageID string
+	if ok {
+		var err error
+		messageID, err = editor.SendEditableMessage(j.ctx, NewOutputMessage(destination, fmt.Sprintf("Job %s (%s) started.", job.job.ID, job.job.Name)))
+		if err != nil {
+			if !errors.Is(err, ErrMessageEditUnsupported) {
+				logger.Warnf("Failed to send the progress message of job %s: %+v", job.job.ID, err)
+			}
+			ok = false
+		}
+	}
+
+	var mutex sync.Mutex
+	return func(message string) {
+		if ok {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			err := editor.EditMessage(j.ctx, destination, messageID, message)
+			if err != nil {
+				logger.Warnf("Failed to edit the progress message of job %s: %+v", job.job.ID, err)
+			}
+			return
+		}
+
+		if job.snapshot().Finished() {
+			j.bot.SendMessage(j.ctx, NewOutputMessage(destination, message))
+		}
+	}
+}
+
+type runningJob struct {
+	job    *Job
+	cancel context.CancelFunc
+	mutex  sync.RWMutex
+}
+
+func (j *runningJob) snapshot() *Job {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	job := *j.job
+	return &job
+}
+
+func (j *runningJob) setProgress(progress string) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.job.Progress = progress
+}
+
+func (j *runningJob) finish(ctx context.Context, err error) *Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	switch {
+	case ctx.Err() != nil:
+		j.job.State = JobCanceled
+
+	case err != nil:
+		j.job.State = JobFailed
+		j.job.Error = err.Error()
+
+	default:
+		j.job.State = JobSucceeded
+
+	}
+	j.job.FinishedAt = currentClock().Now()
+
+	job := *j.job
+	return &job
+}
+
+// jobRegistry holds the jobs of each Bot so they can be looked up across the Commands' executions and the Bots' restarts.
+type jobRegistry struct {
+	jobs  map[BotType][]*runningJob
+	mutex sync.RWMutex
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{
+		jobs: make(map[BotType][]*runningJob),
+	}
+}
+
+func (r *jobRegistry) add(botType BotType, job *runningJob) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.jobs[botType] = append(r.jobs[botType], job)
+}
+
+func (r *jobRegistry) find(botType BotType, id string) (*runningJob, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, job := range r.jobs[botType] {
+		if job.snapshot().ID == id {
+			return job, true
+		}
+	}
+	return nil, false
+}
+
+// trim discards the oldest finished jobs beyond maxFinishedJobs.
+func (r *jobRegistry) trim(botType BotType) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var finished []*Job
+	for _, job := range r.jobs[botType] {
+		if snapshot := job.snapshot(); snapshot.Finished() {
+			finished = append(finished, snapshot)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(finished[j].FinishedAt)
+	})
+	discarded := make(map[string]struct{})
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		discarded[job.ID] = struct{}{}
+	}
+
+	var remains []*runningJob
+	for _, job := range r.jobs[botType] {
+		if _, ok := discarded[job.snapshot().ID]; !ok {
+			remains = append(remains, job)
+		}
+	}
+	r.jobs[botType] = remains
+}
@@ -0,0 +1,246 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type DummyMessageEditAdapter struct {
+	DummyAdapter
+	SendEditableMessageFunc func(context.Context, Output) (string, error)
+	EditMessageFunc         func(context.Context, OutputDestination, string, interface{}) error
+}
+
+func (adapter *DummyMessageEditAdapter) SendEditableMessage(ctx context.Context, output Output) (string, error) {
+	return adapter.SendEditableMessageFunc(ctx, output)
+}
+
+func (adapter *DummyMessageEditAdapter) EditMessage(ctx context.Context, destination OutputDestination, messageID string, content interface{}) error {
+	return adapter.EditMessageFunc(ctx, destination, messageID, content)
+}
+
+func TestDefaultBot_EditMessage(t *testing.T) {
+	bot := NewBot(&DummyAdapter{}).(*defaultBot)
+	_, err := bot.SendEditableMessage(context.TODO(), NewOutputMessage("dest", "content"))
+	if !errors.Is(err, ErrMessageEditUnsupported) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+	err = bot.EditMessage(context.TODO(), "dest", "id", "content")
+	if !errors.Is(err, ErrMessageEditUnsupported) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	var edited interface{}
+	adapter := &DummyMessageEditAdapter{
+		SendEditableMessageFunc: func(_ context.Context, _ Output) (string, error) {
+			return "id", nil
+		},
+		EditMessageFunc: func(_ context.Context, _ OutputDestination, messageID string, content interface{}) error {
+			if messageID != "id" {
+				t.Errorf("Unexpected message ID is given: %s.", messageID)
+			}
+			edited = content
+			return nil
+		},
+	}
+	bot = NewBot(adapter).(*defaultBot)
+	id, err := bot.SendEditableMessage(context.TODO(), NewOutputMessage("dest", "content"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	err = bot.EditMessage(context.TODO(), "dest", id, "edited")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if edited != "edited" {
+		t.Errorf("Unexpected content is given: %#v.", edited)
+	}
+}
+
+func TestSubmitJob_Unavailable(t *testing.T) {
+	_, err := SubmitJob(context.TODO(), &DummyInput{}, "dummy", func(_ context.Context, _ func(string)) error {
+		return nil
+	})
+	if !errors.Is(err, ErrJobsUnavailable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	_, err = FindJob(context.TODO(), "1")
+	if !errors.Is(err, ErrJobsUnavailable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	err = CancelJob(context.TODO(), "1")
+	if !errors.Is(err, ErrJobsUnavailable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestSubmitJob(t *testing.T) {
+	testSets := []struct {
+		err      error
+		expected JobState
+	}{
+		{
+			err:      nil,
+			expected: JobSucceeded,
+		},
+		{
+			err:      errors.New("boom"),
+			expected: JobFailed,
+		},
+	}
+
+	for i, tt := range testSets {
+		sent := make(chan Output, 1)
+		bot := NewBot(&DummyAdapter{
+			BotTypeValue: "dummy",
+			SendMessageFunc: func(_ context.Context, output Output) {
+				sent <- output
+			},
+		})
+		ctx := withJobs(context.TODO(), &jobs{
+			ctx:      context.TODO(),
+			bot:      bot,
+			registry: newJobRegistry(),
+		})
+
+		job, err := SubmitJob(ctx, &DummyInput{SenderKeyValue: "user", ReplyToValue: "channel"}, "export", func(_ context.Context, progress func(string)) error {
+			progress("half")
+			return tt.err
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if job.ID == "" || job.Name != "export" || job.SenderKey != "user" {
+			t.Errorf("Unexpected job is returned on test #%d: %#v.", i+1, job)
+		}
+
+		// Only the result is sent because the Adapter can not edit a message.
+		select {
+		case output := <-sent:
+			if output.Destination() != "channel" {
+				t.Errorf("Unexpected destination is given on test #%d: %#v.", i+1, output.Destination())
+			}
+			if !strings.Contains(fmt.Sprint(output.Content()), string(tt.expected)) {
+				t.Errorf("Unexpected content is sent on test #%d: %#v.", i+1, output.Content())
+			}
+		case <-time.NewTimer(10 * time.Second).C:
+			t.Fatalf("Result is not sent on test #%d.", i+1)
+		}
+
+		found, err := FindJob(ctx, job.ID)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if found.State != tt.expected || found.Progress != "half" || found.FinishedAt.IsZero() {
+			t.Errorf("Unexpected job is found on test #%d: %#v.", i+1, found)
+		}
+		if tt.err != nil && found.Error != tt.err.Error() {
+			t.Errorf("Error is not recorded on test #%d: %s.", i+1, found.Error)
+		}
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	var mutex sync.Mutex
+	var edits []string
+	edited := make(chan struct{}, 10)
+	adapter := &DummyMessageEditAdapter{
+		DummyAdapter: DummyAdapter{
+			BotTypeValue: "dummy",
+		},
+		SendEditableMessageFunc: func(_ context.Context, output Output) (string, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			edits = append(edits, fmt.Sprint(output.Content()))
+			return "id", nil
+		},
+		EditMessageFunc: func(_ context.Context, _ OutputDestination, _ string, content interface{}) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			edits = append(edits, fmt.Sprint(content))
+			edited <- struct{}{}
+			return nil
+		},
+	}
+	ctx := withJobs(context.TODO(), &jobs{
+		ctx:      context.TODO(),
+		bot:      NewBot(adapter),
+		registry: newJobRegistry(),
+	})
+
+	job, err := SubmitJob(ctx, &DummyInput{ReplyToValue: "channel"}, "deploy", func(ctx context.Context, progress func(string)) error {
+		progress("waiting")
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	// Wait for the progress to be pushed.
+	<-edited
+
+	err = CancelJob(ctx, "unknown")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	err = CancelJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	// Wait for the result to be pushed.
+	select {
+	case <-edited:
+		// O.K.
+	case <-time.NewTimer(10 * time.Second).C:
+		t.Fatal("Result is not pushed.")
+	}
+
+	found, err := FindJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if found.State != JobCanceled {
+		t.Errorf("Unexpected state: %s.", found.State)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(edits) != 3 || !strings.HasSuffix(edits[1], "waiting") || !strings.HasSuffix(edits[2], "canceled.") {
+		t.Errorf("Unexpected edits: %#v.", edits)
+	}
+}
+
+func Test_jobRegistry_trim(t *testing.T) {
+	registry := newJobRegistry()
+	now := time.Now()
+	registry.add("dummy", &runningJob{job: &Job{ID: "running", State: JobRunning}})
+	for i := 0; i < maxFinishedJobs+1; i++ {
+		registry.add("dummy", &runningJob{job: &Job{
+			ID:         fmt.Sprintf("%d", i),
+			State:      JobSucceeded,
+			FinishedAt: now.Add(time.Duration(i) * time.Second),
+		}})
+	}
+
+	registry.trim("dummy")
+
+	if _, ok := registry.find("dummy", "0"); ok {
+		t.Error("The oldest finished job should be discarded.")
+	}
+	if _, ok := registry.find("dummy", "1"); !ok {
+		t.Error("Newer finished job should be kept.")
+	}
+	if _, ok := registry.find("dummy", "running"); !ok {
+		t.Error("Running job should be kept.")
+	}
+}
@@ -0,0 +1,271 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"time"
+)
+
+const (
+	// KAFKA is a dedicated sarah.BotType for Kafka integration.
+	KAFKA sarah.BotType = "kafka"
+)
+
+// ErrNoOutputTopic is returned when neither the output's destination nor Config.OutputTopic tells where to publish.
+var ErrNoOutputTopic = errors.New("output topic is not given")
+
+// Message represents a Kafka message.
+type Message struct {
+	// Topic is the topic the message belongs to.
+	Topic string
+
+	// Partition is the partition the message belongs to. This is ignored on publication.
+	Partition int
+
+	// Offset is the offset of the message in the partition. This is ignored on publication.
+	Offset int64
+
+	// Key is the message key.
+	Key []byte
+
+	// Value is the message payload.
+	Value []byte
+
+	// Headers holds the message headers.
+	Headers map[string][]byte
+
+	// Timestamp is the time when the message was produced.
+	Timestamp time.Time
+}
+
+// Consumer defines an interface of a Kafka consumer that Adapter depends on.
+type Consumer interface {
+	// Fetch blocks until a message arrives or the given context is canceled.
+	Fetch(ctx context.Context) (*Message, error)
+
+	// Commit commits the offset of the given message.
+	Commit(ctx context.Context, message *Message) error
+}
+
+// Producer defines an interface of a Kafka producer that Adapter depends on.
+type Producer interface {
+	// Produce publishes the given message.
+	Produce(ctx context.Context, message *Message) error
+}
+
+// Destination represents where to publish an output.
+type Destination struct {
+	// Topic is the topic to publish to. When this is empty, Config.OutputTopic is used.
+	Topic string
+
+	// Key is the message key to publish with.
+	Key []byte
+}
+
+var _ sarah.OutputDestination = (*Destination)(nil)
+
+// Input is a sarah.Input implementation that represents a consumed Kafka message.
+type Input struct {
+	message     *Message
+	outputTopic string
+}
+
+var _ sarah.Input = (*Input)(nil)
+
+// SenderKey returns the combination of the topic and the message key
+// so the messages with the same key share the same conversational context.
+func (i *Input) SenderKey() string {
+	return fmt.Sprintf("%s|%s", i.message.Topic, i.message.Key)
+}
+
+// Message returns the message payload as a string.
+func (i *Input) Message() string {
+	return string(i.message.Value)
+}
+
+// SentAt returns the timestamp of the message.
+func (i *Input) SentAt() time.Time {
+	return i.message.Timestamp
+}
+
+// ReplyTo returns *Destination that publishes to Config.OutputTopic with the same message key.
+func (i *Input) ReplyTo() sarah.OutputDestination {
+	return &Destination{
+		Topic: i.outputTopic,
+		Key:   i.message.Key,
+	}
+}
+
+// Payload returns the consumed Kafka message.
+// Use this in a Command to refer to the headers or the raw payload.
+func (i *Input) Payload() *Message {
+	return i.message
+}
+
+// Adapter is a sarah.Adapter implementation for Kafka.
+type Adapter struct {
+	config   *Config
+	consumer Consumer
+	producer Producer
+}
+
+var _ sarah.Adapter = (*Adapter)(nil)
+
+// NewAdapter creates and returns a new Adapter instance.
+// The producer can be nil when the bot only consumes messages; outputs are then dropped with error logs.
+func NewAdapter(config *Config, consumer Consumer, producer Producer) (*Adapter, error) {
+	if consumer == nil {
+		return nil, errors.New("consumer is not given")
+	}
+
+	return &Adapter{
+		config:   config,
+		consumer: consumer,
+		producer: producer,
+	}, nil
+}
+
+// BotType returns the sarah.BotType declared by Config.BotType or KAFKA by default.
+func (adapter *Adapter) BotType() sarah.BotType {
+	if adapter.config.BotType == "" {
+		return KAFKA
+	}
+	return adapter.config.BotType
+}
+
+// Run consumes messages until the given context is canceled.
+// Each message is committed after Sarah accepts it as sarah.Input.
+func (adapter *Adapter) Run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
+	for {
+		message, err := adapter.consumer.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Errorf("Failed to fetch a message: %+v", err)
+			notifyErr(sarah.NewTransientError(err))
+			if !wait(ctx, adapter.config.FetchRetryInterval) {
+				return
+			}
+			continue
+		}
+
+		input := &Input{
+			message:     message,
+			outputTopic: adapter.config.OutputTopic,
+		}
+		if !adapter.enqueue(ctx, input, enqueueInput) {
+			return
+		}
+
+		err = adapter.consumer.Commit(ctx, message)
+		if err != nil {
+			logger.Errorf("Failed to commit a message. Topic: %s. Partition: %d. Offset: %d. Error: %+v", message.Topic, message.Partition, message.Offset, err)
+			notifyErr(sarah.NewTransientError(err))
+		}
+	}
+}
+
+// enqueue passes the given input to Sarah. This retries until Sarah accepts the input or the given context is canceled.
+func (adapter *Adapter) enqueue(ctx context.Context, input *Input, enqueueInput func(sarah.Input) error) bool {
+	for {
+		err := enqueueInput(input)
+		if err == nil {
+			return true
+		}
+
+		logger.Warnf("Failed to pass a message to Sarah. Retrying: %+v", err)
+		if !wait(ctx, adapter.config.EnqueueRetryInterval) {
+			return false
+		}
+	}
+}
+
+// SendMessage publishes the given output.
+// The content can be *Message for full control, []byte, string, or any value that can be encoded to JSON.
+// The destination can be *Destination or a topic name as a string.
+func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
+	message, err := adapter.toMessage(output)
+	if err != nil {
+		logger.Errorf("Failed to build a message from %#v: %+v", output, err)
+		return
+	}
+
+	if adapter.producer == nil {
+		logger.Errorf("Producer is not given. Dropping a message to %s.", message.Topic)
+		return
+	}
+
+	err = adapter.producer.Produce(ctx, message)
+	if err != nil {
+		logger.Errorf("Failed to publish a message to %s: %+v", message.Topic, err)
+	}
+}
+
+func (adapter *Adapter) toMessage(output sarah.Output) (*Message, error) {
+	message := &Message{}
+	switch content := output.Content().(type) {
+	case *Message:
+		copied := *content
+		message = &copied
+
+	case []byte:
+		message.Value = content
+
+	case string:
+		message.Value = []byte(content)
+
+	default:
+		b, err := json.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode content: %w", err)
+		}
+		message.Value = b
+
+	}
+
+	switch dest := output.Destination().(type) {
+	case *Destination:
+		if message.Topic == "" {
+			message.Topic = dest.Topic
+		}
+		if message.Key == nil {
+			message.Key = dest.Key
+		}
+
+	case string:
+		if message.Topic == "" {
+			message.Topic = dest
+		}
+
+	}
+
+	if message.Topic == "" {
+		message.Topic = adapter.config.OutputTopic
+	}
+	if message.Topic == "" {
+		return nil, ErrNoOutputTopic
+	}
+
+	return message, nil
+}
+
+// wait blocks for the given duration and returns false when the given context is canceled in the meantime.
+func wait(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+
+	case <-timer.C:
+		return true
+
+	}
+}
@@ -0,0 +1,254 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+type DummyConsumer struct {
+	FetchFunc  func(context.Context) (*Message, error)
+	CommitFunc func(context.Context, *Message) error
+}
+
+var _ Consumer = (*DummyConsumer)(nil)
+
+func (c *DummyConsumer) Fetch(ctx context.Context) (*Message, error) {
+	return c.FetchFunc(ctx)
+}
+
+func (c *DummyConsumer) Commit(ctx context.Context, message *Message) error {
+	return c.CommitFunc(ctx, message)
+}
+
+type DummyProducer struct {
+	ProduceFunc func(context.Context, *Message) error
+}
+
+var _ Producer = (*DummyProducer)(nil)
+
+func (p *DummyProducer) Produce(ctx context.Context, message *Message) error {
+	return p.ProduceFunc(ctx, message)
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+
+	if config.BotType != KAFKA {
+		t.Errorf("Unexpected BotType is set: %s.", config.BotType)
+	}
+
+	if config.FetchRetryInterval <= 0 || config.EnqueueRetryInterval <= 0 {
+		t.Errorf("Retry intervals must be set: %#v.", config)
+	}
+}
+
+func TestNewAdapter(t *testing.T) {
+	config := NewConfig()
+	consumer := &DummyConsumer{}
+	producer := &DummyProducer{}
+
+	adapter, err := NewAdapter(config, consumer, producer)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if adapter.config != config || adapter.consumer != consumer || adapter.producer != producer {
+		t.Errorf("Given values are not set: %#v.", adapter)
+	}
+
+	_, err = NewAdapter(config, nil, producer)
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestAdapter_BotType(t *testing.T) {
+	adapter := &Adapter{config: &Config{}}
+	if adapter.BotType() != KAFKA {
+		t.Errorf("Unexpected BotType is returned: %s.", adapter.BotType())
+	}
+
+	adapter = &Adapter{config: &Config{BotType: "orders"}}
+	if adapter.BotType() != "orders" {
+		t.Errorf("Unexpected BotType is returned: %s.", adapter.BotType())
+	}
+}
+
+func TestAdapter_Run(t *testing.T) {
+	timestamp := time.Now()
+	messages := []*Message{
+		{Topic: "orders", Key: []byte("order-1"), Value: []byte(".process 1"), Timestamp: timestamp},
+		{Topic: "orders", Key: []byte("order-2"), Value: []byte(".process 2"), Timestamp: timestamp},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fetched := 0
+	var committed []*Message
+	consumer := &DummyConsumer{
+		FetchFunc: func(ctx context.Context) (*Message, error) {
+			fetched++
+			if fetched == 1 {
+				return nil, errors.New("temporary failure")
+			}
+			if fetched-2 < len(messages) {
+				return messages[fetched-2], nil
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		CommitFunc: func(_ context.Context, message *Message) error {
+			committed = append(committed, message)
+			if len(committed) == len(messages) {
+				cancel()
+			}
+			return nil
+		},
+	}
+
+	config := &Config{
+		OutputTopic:          "results",
+		FetchRetryInterval:   time.Millisecond,
+		EnqueueRetryInterval: time.Millisecond,
+	}
+	adapter, _ := NewAdapter(config, consumer, nil)
+
+	rejected := false
+	var inputs []sarah.Input
+	enqueueInput := func(input sarah.Input) error {
+		if !rejected {
+			// Sarah is too busy for the first time.
+			rejected = true
+			return errors.New("busy")
+		}
+		inputs = append(inputs, input)
+		return nil
+	}
+
+	var errs []error
+	notifyErr := func(err error) {
+		errs = append(errs, err)
+	}
+
+	adapter.Run(ctx, enqueueInput, notifyErr)
+
+	if len(errs) != 1 {
+		t.Errorf("Fetch failure is not notified: %#v.", errs)
+	}
+
+	if len(inputs) != len(messages) {
+		t.Fatalf("Unexpected number of inputs are passed: %d.", len(inputs))
+	}
+
+	if len(committed) != len(messages) {
+		t.Errorf("Unexpected number of messages are committed: %d.", len(committed))
+	}
+
+	input := inputs[0].(*Input)
+	if input.Message() != ".process 1" {
+		t.Errorf("Unexpected message is returned: %s.", input.Message())
+	}
+
+	if input.SenderKey() != "orders|order-1" {
+		t.Errorf("Unexpected sender key is returned: %s.", input.SenderKey())
+	}
+
+	if !input.SentAt().Equal(timestamp) {
+		t.Errorf("Unexpected timestamp is returned: %s.", input.SentAt())
+	}
+
+	if input.Payload() != messages[0] {
+		t.Errorf("Unexpected payload is returned: %#v.", input.Payload())
+	}
+
+	dest, ok := input.ReplyTo().(*Destination)
+	if !ok {
+		t.Fatalf("Unexpected destination is returned: %#v.", input.ReplyTo())
+	}
+
+	if dest.Topic != "results" || string(dest.Key) != "order-1" {
+		t.Errorf("Unexpected destination is returned: %#v.", dest)
+	}
+}
+
+func TestAdapter_SendMessage(t *testing.T) {
+	testSets := []struct {
+		output sarah.Output
+		topic  string
+		key    string
+		value  string
+	}{
+		{
+			output: sarah.NewOutputMessage(&Destination{Key: []byte("order-1")}, "done"),
+			topic:  "results",
+			key:    "order-1",
+			value:  "done",
+		},
+		{
+			output: sarah.NewOutputMessage("audit", []byte("raw")),
+			topic:  "audit",
+			value:  "raw",
+		},
+		{
+			output: sarah.NewOutputMessage(nil, map[string]int{"count": 1}),
+			topic:  "results",
+			value:  `{"count":1}`,
+		},
+		{
+			output: sarah.NewOutputMessage(&Destination{Topic: "ignored"}, &Message{Topic: "custom", Key: []byte("key"), Value: []byte("value")}),
+			topic:  "custom",
+			key:    "key",
+			value:  "value",
+		},
+	}
+
+	for i, testSet := range testSets {
+		var produced *Message
+		producer := &DummyProducer{
+			ProduceFunc: func(_ context.Context, message *Message) error {
+				produced = message
+				return nil
+			},
+		}
+		adapter := &Adapter{
+			config:   &Config{OutputTopic: "results"},
+			producer: producer,
+		}
+
+		adapter.SendMessage(context.TODO(), testSet.output)
+
+		if produced == nil {
+			t.Fatalf("Message is not produced on test #%d.", i+1)
+		}
+
+		if produced.Topic != testSet.topic {
+			t.Errorf("Unexpected topic is set on test #%d: %s.", i+1, produced.Topic)
+		}
+
+		if string(produced.Key) != testSet.key {
+			t.Errorf("Unexpected key is set on test #%d: %s.", i+1, produced.Key)
+		}
+
+		if string(produced.Value) != testSet.value {
+			t.Errorf("Unexpected value is set on test #%d: %s.", i+1, produced.Value)
+		}
+	}
+}
+
+func TestAdapter_SendMessage_WithoutTopic(t *testing.T) {
+	adapter := &Adapter{
+		config: &Config{},
+		producer: &DummyProducer{
+			ProduceFunc: func(_ context.Context, _ *Message) error {
+				t.Error("Message should not be produced without a topic.")
+				return nil
+			},
+		},
+	}
+
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(nil, "foo"))
+}
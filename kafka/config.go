@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+	"time"
+)
+
+// Config contains some configuration variables for Kafka Adapter.
+type Config struct {
+	// BotType declares the sarah.BotType of the Adapter.
+	// Set a distinct value for each Adapter when multiple pipelines run in the same process.
+	BotType sarah.BotType `json:"bot_type" yaml:"bot_type"`
+
+	// OutputTopic declares the default topic to publish outputs to.
+	// This is used when an output's destination does not specify a topic.
+	OutputTopic string `json:"output_topic" yaml:"output_topic"`
+
+	// FetchRetryInterval declares the interval before fetching again after a failed fetch.
+	FetchRetryInterval time.Duration `json:"fetch_retry_interval" yaml:"fetch_retry_interval"`
+
+	// EnqueueRetryInterval declares the interval before passing the same message to Sarah again when Sarah is too busy to accept it.
+	// Unlike a chat message, an event must not be dropped, so the consumption pauses until Sarah accepts the message.
+	EnqueueRetryInterval time.Duration `json:"enqueue_retry_interval" yaml:"enqueue_retry_interval"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// OutputTopic is empty at this point as there can not be a default value.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank value or override those default values.
+func NewConfig() *Config {
+	return &Config{
+		BotType:              KAFKA,
+		OutputTopic:          "",
+		FetchRetryInterval:   1 * time.Second,
+		EnqueueRetryInterval: 100 * time.Millisecond,
+	}
+}
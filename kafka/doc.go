@@ -0,0 +1,26 @@
+// Package kafka provides a sarah.Adapter implementation that consumes messages from Kafka topics as sarah.Input
+// and publishes the responses of Commands and the results of ScheduledTasks to Kafka topics.
+// This lets go-sarah's command and scheduling framework be reused for internal event processing pipelines.
+//
+// To avoid binding this project to a particular Kafka client library, this package depends on Consumer and Producer interfaces.
+// Implement them with a thin wrapper of the preferred library such as kafka-go as below:
+//
+//	type consumer struct {
+//		reader *kafkago.Reader
+//	}
+//
+//	func (c *consumer) Fetch(ctx context.Context) (*kafka.Message, error) {
+//		m, err := c.reader.FetchMessage(ctx)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return &kafka.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset, Key: m.Key, Value: m.Value, Timestamp: m.Time}, nil
+//	}
+//
+//	...
+//
+//	adapter, _ := kafka.NewAdapter(kafka.NewConfig(), &consumer{reader: reader}, &producer{writer: writer})
+//	sarah.RegisterBot(sarah.NewBot(adapter))
+//
+// The same Producer can be passed to NewInputSink to record every Input that the Bots receive to a Kafka topic.
+package kafka
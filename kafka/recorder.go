@@ -0,0 +1,48 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// InputSink is a sarah.InputSink implementation that publishes each sarah.RecordedInput to a Kafka topic as a JSON message.
+// The sender key is used as the message key, so the Inputs from the same sender are kept in order within a partition.
+//
+//	sarah.RegisterInputRecorder(kafka.NewInputSink(&producer{writer: writer}, "sarah-inputs"))
+//
+// To replay, decode each consumed message value into sarah.RecordedInput with json.Unmarshal and pass it to Bot.Respond of a sandbox Bot.
+type InputSink struct {
+	producer Producer
+	topic    string
+}
+
+var _ sarah.InputSink = (*InputSink)(nil)
+
+// NewInputSink creates and returns a new InputSink that publishes to the given topic via the given Producer.
+func NewInputSink(producer Producer, topic string) *InputSink {
+	return &InputSink{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// Record publishes the given sarah.RecordedInput.
+func (s *InputSink) Record(ctx context.Context, input *sarah.RecordedInput) error {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode input: %w", err)
+	}
+
+	err = s.producer.Produce(ctx, &Message{
+		Topic:     s.topic,
+		Key:       []byte(input.SenderKey()),
+		Value:     b,
+		Timestamp: input.RecordedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish input to %s: %w", s.topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+	"time"
+)
+
+func TestNewInputSink(t *testing.T) {
+	producer := &DummyProducer{}
+	sink := NewInputSink(producer, "inputs")
+
+	if sink.producer != producer {
+		t.Error("Given Producer is not set.")
+	}
+
+	if sink.topic != "inputs" {
+		t.Errorf("Unexpected topic is set: %s.", sink.topic)
+	}
+}
+
+func TestInputSink_Record(t *testing.T) {
+	recordedAt := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+	input := &sarah.RecordedInput{
+		BotType:    "dummy",
+		Sender:     "sender",
+		Text:       ".echo foo",
+		RecordedAt: recordedAt,
+	}
+
+	var published *Message
+	producer := &DummyProducer{
+		ProduceFunc: func(_ context.Context, message *Message) error {
+			published = message
+			return nil
+		},
+	}
+
+	err := NewInputSink(producer, "inputs").Record(context.TODO(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if published.Topic != "inputs" {
+		t.Errorf("Unexpected topic is set: %s.", published.Topic)
+	}
+
+	if string(published.Key) != "sender" {
+		t.Errorf("Unexpected key is set: %s.", published.Key)
+	}
+
+	if !published.Timestamp.Equal(recordedAt) {
+		t.Errorf("Unexpected timestamp is set: %s.", published.Timestamp)
+	}
+
+	decoded := &sarah.RecordedInput{}
+	err = json.Unmarshal(published.Value, decoded)
+	if err != nil {
+		t.Fatalf("Published value can not be decoded: %s.", err.Error())
+	}
+	if decoded.BotType != "dummy" || decoded.Message() != ".echo foo" {
+		t.Errorf("Unexpected value is published: %#v.", decoded)
+	}
+}
+
+func TestInputSink_Record_Error(t *testing.T) {
+	expected := errors.New("dummy")
+	producer := &DummyProducer{
+		ProduceFunc: func(_ context.Context, _ *Message) error {
+			return expected
+		},
+	}
+
+	err := NewInputSink(producer, "inputs").Record(context.TODO(), &sarah.RecordedInput{})
+	if !errors.Is(err, expected) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
@@ -0,0 +1,196 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrKVNotFound is returned by KVStore.Get when no value is stored with the given key.
+var ErrKVNotFound = errors.New("value not found")
+
+// KVStore defines an interface of a persistent key-value storage that plugins use to store small bits of state.
+// Each value is stored under a namespace so multiple plugins can share one storage without key collisions.
+// A plugin usually does not touch KVStore directly, but uses a KVBucket that is bound to the plugin's namespace.
+//
+// Register a KVStore implementation via RegisterKVStore so each Command can retrieve its KVBucket with KVBucketFromContext.
+type KVStore interface {
+	// Get returns the value stored with the given namespace and key.
+	// ErrKVNotFound must be returned when no corresponding value is stored.
+	Get(ctx context.Context, namespace string, key string) ([]byte, error)
+
+	// Set stores the given value with the given namespace and key.
+	// An existing value is overridden.
+	Set(ctx context.Context, namespace string, key string, value []byte) error
+
+	// Delete removes the value stored with the given namespace and key.
+	// This does nothing if a corresponding value is not stored.
+	Delete(ctx context.Context, namespace string, key string) error
+
+	// List returns the keys stored under the given namespace in ascending order.
+	List(ctx context.Context, namespace string) ([]string, error)
+}
+
+// KVNamespace returns a namespace string for the given BotType and Command/ScheduledTask identifier.
+func KVNamespace(botType BotType, id string) string {
+	return fmt.Sprintf("%s:%s", botType.String(), id)
+}
+
+// KVBucket is a view of KVStore that is bound to a particular namespace.
+type KVBucket struct {
+	store     KVStore
+	namespace string
+}
+
+// NewKVBucket creates and returns a new KVBucket that is bound to the namespace of the given BotType and identifier.
+func NewKVBucket(store KVStore, botType BotType, id string) *KVBucket {
+	return &KVBucket{
+		store:     store,
+		namespace: KVNamespace(botType, id),
+	}
+}
+
+// Namespace returns the namespace this KVBucket is bound to.
+func (b *KVBucket) Namespace() string {
+	return b.namespace
+}
+
+// Get returns the value stored with the given key.
+// ErrKVNotFound is returned when no corresponding value is stored.
+func (b *KVBucket) Get(ctx context.Context, key string) ([]byte, error) {
+	return b.store.Get(ctx, b.namespace, key)
+}
+
+// Set stores the given value with the given key.
+func (b *KVBucket) Set(ctx context.Context, key string, value []byte) error {
+	return b.store.Set(ctx, b.namespace, key, value)
+}
+
+// Delete removes the value stored with the given key.
+func (b *KVBucket) Delete(ctx context.Context, key string) error {
+	return b.store.Delete(ctx, b.namespace, key)
+}
+
+// List returns the stored keys in ascending order.
+func (b *KVBucket) List(ctx context.Context) ([]string, error) {
+	return b.store.List(ctx, b.namespace)
+}
+
+type kvBucketKey struct{}
+
+// KVBucketFromContext returns a KVBucket bound to the currently executing Command.
+// This returns nil when no KVStore is registered via RegisterKVStore.
+//
+//	func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+//		bucket := sarah.KVBucketFromContext(ctx)
+//		if bucket == nil {
+//			return nil, errors.New("KVStore is not registered")
+//		}
+//		err := bucket.Set(ctx, input.SenderKey(), []byte(input.Message()))
+//		...
+//	}
+func KVBucketFromContext(ctx context.Context) *KVBucket {
+	bucket, _ := ctx.Value(kvBucketKey{}).(*KVBucket)
+	return bucket
+}
+
+func withKVBucket(ctx context.Context, bucket *KVBucket) context.Context {
+	return context.WithValue(ctx, kvBucketKey{}, bucket)
+}
+
+// kvCommand wraps a Command and provides a KVBucket to the Command's execution via context.
+type kvCommand struct {
+	Command
+	bucket *KVBucket
+}
+
+var _ wrappedCommand = (*kvCommand)(nil)
+
+func (command *kvCommand) Execute(ctx context.Context, input Input) (*CommandResponse, error) {
+	return command.Command.Execute(command.prepareContext(ctx), input)
+}
+
+// Unwrap returns the wrapped Command.
+func (command *kvCommand) Unwrap() Command {
+	return command.Command
+}
+
+// prepareContext provides the KVBucket to the wrapped Command's execution and preview.
+func (command *kvCommand) prepareContext(ctx context.Context) context.Context {
+	return withKVBucket(ctx, command.bucket)
+}
+
+// inMemoryKVStore is a KVStore implementation that stores values in the process memory space.
+// Stored values are lost when the process stops.
+type inMemoryKVStore struct {
+	values map[string]map[string][]byte
+	mutex  sync.RWMutex
+}
+
+var _ KVStore = (*inMemoryKVStore)(nil)
+
+// NewInMemoryKVStore creates and returns a new KVStore implementation that stores values in the process memory space.
+// This is handy for development and testing, but stored values are lost when the process stops.
+func NewInMemoryKVStore() KVStore {
+	return &inMemoryKVStore{
+		values: map[string]map[string][]byte{},
+	}
+}
+
+// Get returns the value stored with the given namespace and key.
+func (s *inMemoryKVStore) Get(_ context.Context, namespace string, key string) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.values[namespace][key]
+	if !ok {
+		return nil, ErrKVNotFound
+	}
+
+	// Return a copy so the caller can not modify the stored value.
+	copied := make([]byte, len(value))
+	copy(copied, value)
+	return copied, nil
+}
+
+// Set stores the given value with the given namespace and key.
+func (s *inMemoryKVStore) Set(_ context.Context, namespace string, key string, value []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.values[namespace]; !ok {
+		s.values[namespace] = map[string][]byte{}
+	}
+
+	copied := make([]byte, len(value))
+	copy(copied, value)
+	s.values[namespace][key] = copied
+	return nil
+}
+
+// Delete removes the value stored with the given namespace and key.
+func (s *inMemoryKVStore) Delete(_ context.Context, namespace string, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.values[namespace], key)
+	if len(s.values[namespace]) == 0 {
+		delete(s.values, namespace)
+	}
+	return nil
+}
+
+// List returns the keys stored under the given namespace in ascending order.
+func (s *inMemoryKVStore) List(_ context.Context, namespace string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	keys := make([]string, 0, len(s.values[namespace]))
+	for key := range s.values[namespace] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,182 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type DummyKVStore struct {
+	GetFunc    func(context.Context, string, string) ([]byte, error)
+	SetFunc    func(context.Context, string, string, []byte) error
+	DeleteFunc func(context.Context, string, string) error
+	ListFunc   func(context.Context, string) ([]string, error)
+}
+
+var _ KVStore = (*DummyKVStore)(nil)
+
+func (s *DummyKVStore) Get(ctx context.Context, namespace string, key string) ([]byte, error) {
+	return s.GetFunc(ctx, namespace, key)
+}
+
+func (s *DummyKVStore) Set(ctx context.Context, namespace string, key string, value []byte) error {
+	return s.SetFunc(ctx, namespace, key, value)
+}
+
+func (s *DummyKVStore) Delete(ctx context.Context, namespace string, key string) error {
+	return s.DeleteFunc(ctx, namespace, key)
+}
+
+func (s *DummyKVStore) List(ctx context.Context, namespace string) ([]string, error) {
+	return s.ListFunc(ctx, namespace)
+}
+
+func TestKVNamespace(t *testing.T) {
+	namespace := KVNamespace("dummy", "command")
+	if namespace != "dummy:command" {
+		t.Errorf("Unexpected namespace is returned: %s.", namespace)
+	}
+}
+
+func TestNewKVBucket(t *testing.T) {
+	store := NewInMemoryKVStore()
+	bucket := NewKVBucket(store, "dummy", "command")
+
+	if bucket.store != store {
+		t.Error("Given KVStore is not set.")
+	}
+
+	if bucket.Namespace() != "dummy:command" {
+		t.Errorf("Unexpected namespace is set: %s.", bucket.Namespace())
+	}
+}
+
+func TestKVBucket(t *testing.T) {
+	ctx := context.TODO()
+	store := NewInMemoryKVStore()
+	bucket := NewKVBucket(store, "dummy", "command")
+	other := NewKVBucket(store, "dummy", "other")
+
+	err := bucket.Set(ctx, "key", []byte("value"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	value, err := bucket.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if string(value) != "value" {
+		t.Errorf("Unexpected value is returned: %s.", value)
+	}
+
+	_, err = other.Get(ctx, "key")
+	if !errors.Is(err, ErrKVNotFound) {
+		t.Errorf("Value must not be shared between namespaces: %#v.", err)
+	}
+
+	keys, err := bucket.List(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(keys) != 1 || keys[0] != "key" {
+		t.Errorf("Unexpected keys are returned: %#v.", keys)
+	}
+
+	err = bucket.Delete(ctx, "key")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	_, err = bucket.Get(ctx, "key")
+	if !errors.Is(err, ErrKVNotFound) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestKVBucketFromContext(t *testing.T) {
+	if KVBucketFromContext(context.TODO()) != nil {
+		t.Error("Nil should be returned when KVBucket is not set.")
+	}
+
+	bucket := NewKVBucket(NewInMemoryKVStore(), "dummy", "command")
+	ctx := withKVBucket(context.TODO(), bucket)
+	if KVBucketFromContext(ctx) != bucket {
+		t.Error("Expected KVBucket is not returned.")
+	}
+}
+
+func Test_kvCommand_Execute(t *testing.T) {
+	bucket := NewKVBucket(NewInMemoryKVStore(), "dummy", "command")
+	command := &kvCommand{
+		Command: &DummyCommand{
+			ExecuteFunc: func(ctx context.Context, _ Input) (*CommandResponse, error) {
+				if KVBucketFromContext(ctx) != bucket {
+					t.Error("Expected KVBucket is not given.")
+				}
+				return nil, nil
+			},
+		},
+		bucket: bucket,
+	}
+
+	_, _ = command.Execute(context.TODO(), &DummyInput{})
+}
+
+func Test_runner_wrapCommand(t *testing.T) {
+	command := &DummyCommand{IdentifierValue: "command"}
+
+	r := &runner{}
+	if r.wrapCommand("dummy", command) != command {
+		t.Error("Command should not be wrapped when KVStore is not registered.")
+	}
+
+	r.kvStore = NewInMemoryKVStore()
+	wrapped, ok := r.wrapCommand("dummy", command).(*kvCommand)
+	if !ok {
+		t.Fatal("Command is not wrapped.")
+	}
+
+	if wrapped.Identifier() != "command" {
+		t.Errorf("Unexpected identifier is returned: %s.", wrapped.Identifier())
+	}
+
+	if wrapped.bucket.Namespace() != "dummy:command" {
+		t.Errorf("Unexpected namespace is set: %s.", wrapped.bucket.Namespace())
+	}
+}
+
+func Test_inMemoryKVStore_Get(t *testing.T) {
+	ctx := context.TODO()
+	store := NewInMemoryKVStore()
+	_ = store.Set(ctx, "ns", "key", []byte("value"))
+
+	value, _ := store.Get(ctx, "ns", "key")
+	value[0] = 'V'
+
+	stored, _ := store.Get(ctx, "ns", "key")
+	if string(stored) != "value" {
+		t.Errorf("Stored value must not be modified by the caller: %s.", stored)
+	}
+}
+
+func Test_inMemoryKVStore_List(t *testing.T) {
+	ctx := context.TODO()
+	store := NewInMemoryKVStore()
+	_ = store.Set(ctx, "ns", "b", []byte("value"))
+	_ = store.Set(ctx, "ns", "a", []byte("value"))
+
+	keys, err := store.List(ctx, "ns")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("Unexpected keys are returned: %#v.", keys)
+	}
+
+	keys, _ = store.List(ctx, "empty")
+	if len(keys) != 0 {
+		t.Errorf("Unexpected keys are returned: %#v.", keys)
+	}
+}
@@ -0,0 +1,133 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/logger"
+	"time"
+)
+
+// ErrLeadershipLost is the cause of a Bot's stop when its replica loses the leadership.
+var ErrLeadershipLost = errors.New("leadership is lost")
+
+// electionRetryInterval is the interval to retry the election when LeaderElector.Elect fails.
+var electionRetryInterval = 5 * time.Second
+
+// Leadership represents the role of the current replica for a Bot with a registered LeaderElector.
+type Leadership string
+
+const (
+	// LeadershipNone indicates that no LeaderElector is registered for the Bot, so the Bot always runs.
+	LeadershipNone Leadership = ""
+
+	// LeadershipFollower indicates that the replica is on hot standby and waits for the leadership.
+	LeadershipFollower Leadership = "follower"
+
+	// LeadershipLeader indicates that the replica is the leader and the Bot is running.
+	LeadershipLeader Leadership = "leader"
+)
+
+// LeaderElector defines an interface to elect a leader among the replicas of the same Bot.
+// Register an implementation via RegisterLeaderElector when a Bot's Adapter must be a singleton such as one with an RTM connection.
+// Implementations are typically backed by a distributed lock service with a lease such as etcd, Consul, or Redis.
+type LeaderElector interface {
+	// Elect blocks until the current replica becomes the leader or the given context is canceled.
+	// On success, this returns a channel that is closed when the leadership is lost, e.g., the lease is not renewed in time.
+	// The replica is expected to keep the leadership until Resign is called or the leadership is lost.
+	Elect(ctx context.Context) (<-chan struct{}, error)
+
+	// Resign gives up the leadership so another replica can take over immediately.
+	Resign(ctx context.Context) error
+}
+
+// RegisterLeaderElector registers a given LeaderElector for the Bot with the given BotType.
+// When one is registered, Sarah runs the Bot only while the current replica is the leader.
+// Other replicas stay on hot standby and take over when the leader replica stops or loses the leadership.
+// The current role is available as BotStatus.Leadership via CurrentStatus.
+func RegisterLeaderElector(botType BotType, elector LeaderElector) {
+	defaultRunner().RegisterLeaderElector(botType, elector)
+}
+
+// RegisterLeaderElector is the Runner counterpart of the package-level RegisterLeaderElector.
+func (rn *Runner) RegisterLeaderElector(botType BotType, elector LeaderElector) {
+	rn.options.register(func(r *runner) {
+		if r.leaderElectors == nil {
+			r.leaderElectors = make(map[BotType]LeaderElector)
+		}
+		r.leaderElectors[botType] = elector
+	})
+}
+
+// elect blocks until the current replica becomes the leader of the given Bot.
+// This returns false when the context is canceled before the election.
+func (r *runner) elect(ctx context.Context, bot Bot, elector LeaderElector) (<-chan struct{}, bool) {
+	r.status.setLeadership(bot.BotType(), LeadershipFollower)
+	for {
+		logger.Infof("Waiting for the leadership of %s", bot.BotType())
+		lost, err := elector.Elect(ctx)
+		if err == nil {
+			logger.Infof("Elected as the leader of %s", bot.BotType())
+			r.status.setLeadership(bot.BotType(), LeadershipLeader)
+			return lost, true
+		}
+
+		if ctx.Err() != nil {
+			return nil, false
+		}
+
+		logger.Errorf("Failed to elect the leader of %s: %+v", bot.BotType(), err)
+		timer := currentClock().NewTimer(electionRetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, false
+
+		case <-timer.C():
+			// Try again.
+
+		}
+	}
+}
+
+// resign gives up the leadership of the given Bot so another replica can take over.
+func resign(elector LeaderElector, botType BotType) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := elector.Resign(ctx)
+	if err != nil {
+		logger.Errorf("Failed to resign the leadership of %s: %+v", botType, err)
+	}
+}
+
+// withLeadership returns a copy of the given context that is canceled with ErrLeadershipLost when the given channel is closed.
+// Call the returned function to release the resources when the context is no longer used.
+func withLeadership(ctx context.Context, lost <-chan struct{}) (context.Context, func()) {
+	leaderCtx, cancel := context.WithCancelCause(ctx)
+	go func() {
+		select {
+		case <-lost:
+			cancel(ErrLeadershipLost)
+
+		case <-leaderCtx.Done():
+			// Released or the parent context is canceled.
+
+		}
+	}()
+
+	return leaderCtx, func() {
+		cancel(nil)
+	}
+}
+
+// isClosed tells if the given channel is closed.
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+
+	default:
+		return false
+
+	}
+}
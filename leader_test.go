@@ -0,0 +1,222 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type DummyLeaderElector struct {
+	ElectFunc  func(context.Context) (<-chan struct{}, error)
+	ResignFunc func(context.Context) error
+}
+
+var _ LeaderElector = (*DummyLeaderElector)(nil)
+
+func (e *DummyLeaderElector) Elect(ctx context.Context) (<-chan struct{}, error) {
+	return e.ElectFunc(ctx)
+}
+
+func (e *DummyLeaderElector) Resign(ctx context.Context) error {
+	return e.ResignFunc(ctx)
+}
+
+func TestRegisterLeaderElector(t *testing.T) {
+	SetupAndRun(func() {
+		elector := &DummyLeaderElector{}
+		RegisterLeaderElector("dummy", elector)
+		r := &runner{status: runnerStatus}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		if r.leaderElectors["dummy"] != elector {
+			t.Error("Given LeaderElector is not set.")
+		}
+	})
+}
+
+func Test_runner_elect(t *testing.T) {
+	defaultInterval := electionRetryInterval
+	electionRetryInterval = time.Millisecond
+	defer func() {
+		electionRetryInterval = defaultInterval
+	}()
+
+	t.Run("Retry on failure", func(t *testing.T) {
+		SetupAndRun(func() {
+			bot := &DummyBot{BotTypeValue: "dummy"}
+			runnerStatus.addBot(bot)
+
+			trial := 0
+			lost := make(chan struct{})
+			elector := &DummyLeaderElector{
+				ElectFunc: func(_ context.Context) (<-chan struct{}, error) {
+					trial++
+					if trial < 2 {
+						return nil, errors.New("connection error")
+					}
+					return lost, nil
+				},
+			}
+
+			r := &runner{status: runnerStatus}
+			given, ok := r.elect(context.TODO(), bot, elector)
+			if !ok {
+				t.Fatal("Leadership is not acquired.")
+			}
+
+			if given != lost {
+				t.Error("Expected channel is not returned.")
+			}
+
+			if CurrentStatus().Bots[0].Leadership != LeadershipLeader {
+				t.Errorf("Unexpected leadership is set: %s.", CurrentStatus().Bots[0].Leadership)
+			}
+		})
+	})
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		SetupAndRun(func() {
+			bot := &DummyBot{BotTypeValue: "dummy"}
+			runnerStatus.addBot(bot)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			elector := &DummyLeaderElector{
+				ElectFunc: func(ctx context.Context) (<-chan struct{}, error) {
+					cancel()
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+			}
+
+			r := &runner{status: runnerStatus}
+			_, ok := r.elect(ctx, bot, elector)
+			if ok {
+				t.Fatal("Leadership should not be acquired.")
+			}
+
+			if CurrentStatus().Bots[0].Leadership != LeadershipFollower {
+				t.Errorf("Unexpected leadership is set: %s.", CurrentStatus().Bots[0].Leadership)
+			}
+		})
+	})
+}
+
+func Test_withLeadership(t *testing.T) {
+	t.Run("Leadership lost", func(t *testing.T) {
+		lost := make(chan struct{})
+		ctx, release := withLeadership(context.Background(), lost)
+		defer release()
+
+		close(lost)
+
+		select {
+		case <-ctx.Done():
+			if !errors.Is(context.Cause(ctx), ErrLeadershipLost) {
+				t.Errorf("Unexpected cause is set: %#v.", context.Cause(ctx))
+			}
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Error("Context is not canceled.")
+
+		}
+	})
+
+	t.Run("Released", func(t *testing.T) {
+		ctx, release := withLeadership(context.Background(), make(chan struct{}))
+		release()
+
+		if errors.Is(context.Cause(ctx), ErrLeadershipLost) {
+			t.Errorf("Unexpected cause is set: %#v.", context.Cause(ctx))
+		}
+	})
+}
+
+func Test_runner_run_WithLeaderElector(t *testing.T) {
+	SetupAndRun(func() {
+		var botType BotType = "myBot"
+
+		run := make(chan struct{}, 2)
+		bot := &DummyBot{
+			BotTypeValue: botType,
+			RunFunc: func(ctx context.Context, _ func(Input) error, _ func(error)) {
+				run <- struct{}{}
+				<-ctx.Done()
+			},
+		}
+
+		terms := make(chan chan struct{}, 2)
+		resigned := make(chan struct{}, 1)
+		elector := &DummyLeaderElector{
+			ElectFunc: func(_ context.Context) (<-chan struct{}, error) {
+				lost := make(chan struct{})
+				terms <- lost
+				return lost, nil
+			},
+			ResignFunc: func(_ context.Context) error {
+				resigned <- struct{}{}
+				return nil
+			},
+		}
+
+		r := &runner{
+			status: runnerStatus,
+			config: &Config{
+				TimeZone: time.Now().Location().String(),
+			},
+			bots:           []Bot{bot},
+			alerters:       &alerters{},
+			leaderElectors: map[BotType]LeaderElector{botType: elector},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		finished := make(chan struct{})
+		go func() {
+			r.run(ctx)
+			close(finished)
+		}()
+
+		// The first term ends with the leadership loss.
+		lost := <-terms
+		<-run
+		close(lost)
+
+		// The bot runs again on the second term.
+		<-terms
+		select {
+		case <-run:
+			// O.K.
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Fatal("Bot is not run after the re-election.")
+
+		}
+
+		cancel()
+		<-finished
+
+		select {
+		case <-resigned:
+			// O.K.
+
+		default:
+			t.Error("Leadership is not resigned on stop.")
+
+		}
+	})
+}
+
+func Test_isClosed(t *testing.T) {
+	c := make(chan struct{})
+	if isClosed(c) {
+		t.Error("Channel is not closed, yet.")
+	}
+
+	close(c)
+	if !isClosed(c) {
+		t.Error("Channel is closed.")
+	}
+}
@@ -0,0 +1,91 @@
+package sarah
+
+import (
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"time"
+)
+
+// LifecycleEventType represents a kind of LifecycleEvent.
+type LifecycleEventType string
+
+const (
+	// BotStarting is emitted before Sarah registers commands and scheduled tasks for a Bot and runs it.
+	BotStarting LifecycleEventType = "bot_starting"
+
+	// BotStarted is emitted when the Bot's preparation completes and Bot.Run is about to be called.
+	BotStarted LifecycleEventType = "bot_started"
+
+	// BotDraining is emitted when the Bot stops accepting new Inputs on shutdown to drain the in-flight ones. See DrainConfig.
+	BotDraining LifecycleEventType = "bot_draining"
+
+	// BotDrained is emitted when the drain completes or times out, right before the Bot's context is canceled.
+	// Check BotStatus.Drain for the result.
+	BotDrained LifecycleEventType = "bot_drained"
+
+	// BotStopped is emitted when Bot.Run returns. LifecycleEvent.Reason tells why the Bot stopped.
+	BotStopped LifecycleEventType = "bot_stopped"
+
+	// RunnerStopped is emitted when all Bots stop and Sarah finishes its operation.
+	// When Sarah stops to restart via Restart, LifecycleEvent.Reason is ErrRunnerRestarting and the Bots start again with a new runner.
+	RunnerStopped LifecycleEventType = "runner_stopped"
+)
+
+// LifecycleEvent represents an event in the lifecycle of Sarah and its Bots.
+type LifecycleEvent struct {
+	// Type tells what happened.
+	Type LifecycleEventType
+
+	// BotType tells which Bot the event is about. This is empty for RunnerStopped.
+	BotType BotType
+
+	// Reason tells why the Bot stopped. This is set only for BotStopped and RunnerStopped.
+	// This is context.Canceled when Sarah's context is canceled, or the escalated error when the Bot is stopped by Sarah's supervision.
+	// For RunnerStopped, this is ErrRunnerRestarting when Sarah stops to restart via Restart, and nil otherwise.
+	Reason error
+
+	// OccurredAt tells when the event occurred.
+	OccurredAt time.Time
+}
+
+// LifecycleHook is a function that is called on each LifecycleEvent.
+// A hook is called synchronously, so the Bot waits for the hook to return on BotStarting and BotStarted.
+// This is handy to warm caches before the Bot starts, but a hook should return as soon as possible.
+// Run a time-consuming task such as announcing maintenance in a new goroutine.
+type LifecycleHook func(*LifecycleEvent)
+
+// RegisterLifecycleHook registers a given hook that is called on each LifecycleEvent.
+// Operators can use this to announce maintenance, warm caches, or flush state without modifying Bot implementations.
+// Multiple hooks can be registered, and they are called in the order of registration.
+func RegisterLifecycleHook(hook LifecycleHook) {
+	defaultRunner().RegisterLifecycleHook(hook)
+}
+
+// RegisterLifecycleHook is the Runner counterpart of the package-level RegisterLifecycleHook.
+func (rn *Runner) RegisterLifecycleHook(hook LifecycleHook) {
+	rn.options.register(func(r *runner) {
+		r.lifecycleHooks = append(r.lifecycleHooks, hook)
+	})
+}
+
+// notifyLifecycleEvent calls all registered LifecycleHooks in a panic-proof manner.
+func (r *runner) notifyLifecycleEvent(eventType LifecycleEventType, botType BotType, reason error) {
+	event := &LifecycleEvent{
+		Type:       eventType,
+		BotType:    botType,
+		Reason:     reason,
+		OccurredAt: currentClock().Now(),
+	}
+
+	for _, hook := range r.lifecycleHooks {
+		func() {
+			defer func() {
+				if rcv := recover(); rcv != nil {
+					logger.Errorf("Panic on lifecycle hook: %s", fmt.Sprint(rcv))
+				}
+			}()
+
+			hook(event)
+		}()
+	}
+}
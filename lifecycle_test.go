@@ -0,0 +1,115 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegisterLifecycleHook(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterLifecycleHook(func(_ *LifecycleEvent) {})
+		RegisterLifecycleHook(func(_ *LifecycleEvent) {})
+		r := &runner{}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		if len(r.lifecycleHooks) != 2 {
+			t.Errorf("Unexpected number of hooks are set: %d.", len(r.lifecycleHooks))
+		}
+	})
+}
+
+func Test_runner_notifyLifecycleEvent(t *testing.T) {
+	var given []*LifecycleEvent
+	r := &runner{
+		lifecycleHooks: []LifecycleHook{
+			func(_ *LifecycleEvent) {
+				panic("Panic should not affect other hooks' behavior.")
+			},
+			func(event *LifecycleEvent) {
+				given = append(given, event)
+			},
+		},
+	}
+
+	reason := errors.New("stopped")
+	r.notifyLifecycleEvent(BotStopped, "dummy", reason)
+
+	if len(given) != 1 {
+		t.Fatalf("Unexpected number of events are given: %d.", len(given))
+	}
+
+	event := given[0]
+	if event.Type != BotStopped {
+		t.Errorf("Unexpected event type is given: %s.", event.Type)
+	}
+	if event.BotType != "dummy" {
+		t.Errorf("Unexpected BotType is given: %s.", event.BotType)
+	}
+	if event.Reason != reason {
+		t.Errorf("Unexpected reason is given: %#v.", event.Reason)
+	}
+	if event.OccurredAt.IsZero() {
+		t.Error("OccurredAt is not set.")
+	}
+}
+
+func Test_runner_run_LifecycleEvents(t *testing.T) {
+	SetupAndRun(func() {
+		var botType BotType = "myBot"
+		bot := &DummyBot{
+			BotTypeValue: botType,
+			RunFunc: func(_ context.Context, _ func(Input) error, _ func(error)) {
+				// Return immediately.
+			},
+		}
+
+		mutex := &sync.Mutex{}
+		var events []*LifecycleEvent
+		r := &runner{
+			status: runnerStatus,
+			config: &Config{
+				TimeZone: time.Now().Location().String(),
+			},
+			bots: []Bot{
+				bot,
+			},
+			alerters: &alerters{},
+			lifecycleHooks: []LifecycleHook{
+				func(event *LifecycleEvent) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					events = append(events, event)
+				},
+			},
+		}
+
+		r.run(context.Background())
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		expected := []LifecycleEventType{BotStarting, BotStarted, BotStopped, RunnerStopped}
+		if len(events) != len(expected) {
+			t.Fatalf("Unexpected number of events are given: %d.", len(events))
+		}
+		for i, e := range expected {
+			if events[i].Type != e {
+				t.Errorf("Unexpected event is given at %d: %s.", i, events[i].Type)
+			}
+		}
+
+		var nonContinuableErr *BotNonContinuableError
+		if !errors.As(events[2].Reason, &nonContinuableErr) {
+			t.Errorf("Unexpected reason is given: %#v.", events[2].Reason)
+		}
+
+		if events[3].BotType != "" {
+			t.Errorf("BotType should be empty for RunnerStopped: %s.", events[3].BotType)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package sarah
+
+import (
+	"context"
+	"github.com/oklahomer/go-kasumi/logger"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LogLevelEnv is the name of the environment variable that overrides the log level.
+// When this is set, the value is applied on package initialization and Config.LogLevel is ignored,
+// so the log verbosity of a deployed process can be changed without modifying its configuration file.
+const LogLevelEnv = "SARAH_LOG_LEVEL"
+
+var currentLogLevel = struct {
+	level logger.Level
+	mutex sync.RWMutex
+}{
+	// This is the default level of the logger package.
+	level: logger.DebugLevel,
+}
+
+func init() {
+	level := os.Getenv(LogLevelEnv)
+	if level == "" {
+		return
+	}
+
+	err := SetLogLevel(level)
+	if err != nil {
+		logger.Errorf("Failed to apply %s: %+v", LogLevelEnv, err)
+	}
+}
+
+// SetLogLevel sets the output level of the logger: "debug," "info," "warn," or "error."
+// This can be called at runtime, e.g., from an administrative Command, to change the log verbosity without restarting the process.
+func SetLogLevel(level string) error {
+	parsed, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+
+	currentLogLevel.mutex.Lock()
+	defer currentLogLevel.mutex.Unlock()
+	currentLogLevel.level = parsed
+	logger.SetOutputLevel(parsed)
+	return nil
+}
+
+// LogLevel returns the current output level of the logger that is set via SetLogLevel, Config.LogLevel, or LogLevelEnv.
+func LogLevel() string {
+	currentLogLevel.mutex.RLock()
+	defer currentLogLevel.mutex.RUnlock()
+	return strings.ToLower(currentLogLevel.level.String())
+}
+
+// logLevelOverridden tells if the log level is overridden by LogLevelEnv and hence Config.LogLevel must be ignored.
+func logLevelOverridden() bool {
+	return os.Getenv(LogLevelEnv) != ""
+}
+
+// logField is a key-value pair that LoggerFromContext prepends to each log line.
+type logField struct {
+	key   string
+	value string
+}
+
+type logFieldsKey struct{}
+
+// withLogField returns a copy of the given context that carries the given key-value pair for LoggerFromContext.
+// A field with the same key replaces the existing one.
+func withLogField(ctx context.Context, key string, value string) context.Context {
+	current, _ := ctx.Value(logFieldsKey{}).([]logField)
+	fields := make([]logField, 0, len(current)+1)
+	for _, field := range current {
+		if field.key != key {
+			fields = append(fields, field)
+		}
+	}
+	fields = append(fields, logField{key: key, value: value})
+	return context.WithValue(ctx, logFieldsKey{}, fields)
+}
+
+// LoggerFromContext returns a logger.Logger that prepends the contextual information stored in the given context to each log line.
+// When the given context is the one passed to Command's or ScheduledTask's execution,
+// the log line includes the BotType, the Command or ScheduledTask identifier, and the correlation ID of the Input if available.
+// The returned logger.Logger proxies each call to the one set via logger.SetLogger, so the output level set via logger.SetOutputLevel is respected.
+//
+//	func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+//		sarah.LoggerFromContext(ctx).Infof("Searching for %s", input.Message())
+//		// Outputs "[INFO] [BotType=slack Command=search CorrelationID=3f1c0b2a9d8e7f60] Searching for ..."
+//	}
+func LoggerFromContext(ctx context.Context) logger.Logger {
+	fields, _ := ctx.Value(logFieldsKey{}).([]logField)
+	correlationID := CorrelationIDFromContext(ctx)
+	if len(fields) == 0 && correlationID == "" {
+		return &contextLogger{}
+	}
+
+	// Build the prefix with a single buffer since this is called on every Input reception.
+	b := strings.Builder{}
+	b.WriteByte('[')
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(field.key)
+		b.WriteByte('=')
+		b.WriteString(field.value)
+	}
+	if correlationID != "" {
+		if len(fields) > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString("CorrelationID=")
+		b.WriteString(correlationID)
+	}
+	b.WriteByte(']')
+
+	prefix := b.String()
+	formatPrefix := prefix + " "
+	if strings.Contains(prefix, "%") {
+		// Escape the prefix so the values are not interpreted as formatting verbs.
+		formatPrefix = strings.ReplaceAll(prefix, "%", "%%") + " "
+	}
+	return &contextLogger{
+		prefix:       prefix,
+		formatPrefix: formatPrefix,
+	}
+}
+
+// contextLogger is a logger.Logger implementation that prepends the given prefix to each log line.
+type contextLogger struct {
+	prefix       string
+	formatPrefix string
+}
+
+var _ logger.Logger = (*contextLogger)(nil)
+
+func (l *contextLogger) args(args []interface{}) []interface{} {
+	if l.prefix == "" {
+		return args
+	}
+	return append([]interface{}{l.prefix}, args...)
+}
+
+func (l *contextLogger) Debug(args ...interface{}) {
+	logger.Debug(l.args(args)...)
+}
+
+func (l *contextLogger) Debugf(format string, args ...interface{}) {
+	logger.Debugf(l.formatPrefix+format, args...)
+}
+
+func (l *contextLogger) Info(args ...interface{}) {
+	logger.Info(l.args(args)...)
+}
+
+func (l *contextLogger) Infof(format string, args ...interface{}) {
+	logger.Infof(l.formatPrefix+format, args...)
+}
+
+func (l *contextLogger) Warn(args ...interface{}) {
+	logger.Warn(l.args(args)...)
+}
+
+func (l *contextLogger) Warnf(format string, args ...interface{}) {
+	logger.Warnf(l.formatPrefix+format, args...)
+}
+
+func (l *contextLogger) Error(args ...interface{}) {
+	logger.Error(l.args(args)...)
+}
+
+func (l *contextLogger) Errorf(format string, args ...interface{}) {
+	logger.Errorf(l.formatPrefix+format, args...)
+}
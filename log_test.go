@@ -0,0 +1,189 @@
+package sarah
+
+import (
+	"bytes"
+	"context"
+	"github.com/oklahomer/go-kasumi/logger"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLoggerFromContext(t *testing.T) {
+	oldLogger := logger.GetLogger()
+	defer logger.SetLogger(oldLogger)
+
+	buf := &bytes.Buffer{}
+	logger.SetLogger(logger.NewWithStandardLogger(log.New(buf, "", 0)))
+
+	ctx := withLogField(context.TODO(), "BotType", "slack")
+	ctx = withLogField(ctx, "Command", "old")
+	ctx = withLogField(ctx, "Command", "echo")
+	ctx = withCorrelationID(ctx, "abc")
+
+	testSets := []struct {
+		log      func(logger.Logger)
+		expected string
+	}{
+		{
+			log: func(l logger.Logger) {
+				l.Debugf("Hello, %s.", "world")
+			},
+			expected: "[DEBUG] [BotType=slack Command=echo CorrelationID=abc] Hello, world.\n",
+		},
+		{
+			log: func(l logger.Logger) {
+				l.Infof("Hello, %s.", "world")
+			},
+			expected: "[INFO] [BotType=slack Command=echo CorrelationID=abc] Hello, world.\n",
+		},
+		{
+			log: func(l logger.Logger) {
+				l.Warnf("Hello, %s.", "world")
+			},
+			expected: "[WARN] [BotType=slack Command=echo CorrelationID=abc] Hello, world.\n",
+		},
+		{
+			log: func(l logger.Logger) {
+				l.Errorf("Hello, %s.", "world")
+			},
+			expected: "[ERROR] [BotType=slack Command=echo CorrelationID=abc] Hello, world.\n",
+		},
+		{
+			log: func(l logger.Logger) {
+				l.Error("Hello")
+			},
+			expected: "[ERROR] [BotType=slack Command=echo CorrelationID=abc] Hello\n",
+		},
+	}
+
+	for i, tt := range testSets {
+		buf.Reset()
+		tt.log(LoggerFromContext(ctx))
+
+		if buf.String() != tt.expected {
+			t.Errorf("Unexpected output on test #%d: %s.", i+1, buf.String())
+		}
+	}
+}
+
+func TestLoggerFromContext_WithoutFields(t *testing.T) {
+	oldLogger := logger.GetLogger()
+	defer logger.SetLogger(oldLogger)
+
+	buf := &bytes.Buffer{}
+	logger.SetLogger(logger.NewWithStandardLogger(log.New(buf, "", 0)))
+
+	LoggerFromContext(context.TODO()).Infof("100%% plain")
+	if buf.String() != "[INFO] 100% plain\n" {
+		t.Errorf("Unexpected output: %s.", buf.String())
+	}
+
+	buf.Reset()
+	ctx := withLogField(context.TODO(), "Command", "100%")
+	LoggerFromContext(ctx).Infof("Done")
+	if !strings.Contains(buf.String(), "[Command=100%] Done") {
+		t.Errorf("Unexpected output: %s.", buf.String())
+	}
+}
+
+func TestCommands_ExecuteFirstMatched_LogField(t *testing.T) {
+	var fields []logField
+	commands := NewCommands()
+	commands.Append(&DummyCommand{
+		IdentifierValue: "echo",
+		MatchFunc: func(_ Input) bool {
+			return true
+		},
+		ExecuteFunc: func(ctx context.Context, _ Input) (*CommandResponse, error) {
+			fields, _ = ctx.Value(logFieldsKey{}).([]logField)
+			return nil, nil
+		},
+	})
+
+	_, _ = commands.ExecuteFirstMatched(context.TODO(), &DummyInput{})
+
+	if len(fields) != 1 || fields[0].key != "Command" || fields[0].value != "echo" {
+		t.Errorf("Unexpected fields are set: %#v.", fields)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	defer func() {
+		_ = SetLogLevel("debug")
+	}()
+
+	testSets := []struct {
+		level    string
+		expected string
+		hasErr   bool
+	}{
+		{
+			level:    "info",
+			expected: "info",
+		},
+		{
+			level:    "WARNING",
+			expected: "warn",
+		},
+		{
+			level:    "error",
+			expected: "error",
+		},
+		{
+			level:    "verbose",
+			expected: "error",
+			hasErr:   true,
+		},
+	}
+
+	for i, tt := range testSets {
+		err := SetLogLevel(tt.level)
+		if tt.hasErr && err == nil {
+			t.Errorf("Expected error is not returned on test #%d.", i+1)
+		}
+		if !tt.hasErr && err != nil {
+			t.Errorf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+
+		if LogLevel() != tt.expected {
+			t.Errorf("Unexpected log level on test #%d: %s.", i+1, LogLevel())
+		}
+	}
+}
+
+func Test_logLevelOverridden(t *testing.T) {
+	t.Setenv(LogLevelEnv, "")
+	if logLevelOverridden() {
+		t.Error("Log level should not be overridden.")
+	}
+
+	t.Setenv(LogLevelEnv, "error")
+	if !logLevelOverridden() {
+		t.Error("Log level should be overridden.")
+	}
+}
+
+func Test_newRunner_LogLevelOverridden(t *testing.T) {
+	defer func() {
+		_ = SetLogLevel("debug")
+	}()
+	t.Setenv(LogLevelEnv, "error")
+	_ = SetLogLevel("error")
+
+	SetupAndRun(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		_, err := newRunner(ctx, &Config{
+			TimeZone: "UTC",
+			LogLevel: "info",
+		}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if LogLevel() != "error" {
+			t.Errorf("Config.LogLevel should be ignored: %s.", LogLevel())
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package sarah
+
+import (
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogFilter defines a function that wraps a logger.Logger to filter or modify the log lines.
+// Multiple LogFilters can be composed with FilterLogger.
+type LogFilter func(next logger.Logger) logger.Logger
+
+// FilterLogger wraps the given logger.Logger with the given LogFilters.
+// Each log line passes through the filters in the given order before reaching the given logger.Logger.
+//
+//	filtered := sarah.FilterLogger(logger.GetLogger(),
+//		sarah.NewDebugSamplingLogFilter(10, time.Minute),
+//		sarah.NewRedactingLogFilter([]*regexp.Regexp{regexp.MustCompile(`xox[bp]-[0-9A-Za-z-]+`)}, "[REDACTED]"))
+//	logger.SetLogger(filtered)
+func FilterLogger(l logger.Logger, filters ...LogFilter) logger.Logger {
+	for i := len(filters) - 1; i >= 0; i-- {
+		l = filters[i](l)
+	}
+	return l
+}
+
+// LogFilterConfig declares the LogFilters that Sarah applies to the logger on Run.
+type LogFilterConfig struct {
+	// DebugSampleLimit declares the maximum number of Debug lines with the same format that are output in every DebugSampleInterval.
+	// Zero value disables the sampling.
+	DebugSampleLimit int `json:"debug_sample_limit" yaml:"debug_sample_limit"`
+
+	// DebugSampleInterval declares the interval to reset the count of the sampled Debug lines.
+	DebugSampleInterval time.Duration `json:"debug_sample_interval" yaml:"debug_sample_interval"`
+
+	// RedactPatterns declares the regular expressions to find the sensitive strings such as access tokens in the log lines.
+	// The matched strings are replaced with RedactReplacement.
+	RedactPatterns []string `json:"redact_patterns" yaml:"redact_patterns"`
+
+	// RedactFields declares the names of the fields whose values must be redacted.
+	// e.g. "password" redacts the values of password=secret, password: secret, and "password":"secret" in the log lines.
+	RedactFields []string `json:"redact_fields" yaml:"redact_fields"`
+
+	// RedactReplacement declares the string to replace the redacted values with.
+	RedactReplacement string `json:"redact_replacement" yaml:"redact_replacement"`
+}
+
+// NewLogFilterConfig creates and returns a new LogFilterConfig instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewLogFilterConfig() *LogFilterConfig {
+	return &LogFilterConfig{
+		DebugSampleLimit:    0,
+		DebugSampleInterval: time.Minute,
+		RedactPatterns:      []string{},
+		RedactFields:        []string{},
+		RedactReplacement:   "[REDACTED]",
+	}
+}
+
+// filters builds the LogFilters that are declared by the config.
+func (c *LogFilterConfig) filters() ([]LogFilter, error) {
+	var filters []LogFilter
+	if c.DebugSampleLimit > 0 {
+		filters = append(filters, NewDebugSamplingLogFilter(c.DebugSampleLimit, c.DebugSampleInterval))
+	}
+
+	var patterns []*regexp.Regexp
+	for _, pattern := range c.RedactPatterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile redaction pattern %s: %w", pattern, err)
+		}
+		patterns = append(patterns, compiled)
+	}
+	if len(c.RedactFields) > 0 {
+		quoted := make([]string, len(c.RedactFields))
+		for i, field := range c.RedactFields {
+			quoted[i] = regexp.QuoteMeta(field)
+		}
+		// Capture the field name and the separator, so only the value is replaced.
+		fieldPattern := fmt.Sprintf(`(?i)("?\b(?:%s)"?\s*[:=]\s*"?)[^"\s,&}]+`, strings.Join(quoted, "|"))
+		patterns = append(patterns, regexp.MustCompile(fieldPattern))
+	}
+	if len(patterns) > 0 {
+		filters = append(filters, NewRedactingLogFilter(patterns, c.RedactReplacement))
+	}
+
+	return filters, nil
+}
+
+// NewDebugSamplingLogFilter creates and returns a LogFilter that outputs up to the given limit of Debug lines with the same format in every given interval.
+// This prevents repetitive Debug lines such as the ones on every message reception from flooding the log aggregation system.
+// Log lines with other levels are always output.
+func NewDebugSamplingLogFilter(limit int, interval time.Duration) LogFilter {
+	return func(next logger.Logger) logger.Logger {
+		return &samplingLogger{
+			Logger:   next,
+			limit:    limit,
+			interval: interval,
+			counts:   map[string]int{},
+		}
+	}
+}
+
+type samplingLogger struct {
+	logger.Logger
+	limit    int
+	interval time.Duration
+	counts   map[string]int
+	resetAt  time.Time
+	mutex    sync.Mutex
+}
+
+func (l *samplingLogger) sampled(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := currentClock().Now()
+	if now.After(l.resetAt) {
+		// Reset all counts at once so the map does not grow with the number of distinct log lines.
+		l.counts = map[string]int{}
+		l.resetAt = now.Add(l.interval)
+	}
+
+	l.counts[key]++
+	return l.counts[key] <= l.limit
+}
+
+func (l *samplingLogger) Debug(args ...interface{}) {
+	if l.sampled(fmt.Sprint(args...)) {
+		l.Logger.Debug(args...)
+	}
+}
+
+func (l *samplingLogger) Debugf(format string, args ...interface{}) {
+	if l.sampled(format) {
+		l.Logger.Debugf(format, args...)
+	}
+}
+
+// NewRedactingLogFilter creates and returns a LogFilter that replaces the strings matching the given patterns with the given replacement.
+// When a pattern has capturing groups, only the part after the first group is replaced so the field names can be kept as-is.
+func NewRedactingLogFilter(patterns []*regexp.Regexp, replacement string) LogFilter {
+	return func(next logger.Logger) logger.Logger {
+		return &redactingLogger{
+			next:        next,
+			patterns:    patterns,
+			replacement: replacement,
+		}
+	}
+}
+
+type redactingLogger struct {
+	next        logger.Logger
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+func (l *redactingLogger) redact(message string) string {
+	escaped := strings.ReplaceAll(l.replacement, "$", "$$")
+	for _, pattern := range l.patterns {
+		if pattern.NumSubexp() > 0 {
+			message = pattern.ReplaceAllString(message, "${1}"+escaped)
+		} else {
+			message = pattern.ReplaceAllString(message, escaped)
+		}
+	}
+	return message
+}
+
+func (l *redactingLogger) Debug(args ...interface{}) {
+	l.next.Debug(l.redact(sprintArgs(args)))
+}
+
+func (l *redactingLogger) Debugf(format string, args ...interface{}) {
+	l.next.Debugf("%s", l.redact(fmt.Sprintf(format, args...)))
+}
+
+func (l *redactingLogger) Info(args ...interface{}) {
+	l.next.Info(l.redact(sprintArgs(args)))
+}
+
+func (l *redactingLogger) Infof(format string, args ...interface{}) {
+	l.next.Infof("%s", l.redact(fmt.Sprintf(format, args...)))
+}
+
+func (l *redactingLogger) Warn(args ...interface{}) {
+	l.next.Warn(l.redact(sprintArgs(args)))
+}
+
+func (l *redactingLogger) Warnf(format string, args ...interface{}) {
+	l.next.Warnf("%s", l.redact(fmt.Sprintf(format, args...)))
+}
+
+func (l *redactingLogger) Error(args ...interface{}) {
+	l.next.Error(l.redact(sprintArgs(args)))
+}
+
+func (l *redactingLogger) Errorf(format string, args ...interface{}) {
+	l.next.Errorf("%s", l.redact(fmt.Sprintf(format, args...)))
+}
+
+// sprintArgs formats the given arguments in the same way as the default logger of the logger package.
+func sprintArgs(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
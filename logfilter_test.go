@@ -0,0 +1,169 @@
+package sarah
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type DummyLogger struct {
+	lines []string
+}
+
+func (l *DummyLogger) Debug(args ...interface{}) {
+	l.lines = append(l.lines, "[DEBUG] "+fmt.Sprint(args...))
+}
+
+func (l *DummyLogger) Debugf(format string, args ...interface{}) {
+	l.lines = append(l.lines, "[DEBUG] "+fmt.Sprintf(format, args...))
+}
+
+func (l *DummyLogger) Info(args ...interface{}) {
+	l.lines = append(l.lines, "[INFO] "+fmt.Sprint(args...))
+}
+
+func (l *DummyLogger) Infof(format string, args ...interface{}) {
+	l.lines = append(l.lines, "[INFO] "+fmt.Sprintf(format, args...))
+}
+
+func (l *DummyLogger) Warn(args ...interface{}) {
+	l.lines = append(l.lines, "[WARN] "+fmt.Sprint(args...))
+}
+
+func (l *DummyLogger) Warnf(format string, args ...interface{}) {
+	l.lines = append(l.lines, "[WARN] "+fmt.Sprintf(format, args...))
+}
+
+func (l *DummyLogger) Error(args ...interface{}) {
+	l.lines = append(l.lines, "[ERROR] "+fmt.Sprint(args...))
+}
+
+func (l *DummyLogger) Errorf(format string, args ...interface{}) {
+	l.lines = append(l.lines, "[ERROR] "+fmt.Sprintf(format, args...))
+}
+
+func TestNewLogFilterConfig(t *testing.T) {
+	config := NewLogFilterConfig()
+
+	if config.DebugSampleLimit != 0 {
+		t.Errorf("Sampling should be disabled by default: %d.", config.DebugSampleLimit)
+	}
+
+	if config.DebugSampleInterval <= 0 {
+		t.Errorf("Unexpected interval is set: %s.", config.DebugSampleInterval)
+	}
+
+	if config.RedactReplacement == "" {
+		t.Error("Replacement is not set.")
+	}
+}
+
+func TestLogFilterConfig_filters(t *testing.T) {
+	config := NewLogFilterConfig()
+	filters, err := config.filters()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(filters) != 0 {
+		t.Errorf("No filter is expected: %d.", len(filters))
+	}
+
+	config.DebugSampleLimit = 1
+	config.RedactPatterns = []string{`xoxb-[0-9A-Za-z-]+`}
+	config.RedactFields = []string{"password"}
+	filters, err = config.filters()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(filters) != 2 {
+		t.Fatalf("Two filters are expected: %d.", len(filters))
+	}
+
+	dummy := &DummyLogger{}
+	l := FilterLogger(dummy, filters...)
+	l.Debugf("Token: %s", "xoxb-123-abc")
+	l.Debugf("Token: %s", "xoxb-456-def")
+	l.Errorf(`Payload: {"password":"secret","user":"foo"} password=secret&user=foo`)
+
+	expected := []string{
+		"[DEBUG] Token: [REDACTED]",
+		`[ERROR] Payload: {"password":"[REDACTED]","user":"foo"} password=[REDACTED]&user=foo`,
+	}
+	if len(dummy.lines) != len(expected) {
+		t.Fatalf("Unexpected lines are output: %#v.", dummy.lines)
+	}
+	for i, line := range expected {
+		if dummy.lines[i] != line {
+			t.Errorf("Unexpected line is output: %s.", dummy.lines[i])
+		}
+	}
+
+	config.RedactPatterns = []string{"("}
+	_, err = config.filters()
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestNewDebugSamplingLogFilter(t *testing.T) {
+	dummy := &DummyLogger{}
+	l := NewDebugSamplingLogFilter(2, time.Hour)(dummy)
+
+	for i := 0; i < 5; i++ {
+		l.Debugf("Received input: %d", i)
+		l.Debug("Polling")
+		l.Info("Info is not sampled")
+	}
+
+	debugCnt := 0
+	infoCnt := 0
+	for _, line := range dummy.lines {
+		switch line[:6] {
+		case "[DEBUG":
+			debugCnt++
+		case "[INFO]":
+			infoCnt++
+		}
+	}
+
+	if debugCnt != 4 {
+		t.Errorf("Unexpected number of Debug lines are output: %d.", debugCnt)
+	}
+	if infoCnt != 5 {
+		t.Errorf("Unexpected number of Info lines are output: %d.", infoCnt)
+	}
+
+	sampling := l.(*samplingLogger)
+	sampling.resetAt = time.Now().Add(-time.Second)
+	l.Debug("Polling")
+	if len(dummy.lines) != 10 {
+		t.Errorf("Count should be reset after the interval: %d.", len(dummy.lines))
+	}
+}
+
+func TestNewRedactingLogFilter(t *testing.T) {
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`),
+		regexp.MustCompile(`(token=)\w+`),
+	}
+	dummy := &DummyLogger{}
+	l := NewRedactingLogFilter(patterns, "$***")(dummy)
+
+	l.Debug("card", "1234-5678-9012-3456")
+	l.Infof("url: https://example.com/?token=%s", "abc")
+	l.Warn("nothing to redact")
+	l.Error("token=abc")
+
+	expected := []string{
+		"[DEBUG] card $***",
+		"[INFO] url: https://example.com/?token=$***",
+		"[WARN] nothing to redact",
+		"[ERROR] token=$***",
+	}
+	for i, line := range expected {
+		if dummy.lines[i] != line {
+			t.Errorf("Unexpected line is output: %s.", dummy.lines[i])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package slogger provides a logger.Logger implementation that is backed by log/slog.
+//
+// Sarah and its components output logs via github.com/oklahomer/go-kasumi/logger.
+// Pass the Logger to logger.SetLogger so the logs are handled by the preferred slog.Handler:
+//
+//	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo, AddSource: true})
+//	logger.SetLogger(slogger.New(slog.New(handler).With("app", "my-bot")))
+package slogger
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// callerSkip is the number of stack frames to skip to report the caller of logger package's function such as logger.Infof.
+const callerSkip = 4
+
+// Logger is a logger.Logger implementation that proxies each log to *slog.Logger.
+//
+// Each logging level is mapped to the corresponding slog.Level: Debug to slog.LevelDebug, Info to slog.LevelInfo, Warn to slog.LevelWarn, and Error to slog.LevelError.
+// The structured attributes given to slog.Logger.With are included in each log record.
+type Logger struct {
+	logger *slog.Logger
+}
+
+var _ logger.Logger = (*Logger)(nil)
+
+// New creates and returns a new Logger with the given *slog.Logger.
+func New(l *slog.Logger) *Logger {
+	return &Logger{
+		logger: l,
+	}
+}
+
+// Debug outputs the given arguments with slog.LevelDebug.
+func (l *Logger) Debug(args ...interface{}) {
+	l.log(slog.LevelDebug, sprint(args))
+}
+
+// Debugf outputs the given arguments with format with slog.LevelDebug.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Info outputs the given arguments with slog.LevelInfo.
+func (l *Logger) Info(args ...interface{}) {
+	l.log(slog.LevelInfo, sprint(args))
+}
+
+// Infof outputs the given arguments with format with slog.LevelInfo.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Warn outputs the given arguments with slog.LevelWarn.
+func (l *Logger) Warn(args ...interface{}) {
+	l.log(slog.LevelWarn, sprint(args))
+}
+
+// Warnf outputs the given arguments with format with slog.LevelWarn.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// Error outputs the given arguments with slog.LevelError.
+func (l *Logger) Error(args ...interface{}) {
+	l.log(slog.LevelError, sprint(args))
+}
+
+// Errorf outputs the given arguments with format with slog.LevelError.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) log(level slog.Level, message string) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	// Report the caller of the logger package's function instead of this adapter.
+	var pcs [1]uintptr
+	runtime.Callers(callerSkip, pcs[:])
+	record := slog.NewRecord(time.Now(), level, message, pcs[0])
+	_ = l.logger.Handler().Handle(ctx, record)
+}
+
+// sprint formats the given arguments in the same way as the default logger of the logger package.
+func sprint(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
@@ -0,0 +1,123 @@
+package slogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/oklahomer/go-kasumi/logger"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	l := slog.Default()
+	adapter := New(l)
+
+	if adapter.logger != l {
+		t.Error("Given *slog.Logger is not set.")
+	}
+}
+
+func TestLogger(t *testing.T) {
+	oldLogger := logger.GetLogger()
+	defer logger.SetLogger(oldLogger)
+
+	buf := &bytes.Buffer{}
+	handler := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug, AddSource: true})
+	logger.SetLogger(New(slog.New(handler).With("app", "sarah")))
+
+	testSets := []struct {
+		log     func()
+		level   string
+		message string
+	}{
+		{
+			log:     func() { logger.Debug("debug", 1) },
+			level:   "DEBUG",
+			message: "debug 1",
+		},
+		{
+			log:     func() { logger.Debugf("debug %d", 1) },
+			level:   "DEBUG",
+			message: "debug 1",
+		},
+		{
+			log:     func() { logger.Info("info") },
+			level:   "INFO",
+			message: "info",
+		},
+		{
+			log:     func() { logger.Infof("info %s", "formatted") },
+			level:   "INFO",
+			message: "info formatted",
+		},
+		{
+			log:     func() { logger.Warn("warn") },
+			level:   "WARN",
+			message: "warn",
+		},
+		{
+			log:     func() { logger.Warnf("warn %s", "formatted") },
+			level:   "WARN",
+			message: "warn formatted",
+		},
+		{
+			log:     func() { logger.Error("error") },
+			level:   "ERROR",
+			message: "error",
+		},
+		{
+			log:     func() { logger.Errorf("error %s", "formatted") },
+			level:   "ERROR",
+			message: "error formatted",
+		},
+	}
+
+	for i, tt := range testSets {
+		buf.Reset()
+		tt.log()
+
+		record := struct {
+			Level   string `json:"level"`
+			Message string `json:"msg"`
+			App     string `json:"app"`
+			Source  struct {
+				File string `json:"file"`
+			} `json:"source"`
+		}{}
+		err := json.Unmarshal(buf.Bytes(), &record)
+		if err != nil {
+			t.Fatalf("Unexpected output on test #%d: %s.", i+1, buf.String())
+		}
+
+		if record.Level != tt.level {
+			t.Errorf("Unexpected level on test #%d: %s.", i+1, record.Level)
+		}
+		if record.Message != tt.message {
+			t.Errorf("Unexpected message on test #%d: %s.", i+1, record.Message)
+		}
+		if record.App != "sarah" {
+			t.Errorf("Attribute is not included on test #%d: %s.", i+1, buf.String())
+		}
+		if !strings.HasSuffix(record.Source.File, "logger_test.go") {
+			t.Errorf("Unexpected source on test #%d: %s.", i+1, record.Source.File)
+		}
+	}
+}
+
+func TestLogger_Level(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	l := New(slog.New(handler))
+
+	l.Debug("debug")
+	l.Info("info")
+	if buf.Len() != 0 {
+		t.Errorf("Log with lower level is output: %s.", buf.String())
+	}
+
+	l.Warn("warn")
+	if buf.Len() == 0 {
+		t.Error("Log is not output.")
+	}
+}
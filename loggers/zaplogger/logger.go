@@ -0,0 +1,98 @@
+// Package zaplogger provides a logger.Logger implementation that is backed by go.uber.org/zap.
+//
+// To avoid binding this project to zap, this package depends on SugaredLogger interface that *zap.SugaredLogger satisfies.
+// Pass the Logger to logger.SetLogger so the logs are handled by zap:
+//
+//	z, _ := zap.NewProduction(zap.AddCallerSkip(3))
+//	logger.SetLogger(zaplogger.New(z.Sugar(), "app", "my-bot"))
+//
+// zap.AddCallerSkip(3) lets zap report the caller of logger package's function such as logger.Infof instead of this adapter.
+package zaplogger
+
+import (
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"strings"
+)
+
+// SugaredLogger defines an interface of zap's logger that Logger depends on.
+// *zap.SugaredLogger satisfies this interface.
+type SugaredLogger interface {
+	// Debugw logs a message with some additional context with zapcore.DebugLevel.
+	Debugw(msg string, keysAndValues ...interface{})
+
+	// Infow logs a message with some additional context with zapcore.InfoLevel.
+	Infow(msg string, keysAndValues ...interface{})
+
+	// Warnw logs a message with some additional context with zapcore.WarnLevel.
+	Warnw(msg string, keysAndValues ...interface{})
+
+	// Errorw logs a message with some additional context with zapcore.ErrorLevel.
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// Logger is a logger.Logger implementation that proxies each log to SugaredLogger.
+//
+// Each logging level is mapped to the corresponding zapcore.Level: Debug to DebugLevel, Info to InfoLevel, Warn to WarnLevel, and Error to ErrorLevel.
+// The structured fields given to New are included in each log entry.
+type Logger struct {
+	logger SugaredLogger
+	fields []interface{}
+}
+
+var _ logger.Logger = (*Logger)(nil)
+
+// New creates and returns a new Logger with the given SugaredLogger.
+// The given keysAndValues are loosely typed key-value pairs in the same manner as zap's SugaredLogger.With,
+// and are included in every log entry as structured fields.
+func New(l SugaredLogger, keysAndValues ...interface{}) *Logger {
+	return &Logger{
+		logger: l,
+		fields: keysAndValues,
+	}
+}
+
+// Debug outputs the given arguments with zapcore.DebugLevel.
+func (l *Logger) Debug(args ...interface{}) {
+	l.logger.Debugw(sprint(args), l.fields...)
+}
+
+// Debugf outputs the given arguments with format with zapcore.DebugLevel.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logger.Debugw(fmt.Sprintf(format, args...), l.fields...)
+}
+
+// Info outputs the given arguments with zapcore.InfoLevel.
+func (l *Logger) Info(args ...interface{}) {
+	l.logger.Infow(sprint(args), l.fields...)
+}
+
+// Infof outputs the given arguments with format with zapcore.InfoLevel.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logger.Infow(fmt.Sprintf(format, args...), l.fields...)
+}
+
+// Warn outputs the given arguments with zapcore.WarnLevel.
+func (l *Logger) Warn(args ...interface{}) {
+	l.logger.Warnw(sprint(args), l.fields...)
+}
+
+// Warnf outputs the given arguments with format with zapcore.WarnLevel.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logger.Warnw(fmt.Sprintf(format, args...), l.fields...)
+}
+
+// Error outputs the given arguments with zapcore.ErrorLevel.
+func (l *Logger) Error(args ...interface{}) {
+	l.logger.Errorw(sprint(args), l.fields...)
+}
+
+// Errorf outputs the given arguments with format with zapcore.ErrorLevel.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logger.Errorw(fmt.Sprintf(format, args...), l.fields...)
+}
+
+// sprint formats the given arguments in the same way as the default logger of the logger package.
+func sprint(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
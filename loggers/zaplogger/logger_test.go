@@ -0,0 +1,114 @@
+package zaplogger
+
+import (
+	"reflect"
+	"testing"
+)
+
+type entry struct {
+	level   string
+	message string
+	fields  []interface{}
+}
+
+type DummySugaredLogger struct {
+	entries []*entry
+}
+
+func (l *DummySugaredLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, &entry{level: "debug", message: msg, fields: keysAndValues})
+}
+
+func (l *DummySugaredLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, &entry{level: "info", message: msg, fields: keysAndValues})
+}
+
+func (l *DummySugaredLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, &entry{level: "warn", message: msg, fields: keysAndValues})
+}
+
+func (l *DummySugaredLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, &entry{level: "error", message: msg, fields: keysAndValues})
+}
+
+func TestNew(t *testing.T) {
+	sugared := &DummySugaredLogger{}
+	l := New(sugared, "app", "sarah")
+
+	if l.logger != sugared {
+		t.Error("Given SugaredLogger is not set.")
+	}
+
+	if !reflect.DeepEqual(l.fields, []interface{}{"app", "sarah"}) {
+		t.Errorf("Given fields are not set: %#v.", l.fields)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	testSets := []struct {
+		log     func(*Logger)
+		level   string
+		message string
+	}{
+		{
+			log:     func(l *Logger) { l.Debug("debug", 1) },
+			level:   "debug",
+			message: "debug 1",
+		},
+		{
+			log:     func(l *Logger) { l.Debugf("debug %d", 1) },
+			level:   "debug",
+			message: "debug 1",
+		},
+		{
+			log:     func(l *Logger) { l.Info("info") },
+			level:   "info",
+			message: "info",
+		},
+		{
+			log:     func(l *Logger) { l.Infof("info %s", "formatted") },
+			level:   "info",
+			message: "info formatted",
+		},
+		{
+			log:     func(l *Logger) { l.Warn("warn") },
+			level:   "warn",
+			message: "warn",
+		},
+		{
+			log:     func(l *Logger) { l.Warnf("warn %s", "formatted") },
+			level:   "warn",
+			message: "warn formatted",
+		},
+		{
+			log:     func(l *Logger) { l.Error("error") },
+			level:   "error",
+			message: "error",
+		},
+		{
+			log:     func(l *Logger) { l.Errorf("error %s", "formatted") },
+			level:   "error",
+			message: "error formatted",
+		},
+	}
+
+	for i, tt := range testSets {
+		sugared := &DummySugaredLogger{}
+		tt.log(New(sugared, "app", "sarah"))
+
+		if len(sugared.entries) != 1 {
+			t.Fatalf("Unexpected number of entries on test #%d: %d.", i+1, len(sugared.entries))
+		}
+
+		e := sugared.entries[0]
+		if e.level != tt.level {
+			t.Errorf("Unexpected level on test #%d: %s.", i+1, e.level)
+		}
+		if e.message != tt.message {
+			t.Errorf("Unexpected message on test #%d: %s.", i+1, e.message)
+		}
+		if !reflect.DeepEqual(e.fields, []interface{}{"app", "sarah"}) {
+			t.Errorf("Unexpected fields on test #%d: %#v.", i+1, e.fields)
+		}
+	}
+}
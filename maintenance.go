@@ -0,0 +1,89 @@
+package sarah
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultMaintenanceMessage is the message to reply to users' inputs when EnterMaintenance is called with an empty message.
+const DefaultMaintenanceMessage = "The bot is under maintenance. Please try again later."
+
+var maintenance = newMaintenanceModes()
+
+// EnterMaintenance puts the Bot with the given BotType into maintenance mode.
+// While in maintenance, the Bot replies the given message to every Input instead of executing Commands, and its ScheduledTasks are paused.
+// When the message is empty, DefaultMaintenanceMessage is used.
+// The state is reflected in BotStatus of CurrentStatus.
+//
+// This can be called before Run or while running; the Bot keeps its connection with the chat service while in maintenance.
+// Calling this again for the same BotType replaces the message.
+func EnterMaintenance(botType BotType, message string) {
+	defaultRunner().EnterMaintenance(botType, message)
+}
+
+// EnterMaintenance is the Runner counterpart of the package-level EnterMaintenance.
+func (rn *Runner) EnterMaintenance(botType BotType, message string) {
+	rn.registries.maintenance.enter(botType, message)
+}
+
+// ExitMaintenance puts the Bot with the given BotType back from maintenance mode.
+// The Bot starts executing Commands and ScheduledTasks again.
+func ExitMaintenance(botType BotType) {
+	defaultRunner().ExitMaintenance(botType)
+}
+
+// ExitMaintenance is the Runner counterpart of the package-level ExitMaintenance.
+func (rn *Runner) ExitMaintenance(botType BotType) {
+	rn.registries.maintenance.exit(botType)
+}
+
+// maintenanceModes holds the messages to reply for each Bot in maintenance.
+// Calls to its methods are thread-safe.
+type maintenanceModes struct {
+	messages map[BotType]string
+	mutex    sync.RWMutex
+}
+
+func newMaintenanceModes() *maintenanceModes {
+	return &maintenanceModes{
+		messages: map[BotType]string{},
+	}
+}
+
+func (m *maintenanceModes) enter(botType BotType, message string) {
+	if message == "" {
+		message = DefaultMaintenanceMessage
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.messages[botType] = message
+}
+
+func (m *maintenanceModes) exit(botType BotType) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.messages, botType)
+}
+
+// message returns the message to reply while the Bot with the given BotType is in maintenance.
+// The second returned value is false when the Bot is not in maintenance.
+func (m *maintenanceModes) message(botType BotType) (string, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	message, ok := m.messages[botType]
+	return message, ok
+}
+
+// respondMaintenance replies the maintenance message to the given Input when the Bot is in maintenance.
+// This returns true when the Input is handled and hence must not be passed to Bot.Respond.
+func respondMaintenance(ctx context.Context, bot Bot, input Input) bool {
+	message, ok := registriesFromContext(ctx).maintenance.message(bot.BotType())
+	if !ok {
+		return false
+	}
+
+	LoggerFromContext(ctx).Debug("Skip handling the input while in maintenance.")
+	bot.SendMessage(ctx, NewOutputMessage(input.ReplyTo(), message))
+	return true
+}
@@ -0,0 +1,151 @@
+package sarah
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnterMaintenance(t *testing.T) {
+	botType := BotType("maintenance")
+	defer ExitMaintenance(botType)
+
+	EnterMaintenance(botType, "Upgrading.")
+	message, ok := maintenance.message(botType)
+	if !ok {
+		t.Fatal("Bot is not in maintenance.")
+	}
+	if message != "Upgrading." {
+		t.Errorf("Unexpected message is set: %s.", message)
+	}
+
+	EnterMaintenance(botType, "")
+	message, _ = maintenance.message(botType)
+	if message != DefaultMaintenanceMessage {
+		t.Errorf("Unexpected message is set: %s.", message)
+	}
+
+	if _, ok := maintenance.message("other"); ok {
+		t.Error("Other Bot should not be in maintenance.")
+	}
+}
+
+func TestExitMaintenance(t *testing.T) {
+	botType := BotType("maintenance")
+	EnterMaintenance(botType, "Upgrading.")
+	ExitMaintenance(botType)
+
+	if _, ok := maintenance.message(botType); ok {
+		t.Error("Bot is still in maintenance.")
+	}
+}
+
+func Test_setupInputReceiver_Maintenance(t *testing.T) {
+	botType := BotType("maintenance")
+	defer ExitMaintenance(botType)
+
+	worker := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			fnc()
+			return nil
+		},
+	}
+
+	responded := false
+	var sent Output
+	bot := &DummyBot{
+		BotTypeValue: botType,
+		RespondFunc: func(_ context.Context, _ Input) error {
+			responded = true
+			return nil
+		},
+		SendMessageFunc: func(_ context.Context, output Output) {
+			sent = output
+		},
+	}
+	receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
+
+	EnterMaintenance(botType, "Upgrading.")
+	if err := receiveInput(&DummyInput{ReplyToValue: "room"}); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if responded {
+		t.Error("Input must not be handled while in maintenance.")
+	}
+	if sent == nil {
+		t.Fatal("Maintenance message is not sent.")
+	}
+	if sent.Destination() != "room" || sent.Content() != "Upgrading." {
+		t.Errorf("Unexpected output is sent: %#v.", sent)
+	}
+
+	ExitMaintenance(botType)
+	if err := receiveInput(&DummyInput{ReplyToValue: "room"}); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if !responded {
+		t.Error("Input is not handled after maintenance.")
+	}
+}
+
+func Test_executeScheduledTask_Maintenance(t *testing.T) {
+	botType := BotType("maintenance")
+	defer ExitMaintenance(botType)
+
+	executed := false
+	task := &DummyScheduledTask{
+		IdentifierValue: "dummy",
+		ExecuteFunc: func(_ context.Context) ([]*ScheduledTaskResult, error) {
+			executed = true
+			return nil, nil
+		},
+	}
+	bot := &DummyBot{BotTypeValue: botType}
+
+	EnterMaintenance(botType, "")
+	if err := executeScheduledTask(context.TODO(), bot, task); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if executed {
+		t.Error("Task must not be executed while in maintenance.")
+	}
+
+	ExitMaintenance(botType)
+	if err := executeScheduledTask(context.TODO(), bot, task); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !executed {
+		t.Error("Task is not executed after maintenance.")
+	}
+}
+
+func Test_status_snapshot_Maintenance(t *testing.T) {
+	botType := BotType("maintenance")
+	defer ExitMaintenance(botType)
+
+	s := &status{
+		bots: []*botStatus{
+			{
+				botType:  botType,
+				finished: make(chan struct{}),
+			},
+		},
+		finished: make(chan struct{}),
+	}
+
+	EnterMaintenance(botType, "Upgrading.")
+	snapshot := s.snapshot()
+	if !snapshot.Bots[0].Maintenance {
+		t.Error("BotStatus.Maintenance should be true.")
+	}
+	if snapshot.Bots[0].MaintenanceMessage != "Upgrading." {
+		t.Errorf("Unexpected message is returned: %s.", snapshot.Bots[0].MaintenanceMessage)
+	}
+
+	ExitMaintenance(botType)
+	snapshot = s.snapshot()
+	if snapshot.Bots[0].Maintenance || snapshot.Bots[0].MaintenanceMessage != "" {
+		t.Errorf("Unexpected status is returned: %#v.", snapshot.Bots[0])
+	}
+}
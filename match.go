@@ -0,0 +1,165 @@
+package sarah
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matcher is a rule to judge if an Input matches a Command, built by MatchPrefix, MatchKeywords, and their case-insensitive variants.
+// Pass this to CommandPropsBuilder.Match.
+//
+// Unlike a function given to CommandPropsBuilder.MatchFunc, a Matcher tells the literal prefix to Sarah,
+// so the Command is indexed by PrefixedCommand and is not even checked against an Input with a different prefix.
+type Matcher struct {
+	// prefix is the literal string that a matching message always starts with. This is used to index the Command.
+	prefix string
+
+	// name is the Command name that a message starting with an alias is rewritten to.
+	name string
+
+	match func(message string) bool
+}
+
+// Match returns true when the given Input's message matches the rule.
+func (m *Matcher) Match(input Input) bool {
+	return m.match(input.Message())
+}
+
+// MatchPrefix returns a Matcher that matches a message starting with the given prefix followed by a whitespace or the end of the message.
+// e.g. MatchPrefix(".echo") matches ".echo" and ".echo hello" but not ".echoes" or "say .echo".
+func MatchPrefix(prefix string) *Matcher {
+	return &Matcher{
+		prefix: prefix,
+		name:   prefix,
+		match: func(message string) bool {
+			if !strings.HasPrefix(message, prefix) {
+				return false
+			}
+			return endsWord(message[len(prefix):])
+		},
+	}
+}
+
+// MatchPrefixFold is a case-insensitive variant of MatchPrefix.
+// The comparison follows Unicode case folding, so MatchPrefixFold(".café") also matches ".CAFÉ".
+func MatchPrefixFold(prefix string) *Matcher {
+	// Only the leading characters without case variants can be used to index the Command.
+	literal := strings.Builder{}
+	for _, r := range prefix {
+		if unicode.SimpleFold(r) != r {
+			break
+		}
+		literal.WriteRune(r)
+	}
+
+	return &Matcher{
+		prefix: literal.String(),
+		name:   prefix,
+		match: func(message string) bool {
+			rest, ok := trimPrefixFold(message, prefix)
+			if !ok {
+				return false
+			}
+			return endsWord(rest)
+		},
+	}
+}
+
+// MatchKeywords returns a Matcher that matches a message containing any of the given keywords as a word.
+// A keyword surrounded by letters or digits such as "cat" in "category" does not match,
+// except for the keywords of the languages written without spaces such as Japanese and Chinese.
+func MatchKeywords(keywords ...string) *Matcher {
+	return &Matcher{
+		match: func(message string) bool {
+			return containsKeyword(message, keywords, trimPrefix)
+		},
+	}
+}
+
+// MatchKeywordsFold is a case-insensitive variant of MatchKeywords.
+// The comparison follows Unicode case folding as MatchPrefixFold does.
+func MatchKeywordsFold(keywords ...string) *Matcher {
+	return &Matcher{
+		match: func(message string) bool {
+			return containsKeyword(message, keywords, trimPrefixFold)
+		},
+	}
+}
+
+// containsKeyword checks if the message contains any of the keywords as a word with the given prefix comparison.
+func containsKeyword(message string, keywords []string, trim func(string, string) (string, bool)) bool {
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+
+		first, _ := utf8.DecodeRuneInString(keyword)
+		last, _ := utf8.DecodeLastRuneInString(keyword)
+		previous := rune(-1)
+		for i, r := range message {
+			if isSpaceless(first) || !isWordRune(previous) {
+				rest, ok := trim(message[i:], keyword)
+				if ok && (isSpaceless(last) || !startsWithWordRune(rest)) {
+					return true
+				}
+			}
+			previous = r
+		}
+	}
+	return false
+}
+
+func trimPrefix(message string, prefix string) (string, bool) {
+	if !strings.HasPrefix(message, prefix) {
+		return "", false
+	}
+	return message[len(prefix):], true
+}
+
+// trimPrefixFold returns the rest of the message when the message starts with the given prefix under Unicode case folding.
+func trimPrefixFold(message string, prefix string) (string, bool) {
+	for _, p := range prefix {
+		r, size := utf8.DecodeRuneInString(message)
+		if size == 0 || !equalFoldRune(r, p) {
+			return "", false
+		}
+		message = message[size:]
+	}
+	return message, true
+}
+
+func equalFoldRune(a rune, b rune) bool {
+	if a == b {
+		return true
+	}
+	for folded := unicode.SimpleFold(a); folded != a; folded = unicode.SimpleFold(folded) {
+		if folded == b {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWord checks if the given rest of the message begins at a word boundary.
+func endsWord(rest string) bool {
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return unicode.IsSpace(r)
+}
+
+func startsWithWordRune(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	return size > 0 && isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isSpaceless checks if the given character belongs to a script that is written without spaces between words.
+func isSpaceless(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
@@ -0,0 +1,106 @@
+package sarah
+
+import (
+	"testing"
+)
+
+func TestMatchPrefix(t *testing.T) {
+	testSets := []struct {
+		message  string
+		expected bool
+	}{
+		{message: ".echo", expected: true},
+		{message: ".echo hello", expected: true},
+		{message: ".echo\nhello", expected: true},
+		{message: ".echoes", expected: false},
+		{message: "say .echo", expected: false},
+		{message: ".ECHO", expected: false},
+	}
+
+	matcher := MatchPrefix(".echo")
+	if matcher.prefix != ".echo" {
+		t.Errorf("Unexpected prefix is set: %s.", matcher.prefix)
+	}
+	for i, tt := range testSets {
+		if matcher.Match(&DummyInput{MessageValue: tt.message}) != tt.expected {
+			t.Errorf("Unexpected result on test #%d: %s.", i+1, tt.message)
+		}
+	}
+}
+
+func TestMatchPrefixFold(t *testing.T) {
+	testSets := []struct {
+		prefix   string
+		message  string
+		expected bool
+	}{
+		{prefix: ".echo", message: ".echo", expected: true},
+		{prefix: ".echo", message: ".ECHO hello", expected: true},
+		{prefix: ".echo", message: ".Echoes", expected: false},
+		{prefix: ".café", message: ".CAFÉ", expected: true},
+		{prefix: "!kelvin", message: "!KELVIN", expected: true},
+		{prefix: ".echo", message: ".ech", expected: false},
+	}
+
+	for i, tt := range testSets {
+		matcher := MatchPrefixFold(tt.prefix)
+		if matcher.Match(&DummyInput{MessageValue: tt.message}) != tt.expected {
+			t.Errorf("Unexpected result on test #%d: %s.", i+1, tt.message)
+		}
+	}
+
+	matcher := MatchPrefixFold(".echo")
+	if matcher.prefix != "." {
+		t.Errorf("Unexpected prefix is set: %s.", matcher.prefix)
+	}
+	if matcher.name != ".echo" {
+		t.Errorf("Unexpected name is set: %s.", matcher.name)
+	}
+}
+
+func TestMatchKeywords(t *testing.T) {
+	testSets := []struct {
+		keywords []string
+		message  string
+		expected bool
+	}{
+		{keywords: []string{"weather"}, message: "how is the weather today?", expected: true},
+		{keywords: []string{"weather"}, message: "weather", expected: true},
+		{keywords: []string{"weather"}, message: "How is the Weather?", expected: false},
+		{keywords: []string{"cat"}, message: "category", expected: false},
+		{keywords: []string{"cat"}, message: "bobcat", expected: false},
+		{keywords: []string{"cat", "dog"}, message: "hot-dog!", expected: true},
+		{keywords: []string{"天気"}, message: "今日の天気は？", expected: true},
+		{keywords: []string{""}, message: "anything", expected: false},
+	}
+
+	for i, tt := range testSets {
+		matcher := MatchKeywords(tt.keywords...)
+		if matcher.Match(&DummyInput{MessageValue: tt.message}) != tt.expected {
+			t.Errorf("Unexpected result on test #%d: %s.", i+1, tt.message)
+		}
+		if matcher.prefix != "" {
+			t.Errorf("Unexpected prefix is set on test #%d: %s.", i+1, matcher.prefix)
+		}
+	}
+}
+
+func TestMatchKeywordsFold(t *testing.T) {
+	testSets := []struct {
+		keywords []string
+		message  string
+		expected bool
+	}{
+		{keywords: []string{"weather"}, message: "How is the WEATHER?", expected: true},
+		{keywords: []string{"straße"}, message: "Die STRAßE ist lang", expected: true},
+		{keywords: []string{"cat"}, message: "CATEGORY", expected: false},
+		{keywords: []string{"погода"}, message: "Какая ПОГОДА?", expected: true},
+	}
+
+	for i, tt := range testSets {
+		matcher := MatchKeywordsFold(tt.keywords...)
+		if matcher.Match(&DummyInput{MessageValue: tt.message}) != tt.expected {
+			t.Errorf("Unexpected result on test #%d: %s.", i+1, tt.message)
+		}
+	}
+}
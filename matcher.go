@@ -0,0 +1,85 @@
+package sarah
+
+import (
+	"slices"
+)
+
+// PrefixedCommand defines an optional interface that a Command implementation may satisfy to be indexed by its literal prefix.
+// Commands index such Commands in a prefix tree, so only the Commands whose prefix matches the beginning of Input.Message are checked
+// instead of calling Command.Match of all registered Commands.
+//
+// A Command built with CommandPropsBuilder.MatchPattern satisfies this when the pattern begins with a literal string such as `^\.echo\b`.
+type PrefixedCommand interface {
+	Command
+
+	// MatchPrefix returns the literal string that Input.Message must start with for Command.Match to return true.
+	// Return an empty string when there is no such string; the Command is then always checked.
+	MatchPrefix() string
+}
+
+// commandIndex indexes the registered Commands so FindFirstMatched can narrow down the candidates without evaluating all matchers.
+type commandIndex struct {
+	// root is the root node of the prefix tree that holds the positions of PrefixedCommands.
+	root *prefixNode
+
+	// unindexed holds the positions of the Commands without literal prefix in ascending order.
+	unindexed []int
+}
+
+type prefixNode struct {
+	children  map[byte]*prefixNode
+	positions []int
+}
+
+// newCommandIndex builds a commandIndex for the given Commands.
+func newCommandIndex(collection []Command) *commandIndex {
+	index := &commandIndex{
+		root:      &prefixNode{},
+		unindexed: []int{},
+	}
+
+	for i, command := range collection {
+		prefixed, ok := resolveCommand[PrefixedCommand](command)
+		if !ok || prefixed.MatchPrefix() == "" {
+			index.unindexed = append(index.unindexed, i)
+			continue
+		}
+
+		node := index.root
+		prefix := prefixed.MatchPrefix()
+		for j := 0; j < len(prefix); j++ {
+			if node.children == nil {
+				node.children = map[byte]*prefixNode{}
+			}
+			child, ok := node.children[prefix[j]]
+			if !ok {
+				child = &prefixNode{}
+				node.children[prefix[j]] = child
+			}
+			node = child
+		}
+		node.positions = append(node.positions, i)
+	}
+
+	return index
+}
+
+// candidates appends the positions of the Commands that may match the given message to buf in ascending order.
+// Those are the Commands whose prefix matches the beginning of the message and the Commands without prefix.
+func (index *commandIndex) candidates(message string, buf []int) []int {
+	buf = append(buf, index.unindexed...)
+
+	node := index.root
+	for i := 0; i < len(message); i++ {
+		child, ok := node.children[message[i]]
+		if !ok {
+			break
+		}
+		node = child
+		buf = append(buf, node.positions...)
+	}
+
+	// Check the candidates in the order of registration.
+	slices.Sort(buf)
+	return buf
+}
@@ -0,0 +1,159 @@
+package sarah
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type DummyPrefixedCommand struct {
+	DummyCommand
+	MatchPrefixValue string
+}
+
+func (command *DummyPrefixedCommand) MatchPrefix() string {
+	return command.MatchPrefixValue
+}
+
+func newDummyPrefixedCommand(id string, prefix string) *DummyPrefixedCommand {
+	return &DummyPrefixedCommand{
+		DummyCommand: DummyCommand{
+			IdentifierValue: id,
+			MatchFunc: func(input Input) bool {
+				return strings.HasPrefix(input.Message(), prefix)
+			},
+		},
+		MatchPrefixValue: prefix,
+	}
+}
+
+func Test_newCommandIndex(t *testing.T) {
+	collection := []Command{
+		newDummyPrefixedCommand("echo", ".echo"),
+		&DummyCommand{IdentifierValue: "func"},
+		newDummyPrefixedCommand("empty", ""),
+		newDummyPrefixedCommand("e", ".e"),
+	}
+
+	index := newCommandIndex(collection)
+
+	if !reflect.DeepEqual(index.unindexed, []int{1, 2}) {
+		t.Errorf("Unexpected unindexed positions: %#v.", index.unindexed)
+	}
+
+	node := index.root
+	for _, b := range []byte(".e") {
+		node = node.children[b]
+		if node == nil {
+			t.Fatal("Prefix is not indexed.")
+		}
+	}
+	if !reflect.DeepEqual(node.positions, []int{3}) {
+		t.Errorf("Unexpected positions: %#v.", node.positions)
+	}
+}
+
+func Test_commandIndex_candidates(t *testing.T) {
+	collection := []Command{
+		newDummyPrefixedCommand("echo", ".echo"),
+		&DummyCommand{IdentifierValue: "func"},
+		newDummyPrefixedCommand("e", ".e"),
+		newDummyPrefixedCommand("weather", ".weather"),
+	}
+	index := newCommandIndex(collection)
+
+	testSets := []struct {
+		message  string
+		expected []int
+	}{
+		{
+			message:  ".echo foo",
+			expected: []int{0, 1, 2},
+		},
+		{
+			message:  ".e",
+			expected: []int{1, 2},
+		},
+		{
+			message:  ".weather",
+			expected: []int{1, 3},
+		},
+		{
+			message:  "Hello",
+			expected: []int{1},
+		},
+	}
+
+	for i, tt := range testSets {
+		candidates := index.candidates(tt.message, nil)
+		if !reflect.DeepEqual(candidates, tt.expected) {
+			t.Errorf("Unexpected candidates on test #%d: %#v.", i+1, candidates)
+		}
+	}
+}
+
+func TestCommands_FindFirstMatched_Indexed(t *testing.T) {
+	props, err := NewCommandPropsBuilder().
+		BotType("dummy").
+		Identifier("echo").
+		Instruction("dummy").
+		MatchPattern(regexp.MustCompile(`^\.echo\b`)).
+		Func(nil).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	echo, _ := buildCommand(context.TODO(), props, &DummyConfigWatcher{})
+
+	if echo.(PrefixedCommand).MatchPrefix() != ".echo" {
+		t.Fatalf("Unexpected prefix is set: %s.", echo.(PrefixedCommand).MatchPrefix())
+	}
+
+	catchAll := &DummyCommand{
+		IdentifierValue: "catchAll",
+		MatchFunc: func(_ Input) bool {
+			return true
+		},
+	}
+
+	commands := NewCommands()
+	commands.Append(catchAll)
+	commands.Append(echo)
+
+	// The earlier registered Command is still prioritized.
+	if found := commands.FindFirstMatched(&DummyInput{MessageValue: ".echo foo"}); found != catchAll {
+		t.Errorf("Unexpected command is returned: %#v.", found)
+	}
+
+	commands = NewCommands()
+	commands.Append(echo)
+	commands.Append(catchAll)
+
+	if found := commands.FindFirstMatched(&DummyInput{MessageValue: ".echo foo"}); found != echo {
+		t.Errorf("Unexpected command is returned: %#v.", found)
+	}
+
+	if found := commands.FindFirstMatched(&DummyInput{MessageValue: "Hello"}); found != catchAll {
+		t.Errorf("Unexpected command is returned: %#v.", found)
+	}
+}
+
+func Test_newCommandIndex_WrappedCommand(t *testing.T) {
+	collection := []Command{
+		&kvCommand{Command: newDummyPrefixedCommand("echo", ".echo")},
+		&kvCommand{Command: &DummyCommand{IdentifierValue: "func"}},
+	}
+
+	index := newCommandIndex(collection)
+	candidates := index.candidates(".echo", nil)
+	if len(candidates) != 2 || candidates[0] != 0 || candidates[1] != 1 {
+		t.Errorf("Unexpected candidates are returned: %#v.", candidates)
+	}
+
+	candidates = index.candidates("Hello", nil)
+	if len(candidates) != 1 || candidates[0] != 1 {
+		t.Errorf("Unexpected candidates are returned: %#v.", candidates)
+	}
+}
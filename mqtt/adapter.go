@@ -0,0 +1,292 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"strings"
+	"time"
+)
+
+const (
+	// MQTT is a dedicated sarah.BotType for MQTT integration.
+	MQTT sarah.BotType = "mqtt"
+)
+
+// ErrNoResponseTopic is returned when neither the output's destination nor Config.ResponseTopic tells where to publish.
+var ErrNoResponseTopic = errors.New("response topic is not given")
+
+// topicPlaceholder is replaced with the topic of the received message in Config.ResponseTopic.
+const topicPlaceholder = "{topic}"
+
+// Message represents an MQTT message.
+type Message struct {
+	// Topic is the topic the message is published to.
+	Topic string
+
+	// Payload is the message payload.
+	Payload []byte
+
+	// QoS is the quality of service level of the message.
+	QoS byte
+
+	// Retained tells if the message is or should be retained by the broker.
+	Retained bool
+
+	// ResponseTopic is the response topic property of MQTT 5. This is empty when the publisher does not specify one.
+	ResponseTopic string
+}
+
+// ConnectOptions contains the settings that Client.Connect receives.
+type ConnectOptions struct {
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration
+
+	// TLSConfig is nil when Config.TLS is not given.
+	TLSConfig *tls.Config
+}
+
+// Client defines an interface of an MQTT client that Adapter depends on.
+type Client interface {
+	// Connect establishes a connection to the broker with the given options.
+	Connect(ctx context.Context, options *ConnectOptions) error
+
+	// Subscribe starts subscribing to the given topic filter and passes each received message to the given handler.
+	Subscribe(topic string, qos byte, handler func(*Message)) error
+
+	// Unsubscribe stops subscribing to the given topic filters.
+	Unsubscribe(topics ...string) error
+
+	// Publish publishes the given message.
+	Publish(ctx context.Context, message *Message) error
+
+	// Disconnect closes the connection.
+	Disconnect()
+}
+
+// Destination represents where to publish an output.
+type Destination struct {
+	// Topic is the topic to publish to. When this is empty, Config.ResponseTopic is used.
+	Topic string
+}
+
+var _ sarah.OutputDestination = (*Destination)(nil)
+
+// Input is a sarah.Input implementation that represents a received MQTT message.
+type Input struct {
+	message    *Message
+	receivedAt time.Time
+	replyTo    *Destination
+}
+
+var _ sarah.Input = (*Input)(nil)
+
+// SenderKey returns the topic of the message so the messages on the same topic share the same conversational context.
+func (i *Input) SenderKey() string {
+	return i.message.Topic
+}
+
+// Message returns the message payload as a string.
+func (i *Input) Message() string {
+	return string(i.message.Payload)
+}
+
+// SentAt returns the time when the message is received since an MQTT message does not carry its timestamp.
+func (i *Input) SentAt() time.Time {
+	return i.receivedAt
+}
+
+// ReplyTo returns *Destination that publishes to the response topic of the message or to Config.ResponseTopic when the message has none.
+func (i *Input) ReplyTo() sarah.OutputDestination {
+	return i.replyTo
+}
+
+// Payload returns the received MQTT message.
+func (i *Input) Payload() *Message {
+	return i.message
+}
+
+// Adapter is a sarah.Adapter implementation for MQTT.
+type Adapter struct {
+	config *Config
+	client Client
+}
+
+var _ sarah.Adapter = (*Adapter)(nil)
+
+// NewAdapter creates and returns a new Adapter instance.
+func NewAdapter(config *Config, client Client) (*Adapter, error) {
+	if client == nil {
+		return nil, errors.New("client is not given")
+	}
+
+	if len(config.Topics) == 0 {
+		return nil, errors.New("no topic is given")
+	}
+
+	if config.QoS > 2 {
+		return nil, fmt.Errorf("invalid QoS is given: %d", config.QoS)
+	}
+
+	return &Adapter{
+		config: config,
+		client: client,
+	}, nil
+}
+
+// BotType returns the sarah.BotType declared by Config.BotType or MQTT by default.
+func (adapter *Adapter) BotType() sarah.BotType {
+	if adapter.config.BotType == "" {
+		return MQTT
+	}
+	return adapter.config.BotType
+}
+
+// Run connects to the broker and subscribes to the topics until the given context is canceled.
+// A failure to connect or to subscribe is notified as sarah.BotNonContinuableError.
+func (adapter *Adapter) Run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
+	options, err := adapter.connectOptions()
+	if err != nil {
+		notifyErr(sarah.NewBotNonContinuableError(err.Error()))
+		return
+	}
+
+	err = adapter.client.Connect(ctx, options)
+	if err != nil {
+		notifyErr(sarah.NewBotNonContinuableError(fmt.Sprintf("failed to connect to %s: %s", options.BrokerURL, err.Error())))
+		return
+	}
+	defer adapter.client.Disconnect()
+
+	handler := func(message *Message) {
+		err := enqueueInput(adapter.toInput(message))
+		if err != nil {
+			logger.Warnf("Failed to pass a message on %s to Sarah. Dropping: %+v", message.Topic, err)
+		}
+	}
+
+	var subscribed []string
+	defer func() {
+		if len(subscribed) == 0 {
+			return
+		}
+		err := adapter.client.Unsubscribe(subscribed...)
+		if err != nil {
+			logger.Errorf("Failed to unsubscribe: %+v", err)
+		}
+	}()
+
+	for _, topic := range adapter.config.Topics {
+		err := adapter.client.Subscribe(topic, adapter.config.QoS, handler)
+		if err != nil {
+			notifyErr(sarah.NewBotNonContinuableError(fmt.Sprintf("failed to subscribe to %s: %s", topic, err.Error())))
+			return
+		}
+		subscribed = append(subscribed, topic)
+	}
+
+	<-ctx.Done()
+}
+
+func (adapter *Adapter) connectOptions() (*ConnectOptions, error) {
+	options := &ConnectOptions{
+		BrokerURL: adapter.config.BrokerURL,
+		ClientID:  adapter.config.ClientID,
+		Username:  adapter.config.Username,
+		Password:  adapter.config.Password,
+		KeepAlive: adapter.config.KeepAlive,
+	}
+
+	if adapter.config.TLS != nil {
+		tlsConfig, err := NewTLSConfig(adapter.config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		options.TLSConfig = tlsConfig
+	}
+
+	return options, nil
+}
+
+func (adapter *Adapter) toInput(message *Message) *Input {
+	topic := message.ResponseTopic
+	if topic == "" {
+		topic = strings.ReplaceAll(adapter.config.ResponseTopic, topicPlaceholder, message.Topic)
+	}
+
+	return &Input{
+		message:    message,
+		receivedAt: time.Now(),
+		replyTo:    &Destination{Topic: topic},
+	}
+}
+
+// SendMessage publishes the given output with Config.QoS and Config.Retain.
+// The content can be *Message for full control, []byte, string, or any value that can be encoded to JSON.
+// The destination can be *Destination or a topic as a string.
+func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
+	message, err := adapter.toMessage(output)
+	if err != nil {
+		logger.Errorf("Failed to build a message from %#v: %+v", output, err)
+		return
+	}
+
+	err = adapter.client.Publish(ctx, message)
+	if err != nil {
+		logger.Errorf("Failed to publish a message to %s: %+v", message.Topic, err)
+	}
+}
+
+func (adapter *Adapter) toMessage(output sarah.Output) (*Message, error) {
+	message := &Message{
+		QoS:      adapter.config.QoS,
+		Retained: adapter.config.Retain,
+	}
+	switch content := output.Content().(type) {
+	case *Message:
+		copied := *content
+		message = &copied
+
+	case []byte:
+		message.Payload = content
+
+	case string:
+		message.Payload = []byte(content)
+
+	default:
+		b, err := json.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode content: %w", err)
+		}
+		message.Payload = b
+
+	}
+
+	if message.Topic == "" {
+		switch dest := output.Destination().(type) {
+		case *Destination:
+			message.Topic = dest.Topic
+
+		case string:
+			message.Topic = dest
+
+		}
+	}
+
+	if message.Topic == "" && !strings.Contains(adapter.config.ResponseTopic, topicPlaceholder) {
+		// A response topic with the placeholder can not be resolved without the received message.
+		message.Topic = adapter.config.ResponseTopic
+	}
+	if message.Topic == "" {
+		return nil, ErrNoResponseTopic
+	}
+
+	return message, nil
+}
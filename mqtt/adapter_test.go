@@ -0,0 +1,329 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+)
+
+type DummyClient struct {
+	ConnectFunc     func(context.Context, *ConnectOptions) error
+	SubscribeFunc   func(string, byte, func(*Message)) error
+	UnsubscribeFunc func(...string) error
+	PublishFunc     func(context.Context, *Message) error
+	DisconnectFunc  func()
+}
+
+var _ Client = (*DummyClient)(nil)
+
+func (c *DummyClient) Connect(ctx context.Context, options *ConnectOptions) error {
+	return c.ConnectFunc(ctx, options)
+}
+
+func (c *DummyClient) Subscribe(topic string, qos byte, handler func(*Message)) error {
+	return c.SubscribeFunc(topic, qos, handler)
+}
+
+func (c *DummyClient) Unsubscribe(topics ...string) error {
+	return c.UnsubscribeFunc(topics...)
+}
+
+func (c *DummyClient) Publish(ctx context.Context, message *Message) error {
+	return c.PublishFunc(ctx, message)
+}
+
+func (c *DummyClient) Disconnect() {
+	c.DisconnectFunc()
+}
+
+func TestNewAdapter(t *testing.T) {
+	config := &Config{Topics: []string{"home/+/sensor"}, QoS: 1}
+	client := &DummyClient{}
+
+	adapter, err := NewAdapter(config, client)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if adapter.config != config || adapter.client != client {
+		t.Errorf("Given values are not set: %#v.", adapter)
+	}
+
+	testSets := []struct {
+		config *Config
+		client Client
+	}{
+		{config: config, client: nil},
+		{config: &Config{}, client: client},
+		{config: &Config{Topics: []string{"foo"}, QoS: 3}, client: client},
+	}
+	for i, testSet := range testSets {
+		_, err := NewAdapter(testSet.config, testSet.client)
+		if err == nil {
+			t.Errorf("Expected error is not returned on test #%d.", i+1)
+		}
+	}
+}
+
+func TestAdapter_BotType(t *testing.T) {
+	adapter := &Adapter{config: &Config{}}
+	if adapter.BotType() != MQTT {
+		t.Errorf("Unexpected BotType is returned: %s.", adapter.BotType())
+	}
+
+	adapter = &Adapter{config: &Config{BotType: "home"}}
+	if adapter.BotType() != "home" {
+		t.Errorf("Unexpected BotType is returned: %s.", adapter.BotType())
+	}
+}
+
+func TestAdapter_Run(t *testing.T) {
+	config := &Config{
+		BrokerURL:     "tls://broker.example.com:8883",
+		ClientID:      "sarah",
+		Topics:        []string{"home/kitchen/sensor", "home/garage/sensor"},
+		QoS:           2,
+		ResponseTopic: "{topic}/response",
+		TLS:           &TLSConfig{ServerName: "broker.example.com"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var connected *ConnectOptions
+	var unsubscribed []string
+	disconnected := false
+	var inputs []sarah.Input
+	client := &DummyClient{
+		ConnectFunc: func(_ context.Context, options *ConnectOptions) error {
+			connected = options
+			return nil
+		},
+		SubscribeFunc: func(topic string, qos byte, handler func(*Message)) error {
+			if qos != 2 {
+				t.Errorf("Unexpected QoS is given: %d.", qos)
+			}
+
+			handler(&Message{Topic: topic, Payload: []byte("23.5")})
+			if topic == "home/garage/sensor" {
+				cancel()
+			}
+			return nil
+		},
+		UnsubscribeFunc: func(topics ...string) error {
+			unsubscribed = topics
+			return nil
+		},
+		DisconnectFunc: func() {
+			disconnected = true
+		},
+	}
+	adapter, _ := NewAdapter(config, client)
+
+	enqueueInput := func(input sarah.Input) error {
+		inputs = append(inputs, input)
+		return nil
+	}
+
+	adapter.Run(ctx, enqueueInput, func(err error) {
+		t.Errorf("Unexpected error is notified: %s.", err.Error())
+	})
+
+	if connected == nil {
+		t.Fatal("Connection is not established.")
+	}
+
+	if connected.BrokerURL != config.BrokerURL || connected.ClientID != config.ClientID {
+		t.Errorf("Unexpected options are given: %#v.", connected)
+	}
+
+	if connected.TLSConfig == nil || connected.TLSConfig.ServerName != "broker.example.com" {
+		t.Errorf("TLS settings are not given: %#v.", connected.TLSConfig)
+	}
+
+	if len(unsubscribed) != 2 {
+		t.Errorf("Subscriptions are not unsubscribed: %#v.", unsubscribed)
+	}
+
+	if !disconnected {
+		t.Error("Connection is not closed.")
+	}
+
+	if len(inputs) != 2 {
+		t.Fatalf("Unexpected number of inputs are passed: %d.", len(inputs))
+	}
+
+	input := inputs[0].(*Input)
+	if input.SenderKey() != "home/kitchen/sensor" {
+		t.Errorf("Unexpected sender key is returned: %s.", input.SenderKey())
+	}
+
+	if input.Message() != "23.5" {
+		t.Errorf("Unexpected message is returned: %s.", input.Message())
+	}
+
+	if input.SentAt().IsZero() {
+		t.Error("Reception time is not set.")
+	}
+
+	if input.Payload().Topic != "home/kitchen/sensor" {
+		t.Errorf("Unexpected payload is returned: %#v.", input.Payload())
+	}
+
+	if dest := input.ReplyTo().(*Destination); dest.Topic != "home/kitchen/sensor/response" {
+		t.Errorf("Unexpected destination is returned: %#v.", dest)
+	}
+}
+
+func TestAdapter_Run_Error(t *testing.T) {
+	t.Run("Connection failure", func(t *testing.T) {
+		client := &DummyClient{
+			ConnectFunc: func(_ context.Context, _ *ConnectOptions) error {
+				return errors.New("connection refused")
+			},
+		}
+		adapter, _ := NewAdapter(&Config{Topics: []string{"foo"}}, client)
+
+		var notified error
+		adapter.Run(context.TODO(), func(_ sarah.Input) error { return nil }, func(err error) {
+			notified = err
+		})
+
+		if _, ok := notified.(*sarah.BotNonContinuableError); !ok {
+			t.Errorf("Unexpected error is notified: %#v.", notified)
+		}
+	})
+
+	t.Run("Subscription failure", func(t *testing.T) {
+		var unsubscribed []string
+		client := &DummyClient{
+			ConnectFunc: func(_ context.Context, _ *ConnectOptions) error {
+				return nil
+			},
+			SubscribeFunc: func(topic string, _ byte, _ func(*Message)) error {
+				if topic == "bar" {
+					return errors.New("not authorized")
+				}
+				return nil
+			},
+			UnsubscribeFunc: func(topics ...string) error {
+				unsubscribed = topics
+				return nil
+			},
+			DisconnectFunc: func() {},
+		}
+		adapter, _ := NewAdapter(&Config{Topics: []string{"foo", "bar"}}, client)
+
+		var notified error
+		adapter.Run(context.TODO(), func(_ sarah.Input) error { return nil }, func(err error) {
+			notified = err
+		})
+
+		if _, ok := notified.(*sarah.BotNonContinuableError); !ok {
+			t.Errorf("Unexpected error is notified: %#v.", notified)
+		}
+
+		if len(unsubscribed) != 1 || unsubscribed[0] != "foo" {
+			t.Errorf("Established subscription is not unsubscribed: %#v.", unsubscribed)
+		}
+	})
+}
+
+func TestAdapter_toInput(t *testing.T) {
+	adapter := &Adapter{config: &Config{ResponseTopic: "responses"}}
+
+	input := adapter.toInput(&Message{Topic: "home/kitchen/light", ResponseTopic: "home/kitchen/light/ack"})
+	if dest := input.ReplyTo().(*Destination); dest.Topic != "home/kitchen/light/ack" {
+		t.Errorf("Response topic of the message should be preferred: %#v.", dest)
+	}
+
+	input = adapter.toInput(&Message{Topic: "home/kitchen/light"})
+	if dest := input.ReplyTo().(*Destination); dest.Topic != "responses" {
+		t.Errorf("ResponseTopic should be used: %#v.", dest)
+	}
+}
+
+func TestAdapter_SendMessage(t *testing.T) {
+	testSets := []struct {
+		config  *Config
+		output  sarah.Output
+		topic   string
+		payload string
+		qos     byte
+	}{
+		{
+			config:  &Config{QoS: 1},
+			output:  sarah.NewOutputMessage(&Destination{Topic: "home/light"}, "on"),
+			topic:   "home/light",
+			payload: "on",
+			qos:     1,
+		},
+		{
+			config:  &Config{QoS: 0},
+			output:  sarah.NewOutputMessage("home/fan", []byte("off")),
+			topic:   "home/fan",
+			payload: "off",
+			qos:     0,
+		},
+		{
+			config:  &Config{QoS: 1, ResponseTopic: "home/report"},
+			output:  sarah.NewOutputMessage(nil, map[string]float64{"temperature": 23.5}),
+			topic:   "home/report",
+			payload: `{"temperature":23.5}`,
+			qos:     1,
+		},
+		{
+			config:  &Config{QoS: 1},
+			output:  sarah.NewOutputMessage(nil, &Message{Topic: "custom", Payload: []byte("value"), QoS: 2}),
+			topic:   "custom",
+			payload: "value",
+			qos:     2,
+		},
+	}
+
+	for i, testSet := range testSets {
+		var published *Message
+		adapter := &Adapter{
+			config: testSet.config,
+			client: &DummyClient{
+				PublishFunc: func(_ context.Context, message *Message) error {
+					published = message
+					return nil
+				},
+			},
+		}
+
+		adapter.SendMessage(context.TODO(), testSet.output)
+
+		if published == nil {
+			t.Fatalf("Message is not published on test #%d.", i+1)
+		}
+
+		if published.Topic != testSet.topic {
+			t.Errorf("Unexpected topic is set on test #%d: %s.", i+1, published.Topic)
+		}
+
+		if string(published.Payload) != testSet.payload {
+			t.Errorf("Unexpected payload is set on test #%d: %s.", i+1, published.Payload)
+		}
+
+		if published.QoS != testSet.qos {
+			t.Errorf("Unexpected QoS is set on test #%d: %d.", i+1, published.QoS)
+		}
+	}
+}
+
+func TestAdapter_SendMessage_WithoutTopic(t *testing.T) {
+	adapter := &Adapter{
+		config: &Config{ResponseTopic: "{topic}/response"},
+		client: &DummyClient{
+			PublishFunc: func(_ context.Context, _ *Message) error {
+				t.Error("Message should not be published without a topic.")
+				return nil
+			},
+		},
+	}
+
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(nil, "foo"))
+}
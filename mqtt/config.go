@@ -0,0 +1,124 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"os"
+	"time"
+)
+
+// ErrInvalidCABundle is returned when the given CA bundle file does not contain any valid PEM encoded certificate.
+var ErrInvalidCABundle = errors.New("no valid certificate is found in CA bundle")
+
+// Config contains some configuration variables for MQTT Adapter.
+type Config struct {
+	// BotType declares the sarah.BotType of the Adapter.
+	// Set a distinct value for each Adapter when multiple Adapters run in the same process.
+	BotType sarah.BotType `json:"bot_type" yaml:"bot_type"`
+
+	// BrokerURL declares the URL of the broker to connect to such as tcp://localhost:1883 or tls://broker.example.com:8883.
+	BrokerURL string `json:"broker_url" yaml:"broker_url"`
+
+	// ClientID declares the client identifier to connect with.
+	ClientID string `json:"client_id" yaml:"client_id"`
+
+	// Username declares the username to authenticate with. Leave this empty when the broker does not require authentication.
+	Username string `json:"username" yaml:"username"`
+
+	// Password declares the password to authenticate with.
+	Password string `json:"password" yaml:"password"`
+
+	// KeepAlive declares the interval to send keep-alive packets.
+	KeepAlive time.Duration `json:"keep_alive" yaml:"keep_alive"`
+
+	// Topics declares the topic filters to subscribe to. Wildcards such as + and # can be used.
+	Topics []string `json:"topics" yaml:"topics"`
+
+	// QoS declares the quality of service level for both the subscriptions and the publications: 0, 1, or 2.
+	QoS byte `json:"qos" yaml:"qos"`
+
+	// Retain declares if the published messages should be retained by the broker.
+	Retain bool `json:"retain" yaml:"retain"`
+
+	// ResponseTopic declares the topic to publish responses to when the received message does not specify its response topic.
+	// "{topic}" in the value is replaced with the topic of the received message, e.g., "{topic}/response".
+	// This is also the default topic for the results of scheduled tasks.
+	ResponseTopic string `json:"response_topic" yaml:"response_topic"`
+
+	// TLS declares the TLS settings to connect to the broker.
+	// When this is nil, the client library's default is used.
+	TLS *TLSConfig `json:"tls" yaml:"tls"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// BrokerURL, ClientID, and Topics are empty at this point as there can not be default values.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank values or override those default values.
+func NewConfig() *Config {
+	return &Config{
+		BotType:       MQTT,
+		BrokerURL:     "",
+		ClientID:      "",
+		KeepAlive:     30 * time.Second,
+		Topics:        []string{},
+		QoS:           1,
+		Retain:        false,
+		ResponseTopic: "",
+		TLS:           nil,
+	}
+}
+
+// TLSConfig contains the TLS settings to connect to the broker.
+type TLSConfig struct {
+	// CABundleFile declares the path to a PEM encoded certificate file.
+	// The certificates are trusted in addition to the system's root CAs.
+	CABundleFile string `json:"ca_bundle_file" yaml:"ca_bundle_file"`
+
+	// CertFile and KeyFile declare the paths to a PEM encoded client certificate and its private key for mutual TLS.
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+
+	// ServerName declares the server name to verify the broker's certificate with.
+	// When this is empty, the host name in BrokerURL is used.
+	ServerName string `json:"server_name" yaml:"server_name"`
+
+	// InsecureSkipVerify skips the verification of the broker's certificate. Use this only for testing purposes.
+	InsecureSkipVerify bool `json:"insecure_skip_verify" yaml:"insecure_skip_verify"`
+}
+
+// NewTLSConfig creates and returns a new *tls.Config with the given TLSConfig.
+func NewTLSConfig(config *TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if config.CABundleFile != "" {
+		pem, err := os.ReadFile(config.CABundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, ErrInvalidCABundle
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,78 @@
+package mqtt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+
+	if config.BotType != MQTT {
+		t.Errorf("Unexpected BotType is set: %s.", config.BotType)
+	}
+
+	if config.QoS != 1 {
+		t.Errorf("Unexpected QoS is set: %d.", config.QoS)
+	}
+
+	if config.Topics == nil {
+		t.Error("Topics should be initialized.")
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		config := &TLSConfig{ServerName: "broker.example.com"}
+
+		tlsConfig, err := NewTLSConfig(config)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if tlsConfig.ServerName != config.ServerName {
+			t.Errorf("Given server name is not set: %s.", tlsConfig.ServerName)
+		}
+
+		if tlsConfig.RootCAs != nil || len(tlsConfig.Certificates) != 0 {
+			t.Errorf("Unexpected certificates are set: %#v.", tlsConfig)
+		}
+	})
+
+	t.Run("Missing CA bundle", func(t *testing.T) {
+		config := &TLSConfig{CABundleFile: filepath.Join(t.TempDir(), "missing.pem")}
+
+		_, err := NewTLSConfig(config)
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+	})
+
+	t.Run("Invalid CA bundle", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "invalid.pem")
+		err := os.WriteFile(file, []byte("invalid"), 0600)
+		if err != nil {
+			t.Fatalf("Failed to write file: %s.", err.Error())
+		}
+
+		_, err = NewTLSConfig(&TLSConfig{CABundleFile: file})
+		if !errors.Is(err, ErrInvalidCABundle) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Missing client certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		config := &TLSConfig{
+			CertFile: filepath.Join(dir, "client.pem"),
+			KeyFile:  filepath.Join(dir, "client.key"),
+		}
+
+		_, err := NewTLSConfig(config)
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+	})
+}
@@ -0,0 +1,33 @@
+// Package mqtt provides a sarah.Adapter implementation that subscribes to MQTT topics to receive sarah.Input
+// and publishes the responses of Commands and the results of ScheduledTasks to response topics.
+// Combined with scheduled tasks, this makes go-sarah usable as a small home-automation or IoT rule bot.
+//
+// To avoid binding this project to a particular MQTT client library, this package depends on the Client interface.
+// Implement it with a thin wrapper of the preferred library such as paho.mqtt.golang as below:
+//
+//	type client struct {
+//		c pahomqtt.Client
+//	}
+//
+//	func (c *client) Connect(_ context.Context, options *mqtt.ConnectOptions) error {
+//		opts := pahomqtt.NewClientOptions().
+//			AddBroker(options.BrokerURL).
+//			SetClientID(options.ClientID).
+//			SetUsername(options.Username).
+//			SetPassword(options.Password).
+//			SetTLSConfig(options.TLSConfig)
+//		c.c = pahomqtt.NewClient(opts)
+//		token := c.c.Connect()
+//		token.Wait()
+//		return token.Error()
+//	}
+//
+//	...
+//
+//	config := mqtt.NewConfig()
+//	config.BrokerURL = "tls://broker.example.com:8883"
+//	config.Topics = []string{"home/+/sensor"}
+//	config.ResponseTopic = "home/{topic}/response"
+//	adapter, _ := mqtt.NewAdapter(config, &client{})
+//	sarah.RegisterBot(sarah.NewBot(adapter))
+package mqtt
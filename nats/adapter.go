@@ -0,0 +1,229 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"time"
+)
+
+const (
+	// NATS is a dedicated sarah.BotType for NATS integration.
+	NATS sarah.BotType = "nats"
+)
+
+// ErrNoOutputSubject is returned when neither the output's destination nor Config.OutputSubject tells where to publish.
+var ErrNoOutputSubject = errors.New("output subject is not given")
+
+// Message represents a NATS message.
+type Message struct {
+	// Subject is the subject the message is published to.
+	Subject string
+
+	// Reply is the subject to reply to. This is empty when the publisher does not expect a reply.
+	Reply string
+
+	// Data is the message payload.
+	Data []byte
+
+	// Header holds the message headers.
+	Header map[string][]string
+}
+
+// Subscription represents a subscription that Conn.Subscribe returns.
+type Subscription interface {
+	// Unsubscribe stops receiving messages.
+	Unsubscribe() error
+}
+
+// Conn defines an interface of a NATS connection that Adapter depends on.
+type Conn interface {
+	// Subscribe starts subscribing to the given subject and passes each received message to the given handler.
+	// When the queue is not empty, the subscription joins the queue group with the given name.
+	Subscribe(subject string, queue string, handler func(*Message)) (Subscription, error)
+
+	// Publish publishes the given message.
+	Publish(message *Message) error
+}
+
+// Destination represents where to publish an output.
+type Destination struct {
+	// Subject is the subject to publish to. When this is empty, Config.OutputSubject is used.
+	Subject string
+}
+
+var _ sarah.OutputDestination = (*Destination)(nil)
+
+// Input is a sarah.Input implementation that represents a received NATS message.
+type Input struct {
+	message    *Message
+	receivedAt time.Time
+	replyTo    *Destination
+}
+
+var _ sarah.Input = (*Input)(nil)
+
+// SenderKey returns the subject of the message so the messages on the same subject share the same conversational context.
+func (i *Input) SenderKey() string {
+	return i.message.Subject
+}
+
+// Message returns the message payload as a string.
+func (i *Input) Message() string {
+	return string(i.message.Data)
+}
+
+// SentAt returns the time when the message is received since a NATS message does not carry its timestamp.
+func (i *Input) SentAt() time.Time {
+	return i.receivedAt
+}
+
+// ReplyTo returns *Destination that publishes to the reply subject of the message or to Config.OutputSubject when the message has none.
+func (i *Input) ReplyTo() sarah.OutputDestination {
+	return i.replyTo
+}
+
+// Payload returns the received NATS message.
+// Use this in a Command to refer to the headers or the raw payload.
+func (i *Input) Payload() *Message {
+	return i.message
+}
+
+// Adapter is a sarah.Adapter implementation for NATS.
+type Adapter struct {
+	config *Config
+	conn   Conn
+}
+
+var _ sarah.Adapter = (*Adapter)(nil)
+
+// NewAdapter creates and returns a new Adapter instance.
+func NewAdapter(config *Config, conn Conn) (*Adapter, error) {
+	if conn == nil {
+		return nil, errors.New("connection is not given")
+	}
+
+	if len(config.Subjects) == 0 {
+		return nil, errors.New("no subject is given")
+	}
+
+	return &Adapter{
+		config: config,
+		conn:   conn,
+	}, nil
+}
+
+// BotType returns the sarah.BotType declared by Config.BotType or NATS by default.
+func (adapter *Adapter) BotType() sarah.BotType {
+	if adapter.config.BotType == "" {
+		return NATS
+	}
+	return adapter.config.BotType
+}
+
+// Run subscribes to the subjects until the given context is canceled.
+// A failure to subscribe is notified as sarah.BotNonContinuableError since the Bot can not receive all expected messages.
+func (adapter *Adapter) Run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
+	handler := func(message *Message) {
+		err := enqueueInput(adapter.toInput(message))
+		if err != nil {
+			logger.Warnf("Failed to pass a message on %s to Sarah. Dropping: %+v", message.Subject, err)
+		}
+	}
+
+	var subscriptions []Subscription
+	defer func() {
+		for _, subscription := range subscriptions {
+			err := subscription.Unsubscribe()
+			if err != nil {
+				logger.Errorf("Failed to unsubscribe: %+v", err)
+			}
+		}
+	}()
+
+	for _, subject := range adapter.config.Subjects {
+		subscription, err := adapter.conn.Subscribe(subject, adapter.config.QueueGroup, handler)
+		if err != nil {
+			notifyErr(sarah.NewBotNonContinuableError(fmt.Sprintf("failed to subscribe to %s: %s", subject, err.Error())))
+			return
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	<-ctx.Done()
+}
+
+func (adapter *Adapter) toInput(message *Message) *Input {
+	subject := message.Reply
+	if subject == "" {
+		subject = adapter.config.OutputSubject
+	}
+
+	return &Input{
+		message:    message,
+		receivedAt: time.Now(),
+		replyTo:    &Destination{Subject: subject},
+	}
+}
+
+// SendMessage publishes the given output.
+// The content can be *Message for full control, []byte, string, or any value that can be encoded to JSON.
+// The destination can be *Destination or a subject as a string.
+func (adapter *Adapter) SendMessage(_ context.Context, output sarah.Output) {
+	message, err := adapter.toMessage(output)
+	if err != nil {
+		logger.Errorf("Failed to build a message from %#v: %+v", output, err)
+		return
+	}
+
+	err = adapter.conn.Publish(message)
+	if err != nil {
+		logger.Errorf("Failed to publish a message to %s: %+v", message.Subject, err)
+	}
+}
+
+func (adapter *Adapter) toMessage(output sarah.Output) (*Message, error) {
+	message := &Message{}
+	switch content := output.Content().(type) {
+	case *Message:
+		copied := *content
+		message = &copied
+
+	case []byte:
+		message.Data = content
+
+	case string:
+		message.Data = []byte(content)
+
+	default:
+		b, err := json.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode content: %w", err)
+		}
+		message.Data = b
+
+	}
+
+	if message.Subject == "" {
+		switch dest := output.Destination().(type) {
+		case *Destination:
+			message.Subject = dest.Subject
+
+		case string:
+			message.Subject = dest
+
+		}
+	}
+
+	if message.Subject == "" {
+		message.Subject = adapter.config.OutputSubject
+	}
+	if message.Subject == "" {
+		return nil, ErrNoOutputSubject
+	}
+
+	return message, nil
+}
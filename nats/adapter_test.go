@@ -0,0 +1,271 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+)
+
+type DummyConn struct {
+	SubscribeFunc func(string, string, func(*Message)) (Subscription, error)
+	PublishFunc   func(*Message) error
+}
+
+var _ Conn = (*DummyConn)(nil)
+
+func (c *DummyConn) Subscribe(subject string, queue string, handler func(*Message)) (Subscription, error) {
+	return c.SubscribeFunc(subject, queue, handler)
+}
+
+func (c *DummyConn) Publish(message *Message) error {
+	return c.PublishFunc(message)
+}
+
+type DummySubscription struct {
+	UnsubscribeFunc func() error
+}
+
+var _ Subscription = (*DummySubscription)(nil)
+
+func (s *DummySubscription) Unsubscribe() error {
+	return s.UnsubscribeFunc()
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+
+	if config.BotType != NATS {
+		t.Errorf("Unexpected BotType is set: %s.", config.BotType)
+	}
+
+	if config.Subjects == nil {
+		t.Error("Subjects should be initialized.")
+	}
+}
+
+func TestNewAdapter(t *testing.T) {
+	config := &Config{Subjects: []string{"commands.>"}}
+	conn := &DummyConn{}
+
+	adapter, err := NewAdapter(config, conn)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if adapter.config != config || adapter.conn != conn {
+		t.Errorf("Given values are not set: %#v.", adapter)
+	}
+
+	_, err = NewAdapter(config, nil)
+	if err == nil {
+		t.Error("Expected error is not returned without connection.")
+	}
+
+	_, err = NewAdapter(&Config{}, conn)
+	if err == nil {
+		t.Error("Expected error is not returned without subject.")
+	}
+}
+
+func TestAdapter_BotType(t *testing.T) {
+	adapter := &Adapter{config: &Config{}}
+	if adapter.BotType() != NATS {
+		t.Errorf("Unexpected BotType is returned: %s.", adapter.BotType())
+	}
+
+	adapter = &Adapter{config: &Config{BotType: "service"}}
+	if adapter.BotType() != "service" {
+		t.Errorf("Unexpected BotType is returned: %s.", adapter.BotType())
+	}
+}
+
+func TestAdapter_Run(t *testing.T) {
+	config := &Config{
+		Subjects:      []string{"commands.foo", "commands.bar"},
+		QueueGroup:    "sarah",
+		OutputSubject: "results",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	unsubscribed := 0
+	var subjects []string
+	var inputs []sarah.Input
+	conn := &DummyConn{
+		SubscribeFunc: func(subject string, queue string, handler func(*Message)) (Subscription, error) {
+			if queue != "sarah" {
+				t.Errorf("Unexpected queue group is given: %s.", queue)
+			}
+			subjects = append(subjects, subject)
+
+			handler(&Message{Subject: subject, Reply: "_INBOX.1", Data: []byte(".echo foo")})
+			if len(subjects) == len(config.Subjects) {
+				cancel()
+			}
+
+			return &DummySubscription{
+				UnsubscribeFunc: func() error {
+					unsubscribed++
+					return nil
+				},
+			}, nil
+		},
+	}
+	adapter, _ := NewAdapter(config, conn)
+
+	enqueueInput := func(input sarah.Input) error {
+		inputs = append(inputs, input)
+		return nil
+	}
+
+	adapter.Run(ctx, enqueueInput, func(err error) {
+		t.Errorf("Unexpected error is notified: %s.", err.Error())
+	})
+
+	if len(subjects) != 2 {
+		t.Errorf("Unexpected subscriptions are made: %#v.", subjects)
+	}
+
+	if unsubscribed != 2 {
+		t.Errorf("Subscriptions are not unsubscribed: %d.", unsubscribed)
+	}
+
+	if len(inputs) != 2 {
+		t.Fatalf("Unexpected number of inputs are passed: %d.", len(inputs))
+	}
+
+	input := inputs[0].(*Input)
+	if input.SenderKey() != "commands.foo" {
+		t.Errorf("Unexpected sender key is returned: %s.", input.SenderKey())
+	}
+
+	if input.Message() != ".echo foo" {
+		t.Errorf("Unexpected message is returned: %s.", input.Message())
+	}
+
+	if input.SentAt().IsZero() {
+		t.Error("Reception time is not set.")
+	}
+
+	if input.Payload().Reply != "_INBOX.1" {
+		t.Errorf("Unexpected payload is returned: %#v.", input.Payload())
+	}
+
+	if dest := input.ReplyTo().(*Destination); dest.Subject != "_INBOX.1" {
+		t.Errorf("Unexpected destination is returned: %#v.", dest)
+	}
+}
+
+func TestAdapter_Run_SubscriptionError(t *testing.T) {
+	config := &Config{Subjects: []string{"commands.foo", "commands.bar"}}
+	unsubscribed := 0
+	conn := &DummyConn{
+		SubscribeFunc: func(subject string, _ string, _ func(*Message)) (Subscription, error) {
+			if subject == "commands.bar" {
+				return nil, errors.New("permission denied")
+			}
+			return &DummySubscription{
+				UnsubscribeFunc: func() error {
+					unsubscribed++
+					return nil
+				},
+			}, nil
+		},
+	}
+	adapter, _ := NewAdapter(config, conn)
+
+	var notified error
+	adapter.Run(context.TODO(), func(_ sarah.Input) error { return nil }, func(err error) {
+		notified = err
+	})
+
+	if _, ok := notified.(*sarah.BotNonContinuableError); !ok {
+		t.Errorf("Unexpected error is notified: %#v.", notified)
+	}
+
+	if unsubscribed != 1 {
+		t.Errorf("Established subscription is not unsubscribed: %d.", unsubscribed)
+	}
+}
+
+func TestAdapter_toInput(t *testing.T) {
+	adapter := &Adapter{config: &Config{OutputSubject: "results"}}
+
+	input := adapter.toInput(&Message{Subject: "events.created", Data: []byte("foo")})
+
+	if dest := input.ReplyTo().(*Destination); dest.Subject != "results" {
+		t.Errorf("OutputSubject should be used without reply subject: %#v.", dest)
+	}
+}
+
+func TestAdapter_SendMessage(t *testing.T) {
+	testSets := []struct {
+		output  sarah.Output
+		subject string
+		data    string
+	}{
+		{
+			output:  sarah.NewOutputMessage(&Destination{Subject: "_INBOX.1"}, "done"),
+			subject: "_INBOX.1",
+			data:    "done",
+		},
+		{
+			output:  sarah.NewOutputMessage("audit", []byte("raw")),
+			subject: "audit",
+			data:    "raw",
+		},
+		{
+			output:  sarah.NewOutputMessage(nil, map[string]int{"count": 1}),
+			subject: "results",
+			data:    `{"count":1}`,
+		},
+		{
+			output:  sarah.NewOutputMessage(&Destination{Subject: "ignored"}, &Message{Subject: "custom", Data: []byte("value")}),
+			subject: "custom",
+			data:    "value",
+		},
+	}
+
+	for i, testSet := range testSets {
+		var published *Message
+		adapter := &Adapter{
+			config: &Config{OutputSubject: "results"},
+			conn: &DummyConn{
+				PublishFunc: func(message *Message) error {
+					published = message
+					return nil
+				},
+			},
+		}
+
+		adapter.SendMessage(context.TODO(), testSet.output)
+
+		if published == nil {
+			t.Fatalf("Message is not published on test #%d.", i+1)
+		}
+
+		if published.Subject != testSet.subject {
+			t.Errorf("Unexpected subject is set on test #%d: %s.", i+1, published.Subject)
+		}
+
+		if string(published.Data) != testSet.data {
+			t.Errorf("Unexpected data is set on test #%d: %s.", i+1, published.Data)
+		}
+	}
+}
+
+func TestAdapter_SendMessage_WithoutSubject(t *testing.T) {
+	adapter := &Adapter{
+		config: &Config{},
+		conn: &DummyConn{
+			PublishFunc: func(_ *Message) error {
+				t.Error("Message should not be published without a subject.")
+				return nil
+			},
+		},
+	}
+
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(nil, "foo"))
+}
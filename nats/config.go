@@ -0,0 +1,36 @@
+package nats
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// Config contains some configuration variables for NATS Adapter.
+type Config struct {
+	// BotType declares the sarah.BotType of the Adapter.
+	// Set a distinct value for each Adapter when multiple Adapters run in the same process.
+	BotType sarah.BotType `json:"bot_type" yaml:"bot_type"`
+
+	// Subjects declares the subjects to subscribe to. Wildcards are supported as long as the client library supports them.
+	Subjects []string `json:"subjects" yaml:"subjects"`
+
+	// QueueGroup declares the queue group to join.
+	// Bot instances in the same queue group share the load; each message is delivered to only one of them.
+	// When this is empty, every instance receives every message.
+	QueueGroup string `json:"queue_group" yaml:"queue_group"`
+
+	// OutputSubject declares the subject to publish outputs to when the received message has no reply subject,
+	// and the subject to publish the results of scheduled tasks to when a task does not specify one.
+	OutputSubject string `json:"output_subject" yaml:"output_subject"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Subjects and OutputSubject are empty at this point as there can not be default values.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank values or override those default values.
+func NewConfig() *Config {
+	return &Config{
+		BotType:       NATS,
+		Subjects:      []string{},
+		QueueGroup:    "",
+		OutputSubject: "",
+	}
+}
@@ -0,0 +1,30 @@
+// Package nats provides a sarah.Adapter implementation that subscribes to NATS subjects to receive sarah.Input
+// and publishes the responses of Commands to the reply subjects.
+// Subscriptions can join a queue group so multiple bot instances share the load,
+// which enables lightweight service-to-service command execution over NATS.
+//
+// To avoid binding this project to a particular NATS client library, this package depends on Conn and Subscription interfaces.
+// Implement them with a thin wrapper of the preferred library such as nats.go as below:
+//
+//	type conn struct {
+//		nc *natsgo.Conn
+//	}
+//
+//	func (c *conn) Subscribe(subject, queue string, handler func(*nats.Message)) (nats.Subscription, error) {
+//		return c.nc.QueueSubscribe(subject, queue, func(m *natsgo.Msg) {
+//			handler(&nats.Message{Subject: m.Subject, Reply: m.Reply, Data: m.Data})
+//		})
+//	}
+//
+//	func (c *conn) Publish(message *nats.Message) error {
+//		return c.nc.PublishMsg(&natsgo.Msg{Subject: message.Subject, Data: message.Data})
+//	}
+//
+//	...
+//
+//	config := nats.NewConfig()
+//	config.Subjects = []string{"commands.>"}
+//	config.QueueGroup = "sarah"
+//	adapter, _ := nats.NewAdapter(config, &conn{nc: nc})
+//	sarah.RegisterBot(sarah.NewBot(adapter))
+package nats
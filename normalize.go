@@ -0,0 +1,169 @@
+package sarah
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// NormalizableInput is an optional interface that an Input implementation can satisfy to let Sarah normalize its message before Command matching.
+// See NormalizationConfig for the available normalizations.
+// An Input that does not satisfy this interface is handled with its message as-is.
+type NormalizableInput interface {
+	Input
+
+	// SetMessage replaces the message with the given normalized one.
+	SetMessage(message string)
+}
+
+// NormalizationConfig declares the normalizations applied to the message of every Input before Command matching.
+// Each normalization is disabled by default so the existing Commands keep receiving the messages as-is.
+//
+// The message recorded via InputSink and TranscriptStore is the original one, and the normalization requires the Input to satisfy NormalizableInput.
+type NormalizationConfig struct {
+	// TrimZeroWidth declares whether to remove zero-width characters such as U+200B ZERO WIDTH SPACE and U+FEFF BYTE ORDER MARK,
+	// which are often mixed in a copied text.
+	TrimZeroWidth bool `json:"trim_zero_width" yaml:"trim_zero_width"`
+
+	// UnifyWidth declares whether to convert full-width alphanumerics, symbols, and spaces to their half-width forms,
+	// and half-width katakana to their full-width forms.
+	UnifyWidth bool `json:"unify_width" yaml:"unify_width"`
+
+	// NormalizeQuotes declares whether to replace smart quotes such as “ and ’ with the plain ones such as " and '.
+	NormalizeQuotes bool `json:"normalize_quotes" yaml:"normalize_quotes"`
+
+	// StripLinkMarkup declares whether to replace Slack-style link markups with their labels.
+	// <http://example.com|Example> becomes Example, and <http://example.com> becomes http://example.com.
+	// Mentions such as <@U123> are kept as-is.
+	StripLinkMarkup bool `json:"strip_link_markup" yaml:"strip_link_markup"`
+}
+
+var (
+	// zeroWidthReplacer removes ZERO WIDTH SPACE, ZERO WIDTH NON-JOINER, ZERO WIDTH JOINER, WORD JOINER, and ZERO WIDTH NO-BREAK SPACE.
+	zeroWidthReplacer = strings.NewReplacer("\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "")
+
+	quoteReplacer = strings.NewReplacer(
+		"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+		"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`,
+	)
+
+	linkMarkupPattern = regexp.MustCompile(`<((?:https?|mailto):[^|>\s]+)(?:\|([^>]*))?>`)
+
+	// halfWidthKatakana holds the full-width forms of U+FF61 to U+FF9F in order.
+	halfWidthKatakana = []rune("。「」、・ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン゛゜")
+)
+
+// Normalize applies the enabled normalizations to the given message.
+func (c *NormalizationConfig) Normalize(message string) string {
+	if c.TrimZeroWidth {
+		message = zeroWidthReplacer.Replace(message)
+	}
+
+	if c.UnifyWidth {
+		message = unifyWidth(message)
+	}
+
+	if c.NormalizeQuotes {
+		message = quoteReplacer.Replace(message)
+	}
+
+	if c.StripLinkMarkup {
+		message = linkMarkupPattern.ReplaceAllStringFunc(message, func(markup string) string {
+			matches := linkMarkupPattern.FindStringSubmatch(markup)
+			if matches[2] != "" {
+				return matches[2]
+			}
+			return matches[1]
+		})
+	}
+
+	return message
+}
+
+// unifyWidth converts full-width ASCII characters to half-width, and half-width katakana to full-width.
+// A half-width katakana followed by a half-width (semi-)voiced sound mark is combined into a single character such as ガ and パ.
+func unifyWidth(message string) string {
+	var builder strings.Builder
+	builder.Grow(len(message))
+
+	for i := 0; i < len(message); {
+		r, size := utf8.DecodeRuneInString(message[i:])
+		i += size
+
+		switch {
+		case r == '　':
+			// IDEOGRAPHIC SPACE
+			builder.WriteRune(' ')
+
+		case r >= '！' && r <= '～':
+			// FULLWIDTH EXCLAMATION MARK to FULLWIDTH TILDE correspond to the printable ASCII characters.
+			builder.WriteRune(r - 0xfee0)
+
+		case r >= '｡' && r <= 'ﾟ':
+			converted := halfWidthKatakana[r-'｡']
+			if i < len(message) {
+				next, nextSize := utf8.DecodeRuneInString(message[i:])
+				if combined, ok := combineSoundMark(converted, next); ok {
+					converted = combined
+					i += nextSize
+				}
+			}
+			builder.WriteRune(converted)
+
+		default:
+			builder.WriteRune(r)
+
+		}
+	}
+
+	return builder.String()
+}
+
+// combineSoundMark combines the given full-width katakana with the following half-width voiced or semi-voiced sound mark.
+func combineSoundMark(kana rune, mark rune) (rune, bool) {
+	switch mark {
+	case 'ﾞ':
+		// HALFWIDTH KATAKANA VOICED SOUND MARK
+		if kana == 'ウ' {
+			return 'ヴ', true
+		}
+		if strings.ContainsRune("カキクケコサシスセソタチツテトハヒフヘホ", kana) {
+			return kana + 1, true
+		}
+
+	case 'ﾟ':
+		// HALFWIDTH KATAKANA SEMI-VOICED SOUND MARK
+		if strings.ContainsRune("ハヒフヘホ", kana) {
+			return kana + 2, true
+		}
+
+	}
+	return kana, false
+}
+
+// normalizingInputReceiver normalizes the message of each Input with the NormalizationConfig returned by the given function before passing the Input to receive.
+func normalizingInputReceiver(normalization func() *NormalizationConfig, receive func(Input) error) func(Input) error {
+	return func(input Input) error {
+		config := normalization()
+		if config == nil {
+			return receive(input)
+		}
+
+		// HelpInput and AbortInput are created by the Adapter from the original Input, which holds the message.
+		original := input
+		switch typed := input.(type) {
+		case *HelpInput:
+			original = typed.OriginalInput
+
+		case *AbortInput:
+			original = typed.OriginalInput
+
+		}
+
+		if normalizable, ok := original.(NormalizableInput); ok {
+			normalizable.SetMessage(config.Normalize(normalizable.Message()))
+		}
+
+		return receive(input)
+	}
+}
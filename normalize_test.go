@@ -0,0 +1,121 @@
+package sarah
+
+import (
+	"testing"
+)
+
+type DummyNormalizableInput struct {
+	DummyInput
+}
+
+func (i *DummyNormalizableInput) SetMessage(message string) {
+	i.MessageValue = message
+}
+
+func TestNormalizationConfig_Normalize(t *testing.T) {
+	testSets := []struct {
+		config   *NormalizationConfig
+		message  string
+		expected string
+	}{
+		{
+			config:   &NormalizationConfig{},
+			message:  "ｈｅｌｌｏ\u200b “world”",
+			expected: "ｈｅｌｌｏ\u200b “world”",
+		},
+		{
+			config:   &NormalizationConfig{TrimZeroWidth: true},
+			message:  "\ufeff.he\u200bl\u200cl\u200do\u2060",
+			expected: ".hello",
+		},
+		{
+			config:   &NormalizationConfig{UnifyWidth: true},
+			message:  "．ｈｅｌｌｏ　ＷＯＲＬＤ！１２３",
+			expected: ".hello WORLD!123",
+		},
+		{
+			config:   &NormalizationConfig{UnifyWidth: true},
+			message:  "ｶﾀｶﾅ ｶﾞｷﾞﾊﾟﾋﾟｳﾞ ｱﾞ｡",
+			expected: "カタカナ ガギパピヴ ア゛。",
+		},
+		{
+			config:   &NormalizationConfig{NormalizeQuotes: true},
+			message:  "“hello” ‘world’ it’s",
+			expected: `"hello" 'world' it's`,
+		},
+		{
+			config:   &NormalizationConfig{StripLinkMarkup: true},
+			message:  ".open <https://example.com|Example> <http://example.com/path> <mailto:foo@example.com|foo> <@U123> <#C123|general>",
+			expected: ".open Example http://example.com/path foo <@U123> <#C123|general>",
+		},
+		{
+			config: &NormalizationConfig{
+				TrimZeroWidth:   true,
+				UnifyWidth:      true,
+				NormalizeQuotes: true,
+				StripLinkMarkup: true,
+			},
+			message:  "．ｅｃｈｏ\u200b “<https://example.com|ｶﾞｲﾄﾞ>”",
+			expected: `.echo "ガイド"`,
+		},
+	}
+
+	for i, testSet := range testSets {
+		normalized := testSet.config.Normalize(testSet.message)
+		if normalized != testSet.expected {
+			t.Errorf("Unexpected message is returned on test #%d: %s.", i, normalized)
+		}
+	}
+}
+
+func Test_normalizingInputReceiver(t *testing.T) {
+	var config *NormalizationConfig
+	var received []Input
+	receive := normalizingInputReceiver(func() *NormalizationConfig {
+		return config
+	}, func(input Input) error {
+		received = append(received, input)
+		return nil
+	})
+
+	// No normalization.
+	_ = receive(&DummyNormalizableInput{DummyInput{MessageValue: "ｈｉ"}})
+
+	// The config is obtained on every Input.
+	config = &NormalizationConfig{UnifyWidth: true}
+	_ = receive(&DummyNormalizableInput{DummyInput{MessageValue: "ｈｉ"}})
+	_ = receive(&HelpInput{OriginalInput: &DummyNormalizableInput{DummyInput{MessageValue: "ｈｅｌｐ"}}})
+
+	// An Input that does not satisfy NormalizableInput is passed as-is.
+	_ = receive(&DummyInput{MessageValue: "ｈｉ"})
+
+	if len(received) != 4 {
+		t.Fatalf("Unexpected number of inputs are received: %d.", len(received))
+	}
+	expected := []string{"ｈｉ", "hi", "help", "ｈｉ"}
+	for i, input := range received {
+		message := input.Message()
+		if help, ok := input.(*HelpInput); ok {
+			message = help.OriginalInput.Message()
+		}
+		if message != expected[i] {
+			t.Errorf("Unexpected message is received on #%d: %s.", i, message)
+		}
+	}
+}
+
+func Test_runner_normalization(t *testing.T) {
+	r := &runner{}
+	if r.normalization("slack") != nil {
+		t.Error("Nil should be returned without Config.")
+	}
+
+	normalization := &NormalizationConfig{TrimZeroWidth: true}
+	r.config = &Config{Normalization: map[BotType]*NormalizationConfig{"slack": normalization}}
+	if r.normalization("slack") != normalization {
+		t.Errorf("Unexpected config is returned: %#v.", r.normalization("slack"))
+	}
+	if r.normalization("gitter") != nil {
+		t.Errorf("Unexpected config is returned: %#v.", r.normalization("gitter"))
+	}
+}
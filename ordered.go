@@ -0,0 +1,191 @@
+package sarah
+
+import (
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-kasumi/worker"
+	"hash/fnv"
+	"runtime/debug"
+	"sync"
+)
+
+// errOrderedQueueFull is returned when the ordered queue that an Input is assigned to is full.
+var errOrderedQueueFull = errors.New("ordered queue is full")
+
+// OrderedWorkerConfig declares how the worker.Worker created by NewOrderedWorker serializes the Inputs from the same sender.
+type OrderedWorkerConfig struct {
+	// Stripes declares the number of the ordered queues.
+	// The Inputs from the same sender are always assigned to the same queue and are handled one by one in the order of reception.
+	// The Inputs from different senders may share a queue, so a larger value lets more senders be handled concurrently.
+	// Zero or a negative value is treated as one, which serializes all Inputs.
+	Stripes int `json:"stripes" yaml:"stripes"`
+
+	// QueueSize declares the number of the Inputs that each ordered queue can hold while one of them is handled.
+	// An Input is rejected with *BlockedInputError when its queue is full. Zero value means no limit.
+	QueueSize int `json:"queue_size" yaml:"queue_size"`
+}
+
+// NewOrderedWorkerConfig creates and returns a new OrderedWorkerConfig instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewOrderedWorkerConfig() *OrderedWorkerConfig {
+	return &OrderedWorkerConfig{
+		Stripes:   64,
+		QueueSize: 10,
+	}
+}
+
+// NewOrderedWorker wraps the given worker.Worker so the Inputs from the same sender are handled in the order of reception.
+// With a multi-goroutine worker, two rapid messages from the same user can otherwise be handled out of order and break a conversational flow.
+//
+// Each Input is assigned to one of the ordered queues by the hash of its BotType and Input.SenderKey.
+// A queue hands its Inputs to the given worker.Worker one by one, so the Inputs in different queues are still handled concurrently.
+// An Input with an empty SenderKey and other jobs such as the ones SubmitJob enqueues are passed to the given worker.Worker as-is.
+//
+//	w := worker.Run(ctx, worker.NewConfig())
+//	sarah.RegisterWorker(sarah.NewOrderedWorker(w, sarah.NewOrderedWorkerConfig()))
+func NewOrderedWorker(w worker.Worker, config *OrderedWorkerConfig) worker.Worker {
+	stripes := config.Stripes
+	if stripes < 1 {
+		stripes = 1
+	}
+
+	ordered := &orderedWorker{
+		Worker:    w,
+		stripes:   make([]*orderedQueue, stripes),
+		queueSize: config.QueueSize,
+	}
+	for i := range ordered.stripes {
+		ordered.stripes[i] = &orderedQueue{}
+	}
+	return ordered
+}
+
+// orderedEnqueuer is satisfied by a worker.Worker that can handle the jobs with the same key in order.
+type orderedEnqueuer interface {
+	enqueueOrdered(key string, fnc func()) error
+}
+
+type orderedWorker struct {
+	worker.Worker
+	stripes   []*orderedQueue
+	queueSize int
+}
+
+var _ worker.Worker = (*orderedWorker)(nil)
+var _ orderedEnqueuer = (*orderedWorker)(nil)
+
+type orderedQueue struct {
+	jobs    []*orderedJob
+	running bool
+	mutex   sync.Mutex
+}
+
+// orderedJob wraps a stashed job so the one that failed to be enqueued can be identified and removed.
+type orderedJob struct {
+	fnc func()
+}
+
+// enqueueOrdered stashes the given job to the ordered queue that the key is assigned to.
+// When the queue is idle, a job to handle the stashed ones in order is enqueued to the underlying worker.Worker.
+func (w *orderedWorker) enqueueOrdered(key string, fnc func()) error {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	queue := w.stripes[hash.Sum32()%uint32(len(w.stripes))]
+
+	job := &orderedJob{fnc: fnc}
+	queue.mutex.Lock()
+	if w.queueSize > 0 && len(queue.jobs) >= w.queueSize {
+		queue.mutex.Unlock()
+		return errOrderedQueueFull
+	}
+	queue.jobs = append(queue.jobs, job)
+	if queue.running {
+		// The running job handles this one after the preceding ones.
+		queue.mutex.Unlock()
+		return nil
+	}
+	queue.running = true
+	queue.mutex.Unlock()
+
+	err := w.Worker.Enqueue(func() {
+		w.run(queue)
+	})
+	if err != nil {
+		// Other jobs may have been stashed while this one was being enqueued, and their callers were already told they were accepted.
+		// Remove only the given job and let the remaining ones be handled.
+		queue.mutex.Lock()
+		for i, stashed := range queue.jobs {
+			if stashed == job {
+				queue.jobs = append(queue.jobs[:i], queue.jobs[i+1:]...)
+				break
+			}
+		}
+		remaining := len(queue.jobs)
+		if remaining == 0 {
+			queue.running = false
+		}
+		queue.mutex.Unlock()
+
+		if remaining > 0 {
+			logger.Warnf("Failed to enqueue an ordered job. Handling %d stashed job(s) outside of the worker: %+v", remaining, err)
+			go w.run(queue)
+		}
+		return fmt.Errorf("failed to enqueue ordered job: %w", err)
+	}
+	return nil
+}
+
+// run executes the stashed jobs one by one until the queue becomes empty.
+func (w *orderedWorker) run(queue *orderedQueue) {
+	for {
+		queue.mutex.Lock()
+		if len(queue.jobs) == 0 {
+			queue.running = false
+			queue.mutex.Unlock()
+			return
+		}
+		job := queue.jobs[0]
+		queue.jobs[0] = nil
+		queue.jobs = queue.jobs[1:]
+		queue.mutex.Unlock()
+
+		runOrderedJob(job.fnc)
+	}
+}
+
+// runOrderedJob executes the given job and recovers its panic so the following jobs in the queue are not stuck.
+func runOrderedJob(job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Recovered a panic in an ordered job: %#v\n%s", r, debug.Stack())
+		}
+	}()
+
+	job()
+}
+
+// orderingKey returns the key that the Inputs from the same sender of the Bot share.
+// An empty string is returned when the sender can not be identified.
+func orderingKey(botType BotType, input Input) string {
+	senderKey := input.SenderKey()
+	if senderKey == "" {
+		return ""
+	}
+	return botType.String() + "|" + senderKey
+}
+
+// enqueueInputJob enqueues the job to handle the given Input.
+// When the worker.Worker is created by NewOrderedWorker, the jobs for the Inputs from the same sender are handled in order.
+func enqueueInputJob(wkr worker.Worker, botType BotType, input Input, job func()) error {
+	ordered, ok := wkr.(orderedEnqueuer)
+	if !ok {
+		return wkr.Enqueue(job)
+	}
+
+	key := orderingKey(botType, input)
+	if key == "" {
+		return wkr.Enqueue(job)
+	}
+	return ordered.enqueueOrdered(key, job)
+}
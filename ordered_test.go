@@ -0,0 +1,273 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewOrderedWorkerConfig(t *testing.T) {
+	config := NewOrderedWorkerConfig()
+
+	if config.Stripes != 64 {
+		t.Errorf("Unexpected number of stripes is set: %d.", config.Stripes)
+	}
+
+	if config.QueueSize != 10 {
+		t.Errorf("Unexpected queue size is set: %d.", config.QueueSize)
+	}
+}
+
+func TestNewOrderedWorker(t *testing.T) {
+	w := NewOrderedWorker(&DummyWorker{}, &OrderedWorkerConfig{Stripes: 0})
+
+	ordered, ok := w.(*orderedWorker)
+	if !ok {
+		t.Fatalf("Unexpected worker is returned: %T.", w)
+	}
+	if len(ordered.stripes) != 1 {
+		t.Errorf("Unexpected number of stripes are created: %d.", len(ordered.stripes))
+	}
+}
+
+func Test_orderedWorker_enqueueOrdered(t *testing.T) {
+	// Run the jobs concurrently just like a worker pool does.
+	var wg sync.WaitGroup
+	pool := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fnc()
+			}()
+			return nil
+		},
+	}
+	w := NewOrderedWorker(pool, &OrderedWorkerConfig{Stripes: 4}).(*orderedWorker)
+
+	var mutex sync.Mutex
+	handled := map[string][]int{}
+	for i := 0; i < 100; i++ {
+		for _, key := range []string{"alice", "bob"} {
+			key, i := key, i
+			err := w.enqueueOrdered(key, func() {
+				if i%10 == 0 {
+					panic("panicking job does not stop the queue")
+				}
+				time.Sleep(time.Microsecond)
+				mutex.Lock()
+				defer mutex.Unlock()
+				handled[key] = append(handled[key], i)
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error is returned: %s.", err.Error())
+			}
+		}
+	}
+	wg.Wait()
+
+	for key, values := range handled {
+		if len(values) != 90 {
+			t.Errorf("Unexpected number of jobs are handled for %s: %d.", key, len(values))
+		}
+		for i := 1; i < len(values); i++ {
+			if values[i-1] > values[i] {
+				t.Fatalf("Jobs for %s are handled out of order: %v.", key, values)
+			}
+		}
+	}
+}
+
+func Test_orderedWorker_enqueueOrdered_QueueFull(t *testing.T) {
+	var jobs []func()
+	pool := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			jobs = append(jobs, fnc)
+			return nil
+		},
+	}
+	w := NewOrderedWorker(pool, &OrderedWorkerConfig{Stripes: 1, QueueSize: 2}).(*orderedWorker)
+
+	for i := 0; i < 2; i++ {
+		err := w.enqueueOrdered("alice", func() {})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("Only one job should be enqueued to the underlying worker: %d.", len(jobs))
+	}
+
+	err := w.enqueueOrdered("bob", func() {})
+	if !errors.Is(err, errOrderedQueueFull) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	// The queue accepts jobs again once the stashed ones are handled.
+	jobs[0]()
+	err = w.enqueueOrdered("bob", func() {})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(jobs) != 2 {
+		t.Errorf("Idle queue should enqueue a new job: %d.", len(jobs))
+	}
+}
+
+func Test_orderedWorker_enqueueOrdered_Error(t *testing.T) {
+	fail := true
+	pool := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			if fail {
+				return errors.New("worker is busy")
+			}
+			fnc()
+			return nil
+		},
+	}
+	w := NewOrderedWorker(pool, &OrderedWorkerConfig{Stripes: 1}).(*orderedWorker)
+
+	err := w.enqueueOrdered("alice", func() {
+		t.Error("Rejected job should not be executed.")
+	})
+	if err == nil {
+		t.Fatal("Expected error is not returned.")
+	}
+
+	fail = false
+	executed := false
+	err = w.enqueueOrdered("alice", func() {
+		executed = true
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !executed {
+		t.Error("Job is not executed.")
+	}
+}
+
+func Test_orderedWorker_enqueueOrdered_ErrorWithConcurrentSenders(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	pool := &DummyWorker{
+		EnqueueFunc: func(_ func()) error {
+			// Other senders stash their jobs while this one is being enqueued, and then the worker rejects it.
+			close(entered)
+			<-release
+			return errors.New("worker is busy")
+		},
+	}
+	w := NewOrderedWorker(pool, &OrderedWorkerConfig{Stripes: 1}).(*orderedWorker)
+
+	failed := make(chan error, 1)
+	go func() {
+		failed <- w.enqueueOrdered("alice", func() {
+			t.Error("Rejected job should not be executed.")
+		})
+	}()
+	<-entered
+
+	handled := make(chan string, 2)
+	for _, key := range []string{"bob", "carol"} {
+		key := key
+		err := w.enqueueOrdered(key, func() {
+			handled <- key
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned for %s: %s.", key, err.Error())
+		}
+	}
+	close(release)
+
+	if err := <-failed; err == nil {
+		t.Error("Expected error is not returned to the sender whose job is rejected.")
+	}
+
+	for _, expected := range []string{"bob", "carol"} {
+		select {
+		case key := <-handled:
+			if key != expected {
+				t.Errorf("Unexpected job is handled: %s.", key)
+			}
+
+		case <-time.NewTimer(10 * time.Second).C:
+			t.Fatalf("Stashed job for %s is lost.", expected)
+
+		}
+	}
+}
+
+func Test_enqueueInputJob(t *testing.T) {
+	var enqueued int
+	pool := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			enqueued++
+			fnc()
+			return nil
+		},
+	}
+	tracked := newTrackedWorker(NewOrderedWorker(pool, NewOrderedWorkerConfig()), 0)
+
+	executed := 0
+	for _, input := range []Input{&DummyInput{SenderKeyValue: "alice"}, &DummyInput{}} {
+		err := enqueueInputJob(tracked, "dummy", input, func() {
+			executed++
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+	}
+
+	if executed != 2 || enqueued != 2 {
+		t.Errorf("Unexpected number of jobs are handled: %d, %d.", executed, enqueued)
+	}
+	if tracked.depth() != 0 {
+		t.Errorf("Unexpected depth is returned: %d.", tracked.depth())
+	}
+
+	ordered := tracked.Worker.(*orderedWorker)
+	for _, queue := range ordered.stripes {
+		if queue.running || len(queue.jobs) != 0 {
+			t.Errorf("Queue should be idle: %#v.", queue)
+		}
+	}
+}
+
+func Test_setupInputReceiver_WithOrderedWorker(t *testing.T) {
+	SetupAndRun(func() {
+		var jobs []func()
+		pool := &DummyWorker{
+			EnqueueFunc: func(fnc func()) error {
+				jobs = append(jobs, fnc)
+				return nil
+			},
+		}
+		var responded []string
+		bot := &DummyBot{
+			BotTypeValue: "DUMMY",
+			RespondFunc: func(_ context.Context, input Input) error {
+				responded = append(responded, input.Message())
+				return nil
+			},
+		}
+
+		receiveInput := setupInputReceiver(context.TODO(), bot, NewOrderedWorker(pool, NewOrderedWorkerConfig()), nil)
+		for _, message := range []string{"first", "second"} {
+			err := receiveInput(&DummyInput{SenderKeyValue: "alice", MessageValue: message})
+			if err != nil {
+				t.Fatalf("Unexpected error is returned: %s.", err.Error())
+			}
+		}
+
+		if len(jobs) != 1 {
+			t.Fatalf("Inputs from the same sender should share a job: %d.", len(jobs))
+		}
+		jobs[0]()
+		if len(responded) != 2 || responded[0] != "first" || responded[1] != "second" {
+			t.Errorf("Unexpected responses are made: %#v.", responded)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package sarah
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PanicStack represents a panic that occurred in a Bot and was recovered by Sarah.
+// This is passed to a PanicFormatter to build the message of BotNonContinuableError that is sent to the registered Alerters.
+type PanicStack struct {
+	// BotType represents the Bot that panicked.
+	BotType BotType
+
+	// Recovered is the value returned by recover.
+	Recovered interface{}
+
+	// GoroutineID is the ID of the goroutine where the panic occurred. This is zero when the ID can not be read from the stack trace.
+	GoroutineID int64
+
+	// Frames holds the stack frames from the one that panicked to the bottom of the goroutine.
+	Frames []*StackFrame
+}
+
+// StackFrame represents a frame of the stack trace.
+type StackFrame struct {
+	// Function is the name of the function including its package path. e.g. github.com/oklahomer/go-sarah/v4/slack.(*Adapter).Run
+	Function string
+
+	// File is the full path to the source file.
+	File string
+
+	// Line is the line number in the source file.
+	Line int
+}
+
+// String returns the stringified representation of the frame.
+func (f *StackFrame) String() string {
+	return fmt.Sprintf("%s at %s:%d", f.Function, f.File, f.Line)
+}
+
+// PanicFormatter defines a function's signature that formats a PanicStack into the message of an alert.
+// Register one via RegisterPanicFormatter to customize the alert payloads.
+type PanicFormatter func(*PanicStack) string
+
+// RegisterPanicFormatter registers the given PanicFormatter to format the recovered panic of a Bot.
+// When this is not registered, FormatPanicStack with Config.PanicStackDepth is used.
+func RegisterPanicFormatter(formatter PanicFormatter) {
+	defaultRunner().RegisterPanicFormatter(formatter)
+}
+
+// RegisterPanicFormatter is the Runner counterpart of the package-level RegisterPanicFormatter.
+func (rn *Runner) RegisterPanicFormatter(formatter PanicFormatter) {
+	rn.options.register(func(r *runner) {
+		r.panicFormatter = formatter
+	})
+}
+
+// FormatPanicStack returns a PanicFormatter that includes up to the given number of the topmost frames.
+// When depth is zero or less, all frames are included.
+// A small depth such as five keeps the alert compact while still showing where the panic occurred.
+//
+//	panic in bot: slack. "boom". goroutine: 42.
+//	 -> github.com/oklahomer/go-sarah/v4/slack.(*Adapter).Run at /path/to/slack/adapter.go:120
+//	 -> github.com/oklahomer/go-sarah/v4.(*runner).runBot.func2 at /path/to/runner.go:576
+//	 ... 3 more frames
+func FormatPanicStack(depth int) PanicFormatter {
+	return func(stack *PanicStack) string {
+		lines := []string{fmt.Sprintf("panic in bot: %s. %#v. goroutine: %d.", stack.BotType, stack.Recovered, stack.GoroutineID)}
+
+		frames := stack.Frames
+		if depth > 0 && len(frames) > depth {
+			frames = frames[:depth]
+		}
+		for _, frame := range frames {
+			lines = append(lines, fmt.Sprintf(" -> %s", frame))
+		}
+		if omitted := len(stack.Frames) - len(frames); omitted > 0 {
+			lines = append(lines, fmt.Sprintf(" ... %d more frames", omitted))
+		}
+
+		return strings.Join(lines, "\n")
+	}
+}
+
+// newPanicStack builds a PanicStack from the output of debug.Stack that is called in the deferred function to recover the panic.
+// The frames of debug.Stack itself, the deferred function, and the panic call are omitted so the first frame is where the panic occurred.
+func newPanicStack(botType BotType, recovered interface{}, trace []byte) *PanicStack {
+	stack := &PanicStack{
+		BotType:   botType,
+		Recovered: recovered,
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(trace)), "\n")
+	if len(lines) == 0 {
+		return stack
+	}
+
+	// The first line looks like "goroutine 42 [running]:"
+	header := strings.Fields(lines[0])
+	if len(header) >= 2 && header[0] == "goroutine" {
+		stack.GoroutineID, _ = strconv.ParseInt(header[1], 10, 64)
+	}
+
+	// Each frame consists of two lines: the function call and its location that starts with a tab.
+	//
+	//	main.main()
+	//		/path/to/main.go:10 +0x1d
+	var frames []*StackFrame
+	for _, line := range lines[1:] {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			if len(frames) > 0 {
+				frame := frames[len(frames)-1]
+				frame.File, frame.Line = parseLocation(line)
+			}
+
+		case strings.HasPrefix(line, "..."):
+			// e.g. ...additional frames elided...
+
+		default:
+			frames = append(frames, &StackFrame{Function: parseFunction(line)})
+
+		}
+	}
+
+	// Omit the frames up to the panic call.
+	for i, frame := range frames {
+		if frame.Function == "panic" {
+			frames = frames[i+1:]
+			break
+		}
+	}
+	stack.Frames = frames
+
+	return stack
+}
+
+// parseFunction strips the arguments from the function call line of a stack trace. e.g. main.(*Foo).Bar(0x1, 0x2) -> main.(*Foo).Bar
+func parseFunction(line string) string {
+	if strings.HasPrefix(line, "created by ") {
+		// e.g. created by main.main in goroutine 1
+		line = strings.TrimPrefix(line, "created by ")
+		if i := strings.Index(line, " in goroutine "); i > 0 {
+			line = line[:i]
+		}
+		return line
+	}
+	if i := strings.LastIndex(line, "("); i > 0 && strings.HasSuffix(line, ")") {
+		return line[:i]
+	}
+	return line
+}
+
+// parseLocation reads the file path and the line number from the location line of a stack trace. e.g. /path/to/main.go:10 +0x1d
+func parseLocation(line string) (string, int) {
+	location := strings.Fields(strings.TrimSpace(line))
+	if len(location) == 0 {
+		return "", 0
+	}
+
+	i := strings.LastIndex(location[0], ":")
+	if i < 0 {
+		return location[0], 0
+	}
+	n, _ := strconv.Atoi(location[0][i+1:])
+	return location[0][:i], n
+}
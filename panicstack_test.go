@@ -0,0 +1,150 @@
+package sarah
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func panicking() {
+	panic("boom")
+}
+
+func recoverPanicStack() (stack *PanicStack) {
+	defer func() {
+		recovered := recover()
+		stack = newPanicStack("dummy", recovered, debug.Stack())
+	}()
+	panicking()
+	return nil
+}
+
+func Test_newPanicStack(t *testing.T) {
+	stack := recoverPanicStack()
+
+	if stack.BotType != "dummy" || stack.Recovered != "boom" {
+		t.Errorf("Unexpected values are set: %#v.", stack)
+	}
+	if stack.GoroutineID == 0 {
+		t.Error("Goroutine ID is not read.")
+	}
+	if len(stack.Frames) == 0 {
+		t.Fatal("Frames are not read.")
+	}
+
+	top := stack.Frames[0]
+	if !strings.HasSuffix(top.Function, ".panicking") {
+		t.Errorf("The panicking function should be the first frame: %s.", top)
+	}
+	if !strings.HasSuffix(top.File, "panicstack_test.go") || top.Line == 0 {
+		t.Errorf("Unexpected location is read: %s.", top)
+	}
+}
+
+func Test_newPanicStack_Format(t *testing.T) {
+	trace := strings.Join([]string{
+		"goroutine 42 [running]:",
+		"runtime/debug.Stack()",
+		"\t/usr/local/go/src/runtime/debug/stack.go:26 +0x5e",
+		"main.run.func1()",
+		"\t/path/to/main.go:15 +0x45",
+		"panic({0x4b2ee0?, 0x5a0ac8?})",
+		"\t/usr/local/go/src/runtime/panic.go:770 +0x132",
+		"main.(*Foo).Bar(0xc000012345, 0x1)",
+		"\t/path/to/foo.go:10 +0x1d",
+		"...additional frames elided...",
+		"created by main.main in goroutine 1",
+		"\t/path/to/main.go:30 +0x25",
+	}, "\n")
+
+	stack := newPanicStack("dummy", "boom", []byte(trace))
+	if stack.GoroutineID != 42 {
+		t.Errorf("Unexpected goroutine ID is read: %d.", stack.GoroutineID)
+	}
+
+	expected := []*StackFrame{
+		{Function: "main.(*Foo).Bar", File: "/path/to/foo.go", Line: 10},
+		{Function: "main.main", File: "/path/to/main.go", Line: 30},
+	}
+	if len(stack.Frames) != len(expected) {
+		t.Fatalf("Unexpected frames are read: %#v.", stack.Frames)
+	}
+	for i, frame := range stack.Frames {
+		if *frame != *expected[i] {
+			t.Errorf("Unexpected frame is read at %d: %s.", i, frame)
+		}
+	}
+}
+
+func TestFormatPanicStack(t *testing.T) {
+	stack := &PanicStack{
+		BotType:     "dummy",
+		Recovered:   "boom",
+		GoroutineID: 42,
+		Frames: []*StackFrame{
+			{Function: "main.foo", File: "/path/to/main.go", Line: 10},
+			{Function: "main.bar", File: "/path/to/main.go", Line: 20},
+			{Function: "main.main", File: "/path/to/main.go", Line: 30},
+		},
+	}
+
+	testSets := []struct {
+		depth    int
+		expected []string
+	}{
+		{
+			depth: 1,
+			expected: []string{
+				`panic in bot: dummy. "boom". goroutine: 42.`,
+				" -> main.foo at /path/to/main.go:10",
+				" ... 2 more frames",
+			},
+		},
+		{
+			depth: 0,
+			expected: []string{
+				`panic in bot: dummy. "boom". goroutine: 42.`,
+				" -> main.foo at /path/to/main.go:10",
+				" -> main.bar at /path/to/main.go:20",
+				" -> main.main at /path/to/main.go:30",
+			},
+		},
+	}
+
+	for i, tt := range testSets {
+		formatted := FormatPanicStack(tt.depth)(stack)
+		if formatted != strings.Join(tt.expected, "\n") {
+			t.Errorf("Unexpected format is returned on test #%d: %s.", i+1, formatted)
+		}
+	}
+}
+
+func TestRegisterPanicFormatter(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterPanicFormatter(func(_ *PanicStack) string {
+			return "formatted"
+		})
+
+		r := &runner{}
+		options.apply(r)
+		if r.panicFormatter == nil {
+			t.Fatal("Given PanicFormatter is not set.")
+		}
+		if r.formatPanic(&PanicStack{}) != "formatted" {
+			t.Error("Registered PanicFormatter is not used.")
+		}
+	})
+}
+
+func Test_runner_formatPanic(t *testing.T) {
+	r := &runner{
+		config: &Config{PanicStackDepth: 1},
+	}
+	stack := &PanicStack{
+		Frames: []*StackFrame{{}, {}},
+	}
+
+	if !strings.HasSuffix(r.formatPanic(stack), "1 more frames") {
+		t.Errorf("Configured depth is not applied: %s.", r.formatPanic(stack))
+	}
+}
@@ -0,0 +1,137 @@
+package sarah
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// preferencesNamespace is the KVStore namespace suffix that KV-backed Preferences store each user's preference under.
+const preferencesNamespace = "preferences"
+
+// UserPreference represents a user's preference that core features and plugins consult to personalize their outputs.
+type UserPreference struct {
+	// Locale is the user's preferred locale such as "en-US" or "ja-JP".
+	Locale string `json:"locale,omitempty"`
+
+	// TimeZone is the user's time zone in the IANA Time Zone database format such as "Asia/Tokyo".
+	TimeZone string `json:"time_zone,omitempty"`
+
+	// OptOuts is a list of notification topics the user does not want to receive.
+	OptOuts []string `json:"opt_outs,omitempty"`
+}
+
+// Location returns the *time.Location of TimeZone.
+// When TimeZone is empty, the given fallback is returned.
+func (p *UserPreference) Location(fallback *time.Location) (*time.Location, error) {
+	if p.TimeZone == "" {
+		return fallback, nil
+	}
+
+	location, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone %s: %w", p.TimeZone, err)
+	}
+	return location, nil
+}
+
+// OptedOut tells if the user opted out of the notifications with the given topic.
+func (p *UserPreference) OptedOut(topic string) bool {
+	for _, optOut := range p.OptOuts {
+		if optOut == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Preferences defines an interface to store each user's UserPreference.
+// A user is identified by BotType and Input.SenderKey.
+//
+// Register an implementation via RegisterPreferences so Commands and ScheduledTasks can retrieve it with PreferencesFromContext.
+type Preferences interface {
+	// Get returns the preference of the given user.
+	// A zero value of UserPreference must be returned when the user has not stored any preference.
+	Get(ctx context.Context, botType BotType, senderKey string) (*UserPreference, error)
+
+	// Set stores the given preference of the given user. An existing preference is overridden.
+	Set(ctx context.Context, botType BotType, senderKey string, preference *UserPreference) error
+}
+
+// RegisterPreferences registers a given Preferences implementation to Sarah.
+// When one is registered, each Command and ScheduledTask can retrieve it via PreferencesFromContext.
+// Use NewInMemoryPreferences for development or NewKVPreferences with a persistent KVStore for production.
+func RegisterPreferences(preferences Preferences) {
+	defaultRunner().RegisterPreferences(preferences)
+}
+
+// RegisterPreferences is the Runner counterpart of the package-level RegisterPreferences.
+func (rn *Runner) RegisterPreferences(preferences Preferences) {
+	rn.options.register(func(r *runner) {
+		r.preferences = preferences
+	})
+}
+
+type preferencesKey struct{}
+
+// PreferencesFromContext returns the Preferences registered via RegisterPreferences.
+// The given context must be the one passed to Command's or ScheduledTask's execution.
+// This returns nil when no Preferences is registered.
+func PreferencesFromContext(ctx context.Context) Preferences {
+	preferences, _ := ctx.Value(preferencesKey{}).(Preferences)
+	return preferences
+}
+
+func withPreferences(ctx context.Context, preferences Preferences) context.Context {
+	return context.WithValue(ctx, preferencesKey{}, preferences)
+}
+
+// kvPreferences is a Preferences implementation that stores each UserPreference as JSON in a KVStore.
+type kvPreferences struct {
+	store KVStore
+}
+
+var _ Preferences = (*kvPreferences)(nil)
+
+// NewKVPreferences creates and returns a new Preferences implementation that stores each UserPreference in the given KVStore.
+// Pass a KVStore backed by a persistent storage such as the one in storages/redis to keep the preferences across restarts.
+func NewKVPreferences(store KVStore) Preferences {
+	return &kvPreferences{
+		store: store,
+	}
+}
+
+// NewInMemoryPreferences creates and returns a new Preferences implementation that stores each UserPreference in the process memory space.
+// This is handy for development and testing, but stored preferences are lost when the process stops.
+func NewInMemoryPreferences() Preferences {
+	return NewKVPreferences(NewInMemoryKVStore())
+}
+
+// Get returns the preference of the given user.
+func (p *kvPreferences) Get(ctx context.Context, botType BotType, senderKey string) (*UserPreference, error) {
+	value, err := p.store.Get(ctx, KVNamespace(botType, preferencesNamespace), senderKey)
+	if errors.Is(err, ErrKVNotFound) {
+		return &UserPreference{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	preference := &UserPreference{}
+	err = json.Unmarshal(value, preference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode preference: %w", err)
+	}
+	return preference, nil
+}
+
+// Set stores the given preference of the given user.
+func (p *kvPreferences) Set(ctx context.Context, botType BotType, senderKey string, preference *UserPreference) error {
+	value, err := json.Marshal(preference)
+	if err != nil {
+		return fmt.Errorf("failed to encode preference: %w", err)
+	}
+	return p.store.Set(ctx, KVNamespace(botType, preferencesNamespace), senderKey, value)
+}
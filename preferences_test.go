@@ -0,0 +1,139 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type DummyPreferences struct {
+	GetFunc func(context.Context, BotType, string) (*UserPreference, error)
+	SetFunc func(context.Context, BotType, string, *UserPreference) error
+}
+
+var _ Preferences = (*DummyPreferences)(nil)
+
+func (p *DummyPreferences) Get(ctx context.Context, botType BotType, senderKey string) (*UserPreference, error) {
+	return p.GetFunc(ctx, botType, senderKey)
+}
+
+func (p *DummyPreferences) Set(ctx context.Context, botType BotType, senderKey string, preference *UserPreference) error {
+	return p.SetFunc(ctx, botType, senderKey, preference)
+}
+
+func TestUserPreference_Location(t *testing.T) {
+	preference := &UserPreference{}
+	location, err := preference.Location(time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if location != time.UTC {
+		t.Errorf("Fallback location is not returned: %s.", location)
+	}
+
+	preference = &UserPreference{TimeZone: "Asia/Tokyo"}
+	location, err = preference.Location(time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if location.String() != "Asia/Tokyo" {
+		t.Errorf("Unexpected location is returned: %s.", location)
+	}
+
+	preference = &UserPreference{TimeZone: "Invalid/Zone"}
+	_, err = preference.Location(time.UTC)
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestUserPreference_OptedOut(t *testing.T) {
+	preference := &UserPreference{OptOuts: []string{"reminder"}}
+
+	if !preference.OptedOut("reminder") {
+		t.Error("Opted-out topic is not detected.")
+	}
+
+	if preference.OptedOut("digest") {
+		t.Error("Other topic should not be opted out.")
+	}
+}
+
+func TestRegisterPreferences(t *testing.T) {
+	SetupAndRun(func() {
+		preferences := &DummyPreferences{}
+		RegisterPreferences(preferences)
+		r := &runner{}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		if r.preferences != preferences {
+			t.Errorf("Given Preferences is not set: %#v.", r.preferences)
+		}
+	})
+}
+
+func TestPreferencesFromContext(t *testing.T) {
+	if PreferencesFromContext(context.Background()) != nil {
+		t.Error("Nil should be returned when no Preferences is set.")
+	}
+
+	preferences := &DummyPreferences{}
+	ctx := withPreferences(context.Background(), preferences)
+	if PreferencesFromContext(ctx) != preferences {
+		t.Error("Given Preferences is not returned.")
+	}
+}
+
+func TestNewInMemoryPreferences(t *testing.T) {
+	preferences := NewInMemoryPreferences()
+	ctx := context.TODO()
+
+	preference, err := preferences.Get(ctx, "dummy", "user")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if preference.Locale != "" || preference.TimeZone != "" || len(preference.OptOuts) != 0 {
+		t.Errorf("Zero value should be returned: %#v.", preference)
+	}
+
+	err = preferences.Set(ctx, "dummy", "user", &UserPreference{Locale: "ja-JP", TimeZone: "Asia/Tokyo", OptOuts: []string{"digest"}})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	preference, _ = preferences.Get(ctx, "dummy", "user")
+	if preference.Locale != "ja-JP" || preference.TimeZone != "Asia/Tokyo" || !preference.OptedOut("digest") {
+		t.Errorf("Stored preference is not returned: %#v.", preference)
+	}
+
+	other, _ := preferences.Get(ctx, "other", "user")
+	if other.Locale != "" {
+		t.Errorf("Preference of other BotType should not be returned: %#v.", other)
+	}
+}
+
+func Test_kvPreferences_Error(t *testing.T) {
+	store := &DummyKVStore{
+		GetFunc: func(_ context.Context, _ string, _ string) ([]byte, error) {
+			return []byte("invalid"), nil
+		},
+	}
+	preferences := NewKVPreferences(store)
+
+	_, err := preferences.Get(context.TODO(), "dummy", "user")
+	if err == nil {
+		t.Error("Expected error is not returned for a broken value.")
+	}
+
+	store.GetFunc = func(_ context.Context, _ string, _ string) ([]byte, error) {
+		return nil, errors.New("connection error")
+	}
+	_, err = preferences.Get(context.TODO(), "dummy", "user")
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
@@ -0,0 +1,234 @@
+package sarah
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedInput is a serializable snapshot of an Input that a Bot received.
+// This satisfies Input so a recorded Input can be fed to Bot.Respond again to reproduce an issue triggered by real traffic.
+//
+// Note that the concrete type of the original Input is not restored.
+// A Command that type-asserts Input to an Adapter-specific type behaves differently on replay.
+type RecordedInput struct {
+	// BotType is the BotType of the Bot that received the Input.
+	BotType BotType `json:"bot_type"`
+
+	// InputType is the name of the original Input's concrete type for debugging purposes.
+	InputType string `json:"input_type"`
+
+	// Sender is the value returned by Input.SenderKey.
+	Sender string `json:"sender"`
+
+	// Text is the value returned by Input.Message.
+	Text string `json:"text"`
+
+	// Timestamp is the value returned by Input.SentAt.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Destination is the JSON representation of the value returned by Input.ReplyTo.
+	// This is nil when the value can not be represented in JSON.
+	Destination json.RawMessage `json:"destination,omitempty"`
+
+	// RecordedAt is the time when the Input was recorded.
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+var _ Input = (*RecordedInput)(nil)
+var _ NormalizableInput = (*RecordedInput)(nil)
+
+// NewRecordedInput creates and returns a new RecordedInput from the given Input.
+func NewRecordedInput(botType BotType, input Input) *RecordedInput {
+	recorded := &RecordedInput{
+		BotType:    botType,
+		InputType:  fmt.Sprintf("%T", input),
+		Sender:     input.SenderKey(),
+		Text:       input.Message(),
+		Timestamp:  input.SentAt(),
+		RecordedAt: currentClock().Now(),
+	}
+
+	if dest := input.ReplyTo(); dest != nil {
+		b, err := json.Marshal(dest)
+		if err == nil {
+			recorded.Destination = b
+		}
+	}
+
+	return recorded
+}
+
+// SenderKey returns the recorded sender key.
+func (i *RecordedInput) SenderKey() string {
+	return i.Sender
+}
+
+// Message returns the recorded message.
+func (i *RecordedInput) Message() string {
+	return i.Text
+}
+
+// SetMessage replaces the recorded message with the normalized one.
+func (i *RecordedInput) SetMessage(message string) {
+	i.Text = message
+}
+
+// SentAt returns the recorded timestamp.
+func (i *RecordedInput) SentAt() time.Time {
+	return i.Timestamp
+}
+
+// ReplyTo returns the JSON representation of the recorded destination.
+// This returns nil when no destination is recorded.
+func (i *RecordedInput) ReplyTo() OutputDestination {
+	if len(i.Destination) == 0 {
+		// Returning the empty json.RawMessage as is results in a non-nil OutputDestination.
+		return nil
+	}
+	return i.Destination
+}
+
+// InputSink defines an interface to persist the recorded Inputs.
+// Implement this to send the Inputs to the preferred storage.
+// JSONInputSink writes the Inputs to a file, and kafka.InputSink publishes them to a Kafka topic.
+type InputSink interface {
+	// Record persists the given RecordedInput.
+	// This is called synchronously on every Input reception, so the implementation should return as soon as possible.
+	Record(context.Context, *RecordedInput) error
+}
+
+// RegisterInputRecorder registers a given InputSink to record every Input that the Bots receive.
+// Use this with ReplayInputs to reproduce a Command's bug that is triggered by real traffic.
+// Multiple sinks can be registered.
+func RegisterInputRecorder(sink InputSink) {
+	defaultRunner().RegisterInputRecorder(sink)
+}
+
+// RegisterInputRecorder is the Runner counterpart of the package-level RegisterInputRecorder.
+func (rn *Runner) RegisterInputRecorder(sink InputSink) {
+	rn.options.register(func(r *runner) {
+		r.inputSinks = append(r.inputSinks, sink)
+	})
+}
+
+// recordingInputReceiver wraps the given function to pass every Input to the given sinks before handling.
+// A failure to record is logged and does not prevent the Input from being handled.
+func recordingInputReceiver(ctx context.Context, botType BotType, sinks []InputSink, receive func(Input) error) func(Input) error {
+	return func(input Input) error {
+		recorded := NewRecordedInput(botType, input)
+		for _, sink := range sinks {
+			err := sink.Record(ctx, recorded)
+			if err != nil {
+				logger.Errorf("Failed to record an input with %T: %+v", sink, err)
+			}
+		}
+
+		return receive(input)
+	}
+}
+
+// JSONInputSink is an InputSink implementation that writes each RecordedInput as a line of JSON.
+// Pass an *os.File to store the Inputs in a file; the output can be read by ReplayInputs.
+type JSONInputSink struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+var _ InputSink = (*JSONInputSink)(nil)
+
+// NewJSONInputSink creates and returns a new JSONInputSink that writes to the given io.Writer.
+func NewJSONInputSink(writer io.Writer) *JSONInputSink {
+	return &JSONInputSink{
+		writer: writer,
+	}
+}
+
+// Record writes the given RecordedInput as a line of JSON.
+func (s *JSONInputSink) Record(_ context.Context, input *RecordedInput) error {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode input: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err = s.writer.Write(append(b, '\n'))
+	return err
+}
+
+// ReplayInputs reads the RecordedInputs written by JSONInputSink and feeds them to the given Bot in the recorded order.
+// Inputs recorded for other BotTypes are skipped.
+// Use NewSandboxBot to build a Bot whose outputs are captured instead of being sent to the chat service.
+//
+//	bot := sarah.NewSandboxBot("slack", func(_ context.Context, output sarah.Output) {
+//		fmt.Printf("%#v\n", output.Content())
+//	})
+//	bot.AppendCommand(buggyCommand)
+//	file, _ := os.Open("inputs.jsonl")
+//	err := sarah.ReplayInputs(ctx, bot, file)
+func ReplayInputs(ctx context.Context, bot Bot, reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		input := &RecordedInput{}
+		err := json.Unmarshal(line, input)
+		if err != nil {
+			return fmt.Errorf("failed to decode recorded input: %w", err)
+		}
+
+		if input.BotType != bot.BotType() {
+			continue
+		}
+
+		err = bot.Respond(ctx, input)
+		if err != nil {
+			logger.Errorf("Error on replaying input: %#v. Error: %+v", input, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// NewSandboxBot creates and returns a Bot that does not connect to any chat service.
+// Outputs are passed to the given function instead, so the Bot can safely be used with ReplayInputs.
+func NewSandboxBot(botType BotType, sendMessage func(context.Context, Output), options ...DefaultBotOption) Bot {
+	return NewBot(&sandboxAdapter{
+		botType:     botType,
+		sendMessage: sendMessage,
+	}, options...)
+}
+
+type sandboxAdapter struct {
+	botType     BotType
+	sendMessage func(context.Context, Output)
+}
+
+var _ Adapter = (*sandboxAdapter)(nil)
+
+func (a *sandboxAdapter) BotType() BotType {
+	return a.botType
+}
+
+func (a *sandboxAdapter) Run(ctx context.Context, _ func(Input) error, _ func(error)) {
+	<-ctx.Done()
+}
+
+func (a *sandboxAdapter) SendMessage(ctx context.Context, output Output) {
+	a.sendMessage(ctx, output)
+}
@@ -0,0 +1,181 @@
+package sarah
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyInputSink struct {
+	RecordFunc func(context.Context, *RecordedInput) error
+}
+
+var _ InputSink = (*DummyInputSink)(nil)
+
+func (s *DummyInputSink) Record(ctx context.Context, input *RecordedInput) error {
+	return s.RecordFunc(ctx, input)
+}
+
+func TestNewRecordedInput(t *testing.T) {
+	sentAt := time.Now()
+	input := &DummyInput{
+		SenderKeyValue: "sender",
+		MessageValue:   ".echo foo",
+		SentAtValue:    sentAt,
+		ReplyToValue:   "#channel",
+	}
+
+	recorded := NewRecordedInput("dummy", input)
+
+	if recorded.BotType != "dummy" {
+		t.Errorf("Unexpected BotType is set: %s.", recorded.BotType)
+	}
+
+	if recorded.InputType != "*sarah.DummyInput" {
+		t.Errorf("Unexpected type name is set: %s.", recorded.InputType)
+	}
+
+	if recorded.SenderKey() != "sender" || recorded.Message() != ".echo foo" || !recorded.SentAt().Equal(sentAt) {
+		t.Errorf("Unexpected values are set: %#v.", recorded)
+	}
+
+	if string(recorded.Destination) != `"#channel"` {
+		t.Errorf("Unexpected destination is set: %s.", recorded.Destination)
+	}
+}
+
+func TestRecordedInput_ReplyTo(t *testing.T) {
+	recorded := NewRecordedInput("dummy", &DummyInput{ReplyToValue: "#channel"})
+	if recorded.ReplyTo() == nil {
+		t.Error("Recorded destination is not returned.")
+	}
+
+	recorded = NewRecordedInput("dummy", &DummyInput{})
+	if recorded.ReplyTo() != nil {
+		t.Errorf("Nil should be returned when no destination is recorded: %#v.", recorded.ReplyTo())
+	}
+}
+
+func TestRegisterInputRecorder(t *testing.T) {
+	SetupAndRun(func() {
+		sink := &DummyInputSink{}
+		RegisterInputRecorder(sink)
+		r := &runner{}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		if len(r.inputSinks) != 1 || r.inputSinks[0] != sink {
+			t.Errorf("Given InputSink is not set: %#v.", r.inputSinks)
+		}
+	})
+}
+
+func Test_recordingInputReceiver(t *testing.T) {
+	var recorded []*RecordedInput
+	sinks := []InputSink{
+		&DummyInputSink{
+			RecordFunc: func(_ context.Context, _ *RecordedInput) error {
+				return errors.New("failed")
+			},
+		},
+		&DummyInputSink{
+			RecordFunc: func(_ context.Context, input *RecordedInput) error {
+				recorded = append(recorded, input)
+				return nil
+			},
+		},
+	}
+
+	received := 0
+	receive := recordingInputReceiver(context.TODO(), "dummy", sinks, func(_ Input) error {
+		received++
+		return nil
+	})
+
+	err := receive(&DummyInput{MessageValue: "foo"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if received != 1 {
+		t.Error("Input is not passed to the receiver.")
+	}
+
+	if len(recorded) != 1 || recorded[0].Message() != "foo" {
+		t.Errorf("Input is not recorded: %#v.", recorded)
+	}
+}
+
+func TestReplayInputs(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	sink := NewJSONInputSink(buffer)
+	inputs := []*RecordedInput{
+		NewRecordedInput("dummy", &DummyInput{SenderKeyValue: "foo", MessageValue: ".echo hello", ReplyToValue: "#channel"}),
+		NewRecordedInput("other", &DummyInput{SenderKeyValue: "bar", MessageValue: ".echo ignored"}),
+		NewRecordedInput("dummy", &DummyInput{SenderKeyValue: "baz", MessageValue: ".echo world"}),
+	}
+	for _, input := range inputs {
+		err := sink.Record(context.TODO(), input)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+	}
+
+	if strings.Count(buffer.String(), "\n") != 3 {
+		t.Fatalf("Each input should be written in a line: %s", buffer.String())
+	}
+
+	var outputs []Output
+	bot := NewSandboxBot("dummy", func(_ context.Context, output Output) {
+		outputs = append(outputs, output)
+	})
+	bot.AppendCommand(&DummyCommand{
+		IdentifierValue: "echo",
+		MatchFunc: func(input Input) bool {
+			return strings.HasPrefix(input.Message(), ".echo")
+		},
+		ExecuteFunc: func(_ context.Context, input Input) (*CommandResponse, error) {
+			return &CommandResponse{Content: strings.TrimPrefix(input.Message(), ".echo ")}, nil
+		},
+	})
+
+	err := ReplayInputs(context.TODO(), bot, buffer)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(outputs) != 2 {
+		t.Fatalf("Unexpected number of outputs are sent: %d.", len(outputs))
+	}
+
+	if outputs[0].Content() != "hello" || outputs[1].Content() != "world" {
+		t.Errorf("Unexpected outputs are sent: %#v.", outputs)
+	}
+}
+
+func TestReplayInputs_MalformedInput(t *testing.T) {
+	bot := NewSandboxBot("dummy", func(_ context.Context, _ Output) {})
+
+	err := ReplayInputs(context.TODO(), bot, strings.NewReader("{invalid"))
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func Test_sandboxAdapter_Run(t *testing.T) {
+	adapter := &sandboxAdapter{botType: "dummy"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Should return on context cancellation.
+	adapter.Run(ctx, func(_ Input) error { return nil }, func(_ error) {})
+
+	if adapter.BotType() != "dummy" {
+		t.Errorf("Unexpected BotType is returned: %s.", adapter.BotType())
+	}
+}
@@ -0,0 +1,240 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrRegistrationAfterRun is a panic value of the registration functions such as RegisterCommand when they are called after Run.
+// Those registrations never take effect because the registered options are applied only once on Run.
+// Use AddCommand, AddCommandProps, AddScheduledTask, or AddScheduledTaskProps to add a component to the running process.
+var ErrRegistrationAfterRun = errors.New("registration is called after Run")
+
+var running = &runningRunner{}
+
+// SetStrictRegistration switches how the registration functions such as RegisterCommand and RegisterBot behave when they are called after Run.
+// When strict is true, which is the default, such a call panics with ErrRegistrationAfterRun so the mistake is found during development.
+// When strict is false, the call only logs an error and the registration is ignored.
+func SetStrictRegistration(strict bool) {
+	defaultRunner().SetStrictRegistration(strict)
+}
+
+// SetStrictRegistration is the Runner counterpart of the package-level SetStrictRegistration.
+func (rn *Runner) SetStrictRegistration(strict bool) {
+	rn.options.mutex.Lock()
+	defer rn.options.mutex.Unlock()
+
+	rn.options.lenient = !strict
+}
+
+// AddCommand adds the given Command to the Bot with the given BotType while Sarah is running.
+// The Command is appended to the running Bot immediately and is also registered again when the Bot restarts.
+// Use RegisterCommand instead before Run.
+func AddCommand(botType BotType, command Command) error {
+	return defaultRunner().AddCommand(botType, command)
+}
+
+// AddCommand is the Runner counterpart of the package-level AddCommand.
+func (rn *Runner) AddCommand(botType BotType, command Command) error {
+	if command == nil {
+		return fmt.Errorf("nil Command is given to AddCommand for %s", botType)
+	}
+
+	return rn.running.add(botType, func(r *runner) error {
+		if err := r.checkCommandConflict(botType, command.Identifier()); err != nil {
+			return err
+		}
+		r.commands[botType] = append(r.commands[botType], command)
+		return nil
+	}, func(_ context.Context, r *runner, bot Bot) {
+		bot.AppendCommand(r.wrapCommand(botType, command))
+	})
+}
+
+// AddCommandProps adds the given CommandProps to the Bots with the corresponding BotTypes while Sarah is running.
+// The Command is built and appended to the running Bots immediately, and its configuration is subscribed just like one registered via RegisterCommandProps.
+// Use RegisterCommandProps instead before Run.
+func AddCommandProps(props *CommandProps) error {
+	return defaultRunner().AddCommandProps(props)
+}
+
+// AddCommandProps is the Runner counterpart of the package-level AddCommandProps.
+func (rn *Runner) AddCommandProps(props *CommandProps) error {
+	if props == nil {
+		return errors.New("nil CommandProps is given to AddCommandProps")
+	}
+
+	botTypes := props.targetBotTypes()
+	if len(botTypes) == 1 && botTypes[0] == AnyBotType {
+		r := rn.running.get()
+		if r == nil {
+			return ErrRunnerNotRunning
+		}
+		botTypes = r.botTypes()
+	}
+
+	var errs []error
+	for _, botType := range botTypes {
+		p := props.forBotType(botType)
+		err := rn.running.add(botType, func(r *runner) error {
+			if err := r.checkCommandConflict(p.botType, p.identifier); err != nil {
+				return err
+			}
+			r.commandProps[p.botType] = append(r.commandProps[p.botType], p)
+			return nil
+		}, func(botCtx context.Context, r *runner, bot Bot) {
+			r.registerCommandProps(botCtx, bot, p)
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AddScheduledTask adds the given ScheduledTask to the Bot with the given BotType while Sarah is running.
+// The task is scheduled immediately and is also scheduled again when the Bot restarts.
+// Use RegisterScheduledTask instead before Run.
+func AddScheduledTask(botType BotType, task ScheduledTask) error {
+	return defaultRunner().AddScheduledTask(botType, task)
+}
+
+// AddScheduledTask is the Runner counterpart of the package-level AddScheduledTask.
+func (rn *Runner) AddScheduledTask(botType BotType, task ScheduledTask) error {
+	if task == nil {
+		return fmt.Errorf("nil ScheduledTask is given to AddScheduledTask for %s", botType)
+	}
+
+	return rn.running.add(botType, func(r *runner) error {
+		r.scheduledTasks[botType] = append(r.scheduledTasks[botType], task)
+		return nil
+	}, func(botCtx context.Context, r *runner, bot Bot) {
+		r.registerScheduledTask(botCtx, bot, task)
+	})
+}
+
+// AddScheduledTaskProps adds the given ScheduledTaskProps to the Bot with the corresponding BotType while Sarah is running.
+// The task is built and scheduled immediately, and its configuration is subscribed just like one registered via RegisterScheduledTaskProps.
+// Use RegisterScheduledTaskProps instead before Run.
+func AddScheduledTaskProps(props *ScheduledTaskProps) error {
+	return defaultRunner().AddScheduledTaskProps(props)
+}
+
+// AddScheduledTaskProps is the Runner counterpart of the package-level AddScheduledTaskProps.
+func (rn *Runner) AddScheduledTaskProps(props *ScheduledTaskProps) error {
+	if props == nil {
+		return errors.New("nil ScheduledTaskProps is given to AddScheduledTaskProps")
+	}
+
+	return rn.running.add(props.botType, func(r *runner) error {
+		r.scheduledTaskProps[props.botType] = append(r.scheduledTaskProps[props.botType], props)
+		return nil
+	}, func(botCtx context.Context, r *runner, bot Bot) {
+		r.registerScheduledTaskProps(botCtx, bot, props)
+	})
+}
+
+// runningRunner holds the runner that Run started so the components can be added at runtime.
+type runningRunner struct {
+	runner *runner
+	mutex  sync.RWMutex
+}
+
+func (rr *runningRunner) set(r *runner) {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	rr.runner = r
+}
+
+// get returns the running runner. This returns nil when Run is not called, yet.
+func (rr *runningRunner) get() *runner {
+	rr.mutex.RLock()
+	defer rr.mutex.RUnlock()
+
+	return rr.runner
+}
+
+// add stashes the component via stash so the Bot registers it on its next start,
+// and then applies the component via apply when the Bot is currently running.
+// When stash returns an error, the component is neither stashed nor applied.
+func (rr *runningRunner) add(botType BotType, stash func(*runner) error, apply func(context.Context, *runner, Bot)) error {
+	r := rr.get()
+	if r == nil {
+		return ErrRunnerNotRunning
+	}
+
+	r.mutex.Lock()
+	registered := false
+	for _, bot := range r.bots {
+		if bot.BotType() == botType {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		r.mutex.Unlock()
+		return fmt.Errorf("no Bot is registered for %s", botType)
+	}
+	if err := stash(r); err != nil {
+		r.mutex.Unlock()
+		return err
+	}
+	current, ok := r.runningBots[botType]
+	r.mutex.Unlock()
+
+	// When the Bot is not running, the stashed component is registered on its next start.
+	// When the Bot starts concurrently, the component may be applied twice, but Bot.AppendCommand and the scheduler replace the one with the same identifier.
+	if ok {
+		apply(current.ctx, r, current.bot)
+	}
+	return nil
+}
+
+// botTypes returns the BotTypes of the registered Bots.
+func (r *runner) botTypes() []BotType {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var botTypes []BotType
+	for _, bot := range r.bots {
+		botTypes = append(botTypes, bot.BotType())
+	}
+	return botTypes
+}
+
+// runningBot is a Bot that is currently running with its context.
+type runningBot struct {
+	ctx context.Context
+	bot Bot
+}
+
+func (r *runner) setRunningBot(botCtx context.Context, bot Bot) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.runningBots == nil {
+		r.runningBots = make(map[BotType]*runningBot)
+	}
+	r.runningBots[bot.BotType()] = &runningBot{
+		ctx: botCtx,
+		bot: bot,
+	}
+}
+
+func (r *runner) runningBot(botType BotType) (*runningBot, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	running, ok := r.runningBots[botType]
+	return running, ok
+}
+
+func (r *runner) unsetRunningBot(botType BotType) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.runningBots, botType)
+}
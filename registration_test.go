@@ -0,0 +1,204 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_optionHolder_register_AfterSeal(t *testing.T) {
+	SetupAndRun(func() {
+		options.seal()
+
+		defer func() {
+			recovered := recover()
+			if recovered != ErrRegistrationAfterRun {
+				t.Errorf("Expected panic is not given: %#v.", recovered)
+			}
+		}()
+		RegisterCommand("dummy", &DummyCommand{})
+	})
+}
+
+func Test_optionHolder_register_AfterSealWithLenientMode(t *testing.T) {
+	SetupAndRun(func() {
+		SetStrictRegistration(false)
+		options.seal()
+
+		RegisterCommand("dummy", &DummyCommand{})
+		if len(options.stashed) != 0 {
+			t.Errorf("Late registration should be ignored: %d.", len(options.stashed))
+		}
+	})
+}
+
+func TestAddCommand(t *testing.T) {
+	var botType BotType = "dummy"
+	command := &DummyCommand{IdentifierValue: "dummy"}
+
+	t.Run("not running", func(t *testing.T) {
+		SetupAndRun(func() {
+			err := AddCommand(botType, command)
+			if !errors.Is(err, ErrRunnerNotRunning) {
+				t.Errorf("Expected error is not returned: %#v.", err)
+			}
+		})
+	})
+
+	t.Run("unregistered bot", func(t *testing.T) {
+		SetupAndRun(func() {
+			running.set(&runner{
+				commands: map[BotType][]Command{},
+			})
+			err := AddCommand(botType, command)
+			if err == nil {
+				t.Error("Expected error is not returned.")
+			}
+		})
+	})
+
+	t.Run("nil command", func(t *testing.T) {
+		SetupAndRun(func() {
+			err := AddCommand(botType, nil)
+			if err == nil {
+				t.Error("Expected error is not returned.")
+			}
+		})
+	})
+
+	t.Run("running bot", func(t *testing.T) {
+		SetupAndRun(func() {
+			var appended Command
+			bot := &DummyBot{
+				BotTypeValue: botType,
+				AppendCommandFunc: func(c Command) {
+					appended = c
+				},
+			}
+			r := &runner{
+				bots:     []Bot{bot},
+				commands: map[BotType][]Command{},
+			}
+			running.set(r)
+			r.setRunningBot(context.TODO(), bot)
+
+			err := AddCommand(botType, command)
+			if err != nil {
+				t.Fatalf("Unexpected error is returned: %s.", err.Error())
+			}
+			if appended != command {
+				t.Errorf("Command is not appended to the running bot: %#v.", appended)
+			}
+			if len(r.botCommands(botType)) != 1 {
+				t.Error("Command should be stashed for the next start.")
+			}
+		})
+	})
+
+	t.Run("stopped bot", func(t *testing.T) {
+		SetupAndRun(func() {
+			bot := &DummyBot{
+				BotTypeValue: botType,
+				AppendCommandFunc: func(_ Command) {
+					t.Error("Command should not be appended to the stopped bot.")
+				},
+			}
+			r := &runner{
+				bots:     []Bot{bot},
+				commands: map[BotType][]Command{},
+			}
+			running.set(r)
+
+			err := AddCommand(botType, command)
+			if err != nil {
+				t.Fatalf("Unexpected error is returned: %s.", err.Error())
+			}
+			if len(r.botCommands(botType)) != 1 {
+				t.Error("Command should be stashed for the next start.")
+			}
+		})
+	})
+}
+
+func TestAddCommandProps_Shared(t *testing.T) {
+	t.Run("not running", func(t *testing.T) {
+		SetupAndRun(func() {
+			err := AddCommandProps(&CommandProps{botType: AnyBotType})
+			if !errors.Is(err, ErrRunnerNotRunning) {
+				t.Errorf("Expected error is not returned: %#v.", err)
+			}
+		})
+	})
+
+	t.Run("stopped bots", func(t *testing.T) {
+		SetupAndRun(func() {
+			r := &runner{
+				bots:         []Bot{&DummyBot{BotTypeValue: "slack"}, &DummyBot{BotTypeValue: "gitter"}},
+				commandProps: map[BotType][]*CommandProps{},
+			}
+			running.set(r)
+
+			err := AddCommandProps(&CommandProps{botType: AnyBotType, identifier: "shared"})
+			if err != nil {
+				t.Fatalf("Unexpected error is returned: %s.", err.Error())
+			}
+			for _, botType := range []BotType{"slack", "gitter"} {
+				props := r.botCommandProps(botType)
+				if len(props) != 1 || props[0].botType != botType {
+					t.Errorf("CommandProps is not stashed for %s: %#v.", botType, props)
+				}
+			}
+		})
+	})
+
+	t.Run("unregistered bot", func(t *testing.T) {
+		SetupAndRun(func() {
+			r := &runner{
+				bots:         []Bot{&DummyBot{BotTypeValue: "slack"}},
+				commandProps: map[BotType][]*CommandProps{},
+			}
+			running.set(r)
+
+			err := AddCommandProps(&CommandProps{botType: "slack", botTypes: []BotType{"slack", "gitter"}})
+			if err == nil {
+				t.Error("Expected error is not returned.")
+			}
+			if len(r.botCommandProps("slack")) != 1 {
+				t.Error("CommandProps should still be stashed for the registered bot.")
+			}
+		})
+	})
+}
+
+func TestAddScheduledTask(t *testing.T) {
+	SetupAndRun(func() {
+		var botType BotType = "dummy"
+		task := &DummyScheduledTask{IdentifierValue: "dummy", ScheduleValue: "@hourly"}
+		bot := &DummyBot{BotTypeValue: botType}
+
+		var updated ScheduledTask
+		r := &runner{
+			bots:           []Bot{bot},
+			scheduledTasks: map[BotType][]ScheduledTask{},
+			scheduler: &DummyScheduler{
+				UpdateFunc: func(_ BotType, t ScheduledTask, _ func() error) error {
+					updated = t
+					return nil
+				},
+			},
+		}
+		running.set(r)
+		r.setRunningBot(context.TODO(), bot)
+
+		err := AddScheduledTask(botType, task)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		if updated != task {
+			t.Errorf("Task is not scheduled: %#v.", updated)
+		}
+		if len(r.botScheduledTasks(botType)) != 1 {
+			t.Error("Task should be stashed for the next start.")
+		}
+	})
+}
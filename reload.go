@@ -0,0 +1,220 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	// RunnerConfigNamespace is the reserved BotType to read and watch the runner-level Config via ConfigWatcher.
+	// Do not use this value for any Bot's BotType.
+	RunnerConfigNamespace BotType = "runner"
+
+	// RunnerConfigID is the identifier of the runner-level Config in RunnerConfigNamespace.
+	// With the file watcher, the configuration file is located at {baseDir}/runner/runner.yaml.
+	RunnerConfigID = "runner"
+)
+
+// SupervisorConfig declares the behavior of the built-in supervising function.
+// The supervising function counts the escalated errors per BotType and per error type as NewThresholdSupervisor does,
+// and reacts as declared when the count reaches MaxCount within Window.
+type SupervisorConfig struct {
+	// Window declares the length of the sliding window to count the escalated errors.
+	Window time.Duration `json:"window" yaml:"window"`
+
+	// MaxCount declares the number of errors within Window to react.
+	MaxCount int `json:"max_count" yaml:"max_count"`
+
+	// StopBot declares whether to stop the failing Bot.
+	StopBot bool `json:"stop_bot" yaml:"stop_bot"`
+
+	// RestartBot declares whether to restart the failing Bot after RestartCooldown.
+	RestartBot bool `json:"restart_bot" yaml:"restart_bot"`
+
+	// RestartCooldown declares how long to wait before restarting the Bot.
+	RestartCooldown time.Duration `json:"restart_cooldown" yaml:"restart_cooldown"`
+
+	// Alert declares whether to send the escalated error to the registered Alerters.
+	Alert bool `json:"alert" yaml:"alert"`
+}
+
+func (c *SupervisorConfig) supervisor() func(BotType, error) *SupervisionDirective {
+	supervise := NewThresholdSupervisor(c.Window, c.MaxCount, &SupervisionDirective{
+		StopBot:         c.StopBot,
+		RestartBot:      c.RestartBot,
+		RestartCooldown: c.RestartCooldown,
+	})
+	alert := c.Alert
+
+	return func(botType BotType, err error) *SupervisionDirective {
+		directive := supervise(botType, err)
+		if directive == nil || !alert {
+			return directive
+		}
+
+		// Copy the directive so the escalated error is not shared among calls.
+		d := *directive
+		d.AlertingErr = err
+		return &d
+	}
+}
+
+// parseLogLevel converts the given string to logger.Level.
+func parseLogLevel(level string) (logger.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return logger.DebugLevel, nil
+
+	case "info":
+		return logger.InfoLevel, nil
+
+	case "warn", "warning":
+		return logger.WarnLevel, nil
+
+	case "error":
+		return logger.ErrorLevel, nil
+
+	default:
+		return 0, fmt.Errorf("unknown log level: %s", level)
+
+	}
+}
+
+func (r *runner) alertTimeout() time.Duration {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.config == nil {
+		return 0
+	}
+	return r.config.AlertTimeout
+}
+
+func (r *runner) supervisor() func(BotType, error) *SupervisionDirective {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.superviseError
+}
+
+// watchRunnerConfig subscribes to the runner-level Config in RunnerConfigNamespace and applies the change on the fly.
+func (r *runner) watchRunnerConfig(ctx context.Context) {
+	if r.configWatcher == nil || r.config == nil {
+		return
+	}
+
+	err := r.configWatcher.Watch(ctx, RunnerConfigNamespace, RunnerConfigID, func() {
+		logger.Infof("Updating runner configuration")
+		r.reloadConfig(ctx)
+	})
+	if err != nil {
+		logger.Errorf("Failed to subscribe runner configuration: %+v", err)
+	}
+}
+
+// reloadConfig reads the runner-level Config via ConfigWatcher and applies the settings that can be changed without restart.
+// Those are AlertTimeout, LogLevel, Supervisor, InputFilter, Normalization, and Drain. A change to TimeZone is ignored since the scheduler is already running.
+// The given Config is validated just like Run does, and the current settings are kept when it is invalid.
+func (r *runner) reloadConfig(ctx context.Context) {
+	r.mutex.RLock()
+	current := r.config
+	r.mutex.RUnlock()
+
+	// Copy the current settings so the fields that are not given stay the same.
+	config := *current
+	if current.Supervisor != nil {
+		supervisorConfig := *current.Supervisor
+		config.Supervisor = &supervisorConfig
+	}
+	if current.LogFilter != nil {
+		logFilterConfig := *current.LogFilter
+		config.LogFilter = &logFilterConfig
+	}
+	if current.InputFilter != nil {
+		// Decoding into the shared map would modify the filters that the Bots are referring to.
+		config.InputFilter = make(map[BotType]*InputFilterConfig, len(current.InputFilter))
+		for botType, filter := range current.InputFilter {
+			if filter == nil {
+				continue
+			}
+			inputFilterConfig := *filter
+			config.InputFilter[botType] = &inputFilterConfig
+		}
+	}
+	if current.Normalization != nil {
+		config.Normalization = make(map[BotType]*NormalizationConfig, len(current.Normalization))
+		for botType, normalization := range current.Normalization {
+			if normalization == nil {
+				continue
+			}
+			normalizationConfig := *normalization
+			config.Normalization[botType] = &normalizationConfig
+		}
+	}
+	if current.Drain != nil {
+		config.Drain = make(map[BotType]*DrainConfig, len(current.Drain))
+		for botType, drain := range current.Drain {
+			if drain == nil {
+				continue
+			}
+			drainConfig := *drain
+			config.Drain[botType] = &drainConfig
+		}
+	}
+
+	err := r.configWatcher.Read(ctx, RunnerConfigNamespace, RunnerConfigID, &config)
+	var notFoundErr *ConfigNotFoundError
+	if errors.As(err, &notFoundErr) {
+		logger.Infof("Runner configuration is not found. Keep current settings.")
+		return
+	} else if err != nil {
+		logger.Errorf("Failed to read runner configuration: %+v", err)
+		return
+	}
+
+	if config.TimeZone != current.TimeZone {
+		logger.Warnf("TimeZone can not be changed at runtime. Restart the process to apply %s.", config.TimeZone)
+		config.TimeZone = current.TimeZone
+	}
+
+	if !reflect.DeepEqual(config.LogFilter, current.LogFilter) {
+		logger.Warnf("LogFilter can not be changed at runtime. Restart the process to apply the change.")
+		config.LogFilter = current.LogFilter
+	}
+
+	// Validate the same way Run does so a broken configuration does not replace the working one.
+	if errs := validateConfig(&config); len(errs) > 0 {
+		logger.Errorf("Invalid runner configuration is given. Keep current settings: %+v", errors.Join(errs...))
+		return
+	}
+
+	if config.LogLevel != "" && !logLevelOverridden() {
+		err := SetLogLevel(config.LogLevel)
+		if err != nil {
+			logger.Errorf("Failed to apply log level: %+v", err)
+			config.LogLevel = current.LogLevel
+		}
+	}
+
+	if r.scheduler != nil {
+		r.scheduler.setVerbose(config.SchedulerVerbose)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.config = &config
+	// Rebuild the supervising function only on a change so the errors counted in the current window are kept.
+	if r.configuredSupervisor && !reflect.DeepEqual(config.Supervisor, current.Supervisor) {
+		if config.Supervisor == nil {
+			r.superviseError = nil
+		} else {
+			r.superviseError = config.Supervisor.supervisor()
+		}
+	}
+}
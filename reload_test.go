@@ -0,0 +1,283 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/logger"
+	"gopkg.in/yaml.v2"
+	"testing"
+	"time"
+)
+
+func Test_parseLogLevel(t *testing.T) {
+	testSets := []struct {
+		value    string
+		expected logger.Level
+		hasErr   bool
+	}{
+		{value: "debug", expected: logger.DebugLevel},
+		{value: "INFO", expected: logger.InfoLevel},
+		{value: "warn", expected: logger.WarnLevel},
+		{value: "warning", expected: logger.WarnLevel},
+		{value: "error", expected: logger.ErrorLevel},
+		{value: "verbose", hasErr: true},
+	}
+
+	for i, tt := range testSets {
+		level, err := parseLogLevel(tt.value)
+		if tt.hasErr {
+			if err == nil {
+				t.Errorf("Expected error is not returned on test %d.", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+			continue
+		}
+
+		if level != tt.expected {
+			t.Errorf("Unexpected level is returned on test %d: %s.", i, level)
+		}
+	}
+}
+
+func TestSupervisorConfig_supervisor(t *testing.T) {
+	config := &SupervisorConfig{
+		Window:          time.Minute,
+		MaxCount:        2,
+		RestartBot:      true,
+		RestartCooldown: time.Second,
+		Alert:           true,
+	}
+	supervise := config.supervisor()
+
+	escalated := errors.New("error")
+	if d := supervise("dummy", escalated); d != nil {
+		t.Fatal("Directive should not be returned on the first occurrence.")
+	}
+
+	d := supervise("dummy", escalated)
+	if d == nil {
+		t.Fatal("Expected directive is not returned.")
+	}
+
+	if !d.RestartBot || d.RestartCooldown != time.Second || d.StopBot {
+		t.Errorf("Unexpected directive is returned: %#v.", d)
+	}
+
+	if d.AlertingErr != escalated {
+		t.Errorf("Escalated error is not set to AlertingErr: %#v.", d.AlertingErr)
+	}
+}
+
+func Test_runner_reloadConfig(t *testing.T) {
+	defer logger.SetOutputLevel(logger.DebugLevel)
+
+	given := []byte(`
+timezone: Asia/Tokyo
+alert_timeout: 3s
+log_level: warn
+supervisor:
+  window: 1m
+  max_count: 1
+  stop_bot: true
+`)
+	r := &runner{
+		config: &Config{
+			TimeZone:     "UTC",
+			AlertTimeout: 10 * time.Second,
+		},
+		configuredSupervisor: true,
+		configWatcher: &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, botType BotType, id string, configPtr interface{}) error {
+				if botType != RunnerConfigNamespace || id != RunnerConfigID {
+					t.Errorf("Unexpected namespace is given: %s:%s.", botType, id)
+				}
+				return yaml.Unmarshal(given, configPtr)
+			},
+		},
+	}
+
+	r.reloadConfig(context.TODO())
+
+	if r.alertTimeout() != 3*time.Second {
+		t.Errorf("AlertTimeout is not updated: %s.", r.alertTimeout())
+	}
+
+	if r.config.TimeZone != "UTC" {
+		t.Errorf("TimeZone should not be changed at runtime: %s.", r.config.TimeZone)
+	}
+
+	supervise := r.supervisor()
+	if supervise == nil {
+		t.Fatal("Supervising function is not set.")
+	}
+	if d := supervise("dummy", errors.New("error")); d == nil || !d.StopBot {
+		t.Errorf("Unexpected directive is returned: %#v.", d)
+	}
+}
+
+func Test_runner_reloadConfig_SchedulerVerbose(t *testing.T) {
+	var verbose *bool
+	r := &runner{
+		config: NewConfig(),
+		configWatcher: &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+				return yaml.Unmarshal([]byte("scheduler_verbose: false"), configPtr)
+			},
+		},
+		scheduler: &DummyScheduler{
+			SetVerboseFunc: func(v bool) {
+				verbose = &v
+			},
+		},
+	}
+
+	r.reloadConfig(context.TODO())
+
+	if verbose == nil || *verbose {
+		t.Errorf("Verbose mode is not turned off: %#v.", verbose)
+	}
+}
+
+func Test_runner_reloadConfig_CustomSupervisor(t *testing.T) {
+	custom := func(_ BotType, _ error) *SupervisionDirective {
+		return nil
+	}
+	r := &runner{
+		config:         &Config{},
+		superviseError: custom,
+		configWatcher: &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+				configPtr.(*Config).Supervisor = &SupervisorConfig{MaxCount: 1, StopBot: true}
+				return nil
+			},
+		},
+	}
+
+	r.reloadConfig(context.TODO())
+
+	if d := r.supervisor()("dummy", errors.New("error")); d != nil {
+		t.Error("Registered supervising function should not be replaced.")
+	}
+}
+
+func Test_runner_reloadConfig_Invalid(t *testing.T) {
+	config := &Config{AlertTimeout: time.Second}
+	r := &runner{
+		config:               config,
+		configuredSupervisor: true,
+		configWatcher: &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+				return yaml.Unmarshal([]byte(`
+alert_timeout: 3s
+supervisor:
+  max_count: 0
+`), configPtr)
+			},
+		},
+	}
+
+	r.reloadConfig(context.TODO())
+
+	if r.config != config {
+		t.Error("Current configuration should be kept when the given one is invalid.")
+	}
+
+	if r.supervisor() != nil {
+		t.Error("Supervising function should not be set from an invalid configuration.")
+	}
+}
+
+func Test_runner_reloadConfig_UnchangedSupervisor(t *testing.T) {
+	supervisorConfig := &SupervisorConfig{
+		Window:   time.Minute,
+		MaxCount: 2,
+		StopBot:  true,
+	}
+	r := &runner{
+		config: &Config{
+			AlertTimeout: time.Second,
+			Supervisor:   supervisorConfig,
+		},
+		configuredSupervisor: true,
+		superviseError:       supervisorConfig.supervisor(),
+		configWatcher: &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+				return yaml.Unmarshal([]byte("alert_timeout: 3s"), configPtr)
+			},
+		},
+	}
+
+	escalated := errors.New("error")
+	if d := r.supervisor()("dummy", escalated); d != nil {
+		t.Fatal("Directive should not be returned on the first occurrence.")
+	}
+
+	r.reloadConfig(context.TODO())
+
+	if r.alertTimeout() != 3*time.Second {
+		t.Errorf("AlertTimeout is not updated: %s.", r.alertTimeout())
+	}
+
+	// The occurrence before the reload must still be counted.
+	if d := r.supervisor()("dummy", escalated); d == nil || !d.StopBot {
+		t.Errorf("Supervising state is not kept over the reload: %#v.", d)
+	}
+}
+
+func Test_runner_reloadConfig_NotFound(t *testing.T) {
+	config := &Config{AlertTimeout: time.Second}
+	r := &runner{
+		config: config,
+		configWatcher: &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, botType BotType, id string, _ interface{}) error {
+				return &ConfigNotFoundError{BotType: botType, ID: id}
+			},
+		},
+	}
+
+	r.reloadConfig(context.TODO())
+
+	if r.config != config {
+		t.Error("Current configuration should be kept.")
+	}
+}
+
+func Test_runner_watchRunnerConfig(t *testing.T) {
+	var callback func()
+	read := make(chan struct{}, 1)
+	r := &runner{
+		config: &Config{},
+		configWatcher: &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, _ BotType, _ string, _ interface{}) error {
+				read <- struct{}{}
+				return nil
+			},
+			WatchFunc: func(_ context.Context, botType BotType, id string, fnc func()) error {
+				if botType != RunnerConfigNamespace || id != RunnerConfigID {
+					t.Errorf("Unexpected namespace is given: %s:%s.", botType, id)
+				}
+				callback = fnc
+				return nil
+			},
+		},
+	}
+
+	r.watchRunnerConfig(context.TODO())
+	if callback == nil {
+		t.Fatal("Callback is not registered.")
+	}
+
+	callback()
+	select {
+	case <-read:
+		// O.K.
+
+	default:
+		t.Error("Configuration is not read on callback.")
+
+	}
+}
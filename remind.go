@@ -0,0 +1,144 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrRemindersUnavailable is returned by RemindAt when the given context is not the one passed to Command's or ScheduledTask's execution.
+	ErrRemindersUnavailable = errors.New("reminders are not available in the given context")
+
+	// ErrDirectMessageUnsupported is returned when the Bot can not resolve a direct message destination for a user.
+	ErrDirectMessageUnsupported = errors.New("direct message is not supported")
+
+	// ErrReminderInPast is returned by RemindAt when the given time is not in the future.
+	ErrReminderInPast = errors.New("reminder time is in the past")
+)
+
+// reminderSequence is used to give each reminder a unique identifier.
+var reminderSequence uint64
+
+// DirectMessageResolver defines an interface that an Adapter implementation may satisfy to send a direct message to a user.
+// When an Adapter passed to NewBot satisfies this, the Bot also satisfies this and RemindAt can deliver reminders to the users.
+type DirectMessageResolver interface {
+	// DirectMessageDestination returns the OutputDestination to send a direct message to the user with the given Input.SenderKey.
+	DirectMessageDestination(ctx context.Context, senderKey string) (OutputDestination, error)
+}
+
+// Reminder represents a scheduled reminder.
+type Reminder struct {
+	// ID is the unique identifier of the reminder. Pass this to CancelReminder to cancel the reminder.
+	ID string
+
+	// SenderKey is the user to be reminded.
+	SenderKey string
+
+	// At is the delivery time in the scheduler's location, which is declared by Config.TimeZone.
+	At time.Time
+
+	// Destination is the user's direct message destination.
+	Destination OutputDestination
+}
+
+// reminders holds what RemindAt requires to schedule a reminder for a Bot.
+type reminders struct {
+	ctx       context.Context
+	bot       Bot
+	scheduler scheduler
+	location  *time.Location
+}
+
+type remindersKey struct{}
+
+func withReminders(ctx context.Context, r *reminders) context.Context {
+	return context.WithValue(ctx, remindersKey{}, r)
+}
+
+func remindersFromContext(ctx context.Context) *reminders {
+	r, _ := ctx.Value(remindersKey{}).(*reminders)
+	return r
+}
+
+// RemindAt schedules a reminder that sends the given content to the user's direct message destination at the given local time.
+// The given context must be the one passed to Command's or ScheduledTask's execution.
+//
+// The year, month, day, hour, minute, and second of localTime are interpreted in the user's time zone stored in Preferences,
+// so a Command can pass a time parsed from the user's input such as "tomorrow 9:00" without caring about the user's location.
+// When no Preferences is registered or the user has no time zone preference, the scheduler's location declared by Config.TimeZone is used.
+// The direct message destination is resolved by the Bot's Adapter that satisfies DirectMessageResolver.
+//
+//	at, _ := time.Parse("2006-01-02 15:04", "2024-04-01 09:00")
+//	reminder, err := sarah.RemindAt(ctx, input.SenderKey(), at, "Time for the stand-up.")
+func RemindAt(ctx context.Context, senderKey string, localTime time.Time, content interface{}) (*Reminder, error) {
+	r := remindersFromContext(ctx)
+	if r == nil {
+		return nil, ErrRemindersUnavailable
+	}
+
+	schedulerLocation := r.location
+	if schedulerLocation == nil {
+		schedulerLocation = time.Local
+	}
+
+	location := schedulerLocation
+	if preferences := PreferencesFromContext(ctx); preferences != nil {
+		preference, err := preferences.Get(ctx, r.bot.BotType(), senderKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read preference of %s: %w", senderKey, err)
+		}
+
+		location, err = preference.Location(schedulerLocation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	at := time.Date(localTime.Year(), localTime.Month(), localTime.Day(),
+		localTime.Hour(), localTime.Minute(), localTime.Second(), localTime.Nanosecond(), location).In(schedulerLocation)
+	if !at.After(currentClock().Now()) {
+		return nil, ErrReminderInPast
+	}
+
+	resolver, ok := r.bot.(DirectMessageResolver)
+	if !ok {
+		return nil, ErrDirectMessageUnsupported
+	}
+	dest, err := resolver.DirectMessageDestination(ctx, senderKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve direct message destination of %s: %w", senderKey, err)
+	}
+
+	reminder := &Reminder{
+		ID:          fmt.Sprintf("reminder:%d", atomic.AddUint64(&reminderSequence, 1)),
+		SenderKey:   senderKey,
+		At:          at,
+		Destination: dest,
+	}
+	err = r.scheduler.once(r.bot.BotType(), reminder.ID, at, func() {
+		logger.Infof("Sending reminder %s to %s", reminder.ID, senderKey)
+		r.bot.SendMessage(r.ctx, NewOutputMessage(dest, content))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule reminder: %w", err)
+	}
+
+	return reminder, nil
+}
+
+// CancelReminder cancels the reminder with the given identifier.
+// The given context must be the one passed to Command's or ScheduledTask's execution.
+// This does nothing when the reminder is already sent or canceled.
+func CancelReminder(ctx context.Context, id string) error {
+	r := remindersFromContext(ctx)
+	if r == nil {
+		return ErrRemindersUnavailable
+	}
+
+	r.scheduler.remove(r.bot.BotType(), id)
+	return nil
+}
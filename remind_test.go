@@ -0,0 +1,151 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type DummyDirectMessageAdapter struct {
+	DummyAdapter
+	DirectMessageDestinationFunc func(context.Context, string) (OutputDestination, error)
+}
+
+func (adapter *DummyDirectMessageAdapter) DirectMessageDestination(ctx context.Context, senderKey string) (OutputDestination, error) {
+	return adapter.DirectMessageDestinationFunc(ctx, senderKey)
+}
+
+func TestDefaultBot_DirectMessageDestination(t *testing.T) {
+	bot := NewBot(&DummyAdapter{}).(*defaultBot)
+	_, err := bot.DirectMessageDestination(context.TODO(), "user")
+	if !errors.Is(err, ErrDirectMessageUnsupported) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	adapter := &DummyDirectMessageAdapter{
+		DirectMessageDestinationFunc: func(_ context.Context, senderKey string) (OutputDestination, error) {
+			return "dm:" + senderKey, nil
+		},
+	}
+	bot = NewBot(adapter).(*defaultBot)
+	dest, err := bot.DirectMessageDestination(context.TODO(), "user")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if dest != "dm:user" {
+		t.Errorf("Unexpected destination is returned: %#v.", dest)
+	}
+}
+
+func TestRemindAt(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("Time zone database is not available: %s.", err.Error())
+	}
+
+	var botType BotType = "dummy"
+	sent := make(chan Output, 1)
+	adapter := &DummyDirectMessageAdapter{
+		DummyAdapter: DummyAdapter{
+			BotTypeValue: botType,
+			SendMessageFunc: func(_ context.Context, output Output) {
+				sent <- output
+			},
+		},
+		DirectMessageDestinationFunc: func(_ context.Context, senderKey string) (OutputDestination, error) {
+			return "dm:" + senderKey, nil
+		},
+	}
+
+	var scheduledID string
+	var scheduledAt time.Time
+	var job func()
+	scheduler := &DummyScheduler{
+		OnceFunc: func(givenBotType BotType, id string, at time.Time, fn func()) error {
+			if givenBotType != botType {
+				t.Errorf("Unexpected BotType is given: %s.", givenBotType)
+			}
+			scheduledID = id
+			scheduledAt = at
+			job = fn
+			return nil
+		},
+		RemoveFunc: func(_ BotType, id string) {
+			if id != scheduledID {
+				t.Errorf("Unexpected ID is given: %s.", id)
+			}
+		},
+	}
+
+	preferences := NewInMemoryPreferences()
+	_ = preferences.Set(context.TODO(), botType, "user", &UserPreference{TimeZone: "Asia/Tokyo"})
+
+	ctx := withPreferences(context.TODO(), preferences)
+	ctx = withReminders(ctx, &reminders{
+		ctx:       ctx,
+		bot:       NewBot(adapter),
+		scheduler: scheduler,
+		location:  time.UTC,
+	})
+
+	next := time.Now().Add(48 * time.Hour)
+	local := time.Date(next.Year(), next.Month(), next.Day(), 9, 0, 0, 0, time.UTC)
+	reminder, err := RemindAt(ctx, "user", local, "Stand-up")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	expected := time.Date(next.Year(), next.Month(), next.Day(), 9, 0, 0, 0, tokyo).In(time.UTC)
+	if !reminder.At.Equal(expected) || !scheduledAt.Equal(expected) {
+		t.Errorf("Unexpected time is scheduled: %s.", scheduledAt)
+	}
+	if reminder.At.Location() != time.UTC {
+		t.Errorf("Time is not converted to the scheduler's location: %s.", reminder.At.Location())
+	}
+	if reminder.ID != scheduledID {
+		t.Errorf("Unexpected ID is returned: %s.", reminder.ID)
+	}
+	if reminder.Destination != "dm:user" {
+		t.Errorf("Unexpected destination is returned: %#v.", reminder.Destination)
+	}
+
+	job()
+	output := <-sent
+	if output.Destination() != "dm:user" || output.Content() != "Stand-up" {
+		t.Errorf("Unexpected output is sent: %#v.", output)
+	}
+
+	err = CancelReminder(ctx, reminder.ID)
+	if err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+}
+
+func TestRemindAt_Error(t *testing.T) {
+	_, err := RemindAt(context.TODO(), "user", time.Now().Add(time.Hour), "content")
+	if !errors.Is(err, ErrRemindersUnavailable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	err = CancelReminder(context.TODO(), "id")
+	if !errors.Is(err, ErrRemindersUnavailable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	ctx := withReminders(context.TODO(), &reminders{
+		bot:       NewBot(&DummyAdapter{}),
+		scheduler: &DummyScheduler{},
+		location:  time.UTC,
+	})
+
+	_, err = RemindAt(ctx, "user", time.Now().In(time.UTC).Add(-time.Minute), "content")
+	if !errors.Is(err, ErrReminderInPast) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	_, err = RemindAt(ctx, "user", time.Now().In(time.UTC).Add(time.Hour), "content")
+	if !errors.Is(err, ErrDirectMessageUnsupported) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
@@ -0,0 +1,62 @@
+package sarah
+
+import (
+	"context"
+	"fmt"
+)
+
+// CommandErrorResponder defines a function that converts an error returned by Bot.Respond -- typically the one returned by Command execution -- to a response to the user.
+// Returning nil sends nothing to the user.
+// The error is still logged regardless of the returned value.
+type CommandErrorResponder func(ctx context.Context, input Input, err error) *CommandResponse
+
+// RegisterCommandErrorResponder registers a given CommandErrorResponder for the Bot with the given BotType.
+// Without one, the user receives no response when a Command fails and only the log tells the failure.
+//
+//	sarah.RegisterCommandErrorResponder(slack.SLACK, sarah.NewCommandErrorResponder("Something went wrong.", func(input sarah.Input) bool {
+//		return isAdmin(input.SenderKey())
+//	}))
+func RegisterCommandErrorResponder(botType BotType, responder CommandErrorResponder) {
+	defaultRunner().RegisterCommandErrorResponder(botType, responder)
+}
+
+// RegisterCommandErrorResponder is the Runner counterpart of the package-level RegisterCommandErrorResponder.
+func (rn *Runner) RegisterCommandErrorResponder(botType BotType, responder CommandErrorResponder) {
+	rn.options.register(func(r *runner) {
+		if r.commandErrorResponders == nil {
+			r.commandErrorResponders = make(map[BotType]CommandErrorResponder)
+		}
+		r.commandErrorResponders[botType] = responder
+	})
+}
+
+// NewCommandErrorResponder creates and returns a CommandErrorResponder that replies the given generic message to the user.
+// When the given detailed function returns true for the Input, e.g., the user is an administrator, the error detail is appended to the message.
+// Pass nil as detailed to always reply the generic message so the internal details are never exposed to the users.
+func NewCommandErrorResponder(generic string, detailed func(Input) bool) CommandErrorResponder {
+	return func(_ context.Context, input Input, err error) *CommandResponse {
+		message := generic
+		if detailed != nil && detailed(input) {
+			message = fmt.Sprintf("%s %s", generic, err.Error())
+		}
+		return &CommandResponse{
+			Content:     message,
+			UserContext: nil,
+		}
+	}
+}
+
+// respondCommandError sends the response built by the given CommandErrorResponder to where the Input came from.
+func respondCommandError(ctx context.Context, bot Bot, responder CommandErrorResponder, input Input, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			LoggerFromContext(ctx).Errorf("Panic on command error response: %+v", r)
+		}
+	}()
+
+	res := responder(ctx, input, err)
+	if res == nil || res.Content == nil {
+		return
+	}
+	bot.SendMessage(ctx, NewOutputMessage(input.ReplyTo(), res.Content))
+}
@@ -0,0 +1,132 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegisterCommandErrorResponder(t *testing.T) {
+	SetupAndRun(func() {
+		called := false
+		RegisterCommandErrorResponder("dummy", func(_ context.Context, _ Input, _ error) *CommandResponse {
+			called = true
+			return nil
+		})
+		r := &runner{}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		responder, ok := r.commandErrorResponders["dummy"]
+		if !ok {
+			t.Fatal("Given CommandErrorResponder is not set.")
+		}
+
+		responder(context.TODO(), &DummyInput{}, errors.New("dummy"))
+		if !called {
+			t.Error("Given CommandErrorResponder is not set.")
+		}
+	})
+}
+
+func TestNewCommandErrorResponder(t *testing.T) {
+	testSets := []struct {
+		detailed func(Input) bool
+		expected string
+	}{
+		{
+			detailed: nil,
+			expected: "Something went wrong.",
+		},
+		{
+			detailed: func(_ Input) bool {
+				return false
+			},
+			expected: "Something went wrong.",
+		},
+		{
+			detailed: func(_ Input) bool {
+				return true
+			},
+			expected: "Something went wrong. connection refused",
+		},
+	}
+
+	for i, tt := range testSets {
+		responder := NewCommandErrorResponder("Something went wrong.", tt.detailed)
+		res := responder(context.TODO(), &DummyInput{}, errors.New("connection refused"))
+
+		if res == nil {
+			t.Fatalf("Response is not returned on test #%d.", i+1)
+		}
+		if res.Content != tt.expected {
+			t.Errorf("Unexpected content is returned on test #%d: %#v.", i+1, res.Content)
+		}
+	}
+}
+
+func Test_setupInputReceiver_CommandErrorResponder(t *testing.T) {
+	worker := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			fnc()
+			return nil
+		},
+	}
+
+	respondErr := errors.New("execution failure")
+	var sent Output
+	bot := &DummyBot{
+		BotTypeValue: "DUMMY",
+		RespondFunc: func(_ context.Context, _ Input) error {
+			return respondErr
+		},
+		SendMessageFunc: func(_ context.Context, output Output) {
+			sent = output
+		},
+	}
+
+	var givenErr error
+	responder := func(_ context.Context, _ Input, err error) *CommandResponse {
+		givenErr = err
+		return &CommandResponse{Content: "failed"}
+	}
+
+	input := &DummyInput{
+		ReplyToValue: "room",
+	}
+	receiveInput := setupInputReceiver(context.TODO(), bot, worker, responder)
+	if err := receiveInput(input); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if givenErr != respondErr {
+		t.Errorf("Unexpected error is given: %#v.", givenErr)
+	}
+	if sent == nil {
+		t.Fatal("Error response is not sent.")
+	}
+	if sent.Destination() != "room" || sent.Content() != "failed" {
+		t.Errorf("Unexpected output is sent: %#v.", sent)
+	}
+}
+
+func Test_respondCommandError(t *testing.T) {
+	bot := &DummyBot{
+		BotTypeValue: "DUMMY",
+		SendMessageFunc: func(_ context.Context, _ Output) {
+			t.Error("Message must not be sent.")
+		},
+	}
+
+	// Nil response sends nothing.
+	respondCommandError(context.TODO(), bot, func(_ context.Context, _ Input, _ error) *CommandResponse {
+		return nil
+	}, &DummyInput{}, errors.New("dummy"))
+
+	// Panic is recovered.
+	respondCommandError(context.TODO(), bot, func(_ context.Context, _ Input, _ error) *CommandResponse {
+		panic("panic!")
+	}, &DummyInput{}, errors.New("dummy"))
+}
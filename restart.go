@@ -0,0 +1,91 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+)
+
+// ErrRunnerRestarting is the cause of the cancellation when Restart stops the current runner.
+// LifecycleEvent.Reason of RunnerStopped is set to this error so a LifecycleHook can tell a restart from a shutdown.
+var ErrRunnerRestarting = errors.New("go-sarah's process is restarting")
+
+// RestartOption defines a function's signature that Restart accepts to customize the restart.
+type RestartOption func(*restartOptions)
+
+type restartOptions struct {
+	registrations []func()
+}
+
+// RestartWithRegistration creates and returns a RestartOption that calls the given function before the current runner stops.
+// The registration functions such as RegisterBot and RegisterCommandProps can be called in the function to add components that require a new runner,
+// e.g., a Bot with a newly configured Adapter. Such a Bot replaces the registered one with the same BotType.
+// The registrations take effect on the new runner, and are discarded when they are invalid.
+//
+//	err := sarah.Restart(ctx, config, sarah.RestartWithRegistration(func() {
+//		sarah.RegisterBot(sarah.NewBot(newAdapter))
+//	}))
+func RestartWithRegistration(fnc func()) RestartOption {
+	return func(opts *restartOptions) {
+		opts.registrations = append(opts.registrations, fnc)
+	}
+}
+
+// Restart gracefully stops the running Sarah and starts a new one with the given Config without restarting the process.
+// The Bots drain their in-flight Inputs as declared by Config.Drain of the current Config before their contexts are canceled,
+// and then the new runner is built from all the registrations so far just like Run does.
+// This is handy to apply a change that can not be applied at runtime such as Config.TimeZone and a new Bot.
+//
+// The given ctx is the lifetime of the new runner just like the one given to Run.
+// When ctx is canceled before the current runner stops, this returns without starting the new one, and the current one keeps stopping.
+// The given Config and the registrations, including the ones given via RestartWithRegistration, are validated before the current runner stops,
+// so a broken Config or a conflicting BotType does not stop Sarah.
+// When the new runner still fails to start, Sarah stays stopped and Run can be called again after the cause is fixed.
+func Restart(ctx context.Context, config *Config, opts ...RestartOption) error {
+	return defaultRunner().Restart(ctx, config, opts...)
+}
+
+// Restart is the Runner counterpart of the package-level Restart.
+func (rn *Runner) Restart(ctx context.Context, config *Config, opts ...RestartOption) error {
+	r := rn.running.get()
+	if r == nil || r.stopped == nil {
+		return ErrRunnerNotRunning
+	}
+
+	restartOpts := &restartOptions{}
+	for _, opt := range opts {
+		opt(restartOpts)
+	}
+
+	// Validate the new registration set along with the Config before stopping the current runner.
+	discard := rn.options.stage(restartOpts.registrations)
+	if _, errs := prepareRunner(config, rn.options); len(errs) > 0 {
+		discard()
+		return fmt.Errorf("failed to restart bot process: %w", errors.Join(errs...))
+	}
+
+	logger.Info("Stopping the current runner to restart")
+	r.cancel(ErrRunnerRestarting)
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("failed to restart bot process: %w", context.Cause(ctx))
+
+	case <-r.stopped:
+		// All Bots stopped.
+
+	}
+
+	rn.running.set(nil)
+	rn.status.reset()
+	rn.options.unseal()
+
+	logger.Info("Starting a new runner")
+	err := rn.Run(ctx, config)
+	if err != nil {
+		// Let Run be called again.
+		rn.status.reset()
+		return err
+	}
+	return nil
+}
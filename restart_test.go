@@ -0,0 +1,269 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRestartWithRegistration(t *testing.T) {
+	called := false
+	opts := &restartOptions{}
+	RestartWithRegistration(func() {
+		called = true
+	})(opts)
+
+	if len(opts.registrations) != 1 {
+		t.Fatalf("Unexpected number of registrations are set: %d.", len(opts.registrations))
+	}
+	opts.registrations[0]()
+	if !called {
+		t.Error("Given function is not set.")
+	}
+}
+
+func TestRunner_Restart_NotRunning(t *testing.T) {
+	err := New().Restart(context.Background(), NewConfig())
+	if !errors.Is(err, ErrRunnerNotRunning) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestRunner_Restart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan BotType, 3)
+	causes := make(chan error, 3)
+	newBot := func(botType BotType) Bot {
+		return &DummyBot{
+			BotTypeValue:      botType,
+			AppendCommandFunc: func(_ Command) {},
+			RunFunc: func(ctx context.Context, _ func(Input) error, _ func(error)) {
+				started <- botType
+				<-ctx.Done()
+				causes <- context.Cause(ctx)
+			},
+		}
+	}
+	waitStart := func(expected ...BotType) {
+		for range expected {
+			select {
+			case <-started:
+				// O.K.
+
+			case <-time.NewTimer(10 * time.Second).C:
+				t.Fatal("Bot is not started.")
+
+			}
+		}
+	}
+
+	var reasons []error
+	var mutex sync.Mutex
+	rn := New()
+	rn.RegisterBot(newBot("first"))
+	rn.RegisterLifecycleHook(func(event *LifecycleEvent) {
+		if event.Type != RunnerStopped {
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		reasons = append(reasons, event.Reason)
+	})
+
+	config := NewConfig()
+	config.TimeZone = time.UTC.String()
+	err := rn.Run(ctx, config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	waitStart("first")
+
+	// An invalid Config does not stop the current runner.
+	invalid := NewConfig()
+	invalid.TimeZone = "DUMMY"
+	err = rn.Restart(ctx, invalid)
+	if err == nil {
+		t.Fatal("Expected error is not returned.")
+	}
+	if !rn.Status().Running {
+		t.Fatal("Runner should keep running.")
+	}
+
+	err = rn.Restart(ctx, config, RestartWithRegistration(func() {
+		rn.RegisterBot(newBot("second"))
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	waitStart("first", "second")
+
+	cause := <-causes
+	if !errors.Is(cause, ErrRunnerRestarting) {
+		t.Errorf("Unexpected cause is given to the Bot: %#v.", cause)
+	}
+
+	status := rn.Status()
+	if !status.Running || len(status.Bots) != 2 {
+		t.Errorf("Unexpected status is returned: %#v.", status)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(reasons) != 1 || !errors.Is(reasons[0], ErrRunnerRestarting) {
+		t.Errorf("Unexpected RunnerStopped events are notified: %#v.", reasons)
+	}
+}
+
+func TestRunner_Restart_ReplaceBot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan string, 2)
+	newBot := func(adapter string) Bot {
+		return &DummyBot{
+			BotTypeValue:      "dummy",
+			AppendCommandFunc: func(_ Command) {},
+			RunFunc: func(ctx context.Context, _ func(Input) error, _ func(error)) {
+				started <- adapter
+				<-ctx.Done()
+			},
+		}
+	}
+	waitStart := func(expected string) {
+		select {
+		case adapter := <-started:
+			if adapter != expected {
+				t.Errorf("Unexpected Bot is started: %s.", adapter)
+			}
+
+		case <-time.NewTimer(10 * time.Second).C:
+			t.Fatal("Bot is not started.")
+
+		}
+	}
+
+	rn := New()
+	rn.RegisterBot(newBot("old"))
+
+	config := NewConfig()
+	config.TimeZone = time.UTC.String()
+	err := rn.Run(ctx, config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	waitStart("old")
+
+	err = rn.Restart(ctx, config, RestartWithRegistration(func() {
+		rn.RegisterBot(newBot("new"))
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	waitStart("new")
+
+	status := rn.Status()
+	if !status.Running || len(status.Bots) != 1 {
+		t.Errorf("Unexpected status is returned: %#v.", status)
+	}
+
+	// A Bot registered outside of RestartWithRegistration does not replace the registered one.
+	defer func() {
+		recovered := recover()
+		if recovered != ErrRegistrationAfterRun {
+			t.Errorf("Expected panic is not given: %#v.", recovered)
+		}
+	}()
+	rn.RegisterBot(newBot("other"))
+}
+
+func TestRunner_Restart_InvalidRegistration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rn := New()
+	rn.RegisterBot(&DummyBot{
+		BotTypeValue:      "dummy",
+		AppendCommandFunc: func(_ Command) {},
+		RunFunc: func(ctx context.Context, _ func(Input) error, _ func(error)) {
+			<-ctx.Done()
+		},
+	})
+
+	config := NewConfig()
+	config.TimeZone = time.UTC.String()
+	err := rn.Run(ctx, config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	err = rn.Restart(ctx, config, RestartWithRegistration(func() {
+		rn.RegisterBot(nil)
+	}))
+	if err == nil {
+		t.Fatal("Expected error is not returned.")
+	}
+	if !rn.Status().Running {
+		t.Fatal("Runner should keep running.")
+	}
+
+	// The invalid registration is discarded.
+	_, errs := prepareRunner(config, rn.options)
+	if len(errs) > 0 {
+		t.Errorf("Invalid registration is not discarded: %#v.", errs)
+	}
+
+	defer func() {
+		recovered := recover()
+		if recovered != ErrRegistrationAfterRun {
+			t.Errorf("Options should be sealed again: %#v.", recovered)
+		}
+	}()
+	rn.RegisterBot(&DummyBot{BotTypeValue: "other"})
+}
+
+func TestRunner_Restart_ContextCanceled(t *testing.T) {
+	stop := make(chan struct{})
+	rn := New()
+	rn.RegisterBot(&DummyBot{
+		BotTypeValue:      "stuck",
+		AppendCommandFunc: func(_ Command) {},
+		RunFunc: func(_ context.Context, _ func(Input) error, _ func(error)) {
+			// Keep running regardless of the cancellation.
+			<-stop
+		},
+	})
+	defer close(stop)
+
+	config := NewConfig()
+	config.TimeZone = time.UTC.String()
+	err := rn.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = rn.Restart(ctx, config)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func Test_status_reset(t *testing.T) {
+	s := &status{}
+	_ = s.start()
+	s.addBot(&DummyBot{BotTypeValue: "dummy"})
+
+	s.reset()
+
+	if s.running() || len(s.snapshot().Bots) != 0 {
+		t.Error("Status is not reset.")
+	}
+	if err := s.start(); err != nil {
+		t.Errorf("Status should be started again: %s.", err.Error())
+	}
+}
@@ -2,11 +2,12 @@ package sarah
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/oklahomer/go-kasumi/logger"
 	"github.com/oklahomer/go-kasumi/worker"
-	"runtime"
-	"strings"
+	"reflect"
+	"runtime/debug"
 	"sync"
 	"time"
 )
@@ -17,13 +18,58 @@ var options = &optionHolder{}
 type Config struct {
 	// TimeZone tells the scheduler in what timezone the application runs.
 	TimeZone string `json:"timezone" yaml:"timezone"`
+
+	// AlertTimeout declares the time limit for each registered Alerter to send an alert.
+	// Alerters run concurrently, so a slow Alerter does not delay the others. Zero value means no timeout.
+	AlertTimeout time.Duration `json:"alert_timeout" yaml:"alert_timeout"`
+
+	// LogLevel declares the output level of the logger: "debug," "info," "warn," or "error."
+	// When this is empty, the logger's current output level is left as-is.
+	// This is ignored when the environment variable declared by LogLevelEnv is set.
+	LogLevel string `json:"log_level" yaml:"log_level"`
+
+	// LogFilter declares the LogFilters to apply to the logger such as sampling of repetitive Debug lines and redaction of secrets.
+	// The filters wrap the logger.Logger that is set via logger.SetLogger at the time of Run, so call logger.SetLogger beforehand to use a customized one.
+	// This can not be changed at runtime.
+	LogFilter *LogFilterConfig `json:"log_filter" yaml:"log_filter"`
+
+	// SchedulerVerbose declares whether to output the scheduler's informational logs such as the execution of each ScheduledTask.
+	// Skipped runs and recovered panics are always logged regardless of this setting.
+	SchedulerVerbose bool `json:"scheduler_verbose" yaml:"scheduler_verbose"`
+
+	// Supervisor declares the thresholds of the built-in supervising function.
+	// This is used only when no supervising function is registered via RegisterBotErrorSupervisor.
+	Supervisor *SupervisorConfig `json:"supervisor" yaml:"supervisor"`
+
+	// InputFilter declares the restriction on the Inputs that each Bot handles, such as allowed channels and denied users.
+	// The key is the BotType, and a Bot without an entry handles every Input.
+	InputFilter map[BotType]*InputFilterConfig `json:"input_filter" yaml:"input_filter"`
+
+	// Normalization declares the normalizations applied to the message of each Input before Command matching.
+	// The key is the BotType, and a Bot without an entry handles every message as-is.
+	Normalization map[BotType]*NormalizationConfig `json:"normalization" yaml:"normalization"`
+
+	// Drain declares how each Bot drains its in-flight Inputs on shutdown before its context is canceled.
+	// The key is the BotType, and a Bot without an entry is stopped immediately.
+	Drain map[BotType]*DrainConfig `json:"drain" yaml:"drain"`
+
+	// PanicStackDepth declares the number of the topmost stack frames to include in the alert when a Bot panics.
+	// Zero value means all frames. This is ignored when a PanicFormatter is registered via RegisterPanicFormatter.
+	PanicStackDepth int `json:"panic_stack_depth" yaml:"panic_stack_depth"`
+
+	// ConfigHistory declares the number of configuration snapshots to keep in memory for each Command and ScheduledTask so RollbackConfig can re-apply a prior one.
+	// Zero value disables the history and RollbackConfig.
+	ConfigHistory int `json:"config_history" yaml:"config_history"`
 }
 
 // NewConfig creates and returns a new Config instance with default settings.
 // Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
 func NewConfig() *Config {
 	return &Config{
-		TimeZone: time.Now().Location().String(),
+		TimeZone:         time.Now().Location().String(),
+		AlertTimeout:     10 * time.Second,
+		SchedulerVerbose: true,
+		ConfigHistory:    5,
 	}
 }
 
@@ -34,15 +80,83 @@ func NewConfig() *Config {
 type optionHolder struct {
 	mutex   sync.RWMutex
 	stashed []func(*runner)
+
+	// sealed tells if Run already applied the stashed options, so further registrations never take effect.
+	sealed bool
+
+	// lenient tells if a registration after Run is only logged instead of causing a panic. See SetStrictRegistration.
+	lenient bool
+
+	// staging tells if the registration functions are called via RestartWithRegistration.
+	// A Bot registered while staging replaces the registered one with the same BotType.
+	staging bool
 }
 
 func (o *optionHolder) register(opt func(*runner)) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
+	if o.sealed {
+		if !o.lenient {
+			panic(ErrRegistrationAfterRun)
+		}
+		logger.Errorf("Ignore the registration: %s. Use the runtime registration functions such as AddCommand instead.", ErrRegistrationAfterRun.Error())
+		return
+	}
+
 	o.stashed = append(o.stashed, opt)
 }
 
+func (o *optionHolder) seal() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.sealed = true
+}
+
+// stage calls the given registration functions while the current runner is still running so Restart can validate the new registration set before stopping the runner.
+// The returned function discards the staged options when the validation fails.
+func (o *optionHolder) stage(registrations []func()) func() {
+	o.mutex.Lock()
+	staged := len(o.stashed)
+	sealed := o.sealed
+	o.sealed = false
+	o.staging = true
+	o.mutex.Unlock()
+
+	defer func() {
+		o.mutex.Lock()
+		defer o.mutex.Unlock()
+		o.sealed = sealed
+		o.staging = false
+	}()
+
+	for _, register := range registrations {
+		register()
+	}
+
+	return func() {
+		o.mutex.Lock()
+		defer o.mutex.Unlock()
+		o.stashed = o.stashed[:staged]
+	}
+}
+
+func (o *optionHolder) isStaging() bool {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return o.staging
+}
+
+// unseal lets the registration functions stash options again so Restart can build a new runner with them.
+func (o *optionHolder) unseal() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.sealed = false
+}
+
 func (o *optionHolder) apply(r *runner) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
@@ -56,15 +170,47 @@ func (o *optionHolder) apply(r *runner) {
 // When Sarah's process or a registered Bot implementation encounters a critical state, Alerter.Alert is called to notify such state.
 // A developer may call this method multiple times to register multiple Alerters.
 func RegisterAlerter(alerter Alerter) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterAlerter(alerter)
+}
+
+// RegisterAlerter is the Runner counterpart of the package-level RegisterAlerter.
+func (rn *Runner) RegisterAlerter(alerter Alerter) {
+	rn.options.register(func(r *runner) {
+		if alerter == nil {
+			r.registrationErrors = append(r.registrationErrors, errors.New("nil Alerter is given to RegisterAlerter"))
+			return
+		}
 		r.alerters.appendAlerter(alerter)
 	})
 }
 
 // RegisterBot registers a given Bot implementation to be run on Run call.
 // This may be called multiple times to register as many bot instances as wanted.
+// Each Bot must have a unique BotType, or Run returns an error listing the conflicting Bots.
+// To run the same Adapter implementation more than once, name each Bot with BotWithInstanceName.
+// When this is called in the function given to RestartWithRegistration, the Bot replaces the registered one with the same BotType.
 func RegisterBot(bot Bot) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterBot(bot)
+}
+
+// RegisterBot is the Runner counterpart of the package-level RegisterBot.
+func (rn *Runner) RegisterBot(bot Bot) {
+	replace := rn.options.isStaging()
+	rn.options.register(func(r *runner) {
+		if bot == nil {
+			r.registrationErrors = append(r.registrationErrors, errors.New("nil Bot is given to RegisterBot"))
+			return
+		}
+
+		if replace {
+			bots := make([]Bot, 0, len(r.bots))
+			for _, registered := range r.bots {
+				if registered.BotType() != bot.BotType() {
+					bots = append(bots, registered)
+				}
+			}
+			r.bots = bots
+		}
 		r.bots = append(r.bots, bot)
 	})
 }
@@ -73,7 +219,16 @@ func RegisterBot(bot Bot) {
 // On Run, each Command implementation is registered to the corresponding bot via Bot.AppendCommand.
 // A Bot is considered to "correspond" when its BotType matches with the botType.
 func RegisterCommand(botType BotType, command Command) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterCommand(botType, command)
+}
+
+// RegisterCommand is the Runner counterpart of the package-level RegisterCommand.
+func (rn *Runner) RegisterCommand(botType BotType, command Command) {
+	rn.options.register(func(r *runner) {
+		if command == nil {
+			r.registrationErrors = append(r.registrationErrors, fmt.Errorf("nil Command is given to RegisterCommand for %s", botType))
+			return
+		}
 		commands, ok := r.commands[botType]
 		if !ok {
 			commands = []Command{}
@@ -85,19 +240,35 @@ func RegisterCommand(botType BotType, command Command) {
 // RegisterCommandProps registers a given CommandProps to build Command implementation on Run call.
 // This instance is reused when a configuration is updated and the corresponding Command needs to be rebuilt to reflect the changes.
 func RegisterCommandProps(props *CommandProps) {
-	options.register(func(r *runner) {
-		stashed, ok := r.commandProps[props.botType]
-		if !ok {
-			stashed = []*CommandProps{}
+	defaultRunner().RegisterCommandProps(props)
+}
+
+// RegisterCommandProps is the Runner counterpart of the package-level RegisterCommandProps.
+func (rn *Runner) RegisterCommandProps(props *CommandProps) {
+	rn.options.register(func(r *runner) {
+		if props == nil {
+			r.registrationErrors = append(r.registrationErrors, errors.New("nil CommandProps is given to RegisterCommandProps"))
+			return
+		}
+		for _, botType := range props.targetBotTypes() {
+			r.commandProps[botType] = append(r.commandProps[botType], props.forBotType(botType))
 		}
-		r.commandProps[props.botType] = append(stashed, props)
 	})
 }
 
 // RegisterScheduledTask registers a given ScheduledTask to Sarah.
 // On Run, a schedule is set for this task.
 func RegisterScheduledTask(botType BotType, task ScheduledTask) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterScheduledTask(botType, task)
+}
+
+// RegisterScheduledTask is the Runner counterpart of the package-level RegisterScheduledTask.
+func (rn *Runner) RegisterScheduledTask(botType BotType, task ScheduledTask) {
+	rn.options.register(func(r *runner) {
+		if task == nil {
+			r.registrationErrors = append(r.registrationErrors, fmt.Errorf("nil ScheduledTask is given to RegisterScheduledTask for %s", botType))
+			return
+		}
 		tasks, ok := r.scheduledTasks[botType]
 		if !ok {
 			tasks = []ScheduledTask{}
@@ -109,7 +280,16 @@ func RegisterScheduledTask(botType BotType, task ScheduledTask) {
 // RegisterScheduledTaskProps registers a given ScheduledTaskProps to build ScheduledTask on Run call.
 // This instance is reused when a configuration file is updated and the corresponding ScheduledTask needs to be rebuilt.
 func RegisterScheduledTaskProps(props *ScheduledTaskProps) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterScheduledTaskProps(props)
+}
+
+// RegisterScheduledTaskProps is the Runner counterpart of the package-level RegisterScheduledTaskProps.
+func (rn *Runner) RegisterScheduledTaskProps(props *ScheduledTaskProps) {
+	rn.options.register(func(r *runner) {
+		if props == nil {
+			r.registrationErrors = append(r.registrationErrors, errors.New("nil ScheduledTaskProps is given to RegisterScheduledTaskProps"))
+			return
+		}
 		stashed, ok := r.scheduledTaskProps[props.botType]
 		if !ok {
 			stashed = []*ScheduledTaskProps{}
@@ -123,19 +303,58 @@ func RegisterScheduledTaskProps(props *ScheduledTaskProps) {
 // When a configuration is updated, ConfigWatcher reads the new configuration setting and reflects to the corresponding configuration instance
 // so Sarah can rebuild the corresponding Command or ScheduledTask with the new setting.
 func RegisterConfigWatcher(watcher ConfigWatcher) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterConfigWatcher(watcher)
+}
+
+// RegisterConfigWatcher is the Runner counterpart of the package-level RegisterConfigWatcher.
+func (rn *Runner) RegisterConfigWatcher(watcher ConfigWatcher) {
+	rn.options.register(func(r *runner) {
 		r.configWatcher = watcher
 	})
 }
 
 // RegisterWorker registers a given worker.Worker implementation to Sarah.
 // When one is not registered, a worker instance with default setting is used.
+// Wrap the worker.Worker with NewOrderedWorker to handle the Inputs from the same sender in the order of reception.
 func RegisterWorker(worker worker.Worker) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterWorker(worker)
+}
+
+// RegisterWorker is the Runner counterpart of the package-level RegisterWorker.
+func (rn *Runner) RegisterWorker(worker worker.Worker) {
+	rn.options.register(func(r *runner) {
 		r.worker = worker
 	})
 }
 
+// RegisterJobPanicReporter registers a given function that is called when a job executed by the worker panics.
+// Such panic is recovered so the worker goroutine keeps running, and the recovered value is passed to the function along with the stack trace.
+// The number of recovered panics is available via CurrentStatus regardless of the registration.
+func RegisterJobPanicReporter(fnc func(*JobPanic)) {
+	defaultRunner().RegisterJobPanicReporter(fnc)
+}
+
+// RegisterJobPanicReporter is the Runner counterpart of the package-level RegisterJobPanicReporter.
+func (rn *Runner) RegisterJobPanicReporter(fnc func(*JobPanic)) {
+	rn.options.register(func(r *runner) {
+		r.jobPanicReporter = fnc
+	})
+}
+
+// RegisterKVStore registers a given KVStore implementation to Sarah.
+// When one is registered, each Command can retrieve a KVBucket bound to its BotType and identifier via KVBucketFromContext.
+// Use NewInMemoryKVStore for development or an implementation backed by persistent storage for production.
+func RegisterKVStore(store KVStore) {
+	defaultRunner().RegisterKVStore(store)
+}
+
+// RegisterKVStore is the Runner counterpart of the package-level RegisterKVStore.
+func (rn *Runner) RegisterKVStore(store KVStore) {
+	rn.options.register(func(r *runner) {
+		r.kvStore = store
+	})
+}
+
 // RegisterBotErrorSupervisor registers a given supervising function that is called when a Bot escalates an error.
 // This function judges if the given error is worth being notified to administrators and if the Bot should stop.
 // When an action is required, the function may return non-nil *SupervisionDirective to pass the order;
@@ -154,10 +373,20 @@ func RegisterWorker(worker worker.Worker) {
 //	2. The supervising function counts the error and ignores the first two occurrence
 // 	3. When the third error comes within ten seconds from the initial error escalation, return *SupervisionDirective with StopBot value of true
 //
+// NewThresholdSupervisor provides such a supervising function, and EscalateAfter helps to declare a customized one. Return *SupervisionDirective with RestartBot value of true instead to run the bot again after a cooldown.
+//
+// When the cause is known, Bot and Adapter should wrap the escalating error with TransientError, AuthError, or RateLimitError
+// so the supervising function can classify the error with IsTransientError, IsAuthError, or RetryAfter instead of matching the error message.
+//
 // Similarly, if there should be a rate limiter to limit the calls to Alerters, the supervising function should take care of this instead of the failing Bot.
 // Each Bot or Adapter's implementation can be kept simple in this way; Sarah should always supervise and control its belonging Bots.
 func RegisterBotErrorSupervisor(fnc func(BotType, error) *SupervisionDirective) {
-	options.register(func(r *runner) {
+	defaultRunner().RegisterBotErrorSupervisor(fnc)
+}
+
+// RegisterBotErrorSupervisor is the Runner counterpart of the package-level RegisterBotErrorSupervisor.
+func (rn *Runner) RegisterBotErrorSupervisor(fnc func(BotType, error) *SupervisionDirective) {
+	rn.options.register(func(r *runner) {
 		r.superviseError = fnc
 	})
 }
@@ -165,6 +394,11 @@ func RegisterBotErrorSupervisor(fnc func(BotType, error) *SupervisionDirective)
 // Run sets up all required resources and initiates Sarah.
 // Workers, schedulers, and other required resources for a bot interaction start running on this function call.
 // This returns an error when bot interaction cannot start; No error is returned when the process starts successfully.
+// When multiple registrations or Config values are invalid, the errors are joined with errors.Join and returned at once
+// so all of them can be fixed in one iteration.
+//
+// Registration functions such as RegisterCommand must be called before Run; a later call panics with ErrRegistrationAfterRun unless SetStrictRegistration(false) is set.
+// Use AddCommand and its family to add a Command or a ScheduledTask to the running process.
 //
 // Call ctx.Done or CurrentStatus to reference current running status.
 //
@@ -173,24 +407,52 @@ func RegisterBotErrorSupervisor(fnc func(BotType, error) *SupervisionDirective)
 // the critical state is notified to administrators via registered Alerter.
 // Registering multiple Alerter implementations to ensure successful notification is recommended.
 func Run(ctx context.Context, config *Config) error {
-	err := runnerStatus.start()
+	return defaultRunner().Run(ctx, config)
+}
+
+// Run is the Runner counterpart of the package-level Run.
+// This initiates the Bots and the components that are registered to this Runner.
+func (rn *Runner) Run(ctx context.Context, config *Config) error {
+	err := rn.status.start()
 	if err != nil {
 		return fmt.Errorf("failed to start bot process: %w", err)
 	}
 
-	runner, err := newRunner(ctx, config)
+	// Derive a context so Restart can stop this runner while ctx is still active.
+	runnerCtx, cancel := context.WithCancelCause(ctx)
+	runnerCtx = withRegistries(runnerCtx, rn.registries)
+	runner, err := newRunner(runnerCtx, config, rn.options)
 	if err != nil {
+		cancel(err)
 		return fmt.Errorf("failed to start bot process: %w", err)
 	}
-	go runner.run(ctx)
+	if runner.clock != nil {
+		runnerCtx = withRunnerClock(runnerCtx, runner.clock)
+	}
+	runner.cancel = cancel
+	runner.stopped = make(chan struct{})
+	runner.status = rn.status
+	if tracked, ok := runner.worker.(*trackedWorker); ok {
+		rn.status.setWorkerStats(tracked.stats)
+	}
+	rn.status.setSchedulerStats(runner.scheduler.stats)
+	rn.status.setCircuitStats(runner.circuits.stats)
+	rn.selfTester.set(runner.worker, runner.configWatcher, runner.bots)
+	rn.running.set(runner)
+	rn.options.seal()
+	go runner.run(runnerCtx)
 
 	return nil
 }
 
-func newRunner(ctx context.Context, config *Config) (*runner, error) {
+// prepareRunner builds a runner with the given Config and the stashed options, and then validates them.
+// All errors are collected instead of returning on the first one, so all misconfigurations can be fixed at once.
+// This does not affect anything outside the returned runner, so Restart can validate the new registrations before stopping the current runner.
+func prepareRunner(config *Config, opts *optionHolder) (*runner, []error) {
+	var errs []error
 	loc, err := time.LoadLocation(config.TimeZone)
 	if err != nil {
-		return nil, fmt.Errorf(`given timezone "%s" cannot be converted to time.Location: %w`, config.TimeZone, err)
+		errs = append(errs, fmt.Errorf(`given timezone "%s" cannot be converted to time.Location: %w`, config.TimeZone, err))
 	}
 
 	r := &runner{
@@ -202,13 +464,73 @@ func newRunner(ctx context.Context, config *Config) (*runner, error) {
 		commandProps:       make(map[BotType][]*CommandProps),
 		scheduledTasks:     make(map[BotType][]ScheduledTask),
 		scheduledTaskProps: make(map[BotType][]*ScheduledTaskProps),
+		jobs:               newJobRegistry(),
 		alerters:           &alerters{},
-		scheduler:          runScheduler(ctx, loc),
+		scheduler:          nil,
 		superviseError:     nil,
+		kvStore:            nil,
+		location:           loc,
+		clock:              nil,
+		status:             &status{},
+	}
+
+	opts.apply(r)
+	r.shareCommandProps()
+	errs = append(errs, r.registrationErrors...)
+	errs = append(errs, validateBots(r.bots)...)
+	errs = append(errs, r.resolveCommandConflicts()...)
+	errs = append(errs, validateConfig(config)...)
+	return r, errs
+}
+
+func newRunner(ctx context.Context, config *Config, opts *optionHolder) (*runner, error) {
+	r, errs := prepareRunner(config, opts)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	for _, alerter := range *r.alerters {
+		if bound, ok := alerter.(runnerBoundAlerter); ok {
+			bound.bind(r)
+		}
+	}
+
+	if r.clock != nil {
+		// Only the default Runner's registration reaches here, so another Runner never replaces the process-wide Clock.
+		// The previous Clock is restored when the runner stops so the Clock does not leak into the later Runners.
+		r.restoreClock = setClock(r.clock)
+	}
+	r.logValidationWarnings()
+
+	if config.ConfigHistory > 0 {
+		// Keep the snapshots of the configurations regardless of the registered ConfigWatcher so RollbackConfig can re-apply a prior one.
+		r.configHistory = newConfigHistory(r.configWatcher, config.ConfigHistory)
+		r.configWatcher = r.configHistory
 	}
 
-	options.apply(r)
+	r.scheduler = runScheduler(ctx, r.location)
+	r.scheduler.setVerbose(config.SchedulerVerbose)
 
+	if r.superviseError == nil {
+		// No supervising function is registered, so use the built-in one that can be configured via Config.Supervisor.
+		r.configuredSupervisor = true
+		if config.Supervisor != nil {
+			r.superviseError = config.Supervisor.supervisor()
+		}
+	}
+
+	if config.LogLevel != "" && !logLevelOverridden() {
+		// The level is already validated.
+		_ = SetLogLevel(config.LogLevel)
+	}
+
+	if config.LogFilter != nil {
+		// The filters are already validated.
+		filters, _ := config.LogFilter.filters()
+		logger.SetLogger(FilterLogger(logger.GetLogger(), filters...))
+	}
+
+	var tracked *trackedWorker
 	if r.worker == nil {
 		// When the jobs are CPU-intensive, the number of workers can be equal to the number of CPUs.
 		// However, in general, bot interaction involves more IO-intensive jobs such as calling external Weather APIs
@@ -222,8 +544,16 @@ func newRunner(ctx context.Context, config *Config) (*runner, error) {
 		workerConfig := worker.NewConfig()
 		workerConfig.WorkerNum = 100
 		workerConfig.QueueSize = 10
-		r.worker = worker.Run(ctx, worker.NewConfig())
+		// The worker outlives ctx until every Bot stops so the Bots can drain their in-flight Inputs as declared by Config.Drain.
+		var workerCtx context.Context
+		workerCtx, r.stopWorker = context.WithCancel(context.WithoutCancel(ctx))
+		tracked = newTrackedWorker(worker.Run(workerCtx, workerConfig), int(workerConfig.QueueSize))
+	} else {
+		// The capacity of a registered worker is unknown.
+		tracked = newTrackedWorker(r.worker, 0)
 	}
+	tracked.reportPanic = r.jobPanicReporter
+	r.worker = tracked
 
 	return r, nil
 }
@@ -240,6 +570,53 @@ type runner struct {
 	alerters           *alerters
 	scheduler          scheduler
 	superviseError     func(BotType, error) *SupervisionDirective
+	kvStore            KVStore
+	lifecycleHooks     []LifecycleHook
+	jobPanicReporter   func(*JobPanic)
+	leaderElectors     map[BotType]LeaderElector
+	inputSinks         []InputSink
+	executionObservers []CommandExecutionObserver
+	circuits           circuitBreakers
+	transcriptStore    TranscriptStore
+	preferences        Preferences
+	location           *time.Location
+	clock              Clock
+	panicFormatter     PanicFormatter
+	status             *status
+	configHistory      *configHistory
+	jobs               *jobRegistry
+
+	// cancel cancels the context that the runner runs with. Restart calls this with ErrRunnerRestarting.
+	cancel context.CancelCauseFunc
+
+	// stopped is closed when every Bot stops and the runner finishes its operation.
+	stopped chan struct{}
+
+	// stopWorker stops the worker that Sarah created by default. This is called after every Bot stops so the worker can handle the Inputs during the drain.
+	stopWorker context.CancelFunc
+
+	// restoreClock restores the Clock that was effective before the runner started. This is nil when no Clock is registered.
+	restoreClock func()
+
+	// commandErrorResponders holds CommandErrorResponder for each BotType that is registered via RegisterCommandErrorResponder.
+	commandErrorResponders map[BotType]CommandErrorResponder
+
+	// commandConflictPolicies holds CommandConflictPolicy for each BotType that is registered via RegisterCommandConflictPolicy.
+	commandConflictPolicies map[BotType]CommandConflictPolicy
+
+	// configuredSupervisor tells if superviseError is built from Config.Supervisor and hence is rebuilt on reload.
+	// This is false when a supervising function is registered via RegisterBotErrorSupervisor.
+	configuredSupervisor bool
+
+	// registrationErrors holds the errors that the registration functions such as RegisterBot found on Run.
+	registrationErrors []error
+
+	// runningBots holds the currently running Bots with their contexts so AddCommand and its family can apply the components immediately.
+	runningBots map[BotType]*runningBot
+
+	// mutex guards config and superviseError that may be replaced on runtime configuration reload,
+	// and the stashed Commands and ScheduledTasks that may be added at runtime.
+	mutex sync.RWMutex
 }
 
 // SupervisionDirective tells Sarah how to react to Bot's escalating error.
@@ -258,9 +635,67 @@ type SupervisionDirective struct {
 	// AlertingErr is sent registered alerters and administrators will be notified.
 	// Set nil when such alert notification is not required.
 	AlertingErr error
+
+	// RestartBot tells if Sarah needs to stop the failing bot and run it again after RestartCooldown.
+	// Commands and scheduled tasks are registered again, so the Bot and its Adapter implementation must be able to run multiple times.
+	// This is ignored when StopBot is true.
+	RestartBot bool
+
+	// RestartCooldown declares how long to wait before running the bot again when RestartBot is true.
+	RestartCooldown time.Duration
+}
+
+// botRestart is set as the cause of the bot context's cancellation when a SupervisionDirective requires a restart.
+type botRestart struct {
+	cooldown time.Duration
+}
+
+func (r *botRestart) Error() string {
+	return fmt.Sprintf("restart bot in %s", r.cooldown)
+}
+
+// formatPanic formats the given PanicStack with the registered PanicFormatter or with Config.PanicStackDepth.
+func (r *runner) formatPanic(stack *PanicStack) string {
+	r.mutex.RLock()
+	formatter := r.panicFormatter
+	depth := 0
+	if r.config != nil {
+		depth = r.config.PanicStackDepth
+	}
+	r.mutex.RUnlock()
+
+	if formatter == nil {
+		formatter = FormatPanicStack(depth)
+	}
+	return formatter(stack)
+}
+
+// inputFilter returns the InputFilterConfig of the given BotType from the current Config.
+func (r *runner) inputFilter(botType BotType) *InputFilterConfig {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.config == nil {
+		return nil
+	}
+	return r.config.InputFilter[botType]
+}
+
+// normalization returns the NormalizationConfig of the given BotType from the current Config.
+func (r *runner) normalization(botType BotType) *NormalizationConfig {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.config == nil {
+		return nil
+	}
+	return r.config.Normalization[botType]
 }
 
 func (r *runner) botCommands(botType BotType) []Command {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	if commands, ok := r.commands[botType]; ok {
 		return commands
 	}
@@ -268,13 +703,36 @@ func (r *runner) botCommands(botType BotType) []Command {
 }
 
 func (r *runner) botCommandProps(botType BotType) []*CommandProps {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	if props, ok := r.commandProps[botType]; ok {
 		return props
 	}
 	return []*CommandProps{}
 }
 
+// shareCommandProps distributes the CommandProps registered for AnyBotType to all registered Bots.
+// This must be called after all registrations are applied so the Bots registered later also receive them.
+func (r *runner) shareCommandProps() {
+	shared, ok := r.commandProps[AnyBotType]
+	if !ok {
+		return
+	}
+	delete(r.commandProps, AnyBotType)
+
+	for _, bot := range r.bots {
+		botType := bot.BotType()
+		for _, props := range shared {
+			r.commandProps[botType] = append(r.commandProps[botType], props.forBotType(botType))
+		}
+	}
+}
+
 func (r *runner) botScheduledTaskProps(botType BotType) []*ScheduledTaskProps {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	if props, ok := r.scheduledTaskProps[botType]; ok {
 		return props
 	}
@@ -282,6 +740,9 @@ func (r *runner) botScheduledTaskProps(botType BotType) []*ScheduledTaskProps {
 }
 
 func (r *runner) botScheduledTasks(botType BotType) []ScheduledTask {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	if tasks, ok := r.scheduledTasks[botType]; ok {
 		return tasks
 	}
@@ -289,22 +750,91 @@ func (r *runner) botScheduledTasks(botType BotType) []ScheduledTask {
 }
 
 func (r *runner) run(ctx context.Context) {
+	r.watchRunnerConfig(ctx)
+
 	var wg sync.WaitGroup
 	for _, bot := range r.bots {
 		wg.Add(1)
 
 		go func(b Bot) {
 			defer func() {
+				r.status.stopBot(b)
 				wg.Done()
-				runnerStatus.stopBot(b)
 			}()
 
-			runnerStatus.addBot(b)
-			r.runBot(ctx, b)
+			r.status.addBot(b)
+			elector, elected := r.leaderElectors[b.BotType()]
+			var lost <-chan struct{}
+			for {
+				botCtx := ctx
+				release := func() {}
+				if elected {
+					if lost == nil || isClosed(lost) {
+						var ok bool
+						lost, ok = r.elect(ctx, b, elector)
+						if !ok {
+							return
+						}
+					}
+					botCtx, release = withLeadership(ctx, lost)
+				}
+
+				cause := r.runBot(botCtx, b)
+				release()
+				r.notifyLifecycleEvent(BotStopped, b.BotType(), cause)
+
+				if errors.Is(cause, ErrLeadershipLost) {
+					// Stay on hot standby and try to take over the leadership again.
+					logger.Warnf("Lost the leadership of %s", b.BotType())
+					continue
+				}
+
+				var restart *botRestart
+				if !errors.As(cause, &restart) {
+					if elected {
+						// Let another replica take over.
+						resign(elector, b.BotType())
+					}
+					return
+				}
+
+				r.status.countBotRestart(b.BotType())
+				logger.Infof("Restarting %s in %s", b.BotType(), restart.cooldown)
+				timer := currentClock().NewTimer(restart.cooldown)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+
+				case <-timer.C():
+					// Run the bot again.
+
+				}
+			}
 		}(bot)
 
 	}
 	wg.Wait()
+
+	if r.stopWorker != nil {
+		r.stopWorker()
+	}
+	if r.restoreClock != nil {
+		r.restoreClock()
+	}
+	if r.configWatcher != nil && r.config != nil {
+		// Let the next runner subscribe again on Restart.
+		unsubscribeConfigWatcher(r.configWatcher, RunnerConfigNamespace)
+	}
+
+	var reason error
+	if errors.Is(context.Cause(ctx), ErrRunnerRestarting) {
+		reason = ErrRunnerRestarting
+	}
+	r.notifyLifecycleEvent(RunnerStopped, "", reason)
+	if r.stopped != nil {
+		close(r.stopped)
+	}
 }
 
 func unsubscribeConfigWatcher(watcher ConfigWatcher, botType BotType) {
@@ -320,9 +850,39 @@ func unsubscribeConfigWatcher(watcher ConfigWatcher, botType BotType) {
 }
 
 // runBot initiates the given Bot implementation and blocks until the bot stops.
-func (r *runner) runBot(runnerCtx context.Context, bot Bot) {
+// This returns the cause of the bot's stop, which is *botRestart when a SupervisionDirective requires a restart.
+func (r *runner) runBot(runnerCtx context.Context, bot Bot) error {
 	logger.Infof("Starting %s", bot.BotType())
-	botCtx, errNotifier := r.superviseBot(runnerCtx, bot.BotType())
+	r.notifyLifecycleEvent(BotStarting, bot.BotType(), nil)
+	// The Bot's context outlives runnerCtx while the Bot drains its in-flight Inputs.
+	drainer := &inputDrainer{botType: bot.BotType(), queue: registriesFromContext(runnerCtx).inputQueue}
+	r.setBotDrainer(drainer)
+	drainCtx, stopDrain := r.drainContext(runnerCtx, drainer, r.drain(bot.BotType()))
+	defer stopDrain()
+	botCtx, errNotifier := r.superviseBot(drainCtx, bot.BotType())
+	contextRecord := r.startBotContext(bot.BotType())
+	botCtx = withLogField(botCtx, "BotType", bot.BotType().String())
+	if r.preferences != nil {
+		// Let Commands and ScheduledTasks consult the users' preferences via PreferencesFromContext.
+		botCtx = withPreferences(botCtx, r.preferences)
+	}
+	// Let Commands and ScheduledTasks schedule reminders via RemindAt.
+	botCtx = withReminders(botCtx, &reminders{
+		ctx:       botCtx,
+		bot:       bot,
+		scheduler: r.scheduler,
+		location:  r.location,
+	})
+	// Let Commands submit long-running jobs via SubmitJob.
+	botCtx = withJobs(botCtx, &jobs{
+		ctx:      botCtx,
+		bot:      bot,
+		registry: r.jobs,
+	})
+
+	// Let AddCommand and its family apply the components to this Bot while it runs.
+	r.setRunningBot(botCtx, bot)
+	defer r.unsetRunningBot(bot.BotType())
 
 	// Build commands with stashed CommandProps.
 	r.registerCommands(botCtx, bot)
@@ -330,26 +890,48 @@ func (r *runner) runBot(runnerCtx context.Context, bot Bot) {
 	// Register scheduled tasks.
 	r.registerScheduledTasks(botCtx, bot)
 
-	inputReceiver := setupInputReceiver(botCtx, bot, r.worker)
+	inputReceiver := setupInputReceiver(botCtx, bot, r.worker, r.commandErrorResponders[bot.BotType()])
+	// The message is normalized right before Command matching so the transcript and the recorded Input keep the original one.
+	inputReceiver = batchAware(func(receive func(Input) error) func(Input) error {
+		return normalizingInputReceiver(func() *NormalizationConfig {
+			return r.normalization(bot.BotType())
+		}, receive)
+	}, inputReceiver)
+	// Each Input in a BatchInput is recorded and filtered individually.
+	if r.transcriptStore != nil {
+		inputReceiver = batchAware(func(receive func(Input) error) func(Input) error {
+			return bypassSensitiveInput(bot, transcriptInputReceiver(botCtx, bot.BotType(), r.transcriptStore, receive), receive)
+		}, inputReceiver)
+	}
+	inputReceiver = batchAware(func(receive func(Input) error) func(Input) error {
+		return filteringInputReceiver(botCtx, func() *InputFilterConfig {
+			return r.inputFilter(bot.BotType())
+		}, receive)
+	}, inputReceiver)
+	if len(r.inputSinks) > 0 {
+		inputReceiver = batchAware(func(receive func(Input) error) func(Input) error {
+			return bypassSensitiveInput(bot, recordingInputReceiver(botCtx, bot.BotType(), r.inputSinks, receive), receive)
+		}, inputReceiver)
+	}
+	inputReceiver = drainingInputReceiver(drainer, inputReceiver)
 
 	// Run the bot in a panic-proof manner.
+	var reason CancellationReason
 	func() {
 		defer func() {
+			// Judge the reason here when the bot stops by itself because the cancellation below hides it.
+			active := botCtx.Err() == nil
+
 			// When the bot panics, recover and tell as much detailed information as possible via the error notification channel.
 			// The channel receiver sends an alert to the administrator.
-			if r := recover(); r != nil {
-				stack := []string{fmt.Sprintf("panic in bot: %s. %#v.", bot.BotType(), r)}
-
-				// Inform stack trace
-				for depth := 0; ; depth++ {
-					_, src, line, ok := runtime.Caller(depth)
-					if !ok {
-						break
-					}
-					stack = append(stack, fmt.Sprintf(" -> depth:%d. file:%s. line:%d.", depth, src, line))
+			if recovered := recover(); recovered != nil {
+				if active {
+					reason = CancellationPanic
 				}
-
-				errNotifier(NewBotNonContinuableError(strings.Join(stack, "\n")))
+				stack := newPanicStack(bot.BotType(), recovered, debug.Stack())
+				errNotifier(NewBotNonContinuableError(r.formatPanic(stack)))
+			} else if active {
+				reason = CancellationBotReturned
 			}
 
 			// Bot.Run may return without internally sending an error to errNotifier.
@@ -358,23 +940,35 @@ func (r *runner) runBot(runnerCtx context.Context, bot Bot) {
 			errNotifier(NewBotNonContinuableError(fmt.Sprintf("shutdown bot: %s", bot.BotType())))
 		}()
 
+		r.notifyLifecycleEvent(BotStarted, bot.BotType(), nil)
 		bot.Run(botCtx, inputReceiver, errNotifier) // Blocks til interaction ends
 		unsubscribeConfigWatcher(r.configWatcher, bot.BotType())
 	}()
+
+	cause := context.Cause(botCtx)
+	r.cancelBotContext(contextRecord, cancellationReason(runnerCtx, cause, reason), cause)
+	return cause
 }
 
 func (r *runner) superviseBot(runnerCtx context.Context, botType BotType) (context.Context, func(error)) {
-	botCtx, cancel := context.WithCancel(runnerCtx)
+	botCtx, cancel := context.WithCancelCause(runnerCtx)
 
 	sendAlert := func(err error) {
-		e := r.alerters.alertAll(runnerCtx, botType, err)
+		ctx := runnerCtx
+		if timeout := r.alertTimeout(); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(runnerCtx, timeout)
+			defer cancel()
+		}
+
+		e := r.alerters.alertAll(ctx, botType, err)
 		if e != nil {
 			logger.Errorf("Failed to send alert for %s: %+v", botType, e)
 		}
 	}
 
-	stopBot := func() {
-		cancel()
+	stopBot := func(cause error) {
+		cancel(cause)
 		logger.Infof("Stop supervising bot's critical error due to its context cancellation: %s.", botType)
 	}
 
@@ -382,24 +976,29 @@ func (r *runner) superviseBot(runnerCtx context.Context, botType BotType) (conte
 	// If a critical error is sent, this cancels the bot's context to finish its lifecycle.
 	// The bot MUST NOT kill itself, but Sarah does. Beware that Sarah takes care of all related components' lifecycle.
 	handleError := func(err error) {
+		r.setBotError(botType, err)
+
 		switch err.(type) {
 		case *BotNonContinuableError:
 			logger.Errorf("Stop unrecoverable bot. BotType: %s. Error: %+v", botType, err)
 
-			stopBot()
+			stopBot(err)
 
 			go sendAlert(err)
 
 		default:
-			if r.superviseError != nil {
-				directive := r.superviseError(botType, err)
+			if supervise := r.supervisor(); supervise != nil {
+				directive := supervise(botType, err)
 				if directive == nil {
 					return
 				}
 
 				if directive.StopBot {
 					logger.Errorf("Stop bot due to given directive. BotType: %s. Reason: %+v", botType, err)
-					stopBot()
+					stopBot(err)
+				} else if directive.RestartBot {
+					logger.Errorf("Restart bot due to given directive. BotType: %s. Reason: %+v", botType, err)
+					stopBot(&botRestart{cooldown: directive.RestartCooldown})
 				}
 
 				if directive.AlertingErr != nil {
@@ -427,96 +1026,175 @@ func (r *runner) superviseBot(runnerCtx context.Context, botType BotType) (conte
 }
 
 func (r *runner) registerCommands(botCtx context.Context, bot Bot) {
-	props := r.botCommandProps(bot.BotType())
+	for _, p := range r.botCommandProps(bot.BotType()) {
+		r.registerCommandProps(botCtx, bot, p)
+	}
 
-	reg := func(p *CommandProps) {
+	for _, command := range r.botCommands(bot.BotType()) {
+		bot.AppendCommand(r.wrapCommand(bot.BotType(), command))
+	}
+}
+
+// registerCommandProps builds a Command with the given CommandProps and appends it to the Bot.
+// The Command is rebuilt when its configuration is updated.
+func (r *runner) registerCommandProps(botCtx context.Context, bot Bot, p *CommandProps) {
+	reg := func(reload bool) {
 		command, err := buildCommand(botCtx, p, r.configWatcher)
+		r.setPluginState(bot.BotType(), PluginCommand, p.identifier, reload, err)
 		if err != nil {
 			logger.Errorf("Failed to build command %#v: %+v", p, err)
 			return
 		}
-		bot.AppendCommand(command)
+		bot.AppendCommand(r.wrapCommand(bot.BotType(), command))
+	}
+
+	reg(false)
+	err := r.configWatcher.Watch(botCtx, bot.BotType(), p.identifier, func() {
+		logger.Infof("Updating command: %s", p.identifier)
+		reg(true)
+	})
+	if err != nil {
+		logger.Errorf("Failed to subscribe configuration for command %s: %+v", p.identifier, err)
 	}
+}
 
-	callback := func(p *CommandProps) func() {
-		return func() {
-			logger.Infof("Updating command: %s", p.identifier)
-			reg(p)
+// wrapCommand wraps the given Command so its execution can retrieve a KVBucket via KVBucketFromContext,
+// is notified to the registered CommandExecutionObserver values, and is protected by its CircuitBreaker.
+// The given Command is returned as-is when none of them is required.
+func (r *runner) wrapCommand(botType BotType, command Command) Command {
+	unwrapped := command
+	if r.kvStore != nil {
+		command = &kvCommand{
+			Command: command,
+			bucket:  NewKVBucket(r.kvStore, botType, command.Identifier()),
 		}
 	}
 
-	for _, p := range props {
-		reg(p)
-		err := r.configWatcher.Watch(botCtx, bot.BotType(), p.identifier, callback(p))
-		if err != nil {
-			logger.Errorf("Failed to subscribe configuration for command %s: %+v", p.identifier, err)
-			continue
+	if len(r.executionObservers) > 0 {
+		command = &observedCommand{
+			Command:   command,
+			botType:   botType,
+			observers: r.executionObservers,
 		}
 	}
 
-	for _, command := range r.botCommands(bot.BotType()) {
-		bot.AppendCommand(command)
+	if breaking, ok := unwrapped.(circuitBreakingCommand); ok && breaking.circuitBreakerConfig() != nil {
+		// Wrap outermost so a rejected execution is not observed.
+		command = &circuitBreakerCommand{
+			Command: command,
+			breaker: r.circuits.get(botType, command.Identifier(), breaking.circuitBreakerConfig()),
+		}
 	}
+
+	return command
 }
 
 func (r *runner) registerScheduledTasks(botCtx context.Context, bot Bot) {
-	reg := func(p *ScheduledTaskProps) {
+	for _, p := range r.botScheduledTaskProps(bot.BotType()) {
+		r.registerScheduledTaskProps(botCtx, bot, p)
+	}
+
+	for _, task := range r.botScheduledTasks(bot.BotType()) {
+		r.registerScheduledTask(botCtx, bot, task)
+	}
+}
+
+// registerScheduledTaskProps builds a ScheduledTask with the given ScheduledTaskProps and schedules it.
+// The task is rebuilt and rescheduled when its configuration is updated.
+func (r *runner) registerScheduledTaskProps(botCtx context.Context, bot Bot, p *ScheduledTaskProps) {
+	reg := func(reload bool) {
 		r.scheduler.remove(bot.BotType(), p.identifier)
+		registriesFromContext(botCtx).taskTriggers.remove(bot.BotType(), p.identifier)
 
 		task, err := buildScheduledTask(botCtx, p, r.configWatcher)
 		if err != nil {
+			r.setPluginState(bot.BotType(), PluginScheduledTask, p.identifier, reload, err)
 			logger.Errorf("Failed to build scheduled task %s: %+v", p.identifier, err)
 			return
 		}
 
-		err = r.scheduler.update(bot.BotType(), task, func() {
-			executeScheduledTask(botCtx, bot, task)
+		err = r.scheduler.update(bot.BotType(), task, func() error {
+			return executeScheduledTask(botCtx, bot, task)
 		})
+		r.setPluginState(bot.BotType(), PluginScheduledTask, p.identifier, reload, err)
 		if err != nil {
 			logger.Errorf("Failed to schedule a task. ID: %s: %+v", task.Identifier(), err)
+			return
 		}
+		registriesFromContext(botCtx).taskTriggers.set(botCtx, bot, task)
 	}
 
-	callback := func(p *ScheduledTaskProps) func() {
-		return func() {
-			logger.Infof("Updating scheduled task: %s", p.identifier)
-			reg(p)
-		}
+	reg(false)
+	err := r.configWatcher.Watch(botCtx, bot.BotType(), p.identifier, func() {
+		logger.Infof("Updating scheduled task: %s", p.identifier)
+		reg(true)
+	})
+	if err != nil {
+		logger.Errorf("Failed to subscribe configuration for scheduled task %s: %+v", p.identifier, err)
 	}
+}
 
-	for _, p := range r.botScheduledTaskProps(bot.BotType()) {
-		reg(p)
-		err := r.configWatcher.Watch(botCtx, bot.BotType(), p.identifier, callback(p))
-		if err != nil {
-			logger.Errorf("Failed to subscribe configuration for scheduled task %s: %+v", p.identifier, err)
-			continue
-		}
+// setPluginState records the result of a build of the Command or ScheduledTask so operators can see it via CurrentStatus.
+func (r *runner) setPluginState(botType BotType, kind PluginKind, id string, reload bool, err error) {
+	if r.status == nil {
+		return
 	}
+	r.status.setPluginState(botType, kind, id, reload, err)
+}
 
-	for _, task := range r.botScheduledTasks(bot.BotType()) {
-		if task.Schedule() == "" {
-			logger.Errorf("Failed to schedule a task. ID: %s. Reason: %s.", task.Identifier(), "No schedule given.")
-			continue
-		}
+// setBotError records the error that the Bot escalated so operators can see the failure history via CurrentStatus.
+func (r *runner) setBotError(botType BotType, err error) {
+	if r.status == nil {
+		return
+	}
+	r.status.setBotError(botType, err)
+}
 
-		err := r.scheduler.update(bot.BotType(), task, func() {
-			executeScheduledTask(botCtx, bot, task)
-		})
-		if err != nil {
-			logger.Errorf("Failed to schedule a task. id: %s: %+v", task.Identifier(), err)
-		}
+func (r *runner) registerScheduledTask(botCtx context.Context, bot Bot, task ScheduledTask) {
+	if task.Schedule() == "" && upstreamTaskID(task) == "" {
+		logger.Errorf("Failed to schedule a task. ID: %s. Reason: %s.", task.Identifier(), "No schedule given.")
+		return
+	}
+
+	err := r.scheduler.update(bot.BotType(), task, func() error {
+		return executeScheduledTask(botCtx, bot, task)
+	})
+	if err != nil {
+		logger.Errorf("Failed to schedule a task. id: %s: %+v", task.Identifier(), err)
+		return
 	}
+	registriesFromContext(botCtx).taskTriggers.set(botCtx, bot, task)
 }
 
-func executeScheduledTask(ctx context.Context, bot Bot, task ScheduledTask) {
+// executeScheduledTask executes the given task and sends its results.
+// This returns an error only when the task execution fails so the downstream tasks can be skipped.
+func executeScheduledTask(ctx context.Context, bot Bot, task ScheduledTask) error {
+	ctx = withLogField(ctx, "Task", task.Identifier())
+	if _, ok := registriesFromContext(ctx).maintenance.message(bot.BotType()); ok {
+		LoggerFromContext(ctx).Info("Skip executing the scheduled task while in maintenance.")
+		return nil
+	}
+
 	results, err := task.Execute(ctx)
 	if err != nil {
-		logger.Errorf("Error on scheduled task: %s", task.Identifier())
-		return
+		LoggerFromContext(ctx).Errorf("Error on scheduled task: %+v", err)
+		return err
 	} else if results == nil {
-		return
+		return nil
+	}
+
+	return sendScheduledTaskResults(ctx, bot, task, results)
+}
+
+// sendScheduledTaskResults sends the given results of the task in accordance with the task's TaskResultFormatter and TaskBatch settings if any.
+// This returns the context's error when the context is canceled while the results are being sent.
+func sendScheduledTaskResults(ctx context.Context, bot Bot, task ScheduledTask, results []*ScheduledTaskResult) error {
+	var formatter TaskResultFormatter
+	if formatted, ok := task.(FormattedScheduledTask); ok {
+		formatter = formatted.Formatter()
 	}
 
+	var messages []Output
 	for _, res := range results {
 		// The destination returned by task execution has higher priority.
 		// e.g. RSS Reader's task searches for stored feed/destination set, and returns which destination to send.
@@ -527,25 +1205,112 @@ func executeScheduledTask(ctx context.Context, bot Bot, task ScheduledTask) {
 			// e.g. Weather forecast task always sends weather information to #goodmorning room.
 			presetDest := task.DefaultDestination()
 			if presetDest == nil {
-				logger.Errorf("Task was completed, but destination was not set: %s.", task.Identifier())
+				LoggerFromContext(ctx).Errorf("Task was completed, but destination was not set.")
 				continue
 			}
 			dest = presetDest
 		}
 
-		message := NewOutputMessage(dest, res.Content)
+		content := res.Content
+		if formatter != nil {
+			formatted, err := formatter(ctx, bot.BotType(), &ScheduledTaskResult{Content: res.Content, Destination: dest})
+			if err != nil {
+				logger.Errorf("Failed to format the result of scheduled task %s: %+v", task.Identifier(), err)
+				continue
+			}
+			content = formatted
+		}
+
+		messages = append(messages, NewOutputMessage(dest, content))
+	}
+
+	var batch *TaskBatch
+	if batched, ok := task.(BatchedScheduledTask); ok {
+		batch = batched.Batch()
+	}
+	if batch == nil {
+		for _, message := range messages {
+			bot.SendMessage(ctx, message)
+		}
+		return nil
+	}
+
+	if batch.MaxMessages > 0 && len(messages) > batch.MaxMessages {
+		truncated := messages[batch.MaxMessages:]
+		messages = messages[:batch.MaxMessages]
+		logger.Warnf("Scheduled task %s returned %d results; %d of them are truncated.", task.Identifier(), len(results), len(truncated))
+
+		if batch.TruncationNotice != nil {
+			messages = append(messages, truncationNotices(truncated, batch.TruncationNotice)...)
+		}
+	}
+
+	for i, message := range messages {
+		if i > 0 && batch.Interval > 0 {
+			timer := currentClock().NewTimer(batch.Interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+
+			case <-timer.C():
+				// Send the next one.
+
+			}
+		}
 		bot.SendMessage(ctx, message)
 	}
+
+	return nil
+}
+
+// truncationNotices builds messages that notify the number of truncated results for each destination.
+func truncationNotices(truncated []Output, notice func(int) interface{}) []Output {
+	var destinations []OutputDestination
+	var counts []int
+	for _, output := range truncated {
+		found := false
+		for i, dest := range destinations {
+			if reflect.DeepEqual(dest, output.Destination()) {
+				counts[i]++
+				found = true
+				break
+			}
+		}
+		if !found {
+			destinations = append(destinations, output.Destination())
+			counts = append(counts, 1)
+		}
+	}
+
+	var notices []Output
+	for i, dest := range destinations {
+		notices = append(notices, NewOutputMessage(dest, notice(counts[i])))
+	}
+	return notices
 }
 
-func setupInputReceiver(botCtx context.Context, bot Bot, wkr worker.Worker) func(Input) error {
+func setupInputReceiver(botCtx context.Context, bot Bot, wkr worker.Worker, responder CommandErrorResponder) func(Input) error {
 	continuousEnqueueErrCnt := 0
+	inputQueue := registriesFromContext(botCtx).inputQueue
 	return func(input Input) error {
-		err := wkr.Enqueue(func() {
-			err := bot.Respond(botCtx, input)
-			if err != nil {
-				logger.Errorf("Error on message handling. Input: %#v. Error: %+v", input, err)
+		// Give each Input a unique ID so all log lines for the same user request can be correlated.
+		id := newCorrelationID()
+		ctx := withCorrelationID(botCtx, id)
+		LoggerFromContext(ctx).Debugf("Received input. SenderKey: %s", input.SenderKey())
+
+		// Track the Input until its handling completes so a stuck one can be found via InputQueueSnapshot.
+		inputQueue.add(id, bot.BotType(), loggedInput(bot, input))
+		err := enqueueInputJob(wkr, bot.BotType(), input, func() {
+			inputQueue.start(id)
+			defer inputQueue.remove(id)
+
+			// The Inputs in a batch are handled sequentially in the same job to keep their order.
+			if batch, ok := input.(*BatchInput); ok {
+				respondBatch(ctx, bot, responder, batch)
+				return
 			}
+			respondInput(ctx, bot, responder, input)
 		})
 
 		if err == nil {
@@ -554,8 +1319,42 @@ func setupInputReceiver(botCtx context.Context, bot Bot, wkr worker.Worker) func
 
 		}
 
+		inputQueue.remove(id)
 		continuousEnqueueErrCnt++
 		// Could not send because probably the workers are too busy or the runner context is already canceled.
-		return NewBlockedInputError(continuousEnqueueErrCnt)
+		blockedErr := &BlockedInputError{ContinuationCount: continuousEnqueueErrCnt}
+		if tracked, ok := wkr.(*trackedWorker); ok {
+			blockedErr.QueueCapacity = tracked.capacity
+			blockedErr.QueueDepth = tracked.depth()
+			blockedErr.EnqueueLatency = tracked.latency()
+		}
+		if batch, ok := input.(*BatchInput); ok {
+			for _, item := range batch.Inputs {
+				ackInput(item, blockedErr)
+			}
+		} else {
+			ackInput(input, blockedErr)
+		}
+		return blockedErr
+	}
+}
+
+// respondInput passes the given Input to Bot.Respond, and then handles the result.
+func respondInput(ctx context.Context, bot Bot, responder CommandErrorResponder, input Input) {
+	// Bot.Respond consumes the sensitivity of the Input, so this must be prepared beforehand.
+	logged := loggedInput(bot, input)
+
+	if respondMaintenance(ctx, bot, input) {
+		ackInput(input, nil)
+		return
+	}
+
+	err := bot.Respond(ctx, input)
+	if err != nil {
+		LoggerFromContext(ctx).Errorf("Error on message handling. Input: %#v. Error: %+v", logged, err)
+		if responder != nil {
+			respondCommandError(ctx, bot, responder, input, err)
+		}
 	}
+	ackInput(input, err)
 }
@@ -33,6 +33,7 @@ func SetupAndRun(fnc func()) {
 	// Initialize package variables
 	runnerStatus = &status{}
 	options = &optionHolder{}
+	running = &runningRunner{}
 
 	fnc()
 }
@@ -196,6 +197,41 @@ func TestRegisterCommandProps(t *testing.T) {
 	})
 }
 
+func TestRegisterCommandProps_Shared(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterBot(&DummyBot{BotTypeValue: "slack"})
+		RegisterBot(&DummyBot{BotTypeValue: "gitter"})
+		multiple := &CommandProps{botType: "slack", botTypes: []BotType{"slack", "gitter"}, identifier: "multiple"}
+		RegisterCommandProps(multiple)
+		shared := &CommandProps{botType: AnyBotType, identifier: "shared"}
+		RegisterCommandProps(shared)
+
+		r := &runner{
+			commandProps: map[BotType][]*CommandProps{},
+		}
+		options.apply(r)
+		r.shareCommandProps()
+
+		if _, ok := r.commandProps[AnyBotType]; ok {
+			t.Error("CommandProps for AnyBotType should be distributed.")
+		}
+		for _, botType := range []BotType{"slack", "gitter"} {
+			props := r.commandProps[botType]
+			if len(props) != 2 {
+				t.Fatalf("Unexpected number of CommandProps are registered for %s: %d.", botType, len(props))
+			}
+			if props[0].identifier != "multiple" || props[1].identifier != "shared" {
+				t.Errorf("Unexpected CommandProps are registered for %s: %#v.", botType, props)
+			}
+			for _, p := range props {
+				if p.botType != botType {
+					t.Errorf("CommandProps is not bound to %s: %s.", botType, p.botType)
+				}
+			}
+		}
+	})
+}
+
 func TestRegisterScheduledTask(t *testing.T) {
 	SetupAndRun(func() {
 		var botType BotType = "dummy"
@@ -283,6 +319,45 @@ func TestRegisterWorker(t *testing.T) {
 	})
 }
 
+func TestRegisterKVStore(t *testing.T) {
+	SetupAndRun(func() {
+		store := NewInMemoryKVStore()
+		RegisterKVStore(store)
+		r := &runner{}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		if r.kvStore != store {
+			t.Error("Given KVStore is not set.")
+		}
+	})
+}
+
+func TestRegisterJobPanicReporter(t *testing.T) {
+	SetupAndRun(func() {
+		called := false
+		RegisterJobPanicReporter(func(_ *JobPanic) {
+			called = true
+		})
+		r := &runner{}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		if r.jobPanicReporter == nil {
+			t.Fatal("Given reporter is not set.")
+		}
+
+		r.jobPanicReporter(&JobPanic{})
+		if !called {
+			t.Error("Given reporter is not set.")
+		}
+	})
+}
+
 func TestRegisterBotErrorSupervisor(t *testing.T) {
 	SetupAndRun(func() {
 		supervisor := func(_ BotType, _ error) *SupervisionDirective {
@@ -344,7 +419,7 @@ func Test_newRunner(t *testing.T) {
 			TimeZone: time.UTC.String(),
 		}
 
-		r, e := newRunner(context.Background(), config)
+		r, e := newRunner(context.Background(), config, options)
 		if e != nil {
 			t.Fatalf("Unexpected error is returned: %s.", e.Error())
 		}
@@ -367,19 +442,106 @@ func Test_newRunner(t *testing.T) {
 	})
 }
 
+func Test_newRunner_DefaultWorker(t *testing.T) {
+	SetupAndRun(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r, e := newRunner(ctx, &Config{TimeZone: time.UTC.String()}, options)
+		if e != nil {
+			t.Fatalf("Unexpected error is returned: %s.", e.Error())
+		}
+		defer r.stopWorker()
+
+		// The default worker is configured to run a hundred workers regardless of the number of CPUs.
+		jobs := 50
+		started := make(chan struct{}, jobs)
+		block := make(chan struct{})
+		defer close(block)
+		for i := 0; i < jobs; i++ {
+			err := r.worker.Enqueue(func() {
+				started <- struct{}{}
+				<-block
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error is returned on job %d: %s.", i, err.Error())
+			}
+
+			select {
+			case <-started:
+				// O.K.
+
+			case <-time.NewTimer(1 * time.Second).C:
+				t.Fatalf("Job %d is not started concurrently.", i)
+
+			}
+		}
+	})
+}
+
 func Test_newRunner_WithTimeZoneError(t *testing.T) {
 	SetupAndRun(func() {
 		config := &Config{
 			TimeZone: "DUMMY",
 		}
 
-		_, e := newRunner(context.Background(), config)
+		_, e := newRunner(context.Background(), config, options)
 		if e == nil {
 			t.Fatal("Expected error is not returned.")
 		}
 	})
 }
 
+func Test_newRunner_WithMultipleErrors(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterBot(nil)
+		RegisterBot(&DummyBot{BotTypeValue: "dummy"})
+		RegisterBot(&DummyBot{BotTypeValue: "dummy"})
+		RegisterCommand("dummy", nil)
+		RegisterCommandProps(nil)
+		RegisterScheduledTask("dummy", nil)
+		RegisterScheduledTaskProps(nil)
+		RegisterAlerter(nil)
+
+		config := &Config{
+			TimeZone:   "DUMMY",
+			LogLevel:   "verbose",
+			LogFilter:  &LogFilterConfig{RedactPatterns: []string{"("}},
+			Supervisor: &SupervisorConfig{MaxCount: 0},
+		}
+
+		_, err := newRunner(context.Background(), config, options)
+		if err == nil {
+			t.Fatal("Expected error is not returned.")
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatalf("Errors are not joined: %#v.", err)
+		}
+		if len(joined.Unwrap()) != 11 {
+			t.Errorf("Unexpected number of errors are returned: %s.", err.Error())
+		}
+	})
+}
+
+func Test_validateConfig(t *testing.T) {
+	config := NewConfig()
+	if errs := validateConfig(config); len(errs) != 0 {
+		t.Errorf("Default Config should be valid: %#v.", errs)
+	}
+
+	config.Supervisor = &SupervisorConfig{MaxCount: 1}
+	if errs := validateConfig(config); len(errs) != 0 {
+		t.Errorf("Valid Supervisor should be accepted: %#v.", errs)
+	}
+
+	config.Drain = map[BotType]*DrainConfig{"dummy": {Timeout: -1 * time.Second}}
+	if errs := validateConfig(config); len(errs) != 1 {
+		t.Errorf("Negative drain timeout should be rejected: %#v.", errs)
+	}
+}
+
 func Test_runner_run(t *testing.T) {
 	SetupAndRun(func() {
 		var botType BotType = "myBot"
@@ -396,6 +558,7 @@ func Test_runner_run(t *testing.T) {
 		}
 
 		r := &runner{
+			status: runnerStatus,
 			config: config,
 			bots: []Bot{
 				bot,
@@ -432,6 +595,68 @@ func Test_runner_run(t *testing.T) {
 
 }
 
+func Test_runner_run_WithRestart(t *testing.T) {
+	SetupAndRun(func() {
+		var botType BotType = "myBot"
+
+		run := make(chan struct{}, 2)
+		bot := &DummyBot{
+			BotTypeValue: botType,
+			RunFunc: func(ctx context.Context, _ func(Input) error, notifyErr func(error)) {
+				run <- struct{}{}
+				notifyErr(errors.New("reconnection error"))
+				<-ctx.Done()
+			},
+		}
+
+		r := &runner{
+			status: runnerStatus,
+			config: &Config{
+				TimeZone: time.Now().Location().String(),
+			},
+			bots: []Bot{
+				bot,
+			},
+			alerters: &alerters{},
+			superviseError: func(_ BotType, _ error) *SupervisionDirective {
+				return &SupervisionDirective{
+					RestartBot:      true,
+					RestartCooldown: 10 * time.Millisecond,
+				}
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		finished := make(chan struct{})
+		go func() {
+			r.run(ctx)
+			close(finished)
+		}()
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-run:
+				// O.K.
+
+			case <-time.NewTimer(1 * time.Second).C:
+				t.Errorf("Bot is not run for %d time(s).", i+1)
+
+			}
+		}
+
+		cancel()
+		<-finished
+
+		botStatus := r.status.snapshot().Bots[0]
+		if botStatus.RestartCount < 1 {
+			t.Errorf("Restart is not counted: %d.", botStatus.RestartCount)
+		}
+		if botStatus.LastError != "reconnection error" || botStatus.LastErrorAt.IsZero() {
+			t.Errorf("Unexpected last error is recorded: %s at %s.", botStatus.LastError, botStatus.LastErrorAt)
+		}
+	})
+}
+
 func Test_runner_runBot(t *testing.T) {
 	SetupAndRun(func() {
 		var botType BotType = "myBot"
@@ -516,7 +741,7 @@ func Test_runner_runBot(t *testing.T) {
 				},
 			},
 			scheduler: &DummyScheduler{
-				UpdateFunc: func(_ BotType, _ ScheduledTask, _ func()) error {
+				UpdateFunc: func(_ BotType, _ ScheduledTask, _ func() error) error {
 					return nil
 				},
 				RemoveFunc: func(_ BotType, _ string) {},
@@ -794,6 +1019,59 @@ func Test_runner_superviseBot(t *testing.T) {
 	}
 }
 
+func Test_runner_superviseBot_WithRestart(t *testing.T) {
+	r := &runner{
+		alerters: &alerters{},
+		superviseError: func(_ BotType, _ error) *SupervisionDirective {
+			return &SupervisionDirective{
+				RestartBot:      true,
+				RestartCooldown: time.Minute,
+			}
+		},
+	}
+	botCtx, errSupervisor := r.superviseBot(context.Background(), "DummyBotType")
+
+	errSupervisor(errors.New("plain error"))
+
+	select {
+	case <-botCtx.Done():
+		// O.K.
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Fatal("Bot context should be canceled at this point.")
+
+	}
+
+	var restart *botRestart
+	if !errors.As(context.Cause(botCtx), &restart) {
+		t.Fatalf("Unexpected cause is set: %#v.", context.Cause(botCtx))
+	}
+
+	if restart.cooldown != time.Minute {
+		t.Errorf("Unexpected cooldown is set: %s.", restart.cooldown)
+	}
+}
+
+func Test_runner_superviseBot_StopPrecedesRestart(t *testing.T) {
+	r := &runner{
+		alerters: &alerters{},
+		superviseError: func(_ BotType, _ error) *SupervisionDirective {
+			return &SupervisionDirective{
+				StopBot:    true,
+				RestartBot: true,
+			}
+		},
+	}
+	botCtx, errSupervisor := r.superviseBot(context.Background(), "DummyBotType")
+
+	errSupervisor(errors.New("plain error"))
+
+	var restart *botRestart
+	if errors.As(context.Cause(botCtx), &restart) {
+		t.Error("Bot should not be restarted when StopBot is true.")
+	}
+}
+
 func Test_executeScheduledTask(t *testing.T) {
 	SetupAndRun(func() {
 		dummyContent := "dummy content"
@@ -850,6 +1128,136 @@ func Test_executeScheduledTask(t *testing.T) {
 	})
 }
 
+func Test_executeScheduledTask_WithBatch(t *testing.T) {
+	results := []*ScheduledTaskResult{
+		{Content: "1", Destination: "#foo"},
+		{Content: "2", Destination: "#foo"},
+		{Content: "3", Destination: "#foo"},
+		{Content: "4", Destination: "#bar"},
+		{Content: "5", Destination: "#foo"},
+	}
+	testSets := []struct {
+		batch    *TaskBatch
+		expected []string
+	}{
+		{
+			batch:    nil,
+			expected: []string{"#foo:1", "#foo:2", "#foo:3", "#bar:4", "#foo:5"},
+		},
+		{
+			batch:    &TaskBatch{},
+			expected: []string{"#foo:1", "#foo:2", "#foo:3", "#bar:4", "#foo:5"},
+		},
+		{
+			batch:    &TaskBatch{MaxMessages: 2},
+			expected: []string{"#foo:1", "#foo:2"},
+		},
+		{
+			batch: &TaskBatch{
+				MaxMessages: 2,
+				TruncationNotice: func(truncated int) interface{} {
+					return fmt.Sprintf("%d more", truncated)
+				},
+			},
+			expected: []string{"#foo:1", "#foo:2", "#foo:2 more", "#bar:1 more"},
+		},
+		{
+			batch:    &TaskBatch{MaxMessages: 10, Interval: time.Millisecond},
+			expected: []string{"#foo:1", "#foo:2", "#foo:3", "#bar:4", "#foo:5"},
+		},
+	}
+
+	for i, tt := range testSets {
+		var sent []string
+		bot := &DummyBot{SendMessageFunc: func(_ context.Context, output Output) {
+			sent = append(sent, fmt.Sprintf("%s:%s", output.Destination(), output.Content()))
+		}}
+		task := &scheduledTask{
+			identifier: "dummy",
+			taskFunc: func(_ context.Context, _ ...TaskConfig) ([]*ScheduledTaskResult, error) {
+				return results, nil
+			},
+			batch: tt.batch,
+		}
+
+		executeScheduledTask(context.TODO(), bot, task)
+
+		if !reflect.DeepEqual(sent, tt.expected) {
+			t.Errorf("Unexpected messages are sent on test %d: %#v.", i, sent)
+		}
+	}
+}
+
+func Test_executeScheduledTask_WithFormatter(t *testing.T) {
+	var sent []Output
+	bot := &DummyBot{
+		BotTypeValue: "dummy",
+		SendMessageFunc: func(_ context.Context, output Output) {
+			sent = append(sent, output)
+		},
+	}
+	task := &scheduledTask{
+		identifier: "dummy",
+		taskFunc: func(_ context.Context, _ ...TaskConfig) ([]*ScheduledTaskResult, error) {
+			return []*ScheduledTaskResult{
+				{Content: 1},
+				{Content: 2, Destination: "#bar"},
+				{Content: -1},
+			}, nil
+		},
+		defaultDestination: "#foo",
+		formatter: func(_ context.Context, botType BotType, result *ScheduledTaskResult) (interface{}, error) {
+			count := result.Content.(int)
+			if count < 0 {
+				return nil, errors.New("invalid count")
+			}
+			return fmt.Sprintf("%s:%s:%d", botType, result.Destination, count), nil
+		},
+	}
+
+	err := executeScheduledTask(context.TODO(), bot, task)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("Unexpected number of outputs are sent: %d.", len(sent))
+	}
+
+	if sent[0].Content() != "dummy:#foo:1" || sent[0].Destination() != "#foo" {
+		t.Errorf("Unexpected output is sent: %#v.", sent[0])
+	}
+
+	if sent[1].Content() != "dummy:#bar:2" || sent[1].Destination() != "#bar" {
+		t.Errorf("Unexpected output is sent: %#v.", sent[1])
+	}
+}
+
+func Test_executeScheduledTask_WithBatchCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := 0
+	bot := &DummyBot{SendMessageFunc: func(_ context.Context, _ Output) {
+		sent++
+		cancel()
+	}}
+	task := &scheduledTask{
+		identifier: "dummy",
+		taskFunc: func(_ context.Context, _ ...TaskConfig) ([]*ScheduledTaskResult, error) {
+			return []*ScheduledTaskResult{
+				{Content: "1", Destination: "#foo"},
+				{Content: "2", Destination: "#foo"},
+			}, nil
+		},
+		batch: &TaskBatch{Interval: time.Hour},
+	}
+
+	executeScheduledTask(ctx, bot, task)
+
+	if sent != 1 {
+		t.Errorf("Unexpected number of messages are sent: %d.", sent)
+	}
+}
+
 func Test_setupInputReceiver(t *testing.T) {
 	SetupAndRun(func() {
 		responded := make(chan bool, 1)
@@ -868,7 +1276,7 @@ func Test_setupInputReceiver(t *testing.T) {
 			},
 		}
 
-		receiveInput := setupInputReceiver(context.TODO(), bot, worker)
+		receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
 		if err := receiveInput(&DummyInput{}); err != nil {
 			t.Errorf("Error should not be returned at this point: %s.", err.Error())
 		}
@@ -891,7 +1299,7 @@ func Test_setupInputReceiver_BlockedInputError(t *testing.T) {
 			},
 		}
 
-		receiveInput := setupInputReceiver(context.TODO(), bot, worker)
+		receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
 		err := receiveInput(&DummyInput{})
 		if err == nil {
 			t.Fatal("Expected error is not returned.")
@@ -1133,7 +1541,7 @@ func Test_registerScheduledTasks(t *testing.T) {
 						botType: tt.tasks,
 					},
 					scheduler: &DummyScheduler{
-						UpdateFunc: func(_ BotType, _ ScheduledTask, _ func()) error {
+						UpdateFunc: func(_ BotType, _ ScheduledTask, _ func() error) error {
 							if tt.updateError {
 								return errors.New("update error")
 							}
@@ -1153,3 +1561,26 @@ func Test_registerScheduledTasks(t *testing.T) {
 		}
 	})
 }
+
+func Benchmark_setupInputReceiver(b *testing.B) {
+	worker := &DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			fnc()
+			return nil
+		},
+	}
+	bot := &DummyBot{
+		BotTypeValue: "dummy",
+		RespondFunc: func(_ context.Context, _ Input) error {
+			return nil
+		},
+	}
+	receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
+	input := &DummyInput{MessageValue: ".echo foo"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = receiveInput(input)
+	}
+}
@@ -0,0 +1,183 @@
+// Package sarahtest provides utilities to test Commands, ScheduledTasks, and other components built on top of sarah in a deterministic manner.
+//
+// FakeClock replaces the system clock so time-based behaviors such as supervision windows, rate limiters, retry intervals, and the schedules of ScheduledTasks
+// proceed only when the test advances the time.
+//
+//	clock := sarahtest.NewFakeClock(time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC))
+//	sarah.RegisterClock(clock)
+//	// Run sarah and let the component under test set its timer.
+//	clock.BlockUntil(1)
+//	clock.Advance(time.Minute)
+package sarahtest
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/robfig/cron/v3"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock is a sarah.Clock implementation whose time proceeds only when Advance is called.
+type FakeClock struct {
+	now    time.Time
+	timers []*fakeTimer
+	mutex  sync.Mutex
+	cond   *sync.Cond
+}
+
+var _ sarah.Clock = (*FakeClock)(nil)
+
+// NewFakeClock creates and returns a new FakeClock instance that starts at the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{
+		now: now,
+	}
+	c.cond = sync.NewCond(&c.mutex)
+	return c
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// NewTimer creates a new sarah.Timer that fires when the fake time reaches the deadline.
+func (c *FakeClock) NewTimer(d time.Duration) sarah.Timer {
+	return c.addTimer(d, make(chan time.Time, 1), nil)
+}
+
+// AfterFunc creates a new sarah.Timer that calls the given function when the fake time reaches the deadline.
+// Unlike time.AfterFunc, the function is called synchronously in Advance so the test can assert its effect right after Advance returns.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) sarah.Timer {
+	return c.addTimer(d, nil, f)
+}
+
+func (c *FakeClock) addTimer(d time.Duration, ch chan time.Time, f func()) *fakeTimer {
+	c.mutex.Lock()
+	timer := &fakeTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		ch:       ch,
+		f:        f,
+	}
+	c.timers = append(c.timers, timer)
+	c.cond.Broadcast()
+	c.mutex.Unlock()
+
+	if d <= 0 {
+		// Fire immediately just like the timers of the time package do.
+		c.Advance(0)
+	}
+	return timer
+}
+
+// Advance moves the fake time forward by the given duration and fires the timers whose deadlines are reached in order of their deadlines.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	target := c.now.Add(d)
+	for {
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			return c.timers[i].deadline.Before(c.timers[j].deadline)
+		})
+		if len(c.timers) == 0 || c.timers[0].deadline.After(target) {
+			break
+		}
+
+		timer := c.timers[0]
+		c.timers = c.timers[1:]
+		if timer.deadline.After(c.now) {
+			c.now = timer.deadline
+		}
+
+		// Release the lock while firing so the function can call the clock or set another timer.
+		now := c.now
+		c.mutex.Unlock()
+		timer.fire(now)
+		c.mutex.Lock()
+	}
+	c.now = target
+	c.mutex.Unlock()
+}
+
+// PendingTimers returns the number of timers that are not fired or stopped, yet.
+func (c *FakeClock) PendingTimers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until the given number of timers are pending.
+// Use this to wait for a goroutine under test to set its timer before calling Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) stop(timer *fakeTimer) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, t := range c.timers {
+		if t == timer {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	ch       chan time.Time
+	f        func()
+}
+
+var _ sarah.Timer = (*fakeTimer)(nil)
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.stop(t)
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	if t.f != nil {
+		t.f()
+		return
+	}
+
+	select {
+	case t.ch <- now:
+	default:
+		// Already fired and not received.
+	}
+}
+
+// NextRuns returns the next n activation times of the given schedule after the given time.
+// The schedule is parsed in the same way as sarah's scheduler does, so a ScheduledTask's schedule can be verified without waiting for the actual execution.
+// The activation times are calculated in the location of the given time.
+func NextRuns(schedule string, from time.Time, n int) ([]time.Time, error) {
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]time.Time, 0, n)
+	next := from
+	for i := 0; i < n; i++ {
+		next = parsed.Next(next)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+	}
+	return runs, nil
+}
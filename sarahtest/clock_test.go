@@ -0,0 +1,135 @@
+package sarahtest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock_Now(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+
+	if !clock.Now().Equal(now) {
+		t.Errorf("Unexpected time is returned: %s.", clock.Now())
+	}
+
+	clock.Advance(time.Minute)
+	if !clock.Now().Equal(now.Add(time.Minute)) {
+		t.Errorf("Time is not advanced: %s.", clock.Now())
+	}
+}
+
+func TestFakeClock_NewTimer(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("Timer fired before the deadline.")
+	default:
+		// O.K.
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case fired := <-timer.C():
+		if !fired.Equal(now.Add(time.Minute)) {
+			t.Errorf("Unexpected time is delivered: %s.", fired)
+		}
+	default:
+		t.Fatal("Timer did not fire at the deadline.")
+	}
+
+	if timer.Stop() {
+		t.Error("Fired timer should not be stopped.")
+	}
+}
+
+func TestFakeClock_AfterFunc(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	var called []string
+	clock.AfterFunc(2*time.Second, func() {
+		called = append(called, "second")
+	})
+	clock.AfterFunc(time.Second, func() {
+		called = append(called, "first")
+	})
+	stopped := clock.AfterFunc(time.Second, func() {
+		called = append(called, "stopped")
+	})
+
+	if clock.PendingTimers() != 3 {
+		t.Fatalf("Unexpected number of timers are pending: %d.", clock.PendingTimers())
+	}
+	if !stopped.Stop() {
+		t.Error("Pending timer should be stopped.")
+	}
+
+	clock.Advance(time.Hour)
+	if len(called) != 2 || called[0] != "first" || called[1] != "second" {
+		t.Errorf("Unexpected calls: %#v.", called)
+	}
+	if clock.PendingTimers() != 0 {
+		t.Errorf("Fired timers are still pending: %d.", clock.PendingTimers())
+	}
+}
+
+func TestFakeClock_AfterFunc_Immediate(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	called := false
+	clock.AfterFunc(0, func() {
+		called = true
+	})
+
+	if !called {
+		t.Error("Function should be called immediately.")
+	}
+}
+
+func TestFakeClock_BlockUntil(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	fired := make(chan struct{})
+	go func() {
+		<-clock.NewTimer(time.Minute).C()
+		close(fired)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	select {
+	case <-fired:
+		// O.K.
+	case <-time.NewTimer(10 * time.Second).C:
+		t.Error("Timer did not fire.")
+	}
+}
+
+func TestNextRuns(t *testing.T) {
+	from := time.Date(2026, 1, 1, 9, 10, 0, 0, time.UTC)
+	runs, err := NextRuns("*/30 * * * *", from, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	expected := []time.Time{
+		time.Date(2026, 1, 1, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC),
+	}
+	if len(runs) != len(expected) {
+		t.Fatalf("Unexpected number of runs are returned: %d.", len(runs))
+	}
+	for i, run := range runs {
+		if !run.Equal(expected[i]) {
+			t.Errorf("Unexpected run is returned at %d: %s.", i, run)
+		}
+	}
+
+	_, err = NextRuns("invalid", from, 1)
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
@@ -5,19 +5,51 @@ import (
 	"fmt"
 	"github.com/oklahomer/go-kasumi/logger"
 	"github.com/robfig/cron/v3"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// SchedulerStats represents the statistics of the scheduler that executes the registered ScheduledTasks.
+type SchedulerStats struct {
+	// RecoveredPanics is the number of panics recovered in the scheduled executions.
+	RecoveredPanics uint64
+
+	// SkippedRuns is the number of scheduled executions skipped because the previous execution of the same task was still running.
+	SkippedRuns uint64
+}
+
 type scheduler interface {
 	remove(BotType, string)
-	update(BotType, ScheduledTask, func()) error
+	update(BotType, ScheduledTask, func() error) error
+	once(BotType, string, time.Time, func()) error
+	setVerbose(bool)
+	stats() *SchedulerStats
 }
 
 type taskScheduler struct {
-	cron         *cron.Cron
+	cron         *clockCron
+	log          *cronLogAdapter
 	removingTask chan *removingTask
 	updatingTask chan *updatingTask
+	oneShotTask  chan *oneShotTask
+	chains       *taskChains
+	done         <-chan struct{}
+}
+
+// setVerbose switches whether to output cron's informational logs such as the execution of each job.
+// Skipped runs and errors are always logged.
+func (s *taskScheduler) setVerbose(verbose bool) {
+	s.log.setVerbose(verbose)
+}
+
+func (s *taskScheduler) stats() *SchedulerStats {
+	return &SchedulerStats{
+		RecoveredPanics: atomic.LoadUint64(&s.log.panics),
+		SkippedRuns:     atomic.LoadUint64(&s.log.skips),
+	}
 }
 
 func (s *taskScheduler) remove(botType BotType, taskID string) {
@@ -28,7 +60,7 @@ func (s *taskScheduler) remove(botType BotType, taskID string) {
 	s.removingTask <- remove
 }
 
-func (s *taskScheduler) update(botType BotType, task ScheduledTask, fn func()) error {
+func (s *taskScheduler) update(botType BotType, task ScheduledTask, fn func() error) error {
 	add := &updatingTask{
 		botType: botType,
 		task:    task,
@@ -40,26 +72,98 @@ func (s *taskScheduler) update(botType BotType, task ScheduledTask, fn func()) e
 	return <-add.err
 }
 
+// once schedules the given function to be executed only once at the given time.
+// The job can be canceled with remove until it runs, and it is removed from the scheduler after its execution.
+func (s *taskScheduler) once(botType BotType, id string, at time.Time, fn func()) error {
+	add := &oneShotTask{
+		botType: botType,
+		id:      id,
+		at:      at,
+		fn:      fn,
+		err:     make(chan error, 1),
+	}
+
+	select {
+	case <-s.done:
+		return fmt.Errorf("scheduler is already stopped")
+
+	default:
+		// Still running.
+
+	}
+
+	select {
+	case s.oneShotTask <- add:
+
+	case <-s.done:
+		return fmt.Errorf("scheduler is already stopped")
+
+	}
+
+	select {
+	case err := <-add.err:
+		return err
+
+	case <-s.done:
+		return fmt.Errorf("scheduler is already stopped")
+
+	}
+}
+
 type removingTask struct {
 	botType BotType
 	taskID  string
 }
 
+type oneShotTask struct {
+	botType BotType
+	id      string
+	at      time.Time
+	fn      func()
+	err     chan error
+}
+
+// oneShotSchedule is a cron.Schedule implementation that activates only once at the given time.
+type oneShotSchedule struct {
+	at time.Time
+}
+
+var _ cron.Schedule = (*oneShotSchedule)(nil)
+
+// Next returns the activation time if it is still ahead; otherwise, returns zero time so cron never runs the job again.
+func (s *oneShotSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
 type updatingTask struct {
 	botType BotType
 	task    ScheduledTask
-	fn      func()
+	fn      func() error
 	err     chan error
 }
 
 func runScheduler(ctx context.Context, location *time.Location) scheduler {
-	c := cron.New(cron.WithLocation(location), cron.WithLogger(&cronLogAdapter{l: logger.GetLogger()}))
+	log := &cronLogAdapter{l: logger.GetLogger()}
+	c := newClockCron(
+		location,
+		// Recover a panicking job so the other jobs keep running, and skip a job while its previous execution is still running.
+		// Both are reported to cronLogAdapter and are counted as part of SchedulerStats.
+		cron.NewChain(cron.Recover(log), cron.SkipIfStillRunning(log)),
+		log,
+	)
 	c.Start()
 
 	s := &taskScheduler{
 		cron:         c,
+		log:          log,
 		removingTask: make(chan *removingTask, 1),
 		updatingTask: make(chan *updatingTask, 1),
+		oneShotTask:  make(chan *oneShotTask, 1),
+		chains:       newTaskChains(),
+		done:         ctx.Done(),
 	}
 
 	go s.receiveEvent(ctx)
@@ -95,37 +199,217 @@ func (s *taskScheduler) receiveEvent(ctx context.Context) {
 
 		case remove := <-s.removingTask:
 			removeFunc(remove.botType, remove.taskID)
+			s.chains.remove(remove.botType, remove.taskID)
+
+		case add := <-s.oneShotTask:
+			removeFunc(add.botType, add.id)
+
+			botType := add.botType
+			id := add.id
+			fn := add.fn
+			entryID := s.cron.Schedule(&oneShotSchedule{at: add.at}, cron.FuncJob(func() {
+				fn()
+
+				// Remove the entry that never runs again.
+				select {
+				case s.removingTask <- &removingTask{botType: botType, taskID: id}:
+				case <-s.done:
+				}
+			}))
+
+			if _, ok := schedule[botType]; !ok {
+				schedule[botType] = make(map[string]cron.EntryID)
+			}
+			schedule[botType][id] = entryID
+			add.err <- nil
 
 		case add := <-s.updatingTask:
+			botType := add.botType
+			taskID := add.task.Identifier()
+			after := upstreamTaskID(add.task)
+			if after != "" {
+				// A chained task is not scheduled by itself, but runs right after the upstream task succeeds.
+				err := s.chains.detectCycle(botType, taskID, after)
+				if err != nil {
+					add.err <- err
+					continue
+				}
+
+				removeFunc(botType, taskID)
+				s.chains.set(botType, taskID, after, add.fn)
+				add.err <- nil
+				continue
+			}
+
 			if add.task.Schedule() == "" {
-				add.err <- fmt.Errorf("empty schedule is given for %s", add.task.Identifier())
+				add.err <- fmt.Errorf("empty schedule is given for %s", taskID)
 				continue
 			}
 
-			removeFunc(add.botType, add.task.Identifier())
+			removeFunc(botType, taskID)
 
-			id, err := s.cron.AddFunc(add.task.Schedule(), add.fn)
+			id, err := s.cron.AddFunc(add.task.Schedule(), func() {
+				s.chains.run(botType, taskID)
+			})
 			if err != nil {
 				add.err <- err
 				break
 			}
+			s.chains.set(botType, taskID, "", add.fn)
 
 			if _, ok := schedule[add.botType]; !ok {
 				schedule[add.botType] = make(map[string]cron.EntryID)
 			}
-			schedule[add.botType][add.task.Identifier()] = id
+			schedule[add.botType][taskID] = id
 			add.err <- nil
 		}
 	}
 }
 
+// upstreamTaskID returns the identifier of the task that the given task depends on, or an empty string if the task is not chained.
+func upstreamTaskID(task ScheduledTask) string {
+	chained, ok := task.(ChainedScheduledTask)
+	if !ok {
+		return ""
+	}
+	return chained.After()
+}
+
+// chainedTask holds a registered task's function and its upstream task.
+type chainedTask struct {
+	after string
+	fn    func() error
+}
+
+// taskChains manages the dependencies among the registered tasks.
+// Calls to its methods are thread-safe since run is called by cron's goroutine while the others are called by taskScheduler.receiveEvent.
+type taskChains struct {
+	tasks map[BotType]map[string]*chainedTask
+	mutex sync.RWMutex
+}
+
+func newTaskChains() *taskChains {
+	return &taskChains{
+		tasks: make(map[BotType]map[string]*chainedTask),
+	}
+}
+
+func (c *taskChains) set(botType BotType, taskID string, after string, fn func() error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.tasks[botType]; !ok {
+		c.tasks[botType] = make(map[string]*chainedTask)
+	}
+	c.tasks[botType][taskID] = &chainedTask{
+		after: after,
+		fn:    fn,
+	}
+}
+
+func (c *taskChains) remove(botType BotType, taskID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if tasks, ok := c.tasks[botType]; ok {
+		delete(tasks, taskID)
+	}
+}
+
+// detectCycle returns ErrTaskDependencyCycle when the task with the given identifier ends up depending on itself.
+func (c *taskChains) detectCycle(botType BotType, taskID string, after string) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	tasks := c.tasks[botType]
+	path := []string{taskID}
+	for current := after; current != ""; {
+		path = append(path, current)
+		if current == taskID {
+			return fmt.Errorf("%w: %s", ErrTaskDependencyCycle, strings.Join(path, " -> "))
+		}
+
+		task, ok := tasks[current]
+		if !ok {
+			// The upstream task is not registered yet.
+			return nil
+		}
+		current = task.after
+	}
+
+	return nil
+}
+
+// downstream returns the identifiers of the tasks that directly depend on the given task in alphabetical order.
+func (c *taskChains) downstream(botType BotType, taskID string) []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var ids []string
+	for id, task := range c.tasks[botType] {
+		if task.after == taskID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// run executes the given task and then its downstream tasks in the same scheduling cycle.
+// When the task fails, its downstream tasks are skipped and so are theirs.
+func (c *taskChains) run(botType BotType, taskID string) {
+	c.mutex.RLock()
+	task, ok := c.tasks[botType][taskID]
+	c.mutex.RUnlock()
+	if !ok {
+		// The task is removed in the meantime.
+		return
+	}
+
+	err := task.fn()
+	downstream := c.downstream(botType, taskID)
+	if err != nil {
+		if len(downstream) > 0 {
+			logger.Warnf("Skipping downstream task(s) of %s due to its failure: %s. Error: %+v", taskID, strings.Join(downstream, ", "), err)
+		}
+		return
+	}
+
+	for _, id := range downstream {
+		c.run(botType, id)
+	}
+}
+
+// cronLogAdapter bridges cron.Logger to go-sarah's logger.
+// This also counts the panics and the skipped runs that are reported by cron.Recover and cron.SkipIfStillRunning.
 type cronLogAdapter struct {
-	l logger.Logger
+	l      logger.Logger
+	quiet  int32
+	panics uint64
+	skips  uint64
 }
 
 var _ cron.Logger = (*cronLogAdapter)(nil)
 
+func (c *cronLogAdapter) setVerbose(verbose bool) {
+	var quiet int32
+	if !verbose {
+		quiet = 1
+	}
+	atomic.StoreInt32(&c.quiet, quiet)
+}
+
 func (c *cronLogAdapter) Info(msg string, keysAndValues ...interface{}) {
+	switch {
+	case msg == "skip":
+		// Reported by cron.SkipIfStillRunning.
+		atomic.AddUint64(&c.skips, 1)
+
+	case atomic.LoadInt32(&c.quiet) == 1:
+		return
+
+	}
+
 	converted := c.convertKeyValues(keysAndValues)
 	args := append([]interface{}{msg}, converted...)
 	format := c.formatString(len(args))
@@ -133,6 +417,11 @@ func (c *cronLogAdapter) Info(msg string, keysAndValues ...interface{}) {
 }
 
 func (c *cronLogAdapter) Error(err error, msg string, keysAndValues ...interface{}) {
+	if msg == "panic" {
+		// Reported by cron.Recover.
+		atomic.AddUint64(&c.panics, 1)
+	}
+
 	converted := c.convertKeyValues(keysAndValues)
 	args := append([]interface{}{msg, "error", err}, converted...)
 	format := c.formatString(len(args))
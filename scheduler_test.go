@@ -8,24 +8,40 @@ import (
 	"github.com/oklahomer/go-kasumi/logger"
 	"io"
 	"log"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
 )
 
 type DummyScheduler struct {
-	RemoveFunc func(BotType, string)
-	UpdateFunc func(BotType, ScheduledTask, func()) error
+	RemoveFunc     func(BotType, string)
+	UpdateFunc     func(BotType, ScheduledTask, func() error) error
+	OnceFunc       func(BotType, string, time.Time, func()) error
+	SetVerboseFunc func(bool)
+	StatsFunc      func() *SchedulerStats
 }
 
 func (s *DummyScheduler) remove(botType BotType, taskID string) {
 	s.RemoveFunc(botType, taskID)
 }
 
-func (s *DummyScheduler) update(botType BotType, task ScheduledTask, fn func()) error {
+func (s *DummyScheduler) update(botType BotType, task ScheduledTask, fn func() error) error {
 	return s.UpdateFunc(botType, task, fn)
 }
 
+func (s *DummyScheduler) once(botType BotType, id string, at time.Time, fn func()) error {
+	return s.OnceFunc(botType, id, at, fn)
+}
+
+func (s *DummyScheduler) setVerbose(verbose bool) {
+	s.SetVerboseFunc(verbose)
+}
+
+func (s *DummyScheduler) stats() *SchedulerStats {
+	return s.StatsFunc()
+}
+
 func Test_runScheduler(t *testing.T) {
 	rootCtx := context.Background()
 	ctx, cancel := context.WithCancel(rootCtx)
@@ -63,12 +79,12 @@ func TestTaskScheduler_updateAndRemove(t *testing.T) {
 	}
 
 	var storedBotType BotType = "Foo"
-	if err := scheduler.update(storedBotType, task, func() {}); err == nil {
+	if err := scheduler.update(storedBotType, task, func() error { return nil }); err == nil {
 		t.Fatal("Error should return on invalid schedule value.")
 	}
 
 	task.schedule = "@daily"
-	if err := scheduler.update(storedBotType, task, func() {}); err != nil {
+	if err := scheduler.update(storedBotType, task, func() error { return nil }); err != nil {
 		t.Fatalf("Error is returned on valid schedule value: %s", err.Error())
 	}
 	time.Sleep(10 * time.Millisecond)
@@ -96,13 +112,167 @@ func TestTaskScheduler_updateWithEmptySchedule(t *testing.T) {
 	defer cancel()
 	scheduler := runScheduler(ctx, time.Local)
 
-	err := scheduler.update("dummy", &DummyScheduledTask{}, func() {})
+	err := scheduler.update("dummy", &DummyScheduledTask{}, func() error { return nil })
+
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestTaskScheduler_once(t *testing.T) {
+	rootCtx := context.Background()
+	ctx, cancel := context.WithCancel(rootCtx)
+	defer cancel()
+	scheduler := runScheduler(ctx, time.Local)
+
+	var botType BotType = "Foo"
+	executed := make(chan struct{}, 1)
+	err := scheduler.once(botType, "soon", time.Now().Add(time.Second), func() {
+		executed <- struct{}{}
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	err = scheduler.once(botType, "canceled", time.Now().Add(time.Hour), func() {
+		t.Error("Canceled job is executed.")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	scheduler.remove(botType, "canceled")
+
+	select {
+	case <-executed:
+		// O.K.
+
+	case <-time.NewTimer(3 * time.Second).C:
+		t.Fatal("Job is not executed.")
+
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	jobCnt := len(scheduler.(*taskScheduler).cron.Entries())
+	if jobCnt != 0 {
+		t.Errorf("0 job is expected: %d.", jobCnt)
+	}
 
+	cancel()
+	err = scheduler.once(botType, "stopped", time.Now().Add(time.Hour), func() {})
 	if err == nil {
 		t.Error("Expected error is not returned.")
 	}
 }
 
+func Test_oneShotSchedule_Next(t *testing.T) {
+	at := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	schedule := &oneShotSchedule{at: at}
+
+	if next := schedule.Next(at.Add(-time.Minute)); !next.Equal(at) {
+		t.Errorf("Unexpected time is returned: %s.", next)
+	}
+
+	if next := schedule.Next(at); !next.IsZero() {
+		t.Errorf("Zero time is expected: %s.", next)
+	}
+}
+
+func TestTaskScheduler_updateChainedTask(t *testing.T) {
+	rootCtx := context.Background()
+	ctx, cancel := context.WithCancel(rootCtx)
+	defer cancel()
+	scheduler := runScheduler(ctx, time.Local)
+
+	var botType BotType = "Foo"
+	upstream := &scheduledTask{identifier: "fetch", schedule: "@daily"}
+	if err := scheduler.update(botType, upstream, func() error { return nil }); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	downstream := &scheduledTask{identifier: "report", after: "fetch"}
+	if err := scheduler.update(botType, downstream, func() error { return nil }); err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	// A chained task is not scheduled by itself.
+	jobCnt := len(scheduler.(*taskScheduler).cron.Entries())
+	if jobCnt != 1 {
+		t.Errorf("1 job is expected: %d.", jobCnt)
+	}
+
+	// fetch -> report -> fetch
+	cyclic := &scheduledTask{identifier: "fetch", after: "report"}
+	err := scheduler.update(botType, cyclic, func() error { return nil })
+	if !errors.Is(err, ErrTaskDependencyCycle) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func Test_taskChains_detectCycle(t *testing.T) {
+	chains := newTaskChains()
+	chains.set("Foo", "a", "", nil)
+	chains.set("Foo", "b", "a", nil)
+	chains.set("Foo", "c", "b", nil)
+
+	testSets := []struct {
+		taskID string
+		after  string
+		cycle  bool
+	}{
+		{taskID: "d", after: "c", cycle: false},
+		{taskID: "d", after: "unknown", cycle: false},
+		{taskID: "a", after: "c", cycle: true},
+		{taskID: "b", after: "b", cycle: true},
+	}
+
+	for i, tt := range testSets {
+		err := chains.detectCycle("Foo", tt.taskID, tt.after)
+		if tt.cycle && !errors.Is(err, ErrTaskDependencyCycle) {
+			t.Errorf("Expected error is not returned on test %d: %#v.", i, err)
+		}
+		if !tt.cycle && err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+		}
+	}
+
+	// Another Bot's tasks are irrelevant.
+	if err := chains.detectCycle("Bar", "a", "c"); err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+}
+
+func Test_taskChains_run(t *testing.T) {
+	var executed []string
+	fn := func(id string, err error) func() error {
+		return func() error {
+			executed = append(executed, id)
+			return err
+		}
+	}
+
+	chains := newTaskChains()
+	chains.set("Foo", "fetch", "", fn("fetch", nil))
+	chains.set("Foo", "report", "fetch", fn("report", nil))
+	chains.set("Foo", "archive", "fetch", fn("archive", errors.New("failed")))
+	chains.set("Foo", "cleanup", "archive", fn("cleanup", nil))
+	chains.set("Foo", "notify", "report", fn("notify", nil))
+	chains.set("Bar", "report", "fetch", fn("irrelevant", nil))
+
+	chains.run("Foo", "fetch")
+
+	expected := []string{"fetch", "archive", "report", "notify"}
+	if !reflect.DeepEqual(executed, expected) {
+		t.Errorf("Unexpected execution order: %#v.", executed)
+	}
+
+	executed = nil
+	chains.remove("Foo", "fetch")
+	chains.run("Foo", "fetch")
+	if len(executed) != 0 {
+		t.Errorf("Removed task is executed: %#v.", executed)
+	}
+}
+
 func Test_cronLogAdapter_Info(t *testing.T) {
 	buffer := bytes.NewBuffer([]byte{})
 	c := &cronLogAdapter{
@@ -143,6 +313,93 @@ func Test_cronLogAdapter_Info(t *testing.T) {
 	}
 }
 
+func Test_cronLogAdapter_Info_Quiet(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	c := &cronLogAdapter{
+		l: logger.NewWithStandardLogger(log.New(buffer, "", 0)),
+	}
+	c.setVerbose(false)
+
+	c.Info("run", "entry", 1)
+	if buffer.Len() != 0 {
+		t.Errorf("Informational log should be suppressed: %s", buffer.String())
+	}
+
+	c.Info("skip")
+	if buffer.String() != "[INFO] skip\n" {
+		t.Errorf("Skipped run should always be logged: %s", buffer.String())
+	}
+
+	if c.skips != 1 {
+		t.Errorf("Skipped run is not counted: %d.", c.skips)
+	}
+
+	_, _ = io.Copy(io.Discard, buffer)
+	c.setVerbose(true)
+	c.Info("run", "entry", 1)
+	if buffer.Len() == 0 {
+		t.Error("Informational log should be output in verbose mode.")
+	}
+}
+
+func Test_cronLogAdapter_Error_Panic(t *testing.T) {
+	c := &cronLogAdapter{
+		l: logger.NewWithStandardLogger(log.New(io.Discard, "", 0)),
+	}
+
+	c.Error(errors.New("error"), "failure")
+	c.Error(errors.New("panic!"), "panic", "stack", "...")
+
+	if c.panics != 1 {
+		t.Errorf("Unexpected number of panics are counted: %d.", c.panics)
+	}
+}
+
+func TestTaskScheduler_stats(t *testing.T) {
+	s := &taskScheduler{
+		log: &cronLogAdapter{
+			l:      logger.NewWithStandardLogger(log.New(io.Discard, "", 0)),
+			panics: 1,
+			skips:  2,
+		},
+	}
+
+	stats := s.stats()
+	if stats.RecoveredPanics != 1 || stats.SkippedRuns != 2 {
+		t.Errorf("Unexpected stats are returned: %#v.", stats)
+	}
+
+	s.setVerbose(false)
+	if s.log.quiet != 1 {
+		t.Error("Verbose mode is not turned off.")
+	}
+}
+
+func TestTaskScheduler_RecoverPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := runScheduler(ctx, time.Local).(*taskScheduler)
+
+	task := &scheduledTask{identifier: "panic", schedule: "@daily"}
+	err := s.update("dummy", task, func() error {
+		panic("panic!")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	// Run the registered job via the configured chain instead of waiting for the schedule.
+	entries := s.cron.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("1 job is expected: %d.", len(entries))
+	}
+	entries[0].WrappedJob.Run()
+
+	if s.stats().RecoveredPanics != 1 {
+		t.Errorf("Panic is not counted: %d.", s.stats().RecoveredPanics)
+	}
+}
+
 type stringer struct {
 }
 
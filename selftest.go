@@ -0,0 +1,204 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/worker"
+	"sync"
+	"time"
+)
+
+// ErrRunnerNotRunning is returned by SelfTest when Run is not called, yet.
+var ErrRunnerNotRunning = errors.New("go-sarah's process is not running")
+
+// selfTestKey is the key and the configuration id that SelfTest uses to probe UserContextStorage and ConfigWatcher.
+const selfTestKey = "sarah-selftest"
+
+var selfTester = &selfTest{}
+
+// SelfTestResult represents the result of a check that SelfTest runs.
+type SelfTestResult struct {
+	// Latency is how long the check took.
+	Latency time.Duration
+
+	// Error is the reason of the failure. This is empty when the check succeeds.
+	Error string
+}
+
+// OK tells if the check succeeded.
+func (r *SelfTestResult) OK() bool {
+	return r.Error == ""
+}
+
+// SelfTestReport represents the result of SelfTest.
+type SelfTestReport struct {
+	// TestedAt is the time when SelfTest started.
+	TestedAt time.Time
+
+	// Worker represents the round trip of a job through the worker that executes the jobs such as Command executions.
+	Worker *SelfTestResult
+
+	// Bots holds a list of BotSelfTestReport values where each value represents its corresponding Bot's checks.
+	Bots []*BotSelfTestReport
+}
+
+// Healthy tells if every check in the report succeeded.
+func (r *SelfTestReport) Healthy() bool {
+	results := []*SelfTestResult{r.Worker}
+	for _, bot := range r.Bots {
+		results = append(results, bot.UserContextStorage, bot.ConfigWatcher)
+	}
+
+	for _, result := range results {
+		if result != nil && !result.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// BotSelfTestReport represents the checks of a Bot.
+type BotSelfTestReport struct {
+	// Type represents a BotType the corresponding Bot.BotType returns.
+	Type BotType
+
+	// UserContextStorage represents the reachability of the Bot's UserContextStorage.
+	// This is nil when the Bot has no UserContextStorage.
+	UserContextStorage *SelfTestResult
+
+	// ConfigWatcher represents the reachability of the registered ConfigWatcher from the Bot.
+	// This is nil when no ConfigWatcher is registered.
+	ConfigWatcher *SelfTestResult
+
+	// LastSendMessage is how long the Bot's latest Adapter.SendMessage call took.
+	// This is zero when the Bot sent no message, yet, or the Bot is not built with NewBot.
+	LastSendMessage time.Duration
+}
+
+// SelfTest checks if the components of the running process are reachable and reports how long each check takes.
+// This enqueues a job to the worker, reads the UserContextStorage of each Bot, and reads a configuration via the registered ConfigWatcher.
+// Give ctx with a timeout to limit the duration of the checks.
+//
+// Use this to build a health check command or an endpoint for external monitoring. See contrib/selftest for the built-in implementations.
+func SelfTest(ctx context.Context) (*SelfTestReport, error) {
+	return defaultRunner().SelfTest(ctx)
+}
+
+// SelfTest is the Runner counterpart of the package-level SelfTest.
+func (rn *Runner) SelfTest(ctx context.Context) (*SelfTestReport, error) {
+	return rn.selfTester.run(ctx)
+}
+
+// storageProber is satisfied by a Bot that can check the reachability of its UserContextStorage.
+type storageProber interface {
+	probeUserContextStorage() (bool, error)
+}
+
+// sendLatencyProvider is satisfied by a Bot that can tell how long its latest Adapter.SendMessage call took.
+type sendLatencyProvider interface {
+	lastSendLatency() time.Duration
+}
+
+type selfTest struct {
+	worker  worker.Worker
+	watcher ConfigWatcher
+	bots    []Bot
+	mutex   sync.RWMutex
+}
+
+func (s *selfTest) set(wkr worker.Worker, watcher ConfigWatcher, bots []Bot) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.worker = wkr
+	s.watcher = watcher
+	s.bots = bots
+}
+
+func (s *selfTest) run(ctx context.Context) (*SelfTestReport, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.worker == nil {
+		return nil, ErrRunnerNotRunning
+	}
+
+	report := &SelfTestReport{
+		TestedAt: time.Now(),
+		Worker:   probe(ctx, s.probeWorker),
+	}
+	for _, bot := range s.bots {
+		botReport := &BotSelfTestReport{
+			Type: bot.BotType(),
+		}
+
+		if prober, ok := bot.(storageProber); ok {
+			var exists bool
+			result := probe(ctx, func(_ context.Context) error {
+				var err error
+				exists, err = prober.probeUserContextStorage()
+				return err
+			})
+			if exists {
+				botReport.UserContextStorage = result
+			}
+		}
+
+		if _, ok := s.watcher.(*nullConfigWatcher); !ok && s.watcher != nil {
+			botReport.ConfigWatcher = probe(ctx, func(ctx context.Context) error {
+				return s.probeConfigWatcher(ctx, bot.BotType())
+			})
+		}
+
+		if provider, ok := bot.(sendLatencyProvider); ok {
+			botReport.LastSendMessage = provider.lastSendLatency()
+		}
+
+		report.Bots = append(report.Bots, botReport)
+	}
+
+	return report, nil
+}
+
+func (s *selfTest) probeWorker(ctx context.Context) error {
+	done := make(chan struct{})
+	err := s.worker.Enqueue(func() {
+		close(done)
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+
+	}
+}
+
+func (s *selfTest) probeConfigWatcher(ctx context.Context, botType BotType) error {
+	err := s.watcher.Read(ctx, botType, selfTestKey, &map[string]interface{}{})
+	var notFoundErr *ConfigNotFoundError
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return err
+	}
+
+	// The configuration is not expected to exist, but reaching the source of the configurations is what matters.
+	return nil
+}
+
+// probe runs the given check and measures its latency.
+func probe(ctx context.Context, check func(context.Context) error) *SelfTestResult {
+	started := time.Now()
+	err := check(ctx)
+	result := &SelfTestResult{
+		Latency: time.Since(started),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
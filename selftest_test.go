@@ -0,0 +1,153 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSelfTest_NotRunning(t *testing.T) {
+	tester := &selfTest{}
+	_, err := tester.run(context.TODO())
+	if !errors.Is(err, ErrRunnerNotRunning) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func Test_selfTest_run(t *testing.T) {
+	storageBot := &defaultBot{
+		botType: "storage",
+		userContextStorage: &DummyUserContextStorage{
+			GetFunc: func(key string) (ContextualFunc, error) {
+				if key != selfTestKey {
+					t.Errorf("Unexpected key is given: %s.", key)
+				}
+				return nil, nil
+			},
+		},
+		sendLatency: int64(time.Second),
+	}
+	brokenBot := &defaultBot{
+		botType: "broken",
+		userContextStorage: &DummyUserContextStorage{
+			GetFunc: func(_ string) (ContextualFunc, error) {
+				return nil, errors.New("connection refused")
+			},
+		},
+	}
+	plainBot := &DummyBot{
+		BotTypeValue: "plain",
+	}
+
+	tester := &selfTest{}
+	tester.set(
+		&DummyWorker{
+			EnqueueFunc: func(fnc func()) error {
+				go fnc()
+				return nil
+			},
+		},
+		&DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, botType BotType, id string, _ interface{}) error {
+				if id != selfTestKey {
+					t.Errorf("Unexpected id is given: %s.", id)
+				}
+				if botType == "plain" {
+					return errors.New("permission denied")
+				}
+				return &ConfigNotFoundError{BotType: botType, ID: id}
+			},
+		},
+		[]Bot{storageBot, brokenBot, plainBot},
+	)
+
+	report, err := tester.run(context.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if report.Worker == nil || !report.Worker.OK() {
+		t.Errorf("Unexpected worker result is returned: %#v.", report.Worker)
+	}
+	if len(report.Bots) != 3 {
+		t.Fatalf("Unexpected number of bot reports are returned: %d.", len(report.Bots))
+	}
+
+	storage := report.Bots[0]
+	if storage.UserContextStorage == nil || !storage.UserContextStorage.OK() || !storage.ConfigWatcher.OK() {
+		t.Errorf("Unexpected report is returned: %#v.", storage)
+	}
+	if storage.LastSendMessage != time.Second {
+		t.Errorf("Unexpected latency is returned: %s.", storage.LastSendMessage)
+	}
+
+	broken := report.Bots[1]
+	if broken.UserContextStorage == nil || broken.UserContextStorage.Error != "connection refused" {
+		t.Errorf("Unexpected storage result is returned: %#v.", broken.UserContextStorage)
+	}
+
+	plain := report.Bots[2]
+	if plain.UserContextStorage != nil {
+		t.Errorf("Storage should not be checked: %#v.", plain.UserContextStorage)
+	}
+	if plain.ConfigWatcher == nil || plain.ConfigWatcher.Error != "permission denied" {
+		t.Errorf("Unexpected watcher result is returned: %#v.", plain.ConfigWatcher)
+	}
+
+	if report.Healthy() {
+		t.Error("Report with failures should not be healthy.")
+	}
+}
+
+func Test_selfTest_run_WorkerFailure(t *testing.T) {
+	testSets := []struct {
+		enqueue func(func()) error
+	}{
+		{
+			enqueue: func(_ func()) error {
+				return errors.New("queue is full")
+			},
+		},
+		{
+			enqueue: func(_ func()) error {
+				// Never executed.
+				return nil
+			},
+		},
+	}
+
+	for i, tt := range testSets {
+		tester := &selfTest{}
+		tester.set(&DummyWorker{EnqueueFunc: tt.enqueue}, &nullConfigWatcher{}, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		report, err := tester.run(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if report.Worker.OK() || report.Healthy() {
+			t.Errorf("Worker failure is not reported on test #%d.", i+1)
+		}
+	}
+}
+
+func TestSelfTestReport_Healthy(t *testing.T) {
+	report := &SelfTestReport{
+		Worker: &SelfTestResult{},
+		Bots: []*BotSelfTestReport{
+			{Type: "dummy"},
+		},
+	}
+	if !report.Healthy() {
+		t.Error("Report without failures should be healthy.")
+	}
+}
+
+func TestDefaultBot_probeUserContextStorage(t *testing.T) {
+	bot := &defaultBot{}
+	exists, err := bot.probeUserContextStorage()
+	if exists || err != nil {
+		t.Errorf("Unexpected result is returned: %t, %#v.", exists, err)
+	}
+}
@@ -0,0 +1,73 @@
+package sarah
+
+import (
+	"context"
+	"fmt"
+)
+
+// maskedMessage replaces the message of a sensitive Input in the logs and InputQueueSnapshot.
+const maskedMessage = "********"
+
+// MessageDeleter defines an interface that an Adapter implementation may satisfy to delete a message that a user sent.
+// When an Adapter passed to NewBot satisfies this, an Input that is given to a UserContext with UserContext.Sensitive is deleted after its consumption,
+// so a password or a token does not stay in the chat history.
+type MessageDeleter interface {
+	// DeleteMessage deletes the message that the given Input represents.
+	DeleteMessage(ctx context.Context, input Input) error
+}
+
+// sensitiveInputDetector is satisfied by a Bot that can tell if the user's next Input is sensitive.
+type sensitiveInputDetector interface {
+	expectsSensitiveInput(senderKey string) bool
+}
+
+// isSensitiveInput tells if the given Input is the one that the Bot expects to be sensitive.
+// This must be called before Bot.Respond consumes the Input.
+func isSensitiveInput(bot Bot, input Input) bool {
+	detector, ok := bot.(sensitiveInputDetector)
+	return ok && detector.expectsSensitiveInput(input.SenderKey())
+}
+
+// bypassSensitiveInput returns a function that passes a sensitive Input directly to receive,
+// so the given recording function such as the one transcriptInputReceiver returns does not persist it.
+func bypassSensitiveInput(bot Bot, record func(Input) error, receive func(Input) error) func(Input) error {
+	return func(input Input) error {
+		if isSensitiveInput(bot, input) {
+			return receive(input)
+		}
+		return record(input)
+	}
+}
+
+// loggedInput returns the given Input in a form that can be logged, where the sensitive Input is masked.
+func loggedInput(bot Bot, input Input) Input {
+	if batch, ok := input.(*BatchInput); ok {
+		inputs := make([]Input, len(batch.Inputs))
+		for i, item := range batch.Inputs {
+			inputs[i] = loggedInput(bot, item)
+		}
+		return NewBatchInput(inputs...)
+	}
+
+	if isSensitiveInput(bot, input) {
+		return &maskedInput{Input: input}
+	}
+	return input
+}
+
+// maskedInput hides the message of a sensitive Input.
+type maskedInput struct {
+	Input
+}
+
+var _ Input = (*maskedInput)(nil)
+
+// Message returns the masked message instead of the original one.
+func (i *maskedInput) Message() string {
+	return maskedMessage
+}
+
+// GoString hides the original message when the Input is logged with %#v.
+func (i *maskedInput) GoString() string {
+	return fmt.Sprintf("%T{SenderKey: %q, Message: %q}", i.Input, i.SenderKey(), maskedMessage)
+}
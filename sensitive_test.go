@@ -0,0 +1,153 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type DummyMessageDeleteAdapter struct {
+	DummyAdapter
+	DeleteMessageFunc func(context.Context, Input) error
+}
+
+func (adapter *DummyMessageDeleteAdapter) DeleteMessage(ctx context.Context, input Input) error {
+	return adapter.DeleteMessageFunc(ctx, input)
+}
+
+func TestDefaultBot_Respond_Sensitive(t *testing.T) {
+	var deleted Input
+	adapter := &DummyMessageDeleteAdapter{
+		DummyAdapter: DummyAdapter{
+			BotTypeValue:    "dummy",
+			SendMessageFunc: func(_ context.Context, _ Output) {},
+		},
+		DeleteMessageFunc: func(_ context.Context, input Input) error {
+			deleted = input
+			return errors.New("deletion error is only logged")
+		},
+	}
+	bot := NewBot(adapter, BotWithStorage(NewUserContextStorage(NewCacheConfig()))).(*defaultBot)
+
+	var token string
+	bot.AppendCommand(&DummyCommand{
+		IdentifierValue: "login",
+		MatchFunc: func(input Input) bool {
+			return input.Message() == ".login"
+		},
+		ExecuteFunc: func(_ context.Context, _ Input) (*CommandResponse, error) {
+			return &CommandResponse{
+				Content: "Input your token.",
+				UserContext: &UserContext{
+					Next: func(_ context.Context, input Input) (*CommandResponse, error) {
+						token = input.Message()
+						return &CommandResponse{Content: "Logged in."}, nil
+					},
+					Sensitive: true,
+				},
+			}, nil
+		},
+	})
+
+	err := bot.Respond(context.TODO(), &DummyInput{SenderKeyValue: "user", MessageValue: ".login"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !bot.expectsSensitiveInput("user") {
+		t.Fatal("The next input should be sensitive.")
+	}
+	if bot.expectsSensitiveInput("other") {
+		t.Error("Other user's input should not be sensitive.")
+	}
+	if deleted != nil {
+		t.Error("Non-sensitive message should not be deleted.")
+	}
+
+	input := &DummyInput{SenderKeyValue: "user", MessageValue: "secret"}
+	err = bot.Respond(context.TODO(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if token != "secret" {
+		t.Errorf("Sensitive input is not passed to the next function: %s.", token)
+	}
+	if deleted != input {
+		t.Errorf("Sensitive message is not deleted: %#v.", deleted)
+	}
+	if bot.expectsSensitiveInput("user") {
+		t.Error("The mark should be cleared after the consumption.")
+	}
+}
+
+func Test_bypassSensitiveInput(t *testing.T) {
+	bot := &defaultBot{}
+	bot.markSensitiveInput("sensitive")
+
+	var recorded []string
+	var received []string
+	receive := func(input Input) error {
+		received = append(received, input.SenderKey())
+		return nil
+	}
+	record := func(input Input) error {
+		recorded = append(recorded, input.SenderKey())
+		return receive(input)
+	}
+
+	receiver := bypassSensitiveInput(bot, record, receive)
+	_ = receiver(&DummyInput{SenderKeyValue: "sensitive"})
+	_ = receiver(&DummyInput{SenderKeyValue: "normal"})
+
+	if len(recorded) != 1 || recorded[0] != "normal" {
+		t.Errorf("Unexpected inputs are recorded: %#v.", recorded)
+	}
+	if len(received) != 2 {
+		t.Errorf("All inputs should be received: %#v.", received)
+	}
+
+	// A Bot that can not tell the sensitivity records everything.
+	recorded = nil
+	_ = bypassSensitiveInput(&DummyBot{}, record, receive)(&DummyInput{SenderKeyValue: "sensitive"})
+	if len(recorded) != 1 {
+		t.Errorf("Input should be recorded: %#v.", recorded)
+	}
+}
+
+func Test_maskedInput(t *testing.T) {
+	input := &maskedInput{Input: &DummyInput{SenderKeyValue: "user", MessageValue: "secret"}}
+
+	if input.Message() != maskedMessage {
+		t.Errorf("Message is not masked: %s.", input.Message())
+	}
+	if input.SenderKey() != "user" {
+		t.Errorf("Unexpected SenderKey is returned: %s.", input.SenderKey())
+	}
+	if logged := fmt.Sprintf("%#v", input); strings.Contains(logged, "secret") {
+		t.Errorf("Message is logged: %s.", logged)
+	}
+}
+
+func Test_setupInputReceiver_Sensitive(t *testing.T) {
+	SetupAndRun(func() {
+		bot := NewBot(&DummyAdapter{BotTypeValue: "dummy"}).(*defaultBot)
+		bot.markSensitiveInput("user")
+
+		queued := make(chan []*QueuedInput, 1)
+		worker := &DummyWorker{
+			EnqueueFunc: func(_ func()) error {
+				queued <- InputQueueSnapshot()
+				return errors.New("enqueue error")
+			},
+		}
+
+		receiveInput := setupInputReceiver(context.TODO(), bot, worker, nil)
+		_ = receiveInput(&DummyInput{SenderKeyValue: "user", MessageValue: "secret"})
+
+		snapshot := <-queued
+		if len(snapshot) != 1 || snapshot[0].Message != maskedMessage {
+			t.Errorf("Message is not masked in the snapshot: %#v.", snapshot)
+		}
+	})
+}
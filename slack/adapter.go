@@ -11,6 +11,9 @@ import (
 	"github.com/oklahomer/golack/v2/eventsapi"
 	"github.com/oklahomer/golack/v2/rtmapi"
 	"github.com/oklahomer/golack/v2/webapi"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -33,6 +36,30 @@ func WithSlackClient(client SlackClient) AdapterOption {
 	}
 }
 
+// WithHTTPClient creates an AdapterOption with the given http.Client to call Slack Web API.
+// This takes precedence over Config.HTTPClient, and has no effect when a SlackClient is given with WithSlackClient.
+func WithHTTPClient(httpClient *http.Client) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.httpClient = httpClient
+	}
+}
+
+// WithServeMux creates an AdapterOption with the given *http.ServeMux to receive Events API requests.
+// When this option is given, the Events API handler is mounted on the mux with Config.EventsPath
+// instead of starting a dedicated server, so the host application can serve Events API along with its own endpoints.
+// Config.ListenPort, TLS and timeout settings are ignored in that case since the host application's server is responsible for them.
+//
+//   mux := http.NewServeMux()
+//   slackAdapter, _ := slack.NewAdapter(slackConfig, slack.WithEventsPayloadHandler(slack.DefaultEventsPayloadHandler), slack.WithServeMux(mux))
+//   go http.ListenAndServe(":8080", mux)
+//
+// This option has no effect with WithRTMPayloadHandler.
+func WithServeMux(mux *http.ServeMux) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.serveMux = &serveMuxMount{mux: mux}
+	}
+}
+
 // WithEventsPayloadHandler creates an AdapterOption with the given function to handle incoming Events API payloads.
 // The simplest example to receive a message payload is to use a default payload handler as below:
 //
@@ -41,11 +68,15 @@ func WithSlackClient(client SlackClient) AdapterOption {
 // See WithRTMPayloadHandler for the detailed usage. WithEventsPayloadHandler is just another form of payload handler to work with Events API.
 func WithEventsPayloadHandler(fnc func(context.Context, *Config, *eventsapi.EventWrapper, func(sarah.Input) error)) AdapterOption {
 	return func(adapter *Adapter) {
+		adapter.connStats = nil
 		adapter.apiSpecificAdapterBuilder = func(config *Config, client SlackClient) apiSpecificAdapter {
 			return &eventsAPIAdapter{
 				config:        adapter.config,
 				client:        adapter.client,
-				handlePayload: fnc,
+				handlePayload: adapter.subscriptions.eventsPayloadHandler(fnc),
+				serveMux:      adapter.serveMux,
+				modals:        adapter.modals,
+				teams:         adapter.teams,
 			}
 		}
 	}
@@ -59,6 +90,7 @@ func WithEventsPayloadHandler(fnc func(context.Context, *Config, *eventsapi.Even
 // However, Slack's RTM API defines a relatively large amount of payload types.
 // To have a better user experience, developers may provide a customized callback function to handle different types of received payloads.
 // In that case, one may implement a new payload handler and replace DefaultRTMPayloadHandler.
+// When only a few extra event types are of interest, Adapter.On is handier since the default payload handler can be kept as-is.
 // Inside the customized payload handler, a developer may wish to have direct access to SlackClient to post some sort of message to Slack via Web API.
 // To support such a scenario, wrap this function like below so the SlackClient can be accessed within its scope.
 //
@@ -106,11 +138,14 @@ func WithEventsPayloadHandler(fnc func(context.Context, *Config, *eventsapi.Even
 //  slackBot, _ := sarah.NewBot(slackAdapter)
 func WithRTMPayloadHandler(fnc func(context.Context, *Config, rtmapi.DecodedPayload, func(sarah.Input) error)) AdapterOption {
 	return func(adapter *Adapter) {
+		adapter.connStats = &connectionStats{}
 		adapter.apiSpecificAdapterBuilder = func(config *Config, client SlackClient) apiSpecificAdapter {
 			return &rtmAPIAdapter{
 				config:        adapter.config,
 				client:        adapter.client,
-				handlePayload: fnc,
+				handlePayload: adapter.subscriptions.rtmPayloadHandler(fnc),
+				outgoing:      adapter.outgoing,
+				connStats:     adapter.connStats,
 			}
 		}
 	}
@@ -128,13 +163,28 @@ func WithRTMPayloadHandler(fnc func(context.Context, *Config, rtmapi.DecodedPayl
 type Adapter struct {
 	config                    *Config
 	client                    SlackClient
+	web                       golack.WebClient
 	apiSpecificAdapterBuilder func(config *Config, client SlackClient) apiSpecificAdapter
+	outgoing                  *inFlight
+	serveMux                  *serveMuxMount
+	httpClient                *http.Client
+	connStats                 *connectionStats
+	reactions                 ReactionClient
+	destinations              *destinationResolver
+	subscriptions             *subscriptions
+	modals                    *modalRegistry
+	teams                     *teamRegistry
+	threads                   *threadRegistry
 }
 
+var _ sarah.ConnectionStatsReporter = (*Adapter)(nil)
+
 // NewAdapter creates a new Adapter with the given *Config and zero or more AdapterOption values.
 func NewAdapter(config *Config, options ...AdapterOption) (*Adapter, error) {
 	adapter := &Adapter{
-		config: config,
+		config:        config,
+		outgoing:      &inFlight{},
+		subscriptions: &subscriptions{},
 	}
 
 	for _, opt := range options {
@@ -144,7 +194,7 @@ func NewAdapter(config *Config, options ...AdapterOption) (*Adapter, error) {
 	// See if a client is set by WithSlackClient option.
 	// If not, use golack with the given configuration.
 	if adapter.client == nil {
-		if config.Token == "" {
+		if config.Token == "" && config.TokenProvider == nil {
 			return nil, errors.New("Slack client must be provided with WithSlackClient option or must be configurable with given *Config")
 		}
 
@@ -156,7 +206,56 @@ func NewAdapter(config *Config, options ...AdapterOption) (*Adapter, error) {
 			golackConfig.RequestTimeout = config.RequestTimeout
 		}
 
-		adapter.client = golack.New(golackConfig)
+		httpClient := adapter.httpClient
+		if httpClient == nil && config.HTTPClient != nil {
+			var err error
+			httpClient, err = sarah.NewHTTPClient(config.HTTPClient)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build HTTP client: %w", err)
+			}
+		}
+
+		var golackOptions []golack.Option
+		if config.TokenProvider != nil {
+			golackOptions = append(golackOptions, golack.WithWebClient(&tokenWebClient{
+				provider:       config.TokenProvider,
+				requestTimeout: golackConfig.RequestTimeout,
+				httpClient:     httpClient,
+			}))
+		} else if httpClient != nil {
+			webConfig := webapi.NewConfig()
+			webConfig.Token = golackConfig.Token
+			webConfig.RequestTimeout = golackConfig.RequestTimeout
+			golackOptions = append(golackOptions, golack.WithWebClient(webapi.NewClient(webConfig, webapi.WithHTTPClient(httpClient))))
+		}
+
+		g := golack.New(golackConfig, golackOptions...)
+		adapter.client = g
+		adapter.reactions = &webReactionClient{web: g.WebClient}
+		adapter.web = g.WebClient
+		if config.WebSocket != nil {
+			client, err := newDialingClient(g, config.WebSocket)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set up WebSocket dialer: %w", err)
+			}
+			adapter.client = client
+		}
+	}
+
+	if reactions, ok := adapter.client.(ReactionClient); ok {
+		adapter.reactions = reactions
+	}
+	if web, ok := adapter.client.(golack.WebClient); ok {
+		adapter.web = web
+	}
+	if adapter.web != nil {
+		adapter.destinations = newDestinationResolver(adapter.web, config.DestinationCacheTTL)
+	}
+
+	adapter.modals = newModalRegistry(config.ModalTTL)
+	adapter.teams = newTeamRegistry()
+	if adapter.threads == nil {
+		adapter.threads = newThreadRegistry(sarah.NewInMemoryKVStore())
 	}
 
 	if adapter.apiSpecificAdapterBuilder == nil {
@@ -166,6 +265,55 @@ func NewAdapter(config *Config, options ...AdapterOption) (*Adapter, error) {
 	return adapter, nil
 }
 
+// ConnectionStats returns the statistics of the RTM connection.
+// This returns nil when the Adapter works with Events API since there is no persistent connection.
+func (adapter *Adapter) ConnectionStats() *sarah.ConnectionStats {
+	if adapter.connStats == nil {
+		return nil
+	}
+	return adapter.connStats.snapshot()
+}
+
+// Client returns the SlackClient that the Adapter uses to communicate with Slack.
+// This is either the one given with WithSlackClient or the one NewAdapter builds from Config.
+// A plugin can share this instead of constructing its own client with duplicated credentials.
+func (adapter *Adapter) Client() SlackClient {
+	return adapter.client
+}
+
+// WebClient returns the golack.WebClient that the Adapter uses to call Slack Web API.
+// Commands that need Web API methods SlackClient does not cover -- e.g. users.list -- can call them with the shared client and its settings such as Config.HTTPClient.
+//
+//	var members webapi.APIResponse
+//	err := slackAdapter.WebClient().Get(ctx, "users.list", nil, &members)
+//
+// This returns nil when the SlackClient given with WithSlackClient does not implement golack.WebClient.
+func (adapter *Adapter) WebClient() golack.WebClient {
+	return adapter.web
+}
+
+// DirectMessageDestination returns the destination to send a direct message to the user with the given Input.SenderKey.
+// Slack delivers a message posted to a user ID to the direct message channel between the bot and the user.
+func (adapter *Adapter) DirectMessageDestination(_ context.Context, senderKey string) (sarah.OutputDestination, error) {
+	i := strings.LastIndex(senderKey, "|")
+	if i < 0 || i == len(senderKey)-1 {
+		return nil, fmt.Errorf("malformed sender key: %s", senderKey)
+	}
+	return event.ChannelID(senderKey[i+1:]), nil
+}
+
+// resolveDestination resolves the given Destination to a channel ID.
+func (adapter *Adapter) resolveDestination(ctx context.Context, destination Destination) (event.ChannelID, error) {
+	if adapter.destinations == nil {
+		// Without Web API access, only a channel ID can be resolved.
+		if channelID, ok := destination.channelID(); ok {
+			return channelID, nil
+		}
+		return "", fmt.Errorf("%T does not support Web API calls to resolve the destination", adapter.client)
+	}
+	return adapter.destinations.resolve(ctx, destination)
+}
+
 // BotType returns a designated BotType for Slack integration.
 func (adapter *Adapter) BotType() sarah.BotType {
 	return SLACK
@@ -179,33 +327,47 @@ func (adapter *Adapter) BotType() sarah.BotType {
 // Upon a critical situation such as consecutive reconnection trial failures, such a state is notified to Sarah via the 3rd argument function -- notifyErr.
 // Sarah cancels this Bot/Adapter and cleans up related resources when BotNonContinuableError is given to this function.
 func (adapter *Adapter) Run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
+	if adapter.config.OverloadMessage != "" {
+		enqueueInput = sarah.ReplyOnBlockedInput(enqueueInput, adapter.config.OverloadReplyInterval, func(input sarah.Input, _ *sarah.BlockedInputError) {
+			go adapter.SendMessage(ctx, sarah.NewOutputMessage(input.ReplyTo(), adapter.config.OverloadMessage))
+		})
+	}
+
 	adapter.apiSpecificAdapterBuilder(adapter.config, adapter.client).run(ctx, enqueueInput, notifyErr)
 }
 
-// nonBlockSignal tries to send a signal to the given channel in a non-blocking manner.
-// If no goroutine is listening to the channel or one is working on a task triggered by the previous signal,
-// this method skips signaling rather than blocking till one is ready to read the channel.
+// SendMessage lets sarah.Bot send a message to Slack.
 //
-// For a signaling purposes, an empty struct{} should be used.
-// http://peter.bourgon.org/go-in-production/
-//  "Use struct{} as a sentinel value, rather than bool or interface{}. For example, (snip) a signal channel is chan struct{}.
-//  It unambiguously signals an explicit lack of information."
-func nonBlockSignal(id string, target chan<- struct{}) {
-	select {
-	case target <- struct{}{}:
-		// O.K
+// When the given context is already canceled -- e.g. a command finishes its execution while the Bot is shutting down --
+// the message is still sent within Config.DrainTimeout so the pending response is not lost.
+func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
+	if adapter.outgoing != nil {
+		adapter.outgoing.begin()
+		defer adapter.outgoing.end()
+	}
 
-	default:
-		// couldn't send because no goroutine is listening to the  channel or one is currently busy.
-		logger.Debugf("Not sending signal to channel: %s", id)
+	if ctx.Err() != nil && adapter.config.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), adapter.config.DrainTimeout)
+		defer cancel()
+	}
 
+	if destination, ok := output.Destination().(Destination); ok {
+		channelID, err := adapter.resolveDestination(ctx, destination)
+		if err != nil {
+			sarah.LoggerFromContext(ctx).Errorf("Failed to resolve destination %s: %+v", destination, err)
+			return
+		}
+		output = sarah.NewOutputMessage(channelID, output.Content())
 	}
-}
 
-// SendMessage lets sarah.Bot send a message to Slack.
-func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
+	// Call Web API with the token of the workspace where the destination belongs.
+	ctx = adapter.teams.teamContext(ctx, output.Destination())
+
+	content, splittable := sarah.UnwrapUnsplitContent(output.Content())
+
 	var message *webapi.PostMessage
-	switch content := output.Content().(type) {
+	switch content := content.(type) {
 	case *webapi.PostMessage:
 		message = content
 
@@ -217,6 +379,50 @@ func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
 		}
 		message = webapi.NewPostMessage(channel, content)
 
+	case *sarah.RichContent:
+		channelID, ok := output.Destination().(event.ChannelID)
+		if !ok {
+			logger.Errorf("Destination is not instance of Channel. %#v.", output.Destination())
+			return
+		}
+		message = renderRichContent(channelID, content)
+
+	case *Reaction:
+		err := adapter.addReaction(ctx, content)
+		if err != nil {
+			sarah.LoggerFromContext(ctx).Errorf("Failed to add reaction %#v: %+v", content, err)
+		}
+		if content.Message == nil {
+			return
+		}
+		message = content.Message
+
+	case *StickyThread:
+		channelID, ok := output.Destination().(event.ChannelID)
+		if !ok {
+			logger.Errorf("Destination is not instance of Channel. %#v.", output.Destination())
+			return
+		}
+		err := adapter.postStickyThread(ctx, channelID, content)
+		if err != nil {
+			sarah.LoggerFromContext(ctx).Errorf("Failed to post sticky thread %s: %+v", content.Key, err)
+		}
+		return
+
+	case *HomeView:
+		err := adapter.PublishHomeView(ctx, content.UserID, content.View)
+		if err != nil {
+			sarah.LoggerFromContext(ctx).Errorf("Failed to publish home view for %s: %+v", content.UserID, err)
+		}
+		return
+
+	case *Modal:
+		err := adapter.openModal(ctx, content)
+		if err != nil {
+			sarah.LoggerFromContext(ctx).Errorf("Failed to open modal %#v: %+v", content.View, err)
+		}
+		return
+
 	case *sarah.CommandHelps:
 		channelID, ok := output.Destination().(event.ChannelID)
 		if !ok {
@@ -225,7 +431,7 @@ func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
 		}
 
 		var fields []*webapi.AttachmentField
-		for _, commandHelp := range *output.Content().(*sarah.CommandHelps) {
+		for _, commandHelp := range *content {
 			fields = append(fields, &webapi.AttachmentField{
 				Title: commandHelp.Identifier,
 				Value: commandHelp.Instruction,
@@ -247,15 +453,44 @@ func (adapter *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
 		return
 	}
 
-	resp, err := adapter.client.PostMessage(ctx, message)
-	if err != nil {
-		logger.Errorf("Something went wrong with Web API posting: %+v. %+v", err, message)
-		return
+	messages := []*webapi.PostMessage{message}
+	if splittable {
+		messages = splitPostMessage(message, adapter.config.MaxMessageLength)
 	}
 
-	if !resp.OK {
-		logger.Errorf("Failed to post message %#v: %s", message, resp.Error)
+	for _, message := range messages {
+		resp, err := adapter.client.PostMessage(ctx, message)
+		if err != nil {
+			sarah.LoggerFromContext(ctx).Errorf("Something went wrong with Web API posting: %+v. %+v", err, message)
+			return
+		}
+
+		if !resp.OK {
+			sarah.LoggerFromContext(ctx).Errorf("Failed to post message %#v: %s", message, resp.Error)
+			return
+		}
+	}
+}
+
+// splitPostMessage splits the given message into multiple messages when its text exceeds the given limit.
+// A message with blocks or attachments is not split since the text is only a fallback for notifications.
+func splitPostMessage(message *webapi.PostMessage, limit int) []*webapi.PostMessage {
+	if len(message.Blocks) > 0 || len(message.Attachments) > 0 {
+		return []*webapi.PostMessage{message}
 	}
+
+	chunks := sarah.SplitMessage(message.Text, limit)
+	if len(chunks) == 1 {
+		return []*webapi.PostMessage{message}
+	}
+
+	messages := make([]*webapi.PostMessage, len(chunks))
+	for i, chunk := range chunks {
+		copied := *message
+		copied.Text = chunk
+		messages[i] = &copied
+	}
+	return messages
 }
 
 // Input is a sarah.Input implementation that represents a received message.
@@ -267,8 +502,27 @@ type Input struct {
 	timestamp       *event.TimeStamp
 	threadTimeStamp *event.TimeStamp
 	channelID       event.ChannelID
+	userID          event.UserID
+	teamID          string
+	bot             bool
+	ack             func(error)
 }
 
+var usageStyle = &sarah.UsageStyle{
+	Code: func(s string) string {
+		return "`" + s + "`"
+	},
+	Heading: func(s string) string {
+		return "*" + s + "*"
+	},
+}
+
+var _ sarah.SourcedInput = (*Input)(nil)
+var _ sarah.BotAuthoredInput = (*Input)(nil)
+var _ sarah.AckableInput = (*Input)(nil)
+var _ sarah.NormalizableInput = (*Input)(nil)
+var _ sarah.UsageStyledInput = (*Input)(nil)
+
 // SenderKey returns the message sender's id.
 func (i *Input) SenderKey() string {
 	return i.senderKey
@@ -279,6 +533,16 @@ func (i *Input) Message() string {
 	return i.text
 }
 
+// SetMessage replaces the received text with the normalized one.
+func (i *Input) SetMessage(message string) {
+	i.text = message
+}
+
+// UsageStyle returns the style to decorate the instruction generated from sarah.ArgsSpec with Slack's mrkdwn.
+func (i *Input) UsageStyle() *sarah.UsageStyle {
+	return usageStyle
+}
+
 // SentAt returns when the message is sent.
 func (i *Input) SentAt() time.Time {
 	return i.timestamp.Time
@@ -289,7 +553,37 @@ func (i *Input) ReplyTo() sarah.OutputDestination {
 	return i.channelID
 }
 
-// EventToInput converts the given event payload to *Input.
+// UserID returns the Slack user who sent the message.
+func (i *Input) UserID() string {
+	return i.userID.String()
+}
+
+// TeamID returns the ID of the workspace where the message was sent.
+// This is empty when the message is received via RTM API.
+func (i *Input) TeamID() string {
+	return i.teamID
+}
+
+// IsBot tells if the message is sent by one of the bots declared in Config.BotUserIDs.
+func (i *Input) IsBot() bool {
+	return i.bot
+}
+
+// Ack tells the Events API adapter that the Input is processed so the adapter can respond to Slack.
+// This is meaningful only when Config.AckAfterProcessing is true and does nothing otherwise.
+func (i *Input) Ack(err error) {
+	if i.ack != nil {
+		i.ack(err)
+	}
+}
+
+// DirectMessage tells if the message is sent in a direct message channel, whose ID starts with "D."
+func (i *Input) DirectMessage() bool {
+	return strings.HasPrefix(i.channelID.String(), "D")
+}
+
+// EventToInput converts the given event payload to sarah.Input.
+// A message event is converted to *Input, and an app_home_opened event is converted to *AppHomeInput.
 func EventToInput(e interface{}) (sarah.Input, error) {
 	switch typed := e.(type) {
 	case *event.Message:
@@ -300,6 +594,7 @@ func EventToInput(e interface{}) (sarah.Input, error) {
 			timestamp:       typed.TimeStamp,
 			threadTimeStamp: typed.ThreadTimeStamp,
 			channelID:       typed.ChannelID,
+			userID:          typed.UserID,
 		}, nil
 
 	case *event.ChannelMessage:
@@ -310,6 +605,12 @@ func EventToInput(e interface{}) (sarah.Input, error) {
 			timestamp:       typed.TimeStamp,
 			threadTimeStamp: typed.ThreadTimeStamp,
 			channelID:       typed.ChannelID,
+			userID:          typed.UserID,
+		}, nil
+
+	case *event.AppHomeOpened:
+		return &AppHomeInput{
+			Event: typed,
 		}, nil
 
 	default:
@@ -317,6 +618,15 @@ func EventToInput(e interface{}) (sarah.Input, error) {
 	}
 }
 
+// flagBotInput flags the given Input as a bot-authored one when the sender is listed in Config.BotUserIDs.
+func flagBotInput(config *Config, input sarah.Input) {
+	typed, ok := input.(*Input)
+	if !ok {
+		return
+	}
+	typed.bot = slices.Contains(config.BotUserIDs, typed.userID.String())
+}
+
 // IsThreadMessage tells if the given message is sent in a thread.
 // If the message is sent in a thread, this is encouraged to reply in a thread.
 // NewResponse, therefore, defaults to send a response as a thread reply when the input is sent in a thread.
@@ -339,10 +649,16 @@ func IsThreadMessage(input *Input) bool {
 //
 // When an input is sent in a thread, this function defaults to send a response as a thread reply.
 // To explicitly change such behavior, use RespAsThreadReply or RespReplyBroadcast.
+// To add a reaction to the input instead of or in addition to the message, use RespWithReaction.
 func NewResponse(input sarah.Input, msg string, options ...RespOption) (*sarah.CommandResponse, error) {
 	typed, ok := input.(*Input)
 	if !ok {
-		return nil, fmt.Errorf("%T is not currently supported to automatically generate response", input)
+		// An Input that is not a message such as AppHomeInput or ViewInput is replied with a stand-alone message.
+		channelID, isChannel := input.ReplyTo().(event.ChannelID)
+		if !isChannel {
+			return nil, fmt.Errorf("%T is not currently supported to automatically generate response", input)
+		}
+		typed = &Input{channelID: channelID}
 	}
 
 	stash := &respOptions{
@@ -363,13 +679,28 @@ func NewResponse(input sarah.Input, msg string, options ...RespOption) (*sarah.C
 		WithParse(stash.parseMode).
 		WithUnfurlLinks(stash.unfurlLinks).
 		WithUnfurlMedia(stash.unfurlMedia)
-	if replyInThread(typed, stash) {
+	if replyInThread(typed, stash) && threadTimeStamp(typed) != nil {
 		postMessage.
 			WithThreadTimeStamp(threadTimeStamp(typed).String()).
 			WithReplyBroadcast(stash.replyBroadcast)
 	}
+	var content interface{} = postMessage
+	if stash.reaction != "" && typed.timestamp != nil {
+		reaction := &Reaction{
+			Channel:   typed.channelID,
+			TimeStamp: typed.timestamp.OriginalValue,
+			Name:      stash.reaction,
+		}
+		if msg != "" || len(stash.attachments) > 0 {
+			reaction.Message = postMessage
+		}
+		content = reaction
+	}
+	if stash.unsplit {
+		content = &sarah.UnsplitContent{Content: postMessage}
+	}
 	return &sarah.CommandResponse{
-		Content:     postMessage,
+		Content:     content,
 		UserContext: stash.userContext,
 	}, nil
 }
@@ -408,6 +739,13 @@ func RespReplyBroadcast(broadcast bool) RespOption {
 	}
 }
 
+// RespWithoutSplitting sends the response as-is even when its text exceeds Config.MaxMessageLength.
+func RespWithoutSplitting() RespOption {
+	return func(options *respOptions) {
+		options.unsplit = true
+	}
+}
+
 // RespWithAttachments adds given attachments to the response.
 func RespWithAttachments(attachments []*webapi.MessageAttachment) RespOption {
 	return func(options *respOptions) {
@@ -482,6 +820,8 @@ type respOptions struct {
 	unfurlMedia    bool
 	asThreadReply  *bool
 	replyBroadcast bool
+	unsplit        bool
+	reaction       string
 }
 
 type apiSpecificAdapter interface {
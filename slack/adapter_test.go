@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"github.com/oklahomer/go-kasumi/logger"
 	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2"
 	"github.com/oklahomer/golack/v2/event"
 	"github.com/oklahomer/golack/v2/eventsapi"
 	"github.com/oklahomer/golack/v2/rtmapi"
 	"github.com/oklahomer/golack/v2/webapi"
 	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"strconv"
@@ -78,6 +81,40 @@ func TestWithSlackClient(t *testing.T) {
 	}
 }
 
+func TestWithHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	opt := WithHTTPClient(httpClient)
+	adapter := &Adapter{}
+
+	opt(adapter)
+
+	if adapter.httpClient != httpClient {
+		t.Fatal("Given http.Client is not set.")
+	}
+}
+
+func TestWithServeMux(t *testing.T) {
+	mux := http.NewServeMux()
+	opt := WithServeMux(mux)
+	adapter := &Adapter{}
+
+	opt(adapter)
+
+	if adapter.serveMux == nil || adapter.serveMux.mux != mux {
+		t.Fatal("Given ServeMux is not set.")
+	}
+
+	WithEventsPayloadHandler(DefaultEventsPayloadHandler)(adapter)
+	built, ok := adapter.apiSpecificAdapterBuilder(nil, nil).(*eventsAPIAdapter)
+	if !ok {
+		t.Fatal("eventsAPIAdapter is not built.")
+	}
+
+	if built.serveMux != adapter.serveMux {
+		t.Error("Given ServeMux is not passed to eventsAPIAdapter.")
+	}
+}
+
 func TestWithRTMPayloadHandler(t *testing.T) {
 	fnc := func(_ context.Context, _ *Config, _ rtmapi.DecodedPayload, _ func(sarah.Input) error) {}
 	opt := WithRTMPayloadHandler(fnc)
@@ -131,6 +168,47 @@ func TestNewAdapter(t *testing.T) {
 		}
 	})
 
+	t.Run("With HTTP client configuration", func(t *testing.T) {
+		config := &Config{
+			Token: "dummy",
+			HTTPClient: &sarah.HTTPClientConfig{
+				ProxyURL: "http://proxy.example.com:8080",
+			},
+		}
+		adapter, err := NewAdapter(config, WithEventsPayloadHandler(DefaultEventsPayloadHandler))
+
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		g, ok := adapter.client.(*golack.Golack)
+		if !ok {
+			t.Fatalf("Unexpected client is set: %T.", adapter.client)
+		}
+
+		if _, ok := g.WebClient.(*webapi.Client); !ok {
+			t.Errorf("Unexpected WebClient is set: %T.", g.WebClient)
+		}
+	})
+
+	t.Run("Invalid HTTP client configuration", func(t *testing.T) {
+		config := &Config{
+			Token: "dummy",
+			HTTPClient: &sarah.HTTPClientConfig{
+				ProxyURL: "://invalid",
+			},
+		}
+		adapter, err := NewAdapter(config, WithEventsPayloadHandler(DefaultEventsPayloadHandler))
+
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+
+		if adapter != nil {
+			t.Fatal("Adapter should not be returned.")
+		}
+	})
+
 	t.Run("Missing config or SlackClient", func(t *testing.T) {
 		config := &Config{}
 		adapter, err := NewAdapter(config, WithRTMPayloadHandler(DefaultRTMPayloadHandler))
@@ -184,6 +262,78 @@ func TestNewAdapter(t *testing.T) {
 	})
 }
 
+func TestAdapter_Client(t *testing.T) {
+	client := &DummyClient{}
+	adapter, _ := NewAdapter(&Config{}, WithSlackClient(client), WithRTMPayloadHandler(DefaultRTMPayloadHandler))
+	if adapter.Client() != client {
+		t.Errorf("Unexpected SlackClient is returned: %#v.", adapter.Client())
+	}
+
+	adapter, _ = NewAdapter(&Config{Token: "dummy"}, WithRTMPayloadHandler(DefaultRTMPayloadHandler))
+	if _, ok := adapter.Client().(*golack.Golack); !ok {
+		t.Errorf("Unexpected SlackClient is returned: %T.", adapter.Client())
+	}
+}
+
+func TestAdapter_WebClient(t *testing.T) {
+	adapter, _ := NewAdapter(&Config{Token: "dummy"}, WithRTMPayloadHandler(DefaultRTMPayloadHandler))
+	g := adapter.Client().(*golack.Golack)
+	if adapter.WebClient() != g.WebClient {
+		t.Errorf("Unexpected WebClient is returned: %#v.", adapter.WebClient())
+	}
+
+	adapter, _ = NewAdapter(&Config{}, WithSlackClient(&DummyClient{}), WithRTMPayloadHandler(DefaultRTMPayloadHandler))
+	if adapter.WebClient() != nil {
+		t.Errorf("Nil should be returned when SlackClient does not implement golack.WebClient: %#v.", adapter.WebClient())
+	}
+
+	client := &struct {
+		*DummyClient
+		*DummyWebClient
+	}{
+		DummyClient:    &DummyClient{},
+		DummyWebClient: &DummyWebClient{},
+	}
+	adapter, _ = NewAdapter(&Config{}, WithSlackClient(client), WithRTMPayloadHandler(DefaultRTMPayloadHandler))
+	if adapter.WebClient() == nil {
+		t.Error("SlackClient implementing golack.WebClient should be returned.")
+	}
+	if adapter.destinations == nil {
+		t.Error("Destination resolver should be set with the WebClient.")
+	}
+}
+
+func TestAdapter_ConnectionStats(t *testing.T) {
+	adapter, _ := NewAdapter(&Config{}, WithSlackClient(&DummyClient{}), WithRTMPayloadHandler(DefaultRTMPayloadHandler))
+	if adapter.ConnectionStats() == nil {
+		t.Error("Stats must be returned for RTM API.")
+	}
+
+	adapter, _ = NewAdapter(&Config{}, WithSlackClient(&DummyClient{}), WithEventsPayloadHandler(DefaultEventsPayloadHandler))
+	if adapter.ConnectionStats() != nil {
+		t.Error("Nil must be returned for Events API.")
+	}
+}
+
+func TestAdapter_DirectMessageDestination(t *testing.T) {
+	adapter := &Adapter{}
+
+	dest, err := adapter.DirectMessageDestination(context.TODO(), "C123|U456")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if dest != event.ChannelID("U456") {
+		t.Errorf("Unexpected destination is returned: %#v.", dest)
+	}
+
+	for _, senderKey := range []string{"", "C123", "C123|"} {
+		_, err = adapter.DirectMessageDestination(context.TODO(), senderKey)
+		if err == nil {
+			t.Errorf("Expected error is not returned for %s.", senderKey)
+		}
+	}
+}
+
 func TestAdapter_BotType(t *testing.T) {
 	adapter := &Adapter{}
 
@@ -195,6 +345,7 @@ func TestAdapter_BotType(t *testing.T) {
 func TestAdapter_Run(t *testing.T) {
 	called := false
 	adapter := &Adapter{
+		config: NewConfig(),
 		apiSpecificAdapterBuilder: func(_ *Config, _ SlackClient) apiSpecificAdapter {
 			return DummyApiSpecificAdapter{
 				RunFunc: func(_ context.Context, _ func(sarah.Input) error, _ func(error)) {
@@ -213,6 +364,89 @@ func TestAdapter_Run(t *testing.T) {
 	}
 }
 
+func TestAdapter_Run_WithServeMux(t *testing.T) {
+	config := NewConfig()
+	config.Token = "dummy"
+	config.AppSecret = "secret"
+	config.EventsPath = "/slack/events"
+	config.InteractionsPath = "/slack/interactions"
+	mux := http.NewServeMux()
+	adapter, err := NewAdapter(config, WithEventsPayloadHandler(DefaultEventsPayloadHandler), WithServeMux(mux))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	// The Adapter runs again on the same ServeMux when the Bot is restarted.
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			adapter.Run(ctx, func(_ sarah.Input) error { return nil }, func(_ error) {})
+		}()
+
+		// Wait till the handler is mounted.
+		time.Sleep(10 * time.Millisecond)
+		req := signedRequest("secret", time.Now(), `{"type":"url_verification","challenge":"abc"}`)
+		req.URL.Path = "/slack/events"
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK || recorder.Body.String() != "abc" {
+			t.Errorf("Unexpected response is returned on run #%d: %d %s.", i+1, recorder.Code, recorder.Body.String())
+		}
+
+		cancel()
+		select {
+		case <-finished:
+			// O.K.
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Fatalf("Context cancellation is not propagated on run #%d.", i+1)
+
+		}
+	}
+}
+
+func TestAdapter_Run_WithOverloadMessage(t *testing.T) {
+	config := NewConfig()
+	config.OverloadMessage = "I'm too busy. Please try again later."
+	sent := make(chan *webapi.PostMessage, 1)
+	adapter := &Adapter{
+		config: config,
+		client: &DummyClient{
+			PostMessageFunc: func(_ context.Context, message *webapi.PostMessage) (*webapi.APIResponse, error) {
+				sent <- message
+				return &webapi.APIResponse{OK: true}, nil
+			},
+		},
+		apiSpecificAdapterBuilder: func(_ *Config, _ SlackClient) apiSpecificAdapter {
+			return DummyApiSpecificAdapter{
+				RunFunc: func(_ context.Context, enqueueInput func(sarah.Input) error, _ func(error)) {
+					_ = enqueueInput(&Input{channelID: "C123"})
+				},
+			}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	adapter.Run(ctx, func(_ sarah.Input) error { return sarah.NewBlockedInputError(1) }, func(err error) {})
+
+	select {
+	case message := <-sent:
+		if message.Text != config.OverloadMessage {
+			t.Errorf("Unexpected message is sent: %s.", message.Text)
+		}
+		if message.ChannelID != "C123" {
+			t.Errorf("Unexpected channel is given: %s.", message.ChannelID)
+		}
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Error("Overload message is not sent.")
+
+	}
+}
+
 func TestAdapter_SendMessage(t *testing.T) {
 	t.Run("Regular message", func(t *testing.T) {
 		tests := []struct {
@@ -247,6 +481,7 @@ func TestAdapter_SendMessage(t *testing.T) {
 			t.Run(strconv.Itoa(i), func(t *testing.T) {
 				called := false
 				adapter := &Adapter{
+					config: NewConfig(),
 					client: &DummyClient{
 						PostMessageFunc: func(_ context.Context, _ *webapi.PostMessage) (*webapi.APIResponse, error) {
 							called = true
@@ -269,6 +504,7 @@ func TestAdapter_SendMessage(t *testing.T) {
 	t.Run("String message", func(t *testing.T) {
 		called := false
 		adapter := &Adapter{
+			config: NewConfig(),
 			client: &DummyClient{
 				PostMessageFunc: func(_ context.Context, _ *webapi.PostMessage) (*webapi.APIResponse, error) {
 					called = true
@@ -290,6 +526,7 @@ func TestAdapter_SendMessage(t *testing.T) {
 	t.Run("Help command", func(t *testing.T) {
 		called := false
 		adapter := &Adapter{
+			config: NewConfig(),
 			client: &DummyClient{
 				PostMessageFunc: func(_ context.Context, _ *webapi.PostMessage) (*webapi.APIResponse, error) {
 					called = true
@@ -316,6 +553,89 @@ func TestAdapter_SendMessage(t *testing.T) {
 			t.Fatal("Client.PostMessage is not called.")
 		}
 	})
+
+	t.Run("Rich content", func(t *testing.T) {
+		var posted *webapi.PostMessage
+		adapter := &Adapter{
+			config: NewConfig(),
+			client: &DummyClient{
+				PostMessageFunc: func(_ context.Context, message *webapi.PostMessage) (*webapi.APIResponse, error) {
+					posted = message
+					return &webapi.APIResponse{OK: true}, nil
+				},
+			},
+		}
+
+		content := &sarah.RichContent{
+			Text:   "summary",
+			Blocks: []sarah.ContentBlock{&sarah.DividerBlock{}},
+		}
+
+		adapter.SendMessage(context.TODO(), sarah.NewOutputMessage("invalidID", content))
+		if posted != nil {
+			t.Fatal("Invalid output reached Client.PostMessage.")
+		}
+
+		adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(event.ChannelID("test"), content))
+		if posted == nil {
+			t.Fatal("Client.PostMessage is not called.")
+		}
+
+		if posted.Text != "summary" || len(posted.Blocks) != 1 {
+			t.Errorf("Unexpected message is posted: %#v.", posted)
+		}
+	})
+
+	t.Run("Long message", func(t *testing.T) {
+		var posted []*webapi.PostMessage
+		config := NewConfig()
+		config.MaxMessageLength = 10
+		adapter := &Adapter{
+			config: config,
+			client: &DummyClient{
+				PostMessageFunc: func(_ context.Context, message *webapi.PostMessage) (*webapi.APIResponse, error) {
+					posted = append(posted, message)
+					return &webapi.APIResponse{OK: true}, nil
+				},
+			},
+		}
+
+		message := webapi.NewPostMessage("C123", "line1\nline2\nline3").WithThreadTimeStamp("1355517523.000005")
+		adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(event.ChannelID("C123"), message))
+
+		if len(posted) != 3 {
+			t.Fatalf("Unexpected number of messages are posted: %d.", len(posted))
+		}
+
+		for i, p := range posted {
+			if p.Text != fmt.Sprintf("line%d", i+1) {
+				t.Errorf("Unexpected text is posted: %s.", p.Text)
+			}
+
+			if p.ThreadTimeStamp != "1355517523.000005" {
+				t.Errorf("Thread timestamp is not kept: %s.", p.ThreadTimeStamp)
+			}
+		}
+
+		posted = nil
+		adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(event.ChannelID("C123"), &sarah.UnsplitContent{Content: message}))
+
+		if len(posted) != 1 || posted[0] != message {
+			t.Errorf("Unsplit content should be sent as-is: %#v.", posted)
+		}
+	})
+}
+
+func Test_splitPostMessage(t *testing.T) {
+	message := webapi.NewPostMessage("C123", "line1\nline2")
+	if len(splitPostMessage(message, 0)) != 1 {
+		t.Error("Message should not be split when the limit is disabled.")
+	}
+
+	message.WithAttachments([]*webapi.MessageAttachment{{Text: "attachment"}})
+	if len(splitPostMessage(message, 5)) != 1 {
+		t.Error("Message with attachments should not be split.")
+	}
 }
 
 type DummyInput struct {
@@ -598,6 +918,17 @@ func TestRespWithUnfurlMedia(t *testing.T) {
 	}
 }
 
+func TestRespWithoutSplitting(t *testing.T) {
+	options := &respOptions{}
+	opt := RespWithoutSplitting()
+
+	opt(options)
+
+	if !options.unsplit {
+		t.Error("Option is not set.")
+	}
+}
+
 func TestIsThreadMessage(t *testing.T) {
 	now := time.Now()
 	ts := &event.TimeStamp{
@@ -648,16 +979,92 @@ func TestIsThreadMessage(t *testing.T) {
 	}
 }
 
-func Test_nonBlockSignal(t *testing.T) {
-	// Prepare a channel with a buffer of 1.
-	target := make(chan struct{}, 1)
-	defer close(target)
+func TestInput_UserID(t *testing.T) {
+	input, err := EventToInput(&event.Message{
+		ChannelID: "C123",
+		UserID:    "U456",
+		TimeStamp: &event.TimeStamp{},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if input.(*Input).UserID() != "U456" {
+		t.Errorf("Unexpected user ID is returned: %s.", input.(*Input).UserID())
+	}
+}
+
+func TestInput_SetMessage(t *testing.T) {
+	input := &Input{text: "ｔｅｘｔ"}
+	input.SetMessage("text")
+
+	if input.Message() != "text" {
+		t.Errorf("Message is not replaced: %s.", input.Message())
+	}
+}
+
+func TestInput_UsageStyle(t *testing.T) {
+	style := (&Input{}).UsageStyle()
+
+	if style.Code(".echo") != "`.echo`" {
+		t.Errorf("Unexpected code is returned: %s.", style.Code(".echo"))
+	}
+	if style.Heading("Usage:") != "*Usage:*" {
+		t.Errorf("Unexpected heading is returned: %s.", style.Heading("Usage:"))
+	}
+}
+
+func TestInput_Ack(t *testing.T) {
+	// Does not panic without binding.
+	(&Input{}).Ack(nil)
 
-	// Send twice. This exceed the target channel's cap, but the second call should not block.
-	nonBlockSignal("DUMMY ID", target)
-	nonBlockSignal("DUMMY ID", target)
+	var given error
+	err := errors.New("dummy")
+	input := &Input{
+		ack: func(e error) {
+			given = e
+		},
+	}
+	input.Ack(err)
 
-	if len(target) != 1 {
-		t.Errorf("The target channel should have exactly one signal: %d", len(target))
+	if given != err {
+		t.Errorf("Unexpected error is given: %#v.", given)
 	}
 }
+
+func TestInput_DirectMessage(t *testing.T) {
+	testSets := []struct {
+		channelID event.ChannelID
+		expected  bool
+	}{
+		{channelID: "D123", expected: true},
+		{channelID: "C123", expected: false},
+		{channelID: "G123", expected: false},
+	}
+
+	for i, tt := range testSets {
+		input := &Input{channelID: tt.channelID}
+		if input.DirectMessage() != tt.expected {
+			t.Errorf("Unexpected result on test #%d.", i+1)
+		}
+	}
+}
+
+func Test_flagBotInput(t *testing.T) {
+	config := &Config{BotUserIDs: []string{"UBOT"}}
+
+	input := &Input{userID: "UBOT"}
+	flagBotInput(config, input)
+	if !input.IsBot() {
+		t.Error("Input from the listed bot should be flagged.")
+	}
+
+	input = &Input{userID: "UHUMAN"}
+	flagBotInput(config, input)
+	if input.IsBot() {
+		t.Error("Input from a human should not be flagged.")
+	}
+
+	// Other Input implementations are simply ignored.
+	flagBotInput(config, sarah.NewHelpInput(&Input{userID: "UBOT", timestamp: &event.TimeStamp{}}))
+}
@@ -2,6 +2,7 @@ package slack
 
 import (
 	"github.com/oklahomer/go-kasumi/retry"
+	"github.com/oklahomer/go-sarah/v4"
 	"time"
 )
 
@@ -10,12 +11,63 @@ type Config struct {
 	// Token declares the API token to integrate with Gitter.
 	Token string `json:"token" yaml:"token"`
 
+	// TokenProvider provides the token per workspace instead of Token.
+	// Set this to serve multiple workspaces the Slack app is installed to, or to use a token that rotates.
+	// When this is nil, Token is used for all Web API calls. This can not be set via json.Unmarshal or yaml.Unmarshal.
+	TokenProvider TokenProvider `json:"-" yaml:"-"`
+
 	// AppSecret declares the application secret issued by Slack.
 	AppSecret string `json:"app_secret" yaml:"app_secret"`
 
 	// ListenPort declares the port number that receives requests from Slack.
 	ListenPort int `json:"listen_port" yaml:"listen_port"`
 
+	// EventsPath declares the URL path that receives Events API requests.
+	EventsPath string `json:"events_path" yaml:"events_path"`
+
+	// InteractionsPath declares the URL path that receives interaction payloads such as a button click and a modal submission.
+	// Set the URL with this path as the Request URL of Interactivity in the Slack app configuration.
+	// When this is empty, interactions are not received. This is only effective with Events API; RTM API does not support interactivity.
+	InteractionsPath string `json:"interactions_path" yaml:"interactions_path"`
+
+	// ModalTTL declares how long a modal opened with NewModalResponse is associated with the conversation of the Input that opened it.
+	// A modal submitted after this duration is passed as a ViewInput that is not a part of the conversation.
+	ModalTTL time.Duration `json:"modal_ttl" yaml:"modal_ttl"`
+
+	// TLSCertFile declares the path to the certificate file to serve Events API endpoint over HTTPS.
+	// The server runs over HTTPS only when TLSCertFile and TLSKeyFile are both set.
+	TLSCertFile string `json:"tls_cert_file" yaml:"tls_cert_file"`
+
+	// TLSKeyFile declares the path to the private key file that corresponds to TLSCertFile.
+	TLSKeyFile string `json:"tls_key_file" yaml:"tls_key_file"`
+
+	// ReadTimeout declares the maximum duration for the Events API server to read an entire request.
+	ReadTimeout time.Duration `json:"read_timeout" yaml:"read_timeout"`
+
+	// WriteTimeout declares the maximum duration for the Events API server to write a response.
+	WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout"`
+
+	// RequestTimestampTolerance declares the acceptable gap between the current time and the timestamp of an incoming Events API request.
+	// A request with an older or a newer timestamp is rejected to prevent a replay attack. Zero value disables this check.
+	RequestTimestampTolerance time.Duration `json:"request_timestamp_tolerance" yaml:"request_timestamp_tolerance"`
+
+	// EventDedupeWindow declares how long a received event_id is remembered to ignore the redelivery of the same event.
+	EventDedupeWindow time.Duration `json:"event_dedupe_window" yaml:"event_dedupe_window"`
+
+	// AckAfterProcessing declares whether to hold the response to an Events API request until the received Input is processed.
+	// By default, the request is acknowledged as soon as it is received, so the Input is lost when the process crashes before the processing completes.
+	// When this is true, the request is responded with a 5xx status code if the processing fails or does not complete in AckTimeout,
+	// and Slack redelivers the event. The redelivered event is processed again unless the previous delivery is processed successfully.
+	AckAfterProcessing bool `json:"ack_after_processing" yaml:"ack_after_processing"`
+
+	// AckTimeout declares how long to wait for the Input to be processed when AckAfterProcessing is true.
+	// Slack considers a request failed when the response is not returned in 3 seconds, so this should be shorter than that.
+	AckTimeout time.Duration `json:"ack_timeout" yaml:"ack_timeout"`
+
+	// BotUserIDs declares the user IDs of the other bots in the workspace.
+	// Inputs from those users are flagged via Input.IsBot, so Sarah ignores them unless a Command accepts bot Inputs.
+	BotUserIDs []string `json:"bot_user_ids" yaml:"bot_user_ids"`
+
 	// HelpCommand declares the command string that is converted to sarah.HelpInput.
 	HelpCommand string `json:"help_command" yaml:"help_command"`
 
@@ -31,8 +83,55 @@ type Config struct {
 	// PingInterval declares the ping interval for RTM API interaction.
 	PingInterval time.Duration `json:"ping_interval" yaml:"ping_interval"`
 
+	// PongDeadline declares how long to wait for a response after a ping is sent for RTM API interaction.
+	PongDeadline time.Duration `json:"pong_deadline" yaml:"pong_deadline"`
+
+	// MaxMissedPings declares the number of consecutive missed pings to consider the RTM connection dead and reconnect.
+	// Zero value disables this check.
+	MaxMissedPings int `json:"max_missed_pings" yaml:"max_missed_pings"`
+
+	// ReconnectInterval declares the initial interval to wait before reconnecting to RTM API after a connection failure.
+	// The interval doubles on every consecutive failure up to MaxReconnectInterval.
+	ReconnectInterval time.Duration `json:"reconnect_interval" yaml:"reconnect_interval"`
+
+	// MaxReconnectInterval declares the upper limit of the interval to wait before reconnecting to RTM API.
+	MaxReconnectInterval time.Duration `json:"max_reconnect_interval" yaml:"max_reconnect_interval"`
+
+	// WebSocket declares the settings to establish a WebSocket connection for RTM API such as compression and a proxy server.
+	// When this is nil, golack's default dialer is used.
+	WebSocket *WebSocketConfig `json:"websocket" yaml:"websocket"`
+
 	// RetryPolicy declares how a retrial for an API call should behave.
 	RetryPolicy *retry.Policy `json:"retry_policy" yaml:"retry_policy"`
+
+	// Backoff declares an exponential backoff strategy for a retrial of an API call.
+	// When this is set, this is preferred over RetryPolicy.
+	Backoff *sarah.BackoffPolicy `json:"backoff" yaml:"backoff"`
+
+	// HTTPClient declares the settings of the HTTP client to call Slack Web API such as a proxy server and a CA bundle.
+	// When this is nil, http.DefaultClient is used.
+	HTTPClient *sarah.HTTPClientConfig `json:"http_client" yaml:"http_client"`
+
+	// DrainTimeout declares how long to wait for in-flight payloads and outgoing messages on shutdown.
+	// Zero value disables the drain phase so the connection is closed immediately.
+	DrainTimeout time.Duration `json:"drain_timeout" yaml:"drain_timeout"`
+
+	// OverloadMessage declares the message to reply when the user's input is dropped because the bot is too busy.
+	// When this is empty, the overflowing input is silently dropped.
+	OverloadMessage string `json:"overload_message" yaml:"overload_message"`
+
+	// OverloadReplyInterval declares the minimum interval between the replies of OverloadMessage
+	// so the overloaded bot does not make the situation worse by replying to every dropped input.
+	OverloadReplyInterval time.Duration `json:"overload_reply_interval" yaml:"overload_reply_interval"`
+
+	// DestinationCacheTTL declares how long the channel ID resolved from a Destination is cached.
+	// Zero value disables the cache so the Web API is called on every SendMessage with a Destination.
+	DestinationCacheTTL time.Duration `json:"destination_cache_ttl" yaml:"destination_cache_ttl"`
+
+	// MaxMessageLength declares the maximum number of characters in a text message.
+	// A longer text message is split into multiple messages; see sarah.SplitMessage for details.
+	// A message with blocks or attachments is sent as-is. Zero value disables the splitting.
+	MaxMessageLength int `json:"max_message_length" yaml:"max_message_length"`
 }
 
 // NewConfig creates and returns a new Config instance with default settings.
@@ -40,17 +139,35 @@ type Config struct {
 // Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to populate the blank value or override those default values.
 func NewConfig() *Config {
 	return &Config{
-		Token:            "",
-		AppSecret:        "",
-		ListenPort:       8080,
-		HelpCommand:      ".help",
-		AbortCommand:     ".abort",
-		SendingQueueSize: 100,
-		RequestTimeout:   3 * time.Second,
-		PingInterval:     30 * time.Second,
+		Token:                     "",
+		AppSecret:                 "",
+		ListenPort:                8080,
+		EventsPath:                "/",
+		InteractionsPath:          "",
+		ModalTTL:                  30 * time.Minute,
+		ReadTimeout:               10 * time.Second,
+		WriteTimeout:              10 * time.Second,
+		RequestTimestampTolerance: 5 * time.Minute,
+		EventDedupeWindow:         10 * time.Minute,
+		AckAfterProcessing:        false,
+		AckTimeout:                2500 * time.Millisecond,
+		HelpCommand:               ".help",
+		AbortCommand:              ".abort",
+		SendingQueueSize:          100,
+		RequestTimeout:            3 * time.Second,
+		PingInterval:              30 * time.Second,
+		PongDeadline:              10 * time.Second,
+		MaxMissedPings:            3,
+		ReconnectInterval:         1 * time.Second,
+		MaxReconnectInterval:      1 * time.Minute,
 		RetryPolicy: &retry.Policy{
 			Trial:    10,
 			Interval: 500 * time.Millisecond,
 		},
+		DestinationCacheTTL:   time.Hour,
+		OverloadMessage:       "",
+		OverloadReplyInterval: 30 * time.Second,
+		DrainTimeout:          5 * time.Second,
+		MaxMessageLength:      4000,
 	}
 }
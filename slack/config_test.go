@@ -17,6 +17,14 @@ func TestNewConfig(t *testing.T) {
 	if config.Token != "" {
 		t.Errorf("token must be empty at this point, but was %s.", config.Token)
 	}
+
+	if config.EventsPath != "/" {
+		t.Errorf("Unexpected default events path is set: %s.", config.EventsPath)
+	}
+
+	if config.DrainTimeout <= 0 {
+		t.Errorf("Default drain timeout must be set: %s.", config.DrainTimeout)
+	}
 }
 
 func TestConfigUnmarshalYaml(t *testing.T) {
@@ -0,0 +1,100 @@
+package slack
+
+import (
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+)
+
+// renderRichContent renders the given adapter-neutral content into Slack's Block Kit layout.
+// Since Slack does not have a header block in the supported version of Block Kit, a header is rendered as a bold section.
+func renderRichContent(channelID event.ChannelID, content *sarah.RichContent) *webapi.PostMessage {
+	var blocks []event.Block
+	for _, block := range content.Blocks {
+		switch b := block.(type) {
+		case *sarah.HeaderBlock:
+			blocks = append(blocks, event.NewSectionBlock(event.NewMarkdownTextCompositionObject(fmt.Sprintf("*%s*", b.Text))))
+
+		case *sarah.SectionBlock:
+			section := event.NewSectionBlock(event.NewMarkdownTextCompositionObject(b.Text))
+			if len(b.Fields) > 0 {
+				var fields []*event.TextCompositionObject
+				for _, field := range b.Fields {
+					fields = append(fields, event.NewMarkdownTextCompositionObject(field))
+				}
+				section.WithFields(fields)
+			}
+			blocks = append(blocks, section)
+
+		case *sarah.DividerBlock:
+			blocks = append(blocks, event.NewDividerBlock())
+
+		case *sarah.ImageBlock:
+			blocks = append(blocks, event.NewImageBlock(b.URL, b.AltText))
+
+		case *sarah.ButtonsBlock:
+			var elements []event.BlockElement
+			for _, button := range b.Buttons {
+				element := event.NewButtonBlockElement(event.NewPlainTextCompositionObject(button.Text), event.ActionID(button.Value)).
+					WithValue(button.Value)
+				if button.Style != sarah.ButtonDefault {
+					element.WithStyle(event.Style(button.Style))
+				}
+				elements = append(elements, element)
+			}
+			if len(elements) > 0 {
+				blocks = append(blocks, event.NewActionsBlock(elements))
+			}
+
+		}
+	}
+
+	for _, file := range content.Files {
+		label := file.Title
+		if label == "" {
+			label = file.Name
+		}
+		blocks = append(blocks, event.NewSectionBlock(event.NewMarkdownTextCompositionObject(fmt.Sprintf("<%s|%s>", file.URL, label))))
+	}
+
+	var attachments []*webapi.MessageAttachment
+	for _, attachment := range content.Attachments {
+		fields := []*webapi.AttachmentField{}
+		for _, field := range attachment.Fields {
+			fields = append(fields, &webapi.AttachmentField{
+				Title: field.Title,
+				Value: field.Value,
+				Short: field.Short,
+			})
+		}
+
+		fallback := attachment.Title
+		if fallback == "" {
+			fallback = attachment.Text
+		}
+		attachments = append(attachments, &webapi.MessageAttachment{
+			Fallback:  fallback,
+			Color:     attachment.Color,
+			Title:     attachment.Title,
+			TitleLink: attachment.TitleLink,
+			Text:      attachment.Text,
+			Fields:    fields,
+		})
+	}
+
+	// The text is shown in notifications when blocks are given, so fill it with a fallback rendering when empty.
+	text := content.Text
+	if text == "" {
+		text = content.PlainText()
+	}
+
+	message := webapi.NewPostMessage(channelID, text)
+	if len(blocks) > 0 {
+		message.WithBlocks(blocks)
+	}
+	if len(attachments) > 0 {
+		message.WithAttachments(attachments)
+	}
+	return message
+}
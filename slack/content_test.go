@@ -0,0 +1,124 @@
+package slack
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"testing"
+)
+
+func Test_renderRichContent(t *testing.T) {
+	content := &sarah.RichContent{
+		Text: "Deployment finished",
+		Blocks: []sarah.ContentBlock{
+			&sarah.HeaderBlock{Text: "Summary"},
+			&sarah.SectionBlock{Text: "All services are up.", Fields: []string{"api", "web"}},
+			&sarah.DividerBlock{},
+			&sarah.ImageBlock{URL: "https://example.com/graph.png", AltText: "Latency"},
+		},
+		Attachments: []*sarah.ContentAttachment{
+			{
+				Title:  "Release note",
+				Color:  "#36a64f",
+				Fields: []*sarah.ContentField{{Title: "Author", Value: "sarah", Short: true}},
+			},
+		},
+		Files: []*sarah.ContentFile{
+			{Name: "report.csv", URL: "https://example.com/report.csv"},
+		},
+	}
+
+	message := renderRichContent("C123", content)
+
+	if message.ChannelID != "C123" {
+		t.Errorf("Unexpected channel is set: %s.", message.ChannelID)
+	}
+
+	if message.Text != "Deployment finished" {
+		t.Errorf("Unexpected text is set: %s.", message.Text)
+	}
+
+	if len(message.Blocks) != 5 {
+		t.Fatalf("Unexpected number of blocks are set: %d.", len(message.Blocks))
+	}
+
+	header, ok := message.Blocks[0].(*event.SectionBlock)
+	if !ok || header.Text.Text != "*Summary*" {
+		t.Errorf("Header is not rendered as a bold section: %#v.", message.Blocks[0])
+	}
+
+	section := message.Blocks[1].(*event.SectionBlock)
+	if len(section.Fields) != 2 {
+		t.Errorf("Fields are not rendered: %#v.", section)
+	}
+
+	if _, ok := message.Blocks[2].(*event.DividerBlock); !ok {
+		t.Errorf("Divider is not rendered: %#v.", message.Blocks[2])
+	}
+
+	if image, ok := message.Blocks[3].(*event.ImageBlock); !ok || image.ImageURL != "https://example.com/graph.png" {
+		t.Errorf("Image is not rendered: %#v.", message.Blocks[3])
+	}
+
+	file := message.Blocks[4].(*event.SectionBlock)
+	if file.Text.Text != "<https://example.com/report.csv|report.csv>" {
+		t.Errorf("File is not rendered as a link: %s.", file.Text.Text)
+	}
+
+	if len(message.Attachments) != 1 {
+		t.Fatalf("Unexpected number of attachments are set: %d.", len(message.Attachments))
+	}
+
+	attachment := message.Attachments[0]
+	if attachment.Fallback != "Release note" || attachment.Color != "#36a64f" || len(attachment.Fields) != 1 {
+		t.Errorf("Unexpected attachment is rendered: %#v.", attachment)
+	}
+}
+
+func Test_renderRichContent_TextFallback(t *testing.T) {
+	content := &sarah.RichContent{
+		Blocks: []sarah.ContentBlock{&sarah.SectionBlock{Text: "Hello"}},
+	}
+
+	message := renderRichContent("C123", content)
+
+	if message.Text != "Hello" {
+		t.Errorf("Fallback text is not set: %s.", message.Text)
+	}
+
+	if message.Attachments != nil {
+		t.Errorf("Attachments should not be set: %#v.", message.Attachments)
+	}
+}
+
+func Test_renderRichContent_Buttons(t *testing.T) {
+	content := &sarah.RichContent{
+		Text: "Are you sure?",
+		Blocks: []sarah.ContentBlock{
+			&sarah.ButtonsBlock{Buttons: []*sarah.ContentButton{
+				{Text: "Yes", Value: "yes", Style: sarah.ButtonDanger},
+				{Text: "No", Value: "no"},
+			}},
+		},
+	}
+
+	message := renderRichContent("C123", content)
+
+	if len(message.Blocks) != 1 {
+		t.Fatalf("Unexpected number of blocks are set: %d.", len(message.Blocks))
+	}
+
+	actions, ok := message.Blocks[0].(*event.ActionsBlock)
+	if !ok || len(actions.Elements) != 2 {
+		t.Fatalf("Buttons are not rendered: %#v.", message.Blocks[0])
+	}
+
+	yes := actions.Elements[0].(*event.ButtonBlockElement)
+	if yes.Value != "yes" || yes.Style != event.StyleDanger {
+		t.Errorf("Unexpected button is rendered: %#v.", yes)
+	}
+
+	no := actions.Elements[1].(*event.ButtonBlockElement)
+	if no.Value != "no" || no.Style != "" {
+		t.Errorf("Unexpected button is rendered: %#v.", no)
+	}
+}
@@ -0,0 +1,297 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/golack/v2"
+	"github.com/oklahomer/golack/v2/event"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Destination is a human-readable sarah.OutputDestination that Adapter resolves to a channel ID on SendMessage.
+// Because this is a string type, a destination can be written in a configuration file and be returned by sarah.DestinatedConfig.
+//
+//	#general                 a public or private channel with the name of "general."
+//	usergroup:oncall         a group direct message with the members of the user group with the handle of "oncall."
+//	user:U0123456789         a direct message with the user with the given ID.
+//	email:alice@example.com  a direct message with the user with the given e-mail address.
+//	C0123456789              a channel with the given ID.
+//
+// The resolved channel IDs are cached for Config.DestinationCacheTTL, so the Web API is not called on every SendMessage.
+// The bot token must be granted the corresponding scopes such as channels:read, groups:read, usergroups:read, users:read.email, and im:write.
+type Destination string
+
+const (
+	channelNamePrefix = "#"
+	userGroupPrefix   = "usergroup:"
+	userIDPrefix      = "user:"
+	emailPrefix       = "email:"
+)
+
+// ChannelByName returns a Destination that is resolved to the public or private channel with the given name.
+func ChannelByName(name string) Destination {
+	return Destination(channelNamePrefix + strings.TrimPrefix(name, channelNamePrefix))
+}
+
+// UserGroup returns a Destination that is resolved to a group direct message with the members of the user group with the given handle.
+// Slack allows up to eight members in a group direct message, so use a channel to reach a larger user group.
+func UserGroup(handle string) Destination {
+	return Destination(userGroupPrefix + strings.TrimPrefix(handle, "@"))
+}
+
+// DirectMessage returns a Destination that is resolved to a direct message with the user with the given ID.
+func DirectMessage(userID string) Destination {
+	return Destination(userIDPrefix + userID)
+}
+
+// DirectMessageByEmail returns a Destination that is resolved to a direct message with the user with the given e-mail address.
+func DirectMessageByEmail(email string) Destination {
+	return Destination(emailPrefix + email)
+}
+
+// String returns the stringified form of the destination.
+func (d Destination) String() string {
+	return string(d)
+}
+
+// channelID returns the channel ID when the destination is given as a channel ID without any prefix.
+func (d Destination) channelID() (event.ChannelID, bool) {
+	value := string(d)
+	if value == "" {
+		return "", false
+	}
+	for _, prefix := range []string{channelNamePrefix, userGroupPrefix, userIDPrefix, emailPrefix} {
+		if strings.HasPrefix(value, prefix) {
+			return "", false
+		}
+	}
+	return event.ChannelID(value), true
+}
+
+type destinationCacheEntry struct {
+	channelID event.ChannelID
+	expiresAt time.Time
+}
+
+// destinationResolver resolves a Destination to a channel ID with Web API calls and caches the result.
+type destinationResolver struct {
+	web   golack.WebClient
+	ttl   time.Duration
+	cache map[Destination]*destinationCacheEntry
+	now   func() time.Time
+	mutex sync.Mutex
+}
+
+func newDestinationResolver(web golack.WebClient, ttl time.Duration) *destinationResolver {
+	return &destinationResolver{
+		web:   web,
+		ttl:   ttl,
+		cache: map[Destination]*destinationCacheEntry{},
+		now:   time.Now,
+	}
+}
+
+func (r *destinationResolver) resolve(ctx context.Context, destination Destination) (event.ChannelID, error) {
+	r.mutex.Lock()
+	entry, ok := r.cache[destination]
+	r.mutex.Unlock()
+	if ok && r.now().Before(entry.expiresAt) {
+		return entry.channelID, nil
+	}
+
+	channelID, err := r.lookup(ctx, destination)
+	if err != nil {
+		return "", err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cache[destination] = &destinationCacheEntry{
+		channelID: channelID,
+		expiresAt: r.now().Add(r.ttl),
+	}
+	return channelID, nil
+}
+
+func (r *destinationResolver) lookup(ctx context.Context, destination Destination) (event.ChannelID, error) {
+	if channelID, ok := destination.channelID(); ok {
+		return channelID, nil
+	}
+
+	value := string(destination)
+	switch {
+	case strings.HasPrefix(value, channelNamePrefix):
+		return r.channelByName(ctx, strings.TrimPrefix(value, channelNamePrefix))
+
+	case strings.HasPrefix(value, userGroupPrefix):
+		members, err := r.userGroupMembers(ctx, strings.TrimPrefix(value, userGroupPrefix))
+		if err != nil {
+			return "", err
+		}
+		return r.openConversation(ctx, members)
+
+	case strings.HasPrefix(value, userIDPrefix):
+		return r.openConversation(ctx, []string{strings.TrimPrefix(value, userIDPrefix)})
+
+	case strings.HasPrefix(value, emailPrefix):
+		userID, err := r.userByEmail(ctx, strings.TrimPrefix(value, emailPrefix))
+		if err != nil {
+			return "", err
+		}
+		return r.openConversation(ctx, []string{userID})
+
+	default:
+		return "", fmt.Errorf("empty destination is given")
+
+	}
+}
+
+type apiStatus struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func (e *apiStatus) err(method string) error {
+	if e.OK {
+		return nil
+	}
+	return fmt.Errorf("failed %s request: %s", method, e.Error)
+}
+
+func (r *destinationResolver) channelByName(ctx context.Context, name string) (event.ChannelID, error) {
+	cursor := ""
+	for {
+		params := url.Values{}
+		params.Set("types", "public_channel,private_channel")
+		params.Set("exclude_archived", "true")
+		params.Set("limit", "1000")
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		response := &struct {
+			apiStatus
+			Channels []*struct {
+				ID   event.ChannelID `json:"id"`
+				Name string          `json:"name"`
+			} `json:"channels"`
+			ResponseMetadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}{}
+		err := r.web.Get(ctx, "conversations.list", params, response)
+		if err != nil {
+			return "", err
+		}
+		if err := response.err("conversations.list"); err != nil {
+			return "", err
+		}
+
+		for _, channel := range response.Channels {
+			if channel.Name == name {
+				return channel.ID, nil
+			}
+		}
+
+		cursor = response.ResponseMetadata.NextCursor
+		if cursor == "" {
+			return "", fmt.Errorf("channel is not found: %s", name)
+		}
+	}
+}
+
+func (r *destinationResolver) userGroupMembers(ctx context.Context, handle string) ([]string, error) {
+	groups := &struct {
+		apiStatus
+		UserGroups []*struct {
+			ID     string `json:"id"`
+			Handle string `json:"handle"`
+		} `json:"usergroups"`
+	}{}
+	err := r.web.Get(ctx, "usergroups.list", url.Values{}, groups)
+	if err != nil {
+		return nil, err
+	}
+	if err := groups.err("usergroups.list"); err != nil {
+		return nil, err
+	}
+
+	groupID := ""
+	for _, group := range groups.UserGroups {
+		if group.Handle == handle {
+			groupID = group.ID
+			break
+		}
+	}
+	if groupID == "" {
+		return nil, fmt.Errorf("user group is not found: %s", handle)
+	}
+
+	params := url.Values{}
+	params.Set("usergroup", groupID)
+	members := &struct {
+		apiStatus
+		Users []string `json:"users"`
+	}{}
+	err = r.web.Get(ctx, "usergroups.users.list", params, members)
+	if err != nil {
+		return nil, err
+	}
+	if err := members.err("usergroups.users.list"); err != nil {
+		return nil, err
+	}
+	if len(members.Users) == 0 {
+		return nil, fmt.Errorf("user group has no member: %s", handle)
+	}
+
+	return members.Users, nil
+}
+
+func (r *destinationResolver) userByEmail(ctx context.Context, email string) (string, error) {
+	params := url.Values{}
+	params.Set("email", email)
+	response := &struct {
+		apiStatus
+		User *struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}{}
+	err := r.web.Get(ctx, "users.lookupByEmail", params, response)
+	if err != nil {
+		return "", err
+	}
+	if err := response.err("users.lookupByEmail"); err != nil {
+		return "", err
+	}
+	if response.User == nil {
+		return "", fmt.Errorf("user is not found: %s", email)
+	}
+
+	return response.User.ID, nil
+}
+
+func (r *destinationResolver) openConversation(ctx context.Context, userIDs []string) (event.ChannelID, error) {
+	params := url.Values{}
+	params.Set("users", strings.Join(userIDs, ","))
+	response := &struct {
+		apiStatus
+		Channel *struct {
+			ID event.ChannelID `json:"id"`
+		} `json:"channel"`
+	}{}
+	err := r.web.Post(ctx, "conversations.open", params, response)
+	if err != nil {
+		return "", err
+	}
+	if err := response.err("conversations.open"); err != nil {
+		return "", err
+	}
+	if response.Channel == nil {
+		return "", fmt.Errorf("no channel is returned for users: %s", params.Get("users"))
+	}
+
+	return response.Channel.ID, nil
+}
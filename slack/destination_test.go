@@ -0,0 +1,213 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// decodeResponse populates the given response with the given JSON payload as the Web API client does.
+func decodeResponse(t *testing.T, payload string, response interface{}) {
+	err := json.Unmarshal([]byte(payload), response)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+}
+
+func TestDestination_helpers(t *testing.T) {
+	testSets := []struct {
+		destination Destination
+		expected    string
+	}{
+		{destination: ChannelByName("#general"), expected: "#general"},
+		{destination: ChannelByName("general"), expected: "#general"},
+		{destination: UserGroup("@oncall"), expected: "usergroup:oncall"},
+		{destination: DirectMessage("U123"), expected: "user:U123"},
+		{destination: DirectMessageByEmail("alice@example.com"), expected: "email:alice@example.com"},
+	}
+
+	for i, tt := range testSets {
+		if tt.destination.String() != tt.expected {
+			t.Errorf("Unexpected destination is returned on test #%d: %s.", i+1, tt.destination)
+		}
+	}
+}
+
+func TestDestination_channelID(t *testing.T) {
+	if id, ok := Destination("C123").channelID(); !ok || id != "C123" {
+		t.Errorf("Unexpected channel ID is returned: %s.", id)
+	}
+
+	for _, destination := range []Destination{"", "#general", "user:U123", "usergroup:oncall", "email:alice@example.com"} {
+		if _, ok := destination.channelID(); ok {
+			t.Errorf("Channel ID should not be returned for %s.", destination)
+		}
+	}
+}
+
+func Test_destinationResolver_resolve(t *testing.T) {
+	called := map[string]int{}
+	web := &DummyWebClient{
+		GetFunc: func(_ context.Context, method string, params url.Values, response interface{}) error {
+			called[method]++
+			switch method {
+			case "conversations.list":
+				if params.Get("cursor") == "" {
+					decodeResponse(t, `{"ok": true, "channels": [{"id": "C1", "name": "random"}], "response_metadata": {"next_cursor": "next"}}`, response)
+					return nil
+				}
+				decodeResponse(t, `{"ok": true, "channels": [{"id": "C2", "name": "general"}]}`, response)
+
+			case "usergroups.list":
+				decodeResponse(t, `{"ok": true, "usergroups": [{"id": "S1", "handle": "oncall"}]}`, response)
+
+			case "usergroups.users.list":
+				if params.Get("usergroup") != "S1" {
+					t.Errorf("Unexpected user group is given: %s.", params.Get("usergroup"))
+				}
+				decodeResponse(t, `{"ok": true, "users": ["U1", "U2"]}`, response)
+
+			case "users.lookupByEmail":
+				decodeResponse(t, `{"ok": true, "user": {"id": "U3"}}`, response)
+
+			default:
+				t.Errorf("Unexpected method is called: %s.", method)
+
+			}
+			return nil
+		},
+		PostFunc: func(_ context.Context, method string, payload interface{}, response interface{}) error {
+			called[method]++
+			users := payload.(url.Values).Get("users")
+			decodeResponse(t, `{"ok": true, "channel": {"id": "D-`+users+`"}}`, response)
+			return nil
+		},
+	}
+
+	now := time.Now()
+	resolver := newDestinationResolver(web, time.Minute)
+	resolver.now = func() time.Time {
+		return now
+	}
+
+	testSets := []struct {
+		destination Destination
+		expected    event.ChannelID
+	}{
+		{destination: "C123", expected: "C123"},
+		{destination: "#general", expected: "C2"},
+		{destination: "usergroup:oncall", expected: "D-U1,U2"},
+		{destination: "user:U9", expected: "D-U9"},
+		{destination: "email:alice@example.com", expected: "D-U3"},
+	}
+
+	for i, tt := range testSets {
+		channelID, err := resolver.resolve(context.TODO(), tt.destination)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if channelID != tt.expected {
+			t.Errorf("Unexpected channel ID is returned on test #%d: %s.", i+1, channelID)
+		}
+	}
+
+	if called["conversations.list"] != 2 {
+		t.Errorf("Pagination is not handled: %d.", called["conversations.list"])
+	}
+
+	// Cached.
+	_, _ = resolver.resolve(context.TODO(), "#general")
+	if called["conversations.list"] != 2 {
+		t.Error("Cached channel ID is not used.")
+	}
+
+	// Expired.
+	now = now.Add(time.Minute)
+	_, _ = resolver.resolve(context.TODO(), "#general")
+	if called["conversations.list"] != 4 {
+		t.Error("Expired cache is used.")
+	}
+}
+
+func Test_destinationResolver_resolve_Error(t *testing.T) {
+	testSets := []struct {
+		destination Destination
+		getPayload  string
+		getErr      error
+		postPayload string
+	}{
+		{destination: ""},
+		{destination: "#general", getErr: errors.New("dummy")},
+		{destination: "#general", getPayload: `{"ok": false, "error": "missing_scope"}`},
+		{destination: "#general", getPayload: `{"ok": true, "channels": []}`},
+		{destination: "usergroup:oncall", getPayload: `{"ok": true, "usergroups": []}`},
+		{destination: "email:alice@example.com", getPayload: `{"ok": false, "error": "users_not_found"}`},
+		{destination: "user:U1", postPayload: `{"ok": false, "error": "user_not_found"}`},
+	}
+
+	for i, tt := range testSets {
+		web := &DummyWebClient{
+			GetFunc: func(_ context.Context, _ string, _ url.Values, response interface{}) error {
+				if tt.getErr != nil {
+					return tt.getErr
+				}
+				decodeResponse(t, tt.getPayload, response)
+				return nil
+			},
+			PostFunc: func(_ context.Context, _ string, _ interface{}, response interface{}) error {
+				decodeResponse(t, tt.postPayload, response)
+				return nil
+			},
+		}
+
+		resolver := newDestinationResolver(web, time.Minute)
+		if _, err := resolver.resolve(context.TODO(), tt.destination); err == nil {
+			t.Errorf("Expected error is not returned on test #%d.", i+1)
+		}
+		if len(resolver.cache) != 0 {
+			t.Errorf("Failure should not be cached on test #%d.", i+1)
+		}
+	}
+}
+
+func TestAdapter_SendMessage_Destination(t *testing.T) {
+	var posted *webapi.PostMessage
+	adapter := &Adapter{
+		config: NewConfig(),
+		client: &DummyClient{
+			PostMessageFunc: func(_ context.Context, message *webapi.PostMessage) (*webapi.APIResponse, error) {
+				posted = message
+				return &webapi.APIResponse{OK: true}, nil
+			},
+		},
+	}
+
+	// Only a channel ID is resolved without Web API access.
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(Destination("C123"), "Hello"))
+	if posted == nil || posted.ChannelID != "C123" {
+		t.Fatalf("Unexpected message is posted: %#v.", posted)
+	}
+
+	posted = nil
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(ChannelByName("general"), "Hello"))
+	if posted != nil {
+		t.Errorf("Message should not be posted: %#v.", posted)
+	}
+
+	adapter.destinations = newDestinationResolver(&DummyWebClient{
+		GetFunc: func(_ context.Context, _ string, _ url.Values, response interface{}) error {
+			decodeResponse(t, `{"ok": true, "channels": [{"id": "C2", "name": "general"}]}`, response)
+			return nil
+		},
+	}, time.Minute)
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(ChannelByName("general"), "Hello"))
+	if posted == nil || posted.ChannelID != "C2" {
+		t.Errorf("Unexpected message is posted: %#v.", posted)
+	}
+}
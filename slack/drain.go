@@ -0,0 +1,46 @@
+package slack
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// inFlight counts the tasks that are currently in progress so the caller can wait for their completion on shutdown.
+type inFlight struct {
+	count int64
+}
+
+func (f *inFlight) begin() {
+	atomic.AddInt64(&f.count, 1)
+}
+
+func (f *inFlight) end() {
+	atomic.AddInt64(&f.count, -1)
+}
+
+func (f *inFlight) current() int64 {
+	return atomic.LoadInt64(&f.count)
+}
+
+// wait blocks until all in-flight tasks complete or the timeout elapses, and returns the number of tasks that are still in progress.
+func (f *inFlight) wait(timeout time.Duration) int64 {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if remaining := f.current(); remaining == 0 {
+			return 0
+		}
+
+		select {
+		case <-deadline.C:
+			return f.current()
+
+		case <-ticker.C:
+			// Check again.
+
+		}
+	}
+}
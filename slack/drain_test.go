@@ -0,0 +1,64 @@
+package slack
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_inFlight_wait(t *testing.T) {
+	t.Run("Completed", func(t *testing.T) {
+		f := &inFlight{}
+		f.begin()
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			f.end()
+		}()
+
+		remaining := f.wait(time.Second)
+		if remaining != 0 {
+			t.Errorf("All tasks should be completed: %d.", remaining)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		f := &inFlight{}
+		f.begin()
+		f.begin()
+		f.end()
+
+		remaining := f.wait(20 * time.Millisecond)
+		if remaining != 1 {
+			t.Errorf("Unexpected number of remaining tasks is returned: %d.", remaining)
+		}
+	})
+}
+
+func Test_rtmAPIAdapter_drain(t *testing.T) {
+	outgoing := &inFlight{}
+	outgoing.begin()
+	r := &rtmAPIAdapter{
+		config: &Config{
+			DrainTimeout: 100 * time.Millisecond,
+		},
+		outgoing: outgoing,
+	}
+	r.handling.begin()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		r.handling.end()
+		time.Sleep(10 * time.Millisecond)
+		outgoing.end()
+	}()
+
+	started := time.Now()
+	r.drain()
+
+	if r.handling.current() != 0 || outgoing.current() != 0 {
+		t.Error("drain returned before in-flight tasks complete.")
+	}
+
+	if elapsed := time.Since(started); elapsed >= 100*time.Millisecond {
+		t.Errorf("drain should return as soon as all tasks complete: %s.", elapsed)
+	}
+}
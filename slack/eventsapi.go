@@ -1,35 +1,104 @@
 package slack
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/oklahomer/go-kasumi/logger"
 	"github.com/oklahomer/go-sarah/v4"
 	"github.com/oklahomer/golack/v2/eventsapi"
+	"github.com/patrickmn/go-cache"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	slackRetryNumHeaderName    = "X-Slack-Retry-Num"
+	slackRetryReasonHeaderName = "X-Slack-Retry-Reason"
+
+	// maxEventsPayloadSize is the maximum size of the request body to read.
+	maxEventsPayloadSize = 1 << 20
+)
+
+var (
+	errInvalidSignature = errors.New("invalid signature")
+	errRequestExpired   = errors.New("request timestamp is out of the acceptable range")
+	errEventInProcess   = errors.New("the same event is still being processed")
+	errAckTimeout       = errors.New("input is not processed in time")
+)
+
+// eventState represents the processing state of a received event_id when Config.AckAfterProcessing is true.
+type eventState int
+
+const (
+	eventProcessing eventState = iota
+	eventProcessed
 )
 
 type eventsAPIAdapter struct {
 	config        *Config
 	client        SlackClient
 	handlePayload func(context.Context, *Config, *eventsapi.EventWrapper, func(sarah.Input) error)
+	serveMux      *serveMuxMount
+	modals        *modalRegistry
+	teams         *teamRegistry
 }
 
 var _ apiSpecificAdapter = (*eventsAPIAdapter)(nil)
 
 func (e *eventsAPIAdapter) run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
-	receiver := eventsapi.NewDefaultEventReceiver(func(wrapper *eventsapi.EventWrapper) {
-		e.handlePayload(ctx, e.config, wrapper, enqueueInput)
-	})
-	errChan := e.client.RunServer(ctx, receiver)
+	if e.config.AppSecret == "" {
+		notifyErr(sarah.NewBotNonContinuableError("application secret is not set"))
+		return
+	}
+
+	if e.serveMux != nil {
+		// The host application serves the endpoint with its own server.
+		// The handlers of the previous run remain on the mux, but they respond with 503 once their context is canceled.
+		e.serveMux.swap(e.handler(ctx, enqueueInput), e.interactionsHandler(ctx, enqueueInput))
+		e.serveMux.mount(e.eventsPath(), e.config.InteractionsPath)
+		<-ctx.Done()
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(e.eventsPath(), e.handler(ctx, enqueueInput))
+	if e.config.InteractionsPath != "" {
+		mux.Handle(e.config.InteractionsPath, e.interactionsHandler(ctx, enqueueInput))
+	}
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", e.config.ListenPort),
+		Handler:      mux,
+		ReadTimeout:  e.config.ReadTimeout,
+		WriteTimeout: e.config.WriteTimeout,
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		if e.config.TLSCertFile != "" && e.config.TLSKeyFile != "" {
+			errChan <- server.ListenAndServeTLS(e.config.TLSCertFile, e.config.TLSKeyFile)
+			return
+		}
+		errChan <- server.ListenAndServe()
+	}()
 
 	select {
 	case <-ctx.Done():
 		// Context is canceled by caller
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), e.config.RequestTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
 		return
 
 	case err := <-errChan:
-		if err == http.ErrServerClosed {
+		if errors.Is(err, http.ErrServerClosed) {
 			// Server is intentionally stopped probably due to caller's context cancellation.
 			return
 		}
@@ -39,6 +108,272 @@ func (e *eventsAPIAdapter) run(ctx context.Context, enqueueInput func(sarah.Inpu
 	}
 }
 
+// serveMuxMount mounts the Events API handlers on the host application's http.ServeMux.
+// http.ServeMux panics when the same pattern is registered twice, so the handlers are registered only once on the first run,
+// and then dispatch each request to the handlers of the current run.
+// This lets the Bot run again on the same Adapter after it is restarted.
+type serveMuxMount struct {
+	mux          *http.ServeMux
+	once         sync.Once
+	events       http.Handler
+	interactions http.Handler
+	mutex        sync.RWMutex
+}
+
+// swap replaces the handlers that serve the requests.
+func (m *serveMuxMount) swap(events http.Handler, interactions http.Handler) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.events = events
+	m.interactions = interactions
+}
+
+// mount registers the dispatching handlers on the mux unless they are already registered.
+// An empty interactionsPath means the interactions are not received.
+func (m *serveMuxMount) mount(eventsPath string, interactionsPath string) {
+	m.once.Do(func() {
+		m.mux.Handle(eventsPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.mutex.RLock()
+			handler := m.events
+			m.mutex.RUnlock()
+			handler.ServeHTTP(w, r)
+		}))
+
+		if interactionsPath != "" {
+			m.mux.Handle(interactionsPath, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				m.mutex.RLock()
+				handler := m.interactions
+				m.mutex.RUnlock()
+				handler.ServeHTTP(w, r)
+			}))
+		}
+	})
+}
+
+func (e *eventsAPIAdapter) eventsPath() string {
+	if e.config.EventsPath == "" {
+		return "/"
+	}
+	return e.config.EventsPath
+}
+
+// handler returns http.Handler that verifies each incoming request, acknowledges it, and then processes the event asynchronously.
+//
+// Slack retries the delivery when the acknowledgement is not returned in 3 seconds,
+// so the event handling is done in another goroutine and a redelivered event with the same event_id is ignored.
+// When Config.AckAfterProcessing is true, the acknowledgement is instead held until the Input is processed. See serveAckable.
+func (e *eventsAPIAdapter) handler(ctx context.Context, enqueueInput func(sarah.Input) error) http.Handler {
+	received := cache.New(e.config.EventDedupeWindow, e.config.EventDedupeWindow)
+	receiver := eventsapi.NewDefaultEventReceiver(func(wrapper *eventsapi.EventWrapper) {
+		if wrapper.EventID != "" {
+			err := received.Add(string(wrapper.EventID), eventProcessed, cache.DefaultExpiration)
+			if err != nil {
+				logger.Debugf("Skipping already received event: %s", wrapper.EventID)
+				return
+			}
+		}
+
+		go e.handlePayload(ctx, e.config, wrapper, e.teams.teamAware(wrapper.TeamID, enqueueInput))
+	})
+	handler := eventsapi.SetupHandler(receiver)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ctx.Err() != nil {
+			// The Bot is already stopped while the host application's server is still running.
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		err := verifyRequest(r, e.config.AppSecret, e.config.RequestTimestampTolerance, time.Now())
+		if err != nil {
+			logger.Warnf("Rejecting Events API request: %s", err.Error())
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if retryNum := r.Header.Get(slackRetryNumHeaderName); retryNum != "" {
+			logger.Debugf("Retried delivery is given. Retry number: %s. Reason: %s.", retryNum, r.Header.Get(slackRetryReasonHeaderName))
+		}
+
+		if e.config.AckAfterProcessing {
+			e.serveAckable(ctx, w, r, received, enqueueInput)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// serveAckable processes the event synchronously and responds to Slack after the Input is processed.
+// When the processing fails or does not complete in Config.AckTimeout, the request is responded with an error status so Slack redelivers the event.
+//
+// The event_id is remembered as being processed until the Input is acknowledged.
+// A redelivered event is rejected while the previous delivery is being processed, is ignored after the previous delivery is successfully processed,
+// and is processed again after the previous delivery fails.
+// The EventsPayloadHandler must pass the Input to enqueueInput before it returns; otherwise, the event is considered processed without waiting.
+func (e *eventsAPIAdapter) serveAckable(ctx context.Context, w http.ResponseWriter, r *http.Request, received *cache.Cache, enqueueInput func(sarah.Input) error) {
+	writer := &ackResponseWriter{ResponseWriter: w}
+	receiver := eventsapi.NewDefaultEventReceiver(func(wrapper *eventsapi.EventWrapper) {
+		writer.err = e.receiveAckable(ctx, wrapper, received, enqueueInput)
+	})
+	eventsapi.SetupHandler(receiver).ServeHTTP(writer, r)
+}
+
+func (e *eventsAPIAdapter) receiveAckable(ctx context.Context, wrapper *eventsapi.EventWrapper, received *cache.Cache, enqueueInput func(sarah.Input) error) error {
+	id := string(wrapper.EventID)
+	if id != "" {
+		err := received.Add(id, eventProcessing, cache.DefaultExpiration)
+		if err != nil {
+			if state, ok := received.Get(id); ok && state == eventProcessing {
+				return errEventInProcess
+			}
+			logger.Debugf("Skipping already processed event: %s", id)
+			return nil
+		}
+	}
+
+	acked := make(chan error, 1)
+	bound := false
+	enqueueInput = e.teams.teamAware(wrapper.TeamID, enqueueInput)
+	e.handlePayload(ctx, e.config, wrapper, func(input sarah.Input) error {
+		bound = bindAck(input, func(err error) {
+			select {
+			case acked <- err:
+			default:
+				// Already acknowledged.
+			}
+		}) || bound
+		return enqueueInput(input)
+	})
+	if !bound {
+		// There is no Input to wait for. e.g. a non-supported event.
+		finishEvent(received, id, nil)
+		return nil
+	}
+
+	timer := time.NewTimer(e.config.AckTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-acked:
+		finishEvent(received, id, err)
+		return err
+
+	case <-timer.C:
+		// The processing still continues. Reflect its result so the redelivered event is handled accordingly.
+		go func() {
+			select {
+			case err := <-acked:
+				finishEvent(received, id, err)
+
+			case <-ctx.Done():
+				finishEvent(received, id, ctx.Err())
+
+			}
+		}()
+		return errAckTimeout
+
+	case <-ctx.Done():
+		finishEvent(received, id, ctx.Err())
+		return ctx.Err()
+
+	}
+}
+
+// finishEvent remembers the event_id as processed on success, or forgets it so the redelivered event is processed again.
+func finishEvent(received *cache.Cache, id string, err error) {
+	if id == "" {
+		return
+	}
+
+	if err != nil {
+		received.Delete(id)
+		return
+	}
+	received.Set(id, eventProcessed, cache.DefaultExpiration)
+}
+
+// bindAck binds the given function to the Input so the function is called via sarah.AckableInput.
+// This returns false when the Input is not *Input.
+func bindAck(input sarah.Input, ack func(error)) bool {
+	// HelpInput and AbortInput are acknowledged via the original Input.
+	switch typed := input.(type) {
+	case *sarah.HelpInput:
+		input = typed.OriginalInput
+
+	case *sarah.AbortInput:
+		input = typed.OriginalInput
+
+	}
+
+	typed, ok := input.(*Input)
+	if !ok {
+		return false
+	}
+	typed.ack = ack
+	return true
+}
+
+// ackResponseWriter replaces the successful status code written by eventsapi.SetupHandler when the event is not processed.
+type ackResponseWriter struct {
+	http.ResponseWriter
+	err error
+}
+
+func (w *ackResponseWriter) WriteHeader(code int) {
+	if code == http.StatusOK && w.err != nil {
+		logger.Warnf("Responding with an error to let Slack redeliver the event: %s", w.err.Error())
+		code = http.StatusServiceUnavailable
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// verifyRequest checks the X-Slack-Signature and X-Slack-Request-Timestamp headers of the given request.
+// A request with a timestamp that is off from now by more than the given tolerance is rejected to prevent a replay attack.
+// When the tolerance is zero, the timestamp is not checked.
+// The request body is read to calculate the signature, and is then replaced so the subsequent handler can read it again.
+//
+// See https://api.slack.com/authentication/verifying-requests-from-slack
+func verifyRequest(r *http.Request, secret string, tolerance time.Duration, now time.Time) error {
+	timestamp := r.Header.Get(eventsapi.SlackRequestTimestampHeaderName)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp is given: %q", errInvalidSignature, timestamp)
+	}
+
+	if tolerance > 0 {
+		skew := now.Sub(time.Unix(ts, 0))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > tolerance {
+			return errRequestExpired
+		}
+	}
+
+	signature := strings.TrimPrefix(r.Header.Get(eventsapi.SlackSignatureHeaderName), "v0=")
+	given, err := hex.DecodeString(signature)
+	if err != nil || len(given) == 0 {
+		return fmt.Errorf("%w: malformed signature is given", errInvalidSignature)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventsPayloadSize))
+	_ = r.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "v0:%s:", timestamp)
+	_, _ = mac.Write(body)
+	if !hmac.Equal(given, mac.Sum(nil)) {
+		return errInvalidSignature
+	}
+
+	return nil
+}
+
 // DefaultEventsPayloadHandler receives incoming events, converts them to sarah.Input, and then passes them to enqueueInput.
 // To replace this default behavior, define a function with the same signature and replace this.
 //
@@ -56,6 +391,8 @@ func DefaultEventsPayloadHandler(_ context.Context, config *Config, payload *eve
 		return
 	}
 
+	flagBotInput(config, input)
+
 	trimmed := strings.TrimSpace(input.Message())
 	if config.HelpCommand != "" && trimmed == config.HelpCommand {
 		// Help command
@@ -2,59 +2,70 @@ package slack
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"github.com/oklahomer/go-sarah/v4"
 	"github.com/oklahomer/golack/v2/event"
 	"github.com/oklahomer/golack/v2/eventsapi"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
 
 func Test_eventsAPIAdapter_run(t *testing.T) {
 	t.Run("Successful case", func(t *testing.T) {
-		// Prepare an adapter with a client that run a server.
-		// The server notifies a signal when it stops on context cancellation.
-		closed := make(chan struct{}, 1)
-		client := &DummyClient{
-			RunServerFunc: func(ctx context.Context, receiver eventsapi.EventReceiver) <-chan error {
-				<-ctx.Done()
-				closed <- struct{}{}
-				return make(chan error, 1)
-			},
-		}
+		// Prepare an adapter that runs a server with a random port.
 		adapter := &eventsAPIAdapter{
-			config:        nil,
-			client:        client,
+			config: &Config{
+				AppSecret:      "secret",
+				ListenPort:     0,
+				RequestTimeout: 1 * time.Second,
+			},
 			handlePayload: DefaultEventsPayloadHandler,
 		}
 
 		ctx, cancel := context.WithCancel(context.Background())
-		go adapter.run(ctx, func(_ sarah.Input) error { return nil }, func(err error) {})
+		errCh := make(chan error, 1)
+		finished := make(chan struct{})
+		go func() {
+			adapter.run(ctx, func(_ sarah.Input) error { return nil }, func(err error) { errCh <- err })
+			close(finished)
+		}()
 		cancel()
 
-		// Context cancellation should not cause an error state.
+		// Context cancellation should stop the server without causing an error state.
 		select {
-		case <-closed:
+		case err := <-errCh:
+			t.Errorf("Unexpected error is returned: %s", err.Error())
+
+		case <-finished:
 			// O.K.
 
-		case <-time.NewTimer(10 * time.Millisecond).C:
+		case <-time.NewTimer(1 * time.Second).C:
 			t.Error("Context cancellation is not propagated to running server.")
 		}
 	})
 
 	t.Run("Running server returns an error", func(t *testing.T) {
-		// Prepare an adapter with a client that fails to run a server.
-		expectedErr := errors.New("ERROR")
-		client := &DummyClient{
-			RunServerFunc: func(_ context.Context, _ eventsapi.EventReceiver) <-chan error {
-				ch := make(chan error, 1)
-				ch <- expectedErr
-				return ch
-			},
+		// Occupy a port so the server fails to listen.
+		listener, err := net.Listen("tcp", ":0")
+		if err != nil {
+			t.Fatalf("Failed to listen: %s", err.Error())
 		}
+		defer listener.Close()
+
 		adapter := &eventsAPIAdapter{
-			config:        nil,
-			client:        client,
+			config: &Config{
+				AppSecret:  "secret",
+				ListenPort: listener.Addr().(*net.TCPAddr).Port,
+			},
 			handlePayload: DefaultEventsPayloadHandler,
 		}
 
@@ -66,7 +77,6 @@ func Test_eventsAPIAdapter_run(t *testing.T) {
 		}
 		go adapter.run(ctx, func(_ sarah.Input) error { return nil }, notifyErr)
 
-		// Context cancellation should not cause an error state.
 		select {
 		case err := <-errCh:
 			var target *sarah.BotNonContinuableError
@@ -74,10 +84,329 @@ func Test_eventsAPIAdapter_run(t *testing.T) {
 				t.Errorf("Expected error is not returned: %#v", err)
 			}
 
-		case <-time.NewTimer(10 * time.Millisecond).C:
+		case <-time.NewTimer(1 * time.Second).C:
 			t.Error("Error is not returned event though server unexpectedly stopped.")
 		}
 	})
+
+	t.Run("Mount on ServeMux", func(t *testing.T) {
+		mux := http.NewServeMux()
+		adapter := &eventsAPIAdapter{
+			config: &Config{
+				AppSecret:  "secret",
+				EventsPath: "/slack/events",
+			},
+			handlePayload: DefaultEventsPayloadHandler,
+			serveMux:      &serveMuxMount{mux: mux},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		finished := make(chan struct{})
+		go func() {
+			adapter.run(ctx, func(_ sarah.Input) error { return nil }, func(err error) {})
+			close(finished)
+		}()
+
+		// Wait till the handler is mounted.
+		time.Sleep(10 * time.Millisecond)
+		req := signedRequest("secret", time.Now(), `{"type":"url_verification","challenge":"abc"}`)
+		req.URL.Path = "/slack/events"
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK || recorder.Body.String() != "abc" {
+			t.Errorf("Unexpected response is returned: %d %s.", recorder.Code, recorder.Body.String())
+		}
+
+		cancel()
+		select {
+		case <-finished:
+			// O.K.
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Fatal("Context cancellation is not propagated.")
+
+		}
+
+		// The handler remains on the mux, but stops accepting requests.
+		req = signedRequest("secret", time.Now(), `{"type":"url_verification","challenge":"abc"}`)
+		req.URL.Path = "/slack/events"
+		recorder = httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Errorf("Unexpected status code is returned: %d.", recorder.Code)
+		}
+	})
+
+	t.Run("Application secret is not set", func(t *testing.T) {
+		adapter := &eventsAPIAdapter{
+			config:        &Config{},
+			handlePayload: DefaultEventsPayloadHandler,
+		}
+
+		var given error
+		adapter.run(context.TODO(), func(_ sarah.Input) error { return nil }, func(err error) { given = err })
+
+		var target *sarah.BotNonContinuableError
+		if !errors.As(given, &target) {
+			t.Errorf("Expected error is not returned: %#v", given)
+		}
+	})
+}
+
+func signedRequest(secret string, timestamp time.Time, body string) *http.Request {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + ts + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(eventsapi.SlackRequestTimestampHeaderName, ts)
+	req.Header.Set(eventsapi.SlackSignatureHeaderName, "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func Test_verifyRequest(t *testing.T) {
+	now := time.Now()
+	body := `{"type":"event_callback"}`
+
+	testSets := []struct {
+		req *http.Request
+		err error
+	}{
+		{
+			req: signedRequest("secret", now, body),
+			err: nil,
+		},
+		{
+			req: signedRequest("secret", now.Add(-10*time.Minute), body),
+			err: errRequestExpired,
+		},
+		{
+			req: signedRequest("secret", now.Add(10*time.Minute), body),
+			err: errRequestExpired,
+		},
+		{
+			req: signedRequest("wrong", now, body),
+			err: errInvalidSignature,
+		},
+		{
+			req: httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)),
+			err: errInvalidSignature,
+		},
+	}
+
+	for i, testSet := range testSets {
+		err := verifyRequest(testSet.req, "secret", 5*time.Minute, now)
+		if !errors.Is(err, testSet.err) {
+			t.Errorf("Unexpected error is returned on test %d: %#v.", i, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		// The body must be readable by the subsequent handler.
+		read, _ := io.ReadAll(testSet.req.Body)
+		if string(read) != body {
+			t.Errorf("Request body is not restored on test %d: %s.", i, read)
+		}
+	}
+}
+
+func Test_eventsAPIAdapter_handler(t *testing.T) {
+	handled := make(chan *eventsapi.EventWrapper, 2)
+	adapter := &eventsAPIAdapter{
+		config: &Config{
+			AppSecret:                 "secret",
+			RequestTimestampTolerance: 5 * time.Minute,
+			EventDedupeWindow:         time.Minute,
+		},
+		handlePayload: func(_ context.Context, _ *Config, wrapper *eventsapi.EventWrapper, _ func(sarah.Input) error) {
+			handled <- wrapper
+		},
+	}
+	handler := adapter.handler(context.TODO(), func(_ sarah.Input) error { return nil })
+
+	body := `{"type":"event_callback","event_id":"Ev123","event":{"type":"message","channel":"C123","user":"U123","text":"Hello","ts":"1355517523.000005"}}`
+
+	// The first delivery is handled.
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, signedRequest("secret", time.Now(), body))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected status code is returned: %d.", recorder.Code)
+	}
+
+	select {
+	case wrapper := <-handled:
+		if wrapper.EventID != "Ev123" {
+			t.Errorf("Unexpected event is handled: %#v.", wrapper)
+		}
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Fatal("Event is not handled.")
+
+	}
+
+	// The retried delivery is acknowledged but is not handled again.
+	req := signedRequest("secret", time.Now(), body)
+	req.Header.Set(slackRetryNumHeaderName, "1")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected status code is returned: %d.", recorder.Code)
+	}
+
+	select {
+	case wrapper := <-handled:
+		t.Errorf("Retried event is handled: %#v.", wrapper)
+
+	case <-time.NewTimer(50 * time.Millisecond).C:
+		// O.K.
+
+	}
+
+	// The request with invalid signature is rejected.
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, signedRequest("wrong", time.Now(), body))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Unexpected status code is returned: %d.", recorder.Code)
+	}
+}
+
+func Test_eventsAPIAdapter_handler_AckAfterProcessing(t *testing.T) {
+	body := func(id string) string {
+		return `{"type":"event_callback","event_id":"` + id + `","event":{"type":"message","channel":"C123","user":"U123","text":"Hello","ts":"1355517523.000005"}}`
+	}
+	newHandler := func(enqueueInput func(sarah.Input) error) http.Handler {
+		adapter := &eventsAPIAdapter{
+			config: &Config{
+				AppSecret:          "secret",
+				EventDedupeWindow:  time.Minute,
+				AckAfterProcessing: true,
+				AckTimeout:         50 * time.Millisecond,
+			},
+			handlePayload: DefaultEventsPayloadHandler,
+		}
+		return adapter.handler(context.TODO(), enqueueInput)
+	}
+	serve := func(handler http.Handler, id string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, signedRequest("secret", time.Now(), body(id)))
+		return recorder.Code
+	}
+
+	t.Run("Processed", func(t *testing.T) {
+		enqueued := 0
+		handler := newHandler(func(input sarah.Input) error {
+			enqueued++
+			input.(sarah.AckableInput).Ack(nil)
+			return nil
+		})
+
+		if code := serve(handler, "Ev1"); code != http.StatusOK {
+			t.Errorf("Unexpected status code is returned: %d.", code)
+		}
+
+		// The redelivered event is acknowledged without processing.
+		if code := serve(handler, "Ev1"); code != http.StatusOK {
+			t.Errorf("Unexpected status code is returned for redelivery: %d.", code)
+		}
+		if enqueued != 1 {
+			t.Errorf("Unexpected number of inputs are enqueued: %d.", enqueued)
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		enqueued := 0
+		handler := newHandler(func(input sarah.Input) error {
+			enqueued++
+			input.(sarah.AckableInput).Ack(errors.New("dummy"))
+			return nil
+		})
+
+		if code := serve(handler, "Ev1"); code != http.StatusServiceUnavailable {
+			t.Errorf("Unexpected status code is returned: %d.", code)
+		}
+
+		// The redelivered event is processed again.
+		_ = serve(handler, "Ev1")
+		if enqueued != 2 {
+			t.Errorf("Unexpected number of inputs are enqueued: %d.", enqueued)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		enqueued := 0
+		var pending sarah.AckableInput
+		handler := newHandler(func(input sarah.Input) error {
+			enqueued++
+			pending = input.(sarah.AckableInput)
+			return nil
+		})
+
+		if code := serve(handler, "Ev1"); code != http.StatusServiceUnavailable {
+			t.Errorf("Unexpected status code is returned: %d.", code)
+		}
+
+		// The redelivered event is rejected while the previous delivery is being processed.
+		if code := serve(handler, "Ev1"); code != http.StatusServiceUnavailable {
+			t.Errorf("Unexpected status code is returned while processing: %d.", code)
+		}
+
+		// The result of the delayed processing is reflected.
+		pending.Ack(nil)
+		time.Sleep(10 * time.Millisecond)
+		if code := serve(handler, "Ev1"); code != http.StatusOK {
+			t.Errorf("Unexpected status code is returned after processing: %d.", code)
+		}
+		if enqueued != 1 {
+			t.Errorf("Unexpected number of inputs are enqueued: %d.", enqueued)
+		}
+	})
+
+	t.Run("Non-supported event", func(t *testing.T) {
+		adapter := &eventsAPIAdapter{
+			config: &Config{
+				AppSecret:          "secret",
+				EventDedupeWindow:  time.Minute,
+				AckAfterProcessing: true,
+				AckTimeout:         time.Second,
+			},
+			handlePayload: func(_ context.Context, _ *Config, _ *eventsapi.EventWrapper, _ func(sarah.Input) error) {},
+		}
+		handler := adapter.handler(context.TODO(), func(_ sarah.Input) error {
+			return nil
+		})
+
+		if code := serve(handler, "Ev1"); code != http.StatusOK {
+			t.Errorf("Unexpected status code is returned: %d.", code)
+		}
+	})
+}
+
+func Test_bindAck(t *testing.T) {
+	acked := 0
+	ack := func(_ error) {
+		acked++
+	}
+
+	input := &Input{}
+	if !bindAck(input, ack) {
+		t.Fatal("Ack should be bound to *Input.")
+	}
+	input.Ack(nil)
+
+	if !bindAck(&sarah.HelpInput{OriginalInput: &Input{}}, ack) {
+		t.Error("Ack should be bound to the original input of HelpInput.")
+	}
+
+	if bindAck(&DummyInput{}, ack) {
+		t.Error("Ack should not be bound to an unknown input.")
+	}
+
+	if acked != 1 {
+		t.Errorf("Unexpected number of acknowledgements: %d.", acked)
+	}
 }
 
 func TestDefaultEventsPayloadHandler(t *testing.T) {
@@ -0,0 +1,401 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AppHomeInput is a sarah.Input implementation that represents a user opening the App Home.
+// A Command can match this Input by its type and respond with NewHomeViewResponse to publish the Home tab for the user.
+//
+//	sarah.NewCommandPropsBuilder().
+//		MatchFunc(func(input sarah.Input) bool {
+//			_, ok := input.(*slack.AppHomeInput)
+//			return ok
+//		})
+//
+// Message returns the opened tab, which is either "home" or "messages."
+type AppHomeInput struct {
+	Event  *event.AppHomeOpened
+	teamID string
+}
+
+var _ sarah.SourcedInput = (*AppHomeInput)(nil)
+
+// SenderKey returns the App Home channel ID and the user ID, which is in the same form as Input.SenderKey.
+func (i *AppHomeInput) SenderKey() string {
+	return fmt.Sprintf("%s|%s", i.Event.ChannelID.String(), i.Event.UserID.String())
+}
+
+// Message returns the opened tab.
+func (i *AppHomeInput) Message() string {
+	return i.Event.Tab
+}
+
+// SentAt returns when the App Home is opened.
+func (i *AppHomeInput) SentAt() time.Time {
+	if i.Event.EventTimeStamp == nil {
+		return time.Time{}
+	}
+	return i.Event.EventTimeStamp.Time
+}
+
+// ReplyTo returns the App Home channel, which is the direct message channel between the user and the app.
+func (i *AppHomeInput) ReplyTo() sarah.OutputDestination {
+	return i.Event.ChannelID
+}
+
+// UserID returns the Slack user who opened the App Home.
+func (i *AppHomeInput) UserID() string {
+	return i.Event.UserID.String()
+}
+
+// DirectMessage always returns true since the App Home is a private space between the user and the app.
+func (i *AppHomeInput) DirectMessage() bool {
+	return true
+}
+
+// TeamID returns the ID of the workspace where the App Home is opened.
+func (i *AppHomeInput) TeamID() string {
+	return i.teamID
+}
+
+// BlockAction represents an interactive component in a block that a user interacted with, such as a button.
+type BlockAction struct {
+	ActionID        event.ActionID   `json:"action_id"`
+	BlockID         event.BlockID    `json:"block_id"`
+	Type            string           `json:"type"`
+	Value           string           `json:"value"`
+	SelectedOption  *SelectedOption  `json:"selected_option"`
+	ActionTimeStamp *event.TimeStamp `json:"action_ts"`
+}
+
+// SelectedOption represents an option that a user selected with a select menu or radio buttons.
+type SelectedOption struct {
+	Value string `json:"value"`
+}
+
+// ViewPayload represents a view contained in an interaction payload.
+// Only the fields that are typically required to handle the interaction are decoded.
+type ViewPayload struct {
+	ID              event.ViewID `json:"id"`
+	Type            string       `json:"type"`
+	CallbackID      string       `json:"callback_id"`
+	PrivateMetadata string       `json:"private_metadata"`
+	Hash            string       `json:"hash"`
+	State           *ViewState   `json:"state"`
+}
+
+// ViewState represents the values that a user entered in the input blocks of a view.
+type ViewState struct {
+	Values map[event.BlockID]map[event.ActionID]*ViewStateValue `json:"values"`
+}
+
+// ViewStateValue represents a value of an input element.
+// Which field is set depends on the type of the element.
+type ViewStateValue struct {
+	Type            string            `json:"type"`
+	Value           string            `json:"value"`
+	SelectedOption  *SelectedOption   `json:"selected_option"`
+	SelectedOptions []*SelectedOption `json:"selected_options"`
+	SelectedDate    string            `json:"selected_date"`
+	SelectedUser    event.UserID      `json:"selected_user"`
+	SelectedChannel event.ChannelID   `json:"selected_channel"`
+}
+
+// Value returns the value of the input element with the given IDs.
+// The value of a select menu is the value of the selected option.
+// An empty string is returned when no such element exists.
+func (s *ViewState) Value(blockID event.BlockID, actionID event.ActionID) string {
+	if s == nil {
+		return ""
+	}
+
+	value, ok := s.Values[blockID][actionID]
+	if !ok || value == nil {
+		return ""
+	}
+
+	switch {
+	case value.SelectedOption != nil:
+		return value.SelectedOption.Value
+
+	case value.SelectedDate != "":
+		return value.SelectedDate
+
+	case value.SelectedUser != "":
+		return value.SelectedUser.String()
+
+	case value.SelectedChannel != "":
+		return value.SelectedChannel.String()
+
+	default:
+		return value.Value
+
+	}
+}
+
+// BlockActionInput is a sarah.Input implementation that represents a user's interaction with a block element such as a button click.
+// Message returns the action_id of the first action, so a Command can match this Input with sarah.CommandPropsBuilder.MatchPattern.
+// Respond with NewModalResponse to open a modal with the trigger ID of this interaction.
+type BlockActionInput struct {
+	triggerID string
+	userID    event.UserID
+	channelID event.ChannelID
+	actions   []*BlockAction
+	view      *ViewPayload
+	teamID    string
+	timestamp time.Time
+}
+
+var _ sarah.SourcedInput = (*BlockActionInput)(nil)
+
+// SenderKey returns the channel ID and the user ID, which is in the same form as Input.SenderKey.
+// The channel ID is empty when the interaction happens in a view such as the App Home.
+func (i *BlockActionInput) SenderKey() string {
+	return fmt.Sprintf("%s|%s", i.channelID.String(), i.userID.String())
+}
+
+// Message returns the action_id of the first action.
+func (i *BlockActionInput) Message() string {
+	if len(i.actions) == 0 {
+		return ""
+	}
+	return i.actions[0].ActionID.String()
+}
+
+// SentAt returns when the user interacted with the element.
+func (i *BlockActionInput) SentAt() time.Time {
+	return i.timestamp
+}
+
+// ReplyTo returns the channel where the interacted message is posted.
+// When the interaction happens in a view, the user ID is returned so the response is delivered via a direct message.
+func (i *BlockActionInput) ReplyTo() sarah.OutputDestination {
+	if i.channelID == "" {
+		return event.ChannelID(i.userID.String())
+	}
+	return i.channelID
+}
+
+// UserID returns the Slack user who interacted with the element.
+func (i *BlockActionInput) UserID() string {
+	return i.userID.String()
+}
+
+// DirectMessage tells if the interaction happens in a direct message channel or in a view, which is only visible to the user.
+func (i *BlockActionInput) DirectMessage() bool {
+	return i.channelID == "" || strings.HasPrefix(i.channelID.String(), "D")
+}
+
+// TeamID returns the ID of the workspace where the interaction happens.
+func (i *BlockActionInput) TeamID() string {
+	return i.teamID
+}
+
+// TriggerID returns the trigger ID to open a modal. This expires in 3 seconds.
+func (i *BlockActionInput) TriggerID() string {
+	return i.triggerID
+}
+
+// Actions returns the actions the user took.
+func (i *BlockActionInput) Actions() []*BlockAction {
+	return i.actions
+}
+
+// View returns the view where the interaction happens. This is nil when the interaction happens in a message.
+func (i *BlockActionInput) View() *ViewPayload {
+	return i.view
+}
+
+// ViewInput is a sarah.Input implementation that represents a submission of a modal.
+// Message returns the callback_id of the view, so a Command can match this Input with sarah.CommandPropsBuilder.MatchPattern.
+//
+// When the modal is opened with NewModalResponse, SenderKey returns the same value as the Input that opened the modal.
+// Therefore, a function given to RespWithNext along with NewModalResponse receives the submission as the next Input of the conversation.
+// When the modal is closed instead of submitted and the view is built with View.WithNotifyOnClose,
+// ViewInput is passed to Sarah as sarah.AbortInput so the conversation is aborted.
+type ViewInput struct {
+	triggerID string
+	senderKey string
+	userID    event.UserID
+	view      *ViewPayload
+	teamID    string
+	timestamp time.Time
+}
+
+var _ sarah.SourcedInput = (*ViewInput)(nil)
+
+// SenderKey returns the SenderKey of the Input that opened the modal.
+// When the modal is opened in another way, this returns the user ID with an empty channel ID.
+func (i *ViewInput) SenderKey() string {
+	return i.senderKey
+}
+
+// Message returns the callback_id of the submitted view.
+func (i *ViewInput) Message() string {
+	if i.view == nil {
+		return ""
+	}
+	return i.view.CallbackID
+}
+
+// SentAt returns when the view is submitted.
+func (i *ViewInput) SentAt() time.Time {
+	return i.timestamp
+}
+
+// ReplyTo returns the channel where the Input that opened the modal is sent.
+// When such a channel is unknown, the user ID is returned so the response is delivered via a direct message.
+func (i *ViewInput) ReplyTo() sarah.OutputDestination {
+	if idx := strings.LastIndex(i.senderKey, "|"); idx > 0 {
+		return event.ChannelID(i.senderKey[:idx])
+	}
+	return event.ChannelID(i.userID.String())
+}
+
+// UserID returns the Slack user who submitted the view.
+func (i *ViewInput) UserID() string {
+	return i.userID.String()
+}
+
+// DirectMessage always returns true since a modal is only visible to the user.
+func (i *ViewInput) DirectMessage() bool {
+	return true
+}
+
+// TeamID returns the ID of the workspace where the view is submitted.
+func (i *ViewInput) TeamID() string {
+	return i.teamID
+}
+
+// TriggerID returns the trigger ID to open another modal. This expires in 3 seconds.
+func (i *ViewInput) TriggerID() string {
+	return i.triggerID
+}
+
+// View returns the submitted view including the values the user entered.
+func (i *ViewInput) View() *ViewPayload {
+	return i.view
+}
+
+// interactionPayload represents a payload sent to the interactivity endpoint.
+//
+// See https://api.slack.com/reference/interaction-payloads
+type interactionPayload struct {
+	Type      string `json:"type"`
+	TriggerID string `json:"trigger_id"`
+	User      struct {
+		ID event.UserID `json:"id"`
+	} `json:"user"`
+	Team *struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	Channel *struct {
+		ID event.ChannelID `json:"id"`
+	} `json:"channel"`
+	Actions []*BlockAction `json:"actions"`
+	View    *ViewPayload   `json:"view"`
+}
+
+// interactionToInput converts the given interaction payload to sarah.Input.
+// The given lookup returns the SenderKey associated with the given view ID.
+func interactionToInput(payload *interactionPayload, lookup func(event.ViewID) (string, bool), now time.Time) (sarah.Input, error) {
+	switch payload.Type {
+	case "block_actions":
+		input := &BlockActionInput{
+			triggerID: payload.TriggerID,
+			userID:    payload.User.ID,
+			actions:   payload.Actions,
+			view:      payload.View,
+			timestamp: now,
+		}
+		if payload.Channel != nil {
+			input.channelID = payload.Channel.ID
+		}
+		if len(payload.Actions) > 0 && payload.Actions[0].ActionTimeStamp != nil {
+			input.timestamp = payload.Actions[0].ActionTimeStamp.Time
+		}
+		return input, nil
+
+	case "view_submission", "view_closed":
+		if payload.View == nil {
+			return nil, fmt.Errorf("view is not given with %s payload", payload.Type)
+		}
+
+		senderKey, ok := lookup(payload.View.ID)
+		if !ok {
+			senderKey = fmt.Sprintf("|%s", payload.User.ID.String())
+		}
+		input := &ViewInput{
+			triggerID: payload.TriggerID,
+			senderKey: senderKey,
+			userID:    payload.User.ID,
+			view:      payload.View,
+			timestamp: now,
+		}
+		if payload.Type == "view_closed" {
+			return sarah.NewAbortInput(input), nil
+		}
+		return input, nil
+
+	default:
+		return nil, ErrNonSupportedEvent
+
+	}
+}
+
+// interactionsHandler returns http.Handler that receives interaction payloads such as a button click and a modal submission,
+// converts them to sarah.Input, and then passes them to enqueueInput.
+// The request is acknowledged right after the Input is enqueued, so a modal is closed on submission.
+//
+// See https://api.slack.com/interactivity/handling
+func (e *eventsAPIAdapter) interactionsHandler(ctx context.Context, enqueueInput func(sarah.Input) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ctx.Err() != nil {
+			// The Bot is already stopped while the host application's server is still running.
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		err := verifyRequest(r, e.config.AppSecret, e.config.RequestTimestampTolerance, time.Now())
+		if err != nil {
+			logger.Warnf("Rejecting interaction request: %s", err.Error())
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		payload := &interactionPayload{}
+		err = json.Unmarshal([]byte(r.PostFormValue("payload")), payload)
+		if err != nil {
+			logger.Warnf("Failed to decode interaction payload: %s", err.Error())
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		input, err := interactionToInput(payload, e.modals.lookup, time.Now())
+		if err == ErrNonSupportedEvent {
+			logger.Debugf("Interaction given, but no corresponding action is defined. %#v", payload)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if err != nil {
+			logger.Warnf("Failed to convert %s interaction: %s", payload.Type, err.Error())
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if payload.Team != nil {
+			enqueueInput = e.teams.teamAware(payload.Team.ID, enqueueInput)
+		}
+		_ = enqueueInput(input)
+		w.WriteHeader(http.StatusOK)
+	})
+}
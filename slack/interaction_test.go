@@ -0,0 +1,324 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAppHomeInput(t *testing.T) {
+	now := time.Now()
+	ev := &event.AppHomeOpened{
+		UserID:         "U123",
+		ChannelID:      "D123",
+		EventTimeStamp: &event.TimeStamp{Time: now},
+		Tab:            "home",
+	}
+
+	input, err := EventToInput(ev)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	typed, ok := input.(*AppHomeInput)
+	if !ok {
+		t.Fatalf("Unexpected Input is returned: %T.", input)
+	}
+
+	if typed.SenderKey() != "D123|U123" {
+		t.Errorf("Unexpected sender key is returned: %s.", typed.SenderKey())
+	}
+
+	if typed.Message() != "home" {
+		t.Errorf("Unexpected message is returned: %s.", typed.Message())
+	}
+
+	if !typed.SentAt().Equal(now) {
+		t.Errorf("Unexpected timestamp is returned: %s.", typed.SentAt())
+	}
+
+	if typed.ReplyTo() != ev.ChannelID {
+		t.Errorf("Unexpected destination is returned: %#v.", typed.ReplyTo())
+	}
+
+	if typed.UserID() != "U123" || !typed.DirectMessage() {
+		t.Errorf("Unexpected source is returned: %#v.", typed)
+	}
+}
+
+func TestViewState_Value(t *testing.T) {
+	state := &ViewState{}
+	err := json.Unmarshal([]byte(`{
+		"values": {
+			"comment": {"comment_input": {"type": "plain_text_input", "value": "Hello"}},
+			"size": {"size_select": {"type": "static_select", "selected_option": {"value": "large"}}},
+			"date": {"date_picker": {"type": "datepicker", "selected_date": "2026-01-01"}},
+			"user": {"user_select": {"type": "users_select", "selected_user": "U123"}}
+		}
+	}`), state)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	testSets := []struct {
+		blockID  event.BlockID
+		actionID event.ActionID
+		expected string
+	}{
+		{blockID: "comment", actionID: "comment_input", expected: "Hello"},
+		{blockID: "size", actionID: "size_select", expected: "large"},
+		{blockID: "date", actionID: "date_picker", expected: "2026-01-01"},
+		{blockID: "user", actionID: "user_select", expected: "U123"},
+		{blockID: "comment", actionID: "unknown", expected: ""},
+		{blockID: "unknown", actionID: "comment_input", expected: ""},
+	}
+
+	for i, tt := range testSets {
+		value := state.Value(tt.blockID, tt.actionID)
+		if value != tt.expected {
+			t.Errorf("Unexpected value is returned on test #%d: %s.", i+1, value)
+		}
+	}
+
+	var nilState *ViewState
+	if nilState.Value("comment", "comment_input") != "" {
+		t.Error("Empty value should be returned for nil state.")
+	}
+}
+
+func Test_interactionToInput(t *testing.T) {
+	now := time.Now()
+	lookup := func(id event.ViewID) (string, bool) {
+		if id == "V123" {
+			return "C123|U123", true
+		}
+		return "", false
+	}
+
+	t.Run("block_actions in a message", func(t *testing.T) {
+		payload := &interactionPayload{}
+		_ = json.Unmarshal([]byte(`{
+			"type": "block_actions",
+			"trigger_id": "trigger",
+			"user": {"id": "U123"},
+			"channel": {"id": "C123"},
+			"actions": [{"action_id": "open_form", "block_id": "buttons", "type": "button", "value": "1", "action_ts": "1355517523.000005"}]
+		}`), payload)
+
+		input, err := interactionToInput(payload, lookup, now)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		typed, ok := input.(*BlockActionInput)
+		if !ok {
+			t.Fatalf("Unexpected Input is returned: %T.", input)
+		}
+
+		if typed.SenderKey() != "C123|U123" || typed.Message() != "open_form" || typed.TriggerID() != "trigger" {
+			t.Errorf("Unexpected Input is returned: %#v.", typed)
+		}
+
+		if typed.ReplyTo() != event.ChannelID("C123") || typed.DirectMessage() {
+			t.Errorf("Unexpected destination is returned: %#v.", typed.ReplyTo())
+		}
+
+		if typed.SentAt().Unix() != 1355517523 {
+			t.Errorf("Unexpected timestamp is returned: %s.", typed.SentAt())
+		}
+
+		if len(typed.Actions()) != 1 || typed.Actions()[0].Value != "1" || typed.View() != nil {
+			t.Errorf("Unexpected actions are returned: %#v.", typed.Actions())
+		}
+	})
+
+	t.Run("block_actions in a view", func(t *testing.T) {
+		payload := &interactionPayload{}
+		_ = json.Unmarshal([]byte(`{
+			"type": "block_actions",
+			"trigger_id": "trigger",
+			"user": {"id": "U123"},
+			"view": {"id": "V999", "type": "home"},
+			"actions": [{"action_id": "open_form"}]
+		}`), payload)
+
+		input, err := interactionToInput(payload, lookup, now)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		typed := input.(*BlockActionInput)
+		if typed.ReplyTo() != event.ChannelID("U123") || !typed.DirectMessage() {
+			t.Errorf("Unexpected destination is returned: %#v.", typed.ReplyTo())
+		}
+
+		if typed.View() == nil || typed.View().Type != "home" {
+			t.Errorf("Unexpected view is returned: %#v.", typed.View())
+		}
+
+		if !typed.SentAt().Equal(now) {
+			t.Errorf("Unexpected timestamp is returned: %s.", typed.SentAt())
+		}
+	})
+
+	t.Run("view_submission of a registered view", func(t *testing.T) {
+		payload := &interactionPayload{
+			Type:      "view_submission",
+			TriggerID: "trigger",
+			View:      &ViewPayload{ID: "V123", CallbackID: "feedback"},
+		}
+		payload.User.ID = "U123"
+
+		input, err := interactionToInput(payload, lookup, now)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		typed, ok := input.(*ViewInput)
+		if !ok {
+			t.Fatalf("Unexpected Input is returned: %T.", input)
+		}
+
+		if typed.SenderKey() != "C123|U123" || typed.Message() != "feedback" || typed.TriggerID() != "trigger" {
+			t.Errorf("Unexpected Input is returned: %#v.", typed)
+		}
+
+		if typed.ReplyTo() != event.ChannelID("C123") {
+			t.Errorf("Unexpected destination is returned: %#v.", typed.ReplyTo())
+		}
+	})
+
+	t.Run("view_submission of an unknown view", func(t *testing.T) {
+		payload := &interactionPayload{
+			Type: "view_submission",
+			View: &ViewPayload{ID: "V999"},
+		}
+		payload.User.ID = "U123"
+
+		input, _ := interactionToInput(payload, lookup, now)
+		typed := input.(*ViewInput)
+		if typed.SenderKey() != "|U123" {
+			t.Errorf("Unexpected sender key is returned: %s.", typed.SenderKey())
+		}
+
+		if typed.ReplyTo() != event.ChannelID("U123") {
+			t.Errorf("Unexpected destination is returned: %#v.", typed.ReplyTo())
+		}
+	})
+
+	t.Run("view_closed", func(t *testing.T) {
+		payload := &interactionPayload{
+			Type: "view_closed",
+			View: &ViewPayload{ID: "V123"},
+		}
+
+		input, err := interactionToInput(payload, lookup, now)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		abort, ok := input.(*sarah.AbortInput)
+		if !ok {
+			t.Fatalf("Unexpected Input is returned: %T.", input)
+		}
+
+		if abort.SenderKey() != "C123|U123" {
+			t.Errorf("Unexpected sender key is returned: %s.", abort.SenderKey())
+		}
+	})
+
+	t.Run("view_submission without view", func(t *testing.T) {
+		_, err := interactionToInput(&interactionPayload{Type: "view_submission"}, lookup, now)
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+	})
+
+	t.Run("Non-supported type", func(t *testing.T) {
+		_, err := interactionToInput(&interactionPayload{Type: "shortcut"}, lookup, now)
+		if err != ErrNonSupportedEvent {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func Test_eventsAPIAdapter_interactionsHandler(t *testing.T) {
+	var enqueued []sarah.Input
+	adapter := &eventsAPIAdapter{
+		config: &Config{
+			AppSecret:                 "secret",
+			RequestTimestampTolerance: 5 * time.Minute,
+		},
+		modals: newModalRegistry(time.Minute),
+	}
+	handler := adapter.interactionsHandler(context.TODO(), func(input sarah.Input) error {
+		enqueued = append(enqueued, input)
+		return nil
+	})
+
+	request := func(secret string, payload string) *http.Request {
+		req := signedRequest(secret, time.Now(), url.Values{"payload": []string{payload}}.Encode())
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	testSets := []struct {
+		req      *http.Request
+		status   int
+		enqueued int
+	}{
+		{
+			req:      request("secret", `{"type":"block_actions","user":{"id":"U123"},"actions":[{"action_id":"open_form"}]}`),
+			status:   http.StatusOK,
+			enqueued: 1,
+		},
+		{
+			req:      request("secret", `{"type":"shortcut","user":{"id":"U123"}}`),
+			status:   http.StatusOK,
+			enqueued: 1,
+		},
+		{
+			req:      request("secret", `{"type":"view_submission","user":{"id":"U123"}}`),
+			status:   http.StatusBadRequest,
+			enqueued: 1,
+		},
+		{
+			req:      request("secret", `invalid`),
+			status:   http.StatusBadRequest,
+			enqueued: 1,
+		},
+		{
+			req:      request("wrong", `{"type":"block_actions","user":{"id":"U123"}}`),
+			status:   http.StatusUnauthorized,
+			enqueued: 1,
+		},
+	}
+
+	for i, tt := range testSets {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, tt.req)
+
+		if recorder.Code != tt.status {
+			t.Errorf("Unexpected status code is returned on test #%d: %d.", i+1, recorder.Code)
+		}
+
+		if len(enqueued) != tt.enqueued {
+			t.Errorf("Unexpected number of Inputs are enqueued on test #%d: %d.", i+1, len(enqueued))
+		}
+	}
+
+	// The request is rejected after the Bot stops.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder := httptest.NewRecorder()
+	adapter.interactionsHandler(ctx, func(_ sarah.Input) error { return nil }).ServeHTTP(recorder, request("secret", `{}`))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected status code is returned: %d.", recorder.Code)
+	}
+}
@@ -0,0 +1,87 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/golack/v2"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"net/url"
+	"strings"
+)
+
+// Reaction is a content of sarah.CommandResponse that adds an emoji reaction to a message via reactions.add.
+// Ack-style Commands such as "deployed" may prefer a reaction to the triggering message over a text message.
+// Use RespWithReaction to build a response with this content.
+//
+// When Message is set, the message is posted after the reaction is added.
+type Reaction struct {
+	// Channel is the channel where the reacting message is posted.
+	Channel event.ChannelID
+
+	// TimeStamp is the timestamp of the reacting message.
+	TimeStamp string
+
+	// Name is the name of the emoji without surrounding colons. e.g. white_check_mark
+	Name string
+
+	// Message is an optional message to post in addition to the reaction.
+	Message *webapi.PostMessage
+}
+
+// ReactionClient is an optional interface that a SlackClient given to WithSlackClient can implement to add a reaction.
+// The SlackClient that Adapter builds with golack supports reactions without this.
+type ReactionClient interface {
+	AddReaction(ctx context.Context, reaction *Reaction) (*webapi.APIResponse, error)
+}
+
+// webReactionClient is a ReactionClient implementation that calls reactions.add with golack's WebClient.
+type webReactionClient struct {
+	web golack.WebClient
+}
+
+var _ ReactionClient = (*webReactionClient)(nil)
+
+// AddReaction adds the given reaction.
+//
+// See https://api.slack.com/methods/reactions.add for official document.
+func (c *webReactionClient) AddReaction(ctx context.Context, reaction *Reaction) (*webapi.APIResponse, error) {
+	params := url.Values{}
+	params.Set("channel", reaction.Channel.String())
+	params.Set("timestamp", reaction.TimeStamp)
+	params.Set("name", reaction.Name)
+
+	response := &webapi.APIResponse{}
+	err := c.web.Post(ctx, "reactions.add", params, response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// RespWithReaction adds the given emoji reaction to the triggering message.
+// The emoji can be given with or without surrounding colons such as ":white_check_mark:" or "white_check_mark."
+// When NewResponse is called with an empty text and without attachments, only the reaction is sent;
+// otherwise, the message is posted in addition to the reaction.
+func RespWithReaction(emoji string) RespOption {
+	return func(options *respOptions) {
+		options.reaction = strings.Trim(emoji, ":")
+	}
+}
+
+// addReaction adds the given reaction with the Adapter's ReactionClient.
+func (adapter *Adapter) addReaction(ctx context.Context, reaction *Reaction) error {
+	if adapter.reactions == nil {
+		return fmt.Errorf("%T does not support reactions", adapter.client)
+	}
+
+	resp, err := adapter.reactions.AddReaction(ctx, reaction)
+	if err != nil {
+		return err
+	}
+
+	if !resp.OK && resp.Error != "already_reacted" {
+		return fmt.Errorf("failed reactions.add request: %s", resp.Error)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"net/url"
+	"testing"
+)
+
+type DummyReactionClient struct {
+	DummyClient
+	AddReactionFunc func(context.Context, *Reaction) (*webapi.APIResponse, error)
+}
+
+func (client *DummyReactionClient) AddReaction(ctx context.Context, reaction *Reaction) (*webapi.APIResponse, error) {
+	return client.AddReactionFunc(ctx, reaction)
+}
+
+func TestRespWithReaction(t *testing.T) {
+	options := &respOptions{}
+	RespWithReaction(":white_check_mark:")(options)
+
+	if options.reaction != "white_check_mark" {
+		t.Errorf("Unexpected reaction is set: %s.", options.reaction)
+	}
+}
+
+func TestNewResponse_WithReaction(t *testing.T) {
+	input := &Input{
+		channelID: "C123",
+		timestamp: &event.TimeStamp{OriginalValue: "1355517523.000005"},
+	}
+
+	res, err := NewResponse(input, "", RespWithReaction("rocket"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	reaction, ok := res.Content.(*Reaction)
+	if !ok {
+		t.Fatalf("Unexpected content is returned: %#v.", res.Content)
+	}
+	if reaction.Channel != "C123" || reaction.TimeStamp != "1355517523.000005" || reaction.Name != "rocket" {
+		t.Errorf("Unexpected reaction is returned: %#v.", reaction)
+	}
+	if reaction.Message != nil {
+		t.Errorf("Message should not be set: %#v.", reaction.Message)
+	}
+
+	res, err = NewResponse(input, "deployed", RespWithReaction("rocket"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	reaction = res.Content.(*Reaction)
+	if reaction.Message == nil || reaction.Message.Text != "deployed" {
+		t.Errorf("Unexpected message is set: %#v.", reaction.Message)
+	}
+}
+
+func Test_webReactionClient_AddReaction(t *testing.T) {
+	var params url.Values
+	client := &webReactionClient{
+		web: &DummyWebClient{
+			PostFunc: func(_ context.Context, method string, payload interface{}, response interface{}) error {
+				if method != "reactions.add" {
+					t.Errorf("Unexpected method is called: %s.", method)
+				}
+				params = payload.(url.Values)
+				response.(*webapi.APIResponse).OK = true
+				return nil
+			},
+		},
+	}
+
+	resp, err := client.AddReaction(context.TODO(), &Reaction{Channel: "C123", TimeStamp: "123.456", Name: "rocket"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if !resp.OK {
+		t.Error("Unexpected response is returned.")
+	}
+	if params.Get("channel") != "C123" || params.Get("timestamp") != "123.456" || params.Get("name") != "rocket" {
+		t.Errorf("Unexpected parameters are given: %#v.", params)
+	}
+
+	client.web = &DummyWebClient{
+		PostFunc: func(_ context.Context, _ string, _ interface{}, _ interface{}) error {
+			return errors.New("dummy")
+		},
+	}
+	if _, err := client.AddReaction(context.TODO(), &Reaction{}); err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestAdapter_addReaction(t *testing.T) {
+	testSets := []struct {
+		response *webapi.APIResponse
+		err      error
+		failed   bool
+	}{
+		{response: &webapi.APIResponse{OK: true}},
+		{response: &webapi.APIResponse{OK: false, Error: "already_reacted"}},
+		{response: &webapi.APIResponse{OK: false, Error: "invalid_name"}, failed: true},
+		{err: errors.New("dummy"), failed: true},
+	}
+
+	for i, tt := range testSets {
+		adapter := &Adapter{
+			reactions: &DummyReactionClient{
+				AddReactionFunc: func(_ context.Context, _ *Reaction) (*webapi.APIResponse, error) {
+					return tt.response, tt.err
+				},
+			},
+		}
+
+		err := adapter.addReaction(context.TODO(), &Reaction{})
+		if tt.failed != (err != nil) {
+			t.Errorf("Unexpected result on test #%d: %#v.", i+1, err)
+		}
+	}
+
+	adapter := &Adapter{client: &DummyClient{}}
+	if err := adapter.addReaction(context.TODO(), &Reaction{}); err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestAdapter_SendMessage_Reaction(t *testing.T) {
+	reacted := false
+	posted := false
+	client := &DummyReactionClient{
+		DummyClient: DummyClient{
+			PostMessageFunc: func(_ context.Context, _ *webapi.PostMessage) (*webapi.APIResponse, error) {
+				posted = true
+				return &webapi.APIResponse{OK: true}, nil
+			},
+		},
+		AddReactionFunc: func(_ context.Context, _ *Reaction) (*webapi.APIResponse, error) {
+			reacted = true
+			return &webapi.APIResponse{OK: true}, nil
+		},
+	}
+	adapter, err := NewAdapter(NewConfig(), WithSlackClient(client), WithEventsPayloadHandler(DefaultEventsPayloadHandler))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(event.ChannelID("C123"), &Reaction{Name: "rocket"}))
+	if !reacted || posted {
+		t.Errorf("Unexpected calls. Reacted: %t. Posted: %t.", reacted, posted)
+	}
+
+	reacted = false
+	reaction := &Reaction{Name: "rocket", Message: webapi.NewPostMessage("C123", "deployed")}
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(event.ChannelID("C123"), reaction))
+	if !reacted || !posted {
+		t.Errorf("Unexpected calls. Reacted: %t. Posted: %t.", reacted, posted)
+	}
+}
@@ -2,27 +2,31 @@ package slack
 
 import (
 	"context"
-	"fmt"
 	"github.com/oklahomer/go-kasumi/logger"
-	"github.com/oklahomer/go-kasumi/retry"
 	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/heartbeat"
 	"github.com/oklahomer/golack/v2/event"
 	"github.com/oklahomer/golack/v2/rtmapi"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-const pingSignalChannelID = "ping"
-
 type rtmAPIAdapter struct {
 	config        *Config
 	client        SlackClient
 	handlePayload func(context.Context, *Config, rtmapi.DecodedPayload, func(sarah.Input) error)
+	outgoing      *inFlight
+	handling      inFlight
+	dropped       int64
+	connStats     *connectionStats
 }
 
 var _ apiSpecificAdapter = (*rtmAPIAdapter)(nil)
 
 func (r *rtmAPIAdapter) run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
+	backoff := heartbeat.NewBackoff(r.heartbeatConfig())
 	for {
 		conn, err := r.connect(ctx)
 		if err != nil {
@@ -32,47 +36,110 @@ func (r *rtmAPIAdapter) run(ctx context.Context, enqueueInput func(sarah.Input)
 			return
 		}
 
+		connectedAt := time.Now()
+		r.connStats.connected(connectedAt)
+
 		// Create a connection specific context so each connection-scoped goroutine can receive the connection closing signal and eventually return.
 		connCtx, connCancel := context.WithCancel(ctx)
 
-		// This channel is not subject to close. This channel can be accessed in a parallel manner with nonBlockSignal function,
-		// and the receiver is NOT waiting for a close signal. Let GC run when this channel is no longer referred.
-		//
-		// http://stackoverflow.com/a/8593986
-		// "Note that it is only necessary to close a channel if the receiver is looking for a close.
-		// Closing the channel is a control signal on the channel indicating that no more data follows."
-		tryPing := make(chan struct{}, 1)
-
-		go r.receivePayload(connCtx, conn, tryPing, enqueueInput)
+		watchdog := heartbeat.NewWatchdog(r.heartbeatConfig(), conn)
+		go r.receivePayload(connCtx, conn, watchdog, enqueueInput)
 
 		// Payload reception and other connection-related tasks must run in separate goroutines since receivePayload function
 		// internally blocks till the per-connection context is cancelled.
-		connErr := r.superviseConnection(connCtx, conn, tryPing)
+		connErr := r.superviseConnection(connCtx, watchdog)
 
 		// superviseConnection returns when parent context is canceled or the connection is hopelessly unstable.
 		// Close the current connection and do some cleanup.
-		_ = conn.Close()
 		connCancel()
 		if connErr == nil {
 			// Connection is intentionally closed by the caller.
-			// No more interaction follows.
+			// Wait for the in-flight tasks before closing the connection. No more interaction follows.
+			r.drain()
+			_ = conn.Close()
+			r.connStats.disconnected()
 			return
 		}
+		_ = conn.Close()
+		r.connStats.disconnected()
 
 		logger.Errorf("Will try re-connection due to previous connection's fatal state: %+v", connErr)
+		if time.Since(connectedAt) > r.config.MaxReconnectInterval {
+			// The connection was stable for a while, so this is not a consecutive failure.
+			backoff.Reset()
+		}
+		if backoff.Wait(ctx) != nil {
+			return
+		}
+	}
+}
+
+// heartbeatConfig builds heartbeat.Config from the given Config.
+func (r *rtmAPIAdapter) heartbeatConfig() *heartbeat.Config {
+	return &heartbeat.Config{
+		PingInterval:         r.config.PingInterval,
+		PongDeadline:         r.config.PongDeadline,
+		MaxMissedPings:       r.config.MaxMissedPings,
+		ReconnectInterval:    r.config.ReconnectInterval,
+		MaxReconnectInterval: r.config.MaxReconnectInterval,
 	}
 }
 
+// connectionStats records the statistics of RTM connections.
+// The methods are nil-safe so rtmAPIAdapter can run without recording.
+type connectionStats struct {
+	mutex           sync.RWMutex
+	connects        uint64
+	isConnected     bool
+	lastConnectedAt time.Time
+}
+
+func (s *connectionStats) connected(t time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.connects++
+	s.isConnected = true
+	s.lastConnectedAt = t
+}
+
+func (s *connectionStats) disconnected() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.isConnected = false
+}
+
+func (s *connectionStats) snapshot() *sarah.ConnectionStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats := &sarah.ConnectionStats{
+		Connected:       s.isConnected,
+		LastConnectedAt: s.lastConnectedAt,
+	}
+	if s.connects > 0 {
+		stats.Reconnects = s.connects - 1
+	}
+	return stats
+}
+
 func (r *rtmAPIAdapter) connect(ctx context.Context) (rtmapi.Connection, error) {
 	var conn rtmapi.Connection
-	err := retry.WithPolicy(r.config.RetryPolicy, func() (e error) {
+	err := sarah.Retry(ctx, r.config.Backoff, r.config.RetryPolicy, func() (e error) {
 		conn, e = r.client.ConnectRTM(ctx)
 		return e
 	})
 	return conn, err
 }
 
-func (r *rtmAPIAdapter) receivePayload(connCtx context.Context, payloadReceiver rtmapi.PayloadReceiver, tryPing chan<- struct{}, enqueueInput func(sarah.Input) error) {
+func (r *rtmAPIAdapter) receivePayload(connCtx context.Context, payloadReceiver rtmapi.PayloadReceiver, watchdog *heartbeat.Watchdog, enqueueInput func(sarah.Input) error) {
 	for {
 		select {
 		case <-connCtx.Done():
@@ -101,39 +168,67 @@ func (r *rtmAPIAdapter) receivePayload(connCtx context.Context, payloadReceiver
 			default:
 				// Connection might not be stable or is closed already.
 				logger.Infof("Try ping caused by error: %+v", err)
-				nonBlockSignal(pingSignalChannelID, tryPing)
+				watchdog.TryPing()
 				continue
 			}
 
+			// Any payload including pong proves the connection is alive.
+			watchdog.Alive()
+
 			if payload == nil {
 				continue
 			}
 
-			r.handlePayload(connCtx, r.config, payload, enqueueInput)
+			if connCtx.Err() != nil {
+				// The payload arrived after the shutdown began. Do not start handling a new payload.
+				atomic.AddInt64(&r.dropped, 1)
+				return
+			}
+
+			r.handling.begin()
+			r.handlePayload(connCtx, r.config, payload, r.countDropped(enqueueInput))
+			r.handling.end()
 		}
 	}
 }
 
-func (r *rtmAPIAdapter) superviseConnection(connCtx context.Context, payloadSender rtmapi.PayloadSender, tryPing chan struct{}) error {
-	ticker := time.NewTicker(r.config.PingInterval)
-	defer ticker.Stop()
+// countDropped wraps the given function to count the inputs that could not be enqueued.
+func (r *rtmAPIAdapter) countDropped(enqueueInput func(sarah.Input) error) func(sarah.Input) error {
+	return func(input sarah.Input) error {
+		err := enqueueInput(input)
+		if err != nil {
+			atomic.AddInt64(&r.dropped, 1)
+		}
+		return err
+	}
+}
 
-	for {
-		select {
-		case <-connCtx.Done():
-			return nil
+// drain waits for the in-flight payloads to be handed off and the pending outgoing messages to be sent within Config.DrainTimeout,
+// and then logs the summary.
+func (r *rtmAPIAdapter) drain() {
+	if r.config.DrainTimeout <= 0 {
+		return
+	}
 
-		case <-ticker.C:
-			nonBlockSignal(pingSignalChannelID, tryPing)
+	started := time.Now()
+	unhandled := r.handling.wait(r.config.DrainTimeout)
 
-		case <-tryPing:
-			logger.Debug("Send ping")
-			err := payloadSender.Ping()
-			if err != nil {
-				return fmt.Errorf("error on ping: %w", err)
-			}
+	var unsent int64
+	if r.outgoing != nil {
+		remaining := r.config.DrainTimeout - time.Since(started)
+		if remaining > 0 {
+			unsent = r.outgoing.wait(remaining)
+		} else {
+			unsent = r.outgoing.current()
 		}
 	}
+
+	logger.Infof("Drained RTM connection in %s. Unhandled payloads: %d. Unsent messages: %d. Dropped inputs: %d.",
+		time.Since(started), unhandled, unsent, atomic.LoadInt64(&r.dropped))
+}
+
+func (r *rtmAPIAdapter) superviseConnection(connCtx context.Context, watchdog *heartbeat.Watchdog) error {
+	return watchdog.Run(connCtx)
 }
 
 // DefaultRTMPayloadHandler receives incoming events, converts them to sarah.Input, and then passes them to enqueueInput.
@@ -169,6 +264,8 @@ func DefaultRTMPayloadHandler(_ context.Context, config *Config, payload rtmapi.
 			return
 		}
 
+		flagBotInput(config, input)
+
 		trimmed := strings.TrimSpace(input.Message())
 		if config.HelpCommand != "" && trimmed == config.HelpCommand {
 			// Help command
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"github.com/oklahomer/go-kasumi/retry"
 	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/heartbeat"
 	"github.com/oklahomer/golack/v2/event"
 	"github.com/oklahomer/golack/v2/rtmapi"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -129,6 +131,38 @@ func Test_rtmAPIAdapter_run(t *testing.T) {
 	})
 }
 
+func Test_connectionStats(t *testing.T) {
+	var nilStats *connectionStats
+	// Must not panic.
+	nilStats.connected(time.Now())
+	nilStats.disconnected()
+
+	stats := &connectionStats{}
+	snapshot := stats.snapshot()
+	if snapshot.Connected || snapshot.Reconnects != 0 || !snapshot.LastConnectedAt.IsZero() {
+		t.Errorf("Unexpected initial stats: %#v.", snapshot)
+	}
+
+	first := time.Now()
+	stats.connected(first)
+	stats.disconnected()
+	second := first.Add(1 * time.Minute)
+	stats.connected(second)
+
+	snapshot = stats.snapshot()
+	if !snapshot.Connected {
+		t.Error("Connected state is not recorded.")
+	}
+
+	if snapshot.Reconnects != 1 {
+		t.Errorf("Unexpected reconnect count: %d.", snapshot.Reconnects)
+	}
+
+	if !snapshot.LastConnectedAt.Equal(second) {
+		t.Errorf("Unexpected last connected time: %s.", snapshot.LastConnectedAt)
+	}
+}
+
 func Test_rtmAPIAdapter_connect(t *testing.T) {
 	t.Run("Successful case", func(t *testing.T) {
 		// Prepare an apiSpecificAdapter with a client that provides dummy connection instance.
@@ -216,7 +250,7 @@ func Test_rtmAPIAdapter_receivePayload(t *testing.T) {
 		// Run payload reception function in background.
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		go r.receivePayload(ctx, conn, make(chan struct{}), func(_ sarah.Input) error { return nil })
+		go r.receivePayload(ctx, conn, heartbeat.NewWatchdog(heartbeat.NewConfig(), nil), func(_ sarah.Input) error { return nil })
 
 		// Check payload reception.
 		select {
@@ -228,6 +262,64 @@ func Test_rtmAPIAdapter_receivePayload(t *testing.T) {
 		}
 	})
 
+	t.Run("Payload after cancellation", func(t *testing.T) {
+		// Prepare an apiSpecificAdapter that must not be called once the shutdown begins.
+		r := &rtmAPIAdapter{
+			handlePayload: func(_ context.Context, _ *Config, _ rtmapi.DecodedPayload, _ func(sarah.Input) error) {
+				t.Error("PayloadHandler should not be called.")
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		conn := &DummyConnection{
+			ReceiveFunc: func() (rtmapi.DecodedPayload, error) {
+				// The context is canceled while the connection is blocking on reception.
+				cancel()
+				return struct{}{}, nil
+			},
+		}
+
+		r.receivePayload(ctx, conn, heartbeat.NewWatchdog(heartbeat.NewConfig(), nil), func(_ sarah.Input) error { return nil })
+
+		if r.dropped != 1 {
+			t.Errorf("Unexpected number of dropped payloads: %d.", r.dropped)
+		}
+	})
+
+	t.Run("Enqueue failure", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		handled := make(chan struct{}, 1)
+		r := &rtmAPIAdapter{
+			handlePayload: func(_ context.Context, _ *Config, _ rtmapi.DecodedPayload, enqueueInput func(sarah.Input) error) {
+				_ = enqueueInput(&Input{})
+				handled <- struct{}{}
+				cancel()
+			},
+		}
+
+		conn := &DummyConnection{
+			ReceiveFunc: func() (rtmapi.DecodedPayload, error) {
+				return struct{}{}, nil
+			},
+		}
+
+		go r.receivePayload(ctx, conn, heartbeat.NewWatchdog(heartbeat.NewConfig(), nil), func(_ sarah.Input) error { return errors.New("queue is full") })
+
+		select {
+		case <-handled:
+			// O.K.
+
+		case <-time.NewTimer(10 * time.Second).C:
+			t.Fatal("PayloadHandler is not called.")
+
+		}
+
+		if n := atomic.LoadInt64(&r.dropped); n != 1 {
+			t.Errorf("Unexpected number of dropped inputs: %d.", n)
+		}
+	})
+
 	t.Run("Reception error", func(t *testing.T) {
 		// Prepare an apiSpecificAdapter that never receives a payload.
 		r := &rtmAPIAdapter{
@@ -257,7 +349,7 @@ func Test_rtmAPIAdapter_receivePayload(t *testing.T) {
 		// Run payload reception function in background.
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		go r.receivePayload(ctx, conn, make(chan struct{}), func(_ sarah.Input) error { return nil })
+		go r.receivePayload(ctx, conn, heartbeat.NewWatchdog(heartbeat.NewConfig(), nil), func(_ sarah.Input) error { return nil })
 
 		// Give long enough time to receive all errors.
 		time.Sleep(100 * time.Millisecond)
@@ -296,7 +388,7 @@ func Test_rtmAPIAdapter_superviseConnection(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		conErr := make(chan error)
 		go func() {
-			err := r.superviseConnection(ctx, conn, make(chan struct{}, 1))
+			err := r.superviseConnection(ctx, heartbeat.NewWatchdog(r.heartbeatConfig(), conn))
 			conErr <- err
 		}()
 
@@ -346,7 +438,7 @@ func Test_rtmAPIAdapter_superviseConnection(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		conErr := make(chan error)
 		go func() {
-			err := r.superviseConnection(ctx, conn, make(chan struct{}, 1))
+			err := r.superviseConnection(ctx, heartbeat.NewWatchdog(r.heartbeatConfig(), conn))
 			conErr <- err
 		}()
 
@@ -0,0 +1,100 @@
+package slack
+
+import (
+	"context"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/eventsapi"
+	"github.com/oklahomer/golack/v2/rtmapi"
+	"reflect"
+	"sync"
+)
+
+// EventHandler defines a function's signature to handle a Slack event that is registered with Adapter.On.
+// The given event is the decoded payload such as *event.PinAdded.
+type EventHandler func(ctx context.Context, ev interface{})
+
+// subscriptions holds the EventHandlers per event type.
+type subscriptions struct {
+	handlers map[reflect.Type][]EventHandler
+	mutex    sync.RWMutex
+}
+
+// eventType returns the type of the given event regardless of whether the event is given as a value or a pointer.
+func eventType(ev interface{}) reflect.Type {
+	typ := reflect.TypeOf(ev)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		return typ.Elem()
+	}
+	return typ
+}
+
+func (s *subscriptions) add(ev interface{}, handler EventHandler) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = map[reflect.Type][]EventHandler{}
+	}
+	typ := eventType(ev)
+	s.handlers[typ] = append(s.handlers[typ], handler)
+}
+
+// dispatch calls the EventHandlers that are registered for the type of the given event in the registered order.
+// A panicking EventHandler is logged and does not prevent the following EventHandlers and the payload handler from being called.
+func (s *subscriptions) dispatch(ctx context.Context, ev interface{}) {
+	if s == nil || ev == nil {
+		return
+	}
+
+	s.mutex.RLock()
+	handlers := s.handlers[eventType(ev)]
+	s.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Errorf("Panic on handling %T event: %+v", ev, r)
+				}
+			}()
+
+			handler(ctx, ev)
+		}()
+	}
+}
+
+// rtmPayloadHandler wraps the given RTM payload handler so the subscribed EventHandlers are called before the payload handler.
+func (s *subscriptions) rtmPayloadHandler(fnc func(context.Context, *Config, rtmapi.DecodedPayload, func(sarah.Input) error)) func(context.Context, *Config, rtmapi.DecodedPayload, func(sarah.Input) error) {
+	return func(ctx context.Context, config *Config, payload rtmapi.DecodedPayload, enqueueInput func(sarah.Input) error) {
+		s.dispatch(ctx, payload)
+		fnc(ctx, config, payload, enqueueInput)
+	}
+}
+
+// eventsPayloadHandler wraps the given Events API payload handler so the subscribed EventHandlers are called before the payload handler.
+func (s *subscriptions) eventsPayloadHandler(fnc func(context.Context, *Config, *eventsapi.EventWrapper, func(sarah.Input) error)) func(context.Context, *Config, *eventsapi.EventWrapper, func(sarah.Input) error) {
+	return func(ctx context.Context, config *Config, payload *eventsapi.EventWrapper, enqueueInput func(sarah.Input) error) {
+		s.dispatch(ctx, payload.Event)
+		fnc(ctx, config, payload, enqueueInput)
+	}
+}
+
+// On registers the given EventHandler to be called when an event of the same type as the given ev is received.
+// This lets a developer react to an event that DefaultRTMPayloadHandler or DefaultEventsPayloadHandler ignores
+// without replacing the payload handler as a whole.
+//
+//	slackAdapter, _ := slack.NewAdapter(slackConfig, slack.WithRTMPayloadHandler(slack.DefaultRTMPayloadHandler))
+//	slackAdapter.On(event.PinAdded{}, func(ctx context.Context, ev interface{}) {
+//		pinned := ev.(*event.PinAdded)
+//		// Do something with the pinned item.
+//	})
+//
+// The event can be given either as a value or as a pointer.
+// The registered EventHandlers are called in the registered order before the payload handler, and the payload handler still receives the event;
+// for example, an EventHandler registered for event.Message does not prevent the message from being passed to Sarah as an Input.
+// An EventHandler is called on the goroutine that receives payloads, so a time-consuming task should be run in a separate goroutine.
+// Call On before Bot runs.
+func (adapter *Adapter) On(ev interface{}, handler EventHandler) {
+	adapter.subscriptions.add(ev, handler)
+}
@@ -0,0 +1,91 @@
+package slack
+
+import (
+	"context"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/eventsapi"
+	"github.com/oklahomer/golack/v2/rtmapi"
+	"testing"
+)
+
+func TestAdapter_On(t *testing.T) {
+	adapter, err := NewAdapter(&Config{}, WithSlackClient(&DummyClient{}), WithRTMPayloadHandler(DefaultRTMPayloadHandler))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	var called []string
+	adapter.On(event.PinAdded{}, func(_ context.Context, ev interface{}) {
+		if _, ok := ev.(*event.PinAdded); !ok {
+			t.Errorf("Unexpected event is given: %#v.", ev)
+		}
+		called = append(called, "value")
+	})
+	adapter.On(&event.PinAdded{}, func(_ context.Context, _ interface{}) {
+		called = append(called, "pointer")
+	})
+
+	adapter.subscriptions.dispatch(context.TODO(), &event.PinAdded{})
+	adapter.subscriptions.dispatch(context.TODO(), &event.PinRemoved{})
+
+	if len(called) != 2 || called[0] != "value" || called[1] != "pointer" {
+		t.Errorf("Unexpected handlers are called: %#v.", called)
+	}
+}
+
+func Test_subscriptions_dispatch_Panic(t *testing.T) {
+	s := &subscriptions{}
+	called := false
+	s.add(event.PinAdded{}, func(_ context.Context, _ interface{}) {
+		panic("boom")
+	})
+	s.add(event.PinAdded{}, func(_ context.Context, _ interface{}) {
+		called = true
+	})
+
+	s.dispatch(context.TODO(), &event.PinAdded{})
+
+	if !called {
+		t.Error("Following handler is not called after a panic.")
+	}
+
+	// Does not panic.
+	var nilSubscriptions *subscriptions
+	nilSubscriptions.dispatch(context.TODO(), &event.PinAdded{})
+	s.dispatch(context.TODO(), nil)
+}
+
+func Test_subscriptions_rtmPayloadHandler(t *testing.T) {
+	s := &subscriptions{}
+	var called []string
+	s.add(event.Message{}, func(_ context.Context, _ interface{}) {
+		called = append(called, "subscription")
+	})
+
+	handler := s.rtmPayloadHandler(func(_ context.Context, _ *Config, _ rtmapi.DecodedPayload, _ func(sarah.Input) error) {
+		called = append(called, "payload")
+	})
+	handler(context.TODO(), &Config{}, &event.Message{}, func(_ sarah.Input) error { return nil })
+
+	if len(called) != 2 || called[0] != "subscription" || called[1] != "payload" {
+		t.Errorf("Unexpected handlers are called: %#v.", called)
+	}
+}
+
+func Test_subscriptions_eventsPayloadHandler(t *testing.T) {
+	s := &subscriptions{}
+	var called []string
+	s.add(event.PinAdded{}, func(_ context.Context, _ interface{}) {
+		called = append(called, "subscription")
+	})
+
+	handler := s.eventsPayloadHandler(func(_ context.Context, _ *Config, _ *eventsapi.EventWrapper, _ func(sarah.Input) error) {
+		called = append(called, "payload")
+	})
+	handler(context.TODO(), &Config{}, &eventsapi.EventWrapper{Event: &event.PinAdded{}}, func(_ sarah.Input) error { return nil })
+
+	if len(called) != 2 || called[0] != "subscription" || called[1] != "payload" {
+		t.Errorf("Unexpected handlers are called: %#v.", called)
+	}
+}
@@ -0,0 +1,258 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"sync"
+	"time"
+)
+
+// ThreadRollover declares when a StickyThread stops replying to the current parent message and starts a new thread.
+type ThreadRollover string
+
+const (
+	// RolloverNone keeps replying to the same thread forever. This is the default behavior with an empty value.
+	RolloverNone ThreadRollover = "none"
+
+	// RolloverDaily starts a new thread on the first post of each day.
+	RolloverDaily ThreadRollover = "daily"
+
+	// RolloverWeekly starts a new thread on the first post of each ISO 8601 week.
+	RolloverWeekly ThreadRollover = "weekly"
+)
+
+// period returns the identifier of the period that the given time belongs to.
+// A thread started in a different period is not continued.
+func (r ThreadRollover) period(t time.Time) string {
+	switch r {
+	case RolloverDaily:
+		return t.Format("2006-01-02")
+
+	case RolloverWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+
+	default:
+		return ""
+
+	}
+}
+
+// StickyThread is a content of sarah.Output that keeps the posts with the same Key in one thread per destination channel.
+// The first post becomes the parent message, and the following ones are posted as its replies until the period declared by Rollover changes.
+// This keeps a channel tidy when a ScheduledTask periodically posts its results. Use StickyThreadFormatter to wrap the results of a ScheduledTask.
+//
+// The references to the parent messages are stored in the sarah.KVStore given with WithThreadStore, so the threads are continued after the process restarts.
+// Posting a StickyThread requires the SlackClient to implement golack.WebClient since the parent message's timestamp must be retrieved.
+type StickyThread struct {
+	// Key identifies the thread along with the destination channel, e.g. the ScheduledTask's identifier.
+	Key string
+
+	// Rollover declares when to start a new thread.
+	Rollover ThreadRollover
+
+	// Location is used to determine the current day or week for Rollover. time.Local is used when this is nil.
+	Location *time.Location
+
+	// Content is the message to post. This can be a string, *sarah.RichContent, or *webapi.PostMessage.
+	Content interface{}
+}
+
+// StickyThreadFormatter creates and returns a sarah.TaskResultFormatter that posts the results of the ScheduledTask with the given identifier in a sticky thread.
+// The formatter is set with sarah.ScheduledTaskPropsBuilder.Formatter:
+//
+//	props := sarah.NewScheduledTaskPropsBuilder().
+//		Identifier("daily_report").
+//		Formatter(slack.StickyThreadFormatter("daily_report", slack.RolloverWeekly)).
+//		...
+//
+// The results for other Bots are passed through as-is so the task can still be shared with them.
+func StickyThreadFormatter(taskID string, rollover ThreadRollover) sarah.TaskResultFormatter {
+	return func(_ context.Context, botType sarah.BotType, result *sarah.ScheduledTaskResult) (interface{}, error) {
+		if botType != SLACK {
+			return result.Content, nil
+		}
+
+		return &StickyThread{
+			Key:      taskID,
+			Rollover: rollover,
+			Content:  result.Content,
+		}, nil
+	}
+}
+
+// WithThreadStore creates an AdapterOption with the given sarah.KVStore to persist the references to the parent messages of StickyThread.
+// Without this option, the references are kept in the process memory space and hence a new thread is started after the process restarts.
+func WithThreadStore(store sarah.KVStore) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.threads = newThreadRegistry(store)
+	}
+}
+
+// threadNamespace is the namespace of the sarah.KVStore to store the references to the parent messages.
+var threadNamespace = sarah.KVNamespace(SLACK, "sticky_threads")
+
+// threadReference is a reference to the parent message of a StickyThread.
+type threadReference struct {
+	TimeStamp string `json:"ts"`
+	Period    string `json:"period"`
+}
+
+// threadRegistry stores the references to the parent messages of StickyThread.
+type threadRegistry struct {
+	store sarah.KVStore
+
+	// mutex serializes the posts so concurrent posts with the same key do not start multiple threads.
+	mutex sync.Mutex
+}
+
+func newThreadRegistry(store sarah.KVStore) *threadRegistry {
+	return &threadRegistry{
+		store: store,
+	}
+}
+
+func (r *threadRegistry) get(ctx context.Context, key string) (*threadReference, error) {
+	value, err := r.store.Get(ctx, threadNamespace, key)
+	if errors.Is(err, sarah.ErrKVNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &threadReference{}
+	err = json.Unmarshal(value, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode thread reference: %w", err)
+	}
+	return ref, nil
+}
+
+func (r *threadRegistry) set(ctx context.Context, key string, ref *threadReference) error {
+	value, err := json.Marshal(ref)
+	if err != nil {
+		return fmt.Errorf("failed to encode thread reference: %w", err)
+	}
+	return r.store.Set(ctx, threadNamespace, key, value)
+}
+
+// threadKey returns the key to store the reference to the parent message of the given StickyThread in the given channel.
+func threadKey(thread *StickyThread, channelID event.ChannelID) string {
+	return thread.Key + "|" + channelID.String()
+}
+
+// stickyThreadMessage builds the message to post from the given StickyThread.
+// A given *webapi.PostMessage is copied so the thread's timestamp can be set without modifying the original one.
+func stickyThreadMessage(channelID event.ChannelID, thread *StickyThread) (*webapi.PostMessage, error) {
+	switch content := thread.Content.(type) {
+	case string:
+		return webapi.NewPostMessage(channelID, content), nil
+
+	case *sarah.RichContent:
+		return renderRichContent(channelID, content), nil
+
+	case *webapi.PostMessage:
+		copied := *content
+		copied.ChannelID = channelID
+		return &copied, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported content is given to sticky thread: %T", thread.Content)
+
+	}
+}
+
+// postStickyThread posts the given StickyThread as a reply to the current thread, or as a new parent message when no thread is available for the current period.
+func (adapter *Adapter) postStickyThread(ctx context.Context, channelID event.ChannelID, thread *StickyThread) error {
+	if adapter.web == nil {
+		return fmt.Errorf("%T does not support Web API calls to post in a thread", adapter.client)
+	}
+	if adapter.threads == nil {
+		return errors.New("thread store is not set")
+	}
+
+	message, err := stickyThreadMessage(channelID, thread)
+	if err != nil {
+		return err
+	}
+
+	location := thread.Location
+	if location == nil {
+		location = time.Local
+	}
+	period := thread.Rollover.period(time.Now().In(location))
+	key := threadKey(thread, channelID)
+
+	adapter.threads.mutex.Lock()
+	defer adapter.threads.mutex.Unlock()
+
+	ref, err := adapter.threads.get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get thread reference for %s: %w", key, err)
+	}
+
+	if ref != nil && ref.Period == period {
+		message.ThreadTimeStamp = ref.TimeStamp
+		_, err := adapter.postThreadMessage(ctx, message)
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *threadAPIError
+		if !errors.As(err, &apiErr) || apiErr.code != "thread_not_found" {
+			return err
+		}
+
+		// The parent message is deleted. Start a new thread.
+		sarah.LoggerFromContext(ctx).Warnf("Parent message of sticky thread %s is not found. Starting a new thread.", key)
+		message.ThreadTimeStamp = ""
+	}
+
+	ts, err := adapter.postThreadMessage(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	err = adapter.threads.set(ctx, key, &threadReference{
+		TimeStamp: ts,
+		Period:    period,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store thread reference for %s: %w", key, err)
+	}
+	return nil
+}
+
+// threadAPIError represents an error response from chat.postMessage.
+type threadAPIError struct {
+	code string
+}
+
+func (e *threadAPIError) Error() string {
+	return fmt.Sprintf("failed chat.postMessage request: %s", e.code)
+}
+
+// postThreadMessage posts the given message via chat.postMessage, and then returns the timestamp of the posted message.
+//
+// See https://api.slack.com/methods/chat.postMessage
+func (adapter *Adapter) postThreadMessage(ctx context.Context, message *webapi.PostMessage) (string, error) {
+	resp := &struct {
+		webapi.APIResponse
+		TimeStamp string `json:"ts"`
+	}{}
+	err := adapter.web.Post(ctx, "chat.postMessage", message, resp)
+	if err != nil {
+		return "", err
+	}
+
+	if !resp.OK {
+		return "", &threadAPIError{code: resp.Error}
+	}
+	return resp.TimeStamp, nil
+}
@@ -0,0 +1,262 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"testing"
+	"time"
+)
+
+func TestThreadRollover_period(t *testing.T) {
+	now := time.Date(2021, time.January, 3, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rollover ThreadRollover
+		expected string
+	}{
+		{
+			rollover: "",
+			expected: "",
+		},
+		{
+			rollover: RolloverNone,
+			expected: "",
+		},
+		{
+			rollover: RolloverDaily,
+			expected: "2021-01-03",
+		},
+		{
+			// 2021-01-03 belongs to the last week of 2020 in ISO 8601.
+			rollover: RolloverWeekly,
+			expected: "2020-W53",
+		},
+	}
+
+	for i, tt := range tests {
+		period := tt.rollover.period(now)
+		if period != tt.expected {
+			t.Errorf("Unexpected period is returned on test #%d: %s.", i, period)
+		}
+	}
+}
+
+func TestStickyThreadFormatter(t *testing.T) {
+	formatter := StickyThreadFormatter("report", RolloverDaily)
+	result := &sarah.ScheduledTaskResult{Content: "hello"}
+
+	content, err := formatter(context.TODO(), SLACK, result)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	thread, ok := content.(*StickyThread)
+	if !ok {
+		t.Fatalf("Unexpected content is returned: %#v.", content)
+	}
+	if thread.Key != "report" || thread.Rollover != RolloverDaily || thread.Content != "hello" {
+		t.Errorf("Unexpected thread is returned: %#v.", thread)
+	}
+
+	content, err = formatter(context.TODO(), "other", result)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if content != "hello" {
+		t.Errorf("Content for other Bot should be passed through: %#v.", content)
+	}
+}
+
+func TestWithThreadStore(t *testing.T) {
+	store := sarah.NewInMemoryKVStore()
+	adapter := &Adapter{}
+	WithThreadStore(store)(adapter)
+
+	if adapter.threads == nil || adapter.threads.store != store {
+		t.Error("Given store is not set.")
+	}
+}
+
+func Test_stickyThreadMessage(t *testing.T) {
+	var channelID event.ChannelID = "C123"
+
+	original := webapi.NewPostMessage("C999", "original")
+	tests := []struct {
+		content interface{}
+		text    string
+		err     bool
+	}{
+		{
+			content: "text",
+			text:    "text",
+		},
+		{
+			content: &sarah.RichContent{Text: "rich"},
+			text:    "rich",
+		},
+		{
+			content: original,
+			text:    "original",
+		},
+		{
+			content: struct{}{},
+			err:     true,
+		},
+	}
+
+	for i, tt := range tests {
+		message, err := stickyThreadMessage(channelID, &StickyThread{Content: tt.content})
+		if tt.err {
+			if err == nil {
+				t.Errorf("Expected error is not returned on test #%d.", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error is returned on test #%d: %s.", i, err.Error())
+			continue
+		}
+		if message.ChannelID != channelID || message.Text != tt.text {
+			t.Errorf("Unexpected message is returned on test #%d: %#v.", i, message)
+		}
+	}
+
+	if original.ChannelID != "C999" {
+		t.Error("Given message should not be modified.")
+	}
+}
+
+func TestAdapter_SendMessage_StickyThread(t *testing.T) {
+	var posted []*webapi.PostMessage
+	adapter := &Adapter{
+		config:  NewConfig(),
+		threads: newThreadRegistry(sarah.NewInMemoryKVStore()),
+		web: &DummyWebClient{
+			PostFunc: func(_ context.Context, slackMethod string, payload interface{}, response interface{}) error {
+				if slackMethod != "chat.postMessage" {
+					t.Errorf("Unexpected method is called: %s.", slackMethod)
+				}
+				posted = append(posted, payload.(*webapi.PostMessage))
+				return json.Unmarshal([]byte(`{"ok": true, "ts": "1355517536.000001"}`), response)
+			},
+		},
+	}
+
+	send := func(channelID event.ChannelID, key string, rollover ThreadRollover) {
+		thread := &StickyThread{Key: key, Rollover: rollover, Content: "result"}
+		adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(channelID, thread))
+	}
+	send("C123", "report", RolloverDaily)
+	send("C123", "report", RolloverDaily)
+	send("C456", "report", RolloverDaily)
+	send("C123", "other", RolloverNone)
+
+	expected := []string{"", "1355517536.000001", "", ""}
+	if len(posted) != len(expected) {
+		t.Fatalf("Unexpected number of messages are posted: %d.", len(posted))
+	}
+	for i, ts := range expected {
+		if posted[i].ThreadTimeStamp != ts {
+			t.Errorf("Unexpected thread timestamp is set on message #%d: %s.", i, posted[i].ThreadTimeStamp)
+		}
+	}
+}
+
+func TestAdapter_postStickyThread(t *testing.T) {
+	t.Run("Rollover", func(t *testing.T) {
+		store := sarah.NewInMemoryKVStore()
+		adapter := &Adapter{
+			threads: newThreadRegistry(store),
+			web: &DummyWebClient{
+				PostFunc: func(_ context.Context, _ string, payload interface{}, response interface{}) error {
+					if payload.(*webapi.PostMessage).ThreadTimeStamp != "" {
+						t.Error("Reply should not be posted to the thread of the previous period.")
+					}
+					return json.Unmarshal([]byte(`{"ok": true, "ts": "2.000001"}`), response)
+				},
+			},
+		}
+		_ = adapter.threads.set(context.TODO(), "report|C123", &threadReference{TimeStamp: "1.000001", Period: "2000-01-01"})
+
+		err := adapter.postStickyThread(context.TODO(), "C123", &StickyThread{Key: "report", Rollover: RolloverDaily, Content: "result"})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		ref, err := adapter.threads.get(context.TODO(), "report|C123")
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		if ref.TimeStamp != "2.000001" || ref.Period == "2000-01-01" {
+			t.Errorf("Reference is not updated: %#v.", ref)
+		}
+	})
+
+	t.Run("Parent message is deleted", func(t *testing.T) {
+		var posted []string
+		adapter := &Adapter{
+			threads: newThreadRegistry(sarah.NewInMemoryKVStore()),
+			web: &DummyWebClient{
+				PostFunc: func(_ context.Context, _ string, payload interface{}, response interface{}) error {
+					ts := payload.(*webapi.PostMessage).ThreadTimeStamp
+					posted = append(posted, ts)
+					if ts != "" {
+						return json.Unmarshal([]byte(`{"ok": false, "error": "thread_not_found"}`), response)
+					}
+					return json.Unmarshal([]byte(`{"ok": true, "ts": "2.000001"}`), response)
+				},
+			},
+		}
+		_ = adapter.threads.set(context.TODO(), "report|C123", &threadReference{TimeStamp: "1.000001"})
+
+		err := adapter.postStickyThread(context.TODO(), "C123", &StickyThread{Key: "report", Content: "result"})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if len(posted) != 2 || posted[0] != "1.000001" || posted[1] != "" {
+			t.Errorf("Unexpected messages are posted: %#v.", posted)
+		}
+		ref, _ := adapter.threads.get(context.TODO(), "report|C123")
+		if ref == nil || ref.TimeStamp != "2.000001" {
+			t.Errorf("Reference is not updated: %#v.", ref)
+		}
+	})
+
+	t.Run("Error response", func(t *testing.T) {
+		adapter := &Adapter{
+			threads: newThreadRegistry(sarah.NewInMemoryKVStore()),
+			web: &DummyWebClient{
+				PostFunc: func(_ context.Context, _ string, _ interface{}, response interface{}) error {
+					return json.Unmarshal([]byte(`{"ok": false, "error": "channel_not_found"}`), response)
+				},
+			},
+		}
+
+		err := adapter.postStickyThread(context.TODO(), "C123", &StickyThread{Key: "report", Content: "result"})
+		var apiErr *threadAPIError
+		if !errors.As(err, &apiErr) || apiErr.code != "channel_not_found" {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+
+		ref, _ := adapter.threads.get(context.TODO(), "report|C123")
+		if ref != nil {
+			t.Errorf("Reference should not be stored: %#v.", ref)
+		}
+	})
+
+	t.Run("Without WebClient", func(t *testing.T) {
+		adapter := &Adapter{
+			client:  &DummyClient{},
+			threads: newThreadRegistry(sarah.NewInMemoryKVStore()),
+		}
+
+		err := adapter.postStickyThread(context.TODO(), "C123", &StickyThread{Key: "report", Content: "result"})
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// TokenProvider provides the token to call Slack Web API on behalf of the given workspace.
+// Set an implementation to Config.TokenProvider when the token differs per workspace or rotates.
+// Because the token is requested on every Web API call, an implementation should cache the token and refresh it before it expires.
+//
+// The given teamID is empty when the workspace is unknown, such as when the Adapter connects to RTM API.
+type TokenProvider interface {
+	Token(ctx context.Context, teamID string) (string, error)
+}
+
+// TokenProviderFunc is a function type that satisfies TokenProvider.
+type TokenProviderFunc func(ctx context.Context, teamID string) (string, error)
+
+var _ TokenProvider = TokenProviderFunc(nil)
+
+// Token calls the function itself.
+func (f TokenProviderFunc) Token(ctx context.Context, teamID string) (string, error) {
+	return f(ctx, teamID)
+}
+
+type teamIDKey struct{}
+
+// WithTeamID returns a new context that tells the workspace to call Slack Web API for.
+// The Adapter sets this to send a response to the workspace where the Input is sent,
+// but a Command that calls Web API directly with Adapter.WebClient should set this with the Input's TeamID when multiple workspaces are served.
+func WithTeamID(ctx context.Context, teamID string) context.Context {
+	return context.WithValue(ctx, teamIDKey{}, teamID)
+}
+
+// TeamIDFromContext returns the workspace ID set by WithTeamID. An empty string is returned when none is set.
+func TeamIDFromContext(ctx context.Context) string {
+	teamID, _ := ctx.Value(teamIDKey{}).(string)
+	return teamID
+}
+
+// tokenWebClient is a golack.WebClient implementation that calls Slack Web API with the token provided by TokenProvider on every call.
+type tokenWebClient struct {
+	provider       TokenProvider
+	requestTimeout time.Duration
+	httpClient     *http.Client
+}
+
+var _ golack.WebClient = (*tokenWebClient)(nil)
+
+func (c *tokenWebClient) client(ctx context.Context) (*webapi.Client, error) {
+	teamID := TeamIDFromContext(ctx)
+	token, err := c.provider.Token(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for workspace %q: %w", teamID, err)
+	}
+
+	config := webapi.NewConfig()
+	config.Token = token
+	if c.requestTimeout != 0 {
+		config.RequestTimeout = c.requestTimeout
+	}
+	var options []webapi.ClientOption
+	if c.httpClient != nil {
+		options = append(options, webapi.WithHTTPClient(c.httpClient))
+	}
+	return webapi.NewClient(config, options...), nil
+}
+
+// Get calls the given Web API method with the token of the workspace set to the context.
+func (c *tokenWebClient) Get(ctx context.Context, slackMethod string, queryParams url.Values, response interface{}) error {
+	client, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Get(ctx, slackMethod, queryParams, response)
+}
+
+// Post calls the given Web API method with the token of the workspace set to the context.
+func (c *tokenWebClient) Post(ctx context.Context, slackMethod string, payload interface{}, response interface{}) error {
+	client, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Post(ctx, slackMethod, payload, response)
+}
+
+// teamRegistry remembers which workspace each channel and user belongs to,
+// so a message sent to a channel is posted with the token of the corresponding workspace.
+// The methods are nil-safe.
+type teamRegistry struct {
+	teams map[string]string
+	mutex sync.RWMutex
+}
+
+func newTeamRegistry() *teamRegistry {
+	return &teamRegistry{
+		teams: map[string]string{},
+	}
+}
+
+// record remembers the workspace of the channel and the user of the given Input.
+func (r *teamRegistry) record(teamID string, input sarah.Input) {
+	if r == nil || teamID == "" {
+		return
+	}
+
+	var ids []string
+	if channelID, ok := input.ReplyTo().(event.ChannelID); ok && channelID != "" {
+		ids = append(ids, channelID.String())
+	}
+	if sourced, ok := input.(sarah.SourcedInput); ok && sourced.UserID() != "" {
+		ids = append(ids, sourced.UserID())
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, id := range ids {
+		r.teams[id] = teamID
+	}
+}
+
+func (r *teamRegistry) lookup(id string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	teamID, ok := r.teams[id]
+	return teamID, ok
+}
+
+// teamContext returns a context with the workspace of the given destination unless the workspace is already set.
+func (r *teamRegistry) teamContext(ctx context.Context, destination sarah.OutputDestination) context.Context {
+	if TeamIDFromContext(ctx) != "" {
+		return ctx
+	}
+
+	channelID, ok := destination.(event.ChannelID)
+	if !ok {
+		return ctx
+	}
+
+	teamID, ok := r.lookup(channelID.String())
+	if !ok {
+		return ctx
+	}
+	return WithTeamID(ctx, teamID)
+}
+
+// bindTeam sets the given workspace ID to the Input this package provides.
+func bindTeam(input sarah.Input, teamID string) {
+	switch typed := input.(type) {
+	case *Input:
+		typed.teamID = teamID
+
+	case *AppHomeInput:
+		typed.teamID = teamID
+
+	case *BlockActionInput:
+		typed.teamID = teamID
+
+	case *ViewInput:
+		typed.teamID = teamID
+
+	}
+}
+
+// teamAware wraps the given function so each Input is bound to the given workspace and the workspace of its channel and user is remembered.
+func (r *teamRegistry) teamAware(teamID string, enqueueInput func(sarah.Input) error) func(sarah.Input) error {
+	if teamID == "" {
+		return enqueueInput
+	}
+
+	return func(input sarah.Input) error {
+		// HelpInput and AbortInput are created by the payload handler from the original Input.
+		original := input
+		switch typed := input.(type) {
+		case *sarah.HelpInput:
+			original = typed.OriginalInput
+
+		case *sarah.AbortInput:
+			original = typed.OriginalInput
+
+		}
+
+		bindTeam(original, teamID)
+		r.record(teamID, original)
+		return enqueueInput(input)
+	}
+}
@@ -0,0 +1,210 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2"
+	"github.com/oklahomer/golack/v2/event"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyRoundTripper struct {
+	RoundTripFunc func(*http.Request) (*http.Response, error)
+}
+
+func (rt *DummyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt.RoundTripFunc(req)
+}
+
+func TestTeamIDFromContext(t *testing.T) {
+	if TeamIDFromContext(context.TODO()) != "" {
+		t.Error("Empty team ID should be returned.")
+	}
+
+	ctx := WithTeamID(context.TODO(), "T123")
+	if TeamIDFromContext(ctx) != "T123" {
+		t.Errorf("Unexpected team ID is returned: %s.", TeamIDFromContext(ctx))
+	}
+}
+
+func Test_tokenWebClient(t *testing.T) {
+	var authorizations []string
+	client := &tokenWebClient{
+		provider: TokenProviderFunc(func(_ context.Context, teamID string) (string, error) {
+			if teamID == "" {
+				return "", errors.New("unknown workspace")
+			}
+			return "token-" + teamID, nil
+		}),
+		requestTimeout: time.Second,
+		httpClient: &http.Client{
+			Transport: &DummyRoundTripper{
+				RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+					authorizations = append(authorizations, req.Header.Get("Authorization"))
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"ok": true}`)),
+					}, nil
+				},
+			},
+		},
+	}
+
+	err := client.Get(WithTeamID(context.TODO(), "T123"), "auth.test", nil, &struct{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	err = client.Post(WithTeamID(context.TODO(), "T456"), "views.publish", &struct{}{}, &struct{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(authorizations) != 2 || authorizations[0] != "Bearer token-T123" || authorizations[1] != "Bearer token-T456" {
+		t.Errorf("Unexpected tokens are used: %#v.", authorizations)
+	}
+
+	err = client.Post(context.TODO(), "views.publish", &struct{}{}, &struct{}{})
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func Test_teamRegistry(t *testing.T) {
+	registry := newTeamRegistry()
+	registry.record("T123", &Input{channelID: "C123", userID: "U123"})
+	registry.record("", &Input{channelID: "C456", userID: "U456"})
+
+	for _, id := range []string{"C123", "U123"} {
+		if teamID, ok := registry.lookup(id); !ok || teamID != "T123" {
+			t.Errorf("Unexpected team ID is returned for %s: %s.", id, teamID)
+		}
+	}
+
+	if _, ok := registry.lookup("C456"); ok {
+		t.Error("Empty team ID should not be recorded.")
+	}
+
+	ctx := registry.teamContext(context.TODO(), event.ChannelID("C123"))
+	if TeamIDFromContext(ctx) != "T123" {
+		t.Errorf("Unexpected team ID is set: %s.", TeamIDFromContext(ctx))
+	}
+
+	ctx = registry.teamContext(WithTeamID(context.TODO(), "T999"), event.ChannelID("C123"))
+	if TeamIDFromContext(ctx) != "T999" {
+		t.Errorf("Explicitly set team ID should be preferred: %s.", TeamIDFromContext(ctx))
+	}
+
+	ctx = registry.teamContext(context.TODO(), event.ChannelID("C789"))
+	if TeamIDFromContext(ctx) != "" {
+		t.Errorf("Team ID should not be set for unknown channel: %s.", TeamIDFromContext(ctx))
+	}
+
+	// Does not panic.
+	var nilRegistry *teamRegistry
+	nilRegistry.record("T123", &Input{channelID: "C123"})
+	_ = nilRegistry.teamContext(context.TODO(), event.ChannelID("C123"))
+}
+
+func Test_teamRegistry_teamAware(t *testing.T) {
+	registry := newTeamRegistry()
+	var enqueued []sarah.Input
+	enqueueInput := func(input sarah.Input) error {
+		enqueued = append(enqueued, input)
+		return nil
+	}
+
+	input := &Input{channelID: "C123", userID: "U123"}
+	_ = registry.teamAware("T123", enqueueInput)(&sarah.HelpInput{OriginalInput: input})
+	if input.TeamID() != "T123" {
+		t.Errorf("Team ID is not bound to the original Input: %s.", input.TeamID())
+	}
+	if _, ok := enqueued[0].(*sarah.HelpInput); !ok {
+		t.Errorf("Given Input is not passed as-is: %T.", enqueued[0])
+	}
+
+	testSets := []interface {
+		sarah.Input
+		TeamID() string
+	}{
+		&AppHomeInput{Event: &event.AppHomeOpened{ChannelID: "D123", UserID: "U123"}},
+		&BlockActionInput{userID: "U123"},
+		&ViewInput{senderKey: "|U123", userID: "U123"},
+	}
+	for i, tt := range testSets {
+		_ = registry.teamAware("T123", enqueueInput)(tt)
+		if tt.TeamID() != "T123" {
+			t.Errorf("Team ID is not bound on test #%d: %s.", i+1, tt.TeamID())
+		}
+	}
+
+	// Without a team ID, the given function is returned as-is.
+	other := &Input{}
+	_ = registry.teamAware("", enqueueInput)(other)
+	if other.TeamID() != "" {
+		t.Errorf("Unexpected team ID is bound: %s.", other.TeamID())
+	}
+}
+
+func TestNewAdapter_TokenProvider(t *testing.T) {
+	config := &Config{
+		TokenProvider: TokenProviderFunc(func(_ context.Context, _ string) (string, error) {
+			return "token", nil
+		}),
+	}
+	adapter, err := NewAdapter(config, WithEventsPayloadHandler(DefaultEventsPayloadHandler))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	g, ok := adapter.client.(*golack.Golack)
+	if !ok {
+		t.Fatalf("Unexpected client is set: %T.", adapter.client)
+	}
+
+	if _, ok := g.WebClient.(*tokenWebClient); !ok {
+		t.Errorf("Unexpected WebClient is set: %T.", g.WebClient)
+	}
+}
+
+func Test_eventsAPIAdapter_handler_TeamID(t *testing.T) {
+	enqueued := make(chan sarah.Input, 1)
+	adapter := &eventsAPIAdapter{
+		config: &Config{
+			AppSecret:                 "secret",
+			RequestTimestampTolerance: 5 * time.Minute,
+			EventDedupeWindow:         time.Minute,
+		},
+		handlePayload: DefaultEventsPayloadHandler,
+		teams:         newTeamRegistry(),
+	}
+	handler := adapter.handler(context.TODO(), func(input sarah.Input) error {
+		enqueued <- input
+		return nil
+	})
+
+	body := `{"type":"event_callback","team_id":"T123","event_id":"Ev123","event":{"type":"message","channel":"C123","user":"U123","text":"Hello","ts":"1355517523.000005"}}`
+	handler.ServeHTTP(httptest.NewRecorder(), signedRequest("secret", time.Now(), body))
+
+	select {
+	case input := <-enqueued:
+		if input.(*Input).TeamID() != "T123" {
+			t.Errorf("Unexpected team ID is bound: %s.", input.(*Input).TeamID())
+		}
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Fatal("Input is not enqueued.")
+
+	}
+
+	if teamID, _ := adapter.teams.lookup("C123"); teamID != "T123" {
+		t.Errorf("Workspace of the channel is not recorded: %s.", teamID)
+	}
+
+}
@@ -0,0 +1,260 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"github.com/patrickmn/go-cache"
+	"time"
+)
+
+// View represents a view to publish on the App Home or to open as a modal.
+// Use NewHomeView or NewModalView to build one.
+//
+// See https://api.slack.com/reference/surfaces/views
+type View struct {
+	Type            string                       `json:"type"`
+	Title           *event.TextCompositionObject `json:"title,omitempty"`
+	Submit          *event.TextCompositionObject `json:"submit,omitempty"`
+	Close           *event.TextCompositionObject `json:"close,omitempty"`
+	Blocks          []event.Block                `json:"blocks"`
+	PrivateMetadata string                       `json:"private_metadata,omitempty"`
+	CallbackID      string                       `json:"callback_id,omitempty"`
+	ClearOnClose    bool                         `json:"clear_on_close,omitempty"`
+	NotifyOnClose   bool                         `json:"notify_on_close,omitempty"`
+	ExternalID      string                       `json:"external_id,omitempty"`
+}
+
+// NewHomeView creates a new View to publish on the App Home with the given blocks.
+func NewHomeView(blocks []event.Block) *View {
+	return &View{
+		Type:   "home",
+		Blocks: blocks,
+	}
+}
+
+// NewModalView creates a new View to open as a modal with the given callback ID, title, and blocks.
+// The callback ID is returned by ViewInput.Message when the modal is submitted.
+// A modal with input blocks must have a submit button; use View.WithSubmit to set one.
+func NewModalView(callbackID string, title string, blocks []event.Block) *View {
+	return &View{
+		Type:       "modal",
+		Title:      event.NewPlainTextCompositionObject(title),
+		Blocks:     blocks,
+		CallbackID: callbackID,
+	}
+}
+
+// WithSubmit sets the text of the submit button.
+func (v *View) WithSubmit(text string) *View {
+	v.Submit = event.NewPlainTextCompositionObject(text)
+	return v
+}
+
+// WithClose sets the text of the close button.
+func (v *View) WithClose(text string) *View {
+	v.Close = event.NewPlainTextCompositionObject(text)
+	return v
+}
+
+// WithPrivateMetadata sets the string that is returned with ViewInput.
+func (v *View) WithPrivateMetadata(metadata string) *View {
+	v.PrivateMetadata = metadata
+	return v
+}
+
+// WithNotifyOnClose lets Slack notify when the modal is closed without submission.
+// The notification aborts the conversation that is continued with RespWithNext. See ViewInput.
+func (v *View) WithNotifyOnClose(notify bool) *View {
+	v.NotifyOnClose = notify
+	return v
+}
+
+// HomeView is a content of sarah.CommandResponse that publishes the given View on the user's App Home via views.publish.
+// Use NewHomeViewResponse to build a response with this content.
+type HomeView struct {
+	// UserID is the user whose App Home is updated.
+	UserID event.UserID
+
+	// View is the view to publish.
+	View *View
+}
+
+// Modal is a content of sarah.CommandResponse that opens the given View as a modal via views.open.
+// Use NewModalResponse to build a response with this content.
+type Modal struct {
+	// TriggerID is the trigger ID of the interaction that opens the modal.
+	TriggerID string
+
+	// View is the view to open.
+	View *View
+
+	// senderKey is the SenderKey of the Input that opens the modal.
+	senderKey string
+}
+
+// NewHomeViewResponse creates *sarah.CommandResponse that publishes the given View on the App Home of the user who sent the given Input.
+// The Input must be one of the Inputs this package provides such as AppHomeInput.
+// Among the RespOption values, only RespWithNext and RespWithNextSerializable are effective.
+func NewHomeViewResponse(input sarah.Input, view *View, options ...RespOption) (*sarah.CommandResponse, error) {
+	sourced, ok := input.(sarah.SourcedInput)
+	if !ok {
+		return nil, fmt.Errorf("%T is not currently supported to automatically generate response", input)
+	}
+
+	stash := &respOptions{}
+	for _, opt := range options {
+		opt(stash)
+	}
+
+	return &sarah.CommandResponse{
+		Content: &HomeView{
+			UserID: event.UserID(sourced.UserID()),
+			View:   view,
+		},
+		UserContext: stash.userContext,
+	}, nil
+}
+
+// NewModalResponse creates *sarah.CommandResponse that opens the given View as a modal.
+// The Input must carry a trigger ID, which means the Input must be BlockActionInput or ViewInput.
+// Because the trigger ID expires in 3 seconds, the Command must respond immediately.
+//
+// Among the RespOption values, only RespWithNext and RespWithNextSerializable are effective.
+// The submission of the modal is passed to the function given to RespWithNext as ViewInput, so a form can be implemented as a conversation:
+//
+//	func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+//		view := slack.NewModalView("feedback", "Feedback", blocks).WithSubmit("Send")
+//		return slack.NewModalResponse(input, view, slack.RespWithNext(func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+//			submitted := input.(*slack.ViewInput)
+//			comment := submitted.View().State.Value("comment", "comment_input")
+//			return slack.NewResponse(input, "Thanks for the feedback: "+comment)
+//		}))
+//	}
+func NewModalResponse(input sarah.Input, view *View, options ...RespOption) (*sarah.CommandResponse, error) {
+	triggered, ok := input.(interface{ TriggerID() string })
+	if !ok {
+		return nil, fmt.Errorf("%T does not carry a trigger ID to open a modal", input)
+	}
+
+	stash := &respOptions{}
+	for _, opt := range options {
+		opt(stash)
+	}
+
+	return &sarah.CommandResponse{
+		Content: &Modal{
+			TriggerID: triggered.TriggerID(),
+			View:      view,
+			senderKey: input.SenderKey(),
+		},
+		UserContext: stash.userContext,
+	}, nil
+}
+
+// PublishHomeView publishes the given View on the App Home of the given user via views.publish.
+//
+// See https://api.slack.com/methods/views.publish
+func (adapter *Adapter) PublishHomeView(ctx context.Context, userID event.UserID, view *View) error {
+	if adapter.web == nil {
+		return fmt.Errorf("%T does not support Web API calls to publish a view", adapter.client)
+	}
+
+	payload := &struct {
+		UserID event.UserID `json:"user_id"`
+		View   *View        `json:"view"`
+	}{
+		UserID: userID,
+		View:   view,
+	}
+	resp := &webapi.APIResponse{}
+	err := adapter.web.Post(ctx, "views.publish", payload, resp)
+	if err != nil {
+		return err
+	}
+
+	if !resp.OK {
+		return fmt.Errorf("failed views.publish request: %s", resp.Error)
+	}
+	return nil
+}
+
+// OpenModal opens the given View as a modal via views.open, and then returns the ID of the opened view.
+// Unlike NewModalResponse, the submission of the modal opened with this method is not associated with any conversation.
+//
+// See https://api.slack.com/methods/views.open
+func (adapter *Adapter) OpenModal(ctx context.Context, triggerID string, view *View) (event.ViewID, error) {
+	if adapter.web == nil {
+		return "", fmt.Errorf("%T does not support Web API calls to open a view", adapter.client)
+	}
+
+	payload := &struct {
+		TriggerID string `json:"trigger_id"`
+		View      *View  `json:"view"`
+	}{
+		TriggerID: triggerID,
+		View:      view,
+	}
+	resp := &struct {
+		webapi.APIResponse
+		View *struct {
+			ID event.ViewID `json:"id"`
+		} `json:"view"`
+	}{}
+	err := adapter.web.Post(ctx, "views.open", payload, resp)
+	if err != nil {
+		return "", err
+	}
+
+	if !resp.OK {
+		return "", fmt.Errorf("failed views.open request: %s", resp.Error)
+	}
+	if resp.View == nil {
+		return "", nil
+	}
+	return resp.View.ID, nil
+}
+
+// openModal opens the modal and associates the opened view with the SenderKey of the Input that opened it.
+func (adapter *Adapter) openModal(ctx context.Context, modal *Modal) error {
+	viewID, err := adapter.OpenModal(ctx, modal.TriggerID, modal.View)
+	if err != nil {
+		return err
+	}
+
+	adapter.modals.register(viewID, modal.senderKey)
+	return nil
+}
+
+// modalRegistry remembers which Input opened each modal so the submission continues the conversation with the same SenderKey.
+// The methods are nil-safe.
+type modalRegistry struct {
+	senderKeys *cache.Cache
+}
+
+func newModalRegistry(ttl time.Duration) *modalRegistry {
+	return &modalRegistry{
+		senderKeys: cache.New(ttl, ttl),
+	}
+}
+
+func (r *modalRegistry) register(viewID event.ViewID, senderKey string) {
+	if r == nil || viewID == "" || senderKey == "" {
+		return
+	}
+	r.senderKeys.SetDefault(viewID.String(), senderKey)
+}
+
+func (r *modalRegistry) lookup(viewID event.ViewID) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	senderKey, ok := r.senderKeys.Get(viewID.String())
+	if !ok {
+		return "", false
+	}
+	return senderKey.(string), true
+}
@@ -0,0 +1,250 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/webapi"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHomeView(t *testing.T) {
+	blocks := []event.Block{event.NewDividerBlock()}
+	view := NewHomeView(blocks)
+
+	if view.Type != "home" || len(view.Blocks) != 1 {
+		t.Errorf("Unexpected view is returned: %#v.", view)
+	}
+}
+
+func TestNewModalView(t *testing.T) {
+	view := NewModalView("feedback", "Feedback", []event.Block{event.NewDividerBlock()}).
+		WithSubmit("Send").
+		WithClose("Cancel").
+		WithPrivateMetadata("metadata").
+		WithNotifyOnClose(true)
+
+	b, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	decoded := map[string]interface{}{}
+	_ = json.Unmarshal(b, &decoded)
+	if decoded["type"] != "modal" || decoded["callback_id"] != "feedback" || decoded["private_metadata"] != "metadata" || decoded["notify_on_close"] != true {
+		t.Errorf("Unexpected JSON is returned: %s.", string(b))
+	}
+
+	for _, key := range []string{"title", "submit", "close"} {
+		text, ok := decoded[key].(map[string]interface{})
+		if !ok || text["type"] != "plain_text" {
+			t.Errorf("Unexpected %s is returned: %s.", key, string(b))
+		}
+	}
+
+	if _, ok := decoded["external_id"]; ok {
+		t.Errorf("Empty field should be omitted: %s.", string(b))
+	}
+}
+
+func TestNewHomeViewResponse(t *testing.T) {
+	input := &AppHomeInput{Event: &event.AppHomeOpened{UserID: "U123", ChannelID: "D123"}}
+	view := NewHomeView(nil)
+
+	res, err := NewHomeViewResponse(input, view, RespWithNext(func(_ context.Context, _ sarah.Input) (*sarah.CommandResponse, error) {
+		return nil, nil
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	content, ok := res.Content.(*HomeView)
+	if !ok {
+		t.Fatalf("Unexpected content is returned: %#v.", res.Content)
+	}
+
+	if content.UserID != "U123" || content.View != view {
+		t.Errorf("Unexpected content is returned: %#v.", content)
+	}
+
+	if res.UserContext == nil {
+		t.Error("UserContext is not set.")
+	}
+
+	_, err = NewHomeViewResponse(&DummyInput{}, view)
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestNewModalResponse(t *testing.T) {
+	input := &BlockActionInput{triggerID: "trigger", userID: "U123", channelID: "C123"}
+	view := NewModalView("feedback", "Feedback", nil)
+
+	res, err := NewModalResponse(input, view)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	content, ok := res.Content.(*Modal)
+	if !ok {
+		t.Fatalf("Unexpected content is returned: %#v.", res.Content)
+	}
+
+	if content.TriggerID != "trigger" || content.View != view || content.senderKey != "C123|U123" {
+		t.Errorf("Unexpected content is returned: %#v.", content)
+	}
+
+	_, err = NewModalResponse(&AppHomeInput{Event: &event.AppHomeOpened{}}, view)
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+}
+
+func TestNewResponse_NonMessageInput(t *testing.T) {
+	input := &ViewInput{senderKey: "C123|U123", userID: "U123"}
+
+	res, err := NewResponse(input, "Thanks", RespAsThreadReply(true))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	message, ok := res.Content.(*webapi.PostMessage)
+	if !ok {
+		t.Fatalf("Unexpected content is returned: %#v.", res.Content)
+	}
+
+	if message.ChannelID != "C123" || message.Text != "Thanks" || message.ThreadTimeStamp != "" {
+		t.Errorf("Unexpected message is returned: %#v.", message)
+	}
+}
+
+func TestAdapter_PublishHomeView(t *testing.T) {
+	testSets := []struct {
+		err      error
+		response string
+		hasErr   bool
+	}{
+		{response: `{"ok": true}`},
+		{response: `{"ok": false, "error": "invalid_arguments"}`, hasErr: true},
+		{err: errors.New("dummy"), hasErr: true},
+	}
+
+	for i, tt := range testSets {
+		var method string
+		adapter := &Adapter{
+			web: &DummyWebClient{
+				PostFunc: func(_ context.Context, slackMethod string, payload interface{}, response interface{}) error {
+					method = slackMethod
+					b, _ := json.Marshal(payload)
+					if !strings.Contains(string(b), `"user_id":"U123"`) {
+						t.Errorf("Unexpected payload is given on test #%d: %s.", i+1, string(b))
+					}
+					if tt.err != nil {
+						return tt.err
+					}
+					return json.Unmarshal([]byte(tt.response), response)
+				},
+			},
+		}
+
+		err := adapter.PublishHomeView(context.TODO(), "U123", NewHomeView(nil))
+		if tt.hasErr && err == nil {
+			t.Errorf("Expected error is not returned on test #%d.", i+1)
+		}
+		if !tt.hasErr && err != nil {
+			t.Errorf("Unexpected error is returned on test #%d: %s.", i+1, err.Error())
+		}
+		if method != "views.publish" {
+			t.Errorf("Unexpected method is called on test #%d: %s.", i+1, method)
+		}
+	}
+
+	adapter := &Adapter{client: &DummyClient{}}
+	if adapter.PublishHomeView(context.TODO(), "U123", NewHomeView(nil)) == nil {
+		t.Error("Expected error is not returned without WebClient.")
+	}
+}
+
+func TestAdapter_OpenModal(t *testing.T) {
+	adapter := &Adapter{
+		web: &DummyWebClient{
+			PostFunc: func(_ context.Context, slackMethod string, payload interface{}, response interface{}) error {
+				if slackMethod != "views.open" {
+					t.Errorf("Unexpected method is called: %s.", slackMethod)
+				}
+				b, _ := json.Marshal(payload)
+				if !strings.Contains(string(b), `"trigger_id":"trigger"`) {
+					t.Errorf("Unexpected payload is given: %s.", string(b))
+				}
+				return json.Unmarshal([]byte(`{"ok": true, "view": {"id": "V123"}}`), response)
+			},
+		},
+	}
+
+	viewID, err := adapter.OpenModal(context.TODO(), "trigger", NewModalView("feedback", "Feedback", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if viewID != "V123" {
+		t.Errorf("Unexpected view ID is returned: %s.", viewID)
+	}
+
+	adapter = &Adapter{client: &DummyClient{}}
+	if _, err := adapter.OpenModal(context.TODO(), "trigger", NewModalView("feedback", "Feedback", nil)); err == nil {
+		t.Error("Expected error is not returned without WebClient.")
+	}
+}
+
+func TestAdapter_SendMessage_View(t *testing.T) {
+	var methods []string
+	adapter := &Adapter{
+		config: NewConfig(),
+		modals: newModalRegistry(time.Minute),
+		web: &DummyWebClient{
+			PostFunc: func(_ context.Context, slackMethod string, _ interface{}, response interface{}) error {
+				methods = append(methods, slackMethod)
+				return json.Unmarshal([]byte(`{"ok": true, "view": {"id": "V123"}}`), response)
+			},
+		},
+	}
+
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(event.ChannelID("D123"), &HomeView{UserID: "U123", View: NewHomeView(nil)}))
+	adapter.SendMessage(context.TODO(), sarah.NewOutputMessage(event.ChannelID("C123"), &Modal{TriggerID: "trigger", View: NewModalView("feedback", "Feedback", nil), senderKey: "C123|U123"}))
+
+	if len(methods) != 2 || methods[0] != "views.publish" || methods[1] != "views.open" {
+		t.Errorf("Unexpected methods are called: %#v.", methods)
+	}
+
+	senderKey, ok := adapter.modals.lookup("V123")
+	if !ok || senderKey != "C123|U123" {
+		t.Errorf("Opened modal is not associated with the sender: %s.", senderKey)
+	}
+}
+
+func Test_modalRegistry(t *testing.T) {
+	registry := newModalRegistry(time.Minute)
+	registry.register("V123", "C123|U123")
+	registry.register("", "C123|U123")
+	registry.register("V456", "")
+
+	if senderKey, ok := registry.lookup("V123"); !ok || senderKey != "C123|U123" {
+		t.Errorf("Unexpected sender key is returned: %s.", senderKey)
+	}
+
+	if _, ok := registry.lookup("V456"); ok {
+		t.Error("Empty sender key should not be registered.")
+	}
+
+	// Does not panic.
+	var nilRegistry *modalRegistry
+	nilRegistry.register("V123", "C123|U123")
+	if _, ok := nilRegistry.lookup("V123"); ok {
+		t.Error("Nil registry should not return a sender key.")
+	}
+}
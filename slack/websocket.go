@@ -0,0 +1,181 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/oklahomer/golack/v2"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/rtmapi"
+	"github.com/oklahomer/golack/v2/webapi"
+	"github.com/tidwall/gjson"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebSocketConfig contains some configuration variables to establish a WebSocket connection for RTM API.
+// This is typically used to traverse restrictive networks where a direct WebSocket connection is not allowed.
+type WebSocketConfig struct {
+	// EnableCompression declares whether the client should negotiate per message compression with the server.
+	EnableCompression bool `json:"enable_compression" yaml:"enable_compression"`
+
+	// HandshakeTimeout declares the time limit to complete the WebSocket handshake.
+	HandshakeTimeout time.Duration `json:"handshake_timeout" yaml:"handshake_timeout"`
+
+	// Headers declares the additional HTTP headers to be sent on the handshake request.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+
+	// ProxyURL declares the URL of the proxy server.
+	// When this is empty, the proxy is determined by HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string `json:"proxy_url" yaml:"proxy_url"`
+}
+
+// NewWebSocketConfig creates and returns a new WebSocketConfig instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewWebSocketConfig() *WebSocketConfig {
+	return &WebSocketConfig{
+		EnableCompression: false,
+		HandshakeTimeout:  45 * time.Second,
+		Headers:           map[string]string{},
+		ProxyURL:          "",
+	}
+}
+
+// dialingClient is a SlackClient implementation that establishes an RTM connection with a customized WebSocket dialer.
+// Other API calls are delegated to the underlying golack instance.
+type dialingClient struct {
+	*golack.Golack
+	dialer *websocket.Dialer
+	header http.Header
+}
+
+var _ SlackClient = (*dialingClient)(nil)
+
+func newDialingClient(g *golack.Golack, config *WebSocketConfig) (*dialingClient, error) {
+	dialer := &websocket.Dialer{
+		Proxy:             http.ProxyFromEnvironment,
+		HandshakeTimeout:  config.HandshakeTimeout,
+		EnableCompression: config.EnableCompression,
+	}
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+		}
+		dialer.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	header := http.Header{}
+	for k, v := range config.Headers {
+		header.Set(k, v)
+	}
+
+	return &dialingClient{
+		Golack: g,
+		dialer: dialer,
+		header: header,
+	}, nil
+}
+
+// ConnectRTM connects to Slack WebSocket server with the configured dialer.
+func (c *dialingClient) ConnectRTM(ctx context.Context) (rtmapi.Connection, error) {
+	rtmStart := &webapi.RTMStart{}
+	err := c.WebClient.Get(ctx, "rtm.start", nil, rtmStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rtmStart.OK {
+		return nil, fmt.Errorf("failed rtm.start request: %s", rtmStart.Error)
+	}
+
+	conn, _, err := c.dialer.DialContext(ctx, rtmStart.URL, c.header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wsConnection{
+		conn:            conn,
+		outgoingEventID: rtmapi.NewOutgoingEventID(),
+	}, nil
+}
+
+// wsConnection is an rtmapi.Connection implementation that wraps a WebSocket connection established by dialingClient.
+type wsConnection struct {
+	conn            *websocket.Conn
+	outgoingEventID *rtmapi.OutgoingEventID
+}
+
+var _ rtmapi.Connection = (*wsConnection)(nil)
+
+// Receive is a blocking method to receive a payload from the WebSocket connection.
+func (c *wsConnection) Receive() (rtmapi.DecodedPayload, error) {
+	messageType, payload, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only TextMessage is supported by RTM API.
+	if messageType != websocket.TextMessage {
+		return nil, &rtmapi.UnexpectedMessageTypeError{MessageType: messageType, Payload: payload}
+	}
+
+	return decodeRTMPayload(payload)
+}
+
+// Send sends the given message. The message ID is managed per connection.
+func (c *wsConnection) Send(message *rtmapi.OutgoingMessage) error {
+	message.ID = c.outgoingEventID.Next()
+	return c.conn.WriteJSON(message)
+}
+
+// Ping sends a ping message to the server.
+func (c *wsConnection) Ping() error {
+	return c.conn.WriteJSON(rtmapi.NewPing(c.outgoingEventID))
+}
+
+// Close closes the underlying WebSocket connection.
+func (c *wsConnection) Close() error {
+	return c.conn.Close()
+}
+
+// decodeRTMPayload decodes the given payload in the same way golack's rtmapi package does.
+func decodeRTMPayload(input []byte) (rtmapi.DecodedPayload, error) {
+	input = bytes.TrimSpace(input)
+	if len(input) == 0 {
+		return nil, event.ErrEmptyPayload
+	}
+
+	parsed := gjson.ParseBytes(input)
+	e, err := event.Map(parsed)
+	if err == nil {
+		return e, nil
+	}
+
+	// A WebSocket protocol-specific payload is not listed as an "event," so see if the payload is one of those.
+	// https://api.slack.com/rtm#ping_and_pong
+	// https://api.slack.com/rtm#handling_responses
+	if parsed.Get("reply_to").Exists() {
+		var mapping rtmapi.DecodedPayload
+		if parsed.Get("type").String() == "pong" {
+			mapping = &rtmapi.Pong{}
+		} else if ok := parsed.Get("ok"); ok.Exists() && ok.Bool() {
+			mapping = &rtmapi.OKReply{}
+		} else if ok.Exists() {
+			mapping = &rtmapi.NGReply{}
+		}
+
+		if mapping != nil {
+			err := json.Unmarshal(input, mapping)
+			if err != nil {
+				return nil, event.NewMalformedPayloadError(fmt.Sprintf("malformed payload is given: %s", input))
+			}
+			return mapping, nil
+		}
+	}
+
+	return nil, event.NewMalformedPayloadError(fmt.Sprintf("given json object has unknown structure. can not handle: %s.", input))
+}
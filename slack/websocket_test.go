@@ -0,0 +1,192 @@
+package slack
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"github.com/oklahomer/golack/v2"
+	"github.com/oklahomer/golack/v2/event"
+	"github.com/oklahomer/golack/v2/rtmapi"
+	"github.com/oklahomer/golack/v2/webapi"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DummyWebClient struct {
+	GetFunc  func(context.Context, string, url.Values, interface{}) error
+	PostFunc func(context.Context, string, interface{}, interface{}) error
+}
+
+func (c *DummyWebClient) Get(ctx context.Context, slackMethod string, queryParams url.Values, response interface{}) error {
+	return c.GetFunc(ctx, slackMethod, queryParams, response)
+}
+
+func (c *DummyWebClient) Post(ctx context.Context, slackMethod string, payload interface{}, response interface{}) error {
+	return c.PostFunc(ctx, slackMethod, payload, response)
+}
+
+func TestNewWebSocketConfig(t *testing.T) {
+	config := NewWebSocketConfig()
+
+	if config.HandshakeTimeout <= 0 {
+		t.Errorf("Default handshake timeout is not set: %s.", config.HandshakeTimeout)
+	}
+
+	if config.Headers == nil {
+		t.Error("Headers must be initialized.")
+	}
+}
+
+func Test_newDialingClient(t *testing.T) {
+	t.Run("Valid settings", func(t *testing.T) {
+		config := &WebSocketConfig{
+			EnableCompression: true,
+			HandshakeTimeout:  3 * time.Second,
+			Headers:           map[string]string{"X-Foo": "bar"},
+			ProxyURL:          "http://proxy.example.com:8080",
+		}
+
+		client, err := newDialingClient(golack.New(golack.NewConfig()), config)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if !client.dialer.EnableCompression || client.dialer.HandshakeTimeout != config.HandshakeTimeout {
+			t.Errorf("Given settings are not applied: %#v.", client.dialer)
+		}
+
+		if client.header.Get("X-Foo") != "bar" {
+			t.Errorf("Given header is not set: %#v.", client.header)
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+		proxyURL, _ := client.dialer.Proxy(req)
+		if proxyURL == nil || proxyURL.String() != config.ProxyURL {
+			t.Errorf("Given proxy is not set: %s.", proxyURL)
+		}
+	})
+
+	t.Run("Invalid proxy URL", func(t *testing.T) {
+		config := &WebSocketConfig{
+			ProxyURL: "://invalid",
+		}
+
+		_, err := newDialingClient(golack.New(golack.NewConfig()), config)
+		if err == nil {
+			t.Error("Expected error is not returned.")
+		}
+	})
+}
+
+func Test_dialingClient_ConnectRTM(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Foo") != "bar" {
+			t.Errorf("Given header is not sent: %#v.", r.Header)
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade: %s.", err.Error())
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"pong","reply_to":1}`))
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	webClient := &DummyWebClient{
+		GetFunc: func(_ context.Context, slackMethod string, _ url.Values, response interface{}) error {
+			if slackMethod != "rtm.start" {
+				t.Errorf("Unexpected method is called: %s.", slackMethod)
+			}
+			start := response.(*webapi.RTMStart)
+			start.OK = true
+			start.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+			return nil
+		},
+	}
+	config := NewWebSocketConfig()
+	config.Headers["X-Foo"] = "bar"
+	client, err := newDialingClient(golack.New(golack.NewConfig(), golack.WithWebClient(webClient)), config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	conn, err := client.ConnectRTM(context.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	defer conn.Close()
+
+	payload, err := conn.Receive()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if _, ok := payload.(*rtmapi.Pong); !ok {
+		t.Errorf("Unexpected payload is returned: %#v.", payload)
+	}
+
+	err = conn.Ping()
+	if err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+}
+
+func Test_decodeRTMPayload(t *testing.T) {
+	testSets := []struct {
+		input    string
+		expected reflect.Type
+	}{
+		{
+			input:    `{"type":"message","channel":"C123","user":"U123","text":"Hello","ts":"1355517523.000005"}`,
+			expected: reflect.TypeOf(&event.Message{}),
+		},
+		{
+			input:    `{"type":"pong","reply_to":1}`,
+			expected: reflect.TypeOf(&rtmapi.Pong{}),
+		},
+		{
+			input:    `{"ok":true,"reply_to":1,"ts":"1355517523.000005","text":"Hello"}`,
+			expected: reflect.TypeOf(&rtmapi.OKReply{}),
+		},
+		{
+			input:    `{"ok":false,"reply_to":1,"error":{"code":2,"msg":"message text is missing"}}`,
+			expected: reflect.TypeOf(&rtmapi.NGReply{}),
+		},
+		{
+			input:    `{"foo":"bar"}`,
+			expected: nil,
+		},
+		{
+			input:    ` `,
+			expected: nil,
+		},
+	}
+
+	for i, testSet := range testSets {
+		decoded, err := decodeRTMPayload([]byte(testSet.input))
+		if testSet.expected == nil {
+			if err == nil {
+				t.Errorf("Expected error is not returned on test %d.", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+			continue
+		}
+
+		if reflect.TypeOf(decoded) != testSet.expected {
+			t.Errorf("Unexpected payload is returned on test %d: %T.", i, decoded)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package sarah
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// codeFence is the Markdown notation that opens and closes a code block.
+const codeFence = "```"
+
+// UnsplitContent wraps a content so Adapters send it as-is even when the text exceeds the length limit.
+// Use this as CommandResponse.Content when the output must not be split, e.g., a message that is parsed by another bot.
+//
+//	return &sarah.CommandResponse{
+//		Content: &sarah.UnsplitContent{Content: report},
+//	}, nil
+type UnsplitContent struct {
+	Content interface{}
+}
+
+// UnwrapUnsplitContent returns the content wrapped by UnsplitContent and false when the given content is an UnsplitContent.
+// Otherwise, this returns the given content as-is and true to tell the content can be split.
+func UnwrapUnsplitContent(content interface{}) (interface{}, bool) {
+	unsplit, ok := content.(*UnsplitContent)
+	if ok {
+		return unsplit.Content, false
+	}
+	return content, true
+}
+
+// SplitMessage splits the given text into chunks so each chunk does not exceed the given limit in runes.
+// The text is split at line breaks when possible; a line longer than the limit is split in the middle.
+// When a chunk ends inside a Markdown code block, the code block is closed at the end of the chunk and re-opened at the beginning of the next chunk,
+// so each chunk is rendered correctly on its own.
+//
+// When the limit is zero or negative, or the text is short enough, this returns a slice that only contains the given text.
+func SplitMessage(text string, limit int) []string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	s := &messageSplitter{limit: limit}
+	for _, line := range strings.Split(text, "\n") {
+		s.add(line)
+	}
+	return s.finish()
+}
+
+type messageSplitter struct {
+	limit  int
+	chunks []string
+	lines  []string
+	length int
+
+	// fence is the line that opened the current code block such as "```go". This is empty outside a code block.
+	fence string
+
+	// reopened tells if the current chunk only contains the line that re-opens the code block.
+	reopened bool
+}
+
+func (s *messageSplitter) add(line string) {
+	toggle := strings.HasPrefix(strings.TrimSpace(line), codeFence)
+	for {
+		// Reserve room to close the code block when this chunk still ends inside one after this line.
+		reserve := 0
+		if (s.fence != "") != toggle {
+			reserve = len(codeFence) + 1
+		}
+
+		room := s.limit - s.length - reserve
+		if len(s.lines) > 0 {
+			room-- // For the line break.
+		}
+
+		if utf8.RuneCountInString(line) <= room {
+			s.append(line, toggle)
+			return
+		}
+
+		if len(s.lines) > 0 && !s.reopened {
+			s.flush()
+			continue
+		}
+
+		// The line does not fit in an empty chunk, so split the line itself.
+		if room < 1 {
+			room = 1
+		}
+		head, tail := splitRunes(line, room)
+		s.append(head, false)
+		s.flush()
+		line = tail
+	}
+}
+
+func (s *messageSplitter) append(line string, toggle bool) {
+	if len(s.lines) > 0 {
+		s.length++
+	}
+	s.lines = append(s.lines, line)
+	s.length += utf8.RuneCountInString(line)
+	s.reopened = false
+
+	if toggle {
+		if s.fence == "" {
+			s.fence = strings.TrimSpace(line)
+		} else {
+			s.fence = ""
+		}
+	}
+}
+
+func (s *messageSplitter) flush() {
+	chunk := strings.Join(s.lines, "\n")
+	if s.fence != "" {
+		chunk += "\n" + codeFence
+	}
+	s.chunks = append(s.chunks, chunk)
+
+	s.lines = nil
+	s.length = 0
+	if s.fence != "" {
+		s.lines = []string{s.fence}
+		s.length = utf8.RuneCountInString(s.fence)
+		s.reopened = true
+	}
+}
+
+func (s *messageSplitter) finish() []string {
+	if len(s.lines) > 0 && !s.reopened {
+		s.chunks = append(s.chunks, strings.Join(s.lines, "\n"))
+	}
+	return s.chunks
+}
+
+// splitRunes splits the given string at the given number of runes.
+func splitRunes(str string, n int) (string, string) {
+	i := 0
+	for pos := range str {
+		if i == n {
+			return str[:pos], str[pos:]
+		}
+		i++
+	}
+	return str, ""
+}
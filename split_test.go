@@ -0,0 +1,107 @@
+package sarah
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestUnwrapUnsplitContent(t *testing.T) {
+	content, splittable := UnwrapUnsplitContent(&UnsplitContent{Content: "foo"})
+	if splittable {
+		t.Error("Wrapped content should not be splittable.")
+	}
+	if content != "foo" {
+		t.Errorf("Unexpected content is returned: %#v.", content)
+	}
+
+	content, splittable = UnwrapUnsplitContent("bar")
+	if !splittable {
+		t.Error("Plain content should be splittable.")
+	}
+	if content != "bar" {
+		t.Errorf("Unexpected content is returned: %#v.", content)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	testSets := []struct {
+		text     string
+		limit    int
+		expected []string
+	}{
+		{
+			text:     "short",
+			limit:    10,
+			expected: []string{"short"},
+		},
+		{
+			text:     "no limit",
+			limit:    0,
+			expected: []string{"no limit"},
+		},
+		{
+			text:     "line1\nline2\nline3",
+			limit:    11,
+			expected: []string{"line1\nline2", "line3"},
+		},
+		{
+			text:     "abcdefghij",
+			limit:    4,
+			expected: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			text:     "あいうえおかきく",
+			limit:    3,
+			expected: []string{"あいう", "えおか", "きく"},
+		},
+		{
+			text:     "head\n```go\nfoo()\nbar()\n```\ntail",
+			limit:    20,
+			expected: []string{"head\n```go\nfoo()\n```", "```go\nbar()\n```\ntail"},
+		},
+	}
+
+	for i, testSet := range testSets {
+		chunks := SplitMessage(testSet.text, testSet.limit)
+
+		if len(chunks) != len(testSet.expected) {
+			t.Errorf("Unexpected number of chunks are returned on test #%d: %#v.", i+1, chunks)
+			continue
+		}
+
+		for j, chunk := range chunks {
+			if chunk != testSet.expected[j] {
+				t.Errorf("Unexpected chunk is returned on test #%d: %q.", i+1, chunk)
+			}
+		}
+	}
+}
+
+func TestSplitMessage_Limit(t *testing.T) {
+	lines := []string{"Stack trace:", "```"}
+	for i := 0; i < 100; i++ {
+		lines = append(lines, strings.Repeat("x", i%30)+" at github.com/oklahomer/go-sarah")
+	}
+	lines = append(lines, "```", "End of trace.")
+	text := strings.Join(lines, "\n")
+
+	chunks := SplitMessage(text, 200)
+	if len(chunks) < 2 {
+		t.Fatalf("Text is not split: %d.", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if utf8.RuneCountInString(chunk) > 200 {
+			t.Errorf("Chunk #%d exceeds the limit: %d.", i+1, utf8.RuneCountInString(chunk))
+		}
+
+		if strings.Count(chunk, codeFence)%2 != 0 {
+			t.Errorf("Code block is not balanced in chunk #%d: %s.", i+1, chunk)
+		}
+	}
+
+	if !strings.HasSuffix(chunks[len(chunks)-1], "End of trace.") {
+		t.Errorf("Last line is lost: %s.", chunks[len(chunks)-1])
+	}
+}
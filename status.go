@@ -3,7 +3,10 @@ package sarah
 import (
 	"errors"
 	"github.com/oklahomer/go-kasumi/logger"
+	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var runnerStatus = &status{}
@@ -20,7 +23,12 @@ var ErrRunnerAlreadyRunning = errors.New("go-sarah's process is already running"
 //   - Another that periodically calls CurrentStatus and monitors status.
 //     When Status.Running is false and Status.Bots field is empty, then the bot is not initiated yet.
 func CurrentStatus() Status {
-	return runnerStatus.snapshot()
+	return defaultRunner().Status()
+}
+
+// Status returns the current status of the Runner. This is the Runner counterpart of the package-level CurrentStatus.
+func (rn *Runner) Status() Status {
+	return rn.status.snapshot()
 }
 
 // Status represents the current status of Sarah and all registered Bots.
@@ -29,8 +37,66 @@ type Status struct {
 	// Sarah is considered running when Run is called and at least one of its belonging Bot is actively running.
 	Running bool
 
+	// Worker represents the statistics of the worker that executes the jobs such as Command executions.
+	// This is nil when Run is not called, yet.
+	Worker *WorkerStats
+
+	// Scheduler represents the statistics of the scheduler that executes the registered ScheduledTasks.
+	// This is nil when Run is not called, yet.
+	Scheduler *SchedulerStats
+
 	// Bots holds a list of BotStatus values where each value represents its corresponding Bot's status.
 	Bots []BotStatus
+
+	// Runtime represents the process-level statistics of the Go runtime.
+	// Monitor this to spot a goroutine or memory leak caused by a misbehaving Adapter or plugin.
+	Runtime *RuntimeStats
+}
+
+// RuntimeStats represents the process-level statistics of the Go runtime.
+type RuntimeStats struct {
+	// Goroutines is the number of goroutines that currently exist.
+	Goroutines int
+
+	// HeapAlloc is the bytes of allocated heap objects.
+	HeapAlloc uint64
+
+	// HeapObjects is the number of allocated heap objects.
+	HeapObjects uint64
+
+	// NumGC is the number of completed GC cycles.
+	NumGC uint32
+
+	// PauseTotal is the cumulative duration of the stop-the-world pauses since the process started.
+	PauseTotal time.Duration
+
+	// LastPause is the duration of the latest stop-the-world pause. This is zero when no GC cycle has completed.
+	LastPause time.Duration
+
+	// Uptime is how long Sarah has been running since Run was called. This is zero when Run is not called, yet.
+	Uptime time.Duration
+}
+
+// runtimeStats reads the current statistics of the Go runtime.
+// Note that runtime.ReadMemStats briefly stops the world, so avoid calling CurrentStatus in a tight loop.
+func runtimeStats(startedAt time.Time) *RuntimeStats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	stats := &RuntimeStats{
+		Goroutines:  runtime.NumGoroutine(),
+		HeapAlloc:   memStats.HeapAlloc,
+		HeapObjects: memStats.HeapObjects,
+		NumGC:       memStats.NumGC,
+		PauseTotal:  time.Duration(memStats.PauseTotalNs),
+	}
+	if memStats.NumGC > 0 {
+		stats.LastPause = time.Duration(memStats.PauseNs[(memStats.NumGC+255)%256])
+	}
+	if !startedAt.IsZero() {
+		stats.Uptime = time.Since(startedAt)
+	}
+	return stats
 }
 
 // BotStatus represents the current status of a Bot.
@@ -43,12 +109,100 @@ type BotStatus struct {
 	// When this returns false, the state is final and the Bot is never recovered unless the process is rebooted.
 	// In other words, a Bot is "running" even if the connection with the chat service is unstable and recovery is in progress.
 	Running bool
+
+	// Leadership represents the role of the current replica when a LeaderElector is registered for the Bot via RegisterLeaderElector.
+	// While the replica is LeadershipFollower, the Bot is considered running on hot standby even though its Adapter is not connected.
+	Leadership Leadership
+
+	// Maintenance indicates if the Bot is in maintenance mode via EnterMaintenance.
+	// While in maintenance, the Bot replies MaintenanceMessage to every Input and its ScheduledTasks are paused.
+	Maintenance bool
+
+	// MaintenanceMessage is the message that the Bot replies while in maintenance. This is empty when Maintenance is false.
+	MaintenanceMessage string
+
+	// UserContextStorage represents the statistics of the Bot's UserContextStorage.
+	// This is nil when the Bot has no UserContextStorage or its UserContextStorage does not satisfy UserContextStorageStatsReporter.
+	UserContextStorage *UserContextStorageStats
+
+	// Connection represents the statistics of the connection with the chat service.
+	// This is nil when the Bot's Adapter does not satisfy ConnectionStatsReporter or does not maintain a persistent connection.
+	Connection *ConnectionStats
+
+	// Plugins holds the configuration states of the Commands and ScheduledTasks that are built from CommandProps and ScheduledTaskProps.
+	// Check this to notice a plugin that is missing due to an unreadable or invalid configuration.
+	Plugins []PluginStatus
+
+	// Contexts holds the contexts that Sarah derived to run the Bot in chronological order, up to the latest 100.
+	// Check the last one to see why the Bot stopped, and the earlier ones to see the history of restarts during the process lifetime.
+	Contexts []BotContextStatus
+
+	// LastError is the description of the latest error that the Bot escalated via the function given to Bot.Run.
+	// This is empty when no error has been escalated.
+	LastError string
+
+	// LastErrorAt is the time when LastError was escalated. This is zero when no error has been escalated.
+	LastErrorAt time.Time
+
+	// RestartCount is the number of restarts that SupervisionDirective with RestartBot caused during the process lifetime.
+	RestartCount int
+
+	// ConnectedSince is the time when the current connection with the chat service was established.
+	// This is zero when the connection is not established at the moment or the Bot's Adapter does not satisfy ConnectionStatsReporter.
+	ConnectedSince time.Time
+
+	// Drain represents the drain of the Bot's in-flight Inputs on shutdown as declared by Config.Drain.
+	// This is nil while the Bot runs normally or when the Bot is stopped without the drain.
+	Drain *DrainStats
+
+	// Circuits holds the states of the CircuitBreakers of the Commands that are built with CommandPropsBuilder.CircuitBreaker.
+	Circuits []CircuitStatus
+}
+
+// PluginKind represents the kind of plugin.
+type PluginKind string
+
+const (
+	// PluginCommand indicates that the plugin is a Command built from CommandProps.
+	PluginCommand PluginKind = "command"
+
+	// PluginScheduledTask indicates that the plugin is a ScheduledTask built from ScheduledTaskProps.
+	PluginScheduledTask PluginKind = "scheduled task"
+)
+
+// PluginStatus represents the configuration state of a Command or ScheduledTask that is built with its configuration.
+type PluginStatus struct {
+	// Identifier is the identifier of the Command or ScheduledTask.
+	Identifier string
+
+	// Kind tells if the plugin is a Command or a ScheduledTask.
+	Kind PluginKind
+
+	// Loaded indicates if the latest build with the configuration succeeded.
+	// When a reload fails, this turns false while a Command keeps serving with its previous configuration.
+	Loaded bool
+
+	// Error is the reason of the latest failure. This is empty when Loaded is true.
+	Error string
+
+	// LoadedAt is the time when the plugin was built successfully last time. This is zero when the plugin was never built.
+	LoadedAt time.Time
+
+	// ReloadedAt is the time when the latest reload triggered by ConfigWatcher occurred. This is zero when no reload occurred.
+	ReloadedAt time.Time
 }
 
 type status struct {
-	bots     []*botStatus
-	finished chan struct{}
-	mutex    sync.RWMutex
+	bots           []*botStatus
+	workerStats    func() *WorkerStats
+	schedulerStats func() *SchedulerStats
+	circuitStats   func(BotType) []CircuitStatus
+	finished       chan struct{}
+	startedAt      time.Time
+	mutex          sync.RWMutex
+
+	// maintenance holds the maintenance modes of the Runner. The default Runner's ones are referenced when this is nil.
+	maintenance *maintenanceModes
 }
 
 func (s *status) running() bool {
@@ -81,9 +235,23 @@ func (s *status) start() error {
 	}
 
 	s.finished = make(chan struct{})
+	s.startedAt = time.Now()
 	return nil
 }
 
+// reset clears the status of the stopped runner so Run can be called again by Restart.
+func (s *status) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.bots = nil
+	s.workerStats = nil
+	s.schedulerStats = nil
+	s.circuitStats = nil
+	s.finished = nil
+	s.startedAt = time.Time{}
+}
+
 func (s *status) addBot(bot Bot) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -92,9 +260,122 @@ func (s *status) addBot(bot Bot) {
 		botType:  bot.BotType(),
 		finished: make(chan struct{}),
 	}
+	if provider, ok := bot.(storageStatsProvider); ok {
+		botStatus.storageStats = provider.userContextStorageStats
+	}
+	if provider, ok := bot.(connectionStatsProvider); ok {
+		botStatus.connectionStats = provider.connectionStats
+	}
 	s.bots = append(s.bots, botStatus)
 }
 
+func (s *status) setWorkerStats(fnc func() *WorkerStats) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.workerStats = fnc
+}
+
+func (s *status) setSchedulerStats(fnc func() *SchedulerStats) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.schedulerStats = fnc
+}
+
+func (s *status) setCircuitStats(fnc func(BotType) []CircuitStatus) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.circuitStats = fnc
+}
+
+func (s *status) setLeadership(botType BotType, leadership Leadership) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, bs := range s.bots {
+		if bs.botType == botType {
+			bs.setLeadership(leadership)
+		}
+	}
+}
+
+// setPluginState records the result of a build of the Command or ScheduledTask with its configuration.
+// reload tells if the build is triggered by a configuration change that ConfigWatcher detected.
+func (s *status) setPluginState(botType BotType, kind PluginKind, id string, reload bool, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, bs := range s.bots {
+		if bs.botType == botType {
+			bs.setPluginState(kind, id, reload, err)
+		}
+	}
+}
+
+// startBotContext records a new context of the Bot with the given BotType and returns the record to update on cancellation.
+func (s *status) startBotContext(botType BotType) *BotContextStatus {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	record := &BotContextStatus{
+		StartedAt: currentClock().Now(),
+	}
+	for _, bs := range s.bots {
+		if bs.botType == botType {
+			bs.addContext(record)
+		}
+	}
+	return record
+}
+
+// cancelBotContext records the cancellation of the given context.
+func (s *status) cancelBotContext(record *BotContextStatus, reason CancellationReason, cause error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, bs := range s.bots {
+		bs.cancelContext(record, reason, cause)
+	}
+}
+
+// setBotError records the latest error that the Bot with the given BotType escalated.
+func (s *status) setBotError(botType BotType, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, bs := range s.bots {
+		if bs.botType == botType {
+			bs.setError(err)
+		}
+	}
+}
+
+// setBotDrainer replaces the inputDrainer of the Bot with the given one since a new one is created for each context of the Bot.
+func (s *status) setBotDrainer(drainer *inputDrainer) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, bs := range s.bots {
+		if bs.botType == drainer.botType {
+			bs.drainer.Store(drainer)
+		}
+	}
+}
+
+// countBotRestart increments the restart count of the Bot with the given BotType.
+func (s *status) countBotRestart(botType BotType) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, bs := range s.bots {
+		if bs.botType == botType {
+			bs.countRestart()
+		}
+	}
+}
+
 func (s *status) stopBot(bot Bot) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -110,17 +391,53 @@ func (s *status) snapshot() Status {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
+	modes := s.maintenance
+	if modes == nil {
+		modes = maintenance
+	}
+
 	var bots []BotStatus
 	for _, botStatus := range s.bots {
 		bs := BotStatus{
-			Type:    botStatus.botType,
-			Running: botStatus.running(),
+			Type:       botStatus.botType,
+			Running:    botStatus.running(),
+			Leadership: botStatus.getLeadership(),
+		}
+		bs.MaintenanceMessage, bs.Maintenance = modes.message(botStatus.botType)
+		if botStatus.storageStats != nil {
+			bs.UserContextStorage = botStatus.storageStats()
+		}
+		if botStatus.connectionStats != nil {
+			bs.Connection = botStatus.connectionStats()
+			if bs.Connection != nil && bs.Connection.Connected {
+				bs.ConnectedSince = bs.Connection.LastConnectedAt
+			}
+		}
+		bs.Plugins = botStatus.pluginStates()
+		bs.Contexts = botStatus.contextStates()
+		bs.LastError, bs.LastErrorAt, bs.RestartCount = botStatus.failures()
+		if drainer, ok := botStatus.drainer.Load().(*inputDrainer); ok {
+			bs.Drain = drainer.snapshot()
+		}
+		if s.circuitStats != nil {
+			bs.Circuits = s.circuitStats(botStatus.botType)
 		}
 		bots = append(bots, bs)
 	}
+	var workerStats *WorkerStats
+	if s.workerStats != nil {
+		workerStats = s.workerStats()
+	}
+	var schedulerStats *SchedulerStats
+	if s.schedulerStats != nil {
+		schedulerStats = s.schedulerStats()
+	}
 	return Status{
-		Running: s.running(),
-		Bots:    bots,
+		Running:   s.running(),
+		Worker:    workerStats,
+		Scheduler: schedulerStats,
+		Bots:      bots,
+		Runtime:   runtimeStats(s.startedAt),
 	}
 }
 
@@ -138,9 +455,146 @@ func (s *status) stop() {
 	close(s.finished)
 }
 
+// storageStatsProvider is satisfied by a Bot that can provide the statistics of its UserContextStorage.
+type storageStatsProvider interface {
+	userContextStorageStats() *UserContextStorageStats
+}
+
+// connectionStatsProvider is satisfied by a Bot that can provide the statistics of its Adapter's connection.
+type connectionStatsProvider interface {
+	connectionStats() *ConnectionStats
+}
+
 type botStatus struct {
-	botType  BotType
-	finished chan struct{}
+	botType         BotType
+	finished        chan struct{}
+	storageStats    func() *UserContextStorageStats
+	connectionStats func() *ConnectionStats
+	leadership      atomic.Value
+	drainer         atomic.Value
+	plugins         []*PluginStatus
+	pluginMutex     sync.Mutex
+	contexts        []*BotContextStatus
+	contextMutex    sync.Mutex
+	lastError       string
+	lastErrorAt     time.Time
+	restarts        int
+	failureMutex    sync.Mutex
+}
+
+func (bs *botStatus) setError(err error) {
+	bs.failureMutex.Lock()
+	defer bs.failureMutex.Unlock()
+
+	bs.lastError = err.Error()
+	bs.lastErrorAt = currentClock().Now()
+}
+
+func (bs *botStatus) countRestart() {
+	bs.failureMutex.Lock()
+	defer bs.failureMutex.Unlock()
+
+	bs.restarts++
+}
+
+func (bs *botStatus) failures() (string, time.Time, int) {
+	bs.failureMutex.Lock()
+	defer bs.failureMutex.Unlock()
+
+	return bs.lastError, bs.lastErrorAt, bs.restarts
+}
+
+func (bs *botStatus) addContext(record *BotContextStatus) {
+	bs.contextMutex.Lock()
+	defer bs.contextMutex.Unlock()
+
+	bs.contexts = append(bs.contexts, record)
+	if len(bs.contexts) > maxBotContexts {
+		bs.contexts = bs.contexts[len(bs.contexts)-maxBotContexts:]
+	}
+}
+
+// cancelContext updates the given record if it belongs to this Bot. The first cancellation wins.
+func (bs *botStatus) cancelContext(record *BotContextStatus, reason CancellationReason, cause error) {
+	bs.contextMutex.Lock()
+	defer bs.contextMutex.Unlock()
+
+	for _, c := range bs.contexts {
+		if c != record || c.Canceled {
+			continue
+		}
+
+		c.Canceled = true
+		c.CanceledAt = currentClock().Now()
+		c.Reason = reason
+		if cause != nil {
+			c.Cause = cause.Error()
+		}
+	}
+}
+
+func (bs *botStatus) contextStates() []BotContextStatus {
+	bs.contextMutex.Lock()
+	defer bs.contextMutex.Unlock()
+
+	var contexts []BotContextStatus
+	for _, c := range bs.contexts {
+		contexts = append(contexts, *c)
+	}
+	return contexts
+}
+
+func (bs *botStatus) setPluginState(kind PluginKind, id string, reload bool, err error) {
+	bs.pluginMutex.Lock()
+	defer bs.pluginMutex.Unlock()
+
+	var plugin *PluginStatus
+	for _, p := range bs.plugins {
+		if p.Kind == kind && p.Identifier == id {
+			plugin = p
+			break
+		}
+	}
+	if plugin == nil {
+		plugin = &PluginStatus{
+			Identifier: id,
+			Kind:       kind,
+		}
+		bs.plugins = append(bs.plugins, plugin)
+	}
+
+	now := currentClock().Now()
+	if reload {
+		plugin.ReloadedAt = now
+	}
+	if err != nil {
+		plugin.Loaded = false
+		plugin.Error = err.Error()
+		return
+	}
+	plugin.Loaded = true
+	plugin.Error = ""
+	plugin.LoadedAt = now
+}
+
+func (bs *botStatus) pluginStates() []PluginStatus {
+	bs.pluginMutex.Lock()
+	defer bs.pluginMutex.Unlock()
+
+	var plugins []PluginStatus
+	for _, p := range bs.plugins {
+		plugins = append(plugins, *p)
+	}
+	return plugins
+}
+
+func (bs *botStatus) setLeadership(leadership Leadership) {
+	bs.leadership.Store(leadership)
+}
+
+func (bs *botStatus) getLeadership() Leadership {
+	leadership, _ := bs.leadership.Load().(Leadership)
+	return leadership
 }
 
 func (bs *botStatus) running() bool {
@@ -1,6 +1,8 @@
 package sarah
 
 import (
+	"errors"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -114,6 +116,82 @@ func Test_status_addBot(t *testing.T) {
 	if !bs.running() {
 		t.Error("Bot status must be running at this point.")
 	}
+
+	if bs.storageStats != nil {
+		t.Error("Storage stats must not be set for a Bot without storageStatsProvider.")
+	}
+}
+
+func Test_status_addBot_WithStorageStats(t *testing.T) {
+	bot := &defaultBot{
+		botType:            "dummy",
+		userContextStorage: NewUserContextStorage(NewCacheConfig()),
+	}
+	s := &status{finished: make(chan struct{})}
+	s.addBot(bot)
+
+	snapshot := s.snapshot()
+	if snapshot.Bots[0].UserContextStorage == nil {
+		t.Error("Storage stats must be included.")
+	}
+}
+
+func Test_status_addBot_WithConnectionStats(t *testing.T) {
+	adapter := &DummyConnectionStatsAdapter{
+		DummyAdapter: DummyAdapter{BotTypeValue: "dummy"},
+		ConnectionStatsFunc: func() *ConnectionStats {
+			return &ConnectionStats{Connected: true, Reconnects: 1}
+		},
+	}
+	s := &status{finished: make(chan struct{})}
+	s.addBot(NewBot(adapter))
+
+	snapshot := s.snapshot()
+	stats := snapshot.Bots[0].Connection
+	if stats == nil {
+		t.Fatal("Connection stats must be included.")
+	}
+
+	if !stats.Connected || stats.Reconnects != 1 {
+		t.Errorf("Unexpected stats are returned: %#v.", stats)
+	}
+}
+
+func Test_status_snapshot_ConnectedSince(t *testing.T) {
+	connectedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	testSets := []struct {
+		stats    *ConnectionStats
+		expected time.Time
+	}{
+		{
+			stats:    nil,
+			expected: time.Time{},
+		},
+		{
+			stats:    &ConnectionStats{Connected: true, LastConnectedAt: connectedAt},
+			expected: connectedAt,
+		},
+		{
+			stats:    &ConnectionStats{Connected: false, LastConnectedAt: connectedAt},
+			expected: time.Time{},
+		},
+	}
+
+	for i, testSet := range testSets {
+		adapter := &DummyConnectionStatsAdapter{
+			DummyAdapter: DummyAdapter{BotTypeValue: "dummy"},
+			ConnectionStatsFunc: func() *ConnectionStats {
+				return testSet.stats
+			},
+		}
+		s := &status{finished: make(chan struct{})}
+		s.addBot(NewBot(adapter))
+
+		connectedSince := s.snapshot().Bots[0].ConnectedSince
+		if !connectedSince.Equal(testSet.expected) {
+			t.Errorf("Unexpected time is returned on test #%d: %s.", i, connectedSince)
+		}
+	}
 }
 
 func Test_status_stopBot(t *testing.T) {
@@ -183,6 +261,38 @@ func Test_status_snapshot(t *testing.T) {
 	}
 }
 
+func Test_status_snapshot_WorkerStats(t *testing.T) {
+	s := &status{}
+	if s.snapshot().Worker != nil {
+		t.Error("Worker stats should be nil before the worker is set.")
+	}
+
+	stats := &WorkerStats{QueueDepth: 3, RecoveredPanics: 1}
+	s.setWorkerStats(func() *WorkerStats {
+		return stats
+	})
+
+	if s.snapshot().Worker != stats {
+		t.Errorf("Unexpected worker stats are returned: %#v.", s.snapshot().Worker)
+	}
+}
+
+func Test_status_snapshot_SchedulerStats(t *testing.T) {
+	s := &status{}
+	if s.snapshot().Scheduler != nil {
+		t.Error("Scheduler stats should be nil before the scheduler is set.")
+	}
+
+	stats := &SchedulerStats{RecoveredPanics: 1, SkippedRuns: 2}
+	s.setSchedulerStats(func() *SchedulerStats {
+		return stats
+	})
+
+	if s.snapshot().Scheduler != stats {
+		t.Errorf("Unexpected scheduler stats are returned: %#v.", s.snapshot().Scheduler)
+	}
+}
+
 func Test_botStatus_running(t *testing.T) {
 	bs := &botStatus{
 		botType:  "dummy",
@@ -218,3 +328,133 @@ func Test_botStatus_stop(t *testing.T) {
 
 	bs.stop() // Multiple call to this method should not panic.
 }
+
+func Test_status_snapshot_RuntimeStats(t *testing.T) {
+	s := &status{}
+	stats := s.snapshot().Runtime
+	if stats == nil {
+		t.Fatal("Runtime stats should be returned even before Run is called.")
+	}
+	if stats.Goroutines == 0 || stats.HeapAlloc == 0 {
+		t.Errorf("Unexpected runtime stats are returned: %#v.", stats)
+	}
+	if stats.Uptime != 0 {
+		t.Errorf("Uptime should be zero before Run is called: %s.", stats.Uptime)
+	}
+
+	err := s.start()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	s.startedAt = time.Now().Add(-time.Minute)
+	if uptime := s.snapshot().Runtime.Uptime; uptime < time.Minute {
+		t.Errorf("Unexpected uptime is returned: %s.", uptime)
+	}
+}
+
+func Test_runtimeStats_LastPause(t *testing.T) {
+	runtime.GC()
+	stats := runtimeStats(time.Time{})
+	if stats.NumGC == 0 {
+		t.Fatal("GC cycle is not counted.")
+	}
+	if stats.PauseTotal < stats.LastPause {
+		t.Errorf("Unexpected pause durations are returned: %#v.", stats)
+	}
+}
+
+func Test_status_setPluginState(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		var botType BotType = "dummy"
+		s := &status{}
+		s.addBot(&DummyBot{BotTypeValue: botType})
+
+		s.setPluginState(botType, PluginCommand, "hello", false, nil)
+		s.setPluginState(botType, PluginScheduledTask, "weather", false, errors.New("config not found"))
+
+		plugins := s.snapshot().Bots[0].Plugins
+		if len(plugins) != 2 {
+			t.Fatalf("Unexpected number of plugins are returned: %d.", len(plugins))
+		}
+		if !plugins[0].Loaded || !plugins[0].LoadedAt.Equal(now) || !plugins[0].ReloadedAt.IsZero() {
+			t.Errorf("Unexpected state is set: %#v.", plugins[0])
+		}
+		if plugins[1].Loaded || plugins[1].Error != "config not found" || !plugins[1].LoadedAt.IsZero() {
+			t.Errorf("Unexpected state is set: %#v.", plugins[1])
+		}
+
+		// Failed reload
+		reloaded := now.Add(time.Hour)
+		now = reloaded
+		s.setPluginState(botType, PluginCommand, "hello", true, errors.New("invalid config"))
+		plugin := s.snapshot().Bots[0].Plugins[0]
+		if plugin.Loaded || plugin.Error != "invalid config" || !plugin.ReloadedAt.Equal(reloaded) || !plugin.LoadedAt.Equal(reloaded.Add(-time.Hour)) {
+			t.Errorf("Unexpected state is set: %#v.", plugin)
+		}
+
+		// Successful reload
+		s.setPluginState(botType, PluginCommand, "hello", true, nil)
+		plugin = s.snapshot().Bots[0].Plugins[0]
+		if !plugin.Loaded || plugin.Error != "" || !plugin.LoadedAt.Equal(reloaded) {
+			t.Errorf("Unexpected state is set: %#v.", plugin)
+		}
+	})
+}
+
+func Test_status_setBotError(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		var botType BotType = "dummy"
+		s := &status{}
+		s.addBot(&DummyBot{BotTypeValue: botType})
+		s.addBot(&DummyBot{BotTypeValue: "other"})
+
+		bots := s.snapshot().Bots
+		if bots[0].LastError != "" || !bots[0].LastErrorAt.IsZero() {
+			t.Errorf("Unexpected error is recorded: %#v.", bots[0])
+		}
+
+		s.setBotError(botType, errors.New("first error"))
+		now = now.Add(time.Minute)
+		s.setBotError(botType, errors.New("second error"))
+
+		bots = s.snapshot().Bots
+		if bots[0].LastError != "second error" || !bots[0].LastErrorAt.Equal(now) {
+			t.Errorf("Unexpected error is recorded: %#v.", bots[0])
+		}
+		if bots[1].LastError != "" {
+			t.Errorf("Error is recorded to another Bot: %#v.", bots[1])
+		}
+	})
+}
+
+func Test_status_countBotRestart(t *testing.T) {
+	var botType BotType = "dummy"
+	s := &status{}
+	s.addBot(&DummyBot{BotTypeValue: botType})
+	s.addBot(&DummyBot{BotTypeValue: "other"})
+
+	s.countBotRestart(botType)
+	s.countBotRestart(botType)
+
+	bots := s.snapshot().Bots
+	if bots[0].RestartCount != 2 {
+		t.Errorf("Unexpected restart count is returned: %d.", bots[0].RestartCount)
+	}
+	if bots[1].RestartCount != 0 {
+		t.Errorf("Restart is counted for another Bot: %d.", bots[1].RestartCount)
+	}
+}
@@ -1,10 +1,13 @@
 package sarah
 
 import (
+	"container/list"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/patrickmn/go-cache"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,7 +19,15 @@ type CacheConfig struct {
 	// CleanupInterval declares how often the expired items are removed from the storage.
 	// The default UserContextStorage's cache mechanism still holds references to expired values until a cleanup function runs and completely removes the expired values.
 	// However, cached items are considered "expired" once the expiration time is over, and they are not returned to the caller even though the value is still cached.
+	// The cleanup runs on the first Set call after this interval passes.
+	//
+	// Both the expiration and the cleanup are based on the Clock registered via RegisterClock.
 	CleanupInterval time.Duration `json:"cleanup_interval" yaml:"cleanup_interval"`
+
+	// MaxEntries declares the maximum number of stored UserContext values.
+	// When the number exceeds this limit, the least recently used value is evicted.
+	// Zero value means there is no limit.
+	MaxEntries int `json:"max_entries" yaml:"max_entries"`
 }
 
 // NewCacheConfig creates and returns a new CacheConfig instance with the default setting values.
@@ -25,6 +36,7 @@ func NewCacheConfig() *CacheConfig {
 	return &CacheConfig{
 		ExpiresIn:       3 * time.Minute,
 		CleanupInterval: 10 * time.Minute,
+		MaxEntries:      0,
 	}
 }
 
@@ -70,6 +82,16 @@ type UserContext struct {
 	// The pre-registered function is identified by SerializableArgument.FuncIdentifier.
 	// A reference implementation is available at https://github.com/oklahomer/go-sarah-rediscontext
 	Serializable *SerializableArgument
+
+	// TTL overrides how long this UserContext lives in the storage.
+	// When this is zero, the storage's default expiration is applied.
+	TTL time.Duration
+
+	// Sensitive tells that the user's next input is sensitive such as a password or a token.
+	// The next input is not recorded by the registered TranscriptStore and InputSink, and its message is masked in the logs.
+	// When the Bot's Adapter satisfies MessageDeleter, the user's message is deleted after its consumption.
+	// This is supported by the Bot that NewBot returns.
+	Sensitive bool
 }
 
 // NewUserContext creates and returns a new UserContext with the given ContextualFunc.
@@ -97,29 +119,113 @@ type UserContextStorage interface {
 	Flush() error
 }
 
+// UserContextStorageStats represents the statistics of a UserContextStorage.
+type UserContextStorageStats struct {
+	// Entries is the number of currently stored UserContext values.
+	// This may include expired values that are not yet cleaned up.
+	Entries int
+
+	// Hits is the number of Get calls that found a stored UserContext.
+	Hits uint64
+
+	// Misses is the number of Get calls that found no stored UserContext.
+	Misses uint64
+
+	// Evictions is the number of UserContext values evicted due to the size limit.
+	Evictions uint64
+}
+
+// HitRatio returns the ratio of Hits to the total number of Get calls.
+// Zero is returned when Get is never called.
+func (stats *UserContextStorageStats) HitRatio() float64 {
+	total := stats.Hits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(total)
+}
+
+// UserContextStorageStatsReporter defines an interface that a UserContextStorage implementation may satisfy to report its statistics.
+// When a Bot's UserContextStorage satisfies this, the statistics are included in the Bot's BotStatus.
+type UserContextStorageStatsReporter interface {
+	// Stats returns the current statistics.
+	Stats() *UserContextStorageStats
+}
+
 // defaultUserContextStorage is the default implementation of UserContextStorage.
 // This stores user contexts in the process memory space.
 type defaultUserContextStorage struct {
-	cache *cache.Cache
+	cache           *cache.Cache
+	lru             *lruIndex
+	expiresIn       time.Duration
+	cleanupInterval time.Duration
+	nextCleanup     time.Time
+	hits            uint64
+	misses          uint64
+	evictions       uint64
+
+	// mutex serializes the modifications of the stored values.
+	// This prevents a value that is just set from being removed as an expired or evicted one.
+	mutex sync.Mutex
 }
 
+// storedUserContext is a UserContext stored in defaultUserContextStorage along with its expiration time.
+type storedUserContext struct {
+	userContext *UserContext
+
+	// expiresAt is zero when the value never expires.
+	expiresAt time.Time
+}
+
+func (stored *storedUserContext) expired(now time.Time) bool {
+	return !stored.expiresAt.IsZero() && now.After(stored.expiresAt)
+}
+
+var _ UserContextStorageStatsReporter = (*defaultUserContextStorage)(nil)
+
 // NewUserContextStorage creates and returns a new defaultUserContextStorage instance to store users' conversational contexts.
 func NewUserContextStorage(config *CacheConfig) UserContextStorage {
-	return &defaultUserContextStorage{
-		cache: cache.New(config.ExpiresIn, config.CleanupInterval),
+	storage := &defaultUserContextStorage{
+		// The expiration is judged with the registered Clock instead of the cache's janitor that relies on the system clock.
+		cache:           cache.New(cache.NoExpiration, 0),
+		expiresIn:       config.ExpiresIn,
+		cleanupInterval: config.CleanupInterval,
 	}
+
+	if config.MaxEntries > 0 {
+		storage.lru = newLRUIndex(config.MaxEntries)
+		// Called when a value is deleted or is cleaned up on expiration.
+		storage.cache.OnEvicted(func(key string, _ interface{}) {
+			storage.lru.remove(key)
+		})
+	}
+
+	return storage
 }
 
 // Get searches for the user's stored state with the given user key, and return it if one is found.
 func (storage *defaultUserContextStorage) Get(key string) (ContextualFunc, error) {
+	// Look up and touch under the same lock; otherwise, a concurrent Delete or eviction in between lets touch track a key whose value is already gone.
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
 	val, hasKey := storage.cache.Get(key)
 	if !hasKey || val == nil {
+		atomic.AddUint64(&storage.misses, 1)
 		return nil, nil
 	}
 
 	switch v := val.(type) {
-	case *UserContext:
-		return v.Next, nil
+	case *storedUserContext:
+		if v.expired(currentClock().Now()) {
+			// Removed on the next cleanup.
+			atomic.AddUint64(&storage.misses, 1)
+			return nil, nil
+		}
+
+		atomic.AddUint64(&storage.hits, 1)
+		storage.touch(key)
+		return v.userContext.Next, nil
 
 	default:
 		return nil, fmt.Errorf("cached value has illegal type of %T", v)
@@ -130,6 +236,9 @@ func (storage *defaultUserContextStorage) Get(key string) (ContextualFunc, error
 // Delete removes a currently stored user's conversational context.
 // This does nothing if a corresponding context is not stored.
 func (storage *defaultUserContextStorage) Delete(key string) error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
 	storage.cache.Delete(key)
 	return nil
 }
@@ -141,12 +250,132 @@ func (storage *defaultUserContextStorage) Set(key string, userContext *UserConte
 		return errors.New("required UserContext.Next is not set. defaultUserContextStorage only supports in-memory ContextualFunc cache")
 	}
 
-	storage.cache.Set(key, userContext, cache.DefaultExpiration)
+	ttl := storage.expiresIn
+	if userContext.TTL > 0 {
+		ttl = userContext.TTL
+	}
+
+	now := currentClock().Now()
+	stored := &storedUserContext{
+		userContext: userContext,
+	}
+	if ttl > 0 {
+		stored.expiresAt = now.Add(ttl)
+	}
+
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	storage.cache.Set(key, stored, cache.NoExpiration)
+	storage.touch(key)
+	storage.cleanup(now)
 	return nil
 }
 
+// cleanup removes the expired values when CacheConfig.CleanupInterval has passed since the last cleanup.
+// This must be called while holding the mutex.
+func (storage *defaultUserContextStorage) cleanup(now time.Time) {
+	if storage.cleanupInterval <= 0 || now.Before(storage.nextCleanup) {
+		return
+	}
+	storage.nextCleanup = now.Add(storage.cleanupInterval)
+
+	for key, item := range storage.cache.Items() {
+		if stored, ok := item.Object.(*storedUserContext); ok && stored.expired(now) {
+			storage.cache.Delete(key)
+		}
+	}
+}
+
 // Flush removes all stored UserContext values.
 func (storage *defaultUserContextStorage) Flush() error {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
 	storage.cache.Flush()
+	if storage.lru != nil {
+		storage.lru.reset()
+	}
 	return nil
 }
+
+// Stats returns the current statistics.
+func (storage *defaultUserContextStorage) Stats() *UserContextStorageStats {
+	return &UserContextStorageStats{
+		Entries:   storage.cache.ItemCount(),
+		Hits:      atomic.LoadUint64(&storage.hits),
+		Misses:    atomic.LoadUint64(&storage.misses),
+		Evictions: atomic.LoadUint64(&storage.evictions),
+	}
+}
+
+// touch marks the given key as the most recently used one and evicts the least recently used values that exceed the limit.
+// This must be called while holding the mutex so a concurrent Set can not store a new value with an evicted key before the key is deleted.
+func (storage *defaultUserContextStorage) touch(key string) {
+	if storage.lru == nil {
+		return
+	}
+
+	// Eviction must be done without holding lruIndex's lock because cache.Delete calls the OnEvicted callback, which acquires the lock.
+	for _, evicted := range storage.lru.touch(key) {
+		storage.cache.Delete(evicted)
+		atomic.AddUint64(&storage.evictions, 1)
+	}
+}
+
+// lruIndex keeps track of the keys' usage order so the least recently used key can be evicted.
+type lruIndex struct {
+	max      int
+	order    *list.List
+	elements map[string]*list.Element
+	mutex    sync.Mutex
+}
+
+func newLRUIndex(max int) *lruIndex {
+	return &lruIndex{
+		max:      max,
+		order:    list.New(),
+		elements: map[string]*list.Element{},
+	}
+}
+
+// touch marks the given key as the most recently used one and returns the keys that exceed the limit.
+// The returned keys are already removed from the index.
+func (l *lruIndex) touch(key string) []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if elem, ok := l.elements[key]; ok {
+		l.order.MoveToFront(elem)
+	} else {
+		l.elements[key] = l.order.PushFront(key)
+	}
+
+	var evicted []string
+	for l.order.Len() > l.max {
+		elem := l.order.Back()
+		k := elem.Value.(string)
+		l.order.Remove(elem)
+		delete(l.elements, k)
+		evicted = append(evicted, k)
+	}
+	return evicted
+}
+
+func (l *lruIndex) remove(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if elem, ok := l.elements[key]; ok {
+		l.order.Remove(elem)
+		delete(l.elements, key)
+	}
+}
+
+func (l *lruIndex) reset() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.order.Init()
+	l.elements = map[string]*list.Element{}
+}
@@ -3,6 +3,7 @@ package sarah
 import (
 	"context"
 	"github.com/patrickmn/go-cache"
+	"sync"
 	"testing"
 	"time"
 )
@@ -85,3 +86,185 @@ func TestDefaultUserContextStorage_CRUD(t *testing.T) {
 		t.Errorf("Invalid stored value shouldn't be returned: %T", invalidVal)
 	}
 }
+
+func TestNewUserContextStorage_WithMaxEntries(t *testing.T) {
+	config := NewCacheConfig()
+	config.MaxEntries = 2
+	storage := NewUserContextStorage(config).(*defaultUserContextStorage)
+
+	if storage.lru == nil {
+		t.Fatal("LRU index must be set when MaxEntries is given.")
+	}
+}
+
+func TestDefaultUserContextStorage_LRU(t *testing.T) {
+	config := NewCacheConfig()
+	config.MaxEntries = 2
+	storage := NewUserContextStorage(config).(*defaultUserContextStorage)
+	next := func(_ context.Context, _ Input) (*CommandResponse, error) { return nil, nil }
+
+	_ = storage.Set("a", NewUserContext(next))
+	_ = storage.Set("b", NewUserContext(next))
+	// Mark "a" as recently used so "b" is evicted next.
+	_, _ = storage.Get("a")
+	_ = storage.Set("c", NewUserContext(next))
+
+	if val, _ := storage.Get("b"); val != nil {
+		t.Error("Least recently used value must be evicted.")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if val, _ := storage.Get(key); val == nil {
+			t.Errorf("Recently used value must not be evicted: %s.", key)
+		}
+	}
+
+	_ = storage.Delete("a")
+	if storage.lru.order.Len() != 1 {
+		t.Errorf("Deleted key must be removed from LRU index: %d.", storage.lru.order.Len())
+	}
+
+	_ = storage.Flush()
+	if storage.lru.order.Len() != 0 {
+		t.Errorf("LRU index must be reset on flush: %d.", storage.lru.order.Len())
+	}
+}
+
+func TestDefaultUserContextStorage_LRU_Concurrent(t *testing.T) {
+	config := NewCacheConfig()
+	config.MaxEntries = 2
+	storage := NewUserContextStorage(config).(*defaultUserContextStorage)
+	next := func(_ context.Context, _ Input) (*CommandResponse, error) { return nil, nil }
+
+	keys := []string{"a", "b", "c", "d"}
+	wg := &sync.WaitGroup{}
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := keys[(i+j)%len(keys)]
+				_ = storage.Set(key, NewUserContext(next))
+				_, _ = storage.Get(keys[(i+j+1)%len(keys)])
+				_ = storage.Delete(keys[(i+j+2)%len(keys)])
+				_, _ = storage.Get(keys[(i+j+2)%len(keys)])
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if storage.cache.ItemCount() > config.MaxEntries {
+		t.Errorf("Unexpected number of values are stored: %d.", storage.cache.ItemCount())
+	}
+	for key := range storage.cache.Items() {
+		if _, ok := storage.lru.elements[key]; !ok {
+			t.Errorf("Stored key is not tracked by LRU index: %s.", key)
+		}
+	}
+	if storage.lru.order.Len() != storage.cache.ItemCount() {
+		t.Errorf("LRU index and stored values are inconsistent: %d, %d.", storage.lru.order.Len(), storage.cache.ItemCount())
+	}
+}
+
+func TestDefaultUserContextStorage_Stats(t *testing.T) {
+	config := NewCacheConfig()
+	config.MaxEntries = 1
+	storage := NewUserContextStorage(config).(*defaultUserContextStorage)
+	next := func(_ context.Context, _ Input) (*CommandResponse, error) { return nil, nil }
+
+	_ = storage.Set("a", NewUserContext(next))
+	_ = storage.Set("b", NewUserContext(next))
+	_, _ = storage.Get("a")
+	_, _ = storage.Get("b")
+
+	stats := storage.Stats()
+	if stats.Entries != 1 {
+		t.Errorf("Unexpected number of entries: %d.", stats.Entries)
+	}
+
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Unexpected hits and misses: %d, %d.", stats.Hits, stats.Misses)
+	}
+
+	if stats.Evictions != 1 {
+		t.Errorf("Unexpected number of evictions: %d.", stats.Evictions)
+	}
+}
+
+func TestUserContextStorageStats_HitRatio(t *testing.T) {
+	stats := &UserContextStorageStats{}
+	if stats.HitRatio() != 0 {
+		t.Errorf("Unexpected ratio is returned: %f.", stats.HitRatio())
+	}
+
+	stats = &UserContextStorageStats{Hits: 3, Misses: 1}
+	if stats.HitRatio() != 0.75 {
+		t.Errorf("Unexpected ratio is returned: %f.", stats.HitRatio())
+	}
+}
+
+func TestDefaultUserContextStorage_Set_WithTTL(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		storage := NewUserContextStorage(NewCacheConfig()).(*defaultUserContextStorage)
+		next := func(_ context.Context, _ Input) (*CommandResponse, error) { return nil, nil }
+		userContext := NewUserContext(next)
+		userContext.TTL = 1 * time.Hour
+
+		_ = storage.Set("default", NewUserContext(next))
+		_ = storage.Set("ttl", userContext)
+
+		now = now.Add(30 * time.Minute)
+		if val, _ := storage.Get("default"); val != nil {
+			t.Error("Value must expire with the default expiration.")
+		}
+		if val, _ := storage.Get("ttl"); val == nil {
+			t.Error("TTL is not applied.")
+		}
+
+		now = now.Add(31 * time.Minute)
+		if val, _ := storage.Get("ttl"); val != nil {
+			t.Error("Value must expire with the given TTL.")
+		}
+	})
+}
+
+func TestDefaultUserContextStorage_cleanup(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+	c := &DummyClock{
+		NowFunc: func() time.Time {
+			return now
+		},
+	}
+
+	withClock(c, func() {
+		config := NewCacheConfig()
+		config.ExpiresIn = time.Minute
+		config.CleanupInterval = 10 * time.Minute
+		storage := NewUserContextStorage(config).(*defaultUserContextStorage)
+		next := func(_ context.Context, _ Input) (*CommandResponse, error) { return nil, nil }
+
+		_ = storage.Set("first", NewUserContext(next))
+
+		now = now.Add(5 * time.Minute)
+		_ = storage.Set("second", NewUserContext(next))
+		if storage.cache.ItemCount() != 2 {
+			t.Errorf("Expired value must be kept until the cleanup interval passes: %d.", storage.cache.ItemCount())
+		}
+
+		now = now.Add(5 * time.Minute)
+		_ = storage.Set("third", NewUserContext(next))
+		if storage.cache.ItemCount() != 1 {
+			t.Errorf("Expired values are not removed: %d.", storage.cache.ItemCount())
+		}
+		if val, _ := storage.Get("third"); val == nil {
+			t.Error("Value that is not expired must not be removed.")
+		}
+	})
+}
@@ -0,0 +1,164 @@
+// Package bolt provides storage implementations that are backed by BoltDB.
+//
+// BoltDB is an embedded key-value database, so a single-process bot can persist its plugins' state to a local file without an external database server.
+// Since the database file is locked while it is open, the file can not be shared among multiple processes.
+// Use the Redis or DynamoDB implementation to share the state among replicas.
+//
+//	store, err := bolt.NewKVStore(bolt.NewConfig())
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer store.Close()
+//	sarah.RegisterKVStore(store)
+package bolt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"go.etcd.io/bbolt"
+	"time"
+)
+
+// Config contains some configuration variables for BoltDB-backed storages.
+type Config struct {
+	// Path declares the path to the database file. The file is created when it does not exist.
+	Path string `json:"path" yaml:"path"`
+
+	// Timeout declares how long to wait for the lock of the database file that another process holds.
+	// Zero value means waiting indefinitely.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		Path:    "sarah.db",
+		Timeout: 1 * time.Second,
+	}
+}
+
+// KVStore is a sarah.KVStore implementation that stores each namespace as a BoltDB bucket.
+type KVStore struct {
+	db *bbolt.DB
+}
+
+var _ sarah.KVStore = (*KVStore)(nil)
+
+// NewKVStore opens the database file declared by the given Config, and then returns a new KVStore instance.
+// Call KVStore.Close to release the database file when the KVStore is no longer used.
+func NewKVStore(config *Config) (*KVStore, error) {
+	db, err := bbolt.Open(config.Path, 0600, &bbolt.Options{Timeout: config.Timeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open BoltDB file %s: %w", config.Path, err)
+	}
+
+	return &KVStore{
+		db: db,
+	}, nil
+}
+
+// Close releases the database file.
+func (s *KVStore) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the value stored with the given namespace and key.
+// sarah.ErrKVNotFound is returned when no corresponding value is stored.
+func (s *KVStore) Get(ctx context.Context, namespace string, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return sarah.ErrKVNotFound
+		}
+
+		stored := bucket.Get([]byte(key))
+		if stored == nil {
+			return sarah.ErrKVNotFound
+		}
+
+		// The stored value is only valid while the transaction is open.
+		value = make([]byte, len(stored))
+		copy(value, stored)
+		return nil
+	})
+	if errors.Is(err, sarah.ErrKVNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get value from BoltDB: %w", err)
+	}
+
+	return value, nil
+}
+
+// Set stores the given value with the given namespace and key.
+func (s *KVStore) Set(ctx context.Context, namespace string, key string, value []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(namespace))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(key), value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set value to BoltDB: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the value stored with the given namespace and key.
+func (s *KVStore) Delete(ctx context.Context, namespace string, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete value from BoltDB: %w", err)
+	}
+	return nil
+}
+
+// List returns the keys stored under the given namespace in ascending order.
+func (s *KVStore) List(ctx context.Context, namespace string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+
+		// BoltDB iterates over the keys in byte-sorted order.
+		return bucket.ForEach(func(k, _ []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys from BoltDB: %w", err)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,177 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestKVStore(t *testing.T) *KVStore {
+	config := NewConfig()
+	config.Path = filepath.Join(t.TempDir(), "sarah.db")
+	store, err := NewKVStore(config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+	return store
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config.Path == "" {
+		t.Error("Default path is not set.")
+	}
+	if config.Timeout <= 0 {
+		t.Errorf("Unexpected default timeout is set: %s.", config.Timeout)
+	}
+}
+
+func TestNewKVStore(t *testing.T) {
+	store := newTestKVStore(t)
+	if store.db == nil {
+		t.Error("Database is not opened.")
+	}
+}
+
+func TestNewKVStore_Locked(t *testing.T) {
+	config := NewConfig()
+	config.Path = filepath.Join(t.TempDir(), "sarah.db")
+	config.Timeout = 10 * time.Millisecond
+	store, err := NewKVStore(config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	defer store.Close()
+
+	_, err = NewKVStore(config)
+	if err == nil {
+		t.Error("Expected error is not returned while another KVStore holds the file.")
+	}
+}
+
+func TestKVStore_SetAndGet(t *testing.T) {
+	store := newTestKVStore(t)
+
+	_, err := store.Get(context.TODO(), "ns", "key")
+	if !errors.Is(err, sarah.ErrKVNotFound) {
+		t.Errorf("Expected error is not returned for an unknown namespace: %#v.", err)
+	}
+
+	err = store.Set(context.TODO(), "ns", "key", []byte("value"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	value, err := store.Get(context.TODO(), "ns", "key")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if string(value) != "value" {
+		t.Errorf("Unexpected value is returned: %s.", value)
+	}
+
+	_, err = store.Get(context.TODO(), "ns", "unknown")
+	if !errors.Is(err, sarah.ErrKVNotFound) {
+		t.Errorf("Expected error is not returned for an unknown key: %#v.", err)
+	}
+
+	_, err = store.Get(context.TODO(), "other", "key")
+	if !errors.Is(err, sarah.ErrKVNotFound) {
+		t.Errorf("Value of another namespace should not be returned: %#v.", err)
+	}
+}
+
+func TestKVStore_Delete(t *testing.T) {
+	store := newTestKVStore(t)
+
+	err := store.Delete(context.TODO(), "ns", "key")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned for an unknown namespace: %s.", err.Error())
+	}
+
+	_ = store.Set(context.TODO(), "ns", "key", []byte("value"))
+	err = store.Delete(context.TODO(), "ns", "key")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	_, err = store.Get(context.TODO(), "ns", "key")
+	if !errors.Is(err, sarah.ErrKVNotFound) {
+		t.Errorf("Deleted value is still stored: %#v.", err)
+	}
+}
+
+func TestKVStore_List(t *testing.T) {
+	store := newTestKVStore(t)
+
+	keys, err := store.List(context.TODO(), "ns")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(keys) != 0 {
+		t.Errorf("Unexpected keys are returned: %#v.", keys)
+	}
+
+	for _, key := range []string{"b", "a"} {
+		_ = store.Set(context.TODO(), "ns", key, []byte("value"))
+	}
+	_ = store.Set(context.TODO(), "other", "c", []byte("value"))
+
+	keys, err = store.List(context.TODO(), "ns")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("Unexpected keys are returned: %#v.", keys)
+	}
+}
+
+func TestKVStore_Persistence(t *testing.T) {
+	config := NewConfig()
+	config.Path = filepath.Join(t.TempDir(), "sarah.db")
+	store, err := NewKVStore(config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	_ = store.Set(context.TODO(), "ns", "key", []byte("value"))
+	_ = store.Close()
+
+	reopened, err := NewKVStore(config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	defer reopened.Close()
+
+	value, err := reopened.Get(context.TODO(), "ns", "key")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if string(value) != "value" {
+		t.Errorf("Unexpected value is returned: %s.", value)
+	}
+}
+
+func TestKVStore_CanceledContext(t *testing.T) {
+	store := newTestKVStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := store.Get(ctx, "ns", "key"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error is not returned on Get: %#v.", err)
+	}
+	if err := store.Set(ctx, "ns", "key", []byte("value")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error is not returned on Set: %#v.", err)
+	}
+	if err := store.Delete(ctx, "ns", "key"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error is not returned on Delete: %#v.", err)
+	}
+	if _, err := store.List(ctx, "ns"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error is not returned on List: %#v.", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package dynamodb provides sarah.UserContextStorage and sarah.KVStore implementations that are backed by Amazon DynamoDB.
+//
+// To avoid binding this project to a particular version of AWS SDK, this package depends on Client interface,
+// which covers the subset of DynamoDB operations that the storages require.
+// Implement Client with a thin wrapper of the preferred SDK, and configure credentials, regions, endpoints, and HTTP settings there.
+// The wrapper must return an error that satisfies errors.Is(err, ErrThrottled) when a request is throttled
+// -- e.g. ProvisionedThroughputExceededException or ThrottlingException -- so the storages can retry with exponential backoff.
+//
+// Each table must have a string partition key named "pk" and a string sort key named "sk".
+// Enable DynamoDB's Time to Live feature on the "expires_at" attribute so expired user contexts are removed automatically.
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrThrottled represents that a request is throttled by DynamoDB.
+// Client implementation must return an error that wraps this so the request is retried.
+var ErrThrottled = errors.New("request is throttled")
+
+// Item represents an item stored in a DynamoDB table.
+type Item struct {
+	// PartitionKey is stored as the "pk" attribute.
+	PartitionKey string
+
+	// SortKey is stored as the "sk" attribute.
+	SortKey string
+
+	// FuncIdentifier is stored as the "func_identifier" attribute.
+	FuncIdentifier string
+
+	// Value is stored as the "value" attribute of binary type.
+	Value []byte
+
+	// ExpiresAt is stored as the "expires_at" attribute in Unix epoch seconds, which DynamoDB TTL refers to.
+	// Zero value means the item does not expire.
+	ExpiresAt int64
+}
+
+func (item *Item) expired(now time.Time) bool {
+	return item.ExpiresAt > 0 && item.ExpiresAt <= now.Unix()
+}
+
+// Client defines an interface of a DynamoDB client that the storages depend on.
+type Client interface {
+	// GetItem returns the item with the given keys.
+	// This must return nil item and nil error when the item does not exist.
+	GetItem(ctx context.Context, table string, partitionKey string, sortKey string) (*Item, error)
+
+	// PutItem stores the given item. An existing item with the same keys is replaced.
+	PutItem(ctx context.Context, table string, item *Item) error
+
+	// DeleteItem removes the item with the given keys.
+	DeleteItem(ctx context.Context, table string, partitionKey string, sortKey string) error
+
+	// Query returns all items with the given partition key.
+	Query(ctx context.Context, table string, partitionKey string) ([]*Item, error)
+
+	// Scan returns all items in the given table.
+	Scan(ctx context.Context, table string) ([]*Item, error)
+}
+
+// BackoffConfig contains some configuration variables for the exponential backoff on throttling.
+type BackoffConfig struct {
+	// MaxRetries declares how many times a throttled request is retried.
+	MaxRetries int `json:"max_retries" yaml:"max_retries"`
+
+	// BaseDelay declares the delay before the first retry. The delay doubles on each retry.
+	BaseDelay time.Duration `json:"base_delay" yaml:"base_delay"`
+
+	// MaxDelay declares the upper limit of the delay.
+	MaxDelay time.Duration `json:"max_delay" yaml:"max_delay"`
+}
+
+// NewBackoffConfig creates and returns a new BackoffConfig instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewBackoffConfig() *BackoffConfig {
+	return &BackoffConfig{
+		MaxRetries: 5,
+		BaseDelay:  50 * time.Millisecond,
+		MaxDelay:   2 * time.Second,
+	}
+}
+
+// withBackoff calls the given function and retries with exponential backoff while the returned error represents throttling.
+func withBackoff(ctx context.Context, config *BackoffConfig, fnc func() error) error {
+	delay := config.BaseDelay
+	for i := 0; ; i++ {
+		err := fnc()
+		if err == nil || !errors.Is(err, ErrThrottled) || i >= config.MaxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+
+		case <-timer.C:
+			// Continue.
+
+		}
+
+		delay *= 2
+		if delay > config.MaxDelay {
+			delay = config.MaxDelay
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type DummyClient struct {
+	GetItemFunc    func(context.Context, string, string, string) (*Item, error)
+	PutItemFunc    func(context.Context, string, *Item) error
+	DeleteItemFunc func(context.Context, string, string, string) error
+	QueryFunc      func(context.Context, string, string) ([]*Item, error)
+	ScanFunc       func(context.Context, string) ([]*Item, error)
+}
+
+func (c *DummyClient) GetItem(ctx context.Context, table string, partitionKey string, sortKey string) (*Item, error) {
+	return c.GetItemFunc(ctx, table, partitionKey, sortKey)
+}
+
+func (c *DummyClient) PutItem(ctx context.Context, table string, item *Item) error {
+	return c.PutItemFunc(ctx, table, item)
+}
+
+func (c *DummyClient) DeleteItem(ctx context.Context, table string, partitionKey string, sortKey string) error {
+	return c.DeleteItemFunc(ctx, table, partitionKey, sortKey)
+}
+
+func (c *DummyClient) Query(ctx context.Context, table string, partitionKey string) ([]*Item, error) {
+	return c.QueryFunc(ctx, table, partitionKey)
+}
+
+func (c *DummyClient) Scan(ctx context.Context, table string) ([]*Item, error) {
+	return c.ScanFunc(ctx, table)
+}
+
+func TestNewBackoffConfig(t *testing.T) {
+	config := NewBackoffConfig()
+	if config.MaxRetries <= 0 {
+		t.Errorf("Unexpected MaxRetries is set: %d.", config.MaxRetries)
+	}
+}
+
+func TestItem_expired(t *testing.T) {
+	now := time.Now()
+	testSets := []struct {
+		expiresAt int64
+		expired   bool
+	}{
+		{expiresAt: 0, expired: false},
+		{expiresAt: now.Add(1 * time.Minute).Unix(), expired: false},
+		{expiresAt: now.Add(-1 * time.Minute).Unix(), expired: true},
+	}
+
+	for i, testSet := range testSets {
+		item := &Item{ExpiresAt: testSet.expiresAt}
+		if item.expired(now) != testSet.expired {
+			t.Errorf("Unexpected result is returned on test %d.", i)
+		}
+	}
+}
+
+func Test_withBackoff(t *testing.T) {
+	config := &BackoffConfig{
+		MaxRetries: 2,
+		BaseDelay:  1 * time.Millisecond,
+		MaxDelay:   2 * time.Millisecond,
+	}
+	throttled := fmt.Errorf("provisioned throughput exceeded: %w", ErrThrottled)
+
+	testSets := []struct {
+		errs  []error
+		calls int
+		err   error
+	}{
+		{
+			errs:  []error{nil},
+			calls: 1,
+		},
+		{
+			errs:  []error{throttled, nil},
+			calls: 2,
+		},
+		{
+			errs:  []error{throttled, throttled, throttled},
+			calls: 3,
+			err:   ErrThrottled,
+		},
+		{
+			errs:  []error{errors.New("validation error")},
+			calls: 1,
+			err:   errors.New("validation error"),
+		},
+	}
+
+	for i, testSet := range testSets {
+		calls := 0
+		err := withBackoff(context.TODO(), config, func() error {
+			e := testSet.errs[calls]
+			calls++
+			return e
+		})
+
+		if calls != testSet.calls {
+			t.Errorf("Unexpected number of calls on test %d: %d.", i, calls)
+		}
+
+		if testSet.err == nil && err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+		} else if testSet.err != nil && (err == nil || err.Error() != testSet.err.Error() && !errors.Is(err, testSet.err)) {
+			t.Errorf("Expected error is not returned on test %d: %#v.", i, err)
+		}
+	}
+}
+
+func Test_withBackoff_ContextCancel(t *testing.T) {
+	config := &BackoffConfig{
+		MaxRetries: 10,
+		BaseDelay:  1 * time.Second,
+		MaxDelay:   1 * time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := withBackoff(ctx, config, func() error {
+		calls++
+		return ErrThrottled
+	})
+
+	if !errors.Is(err, ErrThrottled) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Unexpected number of calls: %d.", calls)
+	}
+}
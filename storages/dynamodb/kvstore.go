@@ -0,0 +1,106 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"sort"
+)
+
+// KVStore is a sarah.KVStore implementation that stores values in DynamoDB.
+// Each namespace is stored as a partition key and each key is stored as a sort key.
+type KVStore struct {
+	config *Config
+	client Client
+}
+
+var _ sarah.KVStore = (*KVStore)(nil)
+
+// NewKVStore creates and returns a new KVStore instance.
+func NewKVStore(config *Config, client Client) *KVStore {
+	return &KVStore{
+		config: config,
+		client: client,
+	}
+}
+
+// Get returns the value stored with the given namespace and key.
+// sarah.ErrKVNotFound is returned when no corresponding value is stored.
+func (s *KVStore) Get(ctx context.Context, namespace string, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var item *Item
+	err := withBackoff(ctx, s.config.Backoff, func() error {
+		var e error
+		item, e = s.client.GetItem(ctx, s.config.KVTable, namespace, key)
+		return e
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get value from DynamoDB: %w", err)
+	}
+
+	if item == nil {
+		return nil, sarah.ErrKVNotFound
+	}
+
+	return item.Value, nil
+}
+
+// Set stores the given value with the given namespace and key.
+func (s *KVStore) Set(ctx context.Context, namespace string, key string, value []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	item := &Item{
+		PartitionKey: namespace,
+		SortKey:      key,
+		Value:        value,
+	}
+	err := withBackoff(ctx, s.config.Backoff, func() error {
+		return s.client.PutItem(ctx, s.config.KVTable, item)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set value to DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the value stored with the given namespace and key.
+func (s *KVStore) Delete(ctx context.Context, namespace string, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	err := withBackoff(ctx, s.config.Backoff, func() error {
+		return s.client.DeleteItem(ctx, s.config.KVTable, namespace, key)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete value from DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the keys stored under the given namespace in ascending order.
+func (s *KVStore) List(ctx context.Context, namespace string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var items []*Item
+	err := withBackoff(ctx, s.config.Backoff, func() error {
+		var e error
+		items, e = s.client.Query(ctx, s.config.KVTable, namespace)
+		return e
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys from DynamoDB: %w", err)
+	}
+
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.SortKey)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,117 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+)
+
+func TestNewKVStore(t *testing.T) {
+	config := NewConfig()
+	client := &DummyClient{}
+	store := NewKVStore(config, client)
+
+	if store.config != config {
+		t.Error("Given config is not set.")
+	}
+
+	if store.client != client {
+		t.Error("Given client is not set.")
+	}
+}
+
+func TestKVStore_Get(t *testing.T) {
+	testSets := []struct {
+		item *Item
+		err  error
+	}{
+		{
+			item: &Item{Value: []byte("value")},
+		},
+		{
+			item: nil,
+			err:  sarah.ErrKVNotFound,
+		},
+	}
+
+	for i, testSet := range testSets {
+		client := &DummyClient{
+			GetItemFunc: func(_ context.Context, table string, partitionKey string, sortKey string) (*Item, error) {
+				if table != "sarah_kv" || partitionKey != "ns" || sortKey != "key" {
+					t.Errorf("Unexpected arguments are given on test %d: %s, %s, %s.", i, table, partitionKey, sortKey)
+				}
+				return testSet.item, nil
+			},
+		}
+		store := NewKVStore(NewConfig(), client)
+
+		value, err := store.Get(context.TODO(), "ns", "key")
+		if testSet.err != nil {
+			if !errors.Is(err, testSet.err) {
+				t.Errorf("Expected error is not returned on test %d: %#v.", i, err)
+			}
+			continue
+		}
+
+		if string(value) != "value" {
+			t.Errorf("Unexpected value is returned on test %d: %s.", i, value)
+		}
+	}
+}
+
+func TestKVStore_Set(t *testing.T) {
+	var stored *Item
+	client := &DummyClient{
+		PutItemFunc: func(_ context.Context, _ string, item *Item) error {
+			stored = item
+			return nil
+		},
+	}
+	store := NewKVStore(NewConfig(), client)
+
+	err := store.Set(context.TODO(), "ns", "key", []byte("value"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if stored.PartitionKey != "ns" || stored.SortKey != "key" || string(stored.Value) != "value" || stored.ExpiresAt != 0 {
+		t.Errorf("Unexpected item is stored: %#v.", stored)
+	}
+}
+
+func TestKVStore_Delete(t *testing.T) {
+	expected := errors.New("access denied")
+	client := &DummyClient{
+		DeleteItemFunc: func(_ context.Context, _ string, _ string, _ string) error {
+			return expected
+		},
+	}
+	store := NewKVStore(NewConfig(), client)
+
+	err := store.Delete(context.TODO(), "ns", "key")
+	if !errors.Is(err, expected) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestKVStore_List(t *testing.T) {
+	client := &DummyClient{
+		QueryFunc: func(_ context.Context, _ string, partitionKey string) ([]*Item, error) {
+			if partitionKey != "ns" {
+				t.Errorf("Unexpected partition key is given: %s.", partitionKey)
+			}
+			return []*Item{{SortKey: "b"}, {SortKey: "a"}}, nil
+		},
+	}
+	store := NewKVStore(NewConfig(), client)
+
+	keys, err := store.List(context.TODO(), "ns")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("Unexpected keys are returned: %#v.", keys)
+	}
+}
@@ -0,0 +1,162 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/storages"
+	"time"
+)
+
+// userContextSortKey is the fixed sort key of user context items since each user has only one context at a time.
+const userContextSortKey = "user_context"
+
+// Config contains some configuration variables for DynamoDB-backed storages.
+type Config struct {
+	// UserContextTable declares the name of the table to store user contexts.
+	UserContextTable string `json:"user_context_table" yaml:"user_context_table"`
+
+	// KVTable declares the name of the table to store KVStore values.
+	KVTable string `json:"kv_table" yaml:"kv_table"`
+
+	// ExpiresIn declares how long a stored user context lives.
+	ExpiresIn time.Duration `json:"expires_in" yaml:"expires_in"`
+
+	// Timeout declares the timeout of each operation including retries.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// Backoff declares the exponential backoff setting on throttling.
+	Backoff *BackoffConfig `json:"backoff" yaml:"backoff"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		UserContextTable: "sarah_user_contexts",
+		KVTable:          "sarah_kv",
+		ExpiresIn:        3 * time.Minute,
+		Timeout:          5 * time.Second,
+		Backoff:          NewBackoffConfig(),
+	}
+}
+
+// UserContextStorage is a sarah.UserContextStorage implementation that stores serialized user contexts in DynamoDB.
+// Each item is given the "expires_at" attribute so DynamoDB TTL removes expired items.
+type UserContextStorage struct {
+	config   *Config
+	client   Client
+	registry *storages.FuncRegistry
+}
+
+var _ sarah.UserContextStorage = (*UserContextStorage)(nil)
+
+// NewUserContextStorage creates and returns a new UserContextStorage instance.
+// The given FuncRegistry is used to restore the stored user contexts.
+func NewUserContextStorage(config *Config, client Client, registry *storages.FuncRegistry) *UserContextStorage {
+	return &UserContextStorage{
+		config:   config,
+		client:   client,
+		registry: registry,
+	}
+}
+
+// Get searches for the user's stored state with the given user key, and return it if one is found.
+func (s *UserContextStorage) Get(key string) (sarah.ContextualFunc, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	var item *Item
+	err := withBackoff(ctx, s.config.Backoff, func() error {
+		var e error
+		item, e = s.client.GetItem(ctx, s.config.UserContextTable, key, userContextSortKey)
+		return e
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user context: %w", err)
+	}
+
+	// DynamoDB TTL removes expired items lazily, so the expiration must be checked here.
+	if item == nil || item.expired(time.Now()) {
+		return nil, nil
+	}
+
+	return s.registry.Restore(item.FuncIdentifier, item.Value)
+}
+
+// Set stores the given UserContext.
+// The stored context is tied to the given key, which represents a particular user.
+// UserContext.Serializable must be set and its FuncIdentifier must be registered to the FuncRegistry.
+func (s *UserContextStorage) Set(key string, userContext *sarah.UserContext) error {
+	id, argument, err := storages.Serialize(userContext)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	ttl := s.config.ExpiresIn
+	if userContext.TTL > 0 {
+		ttl = userContext.TTL
+	}
+	item := &Item{
+		PartitionKey:   key,
+		SortKey:        userContextSortKey,
+		FuncIdentifier: id,
+		Value:          argument,
+		ExpiresAt:      time.Now().Add(ttl).Unix(),
+	}
+	err = withBackoff(ctx, s.config.Backoff, func() error {
+		return s.client.PutItem(ctx, s.config.UserContextTable, item)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store user context: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a currently stored user's conversational context.
+// This does nothing if a corresponding context is not stored.
+func (s *UserContextStorage) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	err := withBackoff(ctx, s.config.Backoff, func() error {
+		return s.client.DeleteItem(ctx, s.config.UserContextTable, key, userContextSortKey)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete user context: %w", err)
+	}
+
+	return nil
+}
+
+// Flush removes all stored UserContext values.
+// This scans the whole table, so avoid calling this frequently on a large table.
+func (s *UserContextStorage) Flush() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	var items []*Item
+	err := withBackoff(ctx, s.config.Backoff, func() error {
+		var e error
+		items, e = s.client.Scan(ctx, s.config.UserContextTable)
+		return e
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan user contexts: %w", err)
+	}
+
+	for _, item := range items {
+		err = withBackoff(ctx, s.config.Backoff, func() error {
+			return s.client.DeleteItem(ctx, s.config.UserContextTable, item.PartitionKey, item.SortKey)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete user context: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/storages"
+	"testing"
+	"time"
+)
+
+type arg struct {
+	Value string `json:"value"`
+}
+
+func newRegistry() *storages.FuncRegistry {
+	registry := storages.NewFuncRegistry()
+	storages.RegisterFunc(registry, "id", func(_ context.Context, _ sarah.Input, a *arg) (*sarah.CommandResponse, error) {
+		return &sarah.CommandResponse{Content: a.Value}, nil
+	})
+	return registry
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config.UserContextTable == "" || config.KVTable == "" {
+		t.Error("Default table names are not set.")
+	}
+
+	if config.Backoff == nil {
+		t.Error("Default backoff setting is not set.")
+	}
+}
+
+func TestUserContextStorage_Get(t *testing.T) {
+	testSets := []struct {
+		item  *Item
+		found bool
+	}{
+		{
+			item: &Item{
+				FuncIdentifier: "id",
+				Value:          []byte(`{"value":"foo"}`),
+				ExpiresAt:      time.Now().Add(1 * time.Minute).Unix(),
+			},
+			found: true,
+		},
+		{
+			item: &Item{
+				FuncIdentifier: "id",
+				Value:          []byte(`{"value":"foo"}`),
+				ExpiresAt:      time.Now().Add(-1 * time.Minute).Unix(),
+			},
+			found: false,
+		},
+		{
+			item:  nil,
+			found: false,
+		},
+	}
+
+	for i, testSet := range testSets {
+		client := &DummyClient{
+			GetItemFunc: func(_ context.Context, table string, partitionKey string, sortKey string) (*Item, error) {
+				if table != "sarah_user_contexts" || partitionKey != "key" || sortKey != userContextSortKey {
+					t.Errorf("Unexpected arguments are given on test %d: %s, %s, %s.", i, table, partitionKey, sortKey)
+				}
+				return testSet.item, nil
+			},
+		}
+		storage := NewUserContextStorage(NewConfig(), client, newRegistry())
+
+		fnc, err := storage.Get("key")
+		if err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+			continue
+		}
+
+		if !testSet.found {
+			if fnc != nil {
+				t.Errorf("Nil should be returned on test %d.", i)
+			}
+			continue
+		}
+
+		res, _ := fnc(context.TODO(), nil)
+		if res.Content != "foo" {
+			t.Errorf("Unexpected function is restored on test %d: %#v.", i, res.Content)
+		}
+	}
+}
+
+func TestUserContextStorage_Get_WithError(t *testing.T) {
+	expected := errors.New("access denied")
+	client := &DummyClient{
+		GetItemFunc: func(_ context.Context, _ string, _ string, _ string) (*Item, error) {
+			return nil, expected
+		},
+	}
+	storage := NewUserContextStorage(NewConfig(), client, newRegistry())
+
+	_, err := storage.Get("key")
+	if !errors.Is(err, expected) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestUserContextStorage_Set(t *testing.T) {
+	var stored *Item
+	client := &DummyClient{
+		PutItemFunc: func(_ context.Context, _ string, item *Item) error {
+			stored = item
+			return nil
+		},
+	}
+	storage := NewUserContextStorage(NewConfig(), client, newRegistry())
+
+	err := storage.Set("key", &sarah.UserContext{
+		Serializable: &sarah.SerializableArgument{
+			FuncIdentifier: "id",
+			Argument:       &arg{Value: "foo"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if stored == nil {
+		t.Fatal("Item is not stored.")
+	}
+
+	if stored.PartitionKey != "key" || stored.FuncIdentifier != "id" || string(stored.Value) != `{"value":"foo"}` {
+		t.Errorf("Unexpected item is stored: %#v.", stored)
+	}
+
+	if stored.ExpiresAt <= time.Now().Unix() {
+		t.Errorf("Unexpected expiration is set: %d.", stored.ExpiresAt)
+	}
+}
+
+func TestUserContextStorage_Set_WithoutSerializable(t *testing.T) {
+	storage := NewUserContextStorage(NewConfig(), &DummyClient{}, newRegistry())
+
+	err := storage.Set("key", sarah.NewUserContext(nil))
+	if !errors.Is(err, storages.ErrNoSerializable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestUserContextStorage_Delete(t *testing.T) {
+	called := false
+	client := &DummyClient{
+		DeleteItemFunc: func(_ context.Context, _ string, partitionKey string, _ string) error {
+			called = true
+			if partitionKey != "key" {
+				t.Errorf("Unexpected key is given: %s.", partitionKey)
+			}
+			return nil
+		},
+	}
+	storage := NewUserContextStorage(NewConfig(), client, newRegistry())
+
+	err := storage.Delete("key")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if !called {
+		t.Error("Client is not called.")
+	}
+}
+
+func TestUserContextStorage_Flush(t *testing.T) {
+	var deleted []string
+	client := &DummyClient{
+		ScanFunc: func(_ context.Context, _ string) ([]*Item, error) {
+			return []*Item{
+				{PartitionKey: "a", SortKey: userContextSortKey},
+				{PartitionKey: "b", SortKey: userContextSortKey},
+			}, nil
+		},
+		DeleteItemFunc: func(_ context.Context, _ string, partitionKey string, _ string) error {
+			deleted = append(deleted, partitionKey)
+			return nil
+		},
+	}
+	storage := NewUserContextStorage(NewConfig(), client, newRegistry())
+
+	err := storage.Flush()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(deleted) != 2 {
+		t.Errorf("Unexpected items are deleted: %#v.", deleted)
+	}
+}
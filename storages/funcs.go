@@ -0,0 +1,89 @@
+// Package storages and its sub packages provide sarah.UserContextStorage and sarah.KVStore implementations that are backed by external storages.
+//
+// Unlike the default in-memory UserContextStorage, an external storage can not hold a plain sarah.ContextualFunc.
+// Instead, a UserContext must be returned with sarah.SerializableArgument,
+// and the function to continue the conversation must be registered to FuncRegistry beforehand with the corresponding identifier.
+//
+//	registry := storages.NewFuncRegistry()
+//	storages.RegisterFunc(registry, "todo.add", func(ctx context.Context, input sarah.Input, arg *TodoArg) (*sarah.CommandResponse, error) {
+//		...
+//	})
+package storages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"sync"
+)
+
+// ErrFuncNotFound is returned when no function is registered with the given identifier.
+var ErrFuncNotFound = errors.New("function is not registered")
+
+// ErrNoSerializable is returned when a given sarah.UserContext does not have sarah.SerializableArgument.
+var ErrNoSerializable = errors.New("UserContext.Serializable must be set to store UserContext in external storage")
+
+// FuncRegistry stashes functions that continue conversations so a stored sarah.SerializableArgument can be converted back to sarah.ContextualFunc.
+// Calls to its methods are thread-safe.
+type FuncRegistry struct {
+	funcs map[string]func([]byte) (sarah.ContextualFunc, error)
+	mutex sync.RWMutex
+}
+
+// NewFuncRegistry creates and returns a new FuncRegistry instance.
+func NewFuncRegistry() *FuncRegistry {
+	return &FuncRegistry{
+		funcs: map[string]func([]byte) (sarah.ContextualFunc, error){},
+	}
+}
+
+// RegisterFunc registers the given function with the given identifier.
+// When a stored UserContext is restored, its serialized argument is decoded into T and is passed to the function.
+// A function registered with the same identifier is replaced.
+func RegisterFunc[T any](registry *FuncRegistry, id string, fnc func(context.Context, sarah.Input, T) (*sarah.CommandResponse, error)) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.funcs[id] = func(serialized []byte) (sarah.ContextualFunc, error) {
+		var arg T
+		err := json.Unmarshal(serialized, &arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize argument for %s: %w", id, err)
+		}
+
+		return func(ctx context.Context, input sarah.Input) (*sarah.CommandResponse, error) {
+			return fnc(ctx, input, arg)
+		}, nil
+	}
+}
+
+// Restore returns a sarah.ContextualFunc that executes the function registered with the given identifier with the deserialized argument.
+// ErrFuncNotFound is returned when no function is registered with the given identifier.
+func (registry *FuncRegistry) Restore(id string, serialized []byte) (sarah.ContextualFunc, error) {
+	registry.mutex.RLock()
+	restore, ok := registry.funcs[id]
+	registry.mutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFuncNotFound, id)
+	}
+
+	return restore(serialized)
+}
+
+// Serialize converts the given sarah.UserContext into a function identifier and a serialized argument.
+// ErrNoSerializable is returned when UserContext.Serializable is not set.
+func Serialize(userContext *sarah.UserContext) (string, []byte, error) {
+	if userContext.Serializable == nil {
+		return "", nil, ErrNoSerializable
+	}
+
+	serialized, err := json.Marshal(userContext.Serializable.Argument)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to serialize argument: %w", err)
+	}
+
+	return userContext.Serializable.FuncIdentifier, serialized, nil
+}
@@ -0,0 +1,75 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+)
+
+type arg struct {
+	Value string `json:"value"`
+}
+
+func TestNewFuncRegistry(t *testing.T) {
+	registry := NewFuncRegistry()
+	if registry == nil {
+		t.Fatal("FuncRegistry is not returned.")
+	}
+
+	if registry.funcs == nil {
+		t.Error("Function map is not initialized.")
+	}
+}
+
+func TestFuncRegistry_Restore(t *testing.T) {
+	registry := NewFuncRegistry()
+	RegisterFunc(registry, "id", func(_ context.Context, _ sarah.Input, a *arg) (*sarah.CommandResponse, error) {
+		return &sarah.CommandResponse{Content: a.Value}, nil
+	})
+
+	fnc, err := registry.Restore("id", []byte(`{"value":"foo"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	res, _ := fnc(context.TODO(), nil)
+	if res.Content != "foo" {
+		t.Errorf("Unexpected argument is given: %#v.", res.Content)
+	}
+
+	_, err = registry.Restore("id", []byte("invalid"))
+	if err == nil {
+		t.Error("Expected error is not returned.")
+	}
+
+	_, err = registry.Restore("unknown", []byte("{}"))
+	if !errors.Is(err, ErrFuncNotFound) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	id, serialized, err := Serialize(&sarah.UserContext{
+		Serializable: &sarah.SerializableArgument{
+			FuncIdentifier: "id",
+			Argument:       &arg{Value: "foo"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if id != "id" {
+		t.Errorf("Unexpected identifier is returned: %s.", id)
+	}
+
+	if string(serialized) != `{"value":"foo"}` {
+		t.Errorf("Unexpected serialized value is returned: %s.", serialized)
+	}
+
+	_, _, err = Serialize(&sarah.UserContext{})
+	if !errors.Is(err, ErrNoSerializable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
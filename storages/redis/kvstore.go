@@ -0,0 +1,124 @@
+// Package redis provides storage implementations that are backed by Redis.
+//
+// To avoid binding this project to a particular Redis client library, this package depends on Client interface.
+// Implement Client with a thin wrapper of the preferred library such as go-redis as below:
+//
+//	type client struct {
+//		rdb *goredis.Client
+//	}
+//
+//	func (c *client) HGet(ctx context.Context, key string, field string) ([]byte, error) {
+//		value, err := c.rdb.HGet(ctx, key, field).Bytes()
+//		if errors.Is(err, goredis.Nil) {
+//			return nil, nil
+//		}
+//		return value, err
+//	}
+//
+//	...
+//
+//	sarah.RegisterKVStore(redis.NewKVStore(redis.NewConfig(), &client{rdb: rdb}))
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-sarah/v4"
+	"sort"
+)
+
+// Client defines an interface of a Redis client that KVStore depends on.
+// Each method corresponds to the Redis command with the same name.
+type Client interface {
+	// HGet returns the value of the field stored in the hash at key.
+	// This must return nil value and nil error when the key or the field does not exist.
+	HGet(ctx context.Context, key string, field string) ([]byte, error)
+
+	// HSet sets the value of the field in the hash at key.
+	HSet(ctx context.Context, key string, field string, value []byte) error
+
+	// HDel removes the field from the hash at key.
+	HDel(ctx context.Context, key string, field string) error
+
+	// HKeys returns all field names in the hash at key.
+	HKeys(ctx context.Context, key string) ([]string, error)
+}
+
+// Config contains some configuration variables for Redis-backed storages.
+type Config struct {
+	// KeyPrefix declares the prefix of Redis keys.
+	// Each namespace of sarah.KVStore is stored as a hash with the key of KeyPrefix + namespace.
+	KeyPrefix string `json:"key_prefix" yaml:"key_prefix"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		KeyPrefix: "sarah:kv:",
+	}
+}
+
+// KVStore is a sarah.KVStore implementation that stores each namespace as a Redis hash.
+type KVStore struct {
+	config *Config
+	client Client
+}
+
+var _ sarah.KVStore = (*KVStore)(nil)
+
+// NewKVStore creates and returns a new KVStore instance.
+func NewKVStore(config *Config, client Client) *KVStore {
+	return &KVStore{
+		config: config,
+		client: client,
+	}
+}
+
+func (s *KVStore) key(namespace string) string {
+	return s.config.KeyPrefix + namespace
+}
+
+// Get returns the value stored with the given namespace and key.
+// sarah.ErrKVNotFound is returned when no corresponding value is stored.
+func (s *KVStore) Get(ctx context.Context, namespace string, key string) ([]byte, error) {
+	value, err := s.client.HGet(ctx, s.key(namespace), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get value from Redis: %w", err)
+	}
+
+	if value == nil {
+		return nil, sarah.ErrKVNotFound
+	}
+
+	return value, nil
+}
+
+// Set stores the given value with the given namespace and key.
+func (s *KVStore) Set(ctx context.Context, namespace string, key string, value []byte) error {
+	err := s.client.HSet(ctx, s.key(namespace), key, value)
+	if err != nil {
+		return fmt.Errorf("failed to set value to Redis: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the value stored with the given namespace and key.
+func (s *KVStore) Delete(ctx context.Context, namespace string, key string) error {
+	err := s.client.HDel(ctx, s.key(namespace), key)
+	if err != nil {
+		return fmt.Errorf("failed to delete value from Redis: %w", err)
+	}
+	return nil
+}
+
+// List returns the keys stored under the given namespace in ascending order.
+func (s *KVStore) List(ctx context.Context, namespace string) ([]string, error) {
+	keys, err := s.client.HKeys(ctx, s.key(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys from Redis: %w", err)
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"testing"
+)
+
+type DummyClient struct {
+	HGetFunc  func(context.Context, string, string) ([]byte, error)
+	HSetFunc  func(context.Context, string, string, []byte) error
+	HDelFunc  func(context.Context, string, string) error
+	HKeysFunc func(context.Context, string) ([]string, error)
+}
+
+func (c *DummyClient) HGet(ctx context.Context, key string, field string) ([]byte, error) {
+	return c.HGetFunc(ctx, key, field)
+}
+
+func (c *DummyClient) HSet(ctx context.Context, key string, field string, value []byte) error {
+	return c.HSetFunc(ctx, key, field, value)
+}
+
+func (c *DummyClient) HDel(ctx context.Context, key string, field string) error {
+	return c.HDelFunc(ctx, key, field)
+}
+
+func (c *DummyClient) HKeys(ctx context.Context, key string) ([]string, error) {
+	return c.HKeysFunc(ctx, key)
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config.KeyPrefix == "" {
+		t.Error("Default key prefix is not set.")
+	}
+}
+
+func TestNewKVStore(t *testing.T) {
+	config := NewConfig()
+	client := &DummyClient{}
+	store := NewKVStore(config, client)
+
+	if store.config != config {
+		t.Error("Given config is not set.")
+	}
+
+	if store.client != client {
+		t.Error("Given client is not set.")
+	}
+}
+
+func TestKVStore_Get(t *testing.T) {
+	testSets := []struct {
+		value []byte
+		err   error
+	}{
+		{
+			value: []byte("value"),
+		},
+		{
+			value: nil,
+		},
+		{
+			err: errors.New("connection error"),
+		},
+	}
+
+	for i, testSet := range testSets {
+		client := &DummyClient{
+			HGetFunc: func(_ context.Context, key string, field string) ([]byte, error) {
+				if key != "sarah:kv:ns" {
+					t.Errorf("Unexpected key is given on test %d: %s.", i, key)
+				}
+				if field != "key" {
+					t.Errorf("Unexpected field is given on test %d: %s.", i, field)
+				}
+				return testSet.value, testSet.err
+			},
+		}
+		store := NewKVStore(NewConfig(), client)
+
+		value, err := store.Get(context.TODO(), "ns", "key")
+		switch {
+		case testSet.err != nil:
+			if !errors.Is(err, testSet.err) {
+				t.Errorf("Expected error is not returned on test %d: %#v.", i, err)
+			}
+
+		case testSet.value == nil:
+			if !errors.Is(err, sarah.ErrKVNotFound) {
+				t.Errorf("Expected error is not returned on test %d: %#v.", i, err)
+			}
+
+		default:
+			if string(value) != string(testSet.value) {
+				t.Errorf("Unexpected value is returned on test %d: %s.", i, value)
+			}
+
+		}
+	}
+}
+
+func TestKVStore_Set(t *testing.T) {
+	called := false
+	client := &DummyClient{
+		HSetFunc: func(_ context.Context, key string, field string, value []byte) error {
+			called = true
+			if key != "sarah:kv:ns" || field != "key" || string(value) != "value" {
+				t.Errorf("Unexpected arguments are given: %s, %s, %s.", key, field, value)
+			}
+			return nil
+		},
+	}
+	store := NewKVStore(NewConfig(), client)
+
+	err := store.Set(context.TODO(), "ns", "key", []byte("value"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if !called {
+		t.Error("Client is not called.")
+	}
+}
+
+func TestKVStore_Delete(t *testing.T) {
+	expected := errors.New("connection error")
+	client := &DummyClient{
+		HDelFunc: func(_ context.Context, _ string, _ string) error {
+			return expected
+		},
+	}
+	store := NewKVStore(NewConfig(), client)
+
+	err := store.Delete(context.TODO(), "ns", "key")
+	if !errors.Is(err, expected) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestKVStore_List(t *testing.T) {
+	client := &DummyClient{
+		HKeysFunc: func(_ context.Context, _ string) ([]string, error) {
+			return []string{"b", "a"}, nil
+		},
+	}
+	store := NewKVStore(NewConfig(), client)
+
+	keys, err := store.List(context.TODO(), "ns")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("Unexpected keys are returned: %#v.", keys)
+	}
+}
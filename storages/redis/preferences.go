@@ -0,0 +1,13 @@
+package redis
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// NewPreferences creates and returns a new sarah.Preferences implementation that stores each user's preference in Redis.
+// The preferences of each BotType are stored as a hash with the key of Config.KeyPrefix + "<BotType>:preferences".
+//
+//	sarah.RegisterPreferences(redis.NewPreferences(redis.NewConfig(), &client{rdb: rdb}))
+func NewPreferences(config *Config, client Client) sarah.Preferences {
+	return sarah.NewKVPreferences(NewKVStore(config, client))
+}
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewPreferences(t *testing.T) {
+	hash := map[string][]byte{}
+	client := &DummyClient{
+		HGetFunc: func(_ context.Context, key string, field string) ([]byte, error) {
+			if key != "sarah:kv:dummy:preferences" {
+				t.Errorf("Unexpected key is given: %s.", key)
+			}
+			return hash[field], nil
+		},
+		HSetFunc: func(_ context.Context, _ string, field string, value []byte) error {
+			hash[field] = value
+			return nil
+		},
+	}
+	preferences := NewPreferences(NewConfig(), client)
+
+	preference, err := preferences.Get(context.TODO(), "dummy", "user")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if preference.Locale != "" {
+		t.Errorf("Zero value should be returned: %#v.", preference)
+	}
+
+	preference.Locale = "ja-JP"
+	err = preferences.Set(context.TODO(), "dummy", "user", preference)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	stored, _ := preferences.Get(context.TODO(), "dummy", "user")
+	if stored.Locale != "ja-JP" {
+		t.Errorf("Stored preference is not returned: %#v.", stored)
+	}
+}
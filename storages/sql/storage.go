@@ -0,0 +1,297 @@
+// Package sql provides a sarah.UserContextStorage implementation that is backed by RDBMS via database/sql.
+//
+// PostgreSQL and MySQL are supported. Since this package does not import any database driver, import the preferred one in the application.
+// Create the table with Migrate or with the statement returned by Schema before storing user contexts.
+//
+//	db, _ := sql.Open("postgres", dsn)
+//	config := sqlstorage.NewConfig()
+//	config.Dialect = sqlstorage.PostgreSQL
+//	_ = sqlstorage.Migrate(ctx, db, config)
+//
+//	registry := storages.NewFuncRegistry()
+//	storages.RegisterFunc(registry, "todo.add", addTodo)
+//	storage := sqlstorage.NewUserContextStorage(config, db, registry)
+//	go storage.RunCleanup(ctx)
+//	bot := sarah.NewBot(adapter, sarah.BotWithStorage(storage))
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/storages"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect represents a kind of RDBMS.
+type Dialect string
+
+const (
+	// PostgreSQL represents PostgreSQL dialect.
+	PostgreSQL Dialect = "postgres"
+
+	// MySQL represents MySQL dialect.
+	MySQL Dialect = "mysql"
+)
+
+// ErrUnsupportedDialect is returned when the given Dialect is not supported.
+var ErrUnsupportedDialect = errors.New("unsupported dialect")
+
+// DB defines an interface of a database handle that UserContextStorage depends on.
+// *sql.DB and *sql.Conn satisfy this interface.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Config contains some configuration variables for UserContextStorage.
+type Config struct {
+	// Dialect declares the kind of RDBMS.
+	Dialect Dialect `json:"dialect" yaml:"dialect"`
+
+	// TableName declares the name of the table to store user contexts.
+	TableName string `json:"table_name" yaml:"table_name"`
+
+	// ExpiresIn declares how long a stored user context lives.
+	ExpiresIn time.Duration `json:"expires_in" yaml:"expires_in"`
+
+	// CleanupInterval declares how often RunCleanup removes expired rows.
+	CleanupInterval time.Duration `json:"cleanup_interval" yaml:"cleanup_interval"`
+
+	// Timeout declares the timeout of each query.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		Dialect:         PostgreSQL,
+		TableName:       "sarah_user_contexts",
+		ExpiresIn:       3 * time.Minute,
+		CleanupInterval: 10 * time.Minute,
+		Timeout:         5 * time.Second,
+	}
+}
+
+// Schema returns the statements to create the table and its index for the given Config.
+// The statements are separated by semicolons so they can be executed at once with a client such as psql.
+func Schema(config *Config) (string, error) {
+	statements, err := schemaStatements(config)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(statements, ";\n"), nil
+}
+
+// schemaStatements returns the statements to create the table and its index one by one
+// because not all drivers can execute multiple statements at once.
+func schemaStatements(config *Config) ([]string, error) {
+	switch config.Dialect {
+	case PostgreSQL:
+		// The index on expires_at keeps the periodical cleanup from scanning the whole table.
+		// Unlike MySQL, PostgreSQL can not declare an index in CREATE TABLE and an index name must be unique in the schema.
+		return []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	user_key VARCHAR(255) NOT NULL PRIMARY KEY,
+	func_identifier VARCHAR(255) NOT NULL,
+	argument BYTEA NOT NULL,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+)`, config.TableName),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_expires_at ON %s (expires_at)", config.TableName, config.TableName),
+		}, nil
+
+	case MySQL:
+		return []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	user_key VARCHAR(255) NOT NULL PRIMARY KEY,
+	func_identifier VARCHAR(255) NOT NULL,
+	argument BLOB NOT NULL,
+	expires_at DATETIME(6) NOT NULL,
+	INDEX idx_expires_at (expires_at)
+)`, config.TableName),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, config.Dialect)
+
+	}
+}
+
+// Migrate creates the table to store user contexts and its index if they do not exist.
+func Migrate(ctx context.Context, db DB, config *Config) error {
+	statements, err := schemaStatements(config)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", config.TableName, err)
+		}
+	}
+
+	return nil
+}
+
+// UserContextStorage is a sarah.UserContextStorage implementation that stores serialized user contexts in RDBMS.
+// A user context is stored with a single upsert statement, so the last Set wins when multiple processes store a user context for the same user at the same time.
+type UserContextStorage struct {
+	config   *Config
+	db       DB
+	registry *storages.FuncRegistry
+}
+
+var _ sarah.UserContextStorage = (*UserContextStorage)(nil)
+
+// NewUserContextStorage creates and returns a new UserContextStorage instance.
+// The given FuncRegistry is used to restore the stored user contexts.
+func NewUserContextStorage(config *Config, db DB, registry *storages.FuncRegistry) *UserContextStorage {
+	return &UserContextStorage{
+		config:   config,
+		db:       db,
+		registry: registry,
+	}
+}
+
+// rebind converts "?" placeholders into the form that the configured Dialect accepts.
+func (s *UserContextStorage) rebind(query string) string {
+	if s.config.Dialect != PostgreSQL {
+		return query
+	}
+
+	var sb strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// Get searches for the user's stored state with the given user key, and return it if one is found.
+func (s *UserContextStorage) Get(key string) (sarah.ContextualFunc, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	query := s.rebind(fmt.Sprintf("SELECT func_identifier, argument FROM %s WHERE user_key = ? AND expires_at > ?", s.config.TableName))
+	var id string
+	var argument []byte
+	err := s.db.QueryRowContext(ctx, query, key, sarah.CurrentClock().Now()).Scan(&id, &argument)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch user context: %w", err)
+	}
+
+	return s.registry.Restore(id, argument)
+}
+
+// Set stores the given UserContext.
+// The stored context is tied to the given key, which represents a particular user.
+// UserContext.Serializable must be set and its FuncIdentifier must be registered to the FuncRegistry.
+func (s *UserContextStorage) Set(key string, userContext *sarah.UserContext) error {
+	id, argument, err := storages.Serialize(userContext)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	ttl := s.config.ExpiresIn
+	if userContext.TTL > 0 {
+		ttl = userContext.TTL
+	}
+	expiresAt := sarah.CurrentClock().Now().Add(ttl)
+
+	query := s.rebind(s.upsertQuery())
+	_, err = s.db.ExecContext(ctx, query, key, id, argument, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to store user context: %w", err)
+	}
+
+	return nil
+}
+
+// upsertQuery returns a statement that inserts a row or replaces the existing row with the same user key.
+func (s *UserContextStorage) upsertQuery() string {
+	query := fmt.Sprintf("INSERT INTO %s (user_key, func_identifier, argument, expires_at) VALUES (?, ?, ?, ?)", s.config.TableName)
+	if s.config.Dialect == MySQL {
+		return query + " ON DUPLICATE KEY UPDATE func_identifier = VALUES(func_identifier), argument = VALUES(argument), expires_at = VALUES(expires_at)"
+	}
+	return query + " ON CONFLICT (user_key) DO UPDATE SET func_identifier = EXCLUDED.func_identifier, argument = EXCLUDED.argument, expires_at = EXCLUDED.expires_at"
+}
+
+// Delete removes a currently stored user's conversational context.
+// This does nothing if a corresponding context is not stored.
+func (s *UserContextStorage) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	query := s.rebind(fmt.Sprintf("DELETE FROM %s WHERE user_key = ?", s.config.TableName))
+	_, err := s.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete user context: %w", err)
+	}
+	return nil
+}
+
+// Flush removes all stored UserContext values.
+func (s *UserContextStorage) Flush() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", s.config.TableName))
+	if err != nil {
+		return fmt.Errorf("failed to flush user contexts: %w", err)
+	}
+	return nil
+}
+
+// Cleanup removes expired user contexts and returns the number of removed rows.
+// Expired rows are never returned by Get, but they remain in the table until this is called.
+func (s *UserContextStorage) Cleanup(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	query := s.rebind(fmt.Sprintf("DELETE FROM %s WHERE expires_at <= ?", s.config.TableName))
+	result, err := s.db.ExecContext(ctx, query, sarah.CurrentClock().Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove expired user contexts: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// RunCleanup calls Cleanup on every Config.CleanupInterval and blocks until the given context is canceled.
+func (s *UserContextStorage) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			removed, err := s.Cleanup(ctx)
+			if err != nil {
+				logger.Errorf("Failed to clean up expired user contexts: %+v", err)
+				continue
+			}
+			logger.Debugf("Removed %d expired user contexts.", removed)
+
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/oklahomer/go-sarah/v4"
+	"github.com/oklahomer/go-sarah/v4/storages"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DummyConnector is a driver.Connector that passes each query to QueryFunc so tests can script the results.
+type DummyConnector struct {
+	QueryFunc func(query string, args []driver.NamedValue) (*DummyResult, error)
+}
+
+type DummyResult struct {
+	Columns  []string
+	Rows     [][]driver.Value
+	Affected int64
+}
+
+func (c *DummyConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return &dummyConn{connector: c}, nil
+}
+
+func (c *DummyConnector) Driver() driver.Driver {
+	return nil
+}
+
+type dummyConn struct {
+	connector *DummyConnector
+}
+
+func (c *dummyConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *dummyConn) Close() error {
+	return nil
+}
+
+func (c *dummyConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *dummyConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res, err := c.connector.QueryFunc(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(res.Affected), nil
+}
+
+func (c *dummyConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res, err := c.connector.QueryFunc(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &dummyRows{result: res}, nil
+}
+
+type dummyRows struct {
+	result *DummyResult
+	pos    int
+}
+
+func (r *dummyRows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *dummyRows) Close() error {
+	return nil
+}
+
+func (r *dummyRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.pos])
+	r.pos++
+	return nil
+}
+
+type arg struct {
+	Value string `json:"value"`
+}
+
+func newStorage(dialect Dialect, fnc func(string, []driver.NamedValue) (*DummyResult, error)) *UserContextStorage {
+	config := NewConfig()
+	config.Dialect = dialect
+	db := sql.OpenDB(&DummyConnector{QueryFunc: fnc})
+
+	registry := storages.NewFuncRegistry()
+	storages.RegisterFunc(registry, "id", func(_ context.Context, _ sarah.Input, a *arg) (*sarah.CommandResponse, error) {
+		return &sarah.CommandResponse{Content: a.Value}, nil
+	})
+
+	return NewUserContextStorage(config, db, registry)
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+	if config.TableName == "" {
+		t.Error("Default table name is not set.")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	for _, dialect := range []Dialect{PostgreSQL, MySQL} {
+		config := NewConfig()
+		config.Dialect = dialect
+		schema, err := Schema(config)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned for %s: %s.", dialect, err.Error())
+		}
+
+		if !strings.Contains(schema, config.TableName) {
+			t.Errorf("Table name is not included for %s: %s.", dialect, schema)
+		}
+
+		if !strings.Contains(schema, "(expires_at)") {
+			t.Errorf("Index on expires_at is not included for %s: %s.", dialect, schema)
+		}
+	}
+
+	config := NewConfig()
+	config.Dialect = "oracle"
+	_, err := Schema(config)
+	if !errors.Is(err, ErrUnsupportedDialect) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	testSets := []struct {
+		dialect  Dialect
+		prefixes []string
+	}{
+		{
+			dialect: PostgreSQL,
+			prefixes: []string{
+				"CREATE TABLE IF NOT EXISTS",
+				"CREATE INDEX IF NOT EXISTS idx_sarah_user_contexts_expires_at ON sarah_user_contexts (expires_at)",
+			},
+		},
+		{
+			dialect: MySQL,
+			prefixes: []string{
+				"CREATE TABLE IF NOT EXISTS",
+			},
+		},
+	}
+
+	for _, tt := range testSets {
+		var queries []string
+		db := sql.OpenDB(&DummyConnector{
+			QueryFunc: func(query string, _ []driver.NamedValue) (*DummyResult, error) {
+				queries = append(queries, query)
+				return &DummyResult{}, nil
+			},
+		})
+
+		config := NewConfig()
+		config.Dialect = tt.dialect
+		err := Migrate(context.TODO(), db, config)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned for %s: %s.", tt.dialect, err.Error())
+		}
+
+		if len(queries) != len(tt.prefixes) {
+			t.Fatalf("Unexpected number of statements are executed for %s: %#v.", tt.dialect, queries)
+		}
+		for i, prefix := range tt.prefixes {
+			if !strings.HasPrefix(queries[i], prefix) {
+				t.Errorf("Unexpected query is given for %s: %s.", tt.dialect, queries[i])
+			}
+		}
+	}
+}
+
+func TestUserContextStorage_rebind(t *testing.T) {
+	query := "SELECT * FROM t WHERE a = ? AND b = ?"
+
+	s := &UserContextStorage{config: &Config{Dialect: PostgreSQL}}
+	if rebound := s.rebind(query); rebound != "SELECT * FROM t WHERE a = $1 AND b = $2" {
+		t.Errorf("Unexpected query is returned: %s.", rebound)
+	}
+
+	s = &UserContextStorage{config: &Config{Dialect: MySQL}}
+	if rebound := s.rebind(query); rebound != query {
+		t.Errorf("Unexpected query is returned: %s.", rebound)
+	}
+}
+
+func TestUserContextStorage_Get(t *testing.T) {
+	testSets := []struct {
+		rows  [][]driver.Value
+		err   error
+		found bool
+	}{
+		{
+			rows:  [][]driver.Value{{"id", []byte(`{"value":"foo"}`)}},
+			found: true,
+		},
+		{
+			rows: [][]driver.Value{},
+		},
+		{
+			err: errors.New("connection error"),
+		},
+	}
+
+	for i, testSet := range testSets {
+		storage := newStorage(MySQL, func(query string, args []driver.NamedValue) (*DummyResult, error) {
+			if !strings.HasPrefix(query, "SELECT func_identifier, argument") {
+				t.Errorf("Unexpected query is given on test %d: %s.", i, query)
+			}
+			if args[0].Value != "key" {
+				t.Errorf("Unexpected key is given on test %d: %#v.", i, args[0].Value)
+			}
+			if testSet.err != nil {
+				return nil, testSet.err
+			}
+			return &DummyResult{Columns: []string{"func_identifier", "argument"}, Rows: testSet.rows}, nil
+		})
+
+		fnc, err := storage.Get("key")
+		if testSet.err != nil {
+			if err == nil {
+				t.Errorf("Expected error is not returned on test %d.", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+			continue
+		}
+
+		if !testSet.found {
+			if fnc != nil {
+				t.Errorf("Nil should be returned on test %d.", i)
+			}
+			continue
+		}
+
+		res, _ := fnc(context.TODO(), nil)
+		if res.Content != "foo" {
+			t.Errorf("Unexpected function is restored on test %d: %#v.", i, res.Content)
+		}
+	}
+}
+
+func TestUserContextStorage_Set(t *testing.T) {
+	userContext := &sarah.UserContext{
+		Serializable: &sarah.SerializableArgument{
+			FuncIdentifier: "id",
+			Argument:       &arg{Value: "foo"},
+		},
+	}
+
+	testSets := []struct {
+		dialect Dialect
+		prefix  string
+		suffix  string
+		err     error
+	}{
+		{
+			dialect: PostgreSQL,
+			prefix:  "INSERT INTO sarah_user_contexts (user_key, func_identifier, argument, expires_at) VALUES ($1, $2, $3, $4)",
+			suffix:  "ON CONFLICT (user_key) DO UPDATE SET func_identifier = EXCLUDED.func_identifier, argument = EXCLUDED.argument, expires_at = EXCLUDED.expires_at",
+		},
+		{
+			dialect: MySQL,
+			prefix:  "INSERT INTO sarah_user_contexts (user_key, func_identifier, argument, expires_at) VALUES (?, ?, ?, ?)",
+			suffix:  "ON DUPLICATE KEY UPDATE func_identifier = VALUES(func_identifier), argument = VALUES(argument), expires_at = VALUES(expires_at)",
+		},
+		{
+			dialect: PostgreSQL,
+			err:     errors.New("connection error"),
+		},
+	}
+
+	for i, testSet := range testSets {
+		executed := 0
+		storage := newStorage(testSet.dialect, func(query string, args []driver.NamedValue) (*DummyResult, error) {
+			executed++
+			if testSet.err != nil {
+				return nil, testSet.err
+			}
+
+			if !strings.HasPrefix(query, testSet.prefix) || !strings.HasSuffix(query, testSet.suffix) {
+				t.Errorf("Unexpected query is given on test %d: %s.", i, query)
+			}
+			if len(args) != 4 || args[0].Value != "key" || args[1].Value != "id" {
+				t.Errorf("Unexpected arguments are given on test %d: %#v.", i, args)
+			}
+			return &DummyResult{Affected: 1}, nil
+		})
+
+		err := storage.Set("key", userContext)
+		if testSet.err == nil && err != nil {
+			t.Errorf("Unexpected error is returned on test %d: %s.", i, err.Error())
+		} else if testSet.err != nil && (err == nil || !strings.Contains(err.Error(), testSet.err.Error())) {
+			t.Errorf("Expected error is not returned on test %d: %#v.", i, err)
+		}
+
+		if executed != 1 {
+			t.Errorf("User context should be stored with a single statement on test %d: %d.", i, executed)
+		}
+	}
+}
+
+func TestUserContextStorage_Set_WithoutSerializable(t *testing.T) {
+	storage := newStorage(PostgreSQL, func(query string, _ []driver.NamedValue) (*DummyResult, error) {
+		t.Errorf("Query should not be executed: %s.", query)
+		return &DummyResult{}, nil
+	})
+
+	err := storage.Set("key", sarah.NewUserContext(nil))
+	if !errors.Is(err, storages.ErrNoSerializable) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
+func TestUserContextStorage_Delete(t *testing.T) {
+	storage := newStorage(PostgreSQL, func(query string, args []driver.NamedValue) (*DummyResult, error) {
+		if query != "DELETE FROM sarah_user_contexts WHERE user_key = $1" {
+			t.Errorf("Unexpected query is given: %s.", query)
+		}
+		if args[0].Value != "key" {
+			t.Errorf("Unexpected key is given: %#v.", args[0].Value)
+		}
+		return &DummyResult{}, nil
+	})
+
+	err := storage.Delete("key")
+	if err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+}
+
+func TestUserContextStorage_Flush(t *testing.T) {
+	storage := newStorage(PostgreSQL, func(query string, _ []driver.NamedValue) (*DummyResult, error) {
+		if query != "DELETE FROM sarah_user_contexts" {
+			t.Errorf("Unexpected query is given: %s.", query)
+		}
+		return &DummyResult{}, nil
+	})
+
+	err := storage.Flush()
+	if err != nil {
+		t.Errorf("Unexpected error is returned: %s.", err.Error())
+	}
+}
+
+func TestUserContextStorage_Cleanup(t *testing.T) {
+	storage := newStorage(PostgreSQL, func(query string, _ []driver.NamedValue) (*DummyResult, error) {
+		if !strings.HasPrefix(query, "DELETE FROM sarah_user_contexts WHERE expires_at") {
+			t.Errorf("Unexpected query is given: %s.", query)
+		}
+		return &DummyResult{Affected: 3}, nil
+	})
+
+	removed, err := storage.Cleanup(context.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if removed != 3 {
+		t.Errorf("Unexpected number of removed rows is returned: %d.", removed)
+	}
+}
+
+func TestUserContextStorage_RunCleanup(t *testing.T) {
+	called := make(chan struct{}, 1)
+	storage := newStorage(PostgreSQL, func(_ string, _ []driver.NamedValue) (*DummyResult, error) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+		return &DummyResult{}, nil
+	})
+	storage.config.CleanupInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go storage.RunCleanup(ctx)
+
+	select {
+	case <-called:
+		// O.K.
+
+	case <-time.NewTimer(1 * time.Second).C:
+		t.Error("Cleanup is not executed.")
+
+	}
+}
@@ -0,0 +1,145 @@
+package sarah
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EscalateAfter returns a function that returns the given directive only when the function is called n times within the given window.
+// Otherwise, the returned function returns nil so the escalated error is ignored.
+// The count is reset when the directive is returned.
+// The returned function is safe for concurrent use.
+//
+// This is handy to declare a supervising function such as "restart the bot after three reconnection failures in ten minutes."
+//
+//	restart := sarah.EscalateAfter(3, 10*time.Minute, &sarah.SupervisionDirective{
+//		RestartBot:      true,
+//		RestartCooldown: 30 * time.Second,
+//	})
+//	sarah.RegisterBotErrorSupervisor(func(_ sarah.BotType, err error) *sarah.SupervisionDirective {
+//		if errors.Is(err, ErrReconnection) {
+//			return restart()
+//		}
+//		return nil
+//	})
+func EscalateAfter(n int, window time.Duration, directive *SupervisionDirective) func() *SupervisionDirective {
+	counter := &occurrenceCounter{
+		window: window,
+	}
+
+	return func() *SupervisionDirective {
+		if counter.count(currentClock().Now()) < n {
+			return nil
+		}
+
+		counter.reset()
+		return directive
+	}
+}
+
+// occurrenceCounter counts the occurrences within a sliding window.
+type occurrenceCounter struct {
+	window      time.Duration
+	occurrences []time.Time
+	mutex       sync.Mutex
+}
+
+// count records an occurrence at the given time and returns the number of occurrences within the window.
+func (c *occurrenceCounter) count(now time.Time) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	threshold := now.Add(-c.window)
+	valid := c.occurrences[:0]
+	for _, o := range c.occurrences {
+		if o.After(threshold) {
+			valid = append(valid, o)
+		}
+	}
+	c.occurrences = append(valid, now)
+
+	return len(c.occurrences)
+}
+
+func (c *occurrenceCounter) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.occurrences = nil
+}
+
+// NewThresholdSupervisor creates and returns a supervising function that can be registered via RegisterBotErrorSupervisor.
+// The returned function counts the escalated errors per BotType and per error type with a sliding window,
+// and returns the given directive when the count reaches maxCount within the window.
+// Otherwise, nil is returned and the escalated error is ignored.
+//
+// The error type is determined by the concrete type of the escalated error.
+// Errors that are only wrapped with fmt.Errorf are unwrapped so the underlying error type is counted.
+//
+//	sarah.RegisterBotErrorSupervisor(sarah.NewThresholdSupervisor(10*time.Minute, 3, &sarah.SupervisionDirective{
+//		RestartBot:      true,
+//		RestartCooldown: 30 * time.Second,
+//	}))
+func NewThresholdSupervisor(window time.Duration, maxCount int, directive *SupervisionDirective) func(BotType, error) *SupervisionDirective {
+	s := &thresholdSupervisor{
+		window:    window,
+		maxCount:  maxCount,
+		directive: directive,
+		counters:  map[thresholdKey]*occurrenceCounter{},
+	}
+	return s.supervise
+}
+
+type thresholdKey struct {
+	botType BotType
+	errType string
+}
+
+type thresholdSupervisor struct {
+	window    time.Duration
+	maxCount  int
+	directive *SupervisionDirective
+	counters  map[thresholdKey]*occurrenceCounter
+	mutex     sync.Mutex
+}
+
+func (s *thresholdSupervisor) supervise(botType BotType, err error) *SupervisionDirective {
+	key := thresholdKey{
+		botType: botType,
+		errType: errorType(err),
+	}
+
+	s.mutex.Lock()
+	counter, ok := s.counters[key]
+	if !ok {
+		counter = &occurrenceCounter{window: s.window}
+		s.counters[key] = counter
+	}
+	s.mutex.Unlock()
+
+	if counter.count(currentClock().Now()) < s.maxCount {
+		return nil
+	}
+
+	counter.reset()
+	return s.directive
+}
+
+// errorType returns the name of the given error's concrete type.
+// An error that is wrapped with fmt.Errorf is unwrapped so the name of the underlying error's type is returned.
+func errorType(err error) string {
+	for {
+		name := fmt.Sprintf("%T", err)
+		if name != "*fmt.wrapError" {
+			return name
+		}
+
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return name
+		}
+		err = unwrapped
+	}
+}
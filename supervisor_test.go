@@ -0,0 +1,113 @@
+package sarah
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEscalateAfter(t *testing.T) {
+	directive := &SupervisionDirective{RestartBot: true}
+	escalate := EscalateAfter(3, time.Minute, directive)
+
+	for i := 0; i < 2; i++ {
+		if d := escalate(); d != nil {
+			t.Fatalf("Directive should not be returned on call %d.", i+1)
+		}
+	}
+
+	if d := escalate(); d != directive {
+		t.Fatalf("Expected directive is not returned: %#v.", d)
+	}
+
+	// The count is reset after the escalation.
+	if d := escalate(); d != nil {
+		t.Error("Directive should not be returned right after the escalation.")
+	}
+}
+
+func TestEscalateAfter_Concurrency(t *testing.T) {
+	directive := &SupervisionDirective{StopBot: true}
+	escalate := EscalateAfter(10, time.Minute, directive)
+
+	escalated := make(chan *SupervisionDirective, 100)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if d := escalate(); d != nil {
+				escalated <- d
+			}
+		}()
+	}
+	wg.Wait()
+	close(escalated)
+
+	if len(escalated) != 10 {
+		t.Errorf("Unexpected number of escalations: %d.", len(escalated))
+	}
+}
+
+func Test_occurrenceCounter_count(t *testing.T) {
+	counter := &occurrenceCounter{window: time.Minute}
+	now := time.Now()
+
+	counter.count(now.Add(-2 * time.Minute))
+	counter.count(now.Add(-30 * time.Second))
+	if c := counter.count(now); c != 2 {
+		t.Errorf("Occurrences out of the window should be excluded: %d.", c)
+	}
+
+	counter.reset()
+	if c := counter.count(now); c != 1 {
+		t.Errorf("Occurrences are not reset: %d.", c)
+	}
+}
+
+func TestNewThresholdSupervisor(t *testing.T) {
+	directive := &SupervisionDirective{StopBot: true}
+	supervise := NewThresholdSupervisor(time.Minute, 2, directive)
+
+	if d := supervise("foo", NewTransientError(errors.New("first"))); d != nil {
+		t.Fatal("Directive should not be returned on the first occurrence.")
+	}
+
+	// Different BotType and different error type are counted separately.
+	if d := supervise("bar", NewTransientError(errors.New("other bot"))); d != nil {
+		t.Error("Errors of a different BotType should be counted separately.")
+	}
+	if d := supervise("foo", NewAuthError(errors.New("other type"))); d != nil {
+		t.Error("Errors of a different type should be counted separately.")
+	}
+
+	// Wrapped error is counted as its underlying type.
+	if d := supervise("foo", fmt.Errorf("wrapped: %w", NewTransientError(errors.New("second")))); d != directive {
+		t.Errorf("Expected directive is not returned: %#v.", d)
+	}
+
+	if d := supervise("foo", NewTransientError(errors.New("third"))); d != nil {
+		t.Error("Count should be reset after the escalation.")
+	}
+}
+
+func Test_errorType(t *testing.T) {
+	testSets := []struct {
+		err      error
+		expected string
+	}{
+		{err: errors.New("plain"), expected: "*errors.errorString"},
+		{err: NewAuthError(errors.New("auth")), expected: "*sarah.AuthError"},
+		{err: fmt.Errorf("wrapped: %w", NewAuthError(errors.New("auth"))), expected: "*sarah.AuthError"},
+		{err: fmt.Errorf("not wrapped: %s", "foo"), expected: "*errors.errorString"},
+	}
+
+	for i, tt := range testSets {
+		name := errorType(tt.err)
+		if name != tt.expected {
+			t.Errorf("Unexpected type name is returned on test %d: %s.", i, name)
+		}
+	}
+}
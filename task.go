@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 )
 
 var (
@@ -14,6 +15,9 @@ var (
 
 	// ErrTaskScheduleNotGiven is returned when a schedule is provided by neither ScheduledTaskPropsBuilder's parameter nor config.
 	ErrTaskScheduleNotGiven = errors.New("task schedule is not set or given from config struct")
+
+	// ErrTaskDependencyCycle is returned when a chained task ends up depending on itself.
+	ErrTaskDependencyCycle = errors.New("task dependency cycle is detected")
 )
 
 // ScheduledTaskResult is a struct that ScheduledTask returns on its execution.
@@ -32,6 +36,56 @@ type ScheduledTaskResult struct {
 	Destination OutputDestination
 }
 
+// TaskBatch declares how to send the results of a ScheduledTask that may return a large number of ScheduledTaskResult at once.
+// e.g. A feed reader task may return thousands of new entries, which would cause a burst of Bot.SendMessage calls without batching.
+type TaskBatch struct {
+	// MaxMessages declares the maximum number of messages to be sent per task execution.
+	// The results beyond this limit are truncated. Zero value means no limit.
+	MaxMessages int
+
+	// Interval declares the delay between each message.
+	Interval time.Duration
+
+	// TruncationNotice builds a content to notify the number of truncated results.
+	// When this is set, the returned content is sent to each destination whose results are truncated.
+	// When this is nil, the results are silently truncated.
+	TruncationNotice func(truncated int) interface{}
+}
+
+// BatchedScheduledTask defines an interface that a ScheduledTask with a TaskBatch setting satisfies.
+// When a ScheduledTask satisfies this and returns non-nil TaskBatch, its results are sent in accordance with the setting.
+type BatchedScheduledTask interface {
+	ScheduledTask
+
+	// Batch returns the TaskBatch setting of this task.
+	Batch() *TaskBatch
+}
+
+// TaskResultFormatter converts the content of a ScheduledTaskResult into a form that the Bot's Adapter can send.
+// The destination of the given result is already resolved with the task's default destination.
+// The BotType is given so a single formatter can be shared among tasks for different Bots and render the Adapter-specific content for each.
+type TaskResultFormatter func(ctx context.Context, botType BotType, result *ScheduledTaskResult) (interface{}, error)
+
+// FormattedScheduledTask defines an interface that a ScheduledTask with a TaskResultFormatter satisfies.
+// When a ScheduledTask satisfies this and returns a non-nil TaskResultFormatter, each result's content is formatted before being sent.
+type FormattedScheduledTask interface {
+	ScheduledTask
+
+	// Formatter returns the TaskResultFormatter of this task.
+	Formatter() TaskResultFormatter
+}
+
+// ChainedScheduledTask defines an interface that a ScheduledTask depending on another task satisfies.
+// When a ScheduledTask satisfies this and After returns a non-empty identifier, the task is not scheduled by itself.
+// Instead, the task runs right after the upstream task with the identifier successfully completes in the same scheduling cycle.
+// When the upstream task fails, this task and its downstream tasks are skipped.
+type ChainedScheduledTask interface {
+	ScheduledTask
+
+	// After returns the identifier of the upstream task that belongs to the same Bot.
+	After() string
+}
+
 // taskFunc is a function type that represents a scheduled task.
 type taskFunc func(context.Context, ...TaskConfig) ([]*ScheduledTaskResult, error)
 
@@ -88,8 +142,15 @@ type scheduledTask struct {
 	schedule           string
 	defaultDestination OutputDestination
 	configWrapper      *taskConfigWrapper
+	batch              *TaskBatch
+	formatter          TaskResultFormatter
+	after              string
 }
 
+var _ BatchedScheduledTask = (*scheduledTask)(nil)
+var _ FormattedScheduledTask = (*scheduledTask)(nil)
+var _ ChainedScheduledTask = (*scheduledTask)(nil)
+
 // Identifier returns unique id of this task.
 func (task *scheduledTask) Identifier() string {
 	return task.identifier
@@ -119,10 +180,25 @@ func (task *scheduledTask) DefaultDestination() OutputDestination {
 	return task.defaultDestination
 }
 
+// Batch returns the TaskBatch setting of this task.
+func (task *scheduledTask) Batch() *TaskBatch {
+	return task.batch
+}
+
+// Formatter returns the TaskResultFormatter of this task.
+func (task *scheduledTask) Formatter() TaskResultFormatter {
+	return task.formatter
+}
+
+// After returns the identifier of the upstream task.
+func (task *scheduledTask) After() string {
+	return task.after
+}
+
 func buildScheduledTask(ctx context.Context, props *ScheduledTaskProps, watcher ConfigWatcher) (ScheduledTask, error) {
 	if props.config == nil {
-		// If a config struct is not set, props MUST provide a default schedule to execute the task.
-		if props.schedule == "" {
+		// If a config struct is not set, props MUST provide a default schedule to execute the task unless the task is chained.
+		if props.schedule == "" && props.after == "" {
 			return nil, ErrTaskScheduleNotGiven
 		}
 
@@ -133,6 +209,9 @@ func buildScheduledTask(ctx context.Context, props *ScheduledTaskProps, watcher
 			schedule:           props.schedule,
 			defaultDestination: dest,
 			configWrapper:      nil,
+			batch:              props.batch,
+			formatter:          props.formatter,
+			after:              props.after,
 		}, nil
 	}
 
@@ -178,7 +257,7 @@ func buildScheduledTask(ctx context.Context, props *ScheduledTaskProps, watcher
 			schedule = s
 		}
 	}
-	if schedule == "" {
+	if schedule == "" && props.after == "" {
 		return nil, ErrTaskScheduleNotGiven
 	}
 
@@ -200,6 +279,9 @@ func buildScheduledTask(ctx context.Context, props *ScheduledTaskProps, watcher
 			value: cfg,
 			mutex: locker,
 		},
+		batch:     props.batch,
+		formatter: props.formatter,
+		after:     props.after,
 	}, nil
 }
 
@@ -212,6 +294,9 @@ type ScheduledTaskProps struct {
 	schedule           string
 	defaultDestination OutputDestination
 	config             TaskConfig
+	batch              *TaskBatch
+	formatter          TaskResultFormatter
+	after              string
 }
 
 // ScheduledTaskPropsBuilder helps to construct a ScheduledTaskProps.
@@ -266,6 +351,28 @@ func (builder *ScheduledTaskPropsBuilder) DefaultDestination(dest OutputDestinat
 	return builder
 }
 
+// Batch sets a TaskBatch setting to limit the number of messages per execution and to delay each message.
+// Use this when the task may return a large number of results at once.
+func (builder *ScheduledTaskPropsBuilder) Batch(batch *TaskBatch) *ScheduledTaskPropsBuilder {
+	builder.props.batch = batch
+	return builder
+}
+
+// Formatter sets a TaskResultFormatter to convert each result's content before sending.
+// This lets the task function focus on data collection while the presentation is defined separately and reused among tasks.
+func (builder *ScheduledTaskPropsBuilder) Formatter(formatter TaskResultFormatter) *ScheduledTaskPropsBuilder {
+	builder.props.formatter = formatter
+	return builder
+}
+
+// After declares that the task runs right after the task with the given identifier successfully completes.
+// This is useful to build a pipeline such as fetching data in one task and reporting it in another.
+// The upstream task must belong to the same Bot. When this is set, the execution schedule is not required and is ignored if given.
+func (builder *ScheduledTaskPropsBuilder) After(id string) *ScheduledTaskPropsBuilder {
+	builder.props.after = id
+	return builder
+}
+
 // ConfigurableFunc sets a function for the ScheduledTask with a configuration value.
 // The given configuration value -- config -- is passed to the function as a third argument.
 //
@@ -288,6 +395,15 @@ func (builder *ScheduledTaskPropsBuilder) Build() (*ScheduledTaskProps, error) {
 		return nil, ErrTaskInsufficientArgument
 	}
 
+	if builder.props.after != "" {
+		if builder.props.after == builder.props.identifier {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrTaskDependencyCycle, builder.props.identifier, builder.props.after)
+		}
+
+		// The task runs after the upstream task, so the schedule is not required.
+		return builder.props, nil
+	}
+
 	taskConfig := builder.props.config
 	if taskConfig == nil && builder.props.schedule == "" {
 		// Task Schedule can never be specified.
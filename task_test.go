@@ -110,6 +110,36 @@ func TestScheduledTaskPropsBuilder_DefaultDestination(t *testing.T) {
 	}
 }
 
+func TestScheduledTaskPropsBuilder_Batch(t *testing.T) {
+	batch := &TaskBatch{MaxMessages: 10}
+	builder := &ScheduledTaskPropsBuilder{props: &ScheduledTaskProps{}}
+	builder.Batch(batch)
+
+	if builder.props.batch != batch {
+		t.Fatal("Supplied batch setting is not set.")
+	}
+}
+
+func TestScheduledTaskPropsBuilder_Formatter(t *testing.T) {
+	builder := &ScheduledTaskPropsBuilder{props: &ScheduledTaskProps{}}
+	builder.Formatter(func(_ context.Context, _ BotType, _ *ScheduledTaskResult) (interface{}, error) {
+		return "formatted", nil
+	})
+
+	if builder.props.formatter == nil {
+		t.Fatal("Supplied formatter is not set.")
+	}
+}
+
+func TestScheduledTaskPropsBuilder_After(t *testing.T) {
+	builder := &ScheduledTaskPropsBuilder{props: &ScheduledTaskProps{}}
+	builder.After("upstream")
+
+	if builder.props.after != "upstream" {
+		t.Fatal("Supplied upstream task is not set.")
+	}
+}
+
 func TestScheduledTaskPropsBuilder_ConfigurableFunc(t *testing.T) {
 	config := &DummyScheduledTaskConfig{}
 	taskFunc := func(_ context.Context, c TaskConfig) ([]*ScheduledTaskResult, error) {
@@ -173,6 +203,31 @@ func TestScheduledTaskPropsBuilder_Build(t *testing.T) {
 	}
 }
 
+func TestScheduledTaskPropsBuilder_Build_WithAfter(t *testing.T) {
+	builder := &ScheduledTaskPropsBuilder{props: &ScheduledTaskProps{}}
+	builder.BotType("dummyBot").
+		Identifier("report").
+		Func(func(_ context.Context) ([]*ScheduledTaskResult, error) {
+			return nil, nil
+		}).
+		After("fetch")
+
+	props, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if props.after != "fetch" {
+		t.Errorf("Supplied upstream task is not set: %s.", props.after)
+	}
+
+	builder.After("report")
+	_, err = builder.Build()
+	if !errors.Is(err, ErrTaskDependencyCycle) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+}
+
 func TestScheduledTaskPropsBuilder_Build_WithUnconfigurableSchedule(t *testing.T) {
 	nonScheduledConfig := &struct {
 		Token string `yaml:"token"`
@@ -275,6 +330,57 @@ func TestScheduledTask_Schedule(t *testing.T) {
 	}
 }
 
+func Test_scheduledTask_Batch(t *testing.T) {
+	batch := &TaskBatch{MaxMessages: 10}
+	task := &scheduledTask{batch: batch}
+
+	if task.Batch() != batch {
+		t.Errorf("Unexpected batch setting is returned: %#v.", task.Batch())
+	}
+}
+
+func Test_scheduledTask_Formatter(t *testing.T) {
+	task := &scheduledTask{
+		formatter: func(_ context.Context, _ BotType, _ *ScheduledTaskResult) (interface{}, error) {
+			return "formatted", nil
+		},
+	}
+
+	formatted, _ := task.Formatter()(context.TODO(), "dummy", &ScheduledTaskResult{})
+	if formatted != "formatted" {
+		t.Errorf("Unexpected formatter is returned: %#v.", formatted)
+	}
+}
+
+func Test_scheduledTask_After(t *testing.T) {
+	task := &scheduledTask{after: "upstream"}
+
+	if task.After() != "upstream" {
+		t.Errorf("Unexpected upstream task is returned: %s.", task.After())
+	}
+}
+
+func Test_buildScheduledTask_WithAfter(t *testing.T) {
+	props := &ScheduledTaskProps{
+		botType:    "dummyBot",
+		identifier: "report",
+		taskFunc: func(_ context.Context, _ ...TaskConfig) ([]*ScheduledTaskResult, error) {
+			return nil, nil
+		},
+		after: "fetch",
+	}
+
+	task, err := buildScheduledTask(context.TODO(), props, &DummyConfigWatcher{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	chained, ok := task.(ChainedScheduledTask)
+	if !ok || chained.After() != "fetch" {
+		t.Errorf("Upstream task is not set: %#v.", task)
+	}
+}
+
 func Test_buildScheduledTask(t *testing.T) {
 	tests := []struct {
 		props          *ScheduledTaskProps
@@ -0,0 +1,93 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TaskTarget is an entry of FanOutConfig that represents one destination and its own parameters.
+// A struct that implements this interface typically holds the per-destination parameters such as a city name for a weather forecast.
+type TaskTarget interface {
+	// Destination returns the destination to send the result for this entry to.
+	Destination() OutputDestination
+}
+
+// FanOutConfig defines an interface that a configuration with multiple destinations MUST satisfy to be used with ScheduledTaskPropsBuilder.FanOutFunc.
+//
+//	type CityTarget struct {
+//		City    string `yaml:"city"`
+//		Channel string `yaml:"channel"`
+//	}
+//
+//	func (t *CityTarget) Destination() sarah.OutputDestination {
+//		return t.Channel
+//	}
+//
+//	type WeatherConfig struct {
+//		Cron   string        `yaml:"schedule"`
+//		Cities []*CityTarget `yaml:"cities"`
+//	}
+//
+//	func (c *WeatherConfig) Targets() []sarah.TaskTarget {
+//		targets := make([]sarah.TaskTarget, len(c.Cities))
+//		for i, city := range c.Cities {
+//			targets[i] = city
+//		}
+//		return targets
+//	}
+type FanOutConfig interface {
+	TaskConfig
+	Targets() []TaskTarget
+}
+
+// FanOutFunc sets a function that is called for each entry of the given configuration's FanOutConfig.Targets on task execution.
+// This removes the boilerplate loop from a task that sends a similar content to multiple destinations with different parameters.
+//
+// When a returning ScheduledTaskResult does not specify its destination, TaskTarget.Destination of the corresponding entry is used.
+// When the function fails for one entry, the error is logged and the remaining entries are still executed;
+// the task execution is considered a failure only when the function fails for every entry.
+//
+// Like ConfigurableFunc, the configuration value is updated automatically when the corresponding setting is updated,
+// so the entries can be added or removed without a code change.
+func (builder *ScheduledTaskPropsBuilder) FanOutFunc(config FanOutConfig, fn func(context.Context, TaskConfig, TaskTarget) ([]*ScheduledTaskResult, error)) *ScheduledTaskPropsBuilder {
+	builder.props.config = config
+	builder.props.taskFunc = func(ctx context.Context, cfg ...TaskConfig) ([]*ScheduledTaskResult, error) {
+		fanOutConfig, ok := cfg[0].(FanOutConfig)
+		if !ok {
+			return nil, fmt.Errorf("%T does not implement FanOutConfig", cfg[0])
+		}
+		return fanOut(ctx, fanOutConfig, fn)
+	}
+	return builder
+}
+
+func fanOut(ctx context.Context, config FanOutConfig, fn func(context.Context, TaskConfig, TaskTarget) ([]*ScheduledTaskResult, error)) ([]*ScheduledTaskResult, error) {
+	targets := config.Targets()
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	var results []*ScheduledTaskResult
+	var errs []error
+	for _, target := range targets {
+		res, err := fn(ctx, config, target)
+		if err != nil {
+			LoggerFromContext(ctx).Errorf("Failed to execute the task for %s: %+v", destinationKey(target.Destination()), err)
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, r := range res {
+			if r != nil && r.Destination == nil {
+				r.Destination = target.Destination()
+			}
+		}
+		results = append(results, res...)
+	}
+
+	if len(errs) == len(targets) {
+		return nil, errors.Join(errs...)
+	}
+	return results, nil
+}
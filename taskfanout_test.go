@@ -0,0 +1,126 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type DummyTaskTarget struct {
+	City    string
+	Channel string
+}
+
+func (target *DummyTaskTarget) Destination() OutputDestination {
+	return target.Channel
+}
+
+type DummyFanOutConfig struct {
+	ScheduleValue string
+	Cities        []*DummyTaskTarget
+}
+
+func (config *DummyFanOutConfig) Schedule() string {
+	return config.ScheduleValue
+}
+
+func (config *DummyFanOutConfig) Targets() []TaskTarget {
+	targets := make([]TaskTarget, len(config.Cities))
+	for i, city := range config.Cities {
+		targets[i] = city
+	}
+	return targets
+}
+
+func TestScheduledTaskPropsBuilder_FanOutFunc(t *testing.T) {
+	config := &DummyFanOutConfig{
+		ScheduleValue: "@hourly",
+		Cities: []*DummyTaskTarget{
+			{City: "Tokyo", Channel: "#tokyo"},
+			{City: "Osaka", Channel: "#osaka"},
+		},
+	}
+	fn := func(_ context.Context, c TaskConfig, target TaskTarget) ([]*ScheduledTaskResult, error) {
+		if c != config {
+			t.Errorf("Unexpected config is given: %#v.", c)
+		}
+		city := target.(*DummyTaskTarget).City
+		if city == "Osaka" {
+			return []*ScheduledTaskResult{{Content: city, Destination: "#override"}}, nil
+		}
+		return []*ScheduledTaskResult{{Content: city}}, nil
+	}
+
+	props, err := NewScheduledTaskPropsBuilder().
+		BotType("dummy").
+		Identifier("weather").
+		FanOutFunc(config, fn).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if props.config != config {
+		t.Fatal("Supplied config is not set.")
+	}
+
+	results, err := props.taskFunc(context.TODO(), config)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(results) != 2 {
+		t.Fatalf("Unexpected number of results are returned: %d.", len(results))
+	}
+	if results[0].Content != "Tokyo" || results[0].Destination != "#tokyo" {
+		t.Errorf("Destination of the entry is not used: %#v.", results[0])
+	}
+	if results[1].Content != "Osaka" || results[1].Destination != "#override" {
+		t.Errorf("Destination of the result is not preferred: %#v.", results[1])
+	}
+
+	_, err = props.taskFunc(context.TODO(), &DummyScheduledTaskConfig{})
+	if err == nil {
+		t.Error("Expected error is not returned for a config without targets.")
+	}
+}
+
+func Test_fanOut_Error(t *testing.T) {
+	config := &DummyFanOutConfig{
+		Cities: []*DummyTaskTarget{
+			{City: "Tokyo", Channel: "#tokyo"},
+			{City: "Osaka", Channel: "#osaka"},
+		},
+	}
+
+	testSets := []struct {
+		failing  map[string]bool
+		results  int
+		hasError bool
+	}{
+		{failing: map[string]bool{}, results: 2},
+		{failing: map[string]bool{"Tokyo": true}, results: 1},
+		{failing: map[string]bool{"Tokyo": true, "Osaka": true}, hasError: true},
+	}
+
+	for i, tt := range testSets {
+		fn := func(_ context.Context, _ TaskConfig, target TaskTarget) ([]*ScheduledTaskResult, error) {
+			city := target.(*DummyTaskTarget).City
+			if tt.failing[city] {
+				return nil, errors.New("dummy")
+			}
+			return []*ScheduledTaskResult{{Content: city}}, nil
+		}
+
+		results, err := fanOut(context.TODO(), config, fn)
+		if tt.hasError != (err != nil) {
+			t.Errorf("Unexpected error state on test #%d: %#v.", i+1, err)
+		}
+		if len(results) != tt.results {
+			t.Errorf("Unexpected number of results are returned on test #%d: %d.", i+1, len(results))
+		}
+	}
+
+	results, err := fanOut(context.TODO(), &DummyFanOutConfig{}, nil)
+	if err != nil || results != nil {
+		t.Errorf("Nothing should be returned without any entry: %#v, %#v.", results, err)
+	}
+}
@@ -0,0 +1,151 @@
+package sarah
+
+import (
+	"context"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"sync"
+	"time"
+)
+
+// TranscriptEntry represents a message in a conversation transcript.
+type TranscriptEntry struct {
+	// Sender is the value returned by Input.SenderKey.
+	Sender string `json:"sender"`
+
+	// Message is the value returned by Input.Message.
+	Message string `json:"message"`
+
+	// SentAt is the value returned by Input.SentAt.
+	SentAt time.Time `json:"sent_at"`
+}
+
+// TranscriptStore defines an interface to store the conversation transcript of each channel.
+// Implement this to persist the transcripts in the preferred storage such as Redis; use NewInMemoryTranscriptStore otherwise.
+type TranscriptStore interface {
+	// Append adds the given entry to the transcript of the given channel.
+	// This is called synchronously on every Input reception, so the implementation should return as soon as possible.
+	Append(ctx context.Context, botType BotType, channel string, entry *TranscriptEntry) error
+
+	// Recent returns the last n entries of the given channel in chronological order.
+	Recent(ctx context.Context, botType BotType, channel string, n int) ([]*TranscriptEntry, error)
+}
+
+// RegisterTranscriptStore registers a given TranscriptStore to store every Input that the Bots receive as a conversation transcript.
+// Commands can refer to the stored transcript via the same TranscriptStore and TranscriptChannel
+// to provide context-aware responses, e.g., to pass the recent conversation to a language model.
+func RegisterTranscriptStore(store TranscriptStore) {
+	defaultRunner().RegisterTranscriptStore(store)
+}
+
+// RegisterTranscriptStore is the Runner counterpart of the package-level RegisterTranscriptStore.
+func (rn *Runner) RegisterTranscriptStore(store TranscriptStore) {
+	rn.options.register(func(r *runner) {
+		r.transcriptStore = store
+	})
+}
+
+// TranscriptChannel returns the key of the channel that the given Input belongs to.
+// The key is derived from Input.ReplyTo so that the Inputs in the same chat room share the same transcript.
+func TranscriptChannel(input Input) string {
+	return destinationKey(input.ReplyTo())
+}
+
+// transcriptInputReceiver wraps the given function to append every Input to the given TranscriptStore before handling.
+// A failure to store is logged and does not prevent the Input from being handled.
+func transcriptInputReceiver(ctx context.Context, botType BotType, store TranscriptStore, receive func(Input) error) func(Input) error {
+	return func(input Input) error {
+		entry := &TranscriptEntry{
+			Sender:  input.SenderKey(),
+			Message: input.Message(),
+			SentAt:  input.SentAt(),
+		}
+		err := store.Append(ctx, botType, TranscriptChannel(input), entry)
+		if err != nil {
+			logger.Errorf("Failed to store an input to the transcript: %+v", err)
+		}
+
+		return receive(input)
+	}
+}
+
+// inMemoryTranscriptStore is a TranscriptStore implementation that keeps a fixed number of the latest entries per channel in the process memory space.
+type inMemoryTranscriptStore struct {
+	capacity int
+	buffers  map[string]*transcriptRing
+	mutex    sync.Mutex
+}
+
+var _ TranscriptStore = (*inMemoryTranscriptStore)(nil)
+
+// NewInMemoryTranscriptStore creates and returns a new TranscriptStore implementation that keeps the latest entries per channel in a ring buffer.
+// The given capacity declares the maximum number of entries per channel; older entries are discarded.
+// Stored entries are lost when the process stops.
+func NewInMemoryTranscriptStore(capacity int) TranscriptStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &inMemoryTranscriptStore{
+		capacity: capacity,
+		buffers:  map[string]*transcriptRing{},
+	}
+}
+
+// Append adds the given entry to the ring buffer of the given channel.
+func (s *inMemoryTranscriptStore) Append(_ context.Context, botType BotType, channel string, entry *TranscriptEntry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := fmt.Sprintf("%s:%s", botType, channel)
+	buffer, ok := s.buffers[key]
+	if !ok {
+		buffer = &transcriptRing{entries: make([]*TranscriptEntry, s.capacity)}
+		s.buffers[key] = buffer
+	}
+	buffer.push(entry)
+
+	return nil
+}
+
+// Recent returns the last n entries of the given channel in chronological order.
+func (s *inMemoryTranscriptStore) Recent(_ context.Context, botType BotType, channel string, n int) ([]*TranscriptEntry, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	buffer, ok := s.buffers[fmt.Sprintf("%s:%s", botType, channel)]
+	if !ok {
+		return []*TranscriptEntry{}, nil
+	}
+	return buffer.last(n), nil
+}
+
+type transcriptRing struct {
+	entries []*TranscriptEntry
+	next    int
+	size    int
+}
+
+func (r *transcriptRing) push(entry *TranscriptEntry) {
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.size < len(r.entries) {
+		r.size++
+	}
+}
+
+func (r *transcriptRing) last(n int) []*TranscriptEntry {
+	if n > r.size {
+		n = r.size
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	entries := make([]*TranscriptEntry, n)
+	start := r.next - n + len(r.entries)
+	for i := 0; i < n; i++ {
+		entries[i] = r.entries[(start+i)%len(r.entries)]
+	}
+	return entries
+}
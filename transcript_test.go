@@ -0,0 +1,141 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type DummyTranscriptStore struct {
+	AppendFunc func(context.Context, BotType, string, *TranscriptEntry) error
+	RecentFunc func(context.Context, BotType, string, int) ([]*TranscriptEntry, error)
+}
+
+var _ TranscriptStore = (*DummyTranscriptStore)(nil)
+
+func (s *DummyTranscriptStore) Append(ctx context.Context, botType BotType, channel string, entry *TranscriptEntry) error {
+	return s.AppendFunc(ctx, botType, channel, entry)
+}
+
+func (s *DummyTranscriptStore) Recent(ctx context.Context, botType BotType, channel string, n int) ([]*TranscriptEntry, error) {
+	return s.RecentFunc(ctx, botType, channel, n)
+}
+
+type stringerDestination struct {
+	id string
+}
+
+func (d *stringerDestination) String() string {
+	return d.id
+}
+
+func TestRegisterTranscriptStore(t *testing.T) {
+	SetupAndRun(func() {
+		store := &DummyTranscriptStore{}
+		RegisterTranscriptStore(store)
+		r := &runner{}
+
+		for _, v := range options.stashed {
+			v(r)
+		}
+
+		if r.transcriptStore != store {
+			t.Errorf("Given TranscriptStore is not set: %#v.", r.transcriptStore)
+		}
+	})
+}
+
+func TestTranscriptChannel(t *testing.T) {
+	testSets := []struct {
+		dest     OutputDestination
+		expected string
+	}{
+		{dest: "#general", expected: "#general"},
+		{dest: &stringerDestination{id: "C123"}, expected: "C123"},
+		{dest: 123, expected: "123"},
+	}
+
+	for i, testSet := range testSets {
+		channel := TranscriptChannel(&DummyInput{ReplyToValue: testSet.dest})
+		if channel != testSet.expected {
+			t.Errorf("Unexpected channel is returned on test #%d: %s.", i+1, channel)
+		}
+	}
+}
+
+func Test_transcriptInputReceiver(t *testing.T) {
+	var appended []*TranscriptEntry
+	store := &DummyTranscriptStore{
+		AppendFunc: func(_ context.Context, botType BotType, channel string, entry *TranscriptEntry) error {
+			if botType != "dummy" || channel != "#general" {
+				t.Errorf("Unexpected arguments are given: %s, %s.", botType, channel)
+			}
+			appended = append(appended, entry)
+			return errors.New("failure must not block the input")
+		},
+	}
+
+	received := 0
+	receive := transcriptInputReceiver(context.TODO(), "dummy", store, func(_ Input) error {
+		received++
+		return nil
+	})
+
+	err := receive(&DummyInput{SenderKeyValue: "alice", MessageValue: "hello", ReplyToValue: "#general"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if received != 1 {
+		t.Error("Input is not passed to the receiver.")
+	}
+
+	if len(appended) != 1 || appended[0].Sender != "alice" || appended[0].Message != "hello" {
+		t.Errorf("Input is not appended: %#v.", appended)
+	}
+}
+
+func Test_inMemoryTranscriptStore(t *testing.T) {
+	store := NewInMemoryTranscriptStore(3)
+	ctx := context.TODO()
+
+	for i := 1; i <= 5; i++ {
+		err := store.Append(ctx, "dummy", "#general", &TranscriptEntry{Message: fmt.Sprint(i)})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+	}
+	_ = store.Append(ctx, "dummy", "#random", &TranscriptEntry{Message: "other"})
+
+	testSets := []struct {
+		n        int
+		expected []string
+	}{
+		{n: 1, expected: []string{"5"}},
+		{n: 2, expected: []string{"4", "5"}},
+		{n: 10, expected: []string{"3", "4", "5"}},
+		{n: 0, expected: []string{}},
+	}
+
+	for _, testSet := range testSets {
+		entries, err := store.Recent(ctx, "dummy", "#general", testSet.n)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		var messages []string
+		for _, entry := range entries {
+			messages = append(messages, entry.Message)
+		}
+
+		if fmt.Sprint(messages) != fmt.Sprint(testSet.expected) {
+			t.Errorf("Unexpected entries are returned for %d: %#v.", testSet.n, messages)
+		}
+	}
+
+	entries, _ := store.Recent(ctx, "other", "#general", 10)
+	if len(entries) != 0 {
+		t.Errorf("Entries of other BotType should not be returned: %#v.", entries)
+	}
+}
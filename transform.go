@@ -0,0 +1,148 @@
+package sarah
+
+import (
+	"html"
+	"strings"
+)
+
+// OutputTransformer transforms the text of an outgoing message.
+// Use this for a deployment-wide rule such as profanity filtering, emoji shortcode expansion, or entity escaping for an HTML-based chat service.
+type OutputTransformer func(string) string
+
+// EscapeHTML is an OutputTransformer that escapes special characters such as "<" and "&" for a chat service that renders messages as HTML.
+var EscapeHTML OutputTransformer = html.EscapeString
+
+// ReplacingTransformer creates and returns an OutputTransformer that replaces the given old and new string pairs just like strings.NewReplacer.
+// The replacements are performed in the order they appear in the text, without overlapping matches.
+//
+//	// Expand emoji shortcodes.
+//	transformer := sarah.ReplacingTransformer(":smile:", "\U0001F604", ":+1:", "\U0001F44D")
+func ReplacingTransformer(oldnew ...string) OutputTransformer {
+	return strings.NewReplacer(oldnew...).Replace
+}
+
+// BotWithOutputTransformer creates and returns a DefaultBotOption that passes the text of every outgoing message through the given OutputTransformers in the given order.
+// The transformation is applied to the Outputs that Bot.SendMessage sends, and the contents that Bot.SendEditableMessage sends and Bot.EditMessage replaces.
+// A string content and the texts of a *RichContent are transformed while the value of a ContentButton is kept as is so a user can still type it to reply.
+// Other Adapter-specific contents such as Slack's webapi.PostMessage are passed to the Adapter without transformation.
+//
+// Give this option to each NewBot call that needs the transformation, so a different rule can be applied to each chat service.
+//
+//	bot := sarah.NewBot(myAdapter, sarah.BotWithOutputTransformer(sarah.EscapeHTML))
+func BotWithOutputTransformer(transformers ...OutputTransformer) DefaultBotOption {
+	return func(bot *defaultBot) {
+		bot.transformers = append(bot.transformers, transformers...)
+	}
+}
+
+// transformOutput returns an Output with the transformed content. The given Output is returned as is when no transformation is required.
+func transformOutput(output Output, transformers []OutputTransformer) Output {
+	if len(transformers) == 0 {
+		return output
+	}
+
+	content, ok := transformContent(output.Content(), transformers)
+	if !ok {
+		return output
+	}
+	return NewOutputMessage(output.Destination(), content)
+}
+
+// transformContent returns the transformed content and true when the given content is a string or a *RichContent.
+func transformContent(content interface{}, transformers []OutputTransformer) (interface{}, bool) {
+	if len(transformers) == 0 {
+		return content, false
+	}
+
+	transform := func(text string) string {
+		if text == "" {
+			return text
+		}
+		for _, transformer := range transformers {
+			text = transformer(text)
+		}
+		return text
+	}
+
+	switch c := content.(type) {
+	case string:
+		return transform(c), true
+
+	case *RichContent:
+		if c == nil {
+			return content, false
+		}
+		return c.transform(transform), true
+
+	default:
+		return content, false
+
+	}
+}
+
+// transform returns a copy of the RichContent with the texts transformed by the given function.
+// The given RichContent is not modified since a Command may send the same content to multiple destinations.
+func (c *RichContent) transform(fnc func(string) string) *RichContent {
+	transformed := &RichContent{
+		Text: fnc(c.Text),
+	}
+
+	for _, block := range c.Blocks {
+		switch b := block.(type) {
+		case *HeaderBlock:
+			transformed.Blocks = append(transformed.Blocks, &HeaderBlock{Text: fnc(b.Text)})
+
+		case *SectionBlock:
+			section := &SectionBlock{Text: fnc(b.Text)}
+			for _, field := range b.Fields {
+				section.Fields = append(section.Fields, fnc(field))
+			}
+			transformed.Blocks = append(transformed.Blocks, section)
+
+		case *ImageBlock:
+			transformed.Blocks = append(transformed.Blocks, &ImageBlock{URL: b.URL, AltText: fnc(b.AltText)})
+
+		case *ButtonsBlock:
+			buttons := &ButtonsBlock{}
+			for _, button := range b.Buttons {
+				buttons.Buttons = append(buttons.Buttons, &ContentButton{
+					Text:  fnc(button.Text),
+					Value: button.Value,
+					Style: button.Style,
+				})
+			}
+			transformed.Blocks = append(transformed.Blocks, buttons)
+
+		default:
+			transformed.Blocks = append(transformed.Blocks, block)
+
+		}
+	}
+
+	for _, attachment := range c.Attachments {
+		a := &ContentAttachment{
+			Title:     fnc(attachment.Title),
+			TitleLink: attachment.TitleLink,
+			Text:      fnc(attachment.Text),
+			Color:     attachment.Color,
+		}
+		for _, field := range attachment.Fields {
+			a.Fields = append(a.Fields, &ContentField{
+				Title: fnc(field.Title),
+				Value: fnc(field.Value),
+				Short: field.Short,
+			})
+		}
+		transformed.Attachments = append(transformed.Attachments, a)
+	}
+
+	for _, file := range c.Files {
+		transformed.Files = append(transformed.Files, &ContentFile{
+			Name:  file.Name,
+			Title: fnc(file.Title),
+			URL:   file.URL,
+		})
+	}
+
+	return transformed
+}
@@ -0,0 +1,211 @@
+package sarah
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEscapeHTML(t *testing.T) {
+	escaped := EscapeHTML(`<b>"Tom" & Jerry</b>`)
+	expected := "&lt;b&gt;&#34;Tom&#34; &amp; Jerry&lt;/b&gt;"
+	if escaped != expected {
+		t.Errorf("Unexpected text is returned: %s.", escaped)
+	}
+}
+
+func TestReplacingTransformer(t *testing.T) {
+	transformer := ReplacingTransformer(":smile:", "\U0001F604", "darn", "d**n")
+
+	transformed := transformer("Oh darn :smile:")
+	if transformed != "Oh d**n \U0001F604" {
+		t.Errorf("Unexpected text is returned: %s.", transformed)
+	}
+}
+
+func TestBotWithOutputTransformer(t *testing.T) {
+	bot := &defaultBot{}
+	BotWithOutputTransformer(strings.ToUpper)(bot)
+	BotWithOutputTransformer(strings.TrimSpace, EscapeHTML)(bot)
+
+	if len(bot.transformers) != 3 {
+		t.Errorf("Unexpected number of transformers are set: %d.", len(bot.transformers))
+	}
+}
+
+func Test_transformContent(t *testing.T) {
+	transformers := []OutputTransformer{
+		strings.ToUpper,
+		func(s string) string {
+			return s + "!"
+		},
+	}
+
+	testSets := []struct {
+		content     interface{}
+		expected    interface{}
+		transformed bool
+	}{
+		{
+			content:     "hello",
+			expected:    "HELLO!",
+			transformed: true,
+		},
+		{
+			content:     "",
+			expected:    "",
+			transformed: true,
+		},
+		{
+			content: &RichContent{
+				Text: "text",
+				Blocks: []ContentBlock{
+					&HeaderBlock{Text: "header"},
+					&SectionBlock{Text: "section", Fields: []string{"field"}},
+					&DividerBlock{},
+					&ImageBlock{URL: "https://example.com/a.png", AltText: "alt"},
+					&ButtonsBlock{Buttons: []*ContentButton{{Text: "yes", Value: "yes", Style: ButtonPrimary}}},
+				},
+				Attachments: []*ContentAttachment{
+					{
+						Title:     "title",
+						TitleLink: "https://example.com/",
+						Text:      "attachment",
+						Color:     "#36a64f",
+						Fields:    []*ContentField{{Title: "key", Value: "value", Short: true}},
+					},
+				},
+				Files: []*ContentFile{{Name: "a.txt", Title: "file", URL: "https://example.com/a.txt"}},
+			},
+			expected: &RichContent{
+				Text: "TEXT!",
+				Blocks: []ContentBlock{
+					&HeaderBlock{Text: "HEADER!"},
+					&SectionBlock{Text: "SECTION!", Fields: []string{"FIELD!"}},
+					&DividerBlock{},
+					&ImageBlock{URL: "https://example.com/a.png", AltText: "ALT!"},
+					&ButtonsBlock{Buttons: []*ContentButton{{Text: "YES!", Value: "yes", Style: ButtonPrimary}}},
+				},
+				Attachments: []*ContentAttachment{
+					{
+						Title:     "TITLE!",
+						TitleLink: "https://example.com/",
+						Text:      "ATTACHMENT!",
+						Color:     "#36a64f",
+						Fields:    []*ContentField{{Title: "KEY!", Value: "VALUE!", Short: true}},
+					},
+				},
+				Files: []*ContentFile{{Name: "a.txt", Title: "FILE!", URL: "https://example.com/a.txt"}},
+			},
+			transformed: true,
+		},
+		{
+			content:     struct{ Text string }{Text: "adapter specific"},
+			expected:    struct{ Text string }{Text: "adapter specific"},
+			transformed: false,
+		},
+		{
+			content:     (*RichContent)(nil),
+			expected:    (*RichContent)(nil),
+			transformed: false,
+		},
+	}
+
+	for i, testSet := range testSets {
+		content, transformed := transformContent(testSet.content, transformers)
+
+		if transformed != testSet.transformed {
+			t.Errorf("Unexpected flag is returned on test #%d: %t.", i, transformed)
+		}
+
+		if !reflect.DeepEqual(content, testSet.expected) {
+			t.Errorf("Unexpected content is returned on test #%d: %#v.", i, content)
+		}
+	}
+}
+
+func Test_transformContent_KeepOriginal(t *testing.T) {
+	original := &RichContent{
+		Text:   "text",
+		Blocks: []ContentBlock{&SectionBlock{Text: "section"}},
+	}
+
+	_, _ = transformContent(original, []OutputTransformer{strings.ToUpper})
+
+	if original.Text != "text" || original.Blocks[0].(*SectionBlock).Text != "section" {
+		t.Errorf("Given content is modified: %#v.", original)
+	}
+}
+
+func Test_transformOutput(t *testing.T) {
+	output := NewOutputMessage("dest", "hello")
+
+	if transformOutput(output, nil) != output {
+		t.Error("Given Output should be returned without transformers.")
+	}
+
+	transformed := transformOutput(output, []OutputTransformer{strings.ToUpper})
+	if transformed.Destination() != "dest" {
+		t.Errorf("Unexpected destination is set: %#v.", transformed.Destination())
+	}
+	if transformed.Content() != "HELLO" {
+		t.Errorf("Unexpected content is set: %#v.", transformed.Content())
+	}
+
+	unsupported := NewOutputMessage("dest", 123)
+	if transformOutput(unsupported, []OutputTransformer{strings.ToUpper}) != unsupported {
+		t.Error("Given Output should be returned when the content is not transformable.")
+	}
+}
+
+func TestDefaultBot_SendMessage_WithOutputTransformer(t *testing.T) {
+	var sent Output
+	adapter := &DummyAdapter{
+		SendMessageFunc: func(_ context.Context, output Output) {
+			sent = output
+		},
+	}
+	bot := NewBot(adapter, BotWithOutputTransformer(EscapeHTML))
+
+	bot.SendMessage(context.TODO(), NewOutputMessage("dest", "<b>hello</b>"))
+
+	if sent == nil {
+		t.Fatal("Output is not passed to Adapter.")
+	}
+	if sent.Content() != "&lt;b&gt;hello&lt;/b&gt;" {
+		t.Errorf("Unexpected content is sent: %#v.", sent.Content())
+	}
+}
+
+func TestDefaultBot_EditMessage_WithOutputTransformer(t *testing.T) {
+	var sent interface{}
+	var edited interface{}
+	adapter := &DummyMessageEditAdapter{
+		SendEditableMessageFunc: func(_ context.Context, output Output) (string, error) {
+			sent = output.Content()
+			return "id", nil
+		},
+		EditMessageFunc: func(_ context.Context, _ OutputDestination, _ string, content interface{}) error {
+			edited = content
+			return nil
+		},
+	}
+	bot := NewBot(adapter, BotWithOutputTransformer(strings.ToUpper)).(*defaultBot)
+
+	_, err := bot.SendEditableMessage(context.TODO(), NewOutputMessage("dest", "sent"))
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if sent != "SENT" {
+		t.Errorf("Unexpected content is sent: %#v.", sent)
+	}
+
+	err = bot.EditMessage(context.TODO(), "dest", "id", "edited")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if edited != "EDITED" {
+		t.Errorf("Unexpected content is edited: %#v.", edited)
+	}
+}
@@ -0,0 +1,148 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// ErrTaskNotFound is returned by TriggerTask when no ScheduledTask is registered with the given BotType and identifier.
+var ErrTaskNotFound = errors.New("scheduled task is not found")
+
+// ErrTaskBotNotRunning is returned by TriggerTask when the Bot that the ScheduledTask belongs to is not running.
+var ErrTaskBotNotRunning = errors.New("bot is not running")
+
+var taskTriggers = newTriggers()
+
+// TriggerTask executes the registered ScheduledTask with the given BotType and identifier on demand.
+// This is handy to test a task or re-run a failed report without waiting for the next schedule.
+//
+// When dest is not nil, all results are sent to dest regardless of the destinations the task returns.
+// e.g. Pass Input.ReplyTo to send the results to the requesting channel.
+// Otherwise, the results are sent to their destinations just like a scheduled execution.
+// The downstream tasks declared by ScheduledTaskPropsBuilder.After are not executed by this call.
+func TriggerTask(ctx context.Context, botType BotType, id string, dest OutputDestination) error {
+	return defaultRunner().TriggerTask(ctx, botType, id, dest)
+}
+
+// TriggerTask is the Runner counterpart of the package-level TriggerTask.
+func (rn *Runner) TriggerTask(ctx context.Context, botType BotType, id string, dest OutputDestination) error {
+	return triggerTask(ctx, rn.registries.taskTriggers, botType, id, dest)
+}
+
+func triggerTask(ctx context.Context, taskTriggers *triggers, botType BotType, id string, dest OutputDestination) error {
+	task, ok := taskTriggers.get(botType, id)
+	if !ok {
+		return fmt.Errorf("%w: %s:%s", ErrTaskNotFound, botType, id)
+	}
+
+	if task.botCtx.Err() != nil {
+		return fmt.Errorf("%w: %s", ErrTaskBotNotRunning, botType)
+	}
+
+	results, err := task.task.Execute(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to execute scheduled task %s: %w", id, err)
+	}
+
+	if dest != nil {
+		redirected := make([]*ScheduledTaskResult, len(results))
+		for i, res := range results {
+			redirected[i] = &ScheduledTaskResult{
+				Content:     res.Content,
+				Destination: dest,
+			}
+		}
+		results = redirected
+	}
+
+	return sendScheduledTaskResults(ctx, task.bot, task.task, results)
+}
+
+// NewTaskTriggerCommandPropsBuilder creates and returns a new CommandPropsBuilder that is preset to build a Command to trigger a ScheduledTask on demand.
+// The Command matches an input such as ".task daily_report" and executes the ScheduledTask with the given identifier via TriggerTask.
+// The results are sent to the requesting channel.
+// The ScheduledTask is looked up from the Runner that the Command is registered to.
+//
+// This Command should only be available for administrators.
+// Override the matching logic with CommandPropsBuilder.MatchFunc to limit the users who can trigger the tasks.
+//
+//	props := sarah.NewTaskTriggerCommandPropsBuilder(slack.SLACK).
+//		MatchFunc(func(input sarah.Input) bool {
+//			return isAdmin(input.SenderKey()) && strings.HasPrefix(input.Message(), ".task ")
+//		}).
+//		MustBuild()
+//	sarah.RegisterCommandProps(props)
+func NewTaskTriggerCommandPropsBuilder(botType BotType) *CommandPropsBuilder {
+	pattern := regexp.MustCompile(`^\.task\s+`)
+	return NewCommandPropsBuilder().
+		BotType(botType).
+		Identifier("task").
+		MatchPattern(pattern).
+		Instruction("Input .task followed by a scheduled task's identifier to execute the task now.").
+		Func(func(ctx context.Context, input Input) (*CommandResponse, error) {
+			id := StripMessage(pattern, input.Message())
+			err := triggerTask(ctx, registriesFromContext(ctx).taskTriggers, botType, id, input.ReplyTo())
+			if err != nil {
+				return &CommandResponse{
+					Content: fmt.Sprintf("Failed to execute %s: %s", id, err.Error()),
+				}, nil
+			}
+
+			// The results are already sent by TriggerTask.
+			return nil, nil
+		})
+}
+
+type triggerableTask struct {
+	botCtx context.Context
+	bot    Bot
+	task   ScheduledTask
+}
+
+// triggers stashes the registered ScheduledTasks so they can be executed on demand.
+// Calls to its methods are thread-safe.
+type triggers struct {
+	tasks map[BotType]map[string]*triggerableTask
+	mutex sync.RWMutex
+}
+
+func newTriggers() *triggers {
+	return &triggers{
+		tasks: make(map[BotType]map[string]*triggerableTask),
+	}
+}
+
+func (t *triggers) set(botCtx context.Context, bot Bot, task ScheduledTask) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	botType := bot.BotType()
+	if _, ok := t.tasks[botType]; !ok {
+		t.tasks[botType] = make(map[string]*triggerableTask)
+	}
+	t.tasks[botType][task.Identifier()] = &triggerableTask{
+		botCtx: botCtx,
+		bot:    bot,
+		task:   task,
+	}
+}
+
+func (t *triggers) remove(botType BotType, id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if tasks, ok := t.tasks[botType]; ok {
+		delete(tasks, id)
+	}
+}
+
+func (t *triggers) get(botType BotType, id string) (*triggerableTask, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	task, ok := t.tasks[botType][id]
+	return task, ok
+}
@@ -0,0 +1,190 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTriggerTask(t *testing.T) {
+	var botType BotType = "dummy"
+	results := []*ScheduledTaskResult{
+		{Content: "foo", Destination: "#foo"},
+		{Content: "bar"},
+	}
+	task := &DummyScheduledTask{
+		IdentifierValue: "report",
+		ExecuteFunc: func(_ context.Context) ([]*ScheduledTaskResult, error) {
+			return results, nil
+		},
+		DefaultDestinationValue: "#default",
+	}
+
+	t.Run("Redirected", func(t *testing.T) {
+		var sent []Output
+		bot := &DummyBot{
+			BotTypeValue: botType,
+			SendMessageFunc: func(_ context.Context, output Output) {
+				sent = append(sent, output)
+			},
+		}
+		taskTriggers = &triggers{tasks: make(map[BotType]map[string]*triggerableTask)}
+		taskTriggers.set(context.Background(), bot, task)
+
+		err := TriggerTask(context.TODO(), botType, "report", "#requester")
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if len(sent) != 2 {
+			t.Fatalf("Unexpected number of messages are sent: %d.", len(sent))
+		}
+		for _, output := range sent {
+			if output.Destination() != "#requester" {
+				t.Errorf("Unexpected destination is set: %#v.", output.Destination())
+			}
+		}
+
+		if results[1].Destination != nil {
+			t.Error("Original result should not be modified.")
+		}
+	})
+
+	t.Run("Not redirected", func(t *testing.T) {
+		var sent []Output
+		bot := &DummyBot{
+			BotTypeValue: botType,
+			SendMessageFunc: func(_ context.Context, output Output) {
+				sent = append(sent, output)
+			},
+		}
+		taskTriggers = &triggers{tasks: make(map[BotType]map[string]*triggerableTask)}
+		taskTriggers.set(context.Background(), bot, task)
+
+		err := TriggerTask(context.TODO(), botType, "report", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		if len(sent) != 2 || sent[0].Destination() != "#foo" || sent[1].Destination() != "#default" {
+			t.Errorf("Unexpected messages are sent: %#v.", sent)
+		}
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		taskTriggers = &triggers{tasks: make(map[BotType]map[string]*triggerableTask)}
+
+		err := TriggerTask(context.TODO(), botType, "report", nil)
+		if !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Bot not running", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		taskTriggers = &triggers{tasks: make(map[BotType]map[string]*triggerableTask)}
+		taskTriggers.set(ctx, &DummyBot{BotTypeValue: botType}, task)
+
+		err := TriggerTask(context.TODO(), botType, "report", nil)
+		if !errors.Is(err, ErrTaskBotNotRunning) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Execution failure", func(t *testing.T) {
+		expected := errors.New("failed")
+		failing := &DummyScheduledTask{
+			IdentifierValue: "report",
+			ExecuteFunc: func(_ context.Context) ([]*ScheduledTaskResult, error) {
+				return nil, expected
+			},
+		}
+		taskTriggers = &triggers{tasks: make(map[BotType]map[string]*triggerableTask)}
+		taskTriggers.set(context.Background(), &DummyBot{BotTypeValue: botType}, failing)
+
+		err := TriggerTask(context.TODO(), botType, "report", nil)
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func TestNewTaskTriggerCommandPropsBuilder(t *testing.T) {
+	var botType BotType = "dummy"
+	props, err := NewTaskTriggerCommandPropsBuilder(botType).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	command, err := buildCommand(context.TODO(), props, &DummyConfigWatcher{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	if !command.Match(&DummyInput{MessageValue: ".task report"}) {
+		t.Error("Command should match.")
+	}
+
+	if command.Match(&DummyInput{MessageValue: ".tasks"}) {
+		t.Error("Command should not match.")
+	}
+
+	var sent []Output
+	bot := &DummyBot{
+		BotTypeValue: botType,
+		SendMessageFunc: func(_ context.Context, output Output) {
+			sent = append(sent, output)
+		},
+	}
+	taskTriggers = &triggers{tasks: make(map[BotType]map[string]*triggerableTask)}
+	taskTriggers.set(context.Background(), bot, &DummyScheduledTask{
+		IdentifierValue: "report",
+		ExecuteFunc: func(_ context.Context) ([]*ScheduledTaskResult, error) {
+			return []*ScheduledTaskResult{{Content: "foo"}}, nil
+		},
+	})
+
+	res, err := command.Execute(context.TODO(), &DummyInput{MessageValue: ".task report", ReplyToValue: "#requester"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if res != nil {
+		t.Errorf("Unexpected response is returned: %#v.", res)
+	}
+	if len(sent) != 1 || sent[0].Destination() != "#requester" {
+		t.Errorf("Unexpected messages are sent: %#v.", sent)
+	}
+
+	res, err = command.Execute(context.TODO(), &DummyInput{MessageValue: ".task unknown", ReplyToValue: "#requester"})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if res == nil || !strings.Contains(res.Content.(string), "unknown") {
+		t.Errorf("Failure is not reported: %#v.", res)
+	}
+}
+
+func Test_triggers(t *testing.T) {
+	tr := &triggers{tasks: make(map[BotType]map[string]*triggerableTask)}
+	bot := &DummyBot{BotTypeValue: "dummy"}
+	task := &DummyScheduledTask{IdentifierValue: "report"}
+
+	tr.set(context.TODO(), bot, task)
+	stored, ok := tr.get("dummy", "report")
+	if !ok {
+		t.Fatal("Registered task is not found.")
+	}
+	if stored.task != task || stored.bot != bot {
+		t.Errorf("Unexpected task is stored: %#v.", stored)
+	}
+
+	tr.remove("dummy", "report")
+	if _, ok := tr.get("dummy", "report"); ok {
+		t.Error("Removed task is still found.")
+	}
+
+	// Removing an unknown task causes no trouble.
+	tr.remove("unknown", "report")
+}
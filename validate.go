@@ -0,0 +1,170 @@
+package sarah
+
+import (
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"sort"
+	"strings"
+)
+
+// ValidationWarning represents a potential misconfiguration of the registered Commands that Validate reports.
+type ValidationWarning struct {
+	// BotType is the BotType that the Command is registered for.
+	BotType BotType
+
+	// Identifier is the identifier of the Command that may not work as expected.
+	Identifier string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String returns the stringified representation of the warning.
+func (w *ValidationWarning) String() string {
+	return fmt.Sprintf("%s:%s: %s", w.BotType, w.Identifier, w.Message)
+}
+
+// Validate inspects the Commands registered via RegisterCommand and RegisterCommandProps and reports potential misconfigurations:
+//   - Commands with the same identifier. The later registration replaces the earlier one unless another CommandConflictPolicy is registered.
+//   - Commands whose literal prefixes overlap. The earlier registered Command may match the Input and shadow the later one.
+//     e.g. a Command with `^\.echo` is checked before and may shadow another Command with `^\.echoes`.
+//
+// The Commands are checked in the order that Bots check them: the ones built from CommandProps first, then the ones registered via RegisterCommand.
+// Call this before Run, e.g., in a test, to discover shadowed Commands before production. Sarah also logs the same warnings on Run.
+func Validate() []*ValidationWarning {
+	return defaultRunner().Validate()
+}
+
+// Validate is the Runner counterpart of the package-level Validate.
+func (rn *Runner) Validate() []*ValidationWarning {
+	r := &runner{
+		commands:     make(map[BotType][]Command),
+		commandProps: make(map[BotType][]*CommandProps),
+	}
+	rn.options.apply(r)
+	r.shareCommandProps()
+
+	return r.validateCommands()
+}
+
+// registeredCommand is a Command registration that validateCommands inspects.
+type registeredCommand struct {
+	identifier string
+	prefix     string
+}
+
+func (r *runner) validateCommands() []*ValidationWarning {
+	botTypes := map[BotType]struct{}{}
+	for botType := range r.commands {
+		botTypes[botType] = struct{}{}
+	}
+	for botType := range r.commandProps {
+		botTypes[botType] = struct{}{}
+	}
+
+	// Sort to return the warnings in a stable order.
+	sorted := make([]BotType, 0, len(botTypes))
+	for botType := range botTypes {
+		sorted = append(sorted, botType)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var warnings []*ValidationWarning
+	for _, botType := range sorted {
+		var registered []*registeredCommand
+		for _, props := range r.botCommandProps(botType) {
+			registered = append(registered, &registeredCommand{identifier: props.identifier, prefix: aliasedPrefix(props.matchPrefix, props.aliases)})
+		}
+		for _, command := range r.botCommands(botType) {
+			c := &registeredCommand{identifier: command.Identifier()}
+			if prefixed, ok := resolveCommand[PrefixedCommand](command); ok {
+				c.prefix = prefixed.MatchPrefix()
+			}
+			registered = append(registered, c)
+		}
+
+		warnings = append(warnings, validateRegisteredCommands(botType, r.commandConflictPolicy(botType), registered)...)
+	}
+
+	return warnings
+}
+
+func validateRegisteredCommands(botType BotType, policy CommandConflictPolicy, registered []*registeredCommand) []*ValidationWarning {
+	var warnings []*ValidationWarning
+	for j, later := range registered {
+		for _, earlier := range registered[:j] {
+			if earlier.identifier == later.identifier {
+				warnings = append(warnings, &ValidationWarning{
+					BotType:    botType,
+					Identifier: later.identifier,
+					Message:    duplicateMessage(policy),
+				})
+				break
+			}
+
+			if earlier.prefix != "" && later.prefix != "" && strings.HasPrefix(later.prefix, earlier.prefix) {
+				warnings = append(warnings, &ValidationWarning{
+					BotType:    botType,
+					Identifier: later.identifier,
+					Message: fmt.Sprintf("%s is checked earlier and may shadow this command: both match the messages starting with %q",
+						earlier.identifier, later.prefix),
+				})
+				break
+			}
+		}
+	}
+
+	return warnings
+}
+
+// duplicateMessage describes how the given CommandConflictPolicy treats the identifier that is registered more than once.
+func duplicateMessage(policy CommandConflictPolicy) string {
+	switch policy {
+	case CommandConflictKeepFirst:
+		return "the identifier is registered more than once, so the later registration is ignored"
+
+	case CommandConflictError:
+		return "the identifier is registered more than once, so Run fails"
+
+	default:
+		return "the identifier is registered more than once, so the later registration replaces the earlier one"
+
+	}
+}
+
+// logValidationWarnings logs the warnings of the registered Commands.
+func (r *runner) logValidationWarnings() {
+	for _, warning := range r.validateCommands() {
+		logger.Warnf("Command validation: %s", warning.String())
+	}
+}
+
+// validateConfig returns all errors in the given Config so they can be reported at once on Run.
+func validateConfig(config *Config) []error {
+	var errs []error
+	if config.LogLevel != "" {
+		if _, err := parseLogLevel(config.LogLevel); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if config.LogFilter != nil {
+		if _, err := config.LogFilter.filters(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if config.Supervisor != nil && config.Supervisor.MaxCount < 1 {
+		errs = append(errs, fmt.Errorf("max_count of the supervisor must be one or greater: %d", config.Supervisor.MaxCount))
+	}
+
+	for botType, drain := range config.Drain {
+		if drain != nil && drain.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("timeout of the drain for %s must not be negative: %s", botType, drain.Timeout))
+		}
+	}
+
+	return errs
+}
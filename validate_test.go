@@ -0,0 +1,75 @@
+package sarah
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func buildValidationProps(t *testing.T, botType BotType, id string, pattern string) *CommandProps {
+	props, err := NewCommandPropsBuilder().
+		BotType(botType).
+		Identifier(id).
+		Instruction("dummy").
+		MatchPattern(regexp.MustCompile(pattern)).
+		Func(func(_ context.Context, _ Input) (*CommandResponse, error) {
+			return nil, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	return props
+}
+
+func TestValidationWarning_String(t *testing.T) {
+	warning := &ValidationWarning{
+		BotType:    "slack",
+		Identifier: "echo",
+		Message:    "message",
+	}
+
+	if warning.String() != "slack:echo: message" {
+		t.Errorf("Unexpected string is returned: %s.", warning.String())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterCommandProps(buildValidationProps(t, "slack", "echo", `^\.echo`))
+		RegisterCommandProps(buildValidationProps(t, "slack", "echoes", `^\.echoes\b`))
+		RegisterCommandProps(buildValidationProps(t, "slack", "weather", `^\.weather\b`))
+		RegisterCommand("slack", newDummyPrefixedCommand("weather", ".forecast"))
+		RegisterCommand("slack", &DummyCommand{IdentifierValue: "catchAll"})
+
+		// Commands for other BotTypes do not conflict.
+		RegisterCommandProps(buildValidationProps(t, "gitter", "echo", `^\.echo`))
+		RegisterCommandProps(buildValidationProps(t, "gitter", "hello", `(?i)^\.HELLO`))
+
+		warnings := Validate()
+
+		if len(warnings) != 2 {
+			t.Fatalf("Unexpected number of warnings are returned: %#v.", warnings)
+		}
+
+		if warnings[0].BotType != "slack" || warnings[0].Identifier != "echoes" {
+			t.Errorf("Unexpected warning is returned: %s.", warnings[0].String())
+		}
+
+		if warnings[1].BotType != "slack" || warnings[1].Identifier != "weather" {
+			t.Errorf("Unexpected warning is returned: %s.", warnings[1].String())
+		}
+	})
+}
+
+func TestValidate_NoWarning(t *testing.T) {
+	SetupAndRun(func() {
+		RegisterCommandProps(buildValidationProps(t, "slack", "echo", `^\.echo\b`))
+		RegisterCommandProps(buildValidationProps(t, "slack", "weather", `^\.weather\b`))
+
+		warnings := Validate()
+		if len(warnings) != 0 {
+			t.Errorf("Unexpected warnings are returned: %#v.", warnings)
+		}
+	})
+}
@@ -57,3 +57,68 @@ func (*nullConfigWatcher) Watch(_ context.Context, _ BotType, _ string, _ func()
 func (*nullConfigWatcher) Unwatch(_ BotType) error {
 	return nil
 }
+
+// NewCompositeConfigWatcher creates and returns a ConfigWatcher that composes the given ConfigWatcher implementations.
+// The watchers are given in the order of precedence; the first one has the highest precedence.
+// This helps to migrate the configuration source gradually, e.g., from files to a key-value store, while keeping both sources available.
+//
+// Read reads the configuration from each watcher in the reverse order so the value from a watcher with higher precedence overrides the one with lower precedence.
+// A watcher without the configuration is skipped, and ConfigNotFoundError is returned only when none of the watchers has the configuration.
+// Watch subscribes to the changes of all watchers, and the callback is called when any of them detects a change.
+// Unwatch unsubscribes from all watchers.
+func NewCompositeConfigWatcher(watchers ...ConfigWatcher) ConfigWatcher {
+	return &compositeConfigWatcher{
+		watchers: watchers,
+	}
+}
+
+type compositeConfigWatcher struct {
+	watchers []ConfigWatcher
+}
+
+var _ ConfigWatcher = (*compositeConfigWatcher)(nil)
+
+func (w *compositeConfigWatcher) Read(botCtx context.Context, botType BotType, id string, configPtr interface{}) error {
+	found := false
+	for i := len(w.watchers) - 1; i >= 0; i-- {
+		err := w.watchers[i].Read(botCtx, botType, id, configPtr)
+		var notFoundErr *ConfigNotFoundError
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read configuration with the watcher at %d: %w", i, err)
+		}
+		found = true
+	}
+
+	if !found {
+		return &ConfigNotFoundError{
+			BotType: botType,
+			ID:      id,
+		}
+	}
+	return nil
+}
+
+func (w *compositeConfigWatcher) Watch(botCtx context.Context, botType BotType, id string, callback func()) error {
+	var errs []error
+	for i, watcher := range w.watchers {
+		err := watcher.Watch(botCtx, botType, id, callback)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to watch configuration with the watcher at %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *compositeConfigWatcher) Unwatch(botType BotType) error {
+	var errs []error
+	for i, watcher := range w.watchers {
+		err := watcher.Unwatch(botType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to unwatch configuration with the watcher at %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
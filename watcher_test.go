@@ -2,6 +2,7 @@ package sarah
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -46,3 +47,173 @@ func TestNullConfigWatcher_Unwatch(t *testing.T) {
 		t.Fatalf("Unexpected error is returned: %s.", err.Error())
 	}
 }
+
+func TestNewCompositeConfigWatcher(t *testing.T) {
+	w1 := &DummyConfigWatcher{}
+	w2 := &DummyConfigWatcher{}
+	watcher := NewCompositeConfigWatcher(w1, w2)
+
+	composite, ok := watcher.(*compositeConfigWatcher)
+	if !ok {
+		t.Fatalf("Unexpected type is returned: %T.", watcher)
+	}
+	if len(composite.watchers) != 2 || composite.watchers[0] != w1 || composite.watchers[1] != w2 {
+		t.Errorf("Given watchers are not set in order: %#v.", composite.watchers)
+	}
+}
+
+func TestCompositeConfigWatcher_Read(t *testing.T) {
+	type config struct {
+		Token   string
+		Channel string
+	}
+	notFound := func(_ context.Context, botType BotType, id string, _ interface{}) error {
+		return &ConfigNotFoundError{BotType: botType, ID: id}
+	}
+
+	t.Run("Merge", func(t *testing.T) {
+		high := &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+				configPtr.(*config).Token = "high"
+				return nil
+			},
+		}
+		low := &DummyConfigWatcher{
+			ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+				configPtr.(*config).Token = "low"
+				configPtr.(*config).Channel = "low"
+				return nil
+			},
+		}
+		watcher := &compositeConfigWatcher{watchers: []ConfigWatcher{high, low}}
+
+		c := &config{}
+		err := watcher.Read(context.TODO(), "dummy", "id", c)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		if c.Token != "high" || c.Channel != "low" {
+			t.Errorf("Configuration is not merged in order of precedence: %#v.", c)
+		}
+	})
+
+	t.Run("Fall through", func(t *testing.T) {
+		watcher := &compositeConfigWatcher{
+			watchers: []ConfigWatcher{
+				&DummyConfigWatcher{ReadFunc: notFound},
+				&DummyConfigWatcher{
+					ReadFunc: func(_ context.Context, _ BotType, _ string, configPtr interface{}) error {
+						configPtr.(*config).Token = "low"
+						return nil
+					},
+				},
+			},
+		}
+
+		c := &config{}
+		err := watcher.Read(context.TODO(), "dummy", "id", c)
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+		if c.Token != "low" {
+			t.Errorf("Configuration is not read: %#v.", c)
+		}
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		watcher := &compositeConfigWatcher{
+			watchers: []ConfigWatcher{
+				&DummyConfigWatcher{ReadFunc: notFound},
+				&DummyConfigWatcher{ReadFunc: notFound},
+			},
+		}
+
+		err := watcher.Read(context.TODO(), "dummy", "id", &config{})
+		var notFoundErr *ConfigNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		expected := errors.New("dummy")
+		watcher := &compositeConfigWatcher{
+			watchers: []ConfigWatcher{
+				&DummyConfigWatcher{
+					ReadFunc: func(_ context.Context, _ BotType, _ string, _ interface{}) error {
+						return expected
+					},
+				},
+				&DummyConfigWatcher{ReadFunc: notFound},
+			},
+		}
+
+		err := watcher.Read(context.TODO(), "dummy", "id", &config{})
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func TestCompositeConfigWatcher_Watch(t *testing.T) {
+	expected := errors.New("dummy")
+	var subscribed []func()
+	watcher := &compositeConfigWatcher{
+		watchers: []ConfigWatcher{
+			&DummyConfigWatcher{
+				WatchFunc: func(_ context.Context, _ BotType, _ string, callback func()) error {
+					subscribed = append(subscribed, callback)
+					return nil
+				},
+			},
+			&DummyConfigWatcher{
+				WatchFunc: func(_ context.Context, _ BotType, _ string, _ func()) error {
+					return expected
+				},
+			},
+			&DummyConfigWatcher{
+				WatchFunc: func(_ context.Context, _ BotType, _ string, callback func()) error {
+					subscribed = append(subscribed, callback)
+					return nil
+				},
+			},
+		},
+	}
+
+	called := 0
+	err := watcher.Watch(context.TODO(), "dummy", "id", func() {
+		called++
+	})
+	if !errors.Is(err, expected) {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+	if len(subscribed) != 2 {
+		t.Fatalf("Other watchers should still subscribe: %d.", len(subscribed))
+	}
+
+	for _, callback := range subscribed {
+		callback()
+	}
+	if called != 2 {
+		t.Errorf("Callback is not called by each watcher: %d.", called)
+	}
+}
+
+func TestCompositeConfigWatcher_Unwatch(t *testing.T) {
+	unwatched := 0
+	w := &DummyConfigWatcher{
+		UnwatchFunc: func(_ BotType) error {
+			unwatched++
+			return nil
+		},
+	}
+	watcher := &compositeConfigWatcher{watchers: []ConfigWatcher{w, w}}
+
+	err := watcher.Unwatch("dummy")
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if unwatched != 2 {
+		t.Errorf("Every watcher should be unsubscribed: %d.", unwatched)
+	}
+}
@@ -2,7 +2,9 @@
 package watchers
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,9 +12,12 @@ import (
 	"github.com/oklahomer/go-kasumi/logger"
 	"github.com/oklahomer/go-sarah/v4"
 	"gopkg.in/yaml.v2"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 // abstractFsWatcher defines an interface to abstract fsnotify.Watcher.
@@ -29,11 +34,37 @@ type subscription struct {
 	absDir   string
 	callback func()
 	initErr  chan error
+
+	// hash is the hash of the file content that is notified last time. This is nil when the content is not read, yet.
+	hash []byte
+
+	// timer is the pending timer to notify the change after the quiet period.
+	timer *time.Timer
+
+	// nestedDirs holds the subscribing directories under the subdirectory named after the id.
+	nestedDirs []string
+}
+
+// FileWatcherOption defines a function's signature that NewFileWatcher's functional option must satisfy.
+type FileWatcherOption func(*fileWatcher)
+
+// WithQuietPeriod creates a FileWatcherOption that debounces the notifications of each subscription.
+// A change is notified only after the configuration file stays unchanged for the given period,
+// so an editor that writes a file multiple times in a row triggers only one rebuild of the corresponding Command or ScheduledTask.
+// Zero value, which is the default, notifies each change immediately.
+func WithQuietPeriod(period time.Duration) FileWatcherOption {
+	return func(w *fileWatcher) {
+		w.quietPeriod = period
+	}
 }
 
 // NewFileWatcher creates and a returns a new instance of sarah.ConfigWatcher implementation.
 // This watcher subscribes to changes on the filesystem.
-func NewFileWatcher(ctx context.Context, baseDir string) (sarah.ConfigWatcher, error) {
+// The configuration of a Command or a ScheduledTask is placed either as a file such as baseDir/slack/hello.yaml
+// or as a subdirectory such as baseDir/slack/hello/config.yaml along with auxiliary files; the subdirectory is subscribed recursively.
+// A write event is notified only when the content of the file differs from the one that was notified last time,
+// so touching a file or saving it without any modification does not trigger a rebuild.
+func NewFileWatcher(ctx context.Context, baseDir string, options ...FileWatcherOption) (sarah.ConfigWatcher, error) {
 	fsWatcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, fmt.Errorf("failed to start file watcher: %w", err)
@@ -43,8 +74,12 @@ func NewFileWatcher(ctx context.Context, baseDir string) (sarah.ConfigWatcher, e
 		fsWatcher:   fsWatcher,
 		subscribe:   make(chan *subscription),
 		unsubscribe: make(chan sarah.BotType),
+		settled:     make(chan *subscription),
 		baseDir:     baseDir,
 	}
+	for _, opt := range options {
+		opt(w)
+	}
 	go w.run(ctx, fsWatcher.Events, fsWatcher.Errors)
 
 	return w, nil
@@ -54,7 +89,9 @@ type fileWatcher struct {
 	fsWatcher   abstractFsWatcher
 	subscribe   chan *subscription
 	unsubscribe chan sarah.BotType
+	settled     chan *subscription
 	baseDir     string
+	quietPeriod time.Duration
 }
 
 var _ sarah.ConfigWatcher = (*fileWatcher)(nil)
@@ -147,7 +184,7 @@ func (w *fileWatcher) run(ctx context.Context, events <-chan fsnotify.Event, err
 			case event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create:
 				logger.Infof("Received %s event for %s.", event.Op.String(), event.Name)
 
-				doHandleEvent(event, subscriptions)
+				w.handleEvent(ctx, event, subscriptions)
 
 			default:
 				// Do nothing
@@ -163,34 +200,190 @@ func (w *fileWatcher) run(ctx context.Context, events <-chan fsnotify.Event, err
 			logger.Infof("Stop subscribing config files for %s", botType)
 			doUnsubscribe(w.fsWatcher, botType, subscriptions)
 
+		case settled := <-w.settled:
+			if !isSubscribing(settled, subscriptions) {
+				// Unsubscribed during the quiet period.
+				continue
+			}
+			notifyChange(settled)
+
 		case err := <-errs:
 			logger.Errorf("Error on subscribing to directory change: %+v", err)
 		}
 	}
 }
 
-func doHandleEvent(event fsnotify.Event, subscriptions map[string][]*subscription) {
-	configFile, err := plainPathToFile(event.Name)
-	if errors.Is(err, errUnableToDetermineConfigFileFormat) || errors.Is(err, errUnsupportedConfigFileFormat) {
-		// Irrelevant file is updated
-		return
-	} else if err != nil {
+func (w *fileWatcher) handleEvent(ctx context.Context, event fsnotify.Event, subscriptions map[string][]*subscription) {
+	absPath, err := filepath.Abs(event.Name)
+	if err != nil {
 		logger.Warnf("Failed to locate %s: %+v", event.Name, err)
 		return
 	}
 
-	watches, ok := subscriptions[configFile.absDir]
+	absDir, id, ok := resolveSubscription(absPath, subscriptions)
 	if !ok {
-		// No corresponding subscription is found for the directory
+		// Irrelevant file is updated, or no corresponding subscription is found for the directory
 		return
 	}
 
 	// Notify all subscribers
-	for _, watch := range watches {
-		if watch.id == configFile.id {
-			watch.callback()
+	for _, watch := range subscriptions[absDir] {
+		if watch.id != id {
+			continue
+		}
+
+		if event.Op&fsnotify.Create == fsnotify.Create && isDir(absPath) {
+			// Subscribe to the newly created directory in the Command's subdirectory, too.
+			addNestedDirs(w.fsWatcher, watch, absPath)
+		}
+
+		if w.quietPeriod <= 0 {
+			notifyChange(watch)
+			continue
+		}
+
+		// Postpone the notification until the files stay unchanged for the quiet period.
+		if watch.timer != nil {
+			watch.timer.Stop()
+		}
+		settled := watch
+		watch.timer = time.AfterFunc(w.quietPeriod, func() {
+			select {
+			case w.settled <- settled:
+				// O.K.
+
+			case <-ctx.Done():
+				// The watcher is already stopped.
+
+			}
+		})
+	}
+}
+
+// resolveSubscription finds the subscribing directory and the configuration id that the given path belongs to.
+// The path is either a configuration file directly under the subscribing directory such as configs/slack/hello.yaml,
+// or a file or a directory of any depth under the subdirectory named after the id such as configs/slack/hello/template.txt.
+func resolveSubscription(absPath string, subscriptions map[string][]*subscription) (string, string, bool) {
+	for dir := filepath.Dir(absPath); ; dir = filepath.Dir(dir) {
+		if _, ok := subscriptions[dir]; ok {
+			rel, err := filepath.Rel(dir, absPath)
+			if err != nil {
+				return "", "", false
+			}
+
+			components := strings.Split(rel, string(filepath.Separator))
+			if len(components) > 1 || isDir(absPath) {
+				// In the subdirectory named after the id.
+				return dir, components[0], true
+			}
+
+			configFile, err := plainPathToFile(absPath)
+			if err != nil {
+				return "", "", false
+			}
+			return dir, configFile.id, true
+		}
+
+		if dir == filepath.Dir(dir) {
+			// Reached the root directory.
+			return "", "", false
+		}
+	}
+}
+
+// notifyChange calls the subscription's callback when the content of the configuration differs from the one that was notified last time.
+// When the configuration can not be read, the callback is called anyway so the subscriber can read the latest state by itself.
+func notifyChange(s *subscription) {
+	hash, err := hashConfig(s.absDir, s.id)
+	if err != nil {
+		logger.Warnf("Failed to read configuration of %s to detect the change: %+v", s.id, err)
+		s.hash = nil
+		s.callback()
+		return
+	}
+
+	if s.hash != nil && bytes.Equal(s.hash, hash) {
+		logger.Debugf("Skip notifying the change since the configuration of %s is not changed.", s.id)
+		return
+	}
+	s.hash = hash
+	s.callback()
+}
+
+// hashConfig calculates the hash of the configuration with the given id.
+// For a configuration file directly under the given directory, only the file's content is hashed.
+// For a configuration in the subdirectory named after the id, all files under the subdirectory including the auxiliary ones are hashed.
+func hashConfig(absDir, id string) ([]byte, error) {
+	file := findPluginConfigFile(absDir, id)
+	if file == nil {
+		return nil, errConfigFileNotFound
+	}
+
+	h := sha256.New()
+	if file.absDir == absDir {
+		content, err := os.ReadFile(file.absPath)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(content)
+		return h.Sum(nil), nil
+	}
+
+	err := filepath.WalkDir(file.absDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
 		}
+
+		// Include the relative path so renaming a file is also detected.
+		rel, _ := filepath.Rel(file.absDir, path)
+		h.Write([]byte(rel))
+		h.Write([]byte{0})
+		h.Write(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return h.Sum(nil), nil
+}
+
+func isSubscribing(s *subscription, subscriptions map[string][]*subscription) bool {
+	for _, subscribing := range subscriptions[s.absDir] {
+		if subscribing == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isDir(absPath string) bool {
+	info, err := os.Stat(absPath)
+	return err == nil && info.IsDir()
+}
+
+// addNestedDirs subscribes to the given directory and its descendants so the changes in nested directories are notified.
+func addNestedDirs(a abstractFsWatcher, s *subscription, root string) {
+	_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+
+		if slices.Contains(s.nestedDirs, path) {
+			return nil
+		}
+		err = a.Add(path)
+		if err != nil {
+			logger.Warnf("Failed to subscribe to %s: %+v", path, err)
+			return nil
+		}
+		s.nestedDirs = append(s.nestedDirs, path)
+		return nil
+	})
 }
 
 func doSubscribe(a abstractFsWatcher, s *subscription, subscriptions map[string][]*subscription) error {
@@ -209,6 +402,15 @@ func doSubscribe(a abstractFsWatcher, s *subscription, subscriptions map[string]
 			return sarah.ErrAlreadySubscribing
 		}
 	}
+
+	// Subscribe to the subdirectory named after the id recursively when the configuration consists of multiple files.
+	if nested := filepath.Join(s.absDir, s.id); isDir(nested) {
+		addNestedDirs(a, s, nested)
+	}
+
+	// Remember the current content so saving the file without any modification is not notified.
+	s.hash, _ = hashConfig(s.absDir, s.id)
+
 	subscriptions[s.absDir] = append(watches, s)
 	return nil
 }
@@ -220,6 +422,14 @@ func doUnsubscribe(a abstractFsWatcher, botType sarah.BotType, subscriptions map
 		for _, subscribeDir := range subscribeDirs {
 			if subscribeDir.botType != botType {
 				remains = append(remains, subscribeDir)
+				continue
+			}
+
+			if subscribeDir.timer != nil {
+				subscribeDir.timer.Stop()
+			}
+			for _, nested := range subscribeDir.nestedDirs {
+				_ = a.Remove(nested)
 			}
 		}
 
@@ -235,6 +445,9 @@ func doUnsubscribe(a abstractFsWatcher, botType sarah.BotType, subscriptions map
 	}
 }
 
+// nestedConfigName is the name of the configuration file without its extension in the subdirectory named after the id.
+const nestedConfigName = "config"
+
 type fileType uint
 
 const (
@@ -246,6 +459,7 @@ const (
 var (
 	errUnableToDetermineConfigFileFormat = errors.New("can not determine file format")
 	errUnsupportedConfigFileFormat       = errors.New("unsupported file format")
+	errConfigFileNotFound                = errors.New("configuration file is not found")
 	configFileCandidates                 = []struct {
 		ext      string
 		fileType fileType
@@ -272,23 +486,28 @@ type pluginConfigFile struct {
 	fileType fileType
 }
 
+// findPluginConfigFile looks for the configuration file with the given id in the given directory.
+// A file named after the id such as hello.yaml is preferred, and then a file named config in the subdirectory named after the id such as hello/config.yaml is looked up.
+// The latter layout lets a complex configuration ship auxiliary files in the same subdirectory.
 func findPluginConfigFile(configDir, id string) *pluginConfigFile {
-	for _, c := range configFileCandidates {
-		configPath := filepath.Join(configDir, fmt.Sprintf("%s%s", id, c.ext))
-		absPath, err := filepath.Abs(configPath)
-		if err != nil {
-			continue
-		}
+	for _, name := range []string{id, filepath.Join(id, nestedConfigName)} {
+		for _, c := range configFileCandidates {
+			configPath := filepath.Join(configDir, fmt.Sprintf("%s%s", name, c.ext))
+			absPath, err := filepath.Abs(configPath)
+			if err != nil {
+				continue
+			}
 
-		_, err = os.Stat(absPath)
-		if err == nil {
-			// File exists.
-			absDir, _ := filepath.Split(absPath)
-			return &pluginConfigFile{
-				id:       id,
-				absPath:  absPath,
-				absDir:   filepath.Dir(absDir), // Handle the trailing slash
-				fileType: c.fileType,
+			_, err = os.Stat(absPath)
+			if err == nil {
+				// File exists.
+				absDir, _ := filepath.Split(absPath)
+				return &pluginConfigFile{
+					id:       id,
+					absPath:  absPath,
+					absDir:   filepath.Dir(absDir), // Handle the trailing slash
+					fileType: c.fileType,
+				}
 			}
 		}
 	}
@@ -661,3 +661,207 @@ func Test_plainPathToFile(t *testing.T) {
 	}
 
 }
+
+func TestWithQuietPeriod(t *testing.T) {
+	w := &fileWatcher{}
+	WithQuietPeriod(time.Second)(w)
+
+	if w.quietPeriod != time.Second {
+		t.Errorf("Given period is not set: %s.", w.quietPeriod)
+	}
+}
+
+func Test_notifyChange(t *testing.T) {
+	dir := t.TempDir()
+	absPath := filepath.Join(dir, "hello.yaml")
+	err := os.WriteFile(absPath, []byte("text: hello"), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write file: %s.", err.Error())
+	}
+
+	notified := 0
+	s := &subscription{
+		absDir: dir,
+		id:     "hello",
+		callback: func() {
+			notified++
+		},
+	}
+	err = doSubscribe(&dummyFsWatcher{AddFunc: func(_ string) error { return nil }}, s, map[string][]*subscription{})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+
+	// Saved without any modification.
+	notifyChange(s)
+	if notified != 0 {
+		t.Errorf("Unchanged content should not be notified: %d.", notified)
+	}
+
+	err = os.WriteFile(absPath, []byte("text: world"), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write file: %s.", err.Error())
+	}
+	notifyChange(s)
+	notifyChange(s)
+	if notified != 1 {
+		t.Errorf("Changed content should be notified only once: %d.", notified)
+	}
+
+	// The content can not be read.
+	err = os.Remove(absPath)
+	if err != nil {
+		t.Fatalf("Failed to remove file: %s.", err.Error())
+	}
+	notifyChange(s)
+	if notified != 2 {
+		t.Errorf("Unreadable configuration should be notified: %d.", notified)
+	}
+}
+
+func TestFileWatcher_handleEvent_WithQuietPeriod(t *testing.T) {
+	dir := t.TempDir()
+	absPath := filepath.Join(dir, "hello.yaml")
+
+	w := &fileWatcher{
+		settled:     make(chan *subscription, 1),
+		quietPeriod: 50 * time.Millisecond,
+	}
+	s := &subscription{
+		absDir:   dir,
+		id:       "hello",
+		callback: func() {},
+	}
+	subscriptions := map[string][]*subscription{dir: {s}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Rapid writes.
+	for i := 0; i < 3; i++ {
+		w.handleEvent(ctx, fsnotify.Event{Op: fsnotify.Write, Name: absPath}, subscriptions)
+	}
+
+	select {
+	case settled := <-w.settled:
+		if settled != s {
+			t.Errorf("Unexpected change is settled: %#v.", settled)
+		}
+
+	case <-time.NewTimer(time.Second).C:
+		t.Fatal("Change is not settled.")
+
+	}
+
+	select {
+	case <-w.settled:
+		t.Error("Rapid writes should be settled only once.")
+
+	case <-time.NewTimer(200 * time.Millisecond).C:
+		// O.K.
+
+	}
+}
+
+func TestFileWatcher_handleEvent_NestedDirectory(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "hello")
+	err := os.MkdirAll(filepath.Join(nested, "templates"), 0o700)
+	if err != nil {
+		t.Fatalf("Failed to create directory: %s.", err.Error())
+	}
+	err = os.WriteFile(filepath.Join(nested, "config.yaml"), []byte("text: hello"), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write file: %s.", err.Error())
+	}
+
+	var added []string
+	var removed []string
+	fsWatcher := &dummyFsWatcher{
+		AddFunc: func(dir string) error {
+			added = append(added, dir)
+			return nil
+		},
+		RemoveFunc: func(dir string) error {
+			removed = append(removed, dir)
+			return nil
+		},
+	}
+	w := &fileWatcher{fsWatcher: fsWatcher}
+
+	notified := 0
+	s := &subscription{
+		botType: "dummy",
+		absDir:  dir,
+		id:      "hello",
+		callback: func() {
+			notified++
+		},
+	}
+	subscriptions := map[string][]*subscription{}
+	err = doSubscribe(fsWatcher, s, subscriptions)
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	if len(added) != 3 {
+		t.Fatalf("Directories are not subscribed recursively: %#v.", added)
+	}
+
+	// An auxiliary file is added in the nested directory.
+	auxiliary := filepath.Join(nested, "templates", "greeting.txt")
+	err = os.WriteFile(auxiliary, []byte("Hello, {{.Name}}"), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write file: %s.", err.Error())
+	}
+	w.handleEvent(context.TODO(), fsnotify.Event{Op: fsnotify.Create, Name: auxiliary}, subscriptions)
+	if notified != 1 {
+		t.Errorf("Change of the auxiliary file is not notified: %d.", notified)
+	}
+
+	// A new directory is created in the nested directory.
+	created := filepath.Join(nested, "partials")
+	err = os.Mkdir(created, 0o700)
+	if err != nil {
+		t.Fatalf("Failed to create directory: %s.", err.Error())
+	}
+	w.handleEvent(context.TODO(), fsnotify.Event{Op: fsnotify.Create, Name: created}, subscriptions)
+	if added[len(added)-1] != created {
+		t.Errorf("Created directory is not subscribed: %#v.", added)
+	}
+
+	doUnsubscribe(fsWatcher, "dummy", subscriptions)
+	if len(removed) != 4 {
+		t.Errorf("Nested directories are not unsubscribed: %#v.", removed)
+	}
+}
+
+func Test_findPluginConfigFile_Nested(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "hello")
+	err := os.Mkdir(nested, 0o700)
+	if err != nil {
+		t.Fatalf("Failed to create directory: %s.", err.Error())
+	}
+	err = os.WriteFile(filepath.Join(nested, "config.json"), []byte("{}"), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write file: %s.", err.Error())
+	}
+
+	file := findPluginConfigFile(dir, "hello")
+	if file == nil {
+		t.Fatal("Configuration file is not found.")
+	}
+	if file.id != "hello" || file.absDir != nested || file.fileType != jsonFile {
+		t.Errorf("Unexpected file is returned: %#v.", file)
+	}
+
+	// A file directly under the directory is preferred.
+	err = os.WriteFile(filepath.Join(dir, "hello.yaml"), []byte("{}"), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write file: %s.", err.Error())
+	}
+	file = findPluginConfigFile(dir, "hello")
+	if file == nil || file.absDir != dir || file.fileType != yamlFile {
+		t.Errorf("Unexpected file is returned: %#v.", file)
+	}
+}
@@ -0,0 +1,140 @@
+package sarah
+
+import (
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-kasumi/worker"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// JobPanic represents a panic that occurred in a job enqueued to the worker and was recovered.
+type JobPanic struct {
+	// Recovered is the value returned by recover.
+	Recovered interface{}
+
+	// Stack is the stack trace of the goroutine where the panic occurred.
+	Stack []byte
+
+	// OccurredAt is the time when the panic was recovered.
+	OccurredAt time.Time
+}
+
+// WorkerStats represents the statistics of the worker that executes the jobs such as Command executions.
+type WorkerStats struct {
+	// QueueDepth is the number of enqueued jobs that are not started yet.
+	QueueDepth int
+
+	// RecoveredPanics is the number of panics recovered in the jobs.
+	RecoveredPanics uint64
+}
+
+// trackedWorker wraps worker.Worker to track the number of enqueued jobs that are not started yet and how long they wait in the queue.
+// This also recovers a panic in each job so a panicking job does not kill the worker goroutine.
+type trackedWorker struct {
+	worker.Worker
+	capacity    int
+	pending     int64
+	waited      int64
+	panics      uint64
+	reportPanic func(*JobPanic)
+}
+
+var _ worker.Worker = (*trackedWorker)(nil)
+
+func newTrackedWorker(w worker.Worker, capacity int) *trackedWorker {
+	return &trackedWorker{
+		Worker:   w,
+		capacity: capacity,
+	}
+}
+
+// Enqueue enqueues the given job to the underlying worker.Worker.
+func (w *trackedWorker) Enqueue(fnc func()) error {
+	return w.enqueue(fnc, w.Worker.Enqueue)
+}
+
+// enqueueOrdered enqueues the given job so the jobs with the same key run in order when the underlying worker.Worker is created by NewOrderedWorker.
+// Otherwise, this is identical to Enqueue.
+func (w *trackedWorker) enqueueOrdered(key string, fnc func()) error {
+	ordered, ok := w.Worker.(orderedEnqueuer)
+	if !ok {
+		return w.Enqueue(fnc)
+	}
+	return w.enqueue(fnc, func(job func()) error {
+		return ordered.enqueueOrdered(key, job)
+	})
+}
+
+func (w *trackedWorker) enqueue(fnc func(), enqueue func(func()) error) error {
+	enqueuedAt := time.Now()
+	atomic.AddInt64(&w.pending, 1)
+	err := enqueue(func() {
+		atomic.AddInt64(&w.pending, -1)
+		atomic.StoreInt64(&w.waited, int64(time.Since(enqueuedAt)))
+		w.execute(fnc)
+	})
+	if err != nil {
+		atomic.AddInt64(&w.pending, -1)
+	}
+	return err
+}
+
+// execute runs the given job and recovers its panic.
+func (w *trackedWorker) execute(fnc func()) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		atomic.AddUint64(&w.panics, 1)
+		p := &JobPanic{
+			Recovered:  r,
+			Stack:      debug.Stack(),
+			OccurredAt: time.Now(),
+		}
+		logger.Errorf("Recovered a panic in a job: %#v\n%s", p.Recovered, p.Stack)
+
+		if w.reportPanic != nil {
+			reportJobPanic(w.reportPanic, p)
+		}
+	}()
+
+	fnc()
+}
+
+// depth returns the number of enqueued jobs that are not started yet.
+func (w *trackedWorker) depth() int {
+	return int(atomic.LoadInt64(&w.pending))
+}
+
+// latency returns how long the latest started job waited in the queue.
+func (w *trackedWorker) latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&w.waited))
+}
+
+// recoveredPanics returns the number of panics recovered in the jobs.
+func (w *trackedWorker) recoveredPanics() uint64 {
+	return atomic.LoadUint64(&w.panics)
+}
+
+// stats returns the current statistics of the worker.
+func (w *trackedWorker) stats() *WorkerStats {
+	return &WorkerStats{
+		QueueDepth:      w.depth(),
+		RecoveredPanics: w.recoveredPanics(),
+	}
+}
+
+// reportJobPanic calls the given reporter in a panic-proof manner.
+func reportJobPanic(reporter func(*JobPanic), p *JobPanic) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Panic in job panic reporter: %s", fmt.Sprint(r))
+		}
+	}()
+
+	reporter(p)
+}
@@ -0,0 +1,143 @@
+package sarah
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_trackedWorker_Enqueue(t *testing.T) {
+	var jobs []func()
+	w := newTrackedWorker(&DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			jobs = append(jobs, fnc)
+			return nil
+		},
+	}, 10)
+
+	executed := false
+	_ = w.Enqueue(func() {
+		executed = true
+	})
+	_ = w.Enqueue(func() {})
+
+	if w.depth() != 2 {
+		t.Errorf("Unexpected depth is returned: %d.", w.depth())
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	jobs[0]()
+
+	if !executed {
+		t.Error("Given job is not executed.")
+	}
+
+	if w.depth() != 1 {
+		t.Errorf("Depth is not decremented on job start: %d.", w.depth())
+	}
+
+	if w.latency() < 10*time.Millisecond {
+		t.Errorf("Latency is not recorded: %s.", w.latency())
+	}
+}
+
+func Test_trackedWorker_Enqueue_Error(t *testing.T) {
+	expected := errors.New("queue is full")
+	w := newTrackedWorker(&DummyWorker{
+		EnqueueFunc: func(_ func()) error {
+			return expected
+		},
+	}, 10)
+
+	err := w.Enqueue(func() {})
+	if err != expected {
+		t.Errorf("Expected error is not returned: %#v.", err)
+	}
+
+	if w.depth() != 0 {
+		t.Errorf("Depth should not be incremented on failure: %d.", w.depth())
+	}
+}
+
+func Test_setupInputReceiver_QueueDiagnostics(t *testing.T) {
+	w := newTrackedWorker(&DummyWorker{
+		EnqueueFunc: func(_ func()) error {
+			return errors.New("queue is full")
+		},
+	}, 10)
+	w.pending = 10
+	w.waited = int64(time.Second)
+
+	receiveInput := setupInputReceiver(context.TODO(), &DummyBot{}, w, nil)
+	err := receiveInput(&DummyInput{})
+
+	blocked, ok := err.(*BlockedInputError)
+	if !ok {
+		t.Fatalf("Expected error type is not returned: %T.", err)
+	}
+
+	if blocked.QueueCapacity != 10 || blocked.QueueDepth != 10 || blocked.EnqueueLatency != time.Second {
+		t.Errorf("Unexpected diagnostics are set: %#v.", blocked)
+	}
+}
+
+func Test_trackedWorker_Enqueue_Panic(t *testing.T) {
+	var jobs []func()
+	w := newTrackedWorker(&DummyWorker{
+		EnqueueFunc: func(fnc func()) error {
+			jobs = append(jobs, fnc)
+			return nil
+		},
+	}, 10)
+	var reported *JobPanic
+	w.reportPanic = func(p *JobPanic) {
+		reported = p
+	}
+
+	_ = w.Enqueue(func() {
+		panic("panic!")
+	})
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Panic is not recovered: %#v.", r)
+			}
+		}()
+		jobs[0]()
+	}()
+
+	if w.recoveredPanics() != 1 {
+		t.Errorf("Unexpected number of recovered panics: %d.", w.recoveredPanics())
+	}
+
+	if reported == nil {
+		t.Fatal("Panic is not reported.")
+	}
+
+	if reported.Recovered != "panic!" {
+		t.Errorf("Unexpected recovered value is reported: %#v.", reported.Recovered)
+	}
+
+	if len(reported.Stack) == 0 {
+		t.Error("Stack trace is not reported.")
+	}
+
+	stats := w.stats()
+	if stats.RecoveredPanics != 1 || stats.QueueDepth != 0 {
+		t.Errorf("Unexpected stats are returned: %#v.", stats)
+	}
+}
+
+func Test_reportJobPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Panic in reporter is not recovered: %#v.", r)
+		}
+	}()
+
+	reportJobPanic(func(_ *JobPanic) {
+		panic("panic!")
+	}, &JobPanic{})
+}
@@ -0,0 +1,349 @@
+// Package workers provides worker.Worker implementations that can be registered via sarah.RegisterWorker.
+//
+// AdaptiveWorker grows and shrinks its goroutine pool between the configured minimum and maximum numbers
+// based on the queue depth and the time each job waits in the queue.
+// Bursty traffic can be handled without permanently over-provisioned workers.
+//
+//	config := workers.NewConfig()
+//	config.MinWorkers = 10
+//	config.MaxWorkers = 200
+//	sarah.RegisterWorker(workers.RunAdaptive(ctx, config))
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/oklahomer/go-kasumi/logger"
+	"github.com/oklahomer/go-kasumi/worker"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrEnqueueAfterShutdown is returned when a job is enqueued after the worker's context is canceled.
+var ErrEnqueueAfterShutdown = errors.New("job can not be enqueued after worker shutdown")
+
+// ErrQueueOverflow is returned when the job queue is full.
+var ErrQueueOverflow = errors.New("queue is full")
+
+// Config contains some configuration variables for AdaptiveWorker.
+type Config struct {
+	// MinWorkers declares the number of workers that are always running.
+	MinWorkers uint `json:"min_workers" yaml:"min_workers"`
+
+	// MaxWorkers declares the upper limit of the number of workers.
+	MaxWorkers uint `json:"max_workers" yaml:"max_workers"`
+
+	// QueueSize declares the size of the job queue.
+	QueueSize uint `json:"queue_size" yaml:"queue_size"`
+
+	// ScaleInterval declares how often to evaluate the queue state and spawn additional workers.
+	ScaleInterval time.Duration `json:"scale_interval" yaml:"scale_interval"`
+
+	// ScaleUpQueueRatio declares the ratio of the queue depth to QueueSize to spawn additional workers.
+	ScaleUpQueueRatio float64 `json:"scale_up_queue_ratio" yaml:"scale_up_queue_ratio"`
+
+	// ScaleUpLatency declares the time a job may wait in the queue. Additional workers are spawned when jobs wait longer than this.
+	// Zero value disables the latency-based scaling.
+	ScaleUpLatency time.Duration `json:"scale_up_latency" yaml:"scale_up_latency"`
+
+	// IdleTimeout declares how long an additional worker waits for a job before it stops.
+	IdleTimeout time.Duration `json:"idle_timeout" yaml:"idle_timeout"`
+
+	// SuperviseInterval declares the interval to report the queue statistics to the Reporter.
+	// Zero value disables the reporting.
+	SuperviseInterval time.Duration `json:"supervise_interval" yaml:"supervise_interval"`
+}
+
+// NewConfig creates and returns a new Config instance with default settings.
+// Use json.Unmarshal, yaml.Unmarshal, or manual manipulation to override those default values.
+func NewConfig() *Config {
+	return &Config{
+		MinWorkers:        uint(runtime.NumCPU()),
+		MaxWorkers:        100,
+		QueueSize:         10,
+		ScaleInterval:     1 * time.Second,
+		ScaleUpQueueRatio: 0.5,
+		ScaleUpLatency:    500 * time.Millisecond,
+		IdleTimeout:       30 * time.Second,
+		SuperviseInterval: 60 * time.Second,
+	}
+}
+
+// ScaleEvent represents a change in the number of running workers.
+type ScaleEvent struct {
+	// From is the number of workers before the change.
+	From int
+
+	// To is the number of workers after the change.
+	To int
+
+	// QueueDepth is the number of jobs in the queue when the change occurred.
+	QueueDepth int
+
+	// Latency is the longest time a job waited in the queue since the previous scale evaluation.
+	Latency time.Duration
+}
+
+// ScaleReporter is an optional interface for worker.Reporter to receive ScaleEvent.
+// When a worker.Reporter given via WithReporter implements this, scale events are reported along with the queue statistics.
+// Workers stopping on the context cancellation are not reported since the whole pool is shutting down at that point.
+type ScaleReporter interface {
+	ReportScale(context.Context, *ScaleEvent)
+}
+
+type defaultReporter struct{}
+
+var _ worker.Reporter = (*defaultReporter)(nil)
+var _ ScaleReporter = (*defaultReporter)(nil)
+
+func (*defaultReporter) Report(_ context.Context, stats *worker.Stats) {
+	logger.Infof("Worker queue length: %d", stats.QueueSize)
+}
+
+func (*defaultReporter) ReportScale(_ context.Context, event *ScaleEvent) {
+	logger.Infof("Worker pool is scaled from %d to %d. Queue depth: %d. Latency: %s.", event.From, event.To, event.QueueDepth, event.Latency)
+}
+
+// Option defines a function's signature that AdaptiveWorker's functional options must satisfy.
+type Option func(*AdaptiveWorker)
+
+// WithReporter creates an Option that replaces the default reporter with the given one.
+func WithReporter(reporter worker.Reporter) Option {
+	return func(w *AdaptiveWorker) {
+		w.reporter = reporter
+	}
+}
+
+type job struct {
+	fnc        func()
+	enqueuedAt time.Time
+}
+
+// AdaptiveWorker is a worker.Worker implementation that grows and shrinks its goroutine pool.
+type AdaptiveWorker struct {
+	ctx      context.Context
+	config   *Config
+	reporter worker.Reporter
+	queue    chan *job
+	running  int64
+	latency  int64
+}
+
+var _ worker.Worker = (*AdaptiveWorker)(nil)
+
+// RunAdaptive creates and returns a new AdaptiveWorker instance, and starts its workers and the scaling supervisor.
+// The workers stop when the given context is canceled.
+func RunAdaptive(ctx context.Context, config *Config, options ...Option) *AdaptiveWorker {
+	w := &AdaptiveWorker{
+		ctx:      ctx,
+		config:   config,
+		reporter: &defaultReporter{},
+		queue:    make(chan *job, config.QueueSize),
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	logger.Infof("Start spawning %d workers.", config.MinWorkers)
+	for i := uint(0); i < config.MinWorkers; i++ {
+		atomic.AddInt64(&w.running, 1)
+		go w.runChild(false)
+	}
+
+	go w.supervise()
+
+	return w
+}
+
+// Enqueue enqueues the given job. This does not block, but returns ErrQueueOverflow when the queue is full.
+func (w *AdaptiveWorker) Enqueue(fnc func()) error {
+	if err := w.ctx.Err(); err != nil {
+		return ErrEnqueueAfterShutdown
+	}
+
+	select {
+	case w.queue <- &job{fnc: fnc, enqueuedAt: time.Now()}:
+		return nil
+
+	default:
+		return ErrQueueOverflow
+
+	}
+}
+
+// Running returns the number of currently running workers.
+func (w *AdaptiveWorker) Running() int {
+	return int(atomic.LoadInt64(&w.running))
+}
+
+// runChild receives jobs and executes them.
+// An additional worker stops when no job is given for Config.IdleTimeout while the number of workers exceeds Config.MinWorkers.
+func (w *AdaptiveWorker) runChild(additional bool) {
+	var idle <-chan time.Time
+	var timer *time.Timer
+	if additional && w.config.IdleTimeout > 0 {
+		timer = time.NewTimer(w.config.IdleTimeout)
+		defer timer.Stop()
+		idle = timer.C
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			// This is a shutdown rather than a scale-down, so ScaleReporter is not notified.
+			atomic.AddInt64(&w.running, -1)
+			return
+
+		case <-idle:
+			if w.tryShrink() {
+				return
+			}
+			timer.Reset(w.config.IdleTimeout)
+
+		case j := <-w.queue:
+			w.recordLatency(time.Since(j.enqueuedAt))
+			execute(j.fnc)
+
+			if timer != nil {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(w.config.IdleTimeout)
+			}
+
+		}
+	}
+}
+
+// tryShrink decrements the number of running workers unless the number reaches Config.MinWorkers.
+func (w *AdaptiveWorker) tryShrink() bool {
+	for {
+		current := atomic.LoadInt64(&w.running)
+		if current <= int64(w.config.MinWorkers) {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(&w.running, current, current-1) {
+			w.reportScale(int(current), int(current-1), time.Duration(atomic.LoadInt64(&w.latency)))
+			return true
+		}
+	}
+}
+
+// recordLatency keeps the longest time a job waited in the queue since the previous scale evaluation.
+func (w *AdaptiveWorker) recordLatency(latency time.Duration) {
+	for {
+		current := atomic.LoadInt64(&w.latency)
+		if int64(latency) <= current {
+			return
+		}
+
+		if atomic.CompareAndSwapInt64(&w.latency, current, int64(latency)) {
+			return
+		}
+	}
+}
+
+// scaleUp spawns additional workers when the queue is congested.
+func (w *AdaptiveWorker) scaleUp() {
+	// Start a new window on every evaluation so a long wait in the past does not keep spawning workers.
+	latency := time.Duration(atomic.SwapInt64(&w.latency, 0))
+
+	depth := len(w.queue)
+	if depth == 0 {
+		return
+	}
+
+	congested := w.config.QueueSize > 0 && float64(depth) >= float64(w.config.QueueSize)*w.config.ScaleUpQueueRatio
+	slow := w.config.ScaleUpLatency > 0 && latency > w.config.ScaleUpLatency
+	if !congested && !slow {
+		return
+	}
+
+	current := atomic.LoadInt64(&w.running)
+	target := current + int64(depth)
+	if target > int64(w.config.MaxWorkers) {
+		target = int64(w.config.MaxWorkers)
+	}
+	if target <= current {
+		return
+	}
+
+	for i := current; i < target; i++ {
+		atomic.AddInt64(&w.running, 1)
+		go w.runChild(true)
+	}
+	w.reportScale(int(current), int(target), latency)
+}
+
+func (w *AdaptiveWorker) reportScale(from int, to int, latency time.Duration) {
+	scaleReporter, ok := w.reporter.(ScaleReporter)
+	if !ok {
+		return
+	}
+
+	scaleReporter.ReportScale(w.ctx, &ScaleEvent{
+		From:       from,
+		To:         to,
+		QueueDepth: len(w.queue),
+		Latency:    latency,
+	})
+}
+
+func (w *AdaptiveWorker) supervise() {
+	var scale <-chan time.Time
+	if w.config.ScaleInterval > 0 {
+		ticker := time.NewTicker(w.config.ScaleInterval)
+		defer ticker.Stop()
+		scale = ticker.C
+	}
+
+	var report <-chan time.Time
+	if w.config.SuperviseInterval > 0 {
+		ticker := time.NewTicker(w.config.SuperviseInterval)
+		defer ticker.Stop()
+		report = ticker.C
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+
+		case <-scale:
+			w.scaleUp()
+
+		case <-report:
+			w.reporter.Report(w.ctx, &worker.Stats{
+				QueueSize: len(w.queue),
+			})
+
+		}
+	}
+}
+
+// execute runs the given job in a panic-proof manner so a panicking job does not kill the worker goroutine.
+func execute(fnc func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := []string{fmt.Sprintf("panic in given job. recovered: %#v", r)}
+			for depth := 0; ; depth++ {
+				_, src, line, ok := runtime.Caller(depth)
+				if !ok {
+					break
+				}
+				stack = append(stack, fmt.Sprintf(" -> depth:%d. file:%s. line:%d.", depth, src, line))
+			}
+			logger.Warn(strings.Join(stack, "\n"))
+		}
+	}()
+
+	fnc()
+}
@@ -0,0 +1,347 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"github.com/oklahomer/go-kasumi/worker"
+	"sync"
+	"testing"
+	"time"
+)
+
+type DummyReporter struct {
+	ReportFunc      func(context.Context, *worker.Stats)
+	ReportScaleFunc func(context.Context, *ScaleEvent)
+}
+
+var _ worker.Reporter = (*DummyReporter)(nil)
+var _ ScaleReporter = (*DummyReporter)(nil)
+
+func (r *DummyReporter) Report(ctx context.Context, stats *worker.Stats) {
+	r.ReportFunc(ctx, stats)
+}
+
+func (r *DummyReporter) ReportScale(ctx context.Context, event *ScaleEvent) {
+	r.ReportScaleFunc(ctx, event)
+}
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig()
+
+	if config.MinWorkers == 0 || config.MaxWorkers < config.MinWorkers {
+		t.Errorf("Unexpected number of workers are set: %#v.", config)
+	}
+
+	if config.QueueSize == 0 {
+		t.Error("Default queue size is not set.")
+	}
+}
+
+func TestWithReporter(t *testing.T) {
+	reporter := &DummyReporter{}
+	w := &AdaptiveWorker{}
+
+	WithReporter(reporter)(w)
+
+	if w.reporter != reporter {
+		t.Errorf("Expected reporter is not set: %#v.", w.reporter)
+	}
+}
+
+func TestRunAdaptive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := &Config{
+		MinWorkers: 3,
+		MaxWorkers: 5,
+		QueueSize:  10,
+	}
+	w := RunAdaptive(ctx, config)
+
+	if w.Running() != 3 {
+		t.Errorf("Unexpected number of workers are running: %d.", w.Running())
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	err := w.Enqueue(func() {
+		wg.Done()
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error is returned: %s.", err.Error())
+	}
+	wg.Wait()
+}
+
+func TestAdaptiveWorker_Enqueue(t *testing.T) {
+	t.Run("Queue overflow", func(t *testing.T) {
+		w := &AdaptiveWorker{
+			ctx:   context.Background(),
+			queue: make(chan *job, 1),
+		}
+
+		err := w.Enqueue(func() {})
+		if err != nil {
+			t.Fatalf("Unexpected error is returned: %s.", err.Error())
+		}
+
+		err = w.Enqueue(func() {})
+		if !errors.Is(err, ErrQueueOverflow) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+
+	t.Run("After shutdown", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := &AdaptiveWorker{
+			ctx:   ctx,
+			queue: make(chan *job, 1),
+		}
+
+		err := w.Enqueue(func() {})
+		if !errors.Is(err, ErrEnqueueAfterShutdown) {
+			t.Errorf("Expected error is not returned: %#v.", err)
+		}
+	})
+}
+
+func TestAdaptiveWorker_scaleUp(t *testing.T) {
+	testSets := []struct {
+		config   *Config
+		queued   int
+		latency  time.Duration
+		expected int
+	}{
+		{
+			// Empty queue
+			config:   &Config{MaxWorkers: 5, QueueSize: 10, ScaleUpQueueRatio: 0.5},
+			queued:   0,
+			expected: 0,
+		},
+		{
+			// Queue is not congested
+			config:   &Config{MaxWorkers: 5, QueueSize: 10, ScaleUpQueueRatio: 0.5},
+			queued:   2,
+			expected: 0,
+		},
+		{
+			// Queue is congested
+			config:   &Config{MaxWorkers: 5, QueueSize: 10, ScaleUpQueueRatio: 0.5},
+			queued:   5,
+			expected: 5,
+		},
+		{
+			// Capped by MaxWorkers
+			config:   &Config{MaxWorkers: 3, QueueSize: 10, ScaleUpQueueRatio: 0.5},
+			queued:   8,
+			expected: 3,
+		},
+		{
+			// Jobs wait too long
+			config:   &Config{MaxWorkers: 5, QueueSize: 10, ScaleUpQueueRatio: 0.5, ScaleUpLatency: time.Second},
+			queued:   2,
+			latency:  2 * time.Second,
+			expected: 2,
+		},
+	}
+
+	for i, tt := range testSets {
+		ctx, cancel := context.WithCancel(context.Background())
+		block := make(chan struct{})
+		var event *ScaleEvent
+		w := &AdaptiveWorker{
+			ctx:    ctx,
+			config: tt.config,
+			reporter: &DummyReporter{
+				ReportScaleFunc: func(_ context.Context, e *ScaleEvent) {
+					event = e
+				},
+			},
+			queue:   make(chan *job, tt.config.QueueSize),
+			latency: int64(tt.latency),
+		}
+		for j := 0; j < tt.queued; j++ {
+			_ = w.Enqueue(func() {
+				<-block
+			})
+		}
+
+		w.scaleUp()
+
+		if w.Running() != tt.expected {
+			t.Errorf("Unexpected number of workers are running on test %d: %d.", i, w.Running())
+		}
+
+		if tt.expected == 0 && event != nil {
+			t.Errorf("Unexpected scale event is reported on test %d: %#v.", i, event)
+		}
+
+		if tt.expected > 0 {
+			if event == nil {
+				t.Errorf("Scale event is not reported on test %d.", i)
+			} else if event.From != 0 || event.To != tt.expected {
+				t.Errorf("Unexpected scale event is reported on test %d: %#v.", i, event)
+			}
+		}
+
+		close(block)
+		cancel()
+	}
+}
+
+func TestAdaptiveWorker_scaleUp_LatencyWindow(t *testing.T) {
+	config := &Config{MaxWorkers: 2, QueueSize: 10, ScaleUpQueueRatio: 0.5, ScaleUpLatency: time.Second}
+	w := &AdaptiveWorker{
+		ctx:      context.Background(),
+		config:   config,
+		reporter: &defaultReporter{},
+		queue:    make(chan *job, 10),
+		running:  2,
+		latency:  int64(2 * time.Second),
+	}
+	for j := 0; j < 2; j++ {
+		_ = w.Enqueue(func() {})
+	}
+
+	// The pool is already at its maximum, but the latency of this window is consumed.
+	w.scaleUp()
+
+	config.MaxWorkers = 5
+	w.scaleUp()
+
+	if w.Running() != 2 {
+		t.Errorf("Latency observed in the previous window should not spawn workers: %d.", w.Running())
+	}
+}
+
+func TestAdaptiveWorker_recordLatency(t *testing.T) {
+	w := &AdaptiveWorker{}
+
+	w.recordLatency(2 * time.Second)
+	w.recordLatency(time.Second)
+
+	if w.latency != int64(2*time.Second) {
+		t.Errorf("The longest latency is not kept: %s.", time.Duration(w.latency))
+	}
+}
+
+func TestAdaptiveWorker_tryShrink(t *testing.T) {
+	var event *ScaleEvent
+	w := &AdaptiveWorker{
+		ctx:    context.Background(),
+		config: &Config{MinWorkers: 1},
+		reporter: &DummyReporter{
+			ReportScaleFunc: func(_ context.Context, e *ScaleEvent) {
+				event = e
+			},
+		},
+		queue:   make(chan *job, 1),
+		running: 2,
+	}
+
+	if !w.tryShrink() {
+		t.Fatal("Worker is not shrunk.")
+	}
+
+	if event == nil || event.From != 2 || event.To != 1 {
+		t.Errorf("Unexpected scale event is reported: %#v.", event)
+	}
+
+	if w.tryShrink() {
+		t.Error("Worker is shrunk below MinWorkers.")
+	}
+
+	if w.Running() != 1 {
+		t.Errorf("Unexpected number of workers are running: %d.", w.Running())
+	}
+}
+
+func TestAdaptiveWorker_runChild(t *testing.T) {
+	t.Run("Idle timeout", func(t *testing.T) {
+		w := &AdaptiveWorker{
+			ctx:      context.Background(),
+			config:   &Config{MinWorkers: 0, IdleTimeout: time.Millisecond},
+			reporter: &defaultReporter{},
+			queue:    make(chan *job, 1),
+			running:  1,
+		}
+
+		finished := make(chan struct{})
+		go func() {
+			w.runChild(true)
+			close(finished)
+		}()
+
+		select {
+		case <-finished:
+			if w.Running() != 0 {
+				t.Errorf("Unexpected number of workers are running: %d.", w.Running())
+			}
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Error("Idle worker did not stop.")
+
+		}
+	})
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &AdaptiveWorker{
+			ctx:      ctx,
+			config:   &Config{},
+			reporter: &defaultReporter{},
+			queue:    make(chan *job, 1),
+			running:  1,
+		}
+
+		finished := make(chan struct{})
+		go func() {
+			w.runChild(false)
+			close(finished)
+		}()
+		cancel()
+
+		select {
+		case <-finished:
+			if w.Running() != 0 {
+				t.Errorf("Unexpected number of workers are running: %d.", w.Running())
+			}
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Error("Worker did not stop.")
+
+		}
+	})
+
+	t.Run("Panicking job", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w := &AdaptiveWorker{
+			ctx:      ctx,
+			config:   &Config{},
+			reporter: &defaultReporter{},
+			queue:    make(chan *job, 2),
+			running:  1,
+		}
+		go w.runChild(false)
+
+		done := make(chan struct{})
+		_ = w.Enqueue(func() {
+			panic("panic!")
+		})
+		_ = w.Enqueue(func() {
+			close(done)
+		})
+
+		select {
+		case <-done:
+			// O.K.
+
+		case <-time.NewTimer(1 * time.Second).C:
+			t.Error("Worker did not survive the panic.")
+
+		}
+	})
+}